@@ -0,0 +1,292 @@
+package com.kubesec.integration;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import org.junit.jupiter.api.AfterAll;
+import org.junit.jupiter.api.BeforeAll;
+import org.junit.jupiter.api.Test;
+import org.testcontainers.containers.Container;
+
+import java.io.IOException;
+import java.math.BigDecimal;
+import java.net.URI;
+import java.net.http.HttpClient;
+import java.net.http.HttpRequest;
+import java.net.http.HttpResponse;
+import java.util.Map;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNotEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// Walks users through the real services: account-service for users and accounts,
+// auth-service for tokens, and transaction-service for money movement, which in turn
+// calls the other two.
+class EndToEndTest {
+
+    private static final ObjectMapper MAPPER = new ObjectMapper();
+    private static final HttpClient HTTP = HttpClient.newHttpClient();
+    private static final String PASSWORD = "Vq8#mL2zR7pXw4kT";
+    // Registered email by user id, for logging in.
+    private static final Map<String, String> EMAILS = new ConcurrentHashMap<>();
+
+    private static TestServer server;
+    private static String tellerToken;
+
+    @BeforeAll
+    static void startServer() {
+        server = new TestServer().start();
+    }
+
+    @AfterAll
+    static void stopServer() {
+        if (server != null) {
+            server.close();
+        }
+    }
+
+    @Test
+    void transferMovesMoneyBetweenAccounts() throws Exception {
+        String userId = createUser();
+        String from = createAccount(userId);
+        String to = createAccount(userId);
+        String token = login(userId);
+
+        assertEquals(201, deposit(from, "100.00").statusCode());
+
+        HttpResponse<String> transfer = transfer(token, from, to, "40.00");
+        assertEquals(201, transfer.statusCode(), transfer.body());
+        JsonNode txn = MAPPER.readTree(transfer.body());
+        assertEquals("authorized", txn.get("status").asText());
+
+        HttpResponse<String> captured = action(token, txn.get("id").asText(), "capture");
+        assertEquals(200, captured.statusCode(), captured.body());
+        assertEquals("completed", MAPPER.readTree(captured.body()).get("status").asText());
+
+        BigDecimal fee = txn.get("fee_amount").decimalValue();
+        assertAmount(new BigDecimal("60.00").subtract(fee), balance(from));
+        assertAmount(new BigDecimal("40.00"), balance(to));
+    }
+
+    @Test
+    void transferWithoutTokenIsRejected() throws Exception {
+        String userId = createUser();
+        String from = createAccount(userId);
+        String to = createAccount(userId);
+
+        assertEquals(401, transfer(null, from, to, "1.00").statusCode());
+        assertEquals(401, transfer("not-a-token", from, to, "1.00").statusCode());
+    }
+
+    @Test
+    void transferExceedingBalanceLeavesBothAccountsUnchanged() throws Exception {
+        String userId = createUser();
+        String from = createAccount(userId);
+        String to = createAccount(userId);
+        String token = login(userId);
+        assertEquals(201, deposit(from, "10.00").statusCode());
+
+        HttpResponse<String> transfer = transfer(token, from, to, "25.00");
+        assertEquals(422, transfer.statusCode(), transfer.body());
+
+        assertAmount(new BigDecimal("10.00"), balance(from));
+        assertAmount(BigDecimal.ZERO, balance(to));
+    }
+
+    @Test
+    void voidedTransferReleasesTheHold() throws Exception {
+        String userId = createUser();
+        String from = createAccount(userId);
+        String to = createAccount(userId);
+        String token = login(userId);
+        assertEquals(201, deposit(from, "50.00").statusCode());
+
+        String id = MAPPER.readTree(transfer(token, from, to, "30.00").body()).get("id").asText();
+        // 30.00 of the 50.00 is held, so a second transfer of the same size can't be covered
+        assertEquals(422, transfer(token, from, to, "30.00").statusCode());
+
+        assertEquals(200, action(token, id, "void").statusCode());
+        assertEquals(409, action(token, id, "capture").statusCode());
+
+        assertAmount(new BigDecimal("50.00"), balance(from));
+        assertAmount(BigDecimal.ZERO, balance(to));
+    }
+
+    @Test
+    void transferIsRecordedInBothAccountsHistories() throws Exception {
+        String userId = createUser();
+        String from = createAccount(userId);
+        String to = createAccount(userId);
+        String token = login(userId);
+        assertEquals(201, deposit(from, "20.00").statusCode());
+
+        String id = MAPPER.readTree(transfer(token, from, to, "5.00").body()).get("id").asText();
+        assertEquals(200, action(token, id, "capture").statusCode());
+
+        for (String accountId : new String[] {from, to}) {
+            HttpResponse<String> history = send(HttpRequest.newBuilder(
+                            URI.create(server.transactionUrl() + "/transactions?account_id=" + accountId)), token);
+            assertEquals(200, history.statusCode(), history.body());
+            boolean found = false;
+            for (JsonNode txn : MAPPER.readTree(history.body()).get("transactions")) {
+                found |= txn.get("id").asText().equals(id);
+            }
+            assertTrue(found, "transfer missing from history of " + accountId);
+        }
+    }
+
+    @Test
+    void accountCurrencyIsLockedByTheDatabaseAfterTheFirstTransaction() throws Exception {
+        String accountId = createAccount(createUser());
+
+        // No history yet, so the currency can still change, and change back.
+        assertEquals(200, updateCurrency(accountId, "EUR").statusCode());
+        assertEquals(200, updateCurrency(accountId, "USD").statusCode());
+
+        assertEquals(201, deposit(accountId, "10.00").statusCode());
+
+        HttpResponse<String> rejected = updateCurrency(accountId, "EUR");
+        assertEquals(409, rejected.statusCode(), rejected.body());
+        assertEquals("currency_immutable", MAPPER.readTree(rejected.body()).get("code").asText());
+
+        // The trigger fires for any writer, not only for account-service.
+        Container.ExecResult direct = server.psql("account_db",
+                "UPDATE accounts SET currency = 'EUR' WHERE id = '" + accountId + "'");
+        assertNotEquals(0, direct.getExitCode());
+        assertTrue(direct.getStderr().contains("cannot change after its first transaction"), direct.getStderr());
+        // Other columns can still be updated.
+        assertEquals(0, server.psql("account_db",
+                "UPDATE accounts SET updated_at = NOW() WHERE id = '" + accountId + "'").getExitCode());
+
+        HttpResponse<String> account = send(HttpRequest.newBuilder(
+                URI.create(server.accountUrl() + "/api/v1/accounts/" + accountId)), null);
+        assertEquals("USD", MAPPER.readTree(account.body()).get("currency").asText());
+    }
+
+    @Test
+    void docsServeEachServicesSpec() throws Exception {
+        HttpClient browser = HttpClient.newBuilder().followRedirects(HttpClient.Redirect.NORMAL).build();
+        Map<String, String> knownPaths = Map.of(
+                server.authUrl(), "/api/v1/auth/login",
+                server.accountUrl(), "/api/v1/users",
+                server.transactionUrl(), "/transactions/transfer");
+
+        for (Map.Entry<String, String> service : knownPaths.entrySet()) {
+            String baseUrl = service.getKey();
+
+            // /docs redirects to the swagger-ui page, whose config points at /openapi.json
+            HttpResponse<String> docs = browser.send(HttpRequest.newBuilder(URI.create(baseUrl + "/docs")).build(),
+                    HttpResponse.BodyHandlers.ofString());
+            assertEquals(200, docs.statusCode(), baseUrl);
+            assertTrue(docs.headers().firstValue("Content-Type").orElse("").startsWith("text/html"), baseUrl);
+            assertTrue(docs.body().contains("swagger-ui"), baseUrl);
+
+            HttpResponse<String> config = browser.send(
+                    HttpRequest.newBuilder(URI.create(baseUrl + "/openapi.json/swagger-config")).build(),
+                    HttpResponse.BodyHandlers.ofString());
+            assertEquals(200, config.statusCode(), baseUrl);
+            assertEquals("/openapi.json", MAPPER.readTree(config.body()).get("url").asText(), baseUrl);
+
+            HttpResponse<String> spec = browser.send(
+                    HttpRequest.newBuilder(URI.create(baseUrl + "/openapi.json")).build(),
+                    HttpResponse.BodyHandlers.ofString());
+            assertEquals(200, spec.statusCode(), baseUrl);
+            JsonNode openapi = MAPPER.readTree(spec.body());
+            assertTrue(openapi.get("openapi").asText().startsWith("3."), baseUrl);
+            assertTrue(openapi.get("paths").has(service.getValue()), baseUrl + " documents " + service.getValue());
+        }
+    }
+
+    // Registers through auth-service, which creates the user in account-service and
+    // stores the password for login.
+    private String createUser() throws IOException, InterruptedException {
+        String email = "user-" + UUID.randomUUID() + "@example.com";
+        HttpResponse<String> response = post(server.authUrl() + "/api/v1/auth/register", null,
+                "{\"email\":\"" + email + "\",\"password\":\"" + PASSWORD + "\",\"full_name\":\"Integration User\"}");
+        assertEquals(201, response.statusCode(), response.body());
+        String userId = MAPPER.readTree(response.body()).get("user_id").asText();
+        EMAILS.put(userId, email);
+        return userId;
+    }
+
+    private String createAccount(String userId) throws IOException, InterruptedException {
+        HttpResponse<String> response = post(server.accountUrl() + "/api/v1/accounts", null,
+                "{\"user_id\":\"" + userId + "\",\"account_type\":\"checking\",\"currency\":\"USD\"}");
+        assertEquals(201, response.statusCode(), response.body());
+        return MAPPER.readTree(response.body()).get("id").asText();
+    }
+
+    private String login(String userId) throws IOException, InterruptedException {
+        HttpResponse<String> response = post(server.authUrl() + "/api/v1/auth/login", null,
+                "{\"email\":\"" + EMAILS.get(userId) + "\",\"password\":\"" + PASSWORD + "\"}");
+        assertEquals(200, response.statusCode(), response.body());
+        return MAPPER.readTree(response.body()).get("access_token").asText();
+    }
+
+    private String tellerToken() throws IOException, InterruptedException {
+        if (tellerToken == null) {
+            String tellerId = createUser();
+            server.grantRole(tellerId, "teller");
+            tellerToken = login(tellerId);
+        }
+        return tellerToken;
+    }
+
+    private BigDecimal balance(String accountId) throws IOException, InterruptedException {
+        HttpResponse<String> response = send(HttpRequest.newBuilder(
+                URI.create(server.accountUrl() + "/api/v1/accounts/" + accountId)), null);
+        assertEquals(200, response.statusCode(), response.body());
+        return MAPPER.readTree(response.body()).get("balance").decimalValue();
+    }
+
+    // Deposits are keyed in by a teller, not by the account holder.
+    private HttpResponse<String> deposit(String accountId, String amount)
+            throws IOException, InterruptedException {
+        return post(server.transactionUrl() + "/transactions/deposit", tellerToken(),
+                "{\"account_id\":\"" + accountId + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}");
+    }
+
+    private HttpResponse<String> updateCurrency(String accountId, String currency)
+            throws IOException, InterruptedException {
+        HttpRequest.Builder request = HttpRequest.newBuilder(
+                        URI.create(server.accountUrl() + "/api/v1/accounts/" + accountId + "/currency"))
+                .header("Content-Type", "application/json")
+                .method("PATCH", HttpRequest.BodyPublishers.ofString("{\"currency\":\"" + currency + "\"}"));
+        return send(request, null);
+    }
+
+    private HttpResponse<String> transfer(String token, String from, String to, String amount)
+            throws IOException, InterruptedException {
+        return post(server.transactionUrl() + "/transactions/transfer", token,
+                "{\"from_account_id\":\"" + from + "\",\"to_account_id\":\"" + to
+                        + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}");
+    }
+
+    private HttpResponse<String> action(String token, String transactionId, String action)
+            throws IOException, InterruptedException {
+        return post(server.transactionUrl() + "/transactions/" + transactionId + "/" + action, token, "");
+    }
+
+    private HttpResponse<String> post(String url, String token, String body)
+            throws IOException, InterruptedException {
+        HttpRequest.Builder request = HttpRequest.newBuilder(URI.create(url))
+                .header("Content-Type", "application/json")
+                .POST(HttpRequest.BodyPublishers.ofString(body));
+        return send(request, token);
+    }
+
+    private HttpResponse<String> send(HttpRequest.Builder request, String token)
+            throws IOException, InterruptedException {
+        request.header("X-Tenant-ID", TestServer.DEFAULT_TENANT);
+        if (token != null) {
+            request.header("Authorization", "Bearer " + token);
+        }
+        return HTTP.send(request.build(), HttpResponse.BodyHandlers.ofString());
+    }
+
+    private static void assertAmount(BigDecimal expected, BigDecimal actual) {
+        assertEquals(0, expected.compareTo(actual), "expected " + expected + " but was " + actual);
+    }
+}
@@ -0,0 +1,162 @@
+package com.kubesec.integration;
+
+import org.testcontainers.containers.Container;
+import org.testcontainers.containers.GenericContainer;
+import org.testcontainers.containers.Network;
+import org.testcontainers.containers.PostgreSQLContainer;
+import org.testcontainers.containers.wait.strategy.Wait;
+import org.testcontainers.images.builder.ImageFromDockerfile;
+import org.testcontainers.utility.MountableFile;
+
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.nio.file.Path;
+import java.time.Duration;
+import java.util.Map;
+
+// Runs Postgres, Redis, NATS and the three services on a private Docker network, wired
+// the same way as docker-compose.yaml. The services are built from their own Dockerfiles
+// rather than started in-process: each jar carries its own application.yaml and
+// db/migration on the classpath root, so they can't share a JVM.
+public class TestServer implements AutoCloseable {
+
+    // Seeded by every service's tenants migration.
+    public static final String DEFAULT_TENANT = "00000000-0000-0000-0000-000000000001";
+
+    private static final String DB_USER = "kubesec";
+    private static final String DB_PASSWORD = "kubesec_secret";
+    private static final Duration STARTUP_TIMEOUT = Duration.ofMinutes(3);
+
+    private final Network network = Network.newNetwork();
+    private final PostgreSQLContainer<?> postgres;
+    private final GenericContainer<?> redis;
+    private final GenericContainer<?> nats;
+    private final GenericContainer<?> auth;
+    private final GenericContainer<?> account;
+    private final GenericContainer<?> transaction;
+
+    public TestServer() {
+        Path root = Path.of("..").toAbsolutePath().normalize();
+
+        postgres = new PostgreSQLContainer<>("postgres:16-alpine")
+                .withNetwork(network)
+                .withNetworkAliases("postgres")
+                .withUsername(DB_USER)
+                .withPassword(DB_PASSWORD)
+                .withDatabaseName("postgres")
+                .withCopyFileToContainer(MountableFile.forHostPath(root.resolve("scripts/init-databases.sql")),
+                        "/docker-entrypoint-initdb.d/01-init-databases.sql");
+        redis = new GenericContainer<>("redis:7-alpine")
+                .withNetwork(network)
+                .withNetworkAliases("redis")
+                .withExposedPorts(6379);
+        nats = new GenericContainer<>("nats:2-alpine")
+                .withNetwork(network)
+                .withNetworkAliases("nats")
+                .withCommand("--jetstream", "--http_port", "8222")
+                .withExposedPorts(4222, 8222)
+                .waitingFor(Wait.forHttp("/healthz").forPort(8222));
+
+        auth = service(root, "auth-service", 8082, "/healthz", Map.of(
+                "DB_NAME", "auth_db",
+                "REDIS_HOST", "redis",
+                "REDIS_PORT", "6379",
+                "JWT_SECRET", "integration-test-secret-integration-test-secret",
+                "ACCOUNT_SERVICE_URL", "http://account-service:8081"));
+        account = service(root, "account-service", 8081, "/health", Map.of(
+                "DB_NAME", "account_db",
+                "NATS_URL", "nats://nats:4222",
+                "AUTH_SERVICE_URL", "http://auth-service:8082",
+                "TRANSACTION_SERVICE_URL", "http://transaction-service:8083"));
+        transaction = service(root, "transaction-service", 8083, "/health", Map.of(
+                "DB_NAME", "transaction_db",
+                "NATS_URL", "nats://nats:4222",
+                "REDIS_HOST", "redis",
+                "REDIS_PORT", "6379",
+                "AUTH_SERVICE_URL", "http://auth-service:8082",
+                "ACCOUNT_SERVICE_URL", "http://account-service:8081"));
+    }
+
+    // Starts the infrastructure first, then the services in dependency order. Anything
+    // already started is stopped again if a later container fails to come up.
+    public TestServer start() {
+        try {
+            postgres.start();
+            redis.start();
+            nats.start();
+            auth.start();
+            account.start();
+            transaction.start();
+        } catch (RuntimeException e) {
+            close();
+            throw e;
+        }
+        return this;
+    }
+
+    public String authUrl() {
+        return url(auth, 8082);
+    }
+
+    public String accountUrl() {
+        return url(account, 8081);
+    }
+
+    public String transactionUrl() {
+        return url(transaction, 8083);
+    }
+
+    @Override
+    public void close() {
+        transaction.stop();
+        account.stop();
+        auth.stop();
+        nats.stop();
+        redis.stop();
+        postgres.stop();
+        network.close();
+    }
+
+    // There is no API for granting staff roles; an operator sets them in auth_db.
+    public void grantRole(String userId, String role) {
+        Container.ExecResult result = psql("auth_db",
+                "UPDATE user_credentials SET role = '" + role + "' WHERE user_id = '" + userId + "'");
+        if (result.getExitCode() != 0) {
+            throw new IllegalStateException("grant role: " + result.getStderr());
+        }
+    }
+
+    // Runs one statement directly against a service's database, bypassing the service.
+    // A failed statement is returned with a non-zero exit code and the error on stderr.
+    public Container.ExecResult psql(String database, String sql) {
+        try {
+            return postgres.execInContainer("psql", "-U", DB_USER, "-d", database, "-v", "ON_ERROR_STOP=1", "-c", sql);
+        } catch (IOException e) {
+            throw new UncheckedIOException(e);
+        } catch (InterruptedException e) {
+            Thread.currentThread().interrupt();
+            throw new IllegalStateException("psql interrupted", e);
+        }
+    }
+
+    private GenericContainer<?> service(Path root, String name, int port, String healthPath,
+                                        Map<String, String> env) {
+        ImageFromDockerfile image = new ImageFromDockerfile("kubesec/" + name + "-it", false)
+                .withFileFromPath(".", root.resolve("services").resolve(name));
+        return new GenericContainer<>(image)
+                .withNetwork(network)
+                .withNetworkAliases(name)
+                .withEnv("DB_HOST", "postgres")
+                .withEnv("DB_PORT", "5432")
+                .withEnv("DB_USER", DB_USER)
+                .withEnv("DB_PASSWORD", DB_PASSWORD)
+                .withEnv("SERVER_PORT", String.valueOf(port))
+                .withEnv(env)
+                .withExposedPorts(port)
+                .waitingFor(Wait.forHttp(healthPath).forPort(port).withStartupTimeout(STARTUP_TIMEOUT));
+    }
+
+    private static String url(GenericContainer<?> container, int port) {
+        return "http://" + container.getHost() + ":" + container.getMappedPort(port);
+    }
+}
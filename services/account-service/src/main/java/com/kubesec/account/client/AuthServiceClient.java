@@ -0,0 +1,52 @@
+package com.kubesec.account.client;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.filter.LogContextInterceptor;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.tenant.TenantHeaderInterceptor;
+import org.springframework.stereotype.Component;
+import org.springframework.web.client.RestClient;
+
+import java.time.OffsetDateTime;
+import java.util.Map;
+
+@Component
+public class AuthServiceClient {
+
+    private final RestClient restClient;
+
+    // The builder is Spring's, so calls carry the current trace in a traceparent header.
+    public AuthServiceClient(AppConfig config, RestClient.Builder restClientBuilder) {
+        this.restClient = restClientBuilder
+                .baseUrl(config.getAuthServiceUrl())
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .requestInterceptor(new LogContextInterceptor())
+                .build();
+    }
+
+    public int countFailedLogins(String email, OffsetDateTime since, String authHeader) {
+        FailedCountResponse response = restClient.get()
+                .uri(uri -> uri.path("/api/v1/auth/login-attempts/failed")
+                        .queryParam("email", email)
+                        .queryParam("since", since.toInstant().toString())
+                        .build())
+                .header("Authorization", authHeader)
+                .retrieve()
+                .body(FailedCountResponse.class);
+        return response != null ? response.failed_count() : 0;
+    }
+
+    // The tenant is passed explicitly because platform-level routes run without one bound.
+    public ValidateResponse validateToken(String token, String tenantId) {
+        return restClient.post()
+                .uri("/api/v1/auth/validate")
+                .header(TenantContext.HEADER, tenantId)
+                .body(Map.of("token", token))
+                .retrieve()
+                .body(ValidateResponse.class);
+    }
+
+    public record FailedCountResponse(int failed_count) {}
+
+    public record ValidateResponse(boolean valid, String user_id, String role) {}
+}
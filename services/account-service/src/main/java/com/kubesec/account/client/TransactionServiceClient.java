@@ -0,0 +1,86 @@
+package com.kubesec.account.client;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.filter.LogContextInterceptor;
+import com.kubesec.account.tenant.TenantHeaderInterceptor;
+import org.springframework.stereotype.Component;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+@Component
+public class TransactionServiceClient {
+
+    // The largest page transaction-service serves.
+    private static final int PAGE_SIZE = 100;
+
+    private final RestClient restClient;
+
+    // The builder is Spring's, so calls carry the current trace in a traceparent header.
+    public TransactionServiceClient(AppConfig config, RestClient.Builder restClientBuilder) {
+        this.restClient = restClientBuilder
+                .baseUrl(config.getTransactionServiceUrl())
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .requestInterceptor(new LogContextInterceptor())
+                .build();
+    }
+
+    public int countFailedTransactions(List<UUID> accountIds, OffsetDateTime since, String authHeader) {
+        FailedCountResponse response = restClient.get()
+                .uri(uri -> uri.path("/transactions/failed-count")
+                        .queryParam("account_id", accountIds.toArray())
+                        .queryParam("since", since.toInstant().toString())
+                        .build())
+                .header("Authorization", authHeader)
+                .retrieve()
+                .body(FailedCountResponse.class);
+        return response != null ? response.failed_count() : 0;
+    }
+
+    // Pages through GET /transactions oldest first until next_cursor runs out. Both
+    // bounds are inclusive, as the listing applies them.
+    public List<TransactionRecord> listTransactions(UUID accountId, OffsetDateTime createdAfter,
+                                                    OffsetDateTime createdBefore, String authHeader) {
+        List<TransactionRecord> transactions = new ArrayList<>();
+        String cursor = null;
+        do {
+            String pageCursor = cursor;
+            TransactionPage page = restClient.get()
+                    .uri(uri -> {
+                        uri.path("/transactions")
+                                .queryParam("account_id", accountId)
+                                .queryParam("created_after", createdAfter.toInstant().toString())
+                                .queryParam("created_before", createdBefore.toInstant().toString())
+                                .queryParam("sort_order", "asc")
+                                .queryParam("limit", PAGE_SIZE);
+                        if (pageCursor != null) {
+                            uri.queryParam("cursor", pageCursor);
+                        }
+                        return uri.build();
+                    })
+                    .header("Authorization", authHeader)
+                    .retrieve()
+                    .body(TransactionPage.class);
+            if (page == null) {
+                break;
+            }
+            transactions.addAll(page.transactions());
+            cursor = page.next_cursor();
+        } while (cursor != null);
+        return transactions;
+    }
+
+    public record FailedCountResponse(int failed_count) {}
+
+    public record TransactionPage(List<TransactionRecord> transactions, String next_cursor) {}
+
+    // The fields of a transaction-service transaction that statements need.
+    public record TransactionRecord(UUID id, UUID from_account_id, UUID to_account_id, BigDecimal amount,
+                                    String currency, String type, String status, String description,
+                                    BigDecimal converted_amount, BigDecimal fee_amount,
+                                    OffsetDateTime created_at) {}
+}
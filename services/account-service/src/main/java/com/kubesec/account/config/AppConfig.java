@@ -0,0 +1,105 @@
+package com.kubesec.account.config;
+
+import org.springframework.boot.context.properties.ConfigurationProperties;
+import org.springframework.context.annotation.Configuration;
+
+import java.time.Duration;
+import java.util.List;
+
+@Configuration
+@ConfigurationProperties(prefix = "app")
+public class AppConfig {
+
+    private String natsUrl = "nats://localhost:4222";
+    private String authServiceUrl = "http://localhost:8082";
+    private String transactionServiceUrl = "http://localhost:8083";
+    private int dormancyFreezeAfterDays = 0; // 0 disables the freeze job
+    private String mtlsCaCertFile = "";
+    private String mtlsServerCertFile = "";
+    private String mtlsServerKeyFile = "";
+    private String tlsCertFile = ""; // server TLS without client certs; see TlsEnvironmentPostProcessor
+    private String tlsKeyFile = "";
+    private int dbSchemaRetryAttempts = 8; // 0 skips the schema check
+    private int dbMaxIdleConns; // defaulted by DbPoolEnvironmentPostProcessor
+    private Duration dbSchemaRetryBackoff = Duration.ofSeconds(1);
+    private long maxContentLengthBytes = 1024 * 1024; // declared Content-Length limit
+    private long maxBodyReadBytes = 1024 * 1024; // limit on bytes actually read, for chunked bodies
+    private String linkedAccountHashKey = "change-me-in-production"; // HMAC key for linked account numbers
+    private String creditBureauUrl = ""; // empty disables credit score lookups
+    private int userDefaultMaxAccounts = 5;
+    private String serviceTokenPublicKeyFile = ""; // auth-service's RSA public key, PEM; empty rejects every service call
+    private String internalToken = ""; // X-Internal-Token secret for back-office routes; empty rejects every call
+    private String otelEndpoint = ""; // OTLP collector base URL; empty keeps traces in-process
+    private String serviceName = "account-service"; // service.name on exported spans
+    private List<String> corsAllowedOrigins = List.of("*"); // * allows any origin
+
+    public String getNatsUrl() { return natsUrl; }
+    public void setNatsUrl(String natsUrl) { this.natsUrl = natsUrl; }
+
+    public String getAuthServiceUrl() { return authServiceUrl; }
+    public void setAuthServiceUrl(String authServiceUrl) { this.authServiceUrl = authServiceUrl; }
+
+    public String getTransactionServiceUrl() { return transactionServiceUrl; }
+    public void setTransactionServiceUrl(String transactionServiceUrl) { this.transactionServiceUrl = transactionServiceUrl; }
+
+    public int getDormancyFreezeAfterDays() { return dormancyFreezeAfterDays; }
+    public void setDormancyFreezeAfterDays(int dormancyFreezeAfterDays) { this.dormancyFreezeAfterDays = dormancyFreezeAfterDays; }
+
+    public String getMtlsCaCertFile() { return mtlsCaCertFile; }
+    public void setMtlsCaCertFile(String mtlsCaCertFile) { this.mtlsCaCertFile = mtlsCaCertFile; }
+
+    public String getMtlsServerCertFile() { return mtlsServerCertFile; }
+    public void setMtlsServerCertFile(String mtlsServerCertFile) { this.mtlsServerCertFile = mtlsServerCertFile; }
+
+    public String getMtlsServerKeyFile() { return mtlsServerKeyFile; }
+    public void setMtlsServerKeyFile(String mtlsServerKeyFile) { this.mtlsServerKeyFile = mtlsServerKeyFile; }
+
+    public String getTlsCertFile() { return tlsCertFile; }
+    public void setTlsCertFile(String tlsCertFile) { this.tlsCertFile = tlsCertFile; }
+
+    public String getTlsKeyFile() { return tlsKeyFile; }
+    public void setTlsKeyFile(String tlsKeyFile) { this.tlsKeyFile = tlsKeyFile; }
+
+    public int getDbSchemaRetryAttempts() { return dbSchemaRetryAttempts; }
+    public void setDbSchemaRetryAttempts(int dbSchemaRetryAttempts) { this.dbSchemaRetryAttempts = dbSchemaRetryAttempts; }
+
+    public int getDbMaxIdleConns() { return dbMaxIdleConns; }
+    public void setDbMaxIdleConns(int dbMaxIdleConns) { this.dbMaxIdleConns = dbMaxIdleConns; }
+
+    public Duration getDbSchemaRetryBackoff() { return dbSchemaRetryBackoff; }
+    public void setDbSchemaRetryBackoff(Duration dbSchemaRetryBackoff) { this.dbSchemaRetryBackoff = dbSchemaRetryBackoff; }
+
+    public String getLinkedAccountHashKey() { return linkedAccountHashKey; }
+    public void setLinkedAccountHashKey(String linkedAccountHashKey) { this.linkedAccountHashKey = linkedAccountHashKey; }
+
+    public String getCreditBureauUrl() { return creditBureauUrl; }
+    public void setCreditBureauUrl(String creditBureauUrl) { this.creditBureauUrl = creditBureauUrl; }
+
+    public int getUserDefaultMaxAccounts() { return userDefaultMaxAccounts; }
+    public void setUserDefaultMaxAccounts(int userDefaultMaxAccounts) { this.userDefaultMaxAccounts = userDefaultMaxAccounts; }
+
+    public boolean isMtlsEnabled() {
+        return !mtlsCaCertFile.isBlank() && !mtlsServerCertFile.isBlank() && !mtlsServerKeyFile.isBlank();
+    }
+
+    public long getMaxContentLengthBytes() { return maxContentLengthBytes; }
+    public void setMaxContentLengthBytes(long maxContentLengthBytes) { this.maxContentLengthBytes = maxContentLengthBytes; }
+
+    public long getMaxBodyReadBytes() { return maxBodyReadBytes; }
+    public void setMaxBodyReadBytes(long maxBodyReadBytes) { this.maxBodyReadBytes = maxBodyReadBytes; }
+
+    public String getServiceTokenPublicKeyFile() { return serviceTokenPublicKeyFile; }
+    public void setServiceTokenPublicKeyFile(String serviceTokenPublicKeyFile) { this.serviceTokenPublicKeyFile = serviceTokenPublicKeyFile; }
+
+    public String getInternalToken() { return internalToken; }
+    public void setInternalToken(String internalToken) { this.internalToken = internalToken; }
+
+    public String getOtelEndpoint() { return otelEndpoint; }
+    public void setOtelEndpoint(String otelEndpoint) { this.otelEndpoint = otelEndpoint; }
+
+    public String getServiceName() { return serviceName; }
+    public void setServiceName(String serviceName) { this.serviceName = serviceName; }
+
+    public List<String> getCorsAllowedOrigins() { return corsAllowedOrigins; }
+    public void setCorsAllowedOrigins(List<String> corsAllowedOrigins) { this.corsAllowedOrigins = corsAllowedOrigins; }
+}
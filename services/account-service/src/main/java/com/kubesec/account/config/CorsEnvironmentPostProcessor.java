@@ -0,0 +1,31 @@
+package com.kubesec.account.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Arrays;
+import java.util.Map;
+
+// CORS_ALLOWED_ORIGINS is a comma-separated list of the browser origins allowed to call
+// the API. Unset, any origin is allowed outside production; ENV=production refuses to
+// start without an explicit list, and never accepts the * wildcard.
+public class CorsEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    static final String PROPERTY = "CORS_ALLOWED_ORIGINS";
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String origins = environment.getProperty(PROPERTY, "");
+        boolean production = "production".equalsIgnoreCase(environment.getProperty("ENV"));
+        if (origins.isBlank()) {
+            if (production) {
+                throw new IllegalStateException(PROPERTY + " must list the allowed origins when ENV=production");
+            }
+            environment.getPropertySources().addLast(new MapPropertySource("corsDefaults", Map.of(PROPERTY, "*")));
+        } else if (production && Arrays.stream(origins.split(",")).anyMatch(o -> o.strip().equals("*"))) {
+            throw new IllegalStateException(PROPERTY + " must not allow every origin when ENV=production");
+        }
+    }
+}
@@ -0,0 +1,24 @@
+package com.kubesec.account.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Map;
+
+// Production deployments must not advertise the API surface, so ENV=production
+// switches off both the generated spec and the swagger-ui pages.
+public class DocsEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        if (!"production".equalsIgnoreCase(environment.getProperty("ENV"))) {
+            return;
+        }
+        environment.getPropertySources().addFirst(new MapPropertySource("docsDisabled", Map.of(
+                "springdoc.api-docs.enabled", false,
+                "springdoc.swagger-ui.enabled", false
+        )));
+    }
+}
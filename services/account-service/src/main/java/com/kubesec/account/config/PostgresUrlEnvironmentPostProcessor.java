@@ -0,0 +1,33 @@
+package com.kubesec.account.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.HashMap;
+import java.util.Map;
+
+// When POSTGRES_URL is set it takes precedence over the DB_HOST/DB_PORT/DB_NAME parts
+// assembled in application.yaml.
+public class PostgresUrlEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String url = environment.getProperty("POSTGRES_URL");
+        if (url == null || url.isBlank()) {
+            return;
+        }
+
+        PostgresUrl parsed = PostgresUrl.parse(url);
+        Map<String, Object> properties = new HashMap<>();
+        properties.put("spring.datasource.url", parsed.toJdbcUrl());
+        if (parsed.username() != null) {
+            properties.put("spring.datasource.username", parsed.username());
+        }
+        if (parsed.password() != null) {
+            properties.put("spring.datasource.password", parsed.password());
+        }
+        environment.getPropertySources().addFirst(new MapPropertySource("postgresUrl", properties));
+    }
+}
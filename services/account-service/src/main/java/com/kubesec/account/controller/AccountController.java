@@ -1,14 +1,46 @@
 package com.kubesec.account.controller;
 
+import com.kubesec.account.exception.ForbiddenException;
+import com.kubesec.account.exception.UnauthorizedException;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.ServiceTokenFilter;
+import com.kubesec.account.exception.ValidationException;
 import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountBalances;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.model.Tenant;
 import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.AdjustBalanceRequest;
+import com.kubesec.account.model.dto.AdjustCurrencyBalanceRequest;
 import com.kubesec.account.model.dto.CreateAccountRequest;
 import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.model.dto.UpdateBalanceRequest;
+import com.kubesec.account.model.dto.UpdateCurrencyRequest;
+import com.kubesec.account.model.dto.UpdateKycStatusRequest;
+import com.kubesec.account.model.dto.UpdateDepositLimitRequest;
+import com.kubesec.account.model.dto.UpdateStatementPreferencesRequest;
+import com.kubesec.account.model.dto.UpdateUserRequest;
+import com.kubesec.account.risk.RiskScore;
+import com.kubesec.account.risk.RiskScorer;
 import com.kubesec.account.service.AccountService;
+import com.kubesec.account.statement.Statement;
+import com.kubesec.account.statement.StatementCsv;
+import com.kubesec.account.statement.StatementGenerator;
+import com.kubesec.account.validation.ValidatedBody;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.format.annotation.DateTimeFormat;
+import org.springframework.http.CacheControl;
+import org.springframework.http.ContentDisposition;
+import org.springframework.http.HttpHeaders;
 import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.*;
 
+import java.io.IOException;
+import java.time.LocalDate;
+import java.util.LinkedHashMap;
 import java.util.List;
 import java.util.Map;
 import java.util.UUID;
@@ -17,9 +49,14 @@ import java.util.UUID;
 public class AccountController {
 
     private final AccountService accountService;
+    private final RiskScorer riskScorer;
+    private final StatementGenerator statementGenerator;
 
-    public AccountController(AccountService accountService) {
+    public AccountController(AccountService accountService, RiskScorer riskScorer,
+                             StatementGenerator statementGenerator) {
         this.accountService = accountService;
+        this.riskScorer = riskScorer;
+        this.statementGenerator = statementGenerator;
     }
 
     @GetMapping("/health")
@@ -28,18 +65,89 @@ public class AccountController {
     }
 
     @PostMapping("/api/v1/users")
-    public ResponseEntity<User> createUser(@RequestBody CreateUserRequest request) {
-        if (request.email() == null || request.email().isEmpty()
-                || request.fullName() == null || request.fullName().isEmpty()) {
-            throw new IllegalArgumentException("email and full_name are required");
-        }
+    public ResponseEntity<User> createUser(@RequestBody @ValidatedBody("create-user") CreateUserRequest request) {
         User user = accountService.createUser(request);
         return ResponseEntity.status(HttpStatus.CREATED).body(user);
     }
 
     @GetMapping("/api/v1/users/{id}")
-    public User getUser(@PathVariable UUID id) {
-        return accountService.getUser(id);
+    public User getUser(@PathVariable UUID id, HttpServletRequest request) {
+        User user = accountService.getUser(id);
+        ServerPush.push(request, List.of("/api/v1/users/" + id + "/accounts"));
+        return user;
+    }
+
+    // Users may edit their own profile; admins may edit anyone's.
+    @PatchMapping("/api/v1/users/{id}")
+    public User updateUser(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId,
+            @RequestBody @ValidatedBody("update-user") UpdateUserRequest request) {
+        if (!"admin".equals(role)) {
+            if (callerId == null) {
+                throw new UnauthorizedException("authenticated user required");
+            }
+            if (!callerId.equals(id)) {
+                throw new ForbiddenException("users may only update their own profile");
+            }
+        }
+        return accountService.updateUser(id, request);
+    }
+
+    @DeleteMapping("/api/v1/users/{id}")
+    public ResponseEntity<Void> eraseUser(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId) {
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+        if (callerId == null) {
+            throw new UnauthorizedException("authenticated user required");
+        }
+        accountService.eraseUser(id, callerId.toString());
+        return ResponseEntity.noContent().build();
+    }
+
+    @PatchMapping("/api/v1/users/{id}/preferred-currency")
+    public User updatePreferredCurrency(@PathVariable UUID id, @RequestBody @ValidatedBody("update-currency") UpdateCurrencyRequest request) {
+        return accountService.updatePreferredCurrency(id, request);
+    }
+
+    // Back-office only; InternalTokenFilter checks X-Internal-Token.
+    @PatchMapping("/api/v1/users/{id}/kyc")
+    public User updateKycStatus(@PathVariable UUID id, @RequestBody @ValidatedBody("update-kyc-status") UpdateKycStatusRequest request) {
+        return accountService.updateKycStatus(id, request);
+    }
+
+    @GetMapping("/api/v1/users/{id}/risk-score")
+    public RiskScore getRiskScore(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestHeader(name = "Authorization", required = false) String authHeader) {
+        if (!"admin".equals(role) && !"auditor".equals(role)) {
+            throw new ForbiddenException("admin or auditor role required");
+        }
+        return riskScorer.computeRiskScore(id, authHeader);
+    }
+
+    @GetMapping("/api/v1/admin/users")
+    public List<User> listUsersByCountry(
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestParam String country) {
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+        return accountService.getUsersByCountry(country);
+    }
+
+    @GetMapping("/api/v1/admin/tenants")
+    public List<Tenant> listTenants(@RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role) {
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+        return accountService.listTenants();
     }
 
     @GetMapping("/api/v1/users/{id}/accounts")
@@ -47,14 +155,190 @@ public class AccountController {
         return accountService.listAccountsByUser(id);
     }
 
+    @GetMapping("/api/v1/users/{id}/accounts/{accountId}")
+    public Account getUserAccount(
+            @PathVariable UUID id,
+            @PathVariable UUID accountId,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId) {
+        if (callerId == null) {
+            throw new UnauthorizedException("authenticated user required");
+        }
+        if (!callerId.equals(id)) {
+            throw new ForbiddenException("cannot access another user's accounts");
+        }
+        return accountService.getUserAccount(id, accountId);
+    }
+
     @PostMapping("/api/v1/accounts")
-    public ResponseEntity<Account> createAccount(@RequestBody CreateAccountRequest request) {
+    public ResponseEntity<Account> createAccount(@RequestBody @ValidatedBody("create-account") CreateAccountRequest request) {
         Account account = accountService.createAccount(request);
         return ResponseEntity.status(HttpStatus.CREATED).body(account);
     }
 
+    @GetMapping("/api/v1/accounts")
+    public Map<String, Object> listAccounts(
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestParam(required = false) String status,
+            @RequestParam(name = "account_type", required = false) String accountType,
+            @RequestParam(name = "user_id", required = false) UUID userId,
+            @RequestParam(required = false, defaultValue = "20") int limit,
+            @RequestParam(required = false, defaultValue = "0") int offset) {
+
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+
+        if (limit < 1 || limit > 100) limit = 20;
+        if (offset < 0) offset = 0;
+
+        AccountFilter filter = new AccountFilter();
+        filter.setStatus(status);
+        filter.setAccountType(accountType);
+        filter.setUserId(userId);
+        filter.setLimit(limit);
+        filter.setOffset(offset);
+
+        List<Account> accounts = accountService.listAccounts(filter);
+
+        Map<String, Object> response = new LinkedHashMap<>();
+        response.put("accounts", accounts);
+        response.put("total", accountService.countAccounts(filter));
+        response.put("limit", filter.getLimit());
+        response.put("offset", filter.getOffset());
+        return response;
+    }
+
+    @GetMapping("/api/v1/admin/accounts/dormant")
+    public List<Account> listDormantAccounts(
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestParam(name = "dormant_days", required = false, defaultValue = "365") int dormantDays) {
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+        return accountService.listDormantAccounts(dormantDays);
+    }
+
     @GetMapping("/api/v1/accounts/{id}")
     public Account getAccount(@PathVariable UUID id) {
         return accountService.getAccount(id);
     }
+
+    // Closing keeps the account and its history; only a zero-balance account can be closed,
+    // by its owner or an admin.
+    @DeleteMapping("/api/v1/accounts/{id}")
+    public ResponseEntity<Void> closeAccount(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId) {
+        if (!"admin".equals(role)) {
+            if (callerId == null) {
+                throw new UnauthorizedException("authenticated user required");
+            }
+            if (!callerId.equals(accountService.getAccount(id).getUserId())) {
+                throw new ForbiddenException("users may only close their own accounts");
+            }
+        }
+        accountService.closeAccount(id);
+        return ResponseEntity.noContent().build();
+    }
+
+    // Transaction-service checks funds here before every transfer, so no cache along
+    // the way may hold on to a balance.
+    @GetMapping(value = "/api/v1/accounts/{id}/balance", params = "!at")
+    public ResponseEntity<AccountBalances> getBalances(@PathVariable UUID id,
+                                                       @RequestParam(required = false) String currency) {
+        return ResponseEntity.ok()
+                .cacheControl(CacheControl.noStore())
+                .body(accountService.getBalances(id, currency));
+    }
+
+    @GetMapping(value = "/api/v1/accounts/{id}/balance", params = "at")
+    public BalanceSnapshot getBalanceAtDate(
+            @PathVariable UUID id,
+            @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate at) {
+        return accountService.getBalanceAtDate(id, at);
+    }
+
+    // The Authorization header is forwarded to transaction-service for the history.
+    @GetMapping(value = "/api/v1/accounts/{id}/statement", params = "format!=csv")
+    public Statement getStatement(@PathVariable UUID id,
+                                  @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate from,
+                                  @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate to,
+                                  @RequestParam(defaultValue = "json") String format,
+                                  HttpServletRequest request) {
+        if (!"json".equals(format)) {
+            throw new ValidationException("format must be json or csv");
+        }
+        return statementGenerator.generate(id, from, to, request.getHeader("Authorization"));
+    }
+
+    @GetMapping(value = "/api/v1/accounts/{id}/statement", params = "format=csv")
+    public void downloadStatement(@PathVariable UUID id,
+                                  @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate from,
+                                  @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate to,
+                                  HttpServletRequest request,
+                                  HttpServletResponse response) throws IOException {
+        Statement statement = statementGenerator.generate(id, from, to, request.getHeader("Authorization"));
+        response.setContentType("text/csv;charset=UTF-8");
+        response.setHeader(HttpHeaders.CONTENT_DISPOSITION, ContentDisposition.attachment()
+                .filename("statement-" + id + "-" + from + "-" + to + ".csv").build().toString());
+        StatementCsv.write(statement, response.getWriter());
+    }
+
+    // Only transaction-service moves money this way. ServiceTokenFilter names the calling
+    // service once its token checks out; a user's token never gets that far, whatever its role.
+    @PatchMapping("/api/v1/accounts/{id}/balance/adjust")
+    public Account adjustBalance(@PathVariable UUID id,
+                                 @RequestAttribute(name = ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE, required = false) String serviceName,
+                                 @RequestBody @ValidatedBody("adjust-balance") AdjustBalanceRequest request) {
+        if (serviceName == null) {
+            throw new UnauthorizedException("service token required");
+        }
+        return accountService.adjustBalance(id, request);
+    }
+
+    // Teller adjustments apply immediately instead of waiting for a transaction event.
+    @PatchMapping("/api/v1/accounts/{id}/balance")
+    public Account updateBalance(@PathVariable UUID id,
+                                 @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                 @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId,
+                                 @RequestBody @ValidatedBody("update-balance") UpdateBalanceRequest request) {
+        if (!"admin".equals(role) && !"teller".equals(role)) {
+            throw new ForbiddenException("admin or teller role required");
+        }
+        return accountService.updateBalance(id, request, callerId);
+    }
+
+    // A service route like balance/adjust; see ServiceTokenFilter.
+    @PatchMapping("/api/v1/accounts/{id}/balances/{currency}")
+    public Account adjustCurrencyBalance(@PathVariable UUID id, @PathVariable String currency,
+                                         @RequestAttribute(name = ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE, required = false) String serviceName,
+                                         @RequestBody @ValidatedBody("adjust-currency-balance") AdjustCurrencyBalanceRequest request) {
+        if (serviceName == null) {
+            throw new UnauthorizedException("service token required");
+        }
+        return accountService.adjustCurrencyBalance(id, currency, request);
+    }
+
+    @PatchMapping("/api/v1/accounts/{id}/currency")
+    public Account updateCurrency(@PathVariable UUID id, @RequestBody @ValidatedBody("update-currency") UpdateCurrencyRequest request) {
+        return accountService.updateAccountCurrency(id, request);
+    }
+
+    @PatchMapping("/api/v1/accounts/{id}/statement-preferences")
+    public Account updateStatementPreferences(@PathVariable UUID id,
+                                              @RequestBody @ValidatedBody("update-statement-preferences") UpdateStatementPreferencesRequest request) {
+        return accountService.updateStatementPreferences(id, request);
+    }
+
+    // Deposit limits are an anti-money-laundering control, so account holders can't change their own.
+    @PatchMapping("/api/v1/accounts/{id}/deposit-limit")
+    public Account updateDepositLimit(@PathVariable UUID id,
+                                      @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                      @RequestBody @ValidatedBody("update-deposit-limit") UpdateDepositLimitRequest request) {
+        if (!"admin".equals(role)) {
+            throw new ForbiddenException("admin role required");
+        }
+        return accountService.updateMaxDailyDeposit(id, request);
+    }
 }
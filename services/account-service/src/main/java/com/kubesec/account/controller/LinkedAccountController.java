@@ -0,0 +1,64 @@
+package com.kubesec.account.controller;
+
+import com.kubesec.account.exception.ForbiddenException;
+import com.kubesec.account.exception.UnauthorizedException;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.model.LinkedAccount;
+import com.kubesec.account.model.dto.CreateLinkedAccountRequest;
+import com.kubesec.account.model.dto.VerifyLinkedAccountRequest;
+import com.kubesec.account.service.LinkedAccountService;
+import com.kubesec.account.validation.ValidatedBody;
+import org.springframework.http.HttpStatus;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.*;
+
+import java.util.List;
+import java.util.UUID;
+
+@RestController
+public class LinkedAccountController {
+
+    private final LinkedAccountService linkedAccountService;
+
+    public LinkedAccountController(LinkedAccountService linkedAccountService) {
+        this.linkedAccountService = linkedAccountService;
+    }
+
+    @PostMapping("/api/v1/users/{id}/linked-accounts")
+    public ResponseEntity<LinkedAccount> linkAccount(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId,
+            @RequestBody @ValidatedBody("create-linked-account") CreateLinkedAccountRequest request) {
+        requireSelf(id, callerId);
+        LinkedAccount linked = linkedAccountService.linkAccount(id, request);
+        return ResponseEntity.status(HttpStatus.CREATED).body(linked);
+    }
+
+    @GetMapping("/api/v1/users/{id}/linked-accounts")
+    public List<LinkedAccount> listLinkedAccounts(
+            @PathVariable UUID id,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId) {
+        requireSelf(id, callerId);
+        return linkedAccountService.listLinkedAccounts(id);
+    }
+
+    @PostMapping("/api/v1/users/{id}/linked-accounts/{linkedAccountId}/verify")
+    public LinkedAccount verify(
+            @PathVariable UUID id,
+            @PathVariable UUID linkedAccountId,
+            @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) UUID callerId,
+            @RequestBody @ValidatedBody("verify-linked-account") VerifyLinkedAccountRequest request) {
+        requireSelf(id, callerId);
+        return linkedAccountService.verify(id, linkedAccountId, request);
+    }
+
+    // callerId is set by AuthFilter from the caller's validated token.
+    private static void requireSelf(UUID id, UUID callerId) {
+        if (callerId == null) {
+            throw new UnauthorizedException("authenticated user required");
+        }
+        if (!callerId.equals(id)) {
+            throw new ForbiddenException("cannot access another user's linked accounts");
+        }
+    }
+}
@@ -0,0 +1,25 @@
+package com.kubesec.account.controller;
+
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.PushBuilder;
+
+import java.util.List;
+
+// HTTP/2 server push for resources clients almost always fetch next. The pushed
+// requests inherit the original's headers, so the tenant and caller ids carry over.
+final class ServerPush {
+
+    private ServerPush() {}
+
+    // newPushBuilder returns null when the connection can't push (HTTP/1.1, or the
+    // client disabled it in its settings), which makes this a no-op.
+    static void push(HttpServletRequest request, List<String> paths) {
+        PushBuilder builder = request.newPushBuilder();
+        if (builder == null) {
+            return;
+        }
+        for (String path : paths) {
+            builder.path(path).push();
+        }
+    }
+}
@@ -0,0 +1,7 @@
+package com.kubesec.account.credit;
+
+// Looks up a person's credit score with the external bureau.
+public interface CreditBureauClient {
+
+    int getScore(String nationalId);
+}
@@ -0,0 +1,36 @@
+package com.kubesec.account.credit;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import com.kubesec.account.config.AppConfig;
+import org.springframework.stereotype.Component;
+import org.springframework.web.client.RestClient;
+
+import java.util.Map;
+
+@Component
+public class HttpCreditBureauClient implements CreditBureauClient {
+
+    private final RestClient restClient;
+
+    public HttpCreditBureauClient(AppConfig config) {
+        this.restClient = RestClient.builder()
+                .baseUrl(config.getCreditBureauUrl())
+                .build();
+    }
+
+    // The national ID goes in the body rather than the URL so it stays out of access logs.
+    @Override
+    public int getScore(String nationalId) {
+        ScoreResponse response = restClient.post()
+                .uri("/v1/scores")
+                .body(Map.of("national_id", nationalId))
+                .retrieve()
+                .body(ScoreResponse.class);
+        if (response == null || response.score() == null) {
+            throw new IllegalStateException("credit bureau returned no score");
+        }
+        return response.score();
+    }
+
+    record ScoreResponse(@JsonProperty("score") Integer score) {}
+}
@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class AccountLimitReachedException extends DomainException {
+
+    public AccountLimitReachedException() {
+        super("account_limit_reached", HttpStatus.UNPROCESSABLE_ENTITY, "account limit reached");
+    }
+}
@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class ConflictException extends DomainException {
+
+    public ConflictException(String message) {
+        super("conflict", HttpStatus.CONFLICT, message);
+    }
+}
@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class CurrencyImmutableException extends DomainException {
+
+    public CurrencyImmutableException(String message) {
+        super("currency_immutable", HttpStatus.CONFLICT, message);
+    }
+}
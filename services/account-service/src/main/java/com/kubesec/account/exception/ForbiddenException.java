@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class ForbiddenException extends DomainException {
+
+    public ForbiddenException(String message) {
+        super("forbidden", HttpStatus.FORBIDDEN, message);
+    }
+}
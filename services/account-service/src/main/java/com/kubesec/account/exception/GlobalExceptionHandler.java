@@ -1,5 +1,9 @@
 package com.kubesec.account.exception;
 
+import com.kubesec.account.filter.RecoveryFilter;
+import io.micrometer.core.instrument.Metrics;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
 import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.ExceptionHandler;
@@ -11,10 +15,18 @@ import java.util.Map;
 @RestControllerAdvice
 public class GlobalExceptionHandler {
 
-    @ExceptionHandler(ResourceNotFoundException.class)
-    public ResponseEntity<Map<String, String>> handleNotFound(ResourceNotFoundException ex) {
-        return ResponseEntity.status(HttpStatus.NOT_FOUND)
-                .body(Map.of("error", ex.getMessage()));
+    private static final Logger log = LoggerFactory.getLogger(GlobalExceptionHandler.class);
+
+    @ExceptionHandler(DomainException.class)
+    public ResponseEntity<Map<String, String>> handleDomain(DomainException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode()));
+    }
+
+    @ExceptionHandler(SchemaViolationException.class)
+    public ResponseEntity<Map<String, Object>> handleSchemaViolation(SchemaViolationException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode(), "violations", ex.getViolations()));
     }
 
     @ExceptionHandler(IllegalArgumentException.class)
@@ -32,6 +44,9 @@ public class GlobalExceptionHandler {
 
     @ExceptionHandler(Exception.class)
     public ResponseEntity<Map<String, String>> handleGeneral(Exception ex) {
+        // Counted with the exceptions RecoveryFilter catches outside the dispatcher.
+        Metrics.counter(RecoveryFilter.PANIC_METRIC).increment();
+        log.atError().setMessage("Unhandled exception").setCause(ex).log();
         return ResponseEntity.status(HttpStatus.INTERNAL_SERVER_ERROR)
                 .body(Map.of("error", "internal server error"));
     }
@@ -0,0 +1,11 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+// Another request holds the account lock; callers may retry.
+public class LockContentionException extends DomainException {
+
+    public LockContentionException(String message) {
+        super("lock_contention", HttpStatus.SERVICE_UNAVAILABLE, message);
+    }
+}
@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class NonZeroBalanceException extends DomainException {
+
+    public NonZeroBalanceException() {
+        super("non_zero_balance", HttpStatus.UNPROCESSABLE_ENTITY, "account has non-zero balance");
+    }
+}
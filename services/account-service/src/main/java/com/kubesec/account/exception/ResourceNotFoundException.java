@@ -1,8 +1,10 @@
 package com.kubesec.account.exception;
 
-public class ResourceNotFoundException extends RuntimeException {
+import org.springframework.http.HttpStatus;
+
+public class ResourceNotFoundException extends DomainException {
 
     public ResourceNotFoundException(String message) {
-        super(message);
+        super("not_found", HttpStatus.NOT_FOUND, message);
     }
 }
@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class UpstreamException extends DomainException {
+
+    public UpstreamException(String message) {
+        super("upstream_error", HttpStatus.BAD_GATEWAY, message);
+    }
+}
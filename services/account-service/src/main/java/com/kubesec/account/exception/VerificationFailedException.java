@@ -0,0 +1,10 @@
+package com.kubesec.account.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class VerificationFailedException extends DomainException {
+
+    public VerificationFailedException(String message) {
+        super("verification_failed", HttpStatus.UNPROCESSABLE_ENTITY, message);
+    }
+}
@@ -0,0 +1,61 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.client.AuthServiceClient;
+import com.kubesec.account.tenant.TenantContext;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+
+// Resolves the caller of a request from its bearer token, as validated by auth-service,
+// into the userId and role attributes handlers check. A request without a token, or with
+// one auth-service rejects, continues anonymously: most account routes don't identify
+// the caller, and those that do answer 401 or 403 themselves. Service tokens on the
+// balance routes are ServiceTokenFilter's and are not sent to auth-service.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 3)
+public class AuthFilter extends OncePerRequestFilter {
+
+    private static final Logger log = LoggerFactory.getLogger(AuthFilter.class);
+    public static final String USER_ID_ATTRIBUTE = "userId";
+    public static final String ROLE_ATTRIBUTE = "role";
+
+    private final AuthServiceClient authServiceClient;
+
+    public AuthFilter(AuthServiceClient authServiceClient) {
+        this.authServiceClient = authServiceClient;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        String authHeader = request.getHeader("Authorization");
+        return authHeader == null || !authHeader.startsWith("Bearer ")
+                || request.getHeader(TenantContext.HEADER) == null
+                || ServiceTokenFilter.isServiceRoute(request);
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String token = request.getHeader("Authorization").substring(7);
+        try {
+            AuthServiceClient.ValidateResponse result =
+                    authServiceClient.validateToken(token, request.getHeader(TenantContext.HEADER));
+            if (result != null && result.valid()) {
+                request.setAttribute(USER_ID_ATTRIBUTE, result.user_id());
+                request.setAttribute(ROLE_ATTRIBUTE, result.role());
+            }
+        } catch (Exception e) {
+            log.warn("token validation failed on {}: {}", request.getRequestURI(), e.getMessage());
+        }
+        chain.doFilter(request, response);
+    }
+}
@@ -0,0 +1,69 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import javax.naming.InvalidNameException;
+import javax.naming.ldap.LdapName;
+import javax.naming.ldap.Rdn;
+import java.io.IOException;
+import java.security.cert.X509Certificate;
+
+// The TLS handshake has already verified any presented certificate against the
+// MTLS_CA_CERT_FILE pool; this filter makes one mandatory on service-to-service routes.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
+public class ClientCertFilter extends OncePerRequestFilter {
+
+    private static final Logger log = LoggerFactory.getLogger(ClientCertFilter.class);
+    private static final String CERTIFICATE_ATTRIBUTE = "jakarta.servlet.request.X509Certificate";
+
+    private final AppConfig config;
+
+    public ClientCertFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        return !config.isMtlsEnabled() || !ServiceTokenFilter.isServiceRoute(request);
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        X509Certificate[] certs = (X509Certificate[]) request.getAttribute(CERTIFICATE_ATTRIBUTE);
+        if (certs == null || certs.length == 0) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_FORBIDDEN);
+            response.getWriter().write("{\"error\":\"client certificate required\"}");
+            return;
+        }
+
+        log.info("mTLS client {} calling {}", commonName(certs[0]), request.getRequestURI());
+        chain.doFilter(request, response);
+    }
+
+    private static String commonName(X509Certificate cert) {
+        String subject = cert.getSubjectX500Principal().getName();
+        try {
+            for (Rdn rdn : new LdapName(subject).getRdns()) {
+                if ("CN".equalsIgnoreCase(rdn.getType())) {
+                    return rdn.getValue().toString();
+                }
+            }
+        } catch (InvalidNameException e) {
+            // fall through to the full subject
+        }
+        return subject;
+    }
+}
@@ -0,0 +1,54 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+
+// Guards back-office routes with a shared secret in X-Internal-Token. These are
+// called by internal tooling that holds no user or service JWT.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 2)
+public class InternalTokenFilter extends OncePerRequestFilter {
+
+    public static final String HEADER = "X-Internal-Token";
+
+    private final AppConfig config;
+
+    public InternalTokenFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        return !request.getRequestURI().matches("/api/v1/users/[^/]+/kyc");
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String expected = config.getInternalToken();
+        String provided = request.getHeader(HEADER);
+
+        // An unset token closes these routes rather than leaving them open
+        if (expected == null || expected.isEmpty() || provided == null
+                || !MessageDigest.isEqual(expected.getBytes(StandardCharsets.UTF_8),
+                                          provided.getBytes(StandardCharsets.UTF_8))) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
+            response.getWriter().write("{\"error\":\"invalid internal token\"}");
+            return;
+        }
+
+        chain.doFilter(request, response);
+    }
+}
@@ -1,5 +1,9 @@
 package com.kubesec.account.filter;
 
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.node.ObjectNode;
 import jakarta.servlet.FilterChain;
 import jakarta.servlet.ServletException;
 import jakarta.servlet.http.HttpServletRequest;
@@ -8,24 +12,83 @@ import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
 import org.springframework.core.Ordered;
 import org.springframework.core.annotation.Order;
+import org.springframework.http.MediaType;
 import org.springframework.stereotype.Component;
 import org.springframework.web.filter.OncePerRequestFilter;
 
 import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.util.List;
+import java.util.Set;
 
 @Component
-@Order(Ordered.HIGHEST_PRECEDENCE)
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
 public class LoggingFilter extends OncePerRequestFilter {
 
     private static final Logger log = LoggerFactory.getLogger(LoggingFilter.class);
+    private static final ObjectMapper MAPPER = new ObjectMapper();
+
+    static final List<String> SENSITIVE_KEYS = List.of("password", "card_number", "cvv", "pin");
 
     @Override
     protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
                                     FilterChain chain) throws ServletException, IOException {
+        // The body is only buffered when it will be logged; the cached copy is what
+        // the rest of the chain reads from.
+        if (log.isDebugEnabled() && isJson(request)) {
+            CachedBodyHttpServletRequest cached = new CachedBodyHttpServletRequest(request);
+            if (cached.getBody().length > 0) {
+                log.debug("{} {} body={}", request.getMethod(), request.getRequestURI(),
+                        new String(sanitizeJson(cached.getBody(), SENSITIVE_KEYS), StandardCharsets.UTF_8));
+            }
+            request = cached;
+        }
+
         long start = System.currentTimeMillis();
         chain.doFilter(request, response);
         long duration = System.currentTimeMillis() - start;
-        log.info("{} {} {} {}ms", request.getMethod(), request.getRequestURI(),
-                response.getStatus(), duration);
+        // One line per request; request_id comes along from the MDC
+        log.atInfo().setMessage("request")
+                .addKeyValue("method", request.getMethod())
+                .addKeyValue("path", request.getRequestURI())
+                .addKeyValue("status", response.getStatus())
+                .addKeyValue("duration_ms", duration)
+                .log();
+    }
+
+    // Removes the listed keys at any depth and re-encodes the document. A body that
+    // isn't valid JSON is dropped entirely rather than logged unredacted.
+    public static byte[] sanitizeJson(byte[] body, List<String> sensitiveKeys) {
+        try {
+            JsonNode root = MAPPER.readTree(body);
+            if (root == null) {
+                return new byte[0];
+            }
+            strip(root, Set.copyOf(sensitiveKeys));
+            return MAPPER.writeValueAsBytes(root);
+        } catch (IOException e) {
+            return new byte[0];
+        }
+    }
+
+    private static void strip(JsonNode node, Set<String> keys) {
+        if (node instanceof ObjectNode object) {
+            object.remove(keys);
+        }
+        for (JsonNode child : node) {
+            strip(child, keys);
+        }
+    }
+
+    private static boolean isJson(HttpServletRequest request) {
+        String contentType = request.getContentType();
+        if (contentType == null) {
+            return false;
+        }
+        try {
+            return MediaType.APPLICATION_JSON.isCompatibleWith(MediaType.parseMediaType(contentType));
+        } catch (IllegalArgumentException e) {
+            return false;
+        }
     }
 }
@@ -0,0 +1,116 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.config.AppConfig;
+import io.jsonwebtoken.Claims;
+import io.jsonwebtoken.JwtException;
+import io.jsonwebtoken.Jwts;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.GeneralSecurityException;
+import java.security.KeyFactory;
+import java.security.PublicKey;
+import java.security.spec.X509EncodedKeySpec;
+import java.util.Base64;
+
+// Requires a service token from auth-service on the routes only other services call.
+// The token is signed with auth-service's private key and names this service as its
+// audience; the user's own token is never accepted here. Without the public key no
+// token can be verified, so those routes reject every call.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 2)
+public class ServiceTokenFilter extends OncePerRequestFilter {
+
+    private static final Logger log = LoggerFactory.getLogger(ServiceTokenFilter.class);
+    static final String AUDIENCE = "account-service";
+    // The calling service, taken from the token's issuer.
+    public static final String SERVICE_NAME_ATTRIBUTE = "serviceName";
+
+    private final PublicKey key;
+
+    public ServiceTokenFilter(AppConfig config) {
+        String keyFile = config.getServiceTokenPublicKeyFile();
+        this.key = keyFile.isBlank() ? null : loadPublicKey(Path.of(keyFile));
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        // The same routes ClientCertFilter reserves for transaction-service
+        return !isServiceRoute(request);
+    }
+
+    // Balance reads and adjustments, foreign-currency ones included, are only ever called
+    // by other services. A teller's PATCH of the balance itself carries the teller's own token.
+    static boolean isServiceRoute(HttpServletRequest request) {
+        String path = request.getRequestURI();
+        return path.matches("(/api/v1)?/accounts/[^/]+/balance/adjust")
+                || ("PATCH".equals(request.getMethod()) && path.matches("(/api/v1)?/accounts/[^/]+/balances/[^/]+"))
+                || ("GET".equals(request.getMethod()) && path.matches("(/api/v1)?/accounts/[^/]+/balance"));
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        if (key == null) {
+            log.warn("rejected call to {}: SERVICE_TOKEN_PUBLIC_KEY_FILE is not set", request.getRequestURI());
+            reject(response, "service token required");
+            return;
+        }
+        String authHeader = request.getHeader("Authorization");
+        if (authHeader == null || !authHeader.startsWith("Bearer ")) {
+            reject(response, "service token required");
+            return;
+        }
+
+        Claims claims;
+        try {
+            claims = Jwts.parser()
+                    .verifyWith(key)
+                    .requireAudience(AUDIENCE)
+                    .build()
+                    .parseSignedClaims(authHeader.substring(7))
+                    .getPayload();
+        } catch (JwtException | IllegalArgumentException e) {
+            log.warn("rejected service token on {}: {}", request.getRequestURI(), e.getMessage());
+            reject(response, "invalid service token");
+            return;
+        }
+
+        request.setAttribute(SERVICE_NAME_ATTRIBUTE, claims.getIssuer());
+        chain.doFilter(request, response);
+    }
+
+    private static void reject(HttpServletResponse response, String message) throws IOException {
+        response.setContentType("application/json");
+        response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
+        response.getWriter().write("{\"error\":\"" + message + "\"}");
+    }
+
+    // Expects a PEM "PUBLIC KEY" block, as written by `openssl pkey -pubout`.
+    static PublicKey loadPublicKey(Path file) {
+        try {
+            String pem = Files.readString(file)
+                    .replace("-----BEGIN PUBLIC KEY-----", "")
+                    .replace("-----END PUBLIC KEY-----", "")
+                    .replaceAll("\\s", "");
+            return KeyFactory.getInstance("RSA")
+                    .generatePublic(new X509EncodedKeySpec(Base64.getDecoder().decode(pem)));
+        } catch (IOException e) {
+            throw new UncheckedIOException("read service token key " + file, e);
+        } catch (GeneralSecurityException | IllegalArgumentException e) {
+            throw new IllegalStateException("invalid service token key " + file, e);
+        }
+    }
+}
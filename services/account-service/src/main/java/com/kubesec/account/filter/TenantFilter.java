@@ -0,0 +1,62 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.repository.TenantRepository;
+import com.kubesec.account.tenant.TenantContext;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.util.UUID;
+
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 2)
+public class TenantFilter extends OncePerRequestFilter {
+
+    private final TenantRepository tenantRepository;
+
+    public TenantFilter(TenantRepository tenantRepository) {
+        this.tenantRepository = tenantRepository;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        String path = request.getRequestURI();
+        // Tenant listing is a platform-level admin view, not scoped to one tenant
+        return !path.startsWith("/api/") || path.equals("/api/v1/admin/tenants");
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String header = request.getHeader(TenantContext.HEADER);
+        UUID tenantId;
+        try {
+            tenantId = UUID.fromString(header);
+        } catch (IllegalArgumentException | NullPointerException e) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_BAD_REQUEST);
+            response.getWriter().write("{\"error\":\"missing or invalid X-Tenant-ID header\"}");
+            return;
+        }
+
+        if (!tenantRepository.isActive(tenantId)) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_FORBIDDEN);
+            response.getWriter().write("{\"error\":\"unknown or inactive tenant\"}");
+            return;
+        }
+
+        TenantContext.set(tenantId);
+        try {
+            chain.doFilter(request, response);
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,74 @@
+package com.kubesec.account.health;
+
+import io.micrometer.core.instrument.Gauge;
+import io.micrometer.core.instrument.MeterRegistry;
+import org.springframework.core.io.Resource;
+import org.springframework.core.io.support.PathMatchingResourcePatternResolver;
+import org.springframework.dao.DataAccessException;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+
+// Compares the highest migration Flyway has applied against the highest one
+// bundled in the jar, so a rollout that skipped migrations is never marked ready.
+@Component
+public class MigrationStatusChecker {
+
+    private static final String MIGRATION_LOCATION = "classpath:db/migration/V*__*.sql";
+    private static final Pattern VERSION_PATTERN = Pattern.compile("^V(\\d+)__.*\\.sql$");
+
+    private final JdbcTemplate jdbc;
+    private final int latestVersion;
+
+    public MigrationStatusChecker(JdbcTemplate jdbc, MeterRegistry meterRegistry) {
+        this.jdbc = jdbc;
+        this.latestVersion = scanLatestVersion();
+
+        Gauge.builder("db.migrations.current.version", this, MigrationStatusChecker::currentVersion)
+                .description("Highest schema migration applied to the database")
+                .register(meterRegistry);
+        Gauge.builder("db.migrations.latest.version", this, MigrationStatusChecker::latestVersion)
+                .description("Highest schema migration bundled with this build")
+                .register(meterRegistry);
+    }
+
+    public int currentVersion() {
+        try {
+            Integer version = jdbc.queryForObject(
+                    "SELECT MAX(CAST(version AS INTEGER)) FROM flyway_schema_history WHERE success AND version IS NOT NULL",
+                    Integer.class
+            );
+            return version != null ? version : 0;
+        } catch (DataAccessException e) {
+            // Missing history table means nothing has been applied yet.
+            return 0;
+        }
+    }
+
+    public int latestVersion() {
+        return latestVersion;
+    }
+
+    private static int scanLatestVersion() {
+        try {
+            int latest = 0;
+            for (Resource resource : new PathMatchingResourcePatternResolver().getResources(MIGRATION_LOCATION)) {
+                String filename = resource.getFilename();
+                if (filename == null) {
+                    continue;
+                }
+                Matcher m = VERSION_PATTERN.matcher(filename);
+                if (m.matches()) {
+                    latest = Math.max(latest, Integer.parseInt(m.group(1)));
+                }
+            }
+            return latest;
+        } catch (IOException e) {
+            throw new UncheckedIOException("failed to scan migrations", e);
+        }
+    }
+}
@@ -3,12 +3,17 @@ package com.kubesec.account.model;
 import com.fasterxml.jackson.annotation.JsonProperty;
 import java.math.BigDecimal;
 import java.time.OffsetDateTime;
+import java.util.Map;
+import java.util.TreeMap;
 import java.util.UUID;
 
 public class Account {
 
     private UUID id;
 
+    @JsonProperty("tenant_id")
+    private UUID tenantId;
+
     @JsonProperty("user_id")
     private UUID userId;
 
@@ -19,6 +24,23 @@ public class Account {
     private String currency;
     private String status;
 
+    @JsonProperty("last_activity_at")
+    private OffsetDateTime lastActivityAt;
+
+    @JsonProperty("monthly_statement_enabled")
+    private boolean monthlyStatementEnabled;
+
+    @JsonProperty("max_daily_deposit")
+    private BigDecimal maxDailyDeposit;
+
+    @JsonProperty("overdraft_limit")
+    private BigDecimal overdraftLimit = BigDecimal.ZERO;
+
+    // Balances held in currencies other than currency, keyed by ISO code. Only
+    // loaded when a single account is fetched.
+    @JsonProperty("currency_balances")
+    private Map<String, BigDecimal> currencyBalances = new TreeMap<>();
+
     @JsonProperty("created_at")
     private OffsetDateTime createdAt;
 
@@ -57,6 +79,24 @@ public class Account {
     public String getStatus() { return status; }
     public void setStatus(String status) { this.status = status; }
 
+    public OffsetDateTime getLastActivityAt() { return lastActivityAt; }
+    public void setLastActivityAt(OffsetDateTime lastActivityAt) { this.lastActivityAt = lastActivityAt; }
+
+    public boolean isMonthlyStatementEnabled() { return monthlyStatementEnabled; }
+    public void setMonthlyStatementEnabled(boolean monthlyStatementEnabled) { this.monthlyStatementEnabled = monthlyStatementEnabled; }
+
+    public BigDecimal getMaxDailyDeposit() { return maxDailyDeposit; }
+    public void setMaxDailyDeposit(BigDecimal maxDailyDeposit) { this.maxDailyDeposit = maxDailyDeposit; }
+
+    public BigDecimal getOverdraftLimit() { return overdraftLimit; }
+    public void setOverdraftLimit(BigDecimal overdraftLimit) { this.overdraftLimit = overdraftLimit; }
+
+    public Map<String, BigDecimal> getCurrencyBalances() { return currencyBalances; }
+    public void setCurrencyBalances(Map<String, BigDecimal> currencyBalances) { this.currencyBalances = currencyBalances; }
+
+    public UUID getTenantId() { return tenantId; }
+    public void setTenantId(UUID tenantId) { this.tenantId = tenantId; }
+
     public OffsetDateTime getCreatedAt() { return createdAt; }
     public void setCreatedAt(OffsetDateTime createdAt) { this.createdAt = createdAt; }
 
@@ -0,0 +1,13 @@
+package com.kubesec.account.model;
+
+import java.math.BigDecimal;
+import java.util.Map;
+
+// The balance columns of an account without the rest of the row. currencyBalances
+// holds the foreign-currency sub-balances only.
+public record AccountBalance(
+        BigDecimal balance,
+        String currency,
+        BigDecimal overdraftLimit,
+        Map<String, BigDecimal> currencyBalances
+) {}
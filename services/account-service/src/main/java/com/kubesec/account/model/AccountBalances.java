@@ -0,0 +1,17 @@
+package com.kubesec.account.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.Map;
+import java.util.UUID;
+
+// Current balance in every currency the account holds, including its own. The
+// balance and available balance are for the requested currency; only the
+// account's own currency can be overdrawn.
+public record AccountBalances(
+        @JsonProperty("account_id") UUID accountId,
+        Map<String, BigDecimal> balances,
+        String currency,
+        BigDecimal balance,
+        @JsonProperty("available_balance") BigDecimal availableBalance
+) {}
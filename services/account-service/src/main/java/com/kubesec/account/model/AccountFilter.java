@@ -0,0 +1,27 @@
+package com.kubesec.account.model;
+
+import java.util.UUID;
+
+public class AccountFilter {
+
+    private String status;
+    private String accountType;
+    private UUID userId;
+    private int limit = 20;
+    private int offset = 0;
+
+    public String getStatus() { return status; }
+    public void setStatus(String status) { this.status = status; }
+
+    public String getAccountType() { return accountType; }
+    public void setAccountType(String accountType) { this.accountType = accountType; }
+
+    public UUID getUserId() { return userId; }
+    public void setUserId(UUID userId) { this.userId = userId; }
+
+    public int getLimit() { return limit; }
+    public void setLimit(int limit) { this.limit = limit; }
+
+    public int getOffset() { return offset; }
+    public void setOffset(int offset) { this.offset = offset; }
+}
@@ -0,0 +1,12 @@
+package com.kubesec.account.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.util.UUID;
+
+public record BalanceSnapshot(
+        @JsonProperty("account_id") UUID accountId,
+        BigDecimal balance,
+        @JsonProperty("snapshot_date") LocalDate snapshotDate
+) {}
@@ -0,0 +1,30 @@
+package com.kubesec.account.model;
+
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.util.HexFormat;
+import java.util.UUID;
+
+// Replacement values written over a user's personal data on GDPR erasure.
+public final class Erasure {
+
+    public static final String ERASED_NAME = "DELETED";
+
+    private Erasure() {}
+
+    // A fresh random address keeps the users.email unique constraint satisfied.
+    public static String anonymizedEmail() {
+        return "erased-" + UUID.randomUUID() + "@deleted.invalid";
+    }
+
+    public static String hashUserId(UUID userId) {
+        try {
+            byte[] digest = MessageDigest.getInstance("SHA-256")
+                    .digest(userId.toString().getBytes(StandardCharsets.UTF_8));
+            return HexFormat.of().formatHex(digest);
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException("SHA-256 not available", e);
+        }
+    }
+}
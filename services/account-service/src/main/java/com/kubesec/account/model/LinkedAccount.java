@@ -0,0 +1,24 @@
+package com.kubesec.account.model;
+
+import com.fasterxml.jackson.annotation.JsonIgnore;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+// The micro-deposit amounts are the verification secret and never leave the service.
+public record LinkedAccount(
+        UUID id,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("routing_number") String routingNumber,
+        @JsonIgnore String accountNumberHash,
+        @JsonProperty("account_number_last4") String accountNumberLast4,
+        @JsonProperty("bank_name") String bankName,
+        String status,
+        @JsonIgnore BigDecimal microDeposit1,
+        @JsonIgnore BigDecimal microDeposit2,
+        @JsonIgnore int verificationAttempts,
+        @JsonProperty("created_at") OffsetDateTime createdAt,
+        @JsonProperty("updated_at") OffsetDateTime updatedAt
+) {}
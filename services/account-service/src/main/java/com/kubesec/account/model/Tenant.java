@@ -0,0 +1,12 @@
+package com.kubesec.account.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record Tenant(
+        UUID id,
+        String name,
+        boolean active,
+        @JsonProperty("created_at") OffsetDateTime createdAt
+) {}
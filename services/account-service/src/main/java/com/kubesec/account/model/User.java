@@ -1,12 +1,18 @@
 package com.kubesec.account.model;
 
+import com.fasterxml.jackson.annotation.JsonIgnore;
+import com.fasterxml.jackson.annotation.JsonInclude;
 import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
 import java.time.OffsetDateTime;
 import java.util.UUID;
 
 public class User {
 
     private UUID id;
+    @JsonProperty("tenant_id")
+    private UUID tenantId;
+
     private String email;
 
     @JsonProperty("full_name")
@@ -15,12 +21,43 @@ public class User {
     @JsonProperty("kyc_status")
     private String kycStatus;
 
+    private String nationality;
+
+    @JsonProperty("country_of_residence")
+    private String countryOfResidence;
+
+    // Only sent to the credit bureau; never returned to clients.
+    @JsonIgnore
+    private String nationalId;
+
+    @JsonProperty("credit_score")
+    private Integer creditScore;
+
+    @JsonProperty("credit_score_updated_at")
+    private OffsetDateTime creditScoreUpdatedAt;
+
+    @JsonProperty("preferred_currency")
+    private String preferredCurrency = "USD";
+
+    @JsonProperty("max_accounts")
+    private int maxAccounts = 5;
+
+    @JsonProperty("total_transferred")
+    private BigDecimal totalTransferred = BigDecimal.ZERO;
+
+    @JsonProperty("transaction_count")
+    private int transactionCount;
+
     @JsonProperty("created_at")
     private OffsetDateTime createdAt;
 
     @JsonProperty("updated_at")
     private OffsetDateTime updatedAt;
 
+    @JsonProperty("deleted_at")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private OffsetDateTime deletedAt;
+
     public User() {}
 
     public User(UUID id, String email, String fullName, String kycStatus,
@@ -45,9 +82,42 @@ public class User {
     public String getKycStatus() { return kycStatus; }
     public void setKycStatus(String kycStatus) { this.kycStatus = kycStatus; }
 
+    public String getNationality() { return nationality; }
+    public void setNationality(String nationality) { this.nationality = nationality; }
+
+    public String getCountryOfResidence() { return countryOfResidence; }
+    public void setCountryOfResidence(String countryOfResidence) { this.countryOfResidence = countryOfResidence; }
+
+    public String getNationalId() { return nationalId; }
+    public void setNationalId(String nationalId) { this.nationalId = nationalId; }
+
+    public Integer getCreditScore() { return creditScore; }
+    public void setCreditScore(Integer creditScore) { this.creditScore = creditScore; }
+
+    public OffsetDateTime getCreditScoreUpdatedAt() { return creditScoreUpdatedAt; }
+    public void setCreditScoreUpdatedAt(OffsetDateTime creditScoreUpdatedAt) { this.creditScoreUpdatedAt = creditScoreUpdatedAt; }
+
+    public String getPreferredCurrency() { return preferredCurrency; }
+    public void setPreferredCurrency(String preferredCurrency) { this.preferredCurrency = preferredCurrency; }
+
+    public int getMaxAccounts() { return maxAccounts; }
+    public void setMaxAccounts(int maxAccounts) { this.maxAccounts = maxAccounts; }
+
+    public BigDecimal getTotalTransferred() { return totalTransferred; }
+    public void setTotalTransferred(BigDecimal totalTransferred) { this.totalTransferred = totalTransferred; }
+
+    public int getTransactionCount() { return transactionCount; }
+    public void setTransactionCount(int transactionCount) { this.transactionCount = transactionCount; }
+
+    public UUID getTenantId() { return tenantId; }
+    public void setTenantId(UUID tenantId) { this.tenantId = tenantId; }
+
     public OffsetDateTime getCreatedAt() { return createdAt; }
     public void setCreatedAt(OffsetDateTime createdAt) { this.createdAt = createdAt; }
 
     public OffsetDateTime getUpdatedAt() { return updatedAt; }
     public void setUpdatedAt(OffsetDateTime updatedAt) { this.updatedAt = updatedAt; }
+
+    public OffsetDateTime getDeletedAt() { return deletedAt; }
+    public void setDeletedAt(OffsetDateTime deletedAt) { this.deletedAt = deletedAt; }
 }
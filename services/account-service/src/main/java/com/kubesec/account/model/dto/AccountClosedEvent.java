@@ -0,0 +1,12 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record AccountClosedEvent(
+        @JsonProperty("account_id") UUID accountId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("closed_at") OffsetDateTime closedAt
+) {}
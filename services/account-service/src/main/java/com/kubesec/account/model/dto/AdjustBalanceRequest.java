@@ -0,0 +1,11 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.UUID;
+
+public record AdjustBalanceRequest(
+        BigDecimal amount,
+        @JsonProperty("transaction_id") UUID transactionId,
+        String reason
+) {}
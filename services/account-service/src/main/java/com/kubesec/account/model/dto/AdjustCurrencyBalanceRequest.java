@@ -0,0 +1,5 @@
+package com.kubesec.account.model.dto;
+
+import java.math.BigDecimal;
+
+public record AdjustCurrencyBalanceRequest(BigDecimal amount) {}
@@ -0,0 +1,17 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record BalanceAdjustedEvent(
+        @JsonProperty("account_id") UUID accountId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        BigDecimal delta,
+        BigDecimal balance,
+        String reason,
+        String reference,
+        @JsonProperty("adjusted_by") UUID adjustedBy,
+        @JsonProperty("adjusted_at") OffsetDateTime adjustedAt
+) {}
@@ -0,0 +1,17 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonIgnoreProperties;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.UUID;
+
+// Request payload on accounts.balance.deduct. The amount is positive and is
+// subtracted from the account balance.
+@JsonIgnoreProperties(ignoreUnknown = true)
+public record BalanceDeductRequest(
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("account_id") UUID accountId,
+        BigDecimal amount,
+        @JsonProperty("transaction_id") UUID transactionId,
+        String reason
+) {}
@@ -0,0 +1,9 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record CreateLinkedAccountRequest(
+        @JsonProperty("routing_number") String routingNumber,
+        @JsonProperty("account_number") String accountNumber,
+        @JsonProperty("bank_name") String bankName
+) {}
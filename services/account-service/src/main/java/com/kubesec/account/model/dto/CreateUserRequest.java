@@ -4,5 +4,9 @@ import com.fasterxml.jackson.annotation.JsonProperty;
 
 public record CreateUserRequest(
         String email,
-        @JsonProperty("full_name") String fullName
+        @JsonProperty("full_name") String fullName,
+        String nationality,
+        @JsonProperty("country_of_residence") String countryOfResidence,
+        @JsonProperty("preferred_currency") String preferredCurrency,
+        @JsonProperty("national_id") String nationalId
 ) {}
@@ -0,0 +1,12 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record CreditScoreRequestedEvent(
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("national_id") String nationalId,
+        @JsonProperty("requested_at") OffsetDateTime requestedAt
+) {}
@@ -0,0 +1,13 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record KycUpdatedEvent(
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("old_status") String oldStatus,
+        @JsonProperty("new_status") String newStatus,
+        @JsonProperty("updated_at") OffsetDateTime updatedAt
+) {}
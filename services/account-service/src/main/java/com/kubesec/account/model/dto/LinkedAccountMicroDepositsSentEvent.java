@@ -0,0 +1,12 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.UUID;
+
+public record LinkedAccountMicroDepositsSentEvent(
+        @JsonProperty("linked_account_id") UUID linkedAccountId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("micro_deposit_1") BigDecimal microDeposit1,
+        @JsonProperty("micro_deposit_2") BigDecimal microDeposit2
+) {}
@@ -0,0 +1,16 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+// The full account number travels only in this event, since the worker needs it to
+// send the micro-deposits and it is not stored.
+public record LinkedAccountVerificationRequestedEvent(
+        @JsonProperty("linked_account_id") UUID linkedAccountId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("routing_number") String routingNumber,
+        @JsonProperty("account_number") String accountNumber,
+        @JsonProperty("requested_at") OffsetDateTime requestedAt
+) {}
@@ -0,0 +1,16 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+// period_end is exclusive: the statement covers [period_start, period_end).
+public record StatementRequestedEvent(
+        @JsonProperty("account_id") UUID accountId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("user_id") UUID userId,
+        @JsonProperty("period_start") LocalDate periodStart,
+        @JsonProperty("period_end") LocalDate periodEnd,
+        @JsonProperty("requested_at") OffsetDateTime requestedAt
+) {}
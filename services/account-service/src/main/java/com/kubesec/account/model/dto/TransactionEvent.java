@@ -0,0 +1,25 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonIgnoreProperties;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+@JsonIgnoreProperties(ignoreUnknown = true)
+public record TransactionEvent(
+        @JsonProperty("transaction_id") UUID transactionId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("from_account_id") UUID fromAccountId,
+        @JsonProperty("to_account_id") UUID toAccountId,
+        BigDecimal amount,
+        String currency,
+        String type,
+        String status,
+        @JsonProperty("converted_amount") BigDecimal convertedAmount,
+        @JsonProperty("fee_amount") BigDecimal feeAmount,
+        OffsetDateTime timestamp,
+        @JsonProperty("trace_id") String traceId,
+        @JsonProperty("request_id") String requestId,
+        @JsonProperty("correlation_id") String correlationId
+) {}
@@ -0,0 +1,9 @@
+package com.kubesec.account.model.dto;
+
+import java.math.BigDecimal;
+
+public record UpdateBalanceRequest(
+        BigDecimal delta,
+        String reason,
+        String reference
+) {}
@@ -0,0 +1,3 @@
+package com.kubesec.account.model.dto;
+
+public record UpdateCurrencyRequest(String currency) {}
@@ -0,0 +1,10 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+import java.math.BigDecimal;
+
+// A null limit removes the cap.
+public record UpdateDepositLimitRequest(
+        @JsonProperty("max_daily_deposit") BigDecimal maxDailyDeposit
+) {}
@@ -0,0 +1,5 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record UpdateKycStatusRequest(@JsonProperty("kyc_status") String kycStatus) {}
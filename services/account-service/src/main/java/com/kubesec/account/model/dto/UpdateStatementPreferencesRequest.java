@@ -0,0 +1,7 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record UpdateStatementPreferencesRequest(
+        @JsonProperty("monthly_statement_enabled") Boolean monthlyStatementEnabled
+) {}
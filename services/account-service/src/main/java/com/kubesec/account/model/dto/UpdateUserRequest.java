@@ -0,0 +1,12 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+// Partial update: a field left out of the body, and so null here, is not changed.
+public record UpdateUserRequest(
+        @JsonProperty("full_name") String fullName
+) {
+    public boolean isEmpty() {
+        return fullName == null;
+    }
+}
@@ -0,0 +1,9 @@
+package com.kubesec.account.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+
+public record VerifyLinkedAccountRequest(
+        @JsonProperty("micro_deposit_1") BigDecimal microDeposit1,
+        @JsonProperty("micro_deposit_2") BigDecimal microDeposit2
+) {}
@@ -1,7 +1,15 @@
 package com.kubesec.account.repository;
 
 import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountBalance;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.model.LinkedAccount;
 import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.UpdateUserRequest;
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.time.LocalDate;
 import java.util.List;
 import java.util.Optional;
 import java.util.UUID;
@@ -12,9 +20,73 @@ public interface AccountRepository {
 
     Optional<User> getUser(UUID id);
 
+    List<User> getUsersByCountry(String country);
+
+    Optional<User> updateUser(UUID userId, UpdateUserRequest request);
+
+    void updatePreferredCurrency(UUID userId, String currency);
+
+    void updateKycStatus(UUID userId, String kycStatus);
+
+    void eraseUser(UUID userId, String requestedBy);
+
+    void updateCreditScore(UUID userId, int score);
+
+    void incrementUserTransactionStats(UUID userId, BigDecimal amount);
+
+    boolean markEventProcessed(UUID transactionId);
+
     void createAccount(Account account);
 
     Optional<Account> getAccount(UUID id);
 
+    // Reads only the balance columns, waiting for any in-flight balance update to commit.
+    Optional<AccountBalance> getAccountBalance(UUID id);
+
     List<Account> listAccountsByUser(UUID userId);
+
+    // Accounts the user holds that aren't closed.
+    int countAccountsByUser(UUID userId);
+
+    List<Account> listAccounts(AccountFilter filter);
+
+    int countAccounts(AccountFilter filter);
+
+    void lockAccount(UUID accountId, Duration timeout);
+
+    void adjustBalance(UUID accountId, BigDecimal delta);
+    // Applies delta unless the result would drop below -overdraft_limit; false if it would.
+    boolean adjustBalanceWithinOverdraft(UUID accountId, BigDecimal delta);
+
+    // Adds delta to the account's balance in currency, creating the sub-balance if needed.
+    void adjustCurrencyBalance(UUID accountId, String currency, BigDecimal delta);
+
+    void updateAccountCurrency(UUID accountId, String currency);
+
+    void updateMonthlyStatementEnabled(UUID accountId, boolean enabled);
+
+    void updateMaxDailyDeposit(UUID accountId, BigDecimal limit);
+
+    // Marks the account closed; throws NonZeroBalanceException if its balance isn't zero.
+    void closeAccount(UUID accountId);
+
+    List<Account> listAccountsWithStatementsEnabled();
+
+    List<Account> listDormantAccounts(Duration dormantFor);
+
+    int freezeDormantAccounts(Duration dormantFor);
+
+    int snapshotActiveBalances(LocalDate snapshotDate);
+
+    Optional<BalanceSnapshot> getBalanceSnapshotAtOrBefore(UUID accountId, LocalDate date);
+
+    void createLinkedAccount(LinkedAccount linkedAccount);
+
+    Optional<LinkedAccount> getLinkedAccount(UUID id);
+
+    List<LinkedAccount> listLinkedAccounts(UUID userId);
+
+    void setLinkedAccountMicroDeposits(UUID id, BigDecimal amount1, BigDecimal amount2);
+
+    void updateLinkedAccountVerification(UUID id, String status, int verificationAttempts);
 }
@@ -1,33 +1,65 @@
 package com.kubesec.account.repository;
 
+import com.kubesec.account.exception.CurrencyImmutableException;
+import com.kubesec.account.exception.LockContentionException;
+import com.kubesec.account.exception.NonZeroBalanceException;
 import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountBalance;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.model.Erasure;
+import com.kubesec.account.model.LinkedAccount;
 import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.UpdateUserRequest;
+import com.kubesec.account.tenant.TenantContext;
+import org.springframework.dao.DataAccessException;
 import org.springframework.dao.EmptyResultDataAccessException;
 import org.springframework.jdbc.core.JdbcTemplate;
 import org.springframework.jdbc.core.RowMapper;
 import org.springframework.stereotype.Repository;
 
+import java.math.BigDecimal;
 import java.sql.ResultSet;
 import java.sql.SQLException;
+import java.time.Duration;
+import java.time.LocalDate;
+import java.util.ArrayList;
 import java.util.List;
 import java.util.Optional;
+import java.util.TreeMap;
 import java.util.UUID;
 
 @Repository
 public class AccountRepositoryImpl implements AccountRepository {
 
+    private static final String USER_COLUMNS =
+            "id, tenant_id, email, full_name, kyc_status, nationality, country_of_residence, preferred_currency, "
+                    + "national_id, credit_score, credit_score_updated_at, max_accounts, total_transferred, transaction_count, created_at, updated_at, deleted_at";
+
+    private static final String ACCOUNT_COLUMNS =
+            "id, tenant_id, user_id, account_type, balance, currency, status, last_activity_at, "
+                    + "monthly_statement_enabled, max_daily_deposit, overdraft_limit, created_at, updated_at";
+
+    private static final String LINKED_ACCOUNT_COLUMNS =
+            "id, tenant_id, user_id, routing_number, account_number_hash, account_number_last4, bank_name, status, "
+                    + "micro_deposit_1, micro_deposit_2, verification_attempts, created_at, updated_at";
+
+    private static final Duration LOCK_POLL_INTERVAL = Duration.ofMillis(10);
+
     private final JdbcTemplate jdbc;
 
-    public AccountRepositoryImpl(JdbcTemplate jdbc) {
+    public AccountRepositoryImpl(JdbcTemplate jdbc, SchemaReadiness schemaReadiness) {
         this.jdbc = jdbc;
+        schemaReadiness.await("accounts");
     }
 
     @Override
     public void createUser(User user) {
         jdbc.update(
-                "INSERT INTO users (id, email, full_name, kyc_status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
-                user.getId(), user.getEmail(), user.getFullName(),
-                user.getKycStatus(), user.getCreatedAt(), user.getUpdatedAt()
+                "INSERT INTO users (id, tenant_id, email, full_name, kyc_status, nationality, country_of_residence, preferred_currency, national_id, max_accounts, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+                user.getId(), user.getTenantId(), user.getEmail(), user.getFullName(), user.getKycStatus(),
+                user.getNationality(), user.getCountryOfResidence(), user.getPreferredCurrency(),
+                user.getNationalId(), user.getMaxAccounts(), user.getCreatedAt(), user.getUpdatedAt()
         );
     }
 
@@ -35,19 +67,123 @@ public class AccountRepositoryImpl implements AccountRepository {
     public Optional<User> getUser(UUID id) {
         try {
             return Optional.ofNullable(jdbc.queryForObject(
-                    "SELECT id, email, full_name, kyc_status, created_at, updated_at FROM users WHERE id = ?",
-                    this::mapUser, id
+                    "SELECT " + USER_COLUMNS + " FROM users WHERE id = ? AND tenant_id = ?",
+                    this::mapUser, id, TenantContext.require()
             ));
         } catch (EmptyResultDataAccessException e) {
             return Optional.empty();
         }
     }
 
+    @Override
+    public List<User> getUsersByCountry(String country) {
+        return jdbc.query(
+                "SELECT " + USER_COLUMNS + " FROM users WHERE country_of_residence = ? AND tenant_id = ? ORDER BY created_at",
+                this::mapUser, country, TenantContext.require()
+        );
+    }
+
+    // Only the fields present in the request are set. Erased users can't be updated.
+    @Override
+    public Optional<User> updateUser(UUID userId, UpdateUserRequest request) {
+        List<String> assignments = new ArrayList<>();
+        List<Object> args = new ArrayList<>();
+        if (request.fullName() != null) {
+            assignments.add("full_name = ?");
+            args.add(request.fullName());
+        }
+        if (assignments.isEmpty()) {
+            return getUser(userId);
+        }
+        assignments.add("updated_at = NOW()");
+        args.add(userId);
+        args.add(TenantContext.require());
+        return jdbc.query(
+                "UPDATE users SET " + String.join(", ", assignments)
+                        + " WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL RETURNING " + USER_COLUMNS,
+                this::mapUser, args.toArray()
+        ).stream().findFirst();
+    }
+
+    @Override
+    public void updatePreferredCurrency(UUID userId, String currency) {
+        int rows = jdbc.update(
+                "UPDATE users SET preferred_currency = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                currency, userId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("user " + userId + " not found");
+        }
+    }
+
+    @Override
+    public void updateKycStatus(UUID userId, String kycStatus) {
+        int rows = jdbc.update(
+                "UPDATE users SET kyc_status = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                kycStatus, userId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("user " + userId + " not found");
+        }
+    }
+
+    // Callers run this in one transaction so the user, their accounts and the audit
+    // entry change together.
+    @Override
+    public void eraseUser(UUID userId, String requestedBy) {
+        UUID tenantId = TenantContext.require();
+        int rows = jdbc.update(
+                "UPDATE users SET email = ?, full_name = ?, nationality = NULL, country_of_residence = NULL,"
+                        + " national_id = NULL, credit_score = NULL, credit_score_updated_at = NULL,"
+                        + " deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL",
+                Erasure.anonymizedEmail(), Erasure.ERASED_NAME, userId, tenantId
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("user " + userId + " not found");
+        }
+        jdbc.update(
+                "UPDATE accounts SET status = 'closed', updated_at = NOW() WHERE user_id = ? AND tenant_id = ?",
+                userId, tenantId
+        );
+        jdbc.update(
+                "INSERT INTO gdpr_erasure_log (id, tenant_id, user_id_hash, requested_by) VALUES (?, ?, ?, ?)",
+                UUID.randomUUID(), tenantId, Erasure.hashUserId(userId), requestedBy
+        );
+    }
+
+    @Override
+    public void updateCreditScore(UUID userId, int score) {
+        int rows = jdbc.update(
+                "UPDATE users SET credit_score = ?, credit_score_updated_at = NOW(), updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                score, userId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("user " + userId + " not found");
+        }
+    }
+
+    @Override
+    public void incrementUserTransactionStats(UUID userId, BigDecimal amount) {
+        jdbc.update(
+                "UPDATE users SET total_transferred = total_transferred + ?, transaction_count = transaction_count + 1, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                amount, userId, TenantContext.require()
+        );
+    }
+
+    @Override
+    public boolean markEventProcessed(UUID transactionId) {
+        int rows = jdbc.update(
+                "INSERT INTO processed_events (transaction_id) VALUES (?) ON CONFLICT (transaction_id) DO NOTHING",
+                transactionId
+        );
+        return rows > 0;
+    }
+
     @Override
     public void createAccount(Account account) {
         jdbc.update(
-                "INSERT INTO accounts (id, user_id, account_type, balance, currency, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
-                account.getId(), account.getUserId(), account.getAccountType(),
+                "INSERT INTO accounts (id, tenant_id, user_id, account_type, balance, currency, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+                account.getId(), account.getTenantId(), account.getUserId(), account.getAccountType(),
                 account.getBalance(), account.getCurrency(), account.getStatus(),
                 account.getCreatedAt(), account.getUpdatedAt()
         );
@@ -55,26 +191,351 @@ public class AccountRepositoryImpl implements AccountRepository {
 
     @Override
     public Optional<Account> getAccount(UUID id) {
+        Account account;
         try {
-            return Optional.ofNullable(jdbc.queryForObject(
-                    "SELECT id, user_id, account_type, balance, currency, status, created_at, updated_at FROM accounts WHERE id = ?",
-                    this::mapAccount, id
-            ));
+            account = jdbc.queryForObject(
+                    "SELECT " + ACCOUNT_COLUMNS + " FROM accounts WHERE id = ? AND tenant_id = ?",
+                    this::mapAccount, id, TenantContext.require()
+            );
         } catch (EmptyResultDataAccessException e) {
             return Optional.empty();
         }
+        jdbc.query(
+                "SELECT b.currency, b.balance FROM account_currency_balances b"
+                        + " JOIN accounts a ON a.id = b.account_id WHERE a.id = ? AND a.tenant_id = ? ORDER BY b.currency",
+                rs -> {
+                    account.getCurrencyBalances().put(rs.getString("currency"), rs.getBigDecimal("balance"));
+                },
+                id, TenantContext.require()
+        );
+        return Optional.of(account);
+    }
+
+    // FOR SHARE makes the read wait for a concurrent adjustment to commit instead of
+    // returning the balance from before it.
+    @Override
+    public Optional<AccountBalance> getAccountBalance(UUID id) {
+        Optional<AccountBalance> row = jdbc.query(
+                "SELECT balance, currency, overdraft_limit FROM accounts WHERE id = ? AND tenant_id = ? FOR SHARE",
+                (rs, rowNum) -> new AccountBalance(rs.getBigDecimal("balance"), rs.getString("currency"),
+                        rs.getBigDecimal("overdraft_limit"), new TreeMap<>()),
+                id, TenantContext.require()
+        ).stream().findFirst();
+        row.ifPresent(balance -> jdbc.query(
+                "SELECT currency, balance FROM account_currency_balances WHERE account_id = ?",
+                rs -> {
+                    balance.currencyBalances().put(rs.getString("currency"), rs.getBigDecimal("balance"));
+                },
+                id
+        ));
+        return row;
+    }
+
+    @Override
+    public int countAccountsByUser(UUID userId) {
+        Integer count = jdbc.queryForObject(
+                "SELECT COUNT(*) FROM accounts WHERE user_id = ? AND tenant_id = ? AND status <> 'closed'",
+                Integer.class, userId, TenantContext.require()
+        );
+        return count != null ? count : 0;
     }
 
     @Override
     public List<Account> listAccountsByUser(UUID userId) {
         return jdbc.query(
-                "SELECT id, user_id, account_type, balance, currency, status, created_at, updated_at FROM accounts WHERE user_id = ? ORDER BY created_at",
-                this::mapAccount, userId
+                "SELECT " + ACCOUNT_COLUMNS + " FROM accounts WHERE user_id = ? AND tenant_id = ? ORDER BY created_at",
+                this::mapAccount, userId, TenantContext.require()
+        );
+    }
+
+    @Override
+    public List<Account> listAccounts(AccountFilter filter) {
+        StringBuilder query = new StringBuilder(
+                "SELECT " + ACCOUNT_COLUMNS + " FROM accounts WHERE 1=1"
+        );
+        List<Object> args = new ArrayList<>();
+        appendFilter(query, args, filter);
+
+        query.append(" ORDER BY created_at DESC");
+
+        if (filter.getLimit() > 0) {
+            query.append(" LIMIT ?");
+            args.add(filter.getLimit());
+        }
+
+        if (filter.getOffset() > 0) {
+            query.append(" OFFSET ?");
+            args.add(filter.getOffset());
+        }
+
+        return jdbc.query(query.toString(), this::mapAccount, args.toArray());
+    }
+
+    @Override
+    public int countAccounts(AccountFilter filter) {
+        StringBuilder query = new StringBuilder("SELECT COUNT(*) FROM accounts WHERE 1=1");
+        List<Object> args = new ArrayList<>();
+        appendFilter(query, args, filter);
+
+        Integer count = jdbc.queryForObject(query.toString(), Integer.class, args.toArray());
+        return count != null ? count : 0;
+    }
+
+    // Each predicate lines up with one of idx_accounts_user_id, idx_accounts_status
+    // or idx_accounts_account_type so the planner can pick an index scan.
+    private void appendFilter(StringBuilder query, List<Object> args, AccountFilter filter) {
+        query.append(" AND tenant_id = ?");
+        args.add(TenantContext.require());
+
+        if (filter.getUserId() != null) {
+            query.append(" AND user_id = ?");
+            args.add(filter.getUserId());
+        }
+
+        if (filter.getStatus() != null && !filter.getStatus().isEmpty()) {
+            query.append(" AND status = ?");
+            args.add(filter.getStatus());
+        }
+
+        if (filter.getAccountType() != null && !filter.getAccountType().isEmpty()) {
+            query.append(" AND account_type = ?");
+            args.add(filter.getAccountType());
+        }
+    }
+
+    // Transaction-scoped advisory lock, released by Postgres on commit or rollback.
+    // Must be called inside a transaction. Polls rather than blocking so a stuck
+    // holder surfaces as contention instead of a hung request.
+    @Override
+    public void lockAccount(UUID accountId, Duration timeout) {
+        long deadline = System.nanoTime() + timeout.toNanos();
+        while (true) {
+            Boolean acquired = jdbc.queryForObject(
+                    "SELECT pg_try_advisory_xact_lock(hashtext(?::text))", Boolean.class, accountId
+            );
+            if (Boolean.TRUE.equals(acquired)) {
+                return;
+            }
+            if (System.nanoTime() >= deadline) {
+                throw new LockContentionException("account " + accountId + " is locked by another request");
+            }
+            try {
+                Thread.sleep(LOCK_POLL_INTERVAL.toMillis());
+            } catch (InterruptedException e) {
+                Thread.currentThread().interrupt();
+                throw new LockContentionException("interrupted while waiting for account lock");
+            }
+        }
+    }
+
+    @Override
+    public void adjustBalance(UUID accountId, BigDecimal delta) {
+        int rows = jdbc.update(
+                "UPDATE accounts SET balance = balance + ?, last_activity_at = NOW(), updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                delta, accountId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+    }
+
+    @Override
+    public boolean adjustBalanceWithinOverdraft(UUID accountId, BigDecimal delta) {
+        // The limit is checked in the same statement as the update, so concurrent
+        // adjustments can't jointly push the balance past it.
+        int rows = jdbc.update(
+                "UPDATE accounts SET balance = balance + ?, last_activity_at = NOW(), updated_at = NOW()"
+                        + " WHERE id = ? AND tenant_id = ? AND balance + ? >= -overdraft_limit",
+                delta, accountId, TenantContext.require(), delta
+        );
+        return rows > 0;
+    }
+
+    @Override
+    public void adjustCurrencyBalance(UUID accountId, String currency, BigDecimal delta) {
+        // Selecting from accounts keeps the row within the caller's tenant.
+        int rows = jdbc.update(
+                "INSERT INTO account_currency_balances (account_id, currency, balance, updated_at)"
+                        + " SELECT id, ?, ?, NOW() FROM accounts WHERE id = ? AND tenant_id = ?"
+                        + " ON CONFLICT (account_id, currency) DO UPDATE"
+                        + " SET balance = account_currency_balances.balance + EXCLUDED.balance, updated_at = NOW()",
+                currency, delta, accountId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+        jdbc.update(
+                "UPDATE accounts SET last_activity_at = NOW(), updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                accountId, TenantContext.require()
+        );
+    }
+
+    @Override
+    public void updateAccountCurrency(UUID accountId, String currency) {
+        int rows;
+        try {
+            rows = jdbc.update(
+                    "UPDATE accounts SET currency = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                    currency, accountId, TenantContext.require()
+            );
+        } catch (DataAccessException e) {
+            // Raised by trg_accounts_currency_immutable, which makes the check atomic with the update
+            if (e.getMostSpecificCause() instanceof SQLException sqlException
+                    && "P0001".equals(sqlException.getSQLState())) {
+                throw new CurrencyImmutableException("account currency cannot change after its first transaction");
+            }
+            throw e;
+        }
+        if (rows == 0) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+    }
+
+    @Override
+    public void updateMonthlyStatementEnabled(UUID accountId, boolean enabled) {
+        int rows = jdbc.update(
+                "UPDATE accounts SET monthly_statement_enabled = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                enabled, accountId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+    }
+
+    @Override
+    public void updateMaxDailyDeposit(UUID accountId, BigDecimal limit) {
+        int rows = jdbc.update(
+                "UPDATE accounts SET max_daily_deposit = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                limit, accountId, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+    }
+
+    // The balance is read FOR UPDATE so no balance update can commit between the check
+    // and the status change. Must be called inside a transaction.
+    @Override
+    public void closeAccount(UUID accountId) {
+        UUID tenantId = TenantContext.require();
+        List<BigDecimal> balances = jdbc.queryForList(
+                "SELECT balance FROM accounts WHERE id = ? AND tenant_id = ? FOR UPDATE",
+                BigDecimal.class, accountId, tenantId
+        );
+        if (balances.isEmpty()) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+        if (balances.get(0).signum() != 0) {
+            throw new NonZeroBalanceException();
+        }
+        jdbc.update(
+                "UPDATE accounts SET status = 'closed', updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                accountId, tenantId
+        );
+    }
+
+    // Runs from the monthly statement worker across all tenants; closed accounts get no statement.
+    @Override
+    public List<Account> listAccountsWithStatementsEnabled() {
+        return jdbc.query(
+                "SELECT " + ACCOUNT_COLUMNS + " FROM accounts WHERE monthly_statement_enabled AND status <> 'closed' ORDER BY tenant_id, id",
+                this::mapAccount
+        );
+    }
+
+    // Accounts that never moved money count as active from their creation date.
+    @Override
+    public List<Account> listDormantAccounts(Duration dormantFor) {
+        return jdbc.query(
+                "SELECT " + ACCOUNT_COLUMNS + " FROM accounts WHERE COALESCE(last_activity_at, created_at) < NOW() - (? * INTERVAL '1 second') AND status = 'active' AND tenant_id = ? ORDER BY COALESCE(last_activity_at, created_at)",
+                this::mapAccount, dormantFor.toSeconds(), TenantContext.require()
         );
     }
 
+    // Runs from the scheduled job across all tenants.
+    @Override
+    public int freezeDormantAccounts(Duration dormantFor) {
+        return jdbc.update(
+                "UPDATE accounts SET status = 'frozen', updated_at = NOW() WHERE COALESCE(last_activity_at, created_at) < NOW() - (? * INTERVAL '1 second') AND status = 'active'",
+                dormantFor.toSeconds()
+        );
+    }
+
+    // Runs from the scheduled job across all tenants.
+    @Override
+    public int snapshotActiveBalances(LocalDate snapshotDate) {
+        return jdbc.update(
+                "INSERT INTO balance_snapshots (id, tenant_id, account_id, balance, snapshot_date) "
+                        + "SELECT gen_random_uuid(), tenant_id, id, balance, ? FROM accounts WHERE status = 'active' "
+                        + "ON CONFLICT (account_id, snapshot_date) DO NOTHING",
+                snapshotDate
+        );
+    }
+
+    @Override
+    public Optional<BalanceSnapshot> getBalanceSnapshotAtOrBefore(UUID accountId, LocalDate date) {
+        List<BalanceSnapshot> snapshots = jdbc.query(
+                "SELECT account_id, balance, snapshot_date FROM balance_snapshots WHERE account_id = ? AND tenant_id = ? AND snapshot_date <= ? ORDER BY snapshot_date DESC LIMIT 1",
+                (rs, rowNum) -> new BalanceSnapshot(
+                        rs.getObject("account_id", UUID.class),
+                        rs.getBigDecimal("balance"),
+                        rs.getObject("snapshot_date", LocalDate.class)),
+                accountId, TenantContext.require(), date
+        );
+        return snapshots.stream().findFirst();
+    }
+
+    @Override
+    public void createLinkedAccount(LinkedAccount linkedAccount) {
+        jdbc.update(
+                "INSERT INTO linked_accounts (id, tenant_id, user_id, routing_number, account_number_hash, account_number_last4, bank_name, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+                linkedAccount.id(), linkedAccount.tenantId(), linkedAccount.userId(), linkedAccount.routingNumber(),
+                linkedAccount.accountNumberHash(), linkedAccount.accountNumberLast4(), linkedAccount.bankName(),
+                linkedAccount.status(), linkedAccount.createdAt(), linkedAccount.updatedAt()
+        );
+    }
+
+    @Override
+    public Optional<LinkedAccount> getLinkedAccount(UUID id) {
+        List<LinkedAccount> linked = jdbc.query(
+                "SELECT " + LINKED_ACCOUNT_COLUMNS + " FROM linked_accounts WHERE id = ? AND tenant_id = ?",
+                this::mapLinkedAccount, id, TenantContext.require()
+        );
+        return linked.stream().findFirst();
+    }
+
+    @Override
+    public List<LinkedAccount> listLinkedAccounts(UUID userId) {
+        return jdbc.query(
+                "SELECT " + LINKED_ACCOUNT_COLUMNS + " FROM linked_accounts WHERE user_id = ? AND tenant_id = ? ORDER BY created_at",
+                this::mapLinkedAccount, userId, TenantContext.require()
+        );
+    }
+
+    @Override
+    public void setLinkedAccountMicroDeposits(UUID id, BigDecimal amount1, BigDecimal amount2) {
+        int rows = jdbc.update(
+                "UPDATE linked_accounts SET micro_deposit_1 = ?, micro_deposit_2 = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                amount1, amount2, id, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("linked account " + id + " not found");
+        }
+    }
+
+    @Override
+    public void updateLinkedAccountVerification(UUID id, String status, int verificationAttempts) {
+        int rows = jdbc.update(
+                "UPDATE linked_accounts SET status = ?, verification_attempts = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                status, verificationAttempts, id, TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("linked account " + id + " not found");
+        }
+    }
+
     private User mapUser(ResultSet rs, int rowNum) throws SQLException {
-        return new User(
+        User user = new User(
                 rs.getObject("id", UUID.class),
                 rs.getString("email"),
                 rs.getString("full_name"),
@@ -82,10 +543,22 @@ public class AccountRepositoryImpl implements AccountRepository {
                 rs.getObject("created_at", java.time.OffsetDateTime.class),
                 rs.getObject("updated_at", java.time.OffsetDateTime.class)
         );
+        user.setTenantId(rs.getObject("tenant_id", UUID.class));
+        user.setNationality(rs.getString("nationality"));
+        user.setCountryOfResidence(rs.getString("country_of_residence"));
+        user.setPreferredCurrency(rs.getString("preferred_currency"));
+        user.setNationalId(rs.getString("national_id"));
+        user.setCreditScore(rs.getObject("credit_score", Integer.class));
+        user.setCreditScoreUpdatedAt(rs.getObject("credit_score_updated_at", java.time.OffsetDateTime.class));
+        user.setMaxAccounts(rs.getInt("max_accounts"));
+        user.setTotalTransferred(rs.getBigDecimal("total_transferred"));
+        user.setTransactionCount(rs.getInt("transaction_count"));
+        user.setDeletedAt(rs.getObject("deleted_at", java.time.OffsetDateTime.class));
+        return user;
     }
 
     private Account mapAccount(ResultSet rs, int rowNum) throws SQLException {
-        return new Account(
+        Account account = new Account(
                 rs.getObject("id", UUID.class),
                 rs.getObject("user_id", UUID.class),
                 rs.getString("account_type"),
@@ -95,5 +568,29 @@ public class AccountRepositoryImpl implements AccountRepository {
                 rs.getObject("created_at", java.time.OffsetDateTime.class),
                 rs.getObject("updated_at", java.time.OffsetDateTime.class)
         );
+        account.setTenantId(rs.getObject("tenant_id", UUID.class));
+        account.setLastActivityAt(rs.getObject("last_activity_at", java.time.OffsetDateTime.class));
+        account.setMonthlyStatementEnabled(rs.getBoolean("monthly_statement_enabled"));
+        account.setMaxDailyDeposit(rs.getBigDecimal("max_daily_deposit"));
+        account.setOverdraftLimit(rs.getBigDecimal("overdraft_limit"));
+        return account;
+    }
+
+    private LinkedAccount mapLinkedAccount(ResultSet rs, int rowNum) throws SQLException {
+        return new LinkedAccount(
+                rs.getObject("id", UUID.class),
+                rs.getObject("tenant_id", UUID.class),
+                rs.getObject("user_id", UUID.class),
+                rs.getString("routing_number"),
+                rs.getString("account_number_hash"),
+                rs.getString("account_number_last4"),
+                rs.getString("bank_name"),
+                rs.getString("status"),
+                rs.getBigDecimal("micro_deposit_1"),
+                rs.getBigDecimal("micro_deposit_2"),
+                rs.getInt("verification_attempts"),
+                rs.getObject("created_at", java.time.OffsetDateTime.class),
+                rs.getObject("updated_at", java.time.OffsetDateTime.class)
+        );
     }
 }
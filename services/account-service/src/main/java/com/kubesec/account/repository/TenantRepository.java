@@ -0,0 +1,13 @@
+package com.kubesec.account.repository;
+
+import com.kubesec.account.model.Tenant;
+
+import java.util.List;
+import java.util.UUID;
+
+public interface TenantRepository {
+
+    boolean isActive(UUID tenantId);
+
+    List<Tenant> listTenants();
+}
@@ -0,0 +1,41 @@
+package com.kubesec.account.repository;
+
+import com.kubesec.account.model.Tenant;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.stereotype.Repository;
+
+import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.UUID;
+
+@Repository
+public class TenantRepositoryImpl implements TenantRepository {
+
+    private final JdbcTemplate jdbc;
+
+    public TenantRepositoryImpl(JdbcTemplate jdbc) {
+        this.jdbc = jdbc;
+    }
+
+    @Override
+    public boolean isActive(UUID tenantId) {
+        Integer count = jdbc.queryForObject(
+                "SELECT COUNT(*) FROM tenants WHERE id = ? AND active = TRUE",
+                Integer.class, tenantId
+        );
+        return count != null && count > 0;
+    }
+
+    @Override
+    public List<Tenant> listTenants() {
+        return jdbc.query(
+                "SELECT id, name, active, created_at FROM tenants ORDER BY name",
+                (rs, rowNum) -> new Tenant(
+                        rs.getObject("id", UUID.class),
+                        rs.getString("name"),
+                        rs.getBoolean("active"),
+                        rs.getObject("created_at", OffsetDateTime.class)
+                )
+        );
+    }
+}
@@ -0,0 +1,9 @@
+package com.kubesec.account.risk;
+
+import java.util.List;
+
+public record RiskScore(
+        int score,
+        String level,
+        List<String> factors
+) {}
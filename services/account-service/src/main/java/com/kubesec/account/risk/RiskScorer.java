@@ -0,0 +1,120 @@
+package com.kubesec.account.risk;
+
+import com.kubesec.account.client.AuthServiceClient;
+import com.kubesec.account.client.TransactionServiceClient;
+import com.kubesec.account.exception.ResourceNotFoundException;
+import com.kubesec.account.exception.UpstreamException;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.User;
+import com.kubesec.account.repository.AccountRepository;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.stereotype.Service;
+
+import java.time.Duration;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+// Combines login, transaction, KYC and tenure signals into a 0-100 fraud risk score.
+// Each factor is capped so no single signal can push a user to the maximum on its own.
+@Service
+public class RiskScorer {
+
+    private static final Logger log = LoggerFactory.getLogger(RiskScorer.class);
+    private static final Duration LOOKBACK = Duration.ofDays(30);
+
+    private static final int POINTS_PER_FAILED_LOGIN = 5;
+    private static final int MAX_FAILED_LOGIN_POINTS = 30;
+    private static final int POINTS_PER_FAILED_TRANSACTION = 10;
+    private static final int MAX_FAILED_TRANSACTION_POINTS = 30;
+    private static final int UNVERIFIED_KYC_POINTS = 25;
+    private static final int NEW_ACCOUNT_POINTS = 15;
+    private static final int RECENT_ACCOUNT_POINTS = 5;
+
+    private final AccountRepository repository;
+    private final AuthServiceClient authClient;
+    private final TransactionServiceClient transactionClient;
+
+    public RiskScorer(AccountRepository repository, AuthServiceClient authClient,
+                      TransactionServiceClient transactionClient) {
+        this.repository = repository;
+        this.authClient = authClient;
+        this.transactionClient = transactionClient;
+    }
+
+    public RiskScore computeRiskScore(UUID userId, String authHeader) {
+        User user = repository.getUser(userId)
+                .orElseThrow(() -> new ResourceNotFoundException("user not found"));
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        OffsetDateTime since = now.minus(LOOKBACK);
+
+        int failedLogins;
+        try {
+            failedLogins = authClient.countFailedLogins(user.getEmail(), since, authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("fetch failed logins")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not fetch login history");
+        }
+
+        List<UUID> accountIds = repository.listAccountsByUser(userId).stream()
+                .map(Account::getId)
+                .toList();
+        int failedTransactions = 0;
+        if (!accountIds.isEmpty()) {
+            try {
+                failedTransactions = transactionClient.countFailedTransactions(accountIds, since, authHeader);
+            } catch (Exception e) {
+                log.atError().setMessage("fetch failed transactions")
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+                throw new UpstreamException("could not fetch transaction history");
+            }
+        }
+
+        return score(failedLogins, failedTransactions, "verified".equals(user.getKycStatus()),
+                Duration.between(user.getCreatedAt(), now));
+    }
+
+    private static RiskScore score(int failedLogins, int failedTransactions, boolean kycVerified, Duration accountAge) {
+        int score = 0;
+        List<String> factors = new ArrayList<>();
+
+        if (failedLogins > 0) {
+            score += Math.min(failedLogins * POINTS_PER_FAILED_LOGIN, MAX_FAILED_LOGIN_POINTS);
+            factors.add(failedLogins + " failed logins in the last 30 days");
+        }
+        if (failedTransactions > 0) {
+            score += Math.min(failedTransactions * POINTS_PER_FAILED_TRANSACTION, MAX_FAILED_TRANSACTION_POINTS);
+            factors.add(failedTransactions + " failed transactions in the last 30 days");
+        }
+        if (!kycVerified) {
+            score += UNVERIFIED_KYC_POINTS;
+            factors.add("kyc not verified");
+        }
+        if (accountAge.compareTo(Duration.ofDays(30)) < 0) {
+            score += NEW_ACCOUNT_POINTS;
+            factors.add("user created less than 30 days ago");
+        } else if (accountAge.compareTo(Duration.ofDays(90)) < 0) {
+            score += RECENT_ACCOUNT_POINTS;
+            factors.add("user created less than 90 days ago");
+        }
+
+        score = Math.min(score, 100);
+        return new RiskScore(score, level(score), factors);
+    }
+
+    private static String level(int score) {
+        if (score >= 60) {
+            return "high";
+        }
+        if (score >= 30) {
+            return "medium";
+        }
+        return "low";
+    }
+}
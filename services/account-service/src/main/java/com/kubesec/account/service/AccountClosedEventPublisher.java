@@ -0,0 +1,37 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.AccountClosedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class AccountClosedEventPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(AccountClosedEventPublisher.class);
+    static final String SUBJECT = "accounts.closed";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public AccountClosedEventPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publishAccountClosed(AccountClosedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode account closure")
+                    .addKeyValue("account_id", event.accountId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
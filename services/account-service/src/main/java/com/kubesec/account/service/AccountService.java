@@ -1,29 +1,93 @@
 package com.kubesec.account.service;
 
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.AccountLimitReachedException;
+import com.kubesec.account.exception.ConflictException;
+import com.kubesec.account.exception.ForbiddenException;
 import com.kubesec.account.exception.ResourceNotFoundException;
+import com.kubesec.account.exception.ValidationException;
 import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountBalance;
+import com.kubesec.account.model.AccountBalances;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.model.Tenant;
 import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.AccountClosedEvent;
+import com.kubesec.account.model.dto.AdjustBalanceRequest;
+import com.kubesec.account.model.dto.AdjustCurrencyBalanceRequest;
+import com.kubesec.account.model.dto.BalanceAdjustedEvent;
 import com.kubesec.account.model.dto.CreateAccountRequest;
 import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.model.dto.CreditScoreRequestedEvent;
+import com.kubesec.account.model.dto.KycUpdatedEvent;
+import com.kubesec.account.model.dto.TransactionEvent;
+import com.kubesec.account.model.dto.UpdateBalanceRequest;
+import com.kubesec.account.model.dto.UpdateCurrencyRequest;
+import com.kubesec.account.model.dto.UpdateDepositLimitRequest;
+import com.kubesec.account.model.dto.UpdateKycStatusRequest;
+import com.kubesec.account.model.dto.UpdateStatementPreferencesRequest;
+import com.kubesec.account.model.dto.UpdateUserRequest;
 import com.kubesec.account.repository.AccountRepository;
+import com.kubesec.account.repository.TenantRepository;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.validation.CountryCodes;
+import com.kubesec.account.validation.CurrencyCodes;
+import org.springframework.lang.Nullable;
 import org.springframework.stereotype.Service;
+import org.springframework.transaction.annotation.Transactional;
 
 import java.math.BigDecimal;
+import java.time.Duration;
+import java.time.LocalDate;
 import java.time.OffsetDateTime;
 import java.time.ZoneOffset;
+import java.util.ArrayList;
 import java.util.List;
+import java.util.Map;
+import java.util.Objects;
+import java.util.TreeMap;
 import java.util.UUID;
+import java.util.stream.Stream;
 
 @Service
 public class AccountService {
 
+    private static final Duration BALANCE_LOCK_TIMEOUT = Duration.ofMillis(100);
+
+    // The bureau's score range; anything outside it is a bureau error, not a score.
+    static final int MIN_CREDIT_SCORE = 300;
+    static final int MAX_CREDIT_SCORE = 850;
+
     private final AccountRepository repository;
+    private final TenantRepository tenantRepository;
+    private final AppConfig config;
+    private final CreditScoreEventPublisher creditScorePublisher;
+    private final BalanceEventPublisher balanceEventPublisher;
+    private final KycEventPublisher kycEventPublisher;
+    private final AccountClosedEventPublisher accountClosedPublisher;
 
-    public AccountService(AccountRepository repository) {
+    public AccountService(AccountRepository repository, TenantRepository tenantRepository, AppConfig config,
+                          @Nullable CreditScoreEventPublisher creditScorePublisher,
+                          @Nullable BalanceEventPublisher balanceEventPublisher,
+                          @Nullable KycEventPublisher kycEventPublisher,
+                          @Nullable AccountClosedEventPublisher accountClosedPublisher) {
         this.repository = repository;
+        this.tenantRepository = tenantRepository;
+        this.config = config;
+        this.creditScorePublisher = creditScorePublisher;
+        this.balanceEventPublisher = balanceEventPublisher;
+        this.kycEventPublisher = kycEventPublisher;
+        this.accountClosedPublisher = accountClosedPublisher;
     }
 
     public User createUser(CreateUserRequest request) {
+        validateCountry("nationality", request.nationality());
+        validateCountry("country_of_residence", request.countryOfResidence());
+        if (request.preferredCurrency() != null) {
+            validateCurrency(request.preferredCurrency());
+        }
+
         OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
         User user = new User(
                 UUID.randomUUID(),
@@ -33,22 +97,270 @@ public class AccountService {
                 now,
                 now
         );
+        user.setTenantId(TenantContext.require());
+        user.setNationality(request.nationality());
+        user.setCountryOfResidence(request.countryOfResidence());
+        if (request.preferredCurrency() != null) {
+            user.setPreferredCurrency(request.preferredCurrency());
+        }
+        user.setNationalId(request.nationalId());
+        user.setMaxAccounts(config.getUserDefaultMaxAccounts());
         repository.createUser(user);
+
+        if (creditScorePublisher != null && user.getNationalId() != null) {
+            creditScorePublisher.publishCreditScoreRequested(new CreditScoreRequestedEvent(
+                    user.getId(), user.getTenantId(), user.getNationalId(), now));
+        }
         return user;
     }
 
+    public void updateCreditScore(UUID userId, int score) {
+        if (score < MIN_CREDIT_SCORE || score > MAX_CREDIT_SCORE) {
+            throw new ValidationException("credit score must be between " + MIN_CREDIT_SCORE
+                    + " and " + MAX_CREDIT_SCORE);
+        }
+        repository.updateCreditScore(userId, score);
+    }
+
     public User getUser(UUID id) {
         return repository.getUser(id)
                 .orElseThrow(() -> new ResourceNotFoundException("user not found"));
     }
 
+    // Takes the same account locks as adjustBalance, in id order, so a balance check there
+    // never acts on a balance this event is moving. The event itself isn't checked against
+    // the balance: transaction-service has already completed the transfer it records.
+    // Contention fails the event and the listener has it redelivered.
+    @Transactional
+    public void applyTransactionCompleted(TransactionEvent event) {
+        Stream.of(event.fromAccountId(), event.toAccountId())
+                .filter(Objects::nonNull)
+                .sorted()
+                .forEach(accountId -> repository.lockAccount(accountId, BALANCE_LOCK_TIMEOUT));
+        if (!repository.markEventProcessed(event.transactionId())) {
+            return; // already applied
+        }
+        // Deposits have no source account and withdrawals no destination
+        if (event.fromAccountId() != null) {
+            repository.getAccount(event.fromAccountId()).ifPresent(account ->
+                    repository.incrementUserTransactionStats(account.getUserId(), event.amount()));
+            // The sender also pays the transfer fee; the recipient is credited the amount only
+            BigDecimal debited = event.feeAmount() != null ? event.amount().add(event.feeAmount()) : event.amount();
+            repository.adjustBalance(event.fromAccountId(), debited.negate());
+        }
+        if (event.toAccountId() != null) {
+            BigDecimal credited = event.convertedAmount() != null ? event.convertedAmount() : event.amount();
+            repository.adjustBalance(event.toAccountId(), credited);
+        }
+    }
+
+    // Synchronous balance adjustment used by transaction-service. The transaction id is
+    // recorded as processed so the matching transactions.completed event becomes a no-op.
+    @Transactional
+    public Account adjustBalance(UUID accountId, AdjustBalanceRequest request) {
+        if (request.amount() == null || request.amount().signum() == 0) {
+            throw new ValidationException("amount must be non-zero");
+        }
+
+        // Serialise the balance check and update per account.
+        repository.lockAccount(accountId, BALANCE_LOCK_TIMEOUT);
+        Account account = getAccount(accountId);
+        if (!"active".equals(account.getStatus())) {
+            throw new ConflictException("account is " + account.getStatus());
+        }
+        if (account.getBalance().add(request.amount()).signum() < 0) {
+            throw new ConflictException("adjustment would overdraw the account");
+        }
+
+        if (request.transactionId() != null && !repository.markEventProcessed(request.transactionId())) {
+            return account; // already applied
+        }
+
+        repository.adjustBalance(accountId, request.amount());
+        return getAccount(accountId);
+    }
+
+    // Direct adjustment for teller operations such as cash deposits. Unlike adjustBalance
+    // the balance may go negative, down to the account's overdraft limit.
+    @Transactional
+    public Account updateBalance(UUID accountId, UpdateBalanceRequest request, @Nullable UUID adjustedBy) {
+        if (request.delta() == null || request.delta().signum() == 0) {
+            throw new ValidationException("delta must be non-zero");
+        }
+        Account account = getAccount(accountId);
+        if (!"active".equals(account.getStatus())) {
+            throw new ConflictException("account is " + account.getStatus());
+        }
+        if (!repository.adjustBalanceWithinOverdraft(accountId, request.delta())) {
+            throw new ConflictException("adjustment would exceed the overdraft limit");
+        }
+
+        Account updated = getAccount(accountId);
+        if (balanceEventPublisher != null) {
+            balanceEventPublisher.publishBalanceAdjusted(new BalanceAdjustedEvent(accountId, updated.getTenantId(),
+                    request.delta(), updated.getBalance(), request.reason(), request.reference(), adjustedBy,
+                    OffsetDateTime.now(ZoneOffset.UTC)));
+        }
+        return updated;
+    }
+
+    // Foreign-currency sub-balances can't be overdrawn, whatever the account's own
+    // overdraft limit; each currency is checked on its own.
+    @Transactional
+    public Account adjustCurrencyBalance(UUID accountId, String currency, AdjustCurrencyBalanceRequest request) {
+        validateCurrency(currency);
+        if (request.amount() == null || request.amount().signum() == 0) {
+            throw new ValidationException("amount must be non-zero");
+        }
+
+        repository.lockAccount(accountId, BALANCE_LOCK_TIMEOUT);
+        Account account = getAccount(accountId);
+        if (!"active".equals(account.getStatus())) {
+            throw new ConflictException("account is " + account.getStatus());
+        }
+        if (currency.equals(account.getCurrency())) {
+            throw new ValidationException("use the balance endpoint for the account's own currency");
+        }
+        BigDecimal current = account.getCurrencyBalances().getOrDefault(currency, BigDecimal.ZERO);
+        if (current.add(request.amount()).signum() < 0) {
+            throw new ConflictException("adjustment would overdraw the " + currency + " balance");
+        }
+
+        repository.adjustCurrencyBalance(accountId, currency, request.amount());
+        return getAccount(accountId);
+    }
+
+    // Defaults to the account's own currency. A currency the account has never held
+    // has a zero balance rather than being an error.
+    public AccountBalances getBalances(UUID accountId, @Nullable String currency) {
+        AccountBalance account = repository.getAccountBalance(accountId)
+                .orElseThrow(() -> new ResourceNotFoundException("account not found"));
+        Map<String, BigDecimal> balances = new TreeMap<>(account.currencyBalances());
+        balances.put(account.currency(), account.balance());
+
+        String requested = currency != null ? currency : account.currency();
+        validateCurrency(requested);
+        BigDecimal balance = balances.getOrDefault(requested, BigDecimal.ZERO);
+        BigDecimal available = requested.equals(account.currency())
+                ? balance.add(account.overdraftLimit())
+                : balance;
+        return new AccountBalances(accountId, balances, requested, balance, available);
+    }
+
+    public Account updateAccountCurrency(UUID accountId, UpdateCurrencyRequest request) {
+        String currency = request.currency();
+        Account account = getAccount(accountId);
+        if (currency.equals(account.getCurrency())) {
+            return account;
+        }
+        repository.updateAccountCurrency(accountId, currency);
+        return getAccount(accountId);
+    }
+
+    public Account updateStatementPreferences(UUID accountId, UpdateStatementPreferencesRequest request) {
+        getAccount(accountId);
+        repository.updateMonthlyStatementEnabled(accountId, request.monthlyStatementEnabled());
+        return getAccount(accountId);
+    }
+
+    // Deposits are checked against the limit by transaction-service, which owns the
+    // deposit history.
+    public Account updateMaxDailyDeposit(UUID accountId, UpdateDepositLimitRequest request) {
+        getAccount(accountId);
+        repository.updateMaxDailyDeposit(accountId, request.maxDailyDeposit());
+        return getAccount(accountId);
+    }
+
+    public List<Account> listDormantAccounts(int dormantDays) {
+        if (dormantDays < 1) {
+            throw new ValidationException("dormant_days must be positive");
+        }
+        return repository.listDormantAccounts(Duration.ofDays(dormantDays));
+    }
+
+    public int freezeDormantAccounts(Duration dormantFor) {
+        return repository.freezeDormantAccounts(dormantFor);
+    }
+
+    public int snapshotBalances(LocalDate snapshotDate) {
+        return repository.snapshotActiveBalances(snapshotDate);
+    }
+
+    // The closing balance of the latest day on or before the given date that has a snapshot.
+    public BalanceSnapshot getBalanceAtDate(UUID accountId, LocalDate date) {
+        getAccount(accountId);
+        return repository.getBalanceSnapshotAtOrBefore(accountId, date)
+                .orElseThrow(() -> new ResourceNotFoundException("no balance snapshot on or before " + date));
+    }
+
+    public List<User> getUsersByCountry(String country) {
+        validateCountry("country", country);
+        return repository.getUsersByCountry(country);
+    }
+
+    // Personal fields are overwritten rather than the row deleted, so balances and
+    // transaction history keep their references.
+    @Transactional
+    public void eraseUser(UUID userId, String requestedBy) {
+        User user = getUser(userId);
+        if (user.getDeletedAt() != null) {
+            throw new ConflictException("user has already been erased");
+        }
+        repository.eraseUser(userId, requestedBy);
+    }
+
+    public User updateUser(UUID userId, UpdateUserRequest request) {
+        if (request.isEmpty()) {
+            throw new ValidationException("nothing to update");
+        }
+        return repository.updateUser(userId, request)
+                .orElseThrow(() -> new ResourceNotFoundException("user not found"));
+    }
+
+    public User updatePreferredCurrency(UUID userId, UpdateCurrencyRequest request) {
+        validateCurrency(request.currency());
+        getUser(userId);
+        repository.updatePreferredCurrency(userId, request.currency());
+        return getUser(userId);
+    }
+
+    // Setting the status a user already has is a no-op and publishes nothing.
+    public User updateKycStatus(UUID userId, UpdateKycStatusRequest request) {
+        User user = getUser(userId);
+        String previous = user.getKycStatus();
+        if (request.kycStatus().equals(previous)) {
+            return user;
+        }
+        repository.updateKycStatus(userId, request.kycStatus());
+        User updated = getUser(userId);
+
+        if (kycEventPublisher != null) {
+            kycEventPublisher.publishKycUpdated(new KycUpdatedEvent(userId, updated.getTenantId(), previous,
+                    updated.getKycStatus(), OffsetDateTime.now(ZoneOffset.UTC)));
+        }
+        return updated;
+    }
+
+    private static void validateCurrency(String code) {
+        if (!CurrencyCodes.isValid(code)) {
+            throw new ValidationException("currency must be an ISO 4217 code");
+        }
+    }
+
+    private static void validateCountry(String field, String code) {
+        if (code != null && !CountryCodes.isValid(code)) {
+            throw new ValidationException(field + " must be an ISO 3166-1 alpha-2 code");
+        }
+    }
+
     public Account createAccount(CreateAccountRequest request) {
         UUID userId = UUID.fromString(request.userId());
+        User user = getUser(userId);
+        if (repository.countAccountsByUser(userId) >= user.getMaxAccounts()) {
+            throw new AccountLimitReachedException();
+        }
 
         String accountType = request.accountType();
-        if (!"checking".equals(accountType) && !"savings".equals(accountType)) {
-            throw new IllegalArgumentException("account_type must be checking or savings");
-        }
 
         String currency = request.currency();
         if (currency == null || currency.isEmpty()) {
@@ -66,6 +378,7 @@ public class AccountService {
                 now,
                 now
         );
+        account.setTenantId(TenantContext.require());
         repository.createAccount(account);
         return account;
     }
@@ -75,7 +388,126 @@ public class AccountService {
                 .orElseThrow(() -> new ResourceNotFoundException("account not found"));
     }
 
+    // A closed account stays readable. The advisory lock keeps adjustBalance from
+    // crediting the account between the balance check and the status change.
+    @Transactional
+    public Account closeAccount(UUID accountId) {
+        repository.lockAccount(accountId, BALANCE_LOCK_TIMEOUT);
+        Account account = getAccount(accountId);
+        if ("closed".equals(account.getStatus())) {
+            throw new ConflictException("account is already closed");
+        }
+        repository.closeAccount(accountId);
+
+        Account closed = getAccount(accountId);
+        if (accountClosedPublisher != null) {
+            accountClosedPublisher.publishAccountClosed(new AccountClosedEvent(accountId, closed.getTenantId(),
+                    closed.getUserId(), OffsetDateTime.now(ZoneOffset.UTC)));
+        }
+        return closed;
+    }
+
+    public Account getUserAccount(UUID userId, UUID accountId) {
+        Account account = getAccount(accountId);
+        if (!account.getUserId().equals(userId)) {
+            throw new ForbiddenException("account does not belong to this user");
+        }
+        return account;
+    }
+
     public List<Account> listAccountsByUser(UUID userId) {
         return repository.listAccountsByUser(userId);
     }
+
+    public List<Account> listAccounts(AccountFilter filter) {
+        if (filter.getStatus() != null && !filter.getStatus().isEmpty()
+                && !List.of("active", "frozen", "closed").contains(filter.getStatus())) {
+            throw new ValidationException("status must be active, frozen or closed");
+        }
+        if (filter.getAccountType() != null && !filter.getAccountType().isEmpty()
+                && !"checking".equals(filter.getAccountType()) && !"savings".equals(filter.getAccountType())) {
+            throw new ValidationException("account_type must be checking or savings");
+        }
+        return repository.listAccounts(filter);
+    }
+
+    public int countAccounts(AccountFilter filter) {
+        return repository.countAccounts(filter);
+    }
+
+    public List<Tenant> listTenants() {
+        return tenantRepository.listTenants();
+    }
+
+    public static Builder builder() {
+        return new Builder();
+    }
+
+    // Assembles the service outside the Spring context.
+    public static final class Builder {
+
+        private AccountRepository repository;
+        private TenantRepository tenantRepository;
+        private AppConfig config = new AppConfig();
+        private CreditScoreEventPublisher creditScorePublisher;
+        private BalanceEventPublisher balanceEventPublisher;
+        private KycEventPublisher kycEventPublisher;
+        private AccountClosedEventPublisher accountClosedPublisher;
+
+        private Builder() {}
+
+        public Builder withRepository(AccountRepository repository) {
+            this.repository = repository;
+            return this;
+        }
+
+        public Builder withTenantRepository(TenantRepository tenantRepository) {
+            this.tenantRepository = tenantRepository;
+            return this;
+        }
+
+        public Builder withConfig(AppConfig config) {
+            this.config = config;
+            return this;
+        }
+
+        public Builder withCreditScorePublisher(CreditScoreEventPublisher creditScorePublisher) {
+            this.creditScorePublisher = creditScorePublisher;
+            return this;
+        }
+
+        public Builder withBalanceEventPublisher(BalanceEventPublisher balanceEventPublisher) {
+            this.balanceEventPublisher = balanceEventPublisher;
+            return this;
+        }
+
+        public Builder withKycEventPublisher(KycEventPublisher kycEventPublisher) {
+            this.kycEventPublisher = kycEventPublisher;
+            return this;
+        }
+
+        public Builder withAccountClosedPublisher(AccountClosedEventPublisher accountClosedPublisher) {
+            this.accountClosedPublisher = accountClosedPublisher;
+            return this;
+        }
+
+        public void validate() {
+            List<String> missing = new ArrayList<>();
+            if (repository == null) {
+                missing.add("repository");
+            }
+            if (tenantRepository == null) {
+                missing.add("tenantRepository");
+            }
+            if (!missing.isEmpty()) {
+                throw new IllegalStateException("missing required dependencies: " + String.join(", ", missing));
+            }
+        }
+
+        public AccountService build() {
+            validate();
+            return new AccountService(repository, tenantRepository, config, creditScorePublisher,
+                    balanceEventPublisher, kycEventPublisher, accountClosedPublisher);
+        }
+    }
 }
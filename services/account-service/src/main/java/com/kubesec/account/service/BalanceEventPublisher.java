@@ -0,0 +1,37 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.BalanceAdjustedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class BalanceEventPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(BalanceEventPublisher.class);
+    static final String SUBJECT = "accounts.balance_adjusted";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public BalanceEventPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publishBalanceAdjusted(BalanceAdjustedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode balance adjustment")
+                    .addKeyValue("account_id", event.accountId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
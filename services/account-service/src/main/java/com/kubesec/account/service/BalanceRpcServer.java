@@ -0,0 +1,88 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.exception.DomainException;
+import com.kubesec.account.model.dto.AdjustBalanceRequest;
+import com.kubesec.account.model.dto.BalanceDeductReply;
+import com.kubesec.account.model.dto.BalanceDeductRequest;
+import com.kubesec.account.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Dispatcher;
+import io.nats.client.Message;
+import jakarta.annotation.PostConstruct;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+
+// Answers synchronous balance deductions from transaction-service over NATS
+// request-reply. Deductions go through the same path as the HTTP adjust endpoint.
+@Component
+@Profile("!test")
+public class BalanceRpcServer {
+
+    private static final Logger log = LoggerFactory.getLogger(BalanceRpcServer.class);
+    static final String SUBJECT = "accounts.balance.deduct";
+    private static final String QUEUE_GROUP = "account-service";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final AccountService accountService;
+
+    public BalanceRpcServer(Connection natsConnection, ObjectMapper objectMapper, AccountService accountService) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.accountService = accountService;
+    }
+
+    @PostConstruct
+    public void subscribe() {
+        Dispatcher dispatcher = natsConnection.createDispatcher(this::onMessage);
+        dispatcher.subscribe(SUBJECT, QUEUE_GROUP);
+        log.info("Subscribed to {}", SUBJECT);
+    }
+
+    private void onMessage(Message msg) {
+        if (msg.getReplyTo() == null) {
+            log.warn("Dropping {} message without a reply subject", SUBJECT);
+            return;
+        }
+        try {
+            natsConnection.publish(msg.getReplyTo(), objectMapper.writeValueAsBytes(deduct(msg.getData())));
+        } catch (JsonProcessingException e) {
+            log.error("Failed to encode balance deduction reply: {}", e.getMessage());
+        }
+    }
+
+    BalanceDeductReply deduct(byte[] data) {
+        BalanceDeductRequest request;
+        try {
+            request = objectMapper.readValue(data, BalanceDeductRequest.class);
+        } catch (IOException e) {
+            return BalanceDeductReply.failure("invalid_request", "could not decode request");
+        }
+        if (request.tenantId() == null || request.accountId() == null) {
+            return BalanceDeductReply.failure("invalid_request", "tenant_id and account_id are required");
+        }
+        if (request.amount() == null || request.amount().signum() <= 0) {
+            return BalanceDeductReply.failure("invalid_request", "amount must be positive");
+        }
+
+        TenantContext.set(request.tenantId());
+        try {
+            accountService.adjustBalance(request.accountId(),
+                    new AdjustBalanceRequest(request.amount().negate(), request.transactionId(), request.reason()));
+            return BalanceDeductReply.success();
+        } catch (DomainException e) {
+            return BalanceDeductReply.failure(e.getCode(), e.getMessage());
+        } catch (Exception e) {
+            log.error("Failed to deduct balance for account {}: {}", request.accountId(), e.getMessage());
+            return BalanceDeductReply.failure("internal_error", "internal error");
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,37 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.CreditScoreRequestedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class CreditScoreEventPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(CreditScoreEventPublisher.class);
+    static final String SUBJECT = "user.credit_score_requested";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public CreditScoreEventPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publishCreditScoreRequested(CreditScoreRequestedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode credit score request")
+                    .addKeyValue("user_id", event.userId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
@@ -0,0 +1,80 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.credit.CreditBureauClient;
+import com.kubesec.account.model.dto.CreditScoreRequestedEvent;
+import com.kubesec.account.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Dispatcher;
+import io.nats.client.Message;
+import jakarta.annotation.PostConstruct;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Component;
+
+// Fetches the bureau score for each newly created user, off the request path so a slow
+// or unavailable bureau never delays sign-up.
+@Component
+@Profile("!test")
+public class CreditScoreWorker {
+
+    private static final Logger log = LoggerFactory.getLogger(CreditScoreWorker.class);
+    private static final String QUEUE_GROUP = "account-service";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final CreditBureauClient creditBureauClient;
+    private final AccountService accountService;
+    private final AppConfig config;
+
+    public CreditScoreWorker(Connection natsConnection, ObjectMapper objectMapper,
+                             CreditBureauClient creditBureauClient, AccountService accountService,
+                             AppConfig config) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.creditBureauClient = creditBureauClient;
+        this.accountService = accountService;
+        this.config = config;
+    }
+
+    @PostConstruct
+    public void subscribe() {
+        if (config.getCreditBureauUrl().isEmpty()) {
+            log.info("Credit bureau not configured, credit score lookups disabled");
+            return;
+        }
+        Dispatcher dispatcher = natsConnection.createDispatcher(this::onMessage);
+        dispatcher.subscribe(CreditScoreEventPublisher.SUBJECT, QUEUE_GROUP);
+        log.info("Subscribed to {}", CreditScoreEventPublisher.SUBJECT);
+    }
+
+    private void onMessage(Message msg) {
+        try {
+            handle(objectMapper.readValue(msg.getData(), CreditScoreRequestedEvent.class));
+        } catch (Exception e) {
+            log.error("Failed to decode credit score request: {}", e.getMessage());
+        }
+    }
+
+    // Returns whether a score was stored. Failures are logged and dropped; the user simply
+    // has no score until one is requested again.
+    boolean handle(CreditScoreRequestedEvent event) {
+        if (event.tenantId() == null) {
+            log.warn("Dropping credit score request for user {} without tenant_id", event.userId());
+            return false;
+        }
+        try {
+            TenantContext.set(event.tenantId());
+            int score = creditBureauClient.getScore(event.nationalId());
+            accountService.updateCreditScore(event.userId(), score);
+            return true;
+        } catch (Exception e) {
+            log.error("Failed to update credit score for user {}: {}", event.userId(), e.getMessage());
+            return false;
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
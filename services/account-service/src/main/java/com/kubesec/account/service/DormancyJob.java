@@ -0,0 +1,35 @@
+package com.kubesec.account.service;
+
+import com.kubesec.account.config.AppConfig;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.time.Duration;
+
+@Component
+public class DormancyJob {
+
+    private static final Logger log = LoggerFactory.getLogger(DormancyJob.class);
+
+    private final AccountService accountService;
+    private final AppConfig config;
+
+    public DormancyJob(AccountService accountService, AppConfig config) {
+        this.accountService = accountService;
+        this.config = config;
+    }
+
+    @Scheduled(cron = "0 0 3 * * *")
+    public void freezeDormantAccounts() {
+        int days = config.getDormancyFreezeAfterDays();
+        if (days <= 0) {
+            return;
+        }
+        int frozen = accountService.freezeDormantAccounts(Duration.ofDays(days));
+        if (frozen > 0) {
+            log.info("froze {} accounts dormant for more than {} days", frozen, days);
+        }
+    }
+}
@@ -0,0 +1,37 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.KycUpdatedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class KycEventPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(KycEventPublisher.class);
+    static final String SUBJECT = "users.kyc_updated";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public KycEventPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publishKycUpdated(KycUpdatedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode kyc update")
+                    .addKeyValue("user_id", event.userId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
@@ -0,0 +1,37 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.LinkedAccountVerificationRequestedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class LinkedAccountEventPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(LinkedAccountEventPublisher.class);
+    private static final String SUBJECT = "linked_account.verification_requested";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public LinkedAccountEventPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publishVerificationRequested(LinkedAccountVerificationRequestedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode verification request")
+                    .addKeyValue("linked_account_id", event.linkedAccountId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
@@ -0,0 +1,59 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.dto.LinkedAccountMicroDepositsSentEvent;
+import com.kubesec.account.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Dispatcher;
+import io.nats.client.Message;
+import jakarta.annotation.PostConstruct;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Component;
+
+// Stores the amounts the payments worker sent in response to
+// linked_account.verification_requested, so the user can then verify them.
+@Component
+@Profile("!test")
+public class LinkedAccountMicroDepositListener {
+
+    private static final Logger log = LoggerFactory.getLogger(LinkedAccountMicroDepositListener.class);
+    private static final String SUBJECT = "linked_account.micro_deposits_sent";
+    private static final String QUEUE_GROUP = "account-service";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final LinkedAccountService linkedAccountService;
+
+    public LinkedAccountMicroDepositListener(Connection natsConnection, ObjectMapper objectMapper,
+                                             LinkedAccountService linkedAccountService) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.linkedAccountService = linkedAccountService;
+    }
+
+    @PostConstruct
+    public void subscribe() {
+        Dispatcher dispatcher = natsConnection.createDispatcher(this::onMessage);
+        dispatcher.subscribe(SUBJECT, QUEUE_GROUP);
+        log.info("Subscribed to {}", SUBJECT);
+    }
+
+    private void onMessage(Message msg) {
+        try {
+            LinkedAccountMicroDepositsSentEvent event =
+                    objectMapper.readValue(msg.getData(), LinkedAccountMicroDepositsSentEvent.class);
+            if (event.tenantId() == null) {
+                log.warn("Dropping micro-deposit event for linked account {} without tenant_id", event.linkedAccountId());
+                return;
+            }
+            TenantContext.set(event.tenantId());
+            linkedAccountService.recordMicroDeposits(event.linkedAccountId(), event.microDeposit1(), event.microDeposit2());
+        } catch (Exception e) {
+            log.error("Failed to handle micro-deposit event: {}", e.getMessage());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
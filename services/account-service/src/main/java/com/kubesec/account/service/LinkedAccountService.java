@@ -0,0 +1,143 @@
+package com.kubesec.account.service;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.ConflictException;
+import com.kubesec.account.exception.ResourceNotFoundException;
+import com.kubesec.account.exception.VerificationFailedException;
+import com.kubesec.account.model.LinkedAccount;
+import com.kubesec.account.model.dto.CreateLinkedAccountRequest;
+import com.kubesec.account.model.dto.LinkedAccountVerificationRequestedEvent;
+import com.kubesec.account.model.dto.VerifyLinkedAccountRequest;
+import com.kubesec.account.repository.AccountRepository;
+import com.kubesec.account.tenant.TenantContext;
+import org.springframework.lang.Nullable;
+import org.springframework.stereotype.Service;
+import org.springframework.transaction.annotation.Transactional;
+
+import javax.crypto.Mac;
+import javax.crypto.spec.SecretKeySpec;
+import java.math.BigDecimal;
+import java.nio.charset.StandardCharsets;
+import java.security.GeneralSecurityException;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.HexFormat;
+import java.util.List;
+import java.util.UUID;
+
+@Service
+public class LinkedAccountService {
+
+    static final String PENDING_VERIFICATION = "pending_verification";
+    static final String VERIFIED = "verified";
+    static final String FAILED = "failed";
+
+    // Wrong guesses allowed before the link is marked failed and must be re-created.
+    static final int MAX_VERIFICATION_ATTEMPTS = 3;
+
+    private final AccountRepository repository;
+    private final AppConfig config;
+    private final LinkedAccountEventPublisher eventPublisher;
+
+    public LinkedAccountService(AccountRepository repository, AppConfig config,
+                                @Nullable LinkedAccountEventPublisher eventPublisher) {
+        this.repository = repository;
+        this.config = config;
+        this.eventPublisher = eventPublisher;
+    }
+
+    public LinkedAccount linkAccount(UUID userId, CreateLinkedAccountRequest request) {
+        repository.getUser(userId)
+                .orElseThrow(() -> new ResourceNotFoundException("user not found"));
+
+        String hash = hashAccountNumber(request.routingNumber(), request.accountNumber());
+        boolean alreadyLinked = repository.listLinkedAccounts(userId).stream()
+                .anyMatch(l -> hash.equals(l.accountNumberHash()) && !FAILED.equals(l.status()));
+        if (alreadyLinked) {
+            throw new ConflictException("bank account is already linked");
+        }
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        String accountNumber = request.accountNumber();
+        LinkedAccount linked = new LinkedAccount(
+                UUID.randomUUID(),
+                TenantContext.require(),
+                userId,
+                request.routingNumber(),
+                hash,
+                accountNumber.substring(accountNumber.length() - 4),
+                request.bankName(),
+                PENDING_VERIFICATION,
+                null,
+                null,
+                0,
+                now,
+                now
+        );
+        repository.createLinkedAccount(linked);
+
+        if (eventPublisher != null) {
+            eventPublisher.publishVerificationRequested(new LinkedAccountVerificationRequestedEvent(
+                    linked.id(), linked.tenantId(), userId, request.routingNumber(), accountNumber, now));
+        }
+        return linked;
+    }
+
+    public List<LinkedAccount> listLinkedAccounts(UUID userId) {
+        return repository.listLinkedAccounts(userId);
+    }
+
+    // Called once the payments worker has sent the two micro-deposits.
+    public void recordMicroDeposits(UUID linkedAccountId, BigDecimal amount1, BigDecimal amount2) {
+        repository.setLinkedAccountMicroDeposits(linkedAccountId, amount1, amount2);
+    }
+
+    // The failed attempt has to be persisted even though the request errors.
+    @Transactional(noRollbackFor = VerificationFailedException.class)
+    public LinkedAccount verify(UUID userId, UUID linkedAccountId, VerifyLinkedAccountRequest request) {
+        LinkedAccount linked = repository.getLinkedAccount(linkedAccountId)
+                .filter(l -> userId.equals(l.userId()))
+                .orElseThrow(() -> new ResourceNotFoundException("linked account not found"));
+        if (!PENDING_VERIFICATION.equals(linked.status())) {
+            throw new ConflictException("linked account is " + linked.status());
+        }
+        if (linked.microDeposit1() == null || linked.microDeposit2() == null) {
+            throw new ConflictException("micro-deposits have not been sent yet");
+        }
+
+        if (amountsMatch(linked, request.microDeposit1(), request.microDeposit2())) {
+            repository.updateLinkedAccountVerification(linkedAccountId, VERIFIED, linked.verificationAttempts() + 1);
+            return repository.getLinkedAccount(linkedAccountId).orElseThrow();
+        }
+
+        int attempts = linked.verificationAttempts() + 1;
+        if (attempts >= MAX_VERIFICATION_ATTEMPTS) {
+            repository.updateLinkedAccountVerification(linkedAccountId, FAILED, attempts);
+            throw new VerificationFailedException("micro-deposit amounts do not match; verification attempts exhausted");
+        }
+        repository.updateLinkedAccountVerification(linkedAccountId, PENDING_VERIFICATION, attempts);
+        throw new VerificationFailedException("micro-deposit amounts do not match");
+    }
+
+    // The user doesn't know which deposit arrived first, so either order is accepted.
+    private static boolean amountsMatch(LinkedAccount linked, BigDecimal amount1, BigDecimal amount2) {
+        return (equal(linked.microDeposit1(), amount1) && equal(linked.microDeposit2(), amount2))
+                || (equal(linked.microDeposit1(), amount2) && equal(linked.microDeposit2(), amount1));
+    }
+
+    private static boolean equal(BigDecimal expected, BigDecimal actual) {
+        return actual != null && expected.compareTo(actual) == 0;
+    }
+
+    // Keyed so the stored hash can't be brute-forced from the small account number space.
+    private String hashAccountNumber(String routingNumber, String accountNumber) {
+        try {
+            Mac mac = Mac.getInstance("HmacSHA256");
+            mac.init(new SecretKeySpec(config.getLinkedAccountHashKey().getBytes(StandardCharsets.UTF_8), "HmacSHA256"));
+            byte[] digest = mac.doFinal((routingNumber + ":" + accountNumber).getBytes(StandardCharsets.UTF_8));
+            return HexFormat.of().formatHex(digest);
+        } catch (GeneralSecurityException e) {
+            throw new IllegalStateException("HmacSHA256 unavailable", e);
+        }
+    }
+}
@@ -0,0 +1,75 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.StatementRequestedEvent;
+import com.kubesec.account.repository.AccountRepository;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+
+// Requests last month's statement for every opted-in account. Generation itself is
+// done by whichever service consumes statements.monthly_requested.
+@Component
+@Profile("!test")
+public class MonthlyStatementWorker {
+
+    private static final Logger log = LoggerFactory.getLogger(MonthlyStatementWorker.class);
+    private static final String SUBJECT = "statements.monthly_requested";
+
+    private final AccountRepository repository;
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public MonthlyStatementWorker(AccountRepository repository, Connection natsConnection,
+                                  ObjectMapper objectMapper) {
+        this.repository = repository;
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    @Scheduled(cron = "0 0 2 1 * *", zone = "UTC")
+    public void requestStatements() {
+        requestStatements(OffsetDateTime.now(ZoneOffset.UTC));
+    }
+
+    void requestStatements(OffsetDateTime now) {
+        LocalDate periodEnd = periodEnd(now.toLocalDate());
+        LocalDate periodStart = periodStart(now.toLocalDate());
+
+        List<Account> accounts = repository.listAccountsWithStatementsEnabled();
+        int published = 0;
+        for (Account account : accounts) {
+            StatementRequestedEvent event = new StatementRequestedEvent(
+                    account.getId(), account.getTenantId(), account.getUserId(), periodStart, periodEnd, now);
+            try {
+                natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+                published++;
+            } catch (JsonProcessingException e) {
+                log.atError().setMessage("encode statement request")
+                        .addKeyValue("account_id", account.getId())
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+            }
+        }
+        log.info("requested {} monthly statements for {} to {}", published, periodStart, periodEnd);
+    }
+
+    // The statement covers the calendar month before the one containing today.
+    static LocalDate periodStart(LocalDate today) {
+        return today.withDayOfMonth(1).minusMonths(1);
+    }
+
+    static LocalDate periodEnd(LocalDate today) {
+        return today.withDayOfMonth(1);
+    }
+}
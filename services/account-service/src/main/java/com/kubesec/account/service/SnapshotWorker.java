@@ -0,0 +1,30 @@
+package com.kubesec.account.service;
+
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.time.LocalDate;
+import java.time.ZoneOffset;
+
+// Records every active account's balance as the closing balance of the UTC day that
+// just ended. Snapshots are unique per account and day, so a retried run is a no-op.
+@Component
+public class SnapshotWorker {
+
+    private static final Logger log = LoggerFactory.getLogger(SnapshotWorker.class);
+
+    private final AccountService accountService;
+
+    public SnapshotWorker(AccountService accountService) {
+        this.accountService = accountService;
+    }
+
+    @Scheduled(cron = "0 0 0 * * *", zone = "UTC")
+    public void snapshotBalances() {
+        LocalDate closedDay = LocalDate.now(ZoneOffset.UTC).minusDays(1);
+        int inserted = accountService.snapshotBalances(closedDay);
+        log.info("recorded {} balance snapshots for {}", inserted, closedDay);
+    }
+}
@@ -0,0 +1,136 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.filter.LogContextFilter;
+import com.kubesec.account.model.dto.TransactionEvent;
+import com.kubesec.account.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Dispatcher;
+import io.nats.client.JetStreamApiException;
+import io.nats.client.JetStreamSubscription;
+import io.nats.client.Message;
+import io.nats.client.PushSubscribeOptions;
+import io.nats.client.api.AckPolicy;
+import io.nats.client.api.ConsumerConfiguration;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.slf4j.MDC;
+import org.springframework.context.annotation.Profile;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.time.Duration;
+import java.util.UUID;
+
+// Applies transactions.completed.<account_id> from the TRANSACTIONS stream through one
+// durable consumer shared by every replica, so events published while account-service
+// is down are delivered once it is back. Each message is acked only after it has been
+// applied; a failure is redelivered.
+@Component
+@Profile("!test")
+public class TransactionEventListener {
+
+    private static final Logger log = LoggerFactory.getLogger(TransactionEventListener.class);
+    static final String STREAM = "TRANSACTIONS";
+    static final String SUBJECT_PREFIX = "transactions.completed.";
+    private static final String SUBJECT = SUBJECT_PREFIX + "*";
+    // Durable name and deliver group both; replicas share the consumer's position.
+    private static final String CONSUMER = "account-service";
+    private static final Duration ACK_WAIT = Duration.ofSeconds(30);
+    static final Duration RETRY_DELAY = Duration.ofSeconds(5);
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final AccountService accountService;
+    private Dispatcher dispatcher;
+    private JetStreamSubscription subscription;
+
+    public TransactionEventListener(Connection natsConnection, ObjectMapper objectMapper,
+                                    AccountService accountService) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.accountService = accountService;
+    }
+
+    // transaction-service creates the stream when it starts, which may be after this
+    // service, so the subscription is retried until the stream is there. Events wait in
+    // the stream meanwhile.
+    @Scheduled(fixedDelay = 5_000)
+    public synchronized void ensureSubscribed() {
+        if (subscription != null) {
+            return;
+        }
+        PushSubscribeOptions options = PushSubscribeOptions.builder()
+                .stream(STREAM)
+                .durable(CONSUMER)
+                .configuration(ConsumerConfiguration.builder()
+                        .ackPolicy(AckPolicy.Explicit)
+                        .ackWait(ACK_WAIT)
+                        .build())
+                .build();
+        try {
+            if (dispatcher == null) {
+                dispatcher = natsConnection.createDispatcher();
+            }
+            subscription = natsConnection.jetStream()
+                    .subscribe(SUBJECT, CONSUMER, dispatcher, this::onMessage, false, options);
+            log.info("Consuming {} from stream {} as {}", SUBJECT, STREAM, CONSUMER);
+        } catch (IOException | JetStreamApiException | IllegalStateException e) {
+            log.warn("Cannot consume {} from stream {} yet, retrying: {}", SUBJECT, STREAM, e.getMessage());
+        }
+    }
+
+    void onMessage(Message msg) {
+        TransactionEvent event;
+        try {
+            event = objectMapper.readValue(msg.getData(), TransactionEvent.class);
+        } catch (IOException e) {
+            log.error("Dropping undecodable transaction event on {}: {}", msg.getSubject(), e.getMessage());
+            msg.term();
+            return;
+        }
+        if (!isAppliedCopy(msg.getSubject(), event) || !"completed".equals(event.status())) {
+            msg.ack();
+            return;
+        }
+        if (event.tenantId() == null) {
+            log.warn("Dropping transaction event {} without tenant_id", event.transactionId());
+            msg.term();
+            return;
+        }
+
+        try {
+            TenantContext.set(event.tenantId());
+            // Log lines from here carry the ids of the request that moved the money
+            if (event.traceId() != null) {
+                MDC.put(LogContextFilter.TRACE_ID, event.traceId());
+            }
+            if (event.requestId() != null) {
+                MDC.put(LogContextFilter.REQUEST_ID, event.requestId());
+            }
+            if (event.correlationId() != null) {
+                MDC.put(LogContextFilter.CORRELATION_ID, event.correlationId());
+            }
+            accountService.applyTransactionCompleted(event);
+            msg.ack();
+        } catch (Exception e) {
+            log.error("Failed to apply transaction event {}, retrying: {}", event.transactionId(), e.getMessage());
+            msg.nakWithDelay(RETRY_DELAY);
+        } finally {
+            TenantContext.clear();
+            MDC.remove(LogContextFilter.TRACE_ID);
+            MDC.remove(LogContextFilter.REQUEST_ID);
+            MDC.remove(LogContextFilter.CORRELATION_ID);
+        }
+    }
+
+    // transaction-service publishes one copy of each event per account it touches, and
+    // applyTransactionCompleted moves both legs at once, so only the copy published for
+    // the sender (the recipient for deposits) is applied. The processed-event record
+    // still guards against a redelivered copy.
+    static boolean isAppliedCopy(String subject, TransactionEvent event) {
+        UUID account = event.fromAccountId() != null ? event.fromAccountId() : event.toAccountId();
+        return account != null && subject.equals(SUBJECT_PREFIX + account);
+    }
+}
@@ -0,0 +1,18 @@
+package com.kubesec.account.statement;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.util.List;
+import java.util.UUID;
+
+// from and to are both inclusive UTC dates.
+public record Statement(
+        @JsonProperty("account_id") UUID accountId,
+        String currency,
+        LocalDate from,
+        LocalDate to,
+        @JsonProperty("opening_balance") BigDecimal openingBalance,
+        @JsonProperty("closing_balance") BigDecimal closingBalance,
+        List<StatementRow> rows
+) {}
@@ -0,0 +1,50 @@
+package com.kubesec.account.statement;
+
+import java.io.IOException;
+import java.io.Writer;
+import java.math.BigDecimal;
+
+// Writes statement rows as RFC 4180 CSV with a header line.
+public final class StatementCsv {
+
+    private static final String HEADER = "date,description,debit,credit,running_balance";
+
+    private StatementCsv() {}
+
+    public static void write(Statement statement, Writer out) throws IOException {
+        out.write(HEADER);
+        out.write("\r\n");
+        for (StatementRow row : statement.rows()) {
+            out.write(row.date().toString());
+            out.write(',');
+            out.write(text(row.description()));
+            out.write(',');
+            out.write(amount(row.debit()));
+            out.write(',');
+            out.write(amount(row.credit()));
+            out.write(',');
+            out.write(amount(row.runningBalance()));
+            out.write("\r\n");
+        }
+        out.flush();
+    }
+
+    // Descriptions are user-supplied. A leading formula character is neutralised so a
+    // spreadsheet opening the file shows the text instead of evaluating it.
+    static String text(String value) {
+        if (value == null || value.isEmpty()) {
+            return "";
+        }
+        if ("=+-@\t\r".indexOf(value.charAt(0)) >= 0) {
+            value = "'" + value;
+        }
+        if (value.indexOf(',') >= 0 || value.indexOf('"') >= 0 || value.indexOf('\n') >= 0 || value.indexOf('\r') >= 0) {
+            return '"' + value.replace("\"", "\"\"") + '"';
+        }
+        return value;
+    }
+
+    private static String amount(BigDecimal value) {
+        return value != null ? value.toPlainString() : "";
+    }
+}
@@ -0,0 +1,122 @@
+package com.kubesec.account.statement;
+
+import com.kubesec.account.client.TransactionServiceClient;
+import com.kubesec.account.client.TransactionServiceClient.TransactionRecord;
+import com.kubesec.account.exception.ResourceNotFoundException;
+import com.kubesec.account.exception.UpstreamException;
+import com.kubesec.account.exception.ValidationException;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.repository.AccountRepository;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.stereotype.Service;
+
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Optional;
+import java.util.Set;
+import java.util.UUID;
+
+// Builds an account statement from transaction-service's history. The opening balance
+// is the latest balance snapshot before the period, rolled forward through the
+// transactions between that snapshot and the period start; an account with no earlier
+// snapshot starts from zero at its creation.
+@Service
+public class StatementGenerator {
+
+    private static final Logger log = LoggerFactory.getLogger(StatementGenerator.class);
+    private static final int MAX_PERIOD_DAYS = 366;
+    // Authorized holds and voided or failed transfers never moved the balance.
+    private static final Set<String> POSTED_STATUSES = Set.of("completed", "reversed");
+
+    private final AccountRepository repository;
+    private final TransactionServiceClient transactionClient;
+
+    public StatementGenerator(AccountRepository repository, TransactionServiceClient transactionClient) {
+        this.repository = repository;
+        this.transactionClient = transactionClient;
+    }
+
+    public Statement generate(UUID accountId, LocalDate from, LocalDate to, String authHeader) {
+        if (to.isBefore(from)) {
+            throw new ValidationException("to must not be before from");
+        }
+        if (from.plusDays(MAX_PERIOD_DAYS).isBefore(to)) {
+            throw new ValidationException("statement period must not exceed " + MAX_PERIOD_DAYS + " days");
+        }
+        Account account = repository.getAccount(accountId)
+                .orElseThrow(() -> new ResourceNotFoundException("account not found"));
+
+        Optional<BalanceSnapshot> snapshot = repository.getBalanceSnapshotAtOrBefore(accountId, from.minusDays(1));
+        OffsetDateTime periodStart = from.atStartOfDay().atOffset(ZoneOffset.UTC);
+        OffsetDateTime periodEnd = to.plusDays(1).atStartOfDay().atOffset(ZoneOffset.UTC);
+        OffsetDateTime historyStart = snapshot
+                .map(s -> s.snapshotDate().plusDays(1).atStartOfDay().atOffset(ZoneOffset.UTC))
+                .orElse(account.getCreatedAt());
+
+        List<TransactionRecord> history;
+        try {
+            history = transactionClient.listTransactions(accountId, historyStart, periodEnd, authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("fetch statement transactions")
+                    .addKeyValue("account_id", accountId)
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not fetch transaction history");
+        }
+
+        BigDecimal balance = snapshot.map(BalanceSnapshot::balance).orElse(BigDecimal.ZERO);
+        BigDecimal opening = null;
+        List<StatementRow> rows = new ArrayList<>();
+        for (TransactionRecord txn : history) {
+            // The listing's upper bound is inclusive; midnight belongs to the next day.
+            if (!txn.created_at().isBefore(periodEnd) || !POSTED_STATUSES.contains(txn.status())) {
+                continue;
+            }
+            BigDecimal debit = accountId.equals(txn.from_account_id()) ? debit(txn) : null;
+            BigDecimal credit = accountId.equals(txn.to_account_id()) ? credit(txn) : null;
+            if (txn.created_at().isBefore(periodStart)) {
+                balance = apply(balance, debit, credit);
+                continue;
+            }
+            if (opening == null) {
+                opening = balance;
+            }
+            balance = apply(balance, debit, credit);
+            rows.add(new StatementRow(txn.created_at().withOffsetSameInstant(ZoneOffset.UTC).toLocalDate(),
+                    description(txn), debit, credit, balance));
+        }
+
+        return new Statement(accountId, account.getCurrency(), from, to,
+                opening != null ? opening : balance, balance, rows);
+    }
+
+    // Matches account-service's own booking: the sender pays the amount plus any fee and
+    // the recipient is credited in its own currency.
+    private static BigDecimal debit(TransactionRecord txn) {
+        return txn.fee_amount() != null ? txn.amount().add(txn.fee_amount()) : txn.amount();
+    }
+
+    private static BigDecimal credit(TransactionRecord txn) {
+        return txn.converted_amount() != null ? txn.converted_amount() : txn.amount();
+    }
+
+    private static BigDecimal apply(BigDecimal balance, BigDecimal debit, BigDecimal credit) {
+        if (debit != null) {
+            balance = balance.subtract(debit);
+        }
+        if (credit != null) {
+            balance = balance.add(credit);
+        }
+        return balance;
+    }
+
+    private static String description(TransactionRecord txn) {
+        return txn.description() != null && !txn.description().isEmpty() ? txn.description() : txn.type();
+    }
+}
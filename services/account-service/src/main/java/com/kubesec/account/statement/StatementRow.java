@@ -0,0 +1,14 @@
+package com.kubesec.account.statement;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.LocalDate;
+
+// Exactly one of debit and credit is set.
+public record StatementRow(
+        LocalDate date,
+        String description,
+        BigDecimal debit,
+        BigDecimal credit,
+        @JsonProperty("running_balance") BigDecimal runningBalance
+) {}
@@ -0,0 +1,34 @@
+package com.kubesec.account.tenant;
+
+import java.util.Optional;
+import java.util.UUID;
+
+// Holds the tenant of the request (or event) being handled on the current thread.
+public final class TenantContext {
+
+    public static final String HEADER = "X-Tenant-ID";
+
+    private static final ThreadLocal<UUID> CURRENT = new ThreadLocal<>();
+
+    private TenantContext() {}
+
+    public static void set(UUID tenantId) {
+        CURRENT.set(tenantId);
+    }
+
+    public static void clear() {
+        CURRENT.remove();
+    }
+
+    public static Optional<UUID> get() {
+        return Optional.ofNullable(CURRENT.get());
+    }
+
+    public static UUID require() {
+        UUID tenantId = CURRENT.get();
+        if (tenantId == null) {
+            throw new IllegalStateException("no tenant bound to the current thread");
+        }
+        return tenantId;
+    }
+}
@@ -0,0 +1,19 @@
+package com.kubesec.account.validation;
+
+import java.util.Currency;
+import java.util.Set;
+import java.util.stream.Collectors;
+
+public final class CurrencyCodes {
+
+    // ISO 4217 codes as shipped with the JDK's currency data
+    private static final Set<String> ISO_4217 = Currency.getAvailableCurrencies().stream()
+            .map(Currency::getCurrencyCode)
+            .collect(Collectors.toUnmodifiableSet());
+
+    private CurrencyCodes() {}
+
+    public static boolean isValid(String code) {
+        return code != null && ISO_4217.contains(code);
+    }
+}
@@ -0,0 +1,15 @@
+-- Erased users keep their row so accounts and history stay referentially intact;
+-- personal fields are overwritten and deleted_at marks the erasure.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Audit trail of erasure requests. Only a SHA-256 of the user id is kept, enough to
+-- answer "was this user erased" without retaining the identifier itself.
+CREATE TABLE IF NOT EXISTS gdpr_erasure_log (
+    id           UUID PRIMARY KEY,
+    tenant_id    UUID NOT NULL REFERENCES tenants(id),
+    user_id_hash VARCHAR(64) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    erased_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_gdpr_erasure_log_user_id_hash ON gdpr_erasure_log (user_id_hash);
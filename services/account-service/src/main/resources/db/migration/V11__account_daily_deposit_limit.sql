@@ -0,0 +1,2 @@
+-- Anti-money-laundering cap on deposits per calendar day (UTC). NULL means no limit.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS max_daily_deposit NUMERIC(18, 2) CHECK (max_daily_deposit > 0);
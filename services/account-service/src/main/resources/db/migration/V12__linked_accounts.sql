@@ -0,0 +1,21 @@
+-- External bank accounts linked for ACH transfers. The account number itself is never
+-- stored: only a keyed hash (to spot duplicates) and the last four digits for display.
+-- The micro-deposit amounts are written by the worker that sends them.
+CREATE TABLE IF NOT EXISTS linked_accounts (
+    id                    UUID PRIMARY KEY,
+    tenant_id             UUID NOT NULL REFERENCES tenants(id),
+    user_id               UUID NOT NULL REFERENCES users(id),
+    routing_number        VARCHAR(9) NOT NULL,
+    account_number_hash   VARCHAR(64) NOT NULL,
+    account_number_last4  VARCHAR(4) NOT NULL,
+    bank_name             VARCHAR(255) NOT NULL,
+    status                VARCHAR(30) NOT NULL DEFAULT 'pending_verification'
+                          CHECK (status IN ('pending_verification', 'verified', 'failed')),
+    micro_deposit_1       NUMERIC(4, 2),
+    micro_deposit_2       NUMERIC(4, 2),
+    verification_attempts INT NOT NULL DEFAULT 0,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_linked_accounts_user ON linked_accounts (tenant_id, user_id);
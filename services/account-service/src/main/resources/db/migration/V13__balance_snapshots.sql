@@ -0,0 +1,11 @@
+-- End-of-day balances for reporting. snapshot_date is the UTC day the balance closed;
+-- one row per account per day so a re-run of the job is harmless.
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    id            UUID PRIMARY KEY,
+    tenant_id     UUID NOT NULL REFERENCES tenants(id),
+    account_id    UUID NOT NULL REFERENCES accounts(id),
+    balance       NUMERIC(18, 2) NOT NULL,
+    snapshot_date DATE NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, snapshot_date)
+);
@@ -0,0 +1,5 @@
+-- Credit scores come from the external bureau, looked up by national ID after the user
+-- is created. Both stay NULL until the bureau answers.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS national_id             VARCHAR(32);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS credit_score            INTEGER;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS credit_score_updated_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Cap on open accounts per user. New users get USER_DEFAULT_MAX_ACCOUNTS; existing
+-- users get the same default of 5.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_accounts INTEGER NOT NULL DEFAULT 5 CHECK (max_accounts >= 0);
@@ -0,0 +1,2 @@
+-- How far below zero a direct balance adjustment may take the account. 0 allows no overdraft.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS overdraft_limit NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0);
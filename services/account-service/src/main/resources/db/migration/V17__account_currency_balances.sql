@@ -0,0 +1,10 @@
+-- Sub-balances an account holds in currencies other than its own. accounts.balance
+-- stays the balance in accounts.currency; a row appears the first time a currency
+-- is credited.
+CREATE TABLE IF NOT EXISTS account_currency_balances (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    currency   VARCHAR(3) NOT NULL,
+    balance    NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, currency)
+);
@@ -0,0 +1,3 @@
+-- Indexes backing the admin account listing filters.
+CREATE INDEX IF NOT EXISTS idx_accounts_status       ON accounts (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_accounts_account_type ON accounts (account_type, created_at DESC);
@@ -0,0 +1,9 @@
+-- Lifetime transfer totals per user, maintained from transactions.completed events.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS total_transferred NUMERIC(18, 2) NOT NULL DEFAULT 0.00;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS transaction_count INTEGER        NOT NULL DEFAULT 0;
+
+-- processed_events makes event handling idempotent when events are redelivered or replayed.
+CREATE TABLE IF NOT EXISTS processed_events (
+    transaction_id UUID        PRIMARY KEY,
+    processed_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
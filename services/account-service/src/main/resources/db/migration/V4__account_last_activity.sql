@@ -0,0 +1,4 @@
+-- last_activity_at is bumped on every balance change and drives dormancy detection.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_last_activity ON accounts (last_activity_at) WHERE status = 'active';
@@ -0,0 +1,5 @@
+-- ISO 3166-1 alpha-2 codes required for regulatory reporting.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nationality          VARCHAR(2);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS country_of_residence VARCHAR(2);
+
+CREATE INDEX IF NOT EXISTS idx_users_country_of_residence ON users (country_of_residence);
@@ -0,0 +1,25 @@
+-- tenants are the financial institutions served by the platform. Rows created before
+-- multitenancy belong to the default tenant.
+CREATE TABLE IF NOT EXISTS tenants (
+    id         UUID         PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    active     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default')
+    ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(id);
+ALTER TABLE users ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users (tenant_id);
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS tenant_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(id);
+ALTER TABLE accounts ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS idx_accounts_tenant_id ON accounts (tenant_id);
+
+-- Emails only need to be unique within a tenant.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email ON users (tenant_id, email);
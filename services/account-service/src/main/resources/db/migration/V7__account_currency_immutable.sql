@@ -0,0 +1,17 @@
+-- The transactions themselves live in transaction-service's database; last_activity_at is
+-- set whenever one is applied to the account, so it marks accounts that have history.
+CREATE OR REPLACE FUNCTION prevent_account_currency_change() RETURNS trigger AS $$
+BEGIN
+    IF NEW.currency <> OLD.currency AND OLD.last_activity_at IS NOT NULL THEN
+        RAISE EXCEPTION 'currency of account % cannot change after its first transaction', OLD.id
+            USING ERRCODE = 'P0001';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_accounts_currency_immutable ON accounts;
+CREATE TRIGGER trg_accounts_currency_immutable
+    BEFORE UPDATE ON accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_account_currency_change();
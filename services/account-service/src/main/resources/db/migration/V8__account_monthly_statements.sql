@@ -0,0 +1,4 @@
+-- Accounts opt into monthly statements; the worker only ever reads the opted-in subset.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS monthly_statement_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_monthly_statement ON accounts (id) WHERE monthly_statement_enabled;
@@ -0,0 +1,2 @@
+-- Display currency for users holding accounts in several currencies.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
@@ -2,13 +2,16 @@ package com.kubesec.account;
 
 import org.junit.jupiter.api.Test;
 import org.springframework.boot.test.context.SpringBootTest;
+import org.springframework.test.context.ActiveProfiles;
 import org.springframework.test.context.TestPropertySource;
 
 @SpringBootTest
+@ActiveProfiles("test")
 @TestPropertySource(properties = {
         "spring.datasource.url=jdbc:h2:mem:testdb",
         "spring.datasource.driver-class-name=org.h2.Driver",
-        "spring.flyway.enabled=false"
+        "spring.flyway.enabled=false",
+        "app.db-schema-retry-attempts=0"
 })
 class ApplicationTest {
 
@@ -0,0 +1,28 @@
+package com.kubesec.account.config;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+class DbPoolEnvironmentPostProcessorTest {
+
+    @Test
+    void defaultsToTwiceTheAvailableProcessors() {
+        MockEnvironment environment = new MockEnvironment();
+
+        new DbPoolEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals(2 * Runtime.getRuntime().availableProcessors(),
+                environment.getProperty("DB_MAX_IDLE_CONNS", Integer.class));
+    }
+
+    @Test
+    void explicitSettingWins() {
+        MockEnvironment environment = new MockEnvironment().withProperty("DB_MAX_IDLE_CONNS", "3");
+
+        new DbPoolEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals(3, environment.getProperty("DB_MAX_IDLE_CONNS", Integer.class));
+    }
+}
@@ -0,0 +1,559 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.InternalTokenFilter;
+import com.kubesec.account.filter.ServiceTokenFilter;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.time.LocalDate;
+import java.util.List;
+import java.util.UUID;
+
+import static org.hamcrest.Matchers.matchesPattern;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.delete;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.patch;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives user and account handlers through the tenant filter with no database.
+class AccountControllerFlowTest {
+
+    private static final String INTERNAL_TOKEN = "back-office-secret";
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private InMemoryTenantRepository tenants;
+    private UUID tenantId;
+    private InMemoryAccountRepository repository;
+    private AccountService accountService;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        AppConfig config = new AppConfig();
+        config.setInternalToken(INTERNAL_TOKEN);
+        accountService = AccountService.builder().withRepository(repository).withTenantRepository(tenants).build();
+
+        // The risk-score and statement endpoints need the inter-service clients and are not exercised here.
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null, null))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants), new InternalTokenFilter(config))
+                .build();
+    }
+
+    @Test
+    void balanceAdjustmentsAreIdempotentPerTransaction() throws Exception {
+        String accountId = createAccount(createUser());
+        String transactionId = UUID.randomUUID().toString();
+
+        adjust(accountId, "100.00", transactionId).andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00));
+        adjust(accountId, "100.00", transactionId).andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00));
+
+        adjust(accountId, "-150.00", UUID.randomUUID().toString()).andExpect(status().isConflict());
+
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00));
+    }
+
+    // Not even an admin's or teller's own token can mint money through the service route.
+    @Test
+    void balanceAdjustmentRequiresAServiceToken() throws Exception {
+        String accountId = createAccount(createUser());
+
+        for (String role : new String[]{null, "customer", "teller", "admin"}) {
+            MockHttpServletRequestBuilder request = patch("/api/v1/accounts/" + accountId + "/balance/adjust")
+                    .header(TenantContext.HEADER, tenantId.toString())
+                    .contentType(MediaType.APPLICATION_JSON)
+                    .content("{\"amount\":1000000.00,\"transaction_id\":\"" + UUID.randomUUID() + "\"}");
+            if (role != null) {
+                request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, UUID.randomUUID())
+                        .requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+            }
+            mvc.perform(request).andExpect(status().isUnauthorized())
+                    .andExpect(jsonPath("$.code").value("unauthorized"));
+        }
+
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.balance").value(0.0));
+    }
+
+    @Test
+    void accountListingFiltersByStatusAndType() throws Exception {
+        String userId = createUser();
+        createAccount(userId);
+        String savingsId = objectMapper.readTree(postAccount(userId, "savings").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        postAccount(userId, "savings").andExpect(status().isCreated());
+        close(savingsId, null, UUID.fromString(userId)).andExpect(status().isNoContent());
+
+        listAccounts("admin", "account_type=savings").andExpect(status().isOk())
+                .andExpect(jsonPath("$.total").value(2));
+        listAccounts("admin", "account_type=savings&status=active").andExpect(status().isOk())
+                .andExpect(jsonPath("$.total").value(1))
+                .andExpect(jsonPath("$.accounts[0].account_type").value("savings"));
+        listAccounts("admin", "status=closed").andExpect(status().isOk())
+                .andExpect(jsonPath("$.total").value(1))
+                .andExpect(jsonPath("$.accounts[0].id").value(savingsId));
+        listAccounts("admin", "status=active&limit=1").andExpect(status().isOk())
+                .andExpect(jsonPath("$.total").value(2))
+                .andExpect(jsonPath("$.accounts.length()").value(1));
+    }
+
+    @Test
+    void accountListingRequiresAnAdminRole() throws Exception {
+        createAccount(createUser());
+
+        listAccounts(null, "status=active").andExpect(status().isForbidden());
+        listAccounts("customer", "status=active").andExpect(status().isForbidden());
+        // A role header from the client is not a role.
+        mvc.perform(get("/api/v1/accounts").header(TenantContext.HEADER, tenantId.toString())
+                        .header("X-User-Role", "admin"))
+                .andExpect(status().isForbidden());
+    }
+
+    @Test
+    void kycStatusRequiresTheInternalToken() throws Exception {
+        String userId = createUser();
+
+        mvc.perform(patch("/api/v1/users/" + userId + "/kyc")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"kyc_status\":\"verified\"}"))
+                .andExpect(status().isUnauthorized());
+
+        updateKyc(userId, "verified").andExpect(status().isOk())
+                .andExpect(jsonPath("$.kyc_status").value("verified"));
+        updateKyc(userId, "approved").andExpect(status().isUnprocessableEntity());
+
+        mvc.perform(get("/api/v1/users/" + userId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.kyc_status").value("verified"));
+    }
+
+    @Test
+    void currencyIsImmutableAfterFirstTransaction() throws Exception {
+        String accountId = createAccount(createUser());
+
+        updateCurrency(accountId, "EUR").andExpect(status().isOk())
+                .andExpect(jsonPath("$.currency").value("EUR"));
+
+        adjust(accountId, "10.00", UUID.randomUUID().toString()).andExpect(status().isOk());
+
+        updateCurrency(accountId, "GBP").andExpect(status().isConflict())
+                .andExpect(jsonPath("$.code").value("currency_immutable"));
+    }
+
+    @Test
+    void accountsAreScopedToTenant() throws Exception {
+        String accountId = createAccount(createUser());
+        UUID otherTenant = tenants.addTenant("globex", true).id();
+
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, otherTenant.toString()))
+                .andExpect(status().isNotFound());
+    }
+
+    @Test
+    void inactiveTenantIsRejected() throws Exception {
+        UUID inactive = tenants.addTenant("initech", false).id();
+
+        mvc.perform(get("/api/v1/accounts/" + UUID.randomUUID()).header(TenantContext.HEADER, inactive.toString()))
+                .andExpect(status().isForbidden());
+    }
+
+    @Test
+    void eraseUserAnonymizesPersonalDataAndClosesAccounts() throws Exception {
+        String userId = createUser();
+        String accountId = createAccount(userId);
+        UUID adminId = UUID.randomUUID();
+
+        erase(userId, "admin", adminId).andExpect(status().isNoContent());
+
+        mvc.perform(get("/api/v1/users/" + userId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.email").value(matchesPattern("erased-[0-9a-f-]{36}@deleted\\.invalid")))
+                .andExpect(jsonPath("$.full_name").value("DELETED"))
+                .andExpect(jsonPath("$.nationality").doesNotExist())
+                .andExpect(jsonPath("$.deleted_at").exists());
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.status").value("closed"));
+
+        List<InMemoryAccountRepository.ErasureLogEntry> log = repository.erasureLog();
+        assertEquals(1, log.size());
+        assertEquals(adminId.toString(), log.get(0).requestedBy());
+        assertEquals(64, log.get(0).userIdHash().length());
+        assertFalse(log.get(0).userIdHash().contains(userId));
+
+        erase(userId, "admin", adminId).andExpect(status().isConflict());
+    }
+
+    @Test
+    void eraseUserRequiresAdmin() throws Exception {
+        String userId = createUser();
+
+        erase(userId, "user", UUID.fromString(userId)).andExpect(status().isForbidden());
+        erase(userId, null, UUID.randomUUID()).andExpect(status().isForbidden());
+        assertTrue(repository.erasureLog().isEmpty());
+    }
+
+    @Test
+    void userUpdatesOnlyTheFieldsProvided() throws Exception {
+        String userId = createUser();
+        UUID self = UUID.fromString(userId);
+
+        updateUser(userId, null, self, "{\"full_name\":\"Alice Smith\"}").andExpect(status().isOk())
+                .andExpect(jsonPath("$.full_name").value("Alice Smith"))
+                .andExpect(jsonPath("$.email").value("alice@example.com"));
+        updateUser(userId, null, self, "{}").andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("nothing to update"));
+        updateUser(userId, null, self, "{\"full_name\":\"\"}").andExpect(status().isUnprocessableEntity());
+
+        mvc.perform(get("/api/v1/users/" + userId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.full_name").value("Alice Smith"));
+    }
+
+    @Test
+    void userUpdateIsLimitedToTheUserOrAnAdmin() throws Exception {
+        String userId = createUser();
+
+        updateUser(userId, null, UUID.randomUUID(), "{\"full_name\":\"Mallory\"}").andExpect(status().isForbidden());
+        updateUser(userId, null, null, "{\"full_name\":\"Mallory\"}").andExpect(status().isUnauthorized());
+        updateUser(userId, "admin", UUID.randomUUID(), "{\"full_name\":\"Alice B\"}").andExpect(status().isOk())
+                .andExpect(jsonPath("$.full_name").value("Alice B"));
+        updateUser(UUID.randomUUID().toString(), "admin", null, "{\"full_name\":\"Nobody\"}")
+                .andExpect(status().isNotFound());
+    }
+
+    @Test
+    void accountClosesOnlyOnceItsBalanceIsZero() throws Exception {
+        String userId = createUser();
+        String accountId = createAccount(userId);
+        UUID owner = UUID.fromString(userId);
+        adjust(accountId, "25.00", UUID.randomUUID().toString()).andExpect(status().isOk());
+
+        close(accountId, null, owner).andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.error").value("account has non-zero balance"));
+
+        adjust(accountId, "-25.00", UUID.randomUUID().toString()).andExpect(status().isOk());
+        close(accountId, null, owner).andExpect(status().isNoContent());
+
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("closed"));
+        close(accountId, null, owner).andExpect(status().isConflict());
+    }
+
+    @Test
+    void accountClosesOnlyForItsOwnerOrAnAdmin() throws Exception {
+        String accountId = createAccount(createUser());
+
+        close(accountId, null, null).andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.code").value("unauthorized"));
+        close(accountId, "user", UUID.randomUUID()).andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.code").value("forbidden"));
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.status").value("active"));
+
+        close(accountId, "admin", UUID.randomUUID()).andExpect(status().isNoContent());
+    }
+
+    @Test
+    void depositLimitIsSetByAdminOnly() throws Exception {
+        String accountId = createAccount(createUser());
+
+        depositLimit(accountId, "user", "500.00").andExpect(status().isForbidden());
+        depositLimit(accountId, "admin", "0").andExpect(status().isUnprocessableEntity());
+
+        depositLimit(accountId, "admin", "500.00").andExpect(status().isOk())
+                .andExpect(jsonPath("$.max_daily_deposit").value(500.00));
+        depositLimit(accountId, "admin", "null").andExpect(status().isOk())
+                .andExpect(jsonPath("$.max_daily_deposit").doesNotExist());
+    }
+
+    @Test
+    void historicalBalanceUsesNearestEarlierSnapshot() throws Exception {
+        String accountId = createAccount(createUser());
+        LocalDate day1 = LocalDate.of(2024, 1, 14);
+
+        adjust(accountId, "100.00", UUID.randomUUID().toString()).andExpect(status().isOk());
+        assertEquals(1, snapshot(day1));
+        assertEquals(0, snapshot(day1), "a second run for the same day inserts nothing");
+
+        adjust(accountId, "25.50", UUID.randomUUID().toString()).andExpect(status().isOk());
+        assertEquals(1, snapshot(day1.plusDays(2)));
+
+        balanceAt(accountId, "2024-01-14").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00))
+                .andExpect(jsonPath("$.snapshot_date").value("2024-01-14"));
+        balanceAt(accountId, "2024-01-15").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00))
+                .andExpect(jsonPath("$.snapshot_date").value("2024-01-14"));
+        balanceAt(accountId, "2024-02-01").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(125.50));
+
+        balanceAt(accountId, "2024-01-13").andExpect(status().isNotFound());
+        balanceAt(accountId, "yesterday").andExpect(status().isBadRequest());
+    }
+
+    @Test
+    void accountCreationStopsAtTheUsersLimit() throws Exception {
+        String userId = createUser();
+        mvc.perform(get("/api/v1/users/" + userId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.max_accounts").value(5));
+
+        for (int i = 0; i < 4; i++) {
+            createAccount(userId);
+        }
+        // The fifth account reaches the limit exactly; the sixth is one too many.
+        createAccount(userId);
+        postAccount(userId).andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.error").value("account limit reached"))
+                .andExpect(jsonPath("$.code").value("account_limit_reached"));
+    }
+
+    @Test
+    void accountForUnknownUserIsRejected() throws Exception {
+        postAccount(UUID.randomUUID().toString()).andExpect(status().isNotFound());
+    }
+
+    @Test
+    void unknownCountryCodesAreRejected() throws Exception {
+        // Well-formed but unassigned codes get past the schema and are caught by the service.
+        postUser("{\"email\":\"a@example.com\",\"full_name\":\"A\",\"nationality\":\"XX\"}")
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("nationality must be an ISO 3166-1 alpha-2 code"));
+        postUser("{\"email\":\"a@example.com\",\"full_name\":\"A\",\"country_of_residence\":\"UK\"}")
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("country_of_residence must be an ISO 3166-1 alpha-2 code"));
+        // Lowercase and alpha-3 codes fail the schema's pattern first.
+        postUser("{\"email\":\"a@example.com\",\"full_name\":\"A\",\"nationality\":\"us\"}")
+                .andExpect(status().isUnprocessableEntity());
+        postUser("{\"email\":\"a@example.com\",\"full_name\":\"A\",\"country_of_residence\":\"USA\"}")
+                .andExpect(status().isUnprocessableEntity());
+    }
+
+    @Test
+    void usersAreListedByCountryOfResidence() throws Exception {
+        String resident = objectMapper.readTree(postUser("{\"email\":\"a@example.com\",\"full_name\":\"A\","
+                        + "\"nationality\":\"FR\",\"country_of_residence\":\"TN\"}")
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.nationality").value("FR"))
+                .andExpect(jsonPath("$.country_of_residence").value("TN"))
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        postUser("{\"email\":\"b@example.com\",\"full_name\":\"B\",\"nationality\":\"TN\","
+                + "\"country_of_residence\":\"FR\"}").andExpect(status().isCreated());
+
+        usersByCountry("admin", "TN").andExpect(status().isOk())
+                .andExpect(jsonPath("$.length()").value(1))
+                .andExpect(jsonPath("$[0].id").value(resident));
+        usersByCountry("admin", "XX").andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("country must be an ISO 3166-1 alpha-2 code"));
+        usersByCountry("customer", "TN").andExpect(status().isForbidden());
+    }
+
+    @Test
+    void ownerReadsTheirAccountThroughTheUserRoute() throws Exception {
+        String userId = createUser();
+        String accountId = createAccount(userId);
+
+        userAccount(userId, accountId, UUID.fromString(userId)).andExpect(status().isOk())
+                .andExpect(jsonPath("$.id").value(accountId))
+                .andExpect(jsonPath("$.user_id").value(userId));
+    }
+
+    @Test
+    void foreignUserCannotReadAnotherUsersAccount() throws Exception {
+        String owner = createUser();
+        String accountId = createAccount(owner);
+        UUID intruder = UUID.fromString(createUser());
+
+        // Asking under the owner's id
+        userAccount(owner, accountId, intruder).andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.code").value("forbidden"))
+                .andExpect(jsonPath("$.error").value("cannot access another user's accounts"))
+                .andExpect(jsonPath("$.balance").doesNotExist());
+        // Asking under their own id for an account that is not theirs
+        userAccount(intruder.toString(), accountId, intruder).andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.error").value("account does not belong to this user"))
+                .andExpect(jsonPath("$.balance").doesNotExist());
+    }
+
+    @Test
+    void userRouteRequiresAnAuthenticatedCaller() throws Exception {
+        String userId = createUser();
+        String accountId = createAccount(userId);
+
+        userAccount(userId, accountId, null).andExpect(status().isUnauthorized());
+    }
+
+    @Test
+    void unknownAccountOnTheUserRouteIsNotFound() throws Exception {
+        String userId = createUser();
+
+        userAccount(userId, UUID.randomUUID().toString(), UUID.fromString(userId))
+                .andExpect(status().isNotFound());
+    }
+
+    private int snapshot(LocalDate day) {
+        return accountService.snapshotBalances(day);
+    }
+
+    private ResultActions balanceAt(String accountId, String at) throws Exception {
+        return mvc.perform(get("/api/v1/accounts/" + accountId + "/balance")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .param("at", at));
+    }
+
+    private ResultActions depositLimit(String accountId, String role, String limit) throws Exception {
+        return mvc.perform(patch("/api/v1/accounts/" + accountId + "/deposit-limit")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.ROLE_ATTRIBUTE, role)
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"max_daily_deposit\":" + limit + "}"));
+    }
+
+    private ResultActions updateUser(String userId, String role, UUID callerId, String body) throws Exception {
+        MockHttpServletRequestBuilder request = patch("/api/v1/users/" + userId)
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content(body);
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        if (callerId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, callerId.toString());
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions userAccount(String userId, String accountId, UUID callerId) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/users/" + userId + "/accounts/" + accountId)
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (callerId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, callerId.toString());
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions close(String accountId, String role, UUID callerId) throws Exception {
+        MockHttpServletRequestBuilder request = delete("/api/v1/accounts/" + accountId)
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        if (callerId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, callerId.toString());
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions erase(String userId, String role, UUID callerId) throws Exception {
+        MockHttpServletRequestBuilder request = delete("/api/v1/users/" + userId)
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, callerId.toString());
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions postUser(String body) throws Exception {
+        return mvc.perform(post("/api/v1/users")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content(body));
+    }
+
+    private ResultActions usersByCountry(String role, String country) throws Exception {
+        return mvc.perform(get("/api/v1/admin/users")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.ROLE_ATTRIBUTE, role)
+                .param("country", country));
+    }
+
+    private String createUser() throws Exception {
+        String body = mvc.perform(post("/api/v1/users")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"alice@example.com\",\"full_name\":\"Alice\"}"))
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("id").asText();
+    }
+
+    private String createAccount(String userId) throws Exception {
+        String body = postAccount(userId)
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("id").asText();
+    }
+
+    private ResultActions postAccount(String userId) throws Exception {
+        return postAccount(userId, "checking");
+    }
+
+    private ResultActions postAccount(String userId, String accountType) throws Exception {
+        return mvc.perform(post("/api/v1/accounts")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"user_id\":\"" + userId + "\",\"account_type\":\"" + accountType + "\"}"));
+    }
+
+    private ResultActions listAccounts(String role, String query) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/accounts?" + query)
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions adjust(String accountId, String amount, String transactionId) throws Exception {
+        return mvc.perform(patch("/api/v1/accounts/" + accountId + "/balance/adjust")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"amount\":" + amount + ",\"transaction_id\":\"" + transactionId + "\"}")
+                .requestAttr(ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE, "transaction-service"));
+    }
+
+    private ResultActions updateCurrency(String accountId, String currency) throws Exception {
+        return mvc.perform(patch("/api/v1/accounts/" + accountId + "/currency")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"currency\":\"" + currency + "\"}"));
+    }
+
+    private ResultActions updateKyc(String userId, String kycStatus) throws Exception {
+        return mvc.perform(patch("/api/v1/users/" + userId + "/kyc")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header(InternalTokenFilter.HEADER, INTERNAL_TOKEN)
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"kyc_status\":\"" + kycStatus + "\"}"));
+    }
+}
@@ -0,0 +1,157 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.ServiceTokenFilter;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.model.dto.CreateAccountRequest;
+import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.math.BigDecimal;
+import java.util.UUID;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.patch;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.header;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the foreign-currency sub-balances of a USD account.
+class CurrencyBalanceFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private UUID tenantId;
+    private UUID accountId;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        InMemoryAccountRepository repository = new InMemoryAccountRepository();
+        AccountService accountService = AccountService.builder()
+                .withRepository(repository)
+                .withTenantRepository(tenants)
+                .build();
+
+        TenantContext.set(tenantId);
+        try {
+            UUID userId = accountService.createUser(
+                    new CreateUserRequest("alice@example.com", "Alice", null, null, null, null)).getId();
+            accountId = accountService.createAccount(
+                    new CreateAccountRequest(userId.toString(), "checking", "USD")).getId();
+            repository.adjustBalance(accountId, new BigDecimal("100.00"));
+        } finally {
+            TenantContext.clear();
+        }
+        repository.setOverdraftLimit(accountId, new BigDecimal("50.00"));
+
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null, null))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void firstCreditAddsTheCurrency() throws Exception {
+        adjust("EUR", "40.00").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(100.00))
+                .andExpect(jsonPath("$.currency_balances.EUR").value(40.00));
+
+        balances("EUR").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balances.USD").value(100.00))
+                .andExpect(jsonPath("$.balances.EUR").value(40.00))
+                .andExpect(jsonPath("$.currency").value("EUR"))
+                .andExpect(jsonPath("$.balance").value(40.00))
+                .andExpect(jsonPath("$.available_balance").value(40.00));
+    }
+
+    @Test
+    void laterAdjustmentsChangeTheExistingBalance() throws Exception {
+        adjust("EUR", "40.00").andExpect(status().isOk());
+        adjust("EUR", "15.50").andExpect(status().isOk());
+        adjust("EUR", "-20.00").andExpect(status().isOk())
+                .andExpect(jsonPath("$.currency_balances.EUR").value(35.50));
+
+        mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.currency_balances.length()").value(1))
+                .andExpect(jsonPath("$.currency_balances.EUR").value(35.50));
+    }
+
+    @Test
+    void eachCurrencyIsCheckedForOverdraftOnItsOwn() throws Exception {
+        adjust("EUR", "10.00").andExpect(status().isOk());
+
+        // The USD balance and its overdraft limit don't cover a EUR shortfall.
+        adjust("EUR", "-10.01").andExpect(status().isConflict());
+        adjust("GBP", "-1.00").andExpect(status().isConflict());
+        adjust("EUR", "-10.00").andExpect(status().isOk())
+                .andExpect(jsonPath("$.currency_balances.EUR").value(0.0))
+                .andExpect(jsonPath("$.currency_balances.GBP").doesNotExist());
+    }
+
+    @Test
+    void availableBalanceOfTheAccountCurrencyIncludesTheOverdraft() throws Exception {
+        balances(null).andExpect(status().isOk())
+                .andExpect(header().string("Cache-Control", "no-store"))
+                .andExpect(jsonPath("$.currency").value("USD"))
+                .andExpect(jsonPath("$.balance").value(100.00))
+                .andExpect(jsonPath("$.available_balance").value(150.00));
+        balances("JPY").andExpect(status().isOk())
+                .andExpect(jsonPath("$.available_balance").value(0.0));
+    }
+
+    @Test
+    void adjustmentsRequireAServiceToken() throws Exception {
+        for (String role : new String[]{null, "customer", "admin"}) {
+            MockHttpServletRequestBuilder request = patch("/api/v1/accounts/" + accountId + "/balances/EUR")
+                    .header(TenantContext.HEADER, tenantId.toString())
+                    .contentType(MediaType.APPLICATION_JSON)
+                    .content("{\"amount\":40.00}");
+            if (role != null) {
+                request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+            }
+            mvc.perform(request).andExpect(status().isUnauthorized());
+        }
+
+        balances("EUR").andExpect(jsonPath("$.balances.EUR").doesNotExist());
+    }
+
+    @Test
+    void accountCurrencyIsNotASubBalance() throws Exception {
+        adjust("USD", "10.00").andExpect(status().isBadRequest());
+    }
+
+    private ResultActions adjust(String currency, String amount) throws Exception {
+        return mvc.perform(patch("/api/v1/accounts/" + accountId + "/balances/" + currency)
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"amount\":" + amount + "}")
+                .requestAttr(ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE, "transaction-service"));
+    }
+
+    private ResultActions balances(String currency) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/accounts/" + accountId + "/balance")
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (currency != null) {
+            request.param("currency", currency);
+        }
+        return mvc.perform(request);
+    }
+}
@@ -0,0 +1,174 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.ServiceTokenFilter;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.service.DormancyJob;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.UUID;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.patch;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives last_activity_at tracking, the dormant-account listing and the freeze job
+// against the in-memory repository, backdating activity instead of waiting for it.
+class DormancyFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private final OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+
+    private UUID tenantId;
+    private InMemoryAccountRepository repository;
+    private AccountService accountService;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        accountService = AccountService.builder().withRepository(repository).withTenantRepository(tenants).build();
+
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null, null))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void balanceChangesRecordLastActivity() throws Exception {
+        String accountId = createAccount();
+
+        account(accountId).andExpect(jsonPath("$.last_activity_at").doesNotExist());
+
+        adjust(accountId, "25.00").andExpect(status().isOk());
+
+        account(accountId).andExpect(jsonPath("$.last_activity_at").exists());
+    }
+
+    @Test
+    void dormantListingIncludesAccountsInactivePastTheCutoff() throws Exception {
+        String stale = createAccount();
+        String recent = createAccount();
+        String neverUsed = createAccount();
+        repository.setLastActivityAt(UUID.fromString(stale), now.minusDays(400));
+        repository.setLastActivityAt(UUID.fromString(recent), now.minusDays(10));
+        // With no activity at all, dormancy is measured from when the account was opened.
+        repository.setCreatedAt(UUID.fromString(neverUsed), now.minusDays(380));
+
+        dormant("admin", 365).andExpect(status().isOk())
+                .andExpect(jsonPath("$.length()").value(2))
+                .andExpect(jsonPath("$[0].id").value(stale))
+                .andExpect(jsonPath("$[1].id").value(neverUsed));
+        dormant("admin", 5).andExpect(status().isOk())
+                .andExpect(jsonPath("$.length()").value(3));
+
+        // Any balance change makes the account active again.
+        adjust(stale, "1.00").andExpect(status().isOk());
+        dormant("admin", 365).andExpect(status().isOk())
+                .andExpect(jsonPath("$.length()").value(1))
+                .andExpect(jsonPath("$[0].id").value(neverUsed));
+    }
+
+    @Test
+    void dormantListingIsAdminOnlyAndValidatesDays() throws Exception {
+        createAccount();
+
+        dormant(null, 365).andExpect(status().isForbidden());
+        dormant("customer", 365).andExpect(status().isForbidden());
+        dormant("admin", 0).andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("dormant_days must be positive"));
+    }
+
+    @Test
+    void freezeJobFreezesOnlyDormantActiveAccounts() throws Exception {
+        String stale = createAccount();
+        String recent = createAccount();
+        repository.setLastActivityAt(UUID.fromString(stale), now.minusDays(200));
+        repository.setLastActivityAt(UUID.fromString(recent), now.minusDays(30));
+
+        AppConfig config = new AppConfig();
+        config.setDormancyFreezeAfterDays(180);
+        new DormancyJob(accountService, config).freezeDormantAccounts();
+
+        account(stale).andExpect(jsonPath("$.status").value("frozen"));
+        account(recent).andExpect(jsonPath("$.status").value("active"));
+        // Frozen accounts are no longer reported as dormant.
+        dormant("admin", 180).andExpect(status().isOk())
+                .andExpect(jsonPath("$.length()").value(0));
+    }
+
+    @Test
+    void freezeJobIsDisabledWithoutAThreshold() throws Exception {
+        String stale = createAccount();
+        repository.setLastActivityAt(UUID.fromString(stale), now.minusDays(5000));
+
+        AppConfig config = new AppConfig();
+        config.setDormancyFreezeAfterDays(0);
+        new DormancyJob(accountService, config).freezeDormantAccounts();
+
+        account(stale).andExpect(jsonPath("$.status").value("active"));
+    }
+
+    private ResultActions dormant(String role, int days) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/admin/accounts/dormant")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .param("dormant_days", String.valueOf(days));
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions account(String accountId) throws Exception {
+        return mvc.perform(get("/api/v1/accounts/" + accountId).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk());
+    }
+
+    private ResultActions adjust(String accountId, String amount) throws Exception {
+        return mvc.perform(patch("/api/v1/accounts/" + accountId + "/balance/adjust")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"amount\":" + amount + ",\"transaction_id\":\"" + UUID.randomUUID() + "\"}")
+                .requestAttr(ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE, "transaction-service"));
+    }
+
+    private String createAccount() throws Exception {
+        String user = mvc.perform(post("/api/v1/users")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"dora@example.com\",\"full_name\":\"Dora\"}"))
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        String body = mvc.perform(post("/api/v1/accounts")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"user_id\":\"" + objectMapper.readTree(user).get("id").asText()
+                                + "\",\"account_type\":\"checking\"}"))
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("id").asText();
+    }
+}
@@ -0,0 +1,148 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.service.LinkedAccountService;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.math.BigDecimal;
+import java.util.UUID;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Links an external bank account and verifies it with micro-deposits. The worker that
+// sends the deposits is stood in for by writing the amounts to the repository.
+class LinkedAccountControllerFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private UUID tenantId;
+    private InMemoryAccountRepository repository;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        AccountService accountService = AccountService.builder().withRepository(repository).withTenantRepository(tenants).build();
+        LinkedAccountService linkedAccountService = new LinkedAccountService(repository, new AppConfig(), null);
+
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null, null),
+                        new LinkedAccountController(linkedAccountService))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void verificationSucceedsWithMatchingAmountsInEitherOrder() throws Exception {
+        String userId = createUser();
+        String linkedId = link(userId)
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.status").value("pending_verification"))
+                .andExpect(jsonPath("$.account_number_last4").value("6789"))
+                .andExpect(jsonPath("$.account_number").doesNotExist())
+                .andExpect(jsonPath("$.account_number_hash").doesNotExist())
+                .andReturn().getResponse().getContentAsString();
+        linkedId = objectMapper.readTree(linkedId).get("id").asText();
+
+        verify(userId, linkedId, "0.12", "0.34").andExpect(status().isConflict());
+
+        sendMicroDeposits(linkedId, "0.12", "0.34");
+        verify(userId, linkedId, "0.34", "0.12")
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("verified"))
+                .andExpect(jsonPath("$.micro_deposit_1").doesNotExist());
+
+        verify(userId, linkedId, "0.12", "0.34").andExpect(status().isConflict());
+    }
+
+    @Test
+    void verificationFailsAfterRepeatedWrongAmounts() throws Exception {
+        String userId = createUser();
+        String linkedId = objectMapper.readTree(link(userId).andReturn().getResponse().getContentAsString())
+                .get("id").asText();
+        sendMicroDeposits(linkedId, "0.12", "0.34");
+
+        verify(userId, linkedId, "0.11", "0.34").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("verification_failed"));
+        verify(userId, linkedId, "0.12", "0.33").andExpect(status().isUnprocessableEntity());
+        verify(userId, linkedId, "0.01", "0.02").andExpect(status().isUnprocessableEntity());
+
+        // Exhausted: even the right amounts are refused now.
+        verify(userId, linkedId, "0.12", "0.34").andExpect(status().isConflict());
+    }
+
+    @Test
+    void linkingIsLimitedToTheCaller() throws Exception {
+        String userId = createUser();
+
+        mvc.perform(post("/api/v1/users/" + userId + "/linked-accounts")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, UUID.randomUUID().toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content(linkBody()))
+                .andExpect(status().isForbidden());
+
+        link(userId).andExpect(status().isCreated());
+        link(userId).andExpect(status().isConflict());
+    }
+
+    private void sendMicroDeposits(String linkedId, String amount1, String amount2) {
+        TenantContext.set(tenantId);
+        try {
+            repository.setLinkedAccountMicroDeposits(UUID.fromString(linkedId),
+                    new BigDecimal(amount1), new BigDecimal(amount2));
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    private ResultActions link(String userId) throws Exception {
+        return mvc.perform(post("/api/v1/users/" + userId + "/linked-accounts")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, userId)
+                .contentType(MediaType.APPLICATION_JSON)
+                .content(linkBody()));
+    }
+
+    private static String linkBody() {
+        return "{\"routing_number\":\"021000021\",\"account_number\":\"123456789\",\"bank_name\":\"First Bank\"}";
+    }
+
+    private ResultActions verify(String userId, String linkedId, String amount1, String amount2) throws Exception {
+        return mvc.perform(post("/api/v1/users/" + userId + "/linked-accounts/" + linkedId + "/verify")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, userId)
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"micro_deposit_1\":\"" + amount1 + "\",\"micro_deposit_2\":\"" + amount2 + "\"}"));
+    }
+
+    private String createUser() throws Exception {
+        String body = mvc.perform(post("/api/v1/users")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"alice@example.com\",\"full_name\":\"Alice\"}"))
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("id").asText();
+    }
+}
@@ -0,0 +1,38 @@
+package com.kubesec.account.controller;
+
+import jakarta.servlet.http.PushBuilder;
+import org.junit.jupiter.api.Test;
+import org.mockito.Answers;
+import org.springframework.mock.web.MockHttpServletRequest;
+
+import java.util.List;
+
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.verify;
+
+// There is no HTTP/2 connector in a unit test, so the push builder the container
+// would hand out is mocked.
+class ServerPushTest {
+
+    @Test
+    void pushesEachPathWhenConnectionSupportsPush() {
+        PushBuilder builder = mock(PushBuilder.class, Answers.RETURNS_SELF);
+        MockHttpServletRequest request = new MockHttpServletRequest() {
+            @Override
+            public PushBuilder newPushBuilder() {
+                return builder;
+            }
+        };
+
+        ServerPush.push(request, List.of("/api/v1/users/42/accounts"));
+
+        verify(builder).path("/api/v1/users/42/accounts");
+        verify(builder).push();
+    }
+
+    @Test
+    void isNoOpWithoutPushSupport() {
+        // MockHttpServletRequest is HTTP/1.1 and returns no push builder.
+        ServerPush.push(new MockHttpServletRequest(), List.of("/api/v1/users/42/accounts"));
+    }
+}
@@ -0,0 +1,170 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.client.TransactionServiceClient;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.model.dto.CreateAccountRequest;
+import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.statement.StatementGenerator;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.content;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.header;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Builds statements for an account whose balance was snapshotted at 100.00 on
+// 2023-12-30, against a transaction history served from memory.
+class StatementFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private UUID tenantId;
+    private UUID accountId;
+    private StubTransactionServiceClient transactions;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        InMemoryAccountRepository repository = new InMemoryAccountRepository();
+        AccountService accountService = AccountService.builder()
+                .withRepository(repository)
+                .withTenantRepository(tenants)
+                .build();
+
+        TenantContext.set(tenantId);
+        try {
+            UUID userId = accountService.createUser(
+                    new CreateUserRequest("alice@example.com", "Alice", null, null, null, null)).getId();
+            accountId = accountService.createAccount(
+                    new CreateAccountRequest(userId.toString(), "checking", "USD")).getId();
+            repository.adjustBalance(accountId, new BigDecimal("100.00"));
+        } finally {
+            TenantContext.clear();
+        }
+        repository.snapshotActiveBalances(LocalDate.parse("2023-12-30"));
+
+        UUID other = UUID.randomUUID();
+        transactions = new StubTransactionServiceClient();
+        // Before the period: rolled into the opening balance.
+        transactions.add(null, accountId, "50.00", null, null, "completed", "", "2023-12-31T12:00:00Z");
+        transactions.add(accountId, other, "30.00", null, "0.30", "completed", "=SUM(A1)", "2024-01-05T09:30:00Z");
+        // A hold never moved the balance.
+        transactions.add(accountId, other, "20.00", null, "0.20", "authorized", "", "2024-01-10T10:00:00Z");
+        transactions.add(other, accountId, "25.00", "27.50", "0.25", "completed", "rent, Jan", "2024-01-15T18:00:00Z");
+        // Midnight after the last day belongs to the next period.
+        transactions.add(null, accountId, "10.00", null, null, "completed", "", "2024-02-01T00:00:00Z");
+
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null,
+                        new StatementGenerator(repository, transactions)))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void statementCarriesARunningBalanceFromTheOpeningBalance() throws Exception {
+        statement(null).andExpect(status().isOk())
+                .andExpect(jsonPath("$.currency").value("USD"))
+                .andExpect(jsonPath("$.opening_balance").value(150.00))
+                .andExpect(jsonPath("$.closing_balance").value(147.20))
+                .andExpect(jsonPath("$.rows.length()").value(2))
+                .andExpect(jsonPath("$.rows[0].date").value("2024-01-05"))
+                .andExpect(jsonPath("$.rows[0].debit").value(30.30))
+                .andExpect(jsonPath("$.rows[0].running_balance").value(119.70))
+                .andExpect(jsonPath("$.rows[1].credit").value(27.50))
+                .andExpect(jsonPath("$.rows[1].running_balance").value(147.20));
+
+        assertEquals("Bearer user-token", transactions.authHeader);
+        assertEquals(OffsetDateTime.parse("2023-12-31T00:00:00Z"), transactions.createdAfter);
+    }
+
+    @Test
+    void csvIsDownloadedAsAnAttachment() throws Exception {
+        statement("csv").andExpect(status().isOk())
+                .andExpect(header().string("Content-Disposition",
+                        "attachment; filename=\"statement-" + accountId + "-2024-01-01-2024-01-31.csv\""))
+                .andExpect(content().contentTypeCompatibleWith("text/csv"))
+                .andExpect(content().string("date,description,debit,credit,running_balance\r\n"
+                        + "2024-01-05,'=SUM(A1),30.30,,119.70\r\n"
+                        + "2024-01-15,\"rent, Jan\",,27.50,147.20\r\n"));
+    }
+
+    @Test
+    void invalidPeriodOrFormatIsRejected() throws Exception {
+        statement("xml").andExpect(status().isBadRequest());
+        mvc.perform(get("/api/v1/accounts/" + accountId + "/statement")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .param("from", "2024-01-31")
+                        .param("to", "2024-01-01"))
+                .andExpect(status().isBadRequest());
+    }
+
+    private ResultActions statement(String format) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/accounts/" + accountId + "/statement")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header("Authorization", "Bearer user-token")
+                .param("from", "2024-01-01")
+                .param("to", "2024-01-31");
+        if (format != null) {
+            request.param("format", format);
+        }
+        return mvc.perform(request);
+    }
+
+    // Serves the listing from memory, applying its inclusive bounds.
+    private static class StubTransactionServiceClient extends TransactionServiceClient {
+
+        private final List<TransactionRecord> records = new ArrayList<>();
+        String authHeader;
+        OffsetDateTime createdAfter;
+
+        StubTransactionServiceClient() {
+            super(new AppConfig(), RestClient.builder());
+        }
+
+        void add(UUID from, UUID to, String amount, String convertedAmount, String fee, String status,
+                 String description, String createdAt) {
+            records.add(new TransactionRecord(UUID.randomUUID(), from, to, new BigDecimal(amount), "USD",
+                    from == null ? "deposit" : "transfer", status, description,
+                    convertedAmount != null ? new BigDecimal(convertedAmount) : null,
+                    fee != null ? new BigDecimal(fee) : null, OffsetDateTime.parse(createdAt)));
+        }
+
+        @Override
+        public List<TransactionRecord> listTransactions(UUID accountId, OffsetDateTime createdAfter,
+                                                        OffsetDateTime createdBefore, String authHeader) {
+            this.authHeader = authHeader;
+            this.createdAfter = createdAfter;
+            return records.stream()
+                    .filter(r -> !r.created_at().isBefore(createdAfter) && !r.created_at().isAfter(createdBefore))
+                    .toList();
+        }
+    }
+}
@@ -0,0 +1,197 @@
+package com.kubesec.account.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import com.kubesec.account.filter.AuthFilter;
+import com.kubesec.account.filter.TenantFilter;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.BalanceAdjustedEvent;
+import com.kubesec.account.model.dto.CreateAccountRequest;
+import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.service.BalanceEventPublisher;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import com.kubesec.account.validation.SchemaValidationAdvice;
+import com.kubesec.account.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.math.BigDecimal;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+import java.util.concurrent.CountDownLatch;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.Future;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.patch;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives PATCH /api/v1/accounts/{id}/balance, the synchronous teller adjustment.
+class TellerBalanceFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private UUID tenantId;
+    private InMemoryAccountRepository repository;
+    private BalanceEventPublisher publisher;
+    private UUID accountId;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        publisher = mock(BalanceEventPublisher.class);
+        AccountService accountService = AccountService.builder()
+                .withRepository(repository)
+                .withTenantRepository(tenants)
+                .withBalanceEventPublisher(publisher)
+                .build();
+
+        TenantContext.set(tenantId);
+        try {
+            UUID userId = accountService.createUser(
+                    new CreateUserRequest("alice@example.com", "Alice", null, null, null, null)).getId();
+            Account account = accountService.createAccount(
+                    new CreateAccountRequest(userId.toString(), "checking", "USD"));
+            accountId = account.getId();
+        } finally {
+            TenantContext.clear();
+        }
+        repository.setOverdraftLimit(accountId, new BigDecimal("50.00"));
+
+        mvc = MockMvcBuilders.standaloneSetup(new AccountController(accountService, null, null))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void positiveDeltaCreditsTheAccountAndPublishes() throws Exception {
+        UUID tellerId = UUID.randomUUID();
+
+        updateBalance("teller", tellerId, "100.00", "cash_deposit", "branch-12/slip-881")
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.id").value(accountId.toString()))
+                .andExpect(jsonPath("$.balance").value(100.00));
+
+        ArgumentCaptor<BalanceAdjustedEvent> event = ArgumentCaptor.forClass(BalanceAdjustedEvent.class);
+        verify(publisher).publishBalanceAdjusted(event.capture());
+        assertEquals(accountId, event.getValue().accountId());
+        assertEquals(tenantId, event.getValue().tenantId());
+        assertEquals(0, new BigDecimal("100.00").compareTo(event.getValue().delta()));
+        assertEquals(0, new BigDecimal("100.00").compareTo(event.getValue().balance()));
+        assertEquals("cash_deposit", event.getValue().reason());
+        assertEquals("branch-12/slip-881", event.getValue().reference());
+        assertEquals(tellerId, event.getValue().adjustedBy());
+    }
+
+    @Test
+    void negativeDeltaMayDrawOnTheOverdraft() throws Exception {
+        updateBalance("admin", "20.00", "cash_deposit").andExpect(status().isOk());
+
+        updateBalance("teller", "-70.00", "cash_withdrawal").andExpect(status().isOk())
+                .andExpect(jsonPath("$.balance").value(-50.00));
+    }
+
+    @Test
+    void overdraftBreachIsRejectedAndNothingIsPublished() throws Exception {
+        updateBalance("teller", "-50.01", "cash_withdrawal").andExpect(status().isConflict())
+                .andExpect(jsonPath("$.error").value("adjustment would exceed the overdraft limit"));
+
+        assertEquals(0, balance().signum());
+        verify(publisher, never()).publishBalanceAdjusted(any());
+    }
+
+    @Test
+    void concurrentDebitsStopAtTheOverdraftLimit() throws Exception {
+        updateBalance("teller", "100.00", "cash_deposit").andExpect(status().isOk());
+
+        // 150.00 is available (100.00 balance + 50.00 overdraft), so exactly 15 of 20
+        // debits of 10.00 fit.
+        int debits = 20;
+        ExecutorService pool = Executors.newFixedThreadPool(debits);
+        CountDownLatch start = new CountDownLatch(1);
+        List<Future<Integer>> results = new ArrayList<>();
+        for (int i = 0; i < debits; i++) {
+            results.add(pool.submit(() -> {
+                start.await();
+                return updateBalance("teller", "-10.00", "cash_withdrawal").andReturn().getResponse().getStatus();
+            }));
+        }
+        start.countDown();
+        int ok = 0;
+        int conflict = 0;
+        for (Future<Integer> result : results) {
+            int code = result.get();
+            if (code == 200) ok++;
+            if (code == 409) conflict++;
+        }
+        pool.shutdown();
+
+        assertEquals(15, ok);
+        assertEquals(5, conflict);
+        assertEquals(0, new BigDecimal("-50.00").compareTo(balance()));
+        verify(publisher, times(16)).publishBalanceAdjusted(any());
+    }
+
+    @Test
+    void requiresAdminOrTellerRole() throws Exception {
+        updateBalance("user", "10.00", "cash_deposit").andExpect(status().isForbidden());
+        updateBalance(null, "10.00", "cash_deposit").andExpect(status().isForbidden());
+
+        assertEquals(0, balance().signum());
+    }
+
+    @Test
+    void rejectsUnknownReasonAndZeroDelta() throws Exception {
+        updateBalance("teller", "10.00", "gift").andExpect(status().isUnprocessableEntity());
+        updateBalance("teller", "0.00", "correction").andExpect(status().isBadRequest());
+    }
+
+    private BigDecimal balance() {
+        TenantContext.set(tenantId);
+        try {
+            return repository.getAccount(accountId).orElseThrow().getBalance();
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    private ResultActions updateBalance(String role, String delta, String reason) throws Exception {
+        return updateBalance(role, UUID.randomUUID(), delta, reason, null);
+    }
+
+    private ResultActions updateBalance(String role, UUID callerId, String delta, String reason, String reference)
+            throws Exception {
+        MockHttpServletRequestBuilder request = patch("/api/v1/accounts/" + accountId + "/balance")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, callerId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"delta\":\"" + delta + "\",\"reason\":\"" + reason + "\""
+                        + (reference != null ? ",\"reference\":\"" + reference + "\"" : "") + "}");
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+}
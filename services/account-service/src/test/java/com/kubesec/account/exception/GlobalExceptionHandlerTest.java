@@ -0,0 +1,93 @@
+package com.kubesec.account.exception;
+
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.Arguments;
+import org.junit.jupiter.params.provider.MethodSource;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.List;
+import java.util.stream.Stream;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+class GlobalExceptionHandlerTest {
+
+    private final ThrowingController controller = new ThrowingController();
+    private MockMvc mockMvc;
+
+    @BeforeEach
+    void setUp() {
+        mockMvc = MockMvcBuilders.standaloneSetup(controller)
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .build();
+    }
+
+    static Stream<Arguments> domainExceptions() {
+        return Stream.of(
+                Arguments.of(new AccountLimitReachedException(), 422, "account_limit_reached", "account limit reached"),
+                Arguments.of(new ConflictException("already exists"), 409, "conflict", "already exists"),
+                Arguments.of(new CurrencyImmutableException("currency cannot change"), 409,
+                        "currency_immutable", "currency cannot change"),
+                Arguments.of(new ForbiddenException("not yours"), 403, "forbidden", "not yours"),
+                Arguments.of(new LockContentionException("account is busy"), 503, "lock_contention", "account is busy"),
+                Arguments.of(new NonZeroBalanceException(), 422, "non_zero_balance", "account has non-zero balance"),
+                Arguments.of(new ResourceNotFoundException("account not found"), 404, "not_found", "account not found"),
+                Arguments.of(new UnauthorizedException("invalid token"), 401, "unauthorized", "invalid token"),
+                Arguments.of(new UpstreamException("auth service unavailable"), 502,
+                        "upstream_error", "auth service unavailable"),
+                Arguments.of(new ValidationException("name is required"), 400, "validation_error", "name is required"),
+                Arguments.of(new VerificationFailedException("amounts do not match"), 422,
+                        "verification_failed", "amounts do not match"));
+    }
+
+    @ParameterizedTest
+    @MethodSource("domainExceptions")
+    void domainExceptionsMapToTheirStatusAndCode(DomainException ex, int status, String code, String error)
+            throws Exception {
+        controller.next = ex;
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(status))
+                .andExpect(jsonPath("$.error").value(error))
+                .andExpect(jsonPath("$.code").value(code));
+    }
+
+    @Test
+    void schemaViolationsListEachViolation() throws Exception {
+        controller.next = new SchemaViolationException(List.of("currency: required"));
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.error").value("request body failed validation"))
+                .andExpect(jsonPath("$.code").value("schema_violation"))
+                .andExpect(jsonPath("$.violations[0]").value("currency: required"));
+    }
+
+    @Test
+    void unexpectedExceptionsAreHiddenBehindA500() throws Exception {
+        controller.next = new IllegalStateException("connection pool exhausted");
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(500))
+                .andExpect(jsonPath("$.error").value("internal server error"))
+                .andExpect(jsonPath("$.code").doesNotExist());
+    }
+
+    @RestController
+    static class ThrowingController {
+
+        RuntimeException next;
+
+        @GetMapping("/throw")
+        void fail() {
+            throw next;
+        }
+    }
+}
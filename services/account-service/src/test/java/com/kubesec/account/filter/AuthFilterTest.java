@@ -0,0 +1,90 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.client.AuthServiceClient;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.tenant.TenantContext;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+import org.springframework.web.client.RestClient;
+
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class AuthFilterTest {
+
+    private static final String TENANT = UUID.randomUUID().toString();
+
+    private final StubAuthServiceClient auth = new StubAuthServiceClient();
+    private final AuthFilter filter = new AuthFilter(auth);
+
+    @Test
+    void validatedTokenSetsTheCallerAndRole() throws Exception {
+        MockHttpServletRequest request = request("GET", "/api/v1/accounts", "Bearer good");
+
+        filter.doFilter(request, new MockHttpServletResponse(), (req, res) -> { });
+
+        assertEquals("user-1", request.getAttribute(AuthFilter.USER_ID_ATTRIBUTE));
+        assertEquals("admin", request.getAttribute(AuthFilter.ROLE_ATTRIBUTE));
+        assertEquals(List.of("good@" + TENANT), auth.calls);
+    }
+
+    @Test
+    void rejectedTokenLeavesTheRequestAnonymous() throws Exception {
+        MockHttpServletRequest request = request("GET", "/api/v1/accounts", "Bearer forged");
+        request.addHeader("X-User-Role", "admin");
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request, response, (req, res) -> { });
+
+        assertEquals(200, response.getStatus());
+        assertNull(request.getAttribute(AuthFilter.USER_ID_ATTRIBUTE));
+        assertNull(request.getAttribute(AuthFilter.ROLE_ATTRIBUTE));
+    }
+
+    @Test
+    void serviceRoutesAndRequestsWithoutATokenAreNotValidated() throws Exception {
+        String balance = "/api/v1/accounts/" + UUID.randomUUID() + "/balance";
+        filter.doFilter(request("GET", balance, "Bearer service"), new MockHttpServletResponse(), (req, res) -> { });
+        filter.doFilter(request("PATCH", balance + "/adjust", "Bearer service"), new MockHttpServletResponse(),
+                (req, res) -> { });
+        filter.doFilter(request("GET", "/api/v1/accounts", null), new MockHttpServletResponse(), (req, res) -> { });
+
+        assertTrue(auth.calls.isEmpty());
+
+        // A teller's balance update is not a service route.
+        filter.doFilter(request("PATCH", balance, "Bearer good"), new MockHttpServletResponse(), (req, res) -> { });
+        assertEquals(1, auth.calls.size());
+    }
+
+    private static MockHttpServletRequest request(String method, String path, String authorization) {
+        MockHttpServletRequest request = new MockHttpServletRequest(method, path);
+        request.addHeader(TenantContext.HEADER, TENANT);
+        if (authorization != null) {
+            request.addHeader("Authorization", authorization);
+        }
+        return request;
+    }
+
+    private static class StubAuthServiceClient extends AuthServiceClient {
+
+        final List<String> calls = new ArrayList<>();
+
+        StubAuthServiceClient() {
+            super(new AppConfig(), RestClient.builder());
+        }
+
+        @Override
+        public ValidateResponse validateToken(String token, String tenantId) {
+            calls.add(token + "@" + tenantId);
+            return "good".equals(token)
+                    ? new ValidateResponse(true, "user-1", "admin")
+                    : new ValidateResponse(false, null, null);
+        }
+    }
+}
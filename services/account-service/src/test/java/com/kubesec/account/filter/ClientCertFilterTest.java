@@ -0,0 +1,135 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.config.AppConfig;
+import jakarta.servlet.http.HttpServlet;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.boot.ssl.DefaultSslBundleRegistry;
+import org.springframework.boot.ssl.SslBundle;
+import org.springframework.boot.ssl.pem.PemSslStoreBundle;
+import org.springframework.boot.ssl.pem.PemSslStoreDetails;
+import org.springframework.boot.web.embedded.tomcat.TomcatServletWebServerFactory;
+import org.springframework.boot.web.server.Ssl;
+import org.springframework.boot.web.server.WebServer;
+
+import javax.net.ssl.SSLContext;
+import java.io.IOException;
+import java.net.URI;
+import java.net.http.HttpClient;
+import java.net.http.HttpRequest;
+import java.net.http.HttpResponse;
+import java.util.concurrent.atomic.AtomicInteger;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+// Runs real TLS handshakes against an embedded Tomcat configured like the mtls bundle:
+// the shared CA as trust store and client certificates wanted but not required.
+// The certificates under src/test/resources/mtls come from generate.sh there.
+class ClientCertFilterTest {
+
+    private static final String SERVICE_ROUTE = "/api/v1/accounts/6f1c1b7e-2f4a-4c43-9a57-0d9b1c7a5e21/balance";
+    private static final String USER_ROUTE = "/api/v1/users";
+
+    private final AtomicInteger handled = new AtomicInteger();
+    private AppConfig config;
+    private WebServer server;
+
+    @BeforeEach
+    void setUp() {
+        config = new AppConfig();
+        config.setMtlsCaCertFile("/etc/mtls/ca.crt");
+        config.setMtlsServerCertFile("/etc/mtls/tls.crt");
+        config.setMtlsServerKeyFile("/etc/mtls/tls.key");
+
+        DefaultSslBundleRegistry bundles = new DefaultSslBundleRegistry();
+        bundles.registerBundle("mtls", bundle("server"));
+        Ssl ssl = new Ssl();
+        ssl.setBundle("mtls");
+        ssl.setClientAuth(Ssl.ClientAuth.WANT);
+
+        TomcatServletWebServerFactory factory = new TomcatServletWebServerFactory(0);
+        factory.setSsl(ssl);
+        factory.setSslBundles(bundles);
+        server = factory.getWebServer(context -> {
+            context.addFilter("clientCert", new ClientCertFilter(config))
+                    .addMappingForUrlPatterns(null, false, "/*");
+            context.addServlet("ok", new HttpServlet() {
+                @Override
+                protected void service(HttpServletRequest req, HttpServletResponse resp) throws IOException {
+                    handled.incrementAndGet();
+                    resp.getWriter().write("ok");
+                }
+            }).addMapping("/");
+        });
+        server.start();
+    }
+
+    @AfterEach
+    void tearDown() {
+        server.stop();
+    }
+
+    @Test
+    void certificateFromTheSharedCaReachesServiceRoutes() throws Exception {
+        HttpResponse<String> response = get(client("client"), SERVICE_ROUTE);
+
+        assertEquals(200, response.statusCode());
+        assertEquals("ok", response.body());
+    }
+
+    @Test
+    void missingCertificateIsRejectedOnServiceRoutesOnly() throws Exception {
+        HttpClient anonymous = client(null);
+
+        HttpResponse<String> serviceCall = get(anonymous, SERVICE_ROUTE);
+        assertEquals(403, serviceCall.statusCode());
+        assertEquals("{\"error\":\"client certificate required\"}", serviceCall.body());
+        assertEquals(0, handled.get());
+
+        assertEquals(200, get(anonymous, USER_ROUTE).statusCode(), "user traffic needs no certificate");
+        assertEquals(200, get(anonymous, "/live").statusCode(), "nor do health probes");
+    }
+
+    @Test
+    void certificateFromAnotherCaFailsTheHandshake() {
+        HttpClient rogue = client("rogue-client");
+
+        assertThrows(IOException.class, () -> get(rogue, SERVICE_ROUTE));
+        assertThrows(IOException.class, () -> get(rogue, USER_ROUTE));
+        assertEquals(0, handled.get());
+    }
+
+    @Test
+    void serviceRoutesAreOpenWhenMtlsIsNotConfigured() throws Exception {
+        config.setMtlsCaCertFile("");
+
+        assertEquals(200, get(client(null), SERVICE_ROUTE).statusCode());
+    }
+
+    private HttpResponse<String> get(HttpClient client, String path) throws IOException, InterruptedException {
+        URI uri = URI.create("https://localhost:" + server.getPort() + path);
+        return client.send(HttpRequest.newBuilder(uri).build(), HttpResponse.BodyHandlers.ofString());
+    }
+
+    // Every client trusts the shared CA; name picks the certificate it presents, if any.
+    private static HttpClient client(String name) {
+        SSLContext sslContext = name != null
+                ? bundle(name).createSslContext()
+                : SslBundle.of(new PemSslStoreBundle(null, trustStore())).createSslContext();
+        return HttpClient.newBuilder().sslContext(sslContext).build();
+    }
+
+    private static SslBundle bundle(String name) {
+        PemSslStoreDetails keyStore = PemSslStoreDetails.forCertificate("classpath:mtls/" + name + ".crt")
+                .withPrivateKey("classpath:mtls/" + name + ".key");
+        return SslBundle.of(new PemSslStoreBundle(keyStore, trustStore()));
+    }
+
+    private static PemSslStoreDetails trustStore() {
+        return PemSslStoreDetails.forCertificate("classpath:mtls/ca.crt");
+    }
+}
@@ -0,0 +1,98 @@
+package com.kubesec.account.filter;
+
+import ch.qos.logback.classic.Level;
+import ch.qos.logback.classic.Logger;
+import ch.qos.logback.classic.spi.ILoggingEvent;
+import ch.qos.logback.core.read.ListAppender;
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import jakarta.servlet.http.HttpServletResponse;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.Test;
+import org.slf4j.LoggerFactory;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.nio.charset.StandardCharsets;
+import java.util.Map;
+import java.util.stream.Collectors;
+
+import static org.junit.jupiter.api.Assertions.assertArrayEquals;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class LoggingFilterTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper();
+    private final Logger logger = (Logger) LoggerFactory.getLogger(LoggingFilter.class);
+    private final Level originalLevel = logger.getLevel();
+
+    @AfterEach
+    void restoreLevel() {
+        logger.setLevel(originalLevel);
+    }
+
+    @Test
+    void sensitiveFieldsAreRemovedAtAnyDepth() throws Exception {
+        byte[] body = ("{\"email\":\"alice@example.com\",\"password\":\"hunter2\","
+                + "\"card\":{\"card_number\":\"4111111111111111\",\"cvv\":\"123\",\"expiry\":\"12/30\"},"
+                + "\"devices\":[{\"pin\":\"0000\",\"name\":\"phone\"}]}").getBytes(StandardCharsets.UTF_8);
+
+        byte[] sanitized = LoggingFilter.sanitizeJson(body, LoggingFilter.SENSITIVE_KEYS);
+
+        JsonNode json = objectMapper.readTree(sanitized);
+        assertEquals("alice@example.com", json.get("email").asText());
+        assertFalse(json.has("password"));
+        assertFalse(json.get("card").has("card_number"));
+        assertFalse(json.get("card").has("cvv"));
+        assertEquals("12/30", json.get("card").get("expiry").asText());
+        assertFalse(json.get("devices").get(0).has("pin"));
+        assertFalse(new String(sanitized, StandardCharsets.UTF_8).contains("hunter2"));
+    }
+
+    @Test
+    void invalidJsonIsDropped() {
+        byte[] sanitized = LoggingFilter.sanitizeJson(
+                "password=hunter2".getBytes(StandardCharsets.UTF_8), LoggingFilter.SENSITIVE_KEYS);
+
+        assertEquals(0, sanitized.length);
+    }
+
+    @Test
+    void logsOneLinePerRequestWithItsFields() throws Exception {
+        logger.setLevel(Level.INFO);
+        ListAppender<ILoggingEvent> appender = new ListAppender<>();
+        appender.start();
+        logger.addAppender(appender);
+        try {
+            new LoggingFilter().doFilter(new MockHttpServletRequest("GET", "/api/v1/accounts"),
+                    new MockHttpServletResponse(), (req, res) -> ((HttpServletResponse) res).setStatus(404));
+        } finally {
+            logger.detachAppender(appender);
+        }
+
+        assertEquals(1, appender.list.size());
+        Map<String, Object> fields = appender.list.get(0).getKeyValuePairs().stream()
+                .collect(Collectors.toMap(kv -> kv.key, kv -> kv.value));
+        assertEquals("GET", fields.get("method"));
+        assertEquals("/api/v1/accounts", fields.get("path"));
+        assertEquals(404, fields.get("status"));
+        assertTrue(fields.containsKey("duration_ms"));
+    }
+
+    @Test
+    void bodyIsStillReadableDownstream() throws Exception {
+        logger.setLevel(Level.DEBUG);
+        byte[] body = "{\"email\":\"alice@example.com\",\"password\":\"hunter2\"}".getBytes(StandardCharsets.UTF_8);
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/api/v1/users");
+        request.setContentType("application/json");
+        request.setContent(body);
+
+        byte[][] received = new byte[1][];
+        new LoggingFilter().doFilter(request, new MockHttpServletResponse(),
+                (req, res) -> received[0] = req.getInputStream().readAllBytes());
+
+        assertArrayEquals(body, received[0]);
+    }
+}
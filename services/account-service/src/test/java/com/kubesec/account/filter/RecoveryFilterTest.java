@@ -0,0 +1,81 @@
+package com.kubesec.account.filter;
+
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletResponse;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.HttpHeaders;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+
+class RecoveryFilterTest {
+
+    private final SimpleMeterRegistry registry = new SimpleMeterRegistry();
+    private final RecoveryFilter filter = new RecoveryFilter(registry);
+
+    @Test
+    void exceptionFromTheChainBecomesA500() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        response.setHeader(LogContextFilter.HEADER, "req-1");
+
+        // A statement export that fails after naming its attachment
+        MockHttpServletRequest request = new MockHttpServletRequest("GET", "/api/v1/accounts/" + UUID.randomUUID()
+                + "/statement");
+        filter.doFilter(request, response, (req, res) -> {
+            ((HttpServletResponse) res).setHeader(HttpHeaders.CONTENT_DISPOSITION, "attachment; filename=x.csv");
+            res.getWriter().write("date,amount\n");
+            throw new IllegalStateException("boom");
+        });
+
+        assertEquals(500, response.getStatus());
+        assertEquals("{\"error\":\"internal server error\"}", response.getContentAsString());
+        assertEquals("req-1", response.getHeader(LogContextFilter.HEADER));
+        assertNull(response.getHeader(HttpHeaders.CONTENT_DISPOSITION));
+        assertEquals(1.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+
+    @Test
+    void servletExceptionIsRecoveredToo() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(new MockHttpServletRequest("GET", "/api/v1/accounts"), response, (req, res) -> {
+            throw new ServletException("boom");
+        });
+
+        assertEquals(500, response.getStatus());
+        assertNull(response.getHeader(LogContextFilter.HEADER));
+        assertEquals(1.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+
+    @Test
+    void committedResponseIsLeftAsIs() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(new MockHttpServletRequest("GET", "/api/v1/accounts"), response, (req, res) -> {
+            res.getWriter().write("partial");
+            res.flushBuffer();
+            throw new IllegalStateException("boom");
+        });
+
+        assertEquals(200, response.getStatus());
+        assertEquals("partial", response.getContentAsString());
+        assertEquals(1.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+
+    @Test
+    void requestsThatCompleteAreNotCounted() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(new MockHttpServletRequest("GET", "/health"), response,
+                (req, res) -> res.getWriter().write("ok"));
+
+        assertEquals(200, response.getStatus());
+        assertEquals("ok", response.getContentAsString());
+        assertEquals(0.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+}
@@ -0,0 +1,146 @@
+package com.kubesec.account.filter;
+
+import com.kubesec.account.config.AppConfig;
+import io.jsonwebtoken.Jwts;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.io.TempDir;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.KeyPair;
+import java.security.KeyPairGenerator;
+import java.security.PrivateKey;
+import java.time.Instant;
+import java.util.Base64;
+import java.util.Date;
+import java.util.concurrent.atomic.AtomicBoolean;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class ServiceTokenFilterTest {
+
+    private static final String ADJUST_PATH = "/api/v1/accounts/0b7e5a3c-2f6d-4a51-9a8e-3c1d2b4f5e6a/balance/adjust";
+
+    private KeyPair authKeys;
+    private ServiceTokenFilter filter;
+
+    @BeforeEach
+    void setUp(@TempDir Path dir) throws Exception {
+        authKeys = rsaKeyPair();
+        Path publicKey = dir.resolve("service-token.pub");
+        Files.writeString(publicKey, "-----BEGIN PUBLIC KEY-----\n"
+                + Base64.getMimeEncoder().encodeToString(authKeys.getPublic().getEncoded())
+                + "\n-----END PUBLIC KEY-----\n");
+
+        AppConfig config = new AppConfig();
+        config.setServiceTokenPublicKeyFile(publicKey.toString());
+        filter = new ServiceTokenFilter(config);
+    }
+
+    @Test
+    void validTokenReachesHandlerWithCallerName() throws Exception {
+        MockHttpServletRequest request = request(ADJUST_PATH, token(authKeys.getPrivate(), "account-service", 60));
+        AtomicBoolean called = new AtomicBoolean();
+
+        filter.doFilter(request, new MockHttpServletResponse(), (req, res) -> called.set(true));
+
+        assertTrue(called.get());
+        assertEquals("transaction-service", request.getAttribute(ServiceTokenFilter.SERVICE_NAME_ATTRIBUTE));
+    }
+
+    @Test
+    void missingTokenIsRejected() throws Exception {
+        assertRejected(request(ADJUST_PATH, null));
+    }
+
+    @Test
+    void expiredTokenIsRejected() throws Exception {
+        assertRejected(request(ADJUST_PATH, token(authKeys.getPrivate(), "account-service", -5)));
+    }
+
+    @Test
+    void tokenForAnotherServiceIsRejected() throws Exception {
+        assertRejected(request(ADJUST_PATH, token(authKeys.getPrivate(), "auth-service", 60)));
+    }
+
+    @Test
+    void tokenSignedWithAnotherKeyIsRejected() throws Exception {
+        assertRejected(request(ADJUST_PATH, token(rsaKeyPair().getPrivate(), "account-service", 60)));
+    }
+
+    // An unset key must not switch the check off.
+    @Test
+    void serviceRoutesAreClosedWithoutAKey() throws Exception {
+        filter = new ServiceTokenFilter(new AppConfig());
+
+        assertRejected(request(ADJUST_PATH, null));
+        assertRejected(request(ADJUST_PATH, token(authKeys.getPrivate(), "account-service", 60)));
+        MockHttpServletRequest balance = request(ADJUST_PATH.replace("/adjust", ""), null);
+        balance.setMethod("GET");
+        assertRejected(balance);
+
+        AtomicBoolean called = new AtomicBoolean();
+        filter.doFilter(request("/api/v1/accounts", null), new MockHttpServletResponse(), (req, res) -> called.set(true));
+        assertTrue(called.get(), "user routes stay open");
+    }
+
+    @Test
+    void currencyBalanceAdjustmentsNeedATokenToo() throws Exception {
+        String path = ADJUST_PATH.replace("/balance/adjust", "/balances/EUR");
+        assertRejected(request(path, null));
+
+        AtomicBoolean called = new AtomicBoolean();
+        filter.doFilter(request(path, token(authKeys.getPrivate(), "account-service", 60)),
+                new MockHttpServletResponse(), (req, res) -> called.set(true));
+        assertTrue(called.get());
+    }
+
+    @Test
+    void userRoutesAreNotChecked() throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+
+        filter.doFilter(request("/api/v1/accounts", null), new MockHttpServletResponse(), (req, res) -> called.set(true));
+
+        assertTrue(called.get());
+    }
+
+    private void assertRejected(MockHttpServletRequest request) throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request, response, (req, res) -> called.set(true));
+
+        assertEquals(401, response.getStatus());
+        assertFalse(called.get(), "the handler must not run");
+    }
+
+    private static MockHttpServletRequest request(String path, String token) {
+        MockHttpServletRequest request = new MockHttpServletRequest("PATCH", path);
+        if (token != null) {
+            request.addHeader("Authorization", "Bearer " + token);
+        }
+        return request;
+    }
+
+    private static String token(PrivateKey key, String audience, long expiresInSeconds) {
+        Instant now = Instant.now();
+        return Jwts.builder()
+                .issuer("transaction-service")
+                .audience().add(audience).and()
+                .issuedAt(Date.from(now.minusSeconds(60)))
+                .expiration(Date.from(now.plusSeconds(expiresInSeconds)))
+                .signWith(key)
+                .compact();
+    }
+
+    private static KeyPair rsaKeyPair() throws Exception {
+        KeyPairGenerator generator = KeyPairGenerator.getInstance("RSA");
+        generator.initialize(2048);
+        return generator.generateKeyPair();
+    }
+}
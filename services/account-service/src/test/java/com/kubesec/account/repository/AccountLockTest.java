@@ -0,0 +1,163 @@
+package com.kubesec.account.repository;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.exception.ConflictException;
+import com.kubesec.account.exception.LockContentionException;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.AdjustBalanceRequest;
+import com.kubesec.account.service.AccountService;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.H2AdvisoryLocks;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DataSourceTransactionManager;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+import org.springframework.transaction.support.TransactionTemplate;
+
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+import java.util.concurrent.CountDownLatch;
+import java.util.concurrent.ExecutionException;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.Future;
+import java.util.concurrent.TimeUnit;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertInstanceOf;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// Runs balance adjustments through the real account lock and queries on H2, each in its
+// own transaction as the @Transactional service method would be. H2 has no advisory
+// locks, so H2AdvisoryLocks provides them with the same transaction scope.
+class AccountLockTest {
+
+    private static final int DEBITS = 50;
+
+    private final UUID tenantId = UUID.randomUUID();
+
+    private AccountRepositoryImpl repository;
+    private AccountService accountService;
+    private TransactionTemplate tx;
+    private UUID accountId;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE TABLE accounts ("
+                + "id UUID PRIMARY KEY, tenant_id UUID NOT NULL, user_id UUID NOT NULL, account_type VARCHAR(20),"
+                + " balance DECIMAL(18, 2) NOT NULL DEFAULT 0, currency VARCHAR(3), status VARCHAR(20),"
+                + " last_activity_at TIMESTAMP WITH TIME ZONE, monthly_statement_enabled BOOLEAN NOT NULL DEFAULT FALSE,"
+                + " max_daily_deposit DECIMAL(18, 2), overdraft_limit DECIMAL(18, 2) NOT NULL DEFAULT 0,"
+                + " created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE)");
+        jdbc.execute("CREATE TABLE account_currency_balances ("
+                + "account_id UUID NOT NULL, currency VARCHAR(3) NOT NULL, balance DECIMAL(18, 2) NOT NULL,"
+                + " PRIMARY KEY (account_id, currency))");
+        jdbc.execute("CREATE TABLE processed_events ("
+                + "transaction_id UUID PRIMARY KEY, processed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW())");
+        H2AdvisoryLocks.install(jdbc);
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new AccountRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+        accountService = AccountService.builder()
+                .withRepository(repository)
+                .withTenantRepository(new InMemoryTenantRepository())
+                .build();
+        tx = new TransactionTemplate(new DataSourceTransactionManager(dataSource));
+
+        TenantContext.set(tenantId);
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Account account = new Account(UUID.randomUUID(), UUID.randomUUID(), "checking", new BigDecimal("25.00"),
+                "USD", "active", now, now);
+        account.setTenantId(tenantId);
+        repository.createAccount(account);
+        accountId = account.getId();
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    // Every debit either lands, is refused for overdrawing, or gives up on the lock;
+    // the balance moves by exactly the debits that landed and never goes negative.
+    @Test
+    void concurrentDebitsNeverOverdrawTheAccount() throws Exception {
+        ExecutorService pool = Executors.newFixedThreadPool(DEBITS);
+        CountDownLatch start = new CountDownLatch(1);
+        List<Future<?>> debits = new ArrayList<>();
+        try {
+            for (int i = 0; i < DEBITS; i++) {
+                debits.add(pool.submit(() -> {
+                    start.await();
+                    return adjust("-1.00");
+                }));
+            }
+            start.countDown();
+
+            int applied = 0;
+            for (Future<?> debit : debits) {
+                try {
+                    debit.get(10, TimeUnit.SECONDS);
+                    applied++;
+                } catch (ExecutionException e) {
+                    assertTrue(e.getCause() instanceof ConflictException
+                            || e.getCause() instanceof LockContentionException, e.getCause().toString());
+                }
+            }
+
+            BigDecimal balance = repository.getAccount(accountId).orElseThrow().getBalance();
+            assertTrue(applied > 0, "some debits land");
+            assertTrue(balance.signum() >= 0, balance.toString());
+            assertEquals(0, new BigDecimal("25.00").subtract(BigDecimal.valueOf(applied)).compareTo(balance),
+                    applied + " debits applied, balance " + balance);
+        } finally {
+            pool.shutdownNow();
+        }
+    }
+
+    @Test
+    void adjustmentGivesUpWhileAnotherTransactionHoldsTheLock() throws Exception {
+        ExecutorService pool = Executors.newSingleThreadExecutor();
+        try {
+            tx.executeWithoutResult(status -> {
+                repository.lockAccount(accountId, Duration.ofSeconds(1));
+                long started = System.nanoTime();
+                ExecutionException e = assertThrows(ExecutionException.class,
+                        () -> pool.submit(() -> adjust("-1.00")).get(5, TimeUnit.SECONDS));
+                assertInstanceOf(LockContentionException.class, e.getCause());
+                assertTrue(Duration.ofNanos(System.nanoTime() - started).toMillis() >= 100,
+                        "waits out the 100ms timeout before giving up");
+            });
+
+            // The lock went with the holder's transaction
+            pool.submit(() -> adjust("-1.00")).get(5, TimeUnit.SECONDS);
+            assertEquals(0, new BigDecimal("24.00").compareTo(
+                    repository.getAccount(accountId).orElseThrow().getBalance()));
+        } finally {
+            pool.shutdownNow();
+        }
+    }
+
+    private Account adjust(String amount) {
+        TenantContext.set(tenantId);
+        try {
+            return tx.execute(status -> accountService.adjustBalance(accountId,
+                    new AdjustBalanceRequest(new BigDecimal(amount), UUID.randomUUID(), "test")));
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,161 @@
+package com.kubesec.account.repository;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.User;
+import com.kubesec.account.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.Optional;
+import java.util.Set;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// Runs the real queries against H2 with users and accounts in two tenants, checking
+// that every lookup and update made as one tenant misses the other's rows.
+class TenantIsolationTest {
+
+    private final UUID tenantA = UUID.randomUUID();
+    private final UUID tenantB = UUID.randomUUID();
+
+    private AccountRepositoryImpl repository;
+    private UUID userB;
+    private UUID accountB;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE TABLE users ("
+                + "id UUID PRIMARY KEY, tenant_id UUID NOT NULL, email VARCHAR(255) NOT NULL, full_name VARCHAR(255),"
+                + " kyc_status VARCHAR(20), nationality VARCHAR(2), country_of_residence VARCHAR(2),"
+                + " preferred_currency VARCHAR(3), national_id VARCHAR(64), credit_score INT,"
+                + " credit_score_updated_at TIMESTAMP WITH TIME ZONE, max_accounts INT NOT NULL DEFAULT 5,"
+                + " total_transferred DECIMAL(18, 2) NOT NULL DEFAULT 0, transaction_count INT NOT NULL DEFAULT 0,"
+                + " created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE)");
+        jdbc.execute("CREATE TABLE accounts ("
+                + "id UUID PRIMARY KEY, tenant_id UUID NOT NULL, user_id UUID NOT NULL, account_type VARCHAR(20),"
+                + " balance DECIMAL(18, 2) NOT NULL DEFAULT 0, currency VARCHAR(3), status VARCHAR(20),"
+                + " last_activity_at TIMESTAMP WITH TIME ZONE, monthly_statement_enabled BOOLEAN NOT NULL DEFAULT FALSE,"
+                + " max_daily_deposit DECIMAL(18, 2), overdraft_limit DECIMAL(18, 2) NOT NULL DEFAULT 0,"
+                + " created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE)");
+        jdbc.execute("CREATE TABLE account_currency_balances ("
+                + "account_id UUID NOT NULL, currency VARCHAR(3) NOT NULL, balance DECIMAL(18, 2) NOT NULL,"
+                + " PRIMARY KEY (account_id, currency))");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new AccountRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+
+        TenantContext.set(tenantB);
+        userB = createUser("bob@globex.example");
+        accountB = createAccount(userB, "250.00");
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void anotherTenantCannotReadUsersOrAccounts() {
+        TenantContext.set(tenantA);
+
+        assertEquals(Optional.empty(), repository.getUser(userB));
+        assertEquals(Optional.empty(), repository.getAccount(accountB));
+        assertEquals(List.of(), repository.listAccountsByUser(userB));
+        assertEquals(0, repository.countAccountsByUser(userB));
+        assertEquals(List.of(), repository.getUsersByCountry("TN"));
+
+        AccountFilter byUser = new AccountFilter();
+        byUser.setUserId(userB);
+        assertEquals(List.of(), repository.listAccounts(byUser));
+        assertEquals(List.of(), repository.listAccounts(new AccountFilter()));
+        assertEquals(0, repository.countAccounts(new AccountFilter()));
+    }
+
+    @Test
+    void anotherTenantCannotChangeUsersOrAccounts() {
+        TenantContext.set(tenantA);
+
+        assertThrows(IllegalStateException.class, () -> repository.adjustBalance(accountB, new BigDecimal("-250.00")));
+        assertThrows(IllegalStateException.class, () -> repository.updateKycStatus(userB, "verified"));
+        assertThrows(IllegalStateException.class, () -> repository.updateCreditScore(userB, 300));
+
+        TenantContext.set(tenantB);
+        assertEquals(0, new BigDecimal("250.00").compareTo(repository.getAccount(accountB).orElseThrow().getBalance()));
+        assertEquals("pending", repository.getUser(userB).orElseThrow().getKycStatus());
+    }
+
+    @Test
+    void eachTenantSeesOnlyItsOwnRows() {
+        TenantContext.set(tenantA);
+        UUID userA = createUser("alice@acme.example");
+        UUID accountA = createAccount(userA, "10.00");
+
+        assertEquals(List.of(accountA), ids(repository.listAccounts(new AccountFilter())));
+        assertEquals(List.of(userA), repository.getUsersByCountry("TN").stream().map(User::getId).toList());
+
+        TenantContext.set(tenantB);
+        assertEquals(List.of(accountB), ids(repository.listAccounts(new AccountFilter())));
+        assertEquals(List.of(userB), repository.getUsersByCountry("TN").stream().map(User::getId).toList());
+        assertTrue(repository.getAccount(accountA).isEmpty());
+    }
+
+    @Test
+    void statementListingSpansTenantsButSkipsClosedAndOptedOutAccounts() {
+        TenantContext.set(tenantA);
+        UUID userA = createUser("alice@acme.example");
+        UUID optedIn = createAccount(userA, "0.00");
+        UUID closed = createAccount(userA, "0.00");
+        createAccount(userA, "0.00");
+        repository.updateMonthlyStatementEnabled(optedIn, true);
+        repository.updateMonthlyStatementEnabled(closed, true);
+        repository.closeAccount(closed);
+
+        TenantContext.set(tenantB);
+        assertThrows(IllegalStateException.class, () -> repository.updateMonthlyStatementEnabled(optedIn, false));
+        repository.updateMonthlyStatementEnabled(accountB, true);
+
+        // The worker runs outside any tenant
+        TenantContext.clear();
+        assertEquals(Set.of(optedIn, accountB), Set.copyOf(ids(repository.listAccountsWithStatementsEnabled())));
+    }
+
+    private UUID createUser(String email) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        User user = new User(UUID.randomUUID(), email, "Test User", "pending", now, now);
+        user.setTenantId(TenantContext.require());
+        user.setCountryOfResidence("TN");
+        user.setMaxAccounts(5);
+        repository.createUser(user);
+        return user.getId();
+    }
+
+    private UUID createAccount(UUID userId, String balance) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Account account = new Account(UUID.randomUUID(), userId, "checking", new BigDecimal(balance), "USD", "active",
+                now, now);
+        account.setTenantId(TenantContext.require());
+        repository.createAccount(account);
+        return account.getId();
+    }
+
+    private static List<UUID> ids(List<Account> accounts) {
+        return accounts.stream().map(Account::getId).toList();
+    }
+}
@@ -0,0 +1,111 @@
+package com.kubesec.account.repository;
+
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.model.User;
+import com.kubesec.account.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+import java.util.concurrent.CountDownLatch;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.Future;
+import java.util.concurrent.TimeUnit;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+// Sends concurrent increments for one user against H2. The update adds to the stored
+// totals in a single statement, so none of them may be lost.
+class UserTransactionStatsTest {
+
+    private static final int INCREMENTS = 50;
+
+    private final UUID tenantId = UUID.randomUUID();
+
+    private AccountRepositoryImpl repository;
+    private UUID userId;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1;LOCK_TIMEOUT=10000");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE TABLE users ("
+                + "id UUID PRIMARY KEY, tenant_id UUID NOT NULL, email VARCHAR(255) NOT NULL, full_name VARCHAR(255),"
+                + " kyc_status VARCHAR(20), nationality VARCHAR(2), country_of_residence VARCHAR(2),"
+                + " preferred_currency VARCHAR(3), national_id VARCHAR(64), credit_score INT,"
+                + " credit_score_updated_at TIMESTAMP WITH TIME ZONE, max_accounts INT NOT NULL DEFAULT 5,"
+                + " total_transferred DECIMAL(18, 2) NOT NULL DEFAULT 0, transaction_count INT NOT NULL DEFAULT 0,"
+                + " created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new AccountRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+
+        TenantContext.set(tenantId);
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        User user = new User(UUID.randomUUID(), "alice@example.com", "Alice", "pending", now, now);
+        user.setTenantId(tenantId);
+        user.setMaxAccounts(5);
+        repository.createUser(user);
+        userId = user.getId();
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void concurrentIncrementsAreAllCounted() throws Exception {
+        ExecutorService pool = Executors.newFixedThreadPool(INCREMENTS);
+        CountDownLatch start = new CountDownLatch(1);
+        List<Future<?>> increments = new ArrayList<>();
+        try {
+            for (int i = 0; i < INCREMENTS; i++) {
+                increments.add(pool.submit(() -> {
+                    start.await();
+                    TenantContext.set(tenantId);
+                    try {
+                        repository.incrementUserTransactionStats(userId, new BigDecimal("12.50"));
+                    } finally {
+                        TenantContext.clear();
+                    }
+                    return null;
+                }));
+            }
+            start.countDown();
+            for (Future<?> increment : increments) {
+                increment.get(10, TimeUnit.SECONDS);
+            }
+        } finally {
+            pool.shutdownNow();
+        }
+
+        User user = repository.getUser(userId).orElseThrow();
+        assertEquals(INCREMENTS, user.getTransactionCount());
+        assertEquals(0, new BigDecimal("625.00").compareTo(user.getTotalTransferred()),
+                user.getTotalTransferred().toString());
+    }
+
+    @Test
+    void incrementsForAnotherTenantLeaveTheUserAlone() {
+        TenantContext.set(UUID.randomUUID());
+        repository.incrementUserTransactionStats(userId, new BigDecimal("12.50"));
+
+        TenantContext.set(tenantId);
+        User user = repository.getUser(userId).orElseThrow();
+        assertEquals(0, user.getTransactionCount());
+        assertEquals(0, BigDecimal.ZERO.compareTo(user.getTotalTransferred()));
+    }
+}
@@ -0,0 +1,196 @@
+package com.kubesec.account.risk;
+
+import com.kubesec.account.client.AuthServiceClient;
+import com.kubesec.account.client.TransactionServiceClient;
+import com.kubesec.account.exception.ResourceNotFoundException;
+import com.kubesec.account.exception.UpstreamException;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.User;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.web.client.ResourceAccessException;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.anyList;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+// Scores one factor at a time against a user who otherwise carries no risk: verified
+// KYC, registered long ago and no failures, so each score is that factor's contribution.
+class RiskScorerTest {
+
+    private static final String AUTH_HEADER = "Bearer auditor";
+
+    private final OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+
+    private InMemoryAccountRepository repository;
+    private AuthServiceClient authClient;
+    private TransactionServiceClient transactionClient;
+    private RiskScorer scorer;
+
+    @BeforeEach
+    void setUp() {
+        TenantContext.set(UUID.randomUUID());
+        repository = new InMemoryAccountRepository();
+        authClient = mock(AuthServiceClient.class);
+        transactionClient = mock(TransactionServiceClient.class);
+        scorer = new RiskScorer(repository, authClient, transactionClient);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void establishedVerifiedUserWithNoFailuresScoresZero() {
+        UUID userId = user("verified", 365);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(new RiskScore(0, "low", List.of()), score);
+    }
+
+    @ParameterizedTest
+    @CsvSource({"1, 5", "3, 15", "6, 30", "20, 30"})
+    void failedLoginsAddFivePointsEachUpToThirty(int failedLogins, int expected) {
+        UUID userId = user("verified", 365);
+        when(authClient.countFailedLogins(anyString(), any(), anyString())).thenReturn(failedLogins);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(expected, score.score());
+        assertEquals(List.of(failedLogins + " failed logins in the last 30 days"), score.factors());
+    }
+
+    @ParameterizedTest
+    @CsvSource({"1, 10", "2, 20", "3, 30", "9, 30"})
+    void failedTransactionsAddTenPointsEachUpToThirty(int failedTransactions, int expected) {
+        UUID userId = user("verified", 365);
+        UUID accountId = account(userId);
+        when(transactionClient.countFailedTransactions(eq(List.of(accountId)), any(), anyString()))
+                .thenReturn(failedTransactions);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(expected, score.score());
+        assertEquals(List.of(failedTransactions + " failed transactions in the last 30 days"), score.factors());
+    }
+
+    @ParameterizedTest
+    @CsvSource({"pending", "rejected"})
+    void unverifiedKycAddsTwentyFivePoints(String kycStatus) {
+        UUID userId = user(kycStatus, 365);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(new RiskScore(25, "low", List.of("kyc not verified")), score);
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            "1, 15, user created less than 30 days ago",
+            "29, 15, user created less than 30 days ago",
+            "31, 5, user created less than 90 days ago",
+            "89, 5, user created less than 90 days ago"
+    })
+    void newerUsersAddPointsByAge(int ageDays, int expected, String factor) {
+        UUID userId = user("verified", ageDays);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(expected, score.score());
+        assertEquals(List.of(factor), score.factors());
+    }
+
+    @Test
+    void usersOlderThanNinetyDaysAddNothingForAge() {
+        UUID userId = user("verified", 91);
+
+        assertEquals(0, scorer.computeRiskScore(userId, AUTH_HEADER).score());
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            // failed logins, failed transactions, kyc, age days, score, level
+            "5, 0, verified, 365, 25, low",
+            "0, 3, verified, 365, 30, medium",
+            "6, 3, verified, 365, 60, high",
+            "1, 1, pending, 60, 45, medium",
+            "20, 9, pending, 1, 100, high"
+    })
+    void factorsAddUpAndSetTheLevel(int failedLogins, int failedTransactions, String kycStatus, int ageDays,
+                                    int expected, String level) {
+        UUID userId = user(kycStatus, ageDays);
+        account(userId);
+        when(authClient.countFailedLogins(anyString(), any(), anyString())).thenReturn(failedLogins);
+        when(transactionClient.countFailedTransactions(anyList(), any(), anyString())).thenReturn(failedTransactions);
+
+        RiskScore score = scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        assertEquals(expected, score.score());
+        assertEquals(level, score.level());
+    }
+
+    @Test
+    void userWithoutAccountsIsNotLookedUpInTransactionService() {
+        UUID userId = user("verified", 365);
+
+        scorer.computeRiskScore(userId, AUTH_HEADER);
+
+        verify(transactionClient, never()).countFailedTransactions(anyList(), any(), anyString());
+    }
+
+    @Test
+    void unreachableServicesFailTheScoreInsteadOfUnderstatingIt() {
+        UUID userId = user("verified", 365);
+        account(userId);
+        when(authClient.countFailedLogins(anyString(), any(), anyString()))
+                .thenThrow(new ResourceAccessException("connect timed out"));
+
+        assertThrows(UpstreamException.class, () -> scorer.computeRiskScore(userId, AUTH_HEADER));
+
+        when(authClient.countFailedLogins(anyString(), any(), anyString())).thenReturn(0);
+        when(transactionClient.countFailedTransactions(anyList(), any(), anyString()))
+                .thenThrow(new ResourceAccessException("connect timed out"));
+
+        assertThrows(UpstreamException.class, () -> scorer.computeRiskScore(userId, AUTH_HEADER));
+    }
+
+    @Test
+    void unknownUserIsNotFound() {
+        assertThrows(ResourceNotFoundException.class, () -> scorer.computeRiskScore(UUID.randomUUID(), AUTH_HEADER));
+    }
+
+    private UUID user(String kycStatus, int ageDays) {
+        OffsetDateTime createdAt = now.minusDays(ageDays);
+        User user = new User(UUID.randomUUID(), "risk@example.com", "Risk", kycStatus, createdAt, createdAt);
+        user.setTenantId(TenantContext.require());
+        repository.createUser(user);
+        return user.getId();
+    }
+
+    private UUID account(UUID userId) {
+        Account account = new Account(UUID.randomUUID(), userId, "checking", BigDecimal.ZERO, "USD", "active", now, now);
+        account.setTenantId(TenantContext.require());
+        repository.createAccount(account);
+        return account.getId();
+    }
+}
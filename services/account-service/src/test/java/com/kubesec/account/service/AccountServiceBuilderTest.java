@@ -0,0 +1,29 @@
+package com.kubesec.account.service;
+
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import org.junit.jupiter.api.Test;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class AccountServiceBuilderTest {
+
+    @Test
+    void validateNamesEveryMissingRequiredDependency() {
+        IllegalStateException e = assertThrows(IllegalStateException.class,
+                () -> AccountService.builder().validate());
+
+        assertEquals("missing required dependencies: repository, tenantRepository", e.getMessage());
+    }
+
+    @Test
+    void buildsWithAllDependencies() {
+        AccountService.Builder builder = AccountService.builder()
+                .withRepository(new InMemoryAccountRepository())
+                .withTenantRepository(new InMemoryTenantRepository());
+
+        assertDoesNotThrow(builder::build);
+    }
+}
@@ -0,0 +1,111 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.BalanceDeductReply;
+import com.kubesec.account.model.dto.BalanceDeductRequest;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.math.BigDecimal;
+import java.nio.charset.StandardCharsets;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// Exercises the request handling behind accounts.balance.deduct. There is no
+// embeddable NATS server for the JVM, so the subscription itself isn't covered.
+class BalanceRpcServerTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private InMemoryAccountRepository repository;
+    private BalanceRpcServer server;
+    private UUID tenantId;
+    private UUID accountId;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        server = new BalanceRpcServer(null, objectMapper, AccountService.builder().withRepository(repository).withTenantRepository(tenants).build());
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        accountId = UUID.randomUUID();
+        Account account = new Account(accountId, UUID.randomUUID(), "checking", new BigDecimal("100.00"),
+                "USD", "active", now, now);
+        account.setTenantId(tenantId);
+        repository.createAccount(account);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void deductsFromTheAccount() throws Exception {
+        BalanceDeductReply reply = deduct(new BalanceDeductRequest(tenantId, accountId, new BigDecimal("30.00"),
+                UUID.randomUUID(), "deposit_rollback"));
+
+        assertTrue(reply.ok());
+        assertEquals(new BigDecimal("70.00"), balance());
+    }
+
+    @Test
+    void repeatedTransactionIsAppliedOnce() throws Exception {
+        UUID transactionId = UUID.randomUUID();
+        deduct(new BalanceDeductRequest(tenantId, accountId, new BigDecimal("30.00"), transactionId, null));
+        BalanceDeductReply reply = deduct(new BalanceDeductRequest(tenantId, accountId, new BigDecimal("30.00"),
+                transactionId, null));
+
+        assertTrue(reply.ok());
+        assertEquals(new BigDecimal("70.00"), balance());
+    }
+
+    @Test
+    void overdraftIsRejectedWithItsCode() throws Exception {
+        BalanceDeductReply reply = deduct(new BalanceDeductRequest(tenantId, accountId, new BigDecimal("100.01"),
+                UUID.randomUUID(), null));
+
+        assertFalse(reply.ok());
+        assertEquals("conflict", reply.code());
+        assertEquals(new BigDecimal("100.00"), balance());
+    }
+
+    @Test
+    void accountsInOtherTenantsAreNotFound() throws Exception {
+        BalanceDeductReply reply = deduct(new BalanceDeductRequest(UUID.randomUUID(), accountId, BigDecimal.ONE,
+                UUID.randomUUID(), null));
+
+        assertFalse(reply.ok());
+        assertEquals("not_found", reply.code());
+    }
+
+    @Test
+    void malformedRequestsAreRejected() throws Exception {
+        assertEquals("invalid_request", server.deduct("not json".getBytes(StandardCharsets.UTF_8)).code());
+        assertEquals("invalid_request", deduct(new BalanceDeductRequest(tenantId, accountId, new BigDecimal("-5"),
+                UUID.randomUUID(), null)).code());
+        assertEquals("invalid_request", deduct(new BalanceDeductRequest(null, accountId, BigDecimal.ONE,
+                UUID.randomUUID(), null)).code());
+    }
+
+    private BalanceDeductReply deduct(BalanceDeductRequest request) throws Exception {
+        return server.deduct(objectMapper.writeValueAsBytes(request));
+    }
+
+    private BigDecimal balance() {
+        TenantContext.set(tenantId);
+        return repository.getAccount(accountId).orElseThrow().getBalance();
+    }
+}
@@ -0,0 +1,153 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.config.AppConfig;
+import com.kubesec.account.credit.CreditBureauClient;
+import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.CreateUserRequest;
+import com.kubesec.account.model.dto.CreditScoreRequestedEvent;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.mockito.ArgumentCaptor;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNotNull;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+
+// Exercises the handling behind user.credit_score_requested with a stubbed bureau.
+// As with BalanceRpcServerTest, the NATS subscription itself isn't covered.
+class CreditScoreWorkerTest {
+
+    private InMemoryAccountRepository repository;
+    private CreditScoreEventPublisher publisher;
+    private AccountService accountService;
+    private UUID tenantId;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        publisher = mock(CreditScoreEventPublisher.class);
+        accountService = AccountService.builder()
+                .withRepository(repository)
+                .withTenantRepository(tenants)
+                .withCreditScorePublisher(publisher)
+                .build();
+        TenantContext.set(tenantId);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void creatingAUserWithANationalIdRequestsAScore() {
+        User user = accountService.createUser(request("123-45-6789"));
+
+        ArgumentCaptor<CreditScoreRequestedEvent> event = ArgumentCaptor.forClass(CreditScoreRequestedEvent.class);
+        verify(publisher).publishCreditScoreRequested(event.capture());
+        assertEquals(user.getId(), event.getValue().userId());
+        assertEquals(tenantId, event.getValue().tenantId());
+        assertEquals("123-45-6789", event.getValue().nationalId());
+    }
+
+    @Test
+    void creatingAUserWithoutANationalIdRequestsNothing() {
+        accountService.createUser(request(null));
+
+        verify(publisher, never()).publishCreditScoreRequested(any());
+    }
+
+    @ParameterizedTest
+    @ValueSource(ints = {300, 580, 720, 850})
+    void scoresInRangeAreStored(int score) {
+        User user = accountService.createUser(request("123-45-6789"));
+
+        assertTrue(worker(nationalId -> score).handle(event(user)));
+
+        User stored = user(user.getId());
+        assertEquals(score, stored.getCreditScore());
+        assertNotNull(stored.getCreditScoreUpdatedAt());
+    }
+
+    @ParameterizedTest
+    @ValueSource(ints = {-1, 0, 299, 851, 999})
+    void scoresOutOfRangeAreRejected(int score) {
+        User user = accountService.createUser(request("123-45-6789"));
+
+        assertFalse(worker(nationalId -> score).handle(event(user)));
+
+        assertNull(user(user.getId()).getCreditScore());
+    }
+
+    @Test
+    void bureauFailureLeavesTheScoreUnset() {
+        User user = accountService.createUser(request("123-45-6789"));
+        CreditBureauClient failing = nationalId -> {
+            throw new IllegalStateException("bureau unavailable");
+        };
+
+        assertFalse(worker(failing).handle(event(user)));
+
+        assertNull(user(user.getId()).getCreditScore());
+    }
+
+    @Test
+    void eventWithoutTenantIsDropped() {
+        User user = accountService.createUser(request("123-45-6789"));
+        CreditScoreRequestedEvent event = new CreditScoreRequestedEvent(user.getId(), null, "123-45-6789",
+                OffsetDateTime.now(ZoneOffset.UTC));
+
+        assertFalse(worker(nationalId -> 700).handle(event));
+    }
+
+    @Test
+    void erasureClearsTheScore() {
+        User user = accountService.createUser(request("123-45-6789"));
+        worker(nationalId -> 700).handle(event(user));
+        TenantContext.set(tenantId);
+
+        repository.eraseUser(user.getId(), UUID.randomUUID().toString());
+
+        User erased = user(user.getId());
+        assertNull(erased.getCreditScore());
+        assertNull(erased.getNationalId());
+    }
+
+    private CreditScoreWorker worker(CreditBureauClient client) {
+        return new CreditScoreWorker(null, new ObjectMapper(), client, accountService, new AppConfig());
+    }
+
+    // The worker clears the tenant once it's done, so reads re-enter it.
+    private User user(UUID userId) {
+        TenantContext.set(tenantId);
+        return repository.getUser(userId).orElseThrow();
+    }
+
+    private static CreditScoreRequestedEvent event(User user) {
+        return new CreditScoreRequestedEvent(user.getId(), user.getTenantId(), user.getNationalId(),
+                OffsetDateTime.now(ZoneOffset.UTC));
+    }
+
+    private static CreateUserRequest request(String nationalId) {
+        return new CreateUserRequest("alice@example.com", "Alice", null, null, null, nationalId);
+    }
+}
@@ -0,0 +1,137 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.StatementRequestedEvent;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import io.nats.client.Connection;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.mockito.ArgumentCaptor;
+
+import java.io.IOException;
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Map;
+import java.util.Set;
+import java.util.UUID;
+import java.util.stream.Collectors;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+
+// Checks the statement period around month, year and leap-day boundaries, and what a
+// run publishes for the opted-in accounts it finds.
+class MonthlyStatementWorkerTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private InMemoryAccountRepository repository;
+    private Connection nats;
+    private MonthlyStatementWorker worker;
+
+    @BeforeEach
+    void setUp() {
+        repository = new InMemoryAccountRepository();
+        nats = mock(Connection.class);
+        worker = new MonthlyStatementWorker(repository, nats, objectMapper);
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            // today, period start, period end (exclusive)
+            "2026-05-01, 2026-04-01, 2026-05-01",
+            "2026-05-15, 2026-04-01, 2026-05-01",
+            "2026-05-31, 2026-04-01, 2026-05-01",
+            // January covers December of the year before
+            "2026-01-01, 2025-12-01, 2026-01-01",
+            "2026-01-31, 2025-12-01, 2026-01-01",
+            "2027-01-01, 2026-12-01, 2027-01-01",
+            // February has 28 or 29 days; the period still ends on March 1st
+            "2024-03-01, 2024-02-01, 2024-03-01",
+            "2026-03-01, 2026-02-01, 2026-03-01",
+            "2100-03-01, 2100-02-01, 2100-03-01",
+            "2024-02-29, 2024-01-01, 2024-02-01",
+            // 31-day months do not spill into the next one
+            "2026-08-01, 2026-07-01, 2026-08-01",
+            "2026-09-01, 2026-08-01, 2026-09-01",
+            "2026-12-31, 2026-11-01, 2026-12-01"
+    })
+    void periodIsTheWholePreviousCalendarMonth(LocalDate today, LocalDate start, LocalDate end) {
+        assertEquals(start, MonthlyStatementWorker.periodStart(today));
+        assertEquals(end, MonthlyStatementWorker.periodEnd(today));
+    }
+
+    @Test
+    void consecutiveRunsLeaveNoGapsOrOverlaps() {
+        LocalDate run = LocalDate.of(2023, 11, 1);
+        for (int i = 0; i < 30; i++) {
+            LocalDate next = run.plusMonths(1);
+            assertEquals(MonthlyStatementWorker.periodEnd(run), MonthlyStatementWorker.periodStart(next),
+                    "run on " + next);
+            run = next;
+        }
+    }
+
+    @Test
+    void runPublishesOneRequestPerOptedInAccount() throws Exception {
+        UUID tenant = UUID.randomUUID();
+        Account optedIn = account(tenant, true, "active");
+        Account otherTenant = account(UUID.randomUUID(), true, "frozen");
+        account(tenant, false, "active");
+        account(tenant, true, "closed");
+        OffsetDateTime now = OffsetDateTime.of(2025, 1, 1, 2, 0, 0, 0, ZoneOffset.UTC);
+
+        worker.requestStatements(now);
+
+        ArgumentCaptor<byte[]> payloads = ArgumentCaptor.forClass(byte[].class);
+        verify(nats, times(2)).publish(eq("statements.monthly_requested"), payloads.capture());
+        Map<UUID, StatementRequestedEvent> events = payloads.getAllValues().stream()
+                .map(this::decode)
+                .collect(Collectors.toMap(StatementRequestedEvent::accountId, e -> e));
+        assertEquals(Set.of(optedIn.getId(), otherTenant.getId()), events.keySet());
+
+        StatementRequestedEvent event = events.get(optedIn.getId());
+        assertEquals(tenant, event.tenantId());
+        assertEquals(optedIn.getUserId(), event.userId());
+        assertEquals(LocalDate.of(2024, 12, 1), event.periodStart());
+        assertEquals(LocalDate.of(2025, 1, 1), event.periodEnd());
+        assertEquals(now.toInstant(), event.requestedAt().toInstant());
+    }
+
+    @Test
+    void runWithNoOptedInAccountsPublishesNothing() {
+        account(UUID.randomUUID(), false, "active");
+
+        worker.requestStatements(OffsetDateTime.now(ZoneOffset.UTC));
+
+        verify(nats, never()).publish(any(String.class), any(byte[].class));
+    }
+
+    private Account account(UUID tenant, boolean statements, String status) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Account account = new Account(UUID.randomUUID(), UUID.randomUUID(), "checking", BigDecimal.ZERO, "USD",
+                status, now, now);
+        account.setTenantId(tenant);
+        account.setMonthlyStatementEnabled(statements);
+        repository.createAccount(account);
+        return account;
+    }
+
+    private StatementRequestedEvent decode(byte[] payload) {
+        try {
+            return objectMapper.readValue(payload, StatementRequestedEvent.class);
+        } catch (IOException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+}
@@ -0,0 +1,164 @@
+package com.kubesec.account.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.dto.TransactionEvent;
+import com.kubesec.account.tenant.TenantContext;
+import com.kubesec.account.testdoubles.InMemoryAccountRepository;
+import com.kubesec.account.testdoubles.InMemoryTenantRepository;
+import io.nats.client.Message;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.math.BigDecimal;
+import java.nio.charset.StandardCharsets;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.doThrow;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+// Feeds the listener JetStream messages the way transaction-service publishes them,
+// one copy per account, and checks what is applied and how each message is settled.
+// As with BalanceRpcServerTest, the subscription itself isn't covered.
+class TransactionEventListenerTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private InMemoryAccountRepository repository;
+    private AccountService accountService;
+    private TransactionEventListener listener;
+    private UUID tenantId;
+    private UUID from;
+    private UUID to;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant("acme", true).id();
+        repository = new InMemoryAccountRepository();
+        accountService = AccountService.builder().withRepository(repository).withTenantRepository(tenants).build();
+        listener = new TransactionEventListener(null, objectMapper, accountService);
+        from = account("100.00");
+        to = account("0.00");
+    }
+
+    @Test
+    void transferIsAppliedOnceFromEitherCopyOrder() throws Exception {
+        TransactionEvent event = transfer("40.00");
+        Message recipientCopy = message(to, event);
+        Message senderCopy = message(from, event);
+
+        listener.onMessage(recipientCopy);
+        assertEquals(new BigDecimal("100.00"), balance(from), "the recipient's copy is not applied");
+
+        listener.onMessage(senderCopy);
+        assertEquals(new BigDecimal("59.60"), balance(from));
+        assertEquals(new BigDecimal("40.00"), balance(to));
+        verify(recipientCopy).ack();
+        verify(senderCopy).ack();
+    }
+
+    @Test
+    void redeliveredCopyIsAckedWithoutApplyingAgain() throws Exception {
+        TransactionEvent event = transfer("40.00");
+        listener.onMessage(message(from, event));
+
+        Message redelivered = message(from, event);
+        listener.onMessage(redelivered);
+
+        verify(redelivered).ack();
+        assertEquals(new BigDecimal("59.60"), balance(from));
+        assertEquals(new BigDecimal("40.00"), balance(to));
+    }
+
+    @Test
+    void depositIsAppliedFromTheRecipientsCopy() throws Exception {
+        TransactionEvent deposit = event(null, to, "25.00", null, "deposit", "completed", tenantId);
+
+        Message copy = message(to, deposit);
+        listener.onMessage(copy);
+
+        verify(copy).ack();
+        assertEquals(new BigDecimal("25.00"), balance(to));
+    }
+
+    @Test
+    void failureIsRedeliveredInsteadOfAcked() throws Exception {
+        AccountService failing = mock(AccountService.class);
+        doThrow(new IllegalStateException("database unavailable")).when(failing).applyTransactionCompleted(any());
+        TransactionEventListener listener = new TransactionEventListener(null, objectMapper, failing);
+
+        Message copy = message(from, transfer("40.00"));
+        listener.onMessage(copy);
+
+        verify(copy).nakWithDelay(TransactionEventListener.RETRY_DELAY);
+        verify(copy, never()).ack();
+    }
+
+    @Test
+    void eventsThatCanNeverApplyAreTerminated() throws Exception {
+        Message garbage = mock(Message.class);
+        when(garbage.getSubject()).thenReturn(TransactionEventListener.SUBJECT_PREFIX + from);
+        when(garbage.getData()).thenReturn("not json".getBytes(StandardCharsets.UTF_8));
+        Message noTenant = message(from, event(from, to, "40.00", null, "transfer", "completed", null));
+
+        listener.onMessage(garbage);
+        listener.onMessage(noTenant);
+
+        verify(garbage).term();
+        verify(noTenant).term();
+        assertEquals(new BigDecimal("100.00"), balance(from));
+    }
+
+    @Test
+    void nonCompletedEventsAreAckedAndIgnored() throws Exception {
+        Message failed = message(from, event(from, to, "40.00", null, "transfer", "failed", tenantId));
+
+        listener.onMessage(failed);
+
+        verify(failed).ack();
+        assertEquals(new BigDecimal("100.00"), balance(from));
+    }
+
+    private TransactionEvent transfer(String amount) {
+        return event(from, to, amount, new BigDecimal("0.40"), "transfer", "completed", tenantId);
+    }
+
+    private static TransactionEvent event(UUID from, UUID to, String amount, BigDecimal fee, String type,
+                                          String status, UUID tenant) {
+        return new TransactionEvent(UUID.randomUUID(), tenant, from, to, new BigDecimal(amount), "USD", type,
+                status, null, fee, OffsetDateTime.now(ZoneOffset.UTC), null, null, null);
+    }
+
+    private Message message(UUID account, TransactionEvent event) throws Exception {
+        Message msg = mock(Message.class);
+        when(msg.getSubject()).thenReturn(TransactionEventListener.SUBJECT_PREFIX + account);
+        when(msg.getData()).thenReturn(objectMapper.writeValueAsBytes(event));
+        return msg;
+    }
+
+    private UUID account(String balance) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Account account = new Account(UUID.randomUUID(), UUID.randomUUID(), "checking", new BigDecimal(balance),
+                "USD", "active", now, now);
+        account.setTenantId(tenantId);
+        repository.createAccount(account);
+        return account.getId();
+    }
+
+    private BigDecimal balance(UUID accountId) {
+        TenantContext.set(tenantId);
+        try {
+            return repository.getAccount(accountId).orElseThrow().getBalance();
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,45 @@
+package com.kubesec.account.testdoubles;
+
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.transaction.support.TransactionSynchronization;
+import org.springframework.transaction.support.TransactionSynchronizationManager;
+
+import java.util.Map;
+import java.util.concurrent.ConcurrentHashMap;
+
+// Stands in for Postgres's hashtext and pg_try_advisory_xact_lock on H2. A lock is held
+// by the thread that took it until its Spring-managed transaction completes, which is
+// when Postgres would release it. Public so H2 can call the functions by reflection.
+public final class H2AdvisoryLocks {
+
+    private static final Map<Integer, Thread> HOLDERS = new ConcurrentHashMap<>();
+
+    private H2AdvisoryLocks() {
+    }
+
+    public static void install(JdbcTemplate jdbc) {
+        String name = H2AdvisoryLocks.class.getName();
+        jdbc.execute("CREATE ALIAS hashtext FOR \"" + name + ".hashText\"");
+        jdbc.execute("CREATE ALIAS pg_try_advisory_xact_lock FOR \"" + name + ".tryLock\"");
+    }
+
+    public static int hashText(String text) {
+        return text.hashCode();
+    }
+
+    // Like Postgres, a holder taking its own lock again succeeds.
+    public static boolean tryLock(int key) {
+        Thread current = Thread.currentThread();
+        Thread holder = HOLDERS.putIfAbsent(key, current);
+        if (holder != null) {
+            return holder == current;
+        }
+        TransactionSynchronizationManager.registerSynchronization(new TransactionSynchronization() {
+            @Override
+            public void afterCompletion(int status) {
+                HOLDERS.remove(key, current);
+            }
+        });
+        return true;
+    }
+}
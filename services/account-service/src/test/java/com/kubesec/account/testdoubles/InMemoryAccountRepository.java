@@ -0,0 +1,481 @@
+package com.kubesec.account.testdoubles;
+
+import com.kubesec.account.exception.CurrencyImmutableException;
+import com.kubesec.account.exception.NonZeroBalanceException;
+import com.kubesec.account.model.Account;
+import com.kubesec.account.model.AccountBalance;
+import com.kubesec.account.model.AccountFilter;
+import com.kubesec.account.model.BalanceSnapshot;
+import com.kubesec.account.model.Erasure;
+import com.kubesec.account.model.LinkedAccount;
+import com.kubesec.account.model.User;
+import com.kubesec.account.model.dto.UpdateUserRequest;
+import com.kubesec.account.repository.AccountRepository;
+import com.kubesec.account.tenant.TenantContext;
+
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Comparator;
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+import java.util.Set;
+import java.util.TreeMap;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+import java.util.concurrent.CopyOnWriteArrayList;
+import java.util.function.Consumer;
+import java.util.function.UnaryOperator;
+import java.util.stream.Stream;
+
+// In-memory AccountRepository for handler tests that run without PostgreSQL.
+// Entities are copied on the way in and out so callers can't mutate stored rows,
+// and updates run inside ConcurrentHashMap.compute so each one is atomic.
+public class InMemoryAccountRepository implements AccountRepository {
+
+    private final Map<UUID, User> users = new ConcurrentHashMap<>();
+    private final Map<UUID, Account> accounts = new ConcurrentHashMap<>();
+    private final Set<UUID> processedEvents = ConcurrentHashMap.newKeySet();
+    private final List<ErasureLogEntry> erasureLog = new CopyOnWriteArrayList<>();
+    private final Map<UUID, LinkedAccount> linkedAccounts = new ConcurrentHashMap<>();
+    private final List<StoredSnapshot> snapshots = new CopyOnWriteArrayList<>();
+
+    // A gdpr_erasure_log row.
+    public record ErasureLogEntry(UUID tenantId, String userIdHash, String requestedBy) {}
+
+    private record StoredSnapshot(UUID tenantId, BalanceSnapshot snapshot) {}
+
+    // --- Users ---
+
+    @Override
+    public void createUser(User user) {
+        users.put(user.getId(), copy(user));
+    }
+
+    @Override
+    public Optional<User> getUser(UUID id) {
+        return Optional.ofNullable(users.get(id)).filter(this::inTenant).map(InMemoryAccountRepository::copy);
+    }
+
+    @Override
+    public List<User> getUsersByCountry(String country) {
+        return users.values().stream()
+                .filter(u -> inTenant(u) && country.equals(u.getCountryOfResidence()))
+                .sorted(Comparator.comparing(User::getCreatedAt))
+                .map(InMemoryAccountRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public Optional<User> updateUser(UUID userId, UpdateUserRequest request) {
+        boolean[] found = new boolean[1];
+        users.computeIfPresent(userId, (id, u) -> {
+            if (inTenant(u) && u.getDeletedAt() == null) {
+                if (request.fullName() != null) {
+                    u.setFullName(request.fullName());
+                }
+                u.setUpdatedAt(now());
+                found[0] = true;
+            }
+            return u;
+        });
+        return found[0] ? getUser(userId) : Optional.empty();
+    }
+
+    @Override
+    public void updatePreferredCurrency(UUID userId, String currency) {
+        updateUser(userId, u -> u.setPreferredCurrency(currency));
+    }
+
+    @Override
+    public void updateKycStatus(UUID userId, String kycStatus) {
+        updateUser(userId, u -> u.setKycStatus(kycStatus));
+    }
+
+    @Override
+    public void eraseUser(UUID userId, String requestedBy) {
+        updateUser(userId, u -> {
+            u.setEmail(Erasure.anonymizedEmail());
+            u.setFullName(Erasure.ERASED_NAME);
+            u.setNationality(null);
+            u.setCountryOfResidence(null);
+            u.setNationalId(null);
+            u.setCreditScore(null);
+            u.setCreditScoreUpdatedAt(null);
+            u.setDeletedAt(now());
+        });
+        tenantAccounts().filter(a -> userId.equals(a.getUserId()))
+                .forEach(a -> updateAccount(a.getId(), acct -> acct.setStatus("closed")));
+        erasureLog.add(new ErasureLogEntry(TenantContext.require(), Erasure.hashUserId(userId), requestedBy));
+    }
+
+    public List<ErasureLogEntry> erasureLog() {
+        return List.copyOf(erasureLog);
+    }
+
+    @Override
+    public void updateCreditScore(UUID userId, int score) {
+        updateUser(userId, u -> {
+            u.setCreditScore(score);
+            u.setCreditScoreUpdatedAt(now());
+        });
+    }
+
+    @Override
+    public void incrementUserTransactionStats(UUID userId, BigDecimal amount) {
+        users.computeIfPresent(userId, (id, u) -> {
+            if (inTenant(u)) {
+                u.setTotalTransferred(u.getTotalTransferred().add(amount));
+                u.setTransactionCount(u.getTransactionCount() + 1);
+                u.setUpdatedAt(now());
+            }
+            return u;
+        });
+    }
+
+    @Override
+    public boolean markEventProcessed(UUID transactionId) {
+        return processedEvents.add(transactionId);
+    }
+
+    // --- Accounts ---
+
+    @Override
+    public void createAccount(Account account) {
+        accounts.put(account.getId(), copy(account));
+    }
+
+    @Override
+    public Optional<Account> getAccount(UUID id) {
+        return Optional.ofNullable(accounts.get(id)).filter(this::inTenant).map(InMemoryAccountRepository::copy);
+    }
+
+    @Override
+    public Optional<AccountBalance> getAccountBalance(UUID id) {
+        return Optional.ofNullable(accounts.get(id)).filter(this::inTenant)
+                .map(a -> new AccountBalance(a.getBalance(), a.getCurrency(), a.getOverdraftLimit(),
+                        new TreeMap<>(a.getCurrencyBalances())));
+    }
+
+    @Override
+    public int countAccountsByUser(UUID userId) {
+        return (int) tenantAccounts()
+                .filter(a -> userId.equals(a.getUserId()) && !"closed".equals(a.getStatus()))
+                .count();
+    }
+
+    @Override
+    public List<Account> listAccountsByUser(UUID userId) {
+        return tenantAccounts()
+                .filter(a -> userId.equals(a.getUserId()))
+                .sorted(Comparator.comparing(Account::getCreatedAt))
+                .map(InMemoryAccountRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public List<Account> listAccounts(AccountFilter filter) {
+        Stream<Account> matching = tenantAccounts()
+                .filter(a -> matches(a, filter))
+                .sorted(Comparator.comparing(Account::getCreatedAt).reversed());
+        if (filter.getOffset() > 0) {
+            matching = matching.skip(filter.getOffset());
+        }
+        if (filter.getLimit() > 0) {
+            matching = matching.limit(filter.getLimit());
+        }
+        return matching.map(InMemoryAccountRepository::copy).toList();
+    }
+
+    @Override
+    public int countAccounts(AccountFilter filter) {
+        return (int) tenantAccounts().filter(a -> matches(a, filter)).count();
+    }
+
+    // Each adjustment is applied atomically in the map, and there is no surrounding
+    // database transaction to hold an advisory lock for.
+    @Override
+    public void lockAccount(UUID accountId, Duration timeout) {
+    }
+
+    @Override
+    public void adjustBalance(UUID accountId, BigDecimal delta) {
+        updateAccount(accountId, a -> {
+            a.setBalance(a.getBalance().add(delta));
+            a.setLastActivityAt(now());
+        });
+    }
+
+    @Override
+    public boolean adjustBalanceWithinOverdraft(UUID accountId, BigDecimal delta) {
+        boolean[] applied = new boolean[1];
+        updateAccount(accountId, a -> {
+            BigDecimal balance = a.getBalance().add(delta);
+            if (balance.compareTo(a.getOverdraftLimit().negate()) >= 0) {
+                a.setBalance(balance);
+                a.setLastActivityAt(now());
+                applied[0] = true;
+            }
+        });
+        return applied[0];
+    }
+
+    @Override
+    public void adjustCurrencyBalance(UUID accountId, String currency, BigDecimal delta) {
+        updateAccount(accountId, a -> {
+            a.getCurrencyBalances().merge(currency, delta, BigDecimal::add);
+            a.setLastActivityAt(now());
+        });
+    }
+
+    // There is no endpoint for overdraft limits yet; tests set them directly.
+    public void setOverdraftLimit(UUID accountId, BigDecimal limit) {
+        accounts.get(accountId).setOverdraftLimit(limit);
+    }
+
+    // Dormancy is measured against the clock, so tests move an account's history into the past.
+    public void setLastActivityAt(UUID accountId, OffsetDateTime lastActivityAt) {
+        accounts.get(accountId).setLastActivityAt(lastActivityAt);
+    }
+
+    public void setCreatedAt(UUID accountId, OffsetDateTime createdAt) {
+        accounts.get(accountId).setCreatedAt(createdAt);
+    }
+
+    // Mirrors trg_accounts_currency_immutable.
+    @Override
+    public void updateAccountCurrency(UUID accountId, String currency) {
+        updateAccount(accountId, a -> {
+            if (!a.getCurrency().equals(currency) && a.getLastActivityAt() != null) {
+                throw new CurrencyImmutableException("account currency cannot change after its first transaction");
+            }
+            a.setCurrency(currency);
+        });
+    }
+
+    @Override
+    public void updateMonthlyStatementEnabled(UUID accountId, boolean enabled) {
+        updateAccount(accountId, a -> a.setMonthlyStatementEnabled(enabled));
+    }
+
+    @Override
+    public void updateMaxDailyDeposit(UUID accountId, BigDecimal limit) {
+        updateAccount(accountId, a -> a.setMaxDailyDeposit(limit));
+    }
+
+    @Override
+    public void closeAccount(UUID accountId) {
+        updateAccount(accountId, a -> {
+            if (a.getBalance().signum() != 0) {
+                throw new NonZeroBalanceException();
+            }
+            a.setStatus("closed");
+        });
+    }
+
+    @Override
+    public List<Account> listAccountsWithStatementsEnabled() {
+        return accounts.values().stream()
+                .filter(a -> a.isMonthlyStatementEnabled() && !"closed".equals(a.getStatus()))
+                .sorted(Comparator.comparing(Account::getTenantId).thenComparing(Account::getId))
+                .map(InMemoryAccountRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public List<Account> listDormantAccounts(Duration dormantFor) {
+        OffsetDateTime cutoff = now().minus(dormantFor);
+        return tenantAccounts()
+                .filter(a -> "active".equals(a.getStatus()) && lastActivity(a).isBefore(cutoff))
+                .sorted(Comparator.comparing(InMemoryAccountRepository::lastActivity))
+                .map(InMemoryAccountRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public int freezeDormantAccounts(Duration dormantFor) {
+        OffsetDateTime cutoff = now().minus(dormantFor);
+        int[] frozen = new int[1];
+        accounts.replaceAll((id, a) -> {
+            if ("active".equals(a.getStatus()) && lastActivity(a).isBefore(cutoff)) {
+                a.setStatus("frozen");
+                a.setUpdatedAt(now());
+                frozen[0]++;
+            }
+            return a;
+        });
+        return frozen[0];
+    }
+
+    // --- Balance snapshots ---
+
+    @Override
+    public synchronized int snapshotActiveBalances(LocalDate snapshotDate) {
+        int inserted = 0;
+        for (Account account : accounts.values()) {
+            boolean taken = snapshots.stream().anyMatch(s -> s.snapshot().accountId().equals(account.getId())
+                    && s.snapshot().snapshotDate().equals(snapshotDate));
+            if ("active".equals(account.getStatus()) && !taken) {
+                snapshots.add(new StoredSnapshot(account.getTenantId(),
+                        new BalanceSnapshot(account.getId(), account.getBalance(), snapshotDate)));
+                inserted++;
+            }
+        }
+        return inserted;
+    }
+
+    @Override
+    public Optional<BalanceSnapshot> getBalanceSnapshotAtOrBefore(UUID accountId, LocalDate date) {
+        return snapshots.stream()
+                .filter(s -> TenantContext.require().equals(s.tenantId()))
+                .map(StoredSnapshot::snapshot)
+                .filter(s -> s.accountId().equals(accountId) && !s.snapshotDate().isAfter(date))
+                .max(Comparator.comparing(BalanceSnapshot::snapshotDate));
+    }
+
+    // --- Linked accounts ---
+
+    @Override
+    public void createLinkedAccount(LinkedAccount linkedAccount) {
+        linkedAccounts.put(linkedAccount.id(), linkedAccount);
+    }
+
+    @Override
+    public Optional<LinkedAccount> getLinkedAccount(UUID id) {
+        return Optional.ofNullable(linkedAccounts.get(id)).filter(this::inTenant);
+    }
+
+    @Override
+    public List<LinkedAccount> listLinkedAccounts(UUID userId) {
+        return linkedAccounts.values().stream()
+                .filter(l -> inTenant(l) && userId.equals(l.userId()))
+                .sorted(Comparator.comparing(LinkedAccount::createdAt))
+                .toList();
+    }
+
+    @Override
+    public void setLinkedAccountMicroDeposits(UUID id, BigDecimal amount1, BigDecimal amount2) {
+        updateLinkedAccount(id, l -> new LinkedAccount(l.id(), l.tenantId(), l.userId(), l.routingNumber(),
+                l.accountNumberHash(), l.accountNumberLast4(), l.bankName(), l.status(), amount1, amount2,
+                l.verificationAttempts(), l.createdAt(), now()));
+    }
+
+    @Override
+    public void updateLinkedAccountVerification(UUID id, String status, int verificationAttempts) {
+        updateLinkedAccount(id, l -> new LinkedAccount(l.id(), l.tenantId(), l.userId(), l.routingNumber(),
+                l.accountNumberHash(), l.accountNumberLast4(), l.bankName(), status, l.microDeposit1(),
+                l.microDeposit2(), verificationAttempts, l.createdAt(), now()));
+    }
+
+    private void updateLinkedAccount(UUID id, UnaryOperator<LinkedAccount> update) {
+        boolean[] found = new boolean[1];
+        linkedAccounts.computeIfPresent(id, (key, l) -> {
+            if (!inTenant(l)) {
+                return l;
+            }
+            found[0] = true;
+            return update.apply(l);
+        });
+        if (!found[0]) {
+            throw new IllegalStateException("linked account " + id + " not found");
+        }
+    }
+
+    private void updateUser(UUID userId, Consumer<User> update) {
+        boolean[] found = new boolean[1];
+        users.computeIfPresent(userId, (id, u) -> {
+            if (inTenant(u)) {
+                update.accept(u);
+                u.setUpdatedAt(now());
+                found[0] = true;
+            }
+            return u;
+        });
+        if (!found[0]) {
+            throw new IllegalStateException("user " + userId + " not found");
+        }
+    }
+
+    private void updateAccount(UUID accountId, Consumer<Account> update) {
+        boolean[] found = new boolean[1];
+        accounts.computeIfPresent(accountId, (id, a) -> {
+            if (inTenant(a)) {
+                update.accept(a);
+                a.setUpdatedAt(now());
+                found[0] = true;
+            }
+            return a;
+        });
+        if (!found[0]) {
+            throw new IllegalStateException("account " + accountId + " not found");
+        }
+    }
+
+    private Stream<Account> tenantAccounts() {
+        return accounts.values().stream().filter(this::inTenant);
+    }
+
+    private boolean inTenant(User user) {
+        return TenantContext.require().equals(user.getTenantId());
+    }
+
+    private boolean inTenant(Account account) {
+        return TenantContext.require().equals(account.getTenantId());
+    }
+
+    private boolean inTenant(LinkedAccount linkedAccount) {
+        return TenantContext.require().equals(linkedAccount.tenantId());
+    }
+
+    private static boolean matches(Account account, AccountFilter filter) {
+        if (filter.getUserId() != null && !filter.getUserId().equals(account.getUserId())) {
+            return false;
+        }
+        if (filter.getStatus() != null && !filter.getStatus().isEmpty() && !filter.getStatus().equals(account.getStatus())) {
+            return false;
+        }
+        return filter.getAccountType() == null || filter.getAccountType().isEmpty()
+                || filter.getAccountType().equals(account.getAccountType());
+    }
+
+    private static OffsetDateTime lastActivity(Account account) {
+        return account.getLastActivityAt() != null ? account.getLastActivityAt() : account.getCreatedAt();
+    }
+
+    private static OffsetDateTime now() {
+        return OffsetDateTime.now(ZoneOffset.UTC);
+    }
+
+    private static User copy(User user) {
+        User copy = new User(user.getId(), user.getEmail(), user.getFullName(), user.getKycStatus(),
+                user.getCreatedAt(), user.getUpdatedAt());
+        copy.setTenantId(user.getTenantId());
+        copy.setNationality(user.getNationality());
+        copy.setCountryOfResidence(user.getCountryOfResidence());
+        copy.setPreferredCurrency(user.getPreferredCurrency());
+        copy.setNationalId(user.getNationalId());
+        copy.setCreditScore(user.getCreditScore());
+        copy.setCreditScoreUpdatedAt(user.getCreditScoreUpdatedAt());
+        copy.setMaxAccounts(user.getMaxAccounts());
+        copy.setTotalTransferred(user.getTotalTransferred());
+        copy.setTransactionCount(user.getTransactionCount());
+        copy.setDeletedAt(user.getDeletedAt());
+        return copy;
+    }
+
+    private static Account copy(Account account) {
+        Account copy = new Account(account.getId(), account.getUserId(), account.getAccountType(),
+                account.getBalance(), account.getCurrency(), account.getStatus(),
+                account.getCreatedAt(), account.getUpdatedAt());
+        copy.setTenantId(account.getTenantId());
+        copy.setLastActivityAt(account.getLastActivityAt());
+        copy.setMonthlyStatementEnabled(account.isMonthlyStatementEnabled());
+        copy.setMaxDailyDeposit(account.getMaxDailyDeposit());
+        copy.setOverdraftLimit(account.getOverdraftLimit());
+        copy.setCurrencyBalances(new TreeMap<>(account.getCurrencyBalances()));
+        return copy;
+    }
+}
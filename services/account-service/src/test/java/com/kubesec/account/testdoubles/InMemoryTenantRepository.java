@@ -0,0 +1,34 @@
+package com.kubesec.account.testdoubles;
+
+import com.kubesec.account.model.Tenant;
+import com.kubesec.account.repository.TenantRepository;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Comparator;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+
+public class InMemoryTenantRepository implements TenantRepository {
+
+    private final Map<UUID, Tenant> tenants = new ConcurrentHashMap<>();
+
+    public Tenant addTenant(String name, boolean active) {
+        Tenant tenant = new Tenant(UUID.randomUUID(), name, active, OffsetDateTime.now(ZoneOffset.UTC));
+        tenants.put(tenant.id(), tenant);
+        return tenant;
+    }
+
+    @Override
+    public boolean isActive(UUID tenantId) {
+        Tenant tenant = tenants.get(tenantId);
+        return tenant != null && tenant.active();
+    }
+
+    @Override
+    public List<Tenant> listTenants() {
+        return tenants.values().stream().sorted(Comparator.comparing(Tenant::name)).toList();
+    }
+}
@@ -0,0 +1,25 @@
+package com.kubesec.account.validation;
+
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.NullSource;
+import org.junit.jupiter.params.provider.ValueSource;
+
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class CountryCodesTest {
+
+    @ParameterizedTest
+    @ValueSource(strings = {"US", "GB", "DE", "TN", "JP", "AX", "ZW"})
+    void acceptsIso3166Alpha2Codes(String code) {
+        assertTrue(CountryCodes.isValid(code));
+    }
+
+    // Lowercase, alpha-3, numeric, user-assigned (XX, ZZ) and reserved-only (UK, EU) codes
+    @ParameterizedTest
+    @NullSource
+    @ValueSource(strings = {"", "us", "Us", "USA", "840", "U", "XX", "ZZ", "UK", "EU", " US"})
+    void rejectsAnythingElse(String code) {
+        assertFalse(CountryCodes.isValid(code));
+    }
+}
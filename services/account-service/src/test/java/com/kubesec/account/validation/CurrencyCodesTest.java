@@ -0,0 +1,24 @@
+package com.kubesec.account.validation;
+
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.NullSource;
+import org.junit.jupiter.params.provider.ValueSource;
+
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class CurrencyCodesTest {
+
+    @ParameterizedTest
+    @ValueSource(strings = {"USD", "EUR", "GBP", "JPY", "TND", "CHF"})
+    void acceptsIso4217Codes(String code) {
+        assertTrue(CurrencyCodes.isValid(code));
+    }
+
+    @ParameterizedTest
+    @NullSource
+    @ValueSource(strings = {"", "usd", "US", "USDT", "XYZ", "ABC"})
+    void rejectsUnknownCodes(String code) {
+        assertFalse(CurrencyCodes.isValid(code));
+    }
+}
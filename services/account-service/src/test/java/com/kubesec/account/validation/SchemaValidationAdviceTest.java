@@ -0,0 +1,86 @@
+package com.kubesec.account.validation;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.account.exception.GlobalExceptionHandler;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.bind.annotation.PostMapping;
+import org.springframework.web.bind.annotation.RequestBody;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.Map;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+class SchemaValidationAdviceTest {
+
+    private final EchoController controller = new EchoController();
+    private MockMvc mockMvc;
+
+    @BeforeEach
+    void setUp() {
+        ObjectMapper objectMapper = new ObjectMapper();
+        mockMvc = MockMvcBuilders.standaloneSetup(controller)
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .build();
+    }
+
+    @Test
+    void acceptedBodyReachesTheHandlerUnchanged() throws Exception {
+        mockMvc.perform(post("/currency").contentType(MediaType.APPLICATION_JSON).content("{\"currency\":\"EUR\"}"))
+                .andExpect(status().isOk());
+
+        assertEquals(Map.of("currency", "EUR"), controller.received);
+    }
+
+    @Test
+    void rejectedBodyIsA422ListingTheViolations() throws Exception {
+        mockMvc.perform(post("/currency").contentType(MediaType.APPLICATION_JSON).content("{\"currency\":\"eur\"}"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.code").value("schema_violation"))
+                .andExpect(jsonPath("$.violations.length()").value(1));
+
+        assertNull(controller.received, "the handler must not run");
+    }
+
+    @Test
+    void malformedJsonIsA422() throws Exception {
+        mockMvc.perform(post("/currency").contentType(MediaType.APPLICATION_JSON).content("{\"currency\":"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.violations[0]").value("request body is not valid JSON"));
+
+        assertNull(controller.received);
+    }
+
+    @Test
+    void unannotatedBodiesAreNotValidated() throws Exception {
+        mockMvc.perform(post("/raw").contentType(MediaType.APPLICATION_JSON).content("{\"currency\":\"eur\"}"))
+                .andExpect(status().isOk());
+
+        assertEquals(Map.of("currency", "eur"), controller.received);
+    }
+
+    @RestController
+    static class EchoController {
+
+        Map<String, Object> received;
+
+        @PostMapping("/currency")
+        void currency(@ValidatedBody("update-currency") @RequestBody Map<String, Object> body) {
+            received = body;
+        }
+
+        @PostMapping("/raw")
+        void raw(@RequestBody Map<String, Object> body) {
+            received = body;
+        }
+    }
+}
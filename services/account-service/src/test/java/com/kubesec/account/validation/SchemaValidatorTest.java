@@ -0,0 +1,117 @@
+package com.kubesec.account.validation;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.node.TextNode;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.core.io.Resource;
+import org.springframework.core.io.support.PathMatchingResourcePatternResolver;
+
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class SchemaValidatorTest {
+
+    private static final String USER_ID = "6f1c1b7e-2f4a-4c43-9a57-0d9b1c7a5e21";
+
+    private final ObjectMapper objectMapper = new ObjectMapper();
+    private final SchemaValidator validator = new SchemaValidator();
+
+    @Test
+    void everySchemaLoadsAndRejectsNonObjectBodies() throws Exception {
+        Resource[] schemas = new PathMatchingResourcePatternResolver().getResources("classpath:/schemas/*.json");
+        assertTrue(schemas.length > 0);
+
+        for (Resource schema : schemas) {
+            String name = schema.getFilename().replace(".json", "");
+            assertFalse(validator.validate(name, TextNode.valueOf("body")).isEmpty(), name);
+        }
+    }
+
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "create-account | {\"user_id\":\"" + USER_ID + "\",\"account_type\":\"checking\"}",
+            "create-account | {\"user_id\":\"" + USER_ID + "\",\"account_type\":\"savings\",\"currency\":\"EUR\"}",
+            // Properties the schema does not know about are left to the controller
+            "create-account | {\"user_id\":\"" + USER_ID + "\",\"account_type\":\"checking\",\"nickname\":\"rent\"}",
+            "create-user | {\"email\":\"ada@example.com\",\"full_name\":\"Ada Lovelace\"}",
+            "create-user | {\"email\":\"ada@example.com\",\"full_name\":\"Ada\",\"nationality\":\"GB\","
+                    + "\"country_of_residence\":\"TN\",\"preferred_currency\":\"GBP\"}",
+            "adjust-balance | {\"amount\":-12.5}",
+            "adjust-balance | {\"amount\":40,\"transaction_id\":null,\"reason\":\"deposit\"}",
+            "adjust-balance | {\"amount\":40,\"transaction_id\":\"" + USER_ID + "\"}",
+            "adjust-currency-balance | {\"amount\":3}",
+            "update-balance | {\"delta\":\"-10.25\",\"reason\":\"correction\"}",
+            "update-balance | {\"delta\":\"500\",\"reason\":\"cash_deposit\",\"reference\":\"teller-7\"}",
+            "update-currency | {\"currency\":\"USD\"}",
+            "update-deposit-limit | {\"max_daily_deposit\":2500}",
+            "update-deposit-limit | {\"max_daily_deposit\":null}",
+            "update-kyc-status | {\"kyc_status\":\"verified\"}",
+            "update-statement-preferences | {\"monthly_statement_enabled\":false}",
+            "update-user | {}",
+            "update-user | {\"full_name\":\"Ada King\"}",
+            "create-linked-account | {\"routing_number\":\"021000021\",\"account_number\":\"1234\","
+                    + "\"bank_name\":\"Chase\"}",
+            "verify-linked-account | {\"micro_deposit_1\":\"0.32\",\"micro_deposit_2\":\"0.05\"}"
+    })
+    void validBodiesPass(String schema, String body) throws Exception {
+        assertEquals(List.of(), validator.validate(schema, objectMapper.readTree(body)));
+    }
+
+    // field is the property each rejection must name
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "create-account | {\"account_type\":\"checking\"} | user_id",
+            "create-account | {\"user_id\":\"not-a-uuid\",\"account_type\":\"checking\"} | user_id",
+            "create-account | {\"user_id\":\"" + USER_ID + "\",\"account_type\":\"brokerage\"} | account_type",
+            "create-account | {\"user_id\":\"" + USER_ID + "\",\"account_type\":\"checking\","
+                    + "\"currency\":\"usd\"} | currency",
+            "create-user | {\"email\":\"ada@example.com\"} | full_name",
+            "create-user | {\"email\":\"not-an-email\",\"full_name\":\"Ada\"} | email",
+            "create-user | {\"email\":\"ada@example.com\",\"full_name\":\"\"} | full_name",
+            "create-user | {\"email\":\"ada@example.com\",\"full_name\":\"Ada\",\"nationality\":\"gb\"} | nationality",
+            "create-user | {\"email\":\"ada@example.com\",\"full_name\":\"Ada\","
+                    + "\"preferred_currency\":\"GB\"} | preferred_currency",
+            "adjust-balance | {} | amount",
+            "adjust-balance | {\"amount\":\"40\"} | amount",
+            "adjust-balance | {\"amount\":40,\"transaction_id\":\"txn-1\"} | transaction_id",
+            "adjust-currency-balance | {\"amount\":null} | amount",
+            "update-balance | {\"delta\":\"1.234\",\"reason\":\"correction\"} | delta",
+            "update-balance | {\"delta\":10,\"reason\":\"correction\"} | delta",
+            "update-balance | {\"delta\":\"10\",\"reason\":\"gift\"} | reason",
+            "update-balance | {\"delta\":\"10\"} | reason",
+            "update-currency | {\"currency\":\"usd\"} | currency",
+            "update-deposit-limit | {\"max_daily_deposit\":0} | max_daily_deposit",
+            "update-deposit-limit | {} | max_daily_deposit",
+            "update-kyc-status | {\"kyc_status\":\"approved\"} | kyc_status",
+            "update-statement-preferences | {\"monthly_statement_enabled\":\"yes\"} | monthly_statement_enabled",
+            "update-user | {\"full_name\":\"\"} | full_name",
+            "create-linked-account | {\"routing_number\":\"02100002\",\"account_number\":\"1234\","
+                    + "\"bank_name\":\"Chase\"} | routing_number",
+            "create-linked-account | {\"routing_number\":\"021000021\",\"account_number\":\"12a4\","
+                    + "\"bank_name\":\"Chase\"} | account_number",
+            "verify-linked-account | {\"micro_deposit_1\":\"1.00\",\"micro_deposit_2\":\"0.05\"} | micro_deposit_1",
+            "verify-linked-account | {\"micro_deposit_1\":\"0.32\",\"micro_deposit_2\":\"0.5\"} | micro_deposit_2"
+    })
+    void invalidBodiesNameTheOffendingField(String schema, String body, String field) throws Exception {
+        List<String> violations = validator.validate(schema, objectMapper.readTree(body));
+
+        assertEquals(1, violations.size(), violations.toString());
+        assertTrue(violations.get(0).contains(field), violations.get(0));
+    }
+
+    @Test
+    void everyViolationIsReportedInDocumentOrder() throws Exception {
+        List<String> violations = validator.validate("create-user",
+                objectMapper.readTree("{\"email\":\"not-an-email\",\"nationality\":\"gb\"}"));
+
+        assertEquals(3, violations.size(), violations.toString());
+        assertTrue(violations.get(0).contains("full_name"), violations.get(0));
+        assertTrue(violations.get(1).contains("email"), violations.get(1));
+        assertTrue(violations.get(2).contains("nationality"), violations.get(2));
+    }
+}
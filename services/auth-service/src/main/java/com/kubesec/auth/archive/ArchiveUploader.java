@@ -0,0 +1,9 @@
+package com.kubesec.auth.archive;
+
+import java.io.IOException;
+import java.nio.file.Path;
+
+public interface ArchiveUploader {
+
+    void upload(String key, Path file) throws IOException;
+}
@@ -0,0 +1,92 @@
+package com.kubesec.auth.archive;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.repository.AuthRepository;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.io.OutputStream;
+import java.io.UncheckedIOException;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.time.format.DateTimeFormatter;
+import java.util.UUID;
+
+// Moves login attempts, the service's audit trail, out of Postgres once they are older
+// than AUDIT_LOG_ARCHIVE_AFTER_DAYS. Rows are deleted only after the upload succeeded.
+@Component
+public class AuditArchiver {
+
+    private static final Logger log = LoggerFactory.getLogger(AuditArchiver.class);
+    private static final int DELETE_BATCH_SIZE = 1000;
+
+    private final AuthRepository repository;
+    private final ArchiveUploader uploader;
+    private final ObjectMapper objectMapper;
+    private final AppConfig config;
+
+    public AuditArchiver(AuthRepository repository, ArchiveUploader uploader,
+                         ObjectMapper objectMapper, AppConfig config) {
+        this.repository = repository;
+        this.uploader = uploader;
+        this.objectMapper = objectMapper;
+        this.config = config;
+    }
+
+    @Scheduled(cron = "0 0 4 * * SUN", zone = "UTC")
+    public void archive() {
+        if (config.getAuditArchiveBucket().isBlank()) {
+            return;
+        }
+        OffsetDateTime cutoff = OffsetDateTime.now(ZoneOffset.UTC).minusDays(config.getAuditLogArchiveAfterDays());
+        try {
+            archiveBefore(cutoff);
+        } catch (Exception e) {
+            log.atError().setMessage("archive login attempts")
+                    .addKeyValue("cutoff", cutoff)
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+
+    void archiveBefore(OffsetDateTime cutoff) throws IOException {
+        Path file = Files.createTempFile("login-attempts-", ".ndjson.gz");
+        try {
+            long written;
+            try (OutputStream target = Files.newOutputStream(file);
+                 NdjsonGzipWriter writer = new NdjsonGzipWriter(objectMapper, target)) {
+                repository.streamLoginAttemptsBefore(cutoff, attempt -> {
+                    try {
+                        writer.write(attempt);
+                    } catch (IOException e) {
+                        throw new UncheckedIOException(e);
+                    }
+                });
+                written = writer.getCount();
+            }
+            if (written == 0) {
+                return;
+            }
+
+            String key = "login-attempts/" + cutoff.format(DateTimeFormatter.ISO_LOCAL_DATE)
+                    + "-" + UUID.randomUUID() + ".ndjson.gz";
+            uploader.upload(key, file);
+
+            int deleted = 0;
+            int batch;
+            do {
+                batch = repository.deleteLoginAttemptsBefore(cutoff, DELETE_BATCH_SIZE);
+                deleted += batch;
+            } while (batch == DELETE_BATCH_SIZE);
+            log.info("archived {} login attempts to {} and deleted {}", written, key, deleted);
+        } finally {
+            Files.deleteIfExists(file);
+        }
+    }
+}
@@ -0,0 +1,37 @@
+package com.kubesec.auth.archive;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+
+import java.io.Closeable;
+import java.io.IOException;
+import java.io.OutputStream;
+import java.util.zip.GZIPOutputStream;
+
+// Writes one JSON document per line through gzip, so rows can be streamed straight
+// from a result set without holding the batch in memory.
+public class NdjsonGzipWriter implements Closeable {
+
+    private final ObjectMapper objectMapper;
+    private final GZIPOutputStream out;
+    private long count;
+
+    public NdjsonGzipWriter(ObjectMapper objectMapper, OutputStream target) throws IOException {
+        this.objectMapper = objectMapper;
+        this.out = new GZIPOutputStream(target);
+    }
+
+    public void write(Object value) throws IOException {
+        out.write(objectMapper.writeValueAsBytes(value));
+        out.write('\n');
+        count++;
+    }
+
+    public long getCount() {
+        return count;
+    }
+
+    @Override
+    public void close() throws IOException {
+        out.close();
+    }
+}
@@ -0,0 +1,33 @@
+package com.kubesec.auth.archive;
+
+import software.amazon.awssdk.core.sync.RequestBody;
+import software.amazon.awssdk.services.s3.S3Client;
+import software.amazon.awssdk.services.s3.model.PutObjectRequest;
+
+import java.nio.file.Path;
+
+// The client is built on first use so that a deployment without archival configured
+// does not need AWS credentials or a region to start.
+public class S3ArchiveUploader implements ArchiveUploader {
+
+    private final String bucket;
+    private S3Client client;
+
+    public S3ArchiveUploader(String bucket) {
+        this.bucket = bucket;
+    }
+
+    @Override
+    public synchronized void upload(String key, Path file) {
+        if (client == null) {
+            client = S3Client.create();
+        }
+        client.putObject(PutObjectRequest.builder()
+                        .bucket(bucket)
+                        .key(key)
+                        .contentType("application/x-ndjson")
+                        .contentEncoding("gzip")
+                        .build(),
+                RequestBody.fromFile(file));
+    }
+}
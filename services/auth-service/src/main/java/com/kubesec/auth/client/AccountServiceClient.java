@@ -0,0 +1,39 @@
+package com.kubesec.auth.client;
+
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.filter.LogContextInterceptor;
+import com.kubesec.auth.tenant.TenantHeaderInterceptor;
+import org.springframework.stereotype.Component;
+import org.springframework.web.client.RestClient;
+
+import java.util.Map;
+
+@Component
+public class AccountServiceClient {
+
+    private final RestClient restClient;
+
+    // The builder is Spring's, so calls carry the current trace in a traceparent header.
+    public AccountServiceClient(AppConfig config, RestClient.Builder restClientBuilder) {
+        this.restClient = restClientBuilder
+                .baseUrl(config.getAccountServiceUrl())
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .requestInterceptor(new LogContextInterceptor())
+                .build();
+    }
+
+    // Returns the id account-service assigned to the new user.
+    public String createUser(String email, String fullName) {
+        CreatedUser user = restClient.post()
+                .uri("/api/v1/users")
+                .body(Map.of("email", email, "full_name", fullName))
+                .retrieve()
+                .body(CreatedUser.class);
+        if (user == null || user.id() == null) {
+            throw new IllegalStateException("account-service returned no user id");
+        }
+        return user.id();
+    }
+
+    public record CreatedUser(String id) {}
+}
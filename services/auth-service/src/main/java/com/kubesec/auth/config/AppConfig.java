@@ -9,8 +9,39 @@ import java.time.Duration;
 @ConfigurationProperties(prefix = "app")
 public class AppConfig {
 
-    private String jwtSecret = "change-me-in-production";
+    // Placeholder secret from application.yaml; ConfigValidationEnvironmentPostProcessor refuses to start with it.
+    static final String DEFAULT_JWT_SECRET = "change-me-in-production";
+
+    private String jwtSecret = DEFAULT_JWT_SECRET;
     private int jwtExpiry = 15; // minutes
+    private String jwtSigningMethod = "HS256"; // HS256, RS256 or ES256
+    private String jwtPrivateKeyPath = ""; // PKCS#8 PEM; empty signs with jwtSecret
+    private String jwtPublicKeyPath = ""; // X.509 PEM matching jwtPrivateKeyPath
+    private String jwtIssuer = "kubesec-bank"; // iss on issued tokens; required when parsing
+    private String jwtAudience = "api"; // aud on issued tokens; required when parsing
+    private String adminApiKey = "";
+    private String mtlsCaCertFile = "";
+    private String mtlsServerCertFile = "";
+    private String mtlsServerKeyFile = "";
+    private String tlsCertFile = ""; // server TLS without client certs; see TlsEnvironmentPostProcessor
+    private String tlsKeyFile = "";
+    private int dbSchemaRetryAttempts = 8; // 0 skips the schema check
+    private int dbMaxIdleConns; // defaulted by DbPoolEnvironmentPostProcessor
+    private Duration dbSchemaRetryBackoff = Duration.ofSeconds(1);
+    private long maxContentLengthBytes = 1024 * 1024; // declared Content-Length limit
+    private long maxBodyReadBytes = 1024 * 1024; // limit on bytes actually read, for chunked bodies
+    private int auditLogArchiveAfterDays = 90;
+    private String auditArchiveBucket = ""; // empty disables archival
+    private String natsUrl = "nats://localhost:4222";
+    private String geoipDatabasePath = ""; // GeoLite2-City .mmdb; empty disables lookups
+    private String configWatchFile = ""; // JSON file of reloadable settings; empty disables reloading
+    private String accountServiceUrl = "http://localhost:8081";
+    private Duration emailVerificationExpiry = Duration.ofHours(24);
+    private int minPasswordStrength = 3; // zxcvbn score, 0-4
+    private String serviceTokenPrivateKeyFile = ""; // RSA PKCS#8 PEM; empty disables service tokens
+    private String rateLimitBackend = "memory"; // memory or redis
+    private String otelEndpoint = ""; // OTLP collector base URL; empty keeps traces in-process
+    private String serviceName = "auth-service"; // service.name on exported spans
 
     public String getJwtSecret() { return jwtSecret; }
     public void setJwtSecret(String jwtSecret) { this.jwtSecret = jwtSecret; }
@@ -18,7 +49,95 @@ public class AppConfig {
     public int getJwtExpiry() { return jwtExpiry; }
     public void setJwtExpiry(int jwtExpiry) { this.jwtExpiry = jwtExpiry; }
 
+    public String getJwtSigningMethod() { return jwtSigningMethod; }
+    public void setJwtSigningMethod(String jwtSigningMethod) { this.jwtSigningMethod = jwtSigningMethod; }
+
+    public String getJwtPrivateKeyPath() { return jwtPrivateKeyPath; }
+    public void setJwtPrivateKeyPath(String jwtPrivateKeyPath) { this.jwtPrivateKeyPath = jwtPrivateKeyPath; }
+
+    public String getJwtPublicKeyPath() { return jwtPublicKeyPath; }
+    public void setJwtPublicKeyPath(String jwtPublicKeyPath) { this.jwtPublicKeyPath = jwtPublicKeyPath; }
+
+    public String getJwtIssuer() { return jwtIssuer; }
+    public void setJwtIssuer(String jwtIssuer) { this.jwtIssuer = jwtIssuer; }
+
+    public String getJwtAudience() { return jwtAudience; }
+    public void setJwtAudience(String jwtAudience) { this.jwtAudience = jwtAudience; }
+
+    public String getAdminApiKey() { return adminApiKey; }
+    public void setAdminApiKey(String adminApiKey) { this.adminApiKey = adminApiKey; }
+
     public Duration getJwtExpiryDuration() {
         return Duration.ofMinutes(jwtExpiry);
     }
+
+    public String getMtlsCaCertFile() { return mtlsCaCertFile; }
+    public void setMtlsCaCertFile(String mtlsCaCertFile) { this.mtlsCaCertFile = mtlsCaCertFile; }
+
+    public String getMtlsServerCertFile() { return mtlsServerCertFile; }
+    public void setMtlsServerCertFile(String mtlsServerCertFile) { this.mtlsServerCertFile = mtlsServerCertFile; }
+
+    public String getMtlsServerKeyFile() { return mtlsServerKeyFile; }
+    public void setMtlsServerKeyFile(String mtlsServerKeyFile) { this.mtlsServerKeyFile = mtlsServerKeyFile; }
+
+    public String getTlsCertFile() { return tlsCertFile; }
+    public void setTlsCertFile(String tlsCertFile) { this.tlsCertFile = tlsCertFile; }
+
+    public String getTlsKeyFile() { return tlsKeyFile; }
+    public void setTlsKeyFile(String tlsKeyFile) { this.tlsKeyFile = tlsKeyFile; }
+
+    public int getAuditLogArchiveAfterDays() { return auditLogArchiveAfterDays; }
+    public void setAuditLogArchiveAfterDays(int auditLogArchiveAfterDays) { this.auditLogArchiveAfterDays = auditLogArchiveAfterDays; }
+
+    public String getAuditArchiveBucket() { return auditArchiveBucket; }
+    public void setAuditArchiveBucket(String auditArchiveBucket) { this.auditArchiveBucket = auditArchiveBucket; }
+
+    public int getDbSchemaRetryAttempts() { return dbSchemaRetryAttempts; }
+    public void setDbSchemaRetryAttempts(int dbSchemaRetryAttempts) { this.dbSchemaRetryAttempts = dbSchemaRetryAttempts; }
+
+    public int getDbMaxIdleConns() { return dbMaxIdleConns; }
+    public void setDbMaxIdleConns(int dbMaxIdleConns) { this.dbMaxIdleConns = dbMaxIdleConns; }
+
+    public Duration getDbSchemaRetryBackoff() { return dbSchemaRetryBackoff; }
+    public void setDbSchemaRetryBackoff(Duration dbSchemaRetryBackoff) { this.dbSchemaRetryBackoff = dbSchemaRetryBackoff; }
+
+    public boolean isMtlsEnabled() {
+        return !mtlsCaCertFile.isBlank() && !mtlsServerCertFile.isBlank() && !mtlsServerKeyFile.isBlank();
+    }
+
+    public String getNatsUrl() { return natsUrl; }
+    public void setNatsUrl(String natsUrl) { this.natsUrl = natsUrl; }
+
+    public String getGeoipDatabasePath() { return geoipDatabasePath; }
+    public void setGeoipDatabasePath(String geoipDatabasePath) { this.geoipDatabasePath = geoipDatabasePath; }
+
+    public String getConfigWatchFile() { return configWatchFile; }
+    public void setConfigWatchFile(String configWatchFile) { this.configWatchFile = configWatchFile; }
+
+    public String getAccountServiceUrl() { return accountServiceUrl; }
+    public void setAccountServiceUrl(String accountServiceUrl) { this.accountServiceUrl = accountServiceUrl; }
+
+    public Duration getEmailVerificationExpiry() { return emailVerificationExpiry; }
+    public void setEmailVerificationExpiry(Duration emailVerificationExpiry) { this.emailVerificationExpiry = emailVerificationExpiry; }
+
+    public long getMaxContentLengthBytes() { return maxContentLengthBytes; }
+    public void setMaxContentLengthBytes(long maxContentLengthBytes) { this.maxContentLengthBytes = maxContentLengthBytes; }
+
+    public long getMaxBodyReadBytes() { return maxBodyReadBytes; }
+    public void setMaxBodyReadBytes(long maxBodyReadBytes) { this.maxBodyReadBytes = maxBodyReadBytes; }
+
+    public int getMinPasswordStrength() { return minPasswordStrength; }
+    public void setMinPasswordStrength(int minPasswordStrength) { this.minPasswordStrength = minPasswordStrength; }
+
+    public String getServiceTokenPrivateKeyFile() { return serviceTokenPrivateKeyFile; }
+    public void setServiceTokenPrivateKeyFile(String serviceTokenPrivateKeyFile) { this.serviceTokenPrivateKeyFile = serviceTokenPrivateKeyFile; }
+
+    public String getRateLimitBackend() { return rateLimitBackend; }
+    public void setRateLimitBackend(String rateLimitBackend) { this.rateLimitBackend = rateLimitBackend; }
+
+    public String getOtelEndpoint() { return otelEndpoint; }
+    public void setOtelEndpoint(String otelEndpoint) { this.otelEndpoint = otelEndpoint; }
+
+    public String getServiceName() { return serviceName; }
+    public void setServiceName(String serviceName) { this.serviceName = serviceName; }
 }
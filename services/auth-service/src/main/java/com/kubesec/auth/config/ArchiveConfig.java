@@ -0,0 +1,15 @@
+package com.kubesec.auth.config;
+
+import com.kubesec.auth.archive.ArchiveUploader;
+import com.kubesec.auth.archive.S3ArchiveUploader;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+
+@Configuration
+public class ArchiveConfig {
+
+    @Bean
+    public ArchiveUploader archiveUploader(AppConfig appConfig) {
+        return new S3ArchiveUploader(appConfig.getAuditArchiveBucket());
+    }
+}
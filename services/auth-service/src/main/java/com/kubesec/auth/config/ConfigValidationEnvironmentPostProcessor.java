@@ -0,0 +1,100 @@
+package com.kubesec.auth.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Locale;
+import java.util.Set;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+
+// Refuses to start with settings that would make tokens forgeable or long-lived.
+// Production (ENV or KUBESEC_ENV) also rejects the stock database password and a
+// database or Redis address on localhost. Registered last so it sees the datasource
+// URL and Redis nodes the other post-processors derive.
+public class ConfigValidationEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    static final int MIN_JWT_SECRET_BYTES = 32;
+    static final int MIN_JWT_EXPIRY_MINUTES = 1;
+    static final int MAX_JWT_EXPIRY_MINUTES = 24 * 60;
+
+    private static final Set<String> LOCAL_HOSTS = Set.of("localhost", "127.0.0.1", "::1", "[::1]");
+    // Host of a jdbc:postgresql://host[:port][,host2...]/db URL.
+    private static final Pattern JDBC_HOST = Pattern.compile("^jdbc:postgresql://([^/?]+)");
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        List<String> problems = validate(environment);
+        if (!problems.isEmpty()) {
+            throw new IllegalStateException("invalid configuration: " + String.join("; ", problems));
+        }
+    }
+
+    static List<String> validate(ConfigurableEnvironment environment) {
+        List<String> problems = new ArrayList<>();
+
+        String secret = environment.getProperty("app.jwt-secret", AppConfig.DEFAULT_JWT_SECRET);
+        if (AppConfig.DEFAULT_JWT_SECRET.equals(secret)) {
+            problems.add("JWT_SECRET is still the placeholder default; set a random secret");
+        } else if (secret.getBytes(StandardCharsets.UTF_8).length < MIN_JWT_SECRET_BYTES) {
+            problems.add("JWT_SECRET must be at least " + MIN_JWT_SECRET_BYTES + " bytes");
+        }
+
+        String expiry = environment.getProperty("app.jwt-expiry", "15");
+        try {
+            int minutes = Integer.parseInt(expiry.strip());
+            if (minutes < MIN_JWT_EXPIRY_MINUTES || minutes > MAX_JWT_EXPIRY_MINUTES) {
+                problems.add("JWT_EXPIRY must be between " + MIN_JWT_EXPIRY_MINUTES + " and "
+                        + MAX_JWT_EXPIRY_MINUTES + " minutes, got " + minutes);
+            }
+        } catch (NumberFormatException e) {
+            problems.add("JWT_EXPIRY must be a whole number of minutes, got " + expiry);
+        }
+
+        if (!isProduction(environment)) {
+            return problems;
+        }
+
+        if ("postgres".equals(environment.getProperty("spring.datasource.password"))) {
+            problems.add("DB_PASSWORD must not be the default \"postgres\" in production");
+        }
+        String url = environment.getProperty("spring.datasource.url", "");
+        Matcher matcher = JDBC_HOST.matcher(url);
+        if (matcher.find() && hasLocalHost(matcher.group(1).split(","))) {
+            problems.add("database address must not be localhost in production");
+        }
+        String clusterNodes = environment.getProperty("spring.data.redis.cluster.nodes");
+        String[] redisAddresses = clusterNodes != null && !clusterNodes.isBlank()
+                ? clusterNodes.split(",")
+                : new String[] {environment.getProperty("spring.data.redis.host", "localhost")};
+        if (hasLocalHost(redisAddresses)) {
+            problems.add("Redis address must not be localhost in production");
+        }
+        return problems;
+    }
+
+    private static boolean isProduction(ConfigurableEnvironment environment) {
+        return "production".equalsIgnoreCase(environment.getProperty("ENV"))
+                || "production".equalsIgnoreCase(environment.getProperty("KUBESEC_ENV"));
+    }
+
+    // Addresses are host, host:port or [ipv6]:port.
+    private static boolean hasLocalHost(String[] addresses) {
+        for (String address : addresses) {
+            String host = address.strip().toLowerCase(Locale.ROOT);
+            if (host.startsWith("[")) {
+                host = host.substring(0, host.indexOf(']') + 1);
+            } else if (host.indexOf(':') >= 0 && host.indexOf(':') == host.lastIndexOf(':')) {
+                host = host.substring(0, host.indexOf(':'));
+            }
+            if (LOCAL_HOSTS.contains(host)) {
+                return true;
+            }
+        }
+        return false;
+    }
+}
@@ -0,0 +1,87 @@
+package com.kubesec.auth.config;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.filter.RateLimitFilter;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.nio.file.Files;
+import java.nio.file.NoSuchFileException;
+import java.nio.file.Path;
+import java.time.Duration;
+import java.util.Arrays;
+
+// Reloads rate limit settings from the file named by CONFIG_WATCH_FILE without a
+// restart. The file is polled rather than watched so it also works for ConfigMap
+// volumes, whose updates arrive as symlink swaps. Expected shape:
+//   {"rate_limit": {"limit": 60, "window_seconds": 60}}
+// A file that fails to parse or validate is logged and the previous settings kept.
+@Component
+public class ConfigWatcher {
+
+    private static final Logger log = LoggerFactory.getLogger(ConfigWatcher.class);
+    private static final ObjectMapper MAPPER = new ObjectMapper();
+
+    private final Path file;
+    private final RateLimitFilter rateLimitFilter;
+    private byte[] lastContent;
+
+    public ConfigWatcher(AppConfig config, RateLimitFilter rateLimitFilter) {
+        this.file = config.getConfigWatchFile().isBlank() ? null : Path.of(config.getConfigWatchFile());
+        this.rateLimitFilter = rateLimitFilter;
+    }
+
+    @Scheduled(fixedDelay = 30_000)
+    public synchronized void poll() {
+        if (file == null) {
+            return;
+        }
+
+        byte[] content;
+        try {
+            content = Files.readAllBytes(file);
+        } catch (NoSuchFileException e) {
+            return;
+        } catch (IOException e) {
+            log.warn("could not read {}: {}", file, e.getMessage());
+            return;
+        }
+        if (Arrays.equals(content, lastContent)) {
+            return;
+        }
+        lastContent = content;
+
+        RateLimitSettings settings;
+        try {
+            settings = parse(content);
+        } catch (IOException | IllegalArgumentException e) {
+            log.atError().setMessage("invalid config, keeping current settings")
+                    .addKeyValue("file", file)
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            return;
+        }
+        if (settings != null && !settings.equals(rateLimitFilter.getSettings())) {
+            rateLimitFilter.updateSettings(settings);
+            log.info("rate limit reloaded: {} requests per {}s", settings.limit(), settings.window().toSeconds());
+        }
+    }
+
+    // Returns null when the file has no rate_limit section.
+    static RateLimitSettings parse(byte[] content) throws IOException {
+        JsonNode rateLimit = MAPPER.readTree(content).path("rate_limit");
+        if (rateLimit.isMissingNode()) {
+            return null;
+        }
+        JsonNode limit = rateLimit.path("limit");
+        JsonNode window = rateLimit.path("window_seconds");
+        if (!limit.canConvertToInt() || !window.canConvertToLong()) {
+            throw new IllegalArgumentException("rate_limit.limit and rate_limit.window_seconds must be integers");
+        }
+        return new RateLimitSettings(limit.intValue(), Duration.ofSeconds(window.longValue()));
+    }
+}
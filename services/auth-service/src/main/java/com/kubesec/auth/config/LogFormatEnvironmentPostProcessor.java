@@ -0,0 +1,32 @@
+package com.kubesec.auth.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Map;
+
+// LOG_FORMAT=json writes one ECS JSON document per line, with MDC fields and any
+// key-value pairs as their own fields, for Loki and CloudWatch to index. text keeps the
+// pattern layout. Unset, production logs JSON and everything else text.
+public class LogFormatEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    static final String STRUCTURED_FORMAT = "ecs";
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String format = environment.getProperty("LOG_FORMAT");
+        if (format == null || format.isBlank()) {
+            format = "production".equalsIgnoreCase(environment.getProperty("ENV")) ? "json" : "text";
+        }
+        format = format.strip().toLowerCase();
+        if ("json".equals(format)) {
+            environment.getPropertySources().addLast(new MapPropertySource("logFormat", Map.of(
+                    "logging.structured.format.console", STRUCTURED_FORMAT
+            )));
+        } else if (!"text".equals(format)) {
+            throw new IllegalStateException("LOG_FORMAT must be json or text, got " + format);
+        }
+    }
+}
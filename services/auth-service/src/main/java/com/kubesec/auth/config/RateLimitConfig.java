@@ -0,0 +1,24 @@
+package com.kubesec.auth.config;
+
+import com.kubesec.auth.filter.InMemoryRateLimiter;
+import com.kubesec.auth.filter.RateLimiter;
+import com.kubesec.auth.filter.RedisRateLimiter;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+import org.springframework.data.redis.core.StringRedisTemplate;
+
+@Configuration
+public class RateLimitConfig {
+
+    // Every replica must share the counters or an attacker can spread attempts across
+    // pods, so anything beyond a single replica needs RATE_LIMIT_BACKEND=redis.
+    @Bean
+    public RateLimiter rateLimiter(AppConfig appConfig, StringRedisTemplate redis) {
+        return switch (appConfig.getRateLimitBackend()) {
+            case "memory" -> new InMemoryRateLimiter();
+            case "redis" -> new RedisRateLimiter(redis);
+            default -> throw new IllegalStateException(
+                    "RATE_LIMIT_BACKEND must be memory or redis, got " + appConfig.getRateLimitBackend());
+        };
+    }
+}
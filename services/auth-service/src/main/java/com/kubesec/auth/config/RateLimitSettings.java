@@ -0,0 +1,20 @@
+package com.kubesec.auth.config;
+
+import java.time.Duration;
+
+// The per-IP request budget applied by RateLimitFilter. Swapped as a whole when the
+// watched config file changes, so a request never sees a limit from one version
+// and a window from another.
+public record RateLimitSettings(int limit, Duration window) {
+
+    public static final RateLimitSettings DEFAULT = new RateLimitSettings(60, Duration.ofSeconds(60));
+
+    public RateLimitSettings {
+        if (limit < 1) {
+            throw new IllegalArgumentException("rate limit must be positive");
+        }
+        if (window == null || window.isNegative() || window.isZero()) {
+            throw new IllegalArgumentException("rate limit window must be positive");
+        }
+    }
+}
@@ -0,0 +1,33 @@
+package com.kubesec.auth.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Map;
+
+// REDIS_MODE=cluster points Lettuce at the REDIS_CLUSTER_ADDRS seed nodes instead of
+// REDIS_HOST/REDIS_PORT. Every command this service sends, the rate limiter's script
+// included, touches a single key, so no hash tags are needed for it to be cluster-safe.
+public class RedisModeEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String mode = environment.getProperty("REDIS_MODE", "single");
+        if ("single".equalsIgnoreCase(mode)) {
+            return;
+        }
+        if (!"cluster".equalsIgnoreCase(mode)) {
+            throw new IllegalStateException("REDIS_MODE must be single or cluster, got " + mode);
+        }
+
+        String addrs = environment.getProperty("REDIS_CLUSTER_ADDRS");
+        if (addrs == null || addrs.isBlank()) {
+            throw new IllegalStateException("REDIS_CLUSTER_ADDRS is required when REDIS_MODE=cluster");
+        }
+        environment.getPropertySources().addFirst(new MapPropertySource("redisCluster", Map.of(
+                "spring.data.redis.cluster.nodes", addrs.replace(" ", "")
+        )));
+    }
+}
@@ -0,0 +1,37 @@
+package com.kubesec.auth.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Map;
+
+// Exports spans to the collector named by OTEL_EXPORTER_OTLP_ENDPOINT. That variable
+// holds the collector's base URL, as the OpenTelemetry SDKs read it, while Spring wants
+// the full traces path. Without it spans are still created and propagated in
+// traceparent headers, just not exported.
+public class TracingEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    static final String TRACES_PATH = "/v1/traces";
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String endpoint = environment.getProperty("OTEL_EXPORTER_OTLP_ENDPOINT");
+        if (endpoint == null || endpoint.isBlank()) {
+            return;
+        }
+        // Added last so an explicit management.otlp.tracing.endpoint still wins
+        environment.getPropertySources().addLast(new MapPropertySource("otlpTracing", Map.of(
+                "management.otlp.tracing.endpoint", tracesUrl(endpoint)
+        )));
+    }
+
+    static String tracesUrl(String endpoint) {
+        String url = endpoint.strip();
+        while (url.endsWith("/")) {
+            url = url.substring(0, url.length() - 1);
+        }
+        return url.endsWith(TRACES_PATH) ? url : url + TRACES_PATH;
+    }
+}
@@ -0,0 +1,64 @@
+package com.kubesec.auth.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.service.AuthService;
+import jakarta.servlet.ServletOutputStream;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.format.annotation.DateTimeFormat;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RequestParam;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.time.LocalDate;
+import java.time.ZoneOffset;
+
+// Login attempts are the service's audit trail. Protected by AdminApiKeyFilter.
+@RestController
+public class AuditLogController {
+
+    static final String NDJSON = "application/x-ndjson";
+
+    private final AuthService authService;
+    private final ObjectMapper objectMapper;
+
+    public AuditLogController(AuthService authService, ObjectMapper objectMapper) {
+        this.authService = authService;
+        this.objectMapper = objectMapper;
+    }
+
+    // Streams the tenant's audit log oldest first, one JSON object per line. Each row
+    // is written as it comes off the cursor, so the response is sent chunked and the
+    // log is never held in memory. Both dates are UTC days and inclusive.
+    @GetMapping("/api/v1/admin/audit-log/export")
+    public void export(
+            @RequestParam(required = false) @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate from,
+            @RequestParam(required = false) @DateTimeFormat(iso = DateTimeFormat.ISO.DATE) LocalDate to,
+            HttpServletResponse response) throws IOException {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        if (from != null) {
+            filter.setFrom(from.atStartOfDay().atOffset(ZoneOffset.UTC));
+        }
+        if (to != null) {
+            filter.setTo(to.plusDays(1).atStartOfDay().atOffset(ZoneOffset.UTC));
+        }
+
+        response.setContentType(NDJSON);
+        ServletOutputStream out = response.getOutputStream();
+        try {
+            authService.exportLoginAttempts(filter, attempt -> {
+                try {
+                    out.write(objectMapper.writeValueAsBytes(attempt));
+                    out.write('\n');
+                } catch (IOException e) {
+                    throw new UncheckedIOException(e);
+                }
+            });
+        } catch (UncheckedIOException e) {
+            throw e.getCause();
+        }
+        out.flush();
+    }
+}
@@ -1,16 +1,37 @@
 package com.kubesec.auth.controller;
 
+import com.kubesec.auth.exception.UnauthorizedException;
+import com.kubesec.auth.model.AuthCode;
 import com.kubesec.auth.model.Credentials;
+import com.kubesec.auth.model.Cursor;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.LoginAttemptPage;
+import com.kubesec.auth.model.LoginResult;
+import com.kubesec.auth.model.MfaEnrollment;
 import com.kubesec.auth.model.TokenPair;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.dto.AuthorizeRequest;
+import com.kubesec.auth.model.dto.MfaConfirmRequest;
+import com.kubesec.auth.model.dto.MfaVerifyRequest;
 import com.kubesec.auth.model.dto.RefreshRequest;
+import com.kubesec.auth.model.dto.RegisterDeviceRequest;
+import com.kubesec.auth.model.dto.TokenRequest;
 import com.kubesec.auth.model.dto.TokenValidationResponse;
 import com.kubesec.auth.model.dto.ValidateRequest;
 import com.kubesec.auth.service.AuthService;
+import com.kubesec.auth.validation.ValidatedBody;
 import jakarta.servlet.http.HttpServletRequest;
+import org.springframework.format.annotation.DateTimeFormat;
+import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.*;
 
+import java.time.Duration;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.LinkedHashMap;
 import java.util.Map;
+import java.util.UUID;
 
 @RestController
 public class AuthController {
@@ -26,20 +47,55 @@ public class AuthController {
         return Map.of("status", "ok");
     }
 
+    // 202 with an mfa_token when the user has MFA enabled; the token pair follows from
+    // /api/v1/auth/mfa/verify.
     @PostMapping("/api/v1/auth/login")
-    public TokenPair login(@RequestBody Credentials credentials, HttpServletRequest request) {
-        if (credentials.email() == null || credentials.email().isEmpty()
-                || credentials.password() == null || credentials.password().isEmpty()) {
-            throw new IllegalArgumentException("email and password are required");
+    public ResponseEntity<?> login(@RequestBody @ValidatedBody("login") Credentials credentials, HttpServletRequest request) {
+        LoginResult result = authService.login(credentials.email(), credentials.password(),
+                credentials.deviceFingerprint(), request.getRemoteAddr(), request.getHeader("User-Agent"));
+        if (result.requiresMfa()) {
+            return ResponseEntity.status(HttpStatus.ACCEPTED).body(Map.of(
+                    "mfa_token", result.mfaToken(),
+                    "expires_in", authService.getMfaTokenExpiry().toSeconds()));
         }
-        return authService.login(credentials.email(), credentials.password(), request.getRemoteAddr());
+        return ResponseEntity.ok(result.tokens());
+    }
+
+    @PostMapping("/api/v1/auth/mfa/verify")
+    public TokenPair verifyMfa(@RequestBody @ValidatedBody("mfa-verify") MfaVerifyRequest body,
+                               HttpServletRequest request) {
+        return authService.verifyMfa(body.mfaToken(), body.totpCode(), request.getRemoteAddr(),
+                request.getHeader("User-Agent"));
+    }
+
+    // MFA management acts on the caller's own account; userId is set by JwtAuthFilter
+    @PostMapping("/api/v1/auth/mfa/enroll")
+    public MfaEnrollment enrollMfa(HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        String email = (String) request.getAttribute("email");
+        return authService.enrollMfa(userId, email);
+    }
+
+    @PostMapping("/api/v1/auth/mfa/confirm")
+    public Map<String, Object> confirmMfa(@RequestBody @ValidatedBody("mfa-confirm") MfaConfirmRequest body,
+                                          HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        authService.confirmMfa(userId, body.totpCode());
+        return Map.of("mfa_enabled", true);
+    }
+
+    @DeleteMapping("/api/v1/auth/mfa")
+    public ResponseEntity<Void> disableMfa(HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        authService.disableMfa(userId);
+        return ResponseEntity.noContent().build();
     }
 
     @PostMapping("/api/v1/auth/logout")
     public Map<String, String> logout(HttpServletRequest request) {
         String authHeader = request.getHeader("Authorization");
         if (authHeader == null || !authHeader.startsWith("Bearer ")) {
-            throw new AuthService.AuthenticationException("unauthorized");
+            throw new UnauthorizedException("unauthorized");
         }
         String token = authHeader.substring(7);
 
@@ -50,19 +106,109 @@ public class AuthController {
         return Map.of("message", "logged out successfully");
     }
 
+    @PostMapping("/api/v1/auth/logout-all")
+    public Map<String, Object> logoutAll(HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        int invalidated = authService.logoutAll(userId);
+        return Map.of("message", "logged out of all sessions", "sessions_invalidated", invalidated);
+    }
+
+    @GetMapping("/api/v1/auth/tokens/active")
+    public Map<String, Object> listActiveTokens(HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        return Map.of("tokens", authService.listActiveTokens(userId));
+    }
+
+    // Device endpoints act on the caller's own devices; userId is set by JwtAuthFilter
+    @PostMapping("/api/v1/auth/device/register")
+    public ResponseEntity<TrustedDevice> registerDevice(@RequestBody @ValidatedBody("register-device") RegisterDeviceRequest body,
+                                                        HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        TrustedDevice device = authService.registerTrustedDevice(userId, body.deviceFingerprint(), body.deviceName());
+        return ResponseEntity.status(HttpStatus.CREATED).body(device);
+    }
+
+    @GetMapping("/api/v1/auth/devices")
+    public Map<String, Object> listDevices(HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        return Map.of("devices", authService.listTrustedDevices(userId));
+    }
+
+    @DeleteMapping("/api/v1/auth/devices/{id}")
+    public ResponseEntity<Void> revokeDevice(@PathVariable UUID id, HttpServletRequest request) {
+        String userId = (String) request.getAttribute("userId");
+        authService.revokeTrustedDevice(id, userId);
+        return ResponseEntity.noContent().build();
+    }
+
     @PostMapping("/api/v1/auth/refresh")
-    public TokenPair refresh(@RequestBody RefreshRequest request) {
-        if (request.refreshToken() == null || request.refreshToken().isEmpty()) {
-            throw new IllegalArgumentException("refresh_token is required");
-        }
+    public TokenPair refresh(@RequestBody @ValidatedBody("refresh") RefreshRequest request) {
         return authService.refresh(request.refreshToken());
     }
 
-    @PostMapping("/api/v1/auth/validate")
-    public TokenValidationResponse validate(@RequestBody ValidateRequest request) {
-        if (request.token() == null || request.token().isEmpty()) {
-            throw new IllegalArgumentException("token is required");
+    @PostMapping("/api/v1/auth/authorize")
+    public Map<String, Object> authorize(@RequestBody @ValidatedBody("authorize") AuthorizeRequest body,
+                                         HttpServletRequest request) {
+        // userId and email are set by JwtAuthFilter
+        String userId = (String) request.getAttribute("userId");
+        String email = (String) request.getAttribute("email");
+
+        AuthCode authCode = authService.authorize(userId, email, body.codeChallenge());
+        long expiresIn = Duration.between(OffsetDateTime.now(ZoneOffset.UTC), authCode.expiresAt()).toSeconds();
+        return Map.of("code", authCode.code(), "expires_in", expiresIn);
+    }
+
+    @PostMapping("/api/v1/auth/token")
+    public TokenPair token(@RequestBody @ValidatedBody("token") TokenRequest request) {
+        return authService.exchangeCode(request.code(), request.codeVerifier());
+    }
+
+    @GetMapping("/api/v1/auth/login-attempts/failed")
+    public Map<String, Object> failedLogins(
+            @RequestParam String email,
+            @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime since) {
+        return Map.of("email", email, "failed_count", authService.countFailedLogins(email, since));
+    }
+
+    @GetMapping("/api/v1/admin/login-attempts")
+    public Map<String, Object> listLoginAttempts(
+            @RequestParam(name = "ip", required = false) String ipAddress,
+            @RequestParam(required = false) String email,
+            @RequestParam(required = false) Boolean success,
+            @RequestParam(required = false) @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime from,
+            @RequestParam(required = false) @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime to,
+            @RequestParam(required = false, defaultValue = "50") int limit,
+            @RequestParam(required = false, defaultValue = "0") int offset,
+            @RequestParam(required = false) String cursor) {
+
+        if (limit < 1 || limit > 500) limit = 50;
+        if (offset < 0) offset = 0;
+
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setIpAddress(ipAddress);
+        filter.setEmail(email);
+        filter.setSuccess(success);
+        filter.setFrom(from);
+        filter.setTo(to);
+        filter.setLimit(limit);
+        filter.setOffset(offset);
+        if (cursor != null && !cursor.isEmpty()) {
+            filter.setCursor(Cursor.decode(cursor));
         }
+
+        LoginAttemptPage page = authService.listLoginAttempts(filter);
+
+        Map<String, Object> response = new LinkedHashMap<>();
+        response.put("login_attempts", page.attempts());
+        response.put("total", authService.countLoginAttempts(filter));
+        response.put("limit", filter.getLimit());
+        response.put("offset", filter.getOffset());
+        response.put("next_cursor", page.nextCursor());
+        return response;
+    }
+
+    @PostMapping("/api/v1/auth/validate")
+    public TokenValidationResponse validate(@RequestBody @ValidatedBody("validate") ValidateRequest request) {
         return authService.validate(request.token());
     }
 }
@@ -0,0 +1,29 @@
+package com.kubesec.auth.controller;
+
+import com.kubesec.auth.service.JwtService;
+import org.springframework.http.CacheControl;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.time.Duration;
+import java.util.Map;
+
+@RestController
+public class JwksController {
+
+    private final JwtService jwtService;
+
+    public JwksController(JwtService jwtService) {
+        this.jwtService = jwtService;
+    }
+
+    // Lets other services verify user tokens without holding the signing secret.
+    // Cached briefly so a key rotation reaches verifiers within minutes.
+    @GetMapping("/api/v1/auth/.well-known/jwks.json")
+    public ResponseEntity<Map<String, Object>> jwks() {
+        return ResponseEntity.ok()
+                .cacheControl(CacheControl.maxAge(Duration.ofMinutes(5)).cachePublic())
+                .body(jwtService.jwks());
+    }
+}
@@ -0,0 +1,26 @@
+package com.kubesec.auth.controller;
+
+import com.kubesec.auth.model.dto.RegisterRequest;
+import com.kubesec.auth.model.dto.RegistrationResponse;
+import com.kubesec.auth.service.RegistrationService;
+import com.kubesec.auth.validation.ValidatedBody;
+import org.springframework.http.HttpStatus;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.PostMapping;
+import org.springframework.web.bind.annotation.RequestBody;
+import org.springframework.web.bind.annotation.RestController;
+
+@RestController
+public class RegistrationController {
+
+    private final RegistrationService registrationService;
+
+    public RegistrationController(RegistrationService registrationService) {
+        this.registrationService = registrationService;
+    }
+
+    @PostMapping("/api/v1/auth/register")
+    public ResponseEntity<RegistrationResponse> register(@RequestBody @ValidatedBody("register") RegisterRequest request) {
+        return ResponseEntity.status(HttpStatus.CREATED).body(registrationService.register(request));
+    }
+}
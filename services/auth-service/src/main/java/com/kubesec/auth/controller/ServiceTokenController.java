@@ -0,0 +1,34 @@
+package com.kubesec.auth.controller;
+
+import com.kubesec.auth.exception.ForbiddenException;
+import com.kubesec.auth.filter.ClientCertFilter;
+import com.kubesec.auth.model.ServiceToken;
+import com.kubesec.auth.model.dto.ServiceTokenRequest;
+import com.kubesec.auth.service.ServiceTokenIssuer;
+import com.kubesec.auth.validation.ValidatedBody;
+import org.springframework.web.bind.annotation.PostMapping;
+import org.springframework.web.bind.annotation.RequestAttribute;
+import org.springframework.web.bind.annotation.RequestBody;
+import org.springframework.web.bind.annotation.RestController;
+
+@RestController
+public class ServiceTokenController {
+
+    private final ServiceTokenIssuer issuer;
+
+    public ServiceTokenController(ServiceTokenIssuer issuer) {
+        this.issuer = issuer;
+    }
+
+    // The issuer is the CN of the caller's client certificate, so a service can only
+    // ever obtain tokens in its own name. Without mTLS there is no caller to name.
+    @PostMapping("/api/v1/auth/service-token")
+    public ServiceToken issue(
+            @RequestAttribute(name = ClientCertFilter.CLIENT_NAME_ATTRIBUTE, required = false) String clientName,
+            @RequestBody @ValidatedBody("service-token") ServiceTokenRequest request) {
+        if (clientName == null) {
+            throw new ForbiddenException("client certificate required");
+        }
+        return issuer.issue(clientName, request.audience());
+    }
+}
@@ -1,6 +1,9 @@
 package com.kubesec.auth.exception;
 
-import com.kubesec.auth.service.AuthService;
+import com.kubesec.auth.filter.RecoveryFilter;
+import io.micrometer.core.instrument.Metrics;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
 import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.ExceptionHandler;
@@ -11,16 +14,25 @@ import java.util.Map;
 @RestControllerAdvice
 public class GlobalExceptionHandler {
 
-    @ExceptionHandler(AuthService.AuthenticationException.class)
-    public ResponseEntity<Map<String, String>> handleAuth(AuthService.AuthenticationException ex) {
-        return ResponseEntity.status(HttpStatus.UNAUTHORIZED)
-                .body(Map.of("error", ex.getMessage()));
+    private static final Logger log = LoggerFactory.getLogger(GlobalExceptionHandler.class);
+
+    @ExceptionHandler(DomainException.class)
+    public ResponseEntity<Map<String, String>> handleDomain(DomainException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode()));
     }
 
-    @ExceptionHandler(AuthService.RateLimitedException.class)
-    public ResponseEntity<Map<String, String>> handleRateLimited(AuthService.RateLimitedException ex) {
-        return ResponseEntity.status(HttpStatus.TOO_MANY_REQUESTS)
-                .body(Map.of("error", ex.getMessage()));
+    @ExceptionHandler(SchemaViolationException.class)
+    public ResponseEntity<Map<String, Object>> handleSchemaViolation(SchemaViolationException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode(), "violations", ex.getViolations()));
+    }
+
+    @ExceptionHandler(WeakPasswordException.class)
+    public ResponseEntity<Map<String, Object>> handleWeakPassword(WeakPasswordException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode(), "score", ex.getScore(),
+                        "suggestions", ex.getSuggestions()));
     }
 
     @ExceptionHandler(IllegalArgumentException.class)
@@ -31,6 +43,9 @@ public class GlobalExceptionHandler {
 
     @ExceptionHandler(Exception.class)
     public ResponseEntity<Map<String, String>> handleGeneral(Exception ex) {
+        // Counted with the exceptions RecoveryFilter catches outside the dispatcher.
+        Metrics.counter(RecoveryFilter.PANIC_METRIC).increment();
+        log.atError().setMessage("Unhandled exception").setCause(ex).log();
         return ResponseEntity.status(HttpStatus.INTERNAL_SERVER_ERROR)
                 .body(Map.of("error", "internal error"));
     }
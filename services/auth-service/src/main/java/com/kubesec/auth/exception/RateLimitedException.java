@@ -0,0 +1,10 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class RateLimitedException extends DomainException {
+
+    public RateLimitedException(String message) {
+        super("rate_limited", HttpStatus.TOO_MANY_REQUESTS, message);
+    }
+}
@@ -0,0 +1,10 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class ResourceNotFoundException extends DomainException {
+
+    public ResourceNotFoundException(String message) {
+        super("not_found", HttpStatus.NOT_FOUND, message);
+    }
+}
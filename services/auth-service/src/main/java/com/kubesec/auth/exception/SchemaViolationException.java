@@ -0,0 +1,17 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+import java.util.List;
+
+public class SchemaViolationException extends DomainException {
+
+    private final List<String> violations;
+
+    public SchemaViolationException(List<String> violations) {
+        super("schema_violation", HttpStatus.UNPROCESSABLE_ENTITY, "request body failed validation");
+        this.violations = violations;
+    }
+
+    public List<String> getViolations() { return violations; }
+}
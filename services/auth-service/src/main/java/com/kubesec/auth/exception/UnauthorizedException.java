@@ -0,0 +1,10 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class UnauthorizedException extends DomainException {
+
+    public UnauthorizedException(String message) {
+        super("unauthorized", HttpStatus.UNAUTHORIZED, message);
+    }
+}
@@ -0,0 +1,10 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class ValidationException extends DomainException {
+
+    public ValidationException(String message) {
+        super("validation_error", HttpStatus.BAD_REQUEST, message);
+    }
+}
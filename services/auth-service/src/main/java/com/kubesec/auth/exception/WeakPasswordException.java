@@ -0,0 +1,21 @@
+package com.kubesec.auth.exception;
+
+import org.springframework.http.HttpStatus;
+
+import java.util.List;
+
+public class WeakPasswordException extends DomainException {
+
+    private final int score;
+    private final List<String> suggestions;
+
+    public WeakPasswordException(int score, List<String> suggestions) {
+        super("weak_password", HttpStatus.UNPROCESSABLE_ENTITY, "password too weak");
+        this.score = score;
+        this.suggestions = suggestions;
+    }
+
+    public int getScore() { return score; }
+
+    public List<String> getSuggestions() { return suggestions; }
+}
@@ -0,0 +1,76 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import javax.naming.InvalidNameException;
+import javax.naming.ldap.LdapName;
+import javax.naming.ldap.Rdn;
+import java.io.IOException;
+import java.security.cert.X509Certificate;
+
+// The TLS handshake has already verified any presented certificate against the
+// MTLS_CA_CERT_FILE pool; this filter makes one mandatory on service-to-service routes.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
+public class ClientCertFilter extends OncePerRequestFilter {
+
+    private static final Logger log = LoggerFactory.getLogger(ClientCertFilter.class);
+    private static final String CERTIFICATE_ATTRIBUTE = "jakarta.servlet.request.X509Certificate";
+    // The calling service's certificate CN, for handlers that act on the caller's identity.
+    public static final String CLIENT_NAME_ATTRIBUTE = "clientName";
+
+    private final AppConfig config;
+
+    public ClientCertFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        String path = request.getRequestURI();
+        // Token validation and service tokens are only ever requested by the other services
+        return !config.isMtlsEnabled()
+                || !(path.equals("/api/v1/auth/validate") || path.equals("/api/v1/auth/service-token"));
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        X509Certificate[] certs = (X509Certificate[]) request.getAttribute(CERTIFICATE_ATTRIBUTE);
+        if (certs == null || certs.length == 0) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_FORBIDDEN);
+            response.getWriter().write("{\"error\":\"client certificate required\"}");
+            return;
+        }
+
+        String clientName = commonName(certs[0]);
+        log.info("mTLS client {} calling {}", clientName, request.getRequestURI());
+        request.setAttribute(CLIENT_NAME_ATTRIBUTE, clientName);
+        chain.doFilter(request, response);
+    }
+
+    private static String commonName(X509Certificate cert) {
+        String subject = cert.getSubjectX500Principal().getName();
+        try {
+            for (Rdn rdn : new LdapName(subject).getRdns()) {
+                if ("CN".equalsIgnoreCase(rdn.getType())) {
+                    return rdn.getValue().toString();
+                }
+            }
+        } catch (InvalidNameException e) {
+            // fall through to the full subject
+        }
+        return subject;
+    }
+}
@@ -0,0 +1,42 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.RateLimitSettings;
+
+import java.time.Instant;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.concurrent.ConcurrentHashMap;
+
+// Counts per pod. Enough for local development and single-replica deployments; with
+// more replicas each one has its own budget, so use RedisRateLimiter there.
+public class InMemoryRateLimiter implements RateLimiter {
+
+    private final ConcurrentHashMap<String, List<Instant>> requests = new ConcurrentHashMap<>();
+
+    @Override
+    public Decision acquire(String key, RateLimitSettings settings, Instant now) {
+        int limit = settings.limit();
+        Instant windowStart = now.minus(settings.window());
+
+        List<Instant> timestamps = requests.compute(key, (k, existing) -> {
+            List<Instant> valid = new ArrayList<>();
+            if (existing != null) {
+                for (Instant t : existing) {
+                    if (t.isAfter(windowStart)) {
+                        valid.add(t);
+                    }
+                }
+            }
+            return valid;
+        });
+
+        synchronized (timestamps) {
+            boolean allowed = timestamps.size() < limit;
+            if (allowed) {
+                timestamps.add(now);
+            }
+            Instant oldest = timestamps.isEmpty() ? now : timestamps.get(0);
+            return new Decision(allowed, Math.max(0, limit - timestamps.size()), oldest.plus(settings.window()));
+        }
+    }
+}
@@ -1,12 +1,14 @@
 package com.kubesec.auth.filter;
 
 import com.kubesec.auth.service.JwtService;
+import com.kubesec.auth.tenant.TenantContext;
 import io.jsonwebtoken.Claims;
 import io.jsonwebtoken.JwtException;
 import jakarta.servlet.FilterChain;
 import jakarta.servlet.ServletException;
 import jakarta.servlet.http.HttpServletRequest;
 import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.MDC;
 import org.springframework.core.annotation.Order;
 import org.springframework.stereotype.Component;
 import org.springframework.web.filter.OncePerRequestFilter;
@@ -26,8 +28,15 @@ public class JwtAuthFilter extends OncePerRequestFilter {
     @Override
     protected boolean shouldNotFilter(HttpServletRequest request) {
         String path = request.getRequestURI();
-        // Only protect logout endpoint; login, refresh, validate, health are public
-        return !"/api/v1/auth/logout".equals(path);
+        // Only protect logout, authorize, device and MFA management, the active token list and
+        // the failed-login lookup; login, mfa/verify, register, refresh, token, validate, health
+        // are public
+        return !"/api/v1/auth/logout".equals(path) && !"/api/v1/auth/logout-all".equals(path)
+                && !"/api/v1/auth/authorize".equals(path)
+                && !"/api/v1/auth/login-attempts/failed".equals(path) && !"/api/v1/auth/tokens/active".equals(path)
+                && !"/api/v1/auth/device/register".equals(path) && !path.startsWith("/api/v1/auth/devices")
+                && !"/api/v1/auth/mfa".equals(path) && !"/api/v1/auth/mfa/enroll".equals(path)
+                && !"/api/v1/auth/mfa/confirm".equals(path);
     }
 
     @Override
@@ -44,7 +53,14 @@ public class JwtAuthFilter extends OncePerRequestFilter {
         String token = authHeader.substring(7);
         try {
             Claims claims = jwtService.parseToken(token);
+            if (!jwtService.isIssuedFor(claims, TenantContext.require())) {
+                response.setContentType("application/json");
+                response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
+                response.getWriter().write("{\"error\":\"token was issued for a different tenant\"}");
+                return;
+            }
             request.setAttribute("userId", claims.get("user_id", String.class));
+            MDC.put(LogContextFilter.USER_ID, claims.get("user_id", String.class));
             request.setAttribute("email", claims.get("email", String.class));
         } catch (JwtException e) {
             response.setContentType("application/json");
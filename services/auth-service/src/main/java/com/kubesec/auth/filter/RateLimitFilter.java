@@ -1,5 +1,6 @@
 package com.kubesec.auth.filter;
 
+import com.kubesec.auth.config.RateLimitSettings;
 import jakarta.servlet.FilterChain;
 import jakarta.servlet.ServletException;
 import jakarta.servlet.http.HttpServletRequest;
@@ -10,19 +11,29 @@ import org.springframework.stereotype.Component;
 import org.springframework.web.filter.OncePerRequestFilter;
 
 import java.io.IOException;
+import java.time.Duration;
 import java.time.Instant;
-import java.util.ArrayList;
-import java.util.List;
-import java.util.concurrent.ConcurrentHashMap;
+import java.util.concurrent.atomic.AtomicReference;
 
 @Component
-@Order(Ordered.HIGHEST_PRECEDENCE)
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
 public class RateLimitFilter extends OncePerRequestFilter {
 
-    private static final int LIMIT = 60;
-    private static final long WINDOW_SECONDS = 60;
+    private final AtomicReference<RateLimitSettings> settings = new AtomicReference<>(RateLimitSettings.DEFAULT);
+    private final RateLimiter limiter;
 
-    private final ConcurrentHashMap<String, List<Instant>> requests = new ConcurrentHashMap<>();
+    public RateLimitFilter(RateLimiter limiter) {
+        this.limiter = limiter;
+    }
+
+    public RateLimitSettings getSettings() {
+        return settings.get();
+    }
+
+    // Takes effect from the next request; timestamps already recorded are kept.
+    public void updateSettings(RateLimitSettings updated) {
+        settings.set(updated);
+    }
 
     @Override
     protected boolean shouldNotFilter(HttpServletRequest request) {
@@ -34,30 +45,22 @@ public class RateLimitFilter extends OncePerRequestFilter {
     @Override
     protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
                                     FilterChain chain) throws ServletException, IOException {
-        String ip = request.getRemoteAddr();
+        RateLimitSettings current = settings.get();
         Instant now = Instant.now();
-        Instant windowStart = now.minusSeconds(WINDOW_SECONDS);
+        RateLimiter.Decision decision = limiter.acquire(request.getRemoteAddr(), current, now);
 
-        List<Instant> timestamps = requests.compute(ip, (key, existing) -> {
-            List<Instant> valid = new ArrayList<>();
-            if (existing != null) {
-                for (Instant t : existing) {
-                    if (t.isAfter(windowStart)) {
-                        valid.add(t);
-                    }
-                }
-            }
-            return valid;
-        });
+        // RateLimit-* headers per draft-ietf-httpapi-ratelimit-headers. Reset counts down
+        // in seconds, rounded up so a client that waits it out is never early.
+        long resetMillis = Math.max(0, Duration.between(now, decision.reset()).toMillis());
+        response.setHeader("RateLimit-Limit", String.valueOf(current.limit()));
+        response.setHeader("RateLimit-Remaining", String.valueOf(decision.remaining()));
+        response.setHeader("RateLimit-Reset", String.valueOf((resetMillis + 999) / 1000));
 
-        synchronized (timestamps) {
-            if (timestamps.size() >= LIMIT) {
-                response.setContentType("application/json");
-                response.setStatus(429);
-                response.getWriter().write("{\"error\":\"rate limit exceeded\"}");
-                return;
-            }
-            timestamps.add(now);
+        if (!decision.allowed()) {
+            response.setContentType("application/json");
+            response.setStatus(429);
+            response.getWriter().write("{\"error\":\"rate limit exceeded\"}");
+            return;
         }
 
         chain.doFilter(request, response);
@@ -0,0 +1,16 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.RateLimitSettings;
+
+import java.time.Instant;
+
+// Sliding-window request counter behind RateLimitFilter. A rejected request is not
+// counted against the window.
+public interface RateLimiter {
+
+    Decision acquire(String key, RateLimitSettings settings, Instant now);
+
+    // remaining is what is left after this request; reset is when the oldest request
+    // in the window drops out of it.
+    record Decision(boolean allowed, int remaining, Instant reset) {}
+}
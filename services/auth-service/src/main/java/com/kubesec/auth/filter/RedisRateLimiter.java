@@ -0,0 +1,76 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.RateLimitSettings;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.dao.DataAccessException;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.data.redis.core.script.DefaultRedisScript;
+import org.springframework.data.redis.core.script.RedisScript;
+
+import java.time.Instant;
+import java.util.List;
+import java.util.UUID;
+
+// Shares the window across replicas with one sorted set per key, scored by request
+// time in milliseconds. Each request trims the set, adds itself and counts the window
+// in one script; if that puts the key over the limit the entry is removed again, so
+// rejected requests don't count. A script rather than MULTI/EXEC because Redis Cluster
+// connections don't support transactions, and a script touching a single key does.
+public class RedisRateLimiter implements RateLimiter {
+
+    private static final Logger log = LoggerFactory.getLogger(RedisRateLimiter.class);
+    private static final String KEY_PREFIX = "ratelimit:";
+
+    // ARGV: window start (exclusive), now, member, window in ms, limit.
+    // Returns {allowed, requests counted, score of the oldest counted request}.
+    static final RedisScript<List> ACQUIRE = new DefaultRedisScript<>("""
+            redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+            redis.call('ZADD', KEYS[1], ARGV[2], ARGV[3])
+            redis.call('PEXPIRE', KEYS[1], ARGV[4])
+            local count = redis.call('ZCARD', KEYS[1])
+            local allowed = 1
+            if count > tonumber(ARGV[5]) then
+                redis.call('ZREM', KEYS[1], ARGV[3])
+                count = count - 1
+                allowed = 0
+            end
+            local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')[2] or ARGV[2]
+            return {allowed, count, tonumber(oldest)}
+            """, List.class);
+
+    private final StringRedisTemplate redis;
+    // Used while Redis is unreachable so an outage limits per pod instead of not at all.
+    private final InMemoryRateLimiter fallback = new InMemoryRateLimiter();
+
+    public RedisRateLimiter(StringRedisTemplate redis) {
+        this.redis = redis;
+    }
+
+    @Override
+    public Decision acquire(String key, RateLimitSettings settings, Instant now) {
+        try {
+            return acquireShared(KEY_PREFIX + key, settings, now);
+        } catch (DataAccessException e) {
+            log.warn("rate limit store unavailable, counting locally: {}", e.getMessage());
+            return fallback.acquire(key, settings, now);
+        }
+    }
+
+    private Decision acquireShared(String redisKey, RateLimitSettings settings, Instant now) {
+        long nowMillis = now.toEpochMilli();
+        long windowStart = now.minus(settings.window()).toEpochMilli();
+        // Unique so two requests in the same millisecond are both counted.
+        String member = nowMillis + ":" + UUID.randomUUID();
+
+        @SuppressWarnings("unchecked")
+        List<Long> result = redis.execute(ACQUIRE, List.of(redisKey), String.valueOf(windowStart),
+                String.valueOf(nowMillis), member, String.valueOf(settings.window().toMillis()),
+                String.valueOf(settings.limit()));
+
+        boolean allowed = result.get(0) == 1L;
+        long counted = result.get(1);
+        return new Decision(allowed, (int) Math.max(0, settings.limit() - counted),
+                Instant.ofEpochMilli(result.get(2)).plus(settings.window()));
+    }
+}
@@ -0,0 +1,139 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ReadListener;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.ServletInputStream;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletRequestWrapper;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.BufferedReader;
+import java.io.IOException;
+import java.io.InputStreamReader;
+import java.nio.charset.Charset;
+import java.nio.charset.StandardCharsets;
+
+// Rejects oversized requests with 413. A declared Content-Length over
+// app.max-content-length-bytes is refused before any of the body is read; bodies
+// without one (chunked) are cut off once app.max-body-read-bytes have been read.
+// Runs ahead of every filter that buffers or inflates the body.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE)
+public class RequestSizeFilter extends OncePerRequestFilter {
+
+    private final AppConfig config;
+
+    public RequestSizeFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        long maxContentLength = config.getMaxContentLengthBytes();
+        if (request.getContentLengthLong() > maxContentLength) {
+            reject(response, "request body exceeds " + maxContentLength + " bytes");
+            return;
+        }
+
+        long maxBodyRead = config.getMaxBodyReadBytes();
+        LimitedRequest limited = new LimitedRequest(request, maxBodyRead);
+        chain.doFilter(limited, response);
+
+        // Whatever the handler made of the failed read, the client sees a 413.
+        if (limited.exceeded && !response.isCommitted()) {
+            response.reset();
+            reject(response, "request body exceeds " + maxBodyRead + " bytes");
+        }
+    }
+
+    private static void reject(HttpServletResponse response, String message) throws IOException {
+        response.setContentType("application/json");
+        response.setStatus(HttpServletResponse.SC_REQUEST_ENTITY_TOO_LARGE);
+        response.getWriter().write("{\"error\":\"" + message + "\"}");
+    }
+
+    private static class BodyTooLargeException extends IOException {
+        BodyTooLargeException(long limit) {
+            super("request body exceeds " + limit + " bytes");
+        }
+    }
+
+    private static class LimitedRequest extends HttpServletRequestWrapper {
+
+        private final long limit;
+        private ServletInputStream stream;
+        private boolean exceeded;
+
+        LimitedRequest(HttpServletRequest request, long limit) {
+            super(request);
+            this.limit = limit;
+        }
+
+        @Override
+        public ServletInputStream getInputStream() throws IOException {
+            if (stream == null) {
+                stream = new LimitedInputStream(super.getInputStream());
+            }
+            return stream;
+        }
+
+        @Override
+        public BufferedReader getReader() throws IOException {
+            String encoding = getCharacterEncoding();
+            Charset charset = encoding != null ? Charset.forName(encoding) : StandardCharsets.UTF_8;
+            return new BufferedReader(new InputStreamReader(getInputStream(), charset));
+        }
+
+        private class LimitedInputStream extends ServletInputStream {
+
+            private final ServletInputStream in;
+            private long read;
+
+            LimitedInputStream(ServletInputStream in) {
+                this.in = in;
+            }
+
+            @Override
+            public int read() throws IOException {
+                int b = in.read();
+                if (b != -1) {
+                    count(1);
+                }
+                return b;
+            }
+
+            @Override
+            public int read(byte[] buf, int off, int len) throws IOException {
+                int n = in.read(buf, off, len);
+                if (n > 0) {
+                    count(n);
+                }
+                return n;
+            }
+
+            private void count(int n) throws IOException {
+                read += n;
+                if (read > limit) {
+                    exceeded = true;
+                    throw new BodyTooLargeException(limit);
+                }
+            }
+
+            @Override
+            public boolean isFinished() { return in.isFinished(); }
+
+            @Override
+            public boolean isReady() { return in.isReady(); }
+
+            @Override
+            public void setReadListener(ReadListener listener) { in.setReadListener(listener); }
+        }
+    }
+}
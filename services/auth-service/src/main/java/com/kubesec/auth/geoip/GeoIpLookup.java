@@ -0,0 +1,64 @@
+package com.kubesec.auth.geoip;
+
+import com.kubesec.auth.config.AppConfig;
+import com.maxmind.geoip2.DatabaseReader;
+import com.maxmind.geoip2.exception.GeoIp2Exception;
+import com.maxmind.geoip2.model.CityResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.stereotype.Component;
+
+import java.io.File;
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.net.InetAddress;
+import java.net.UnknownHostException;
+import java.util.Optional;
+
+// Resolves client IPs against a MaxMind GeoLite2-City database. The database is
+// licensed separately and mounted at runtime; without it every lookup is empty.
+@Component
+public class GeoIpLookup {
+
+    private static final Logger log = LoggerFactory.getLogger(GeoIpLookup.class);
+
+    private final DatabaseReader reader;
+
+    public GeoIpLookup(AppConfig config) {
+        this(config.getGeoipDatabasePath().isBlank() ? null : new File(config.getGeoipDatabasePath()));
+    }
+
+    GeoIpLookup(File database) {
+        if (database == null) {
+            this.reader = null;
+            return;
+        }
+        try {
+            this.reader = new DatabaseReader.Builder(database).build();
+        } catch (IOException e) {
+            throw new UncheckedIOException("failed to open geo-ip database " + database, e);
+        }
+    }
+
+    public Optional<GeoLocation> lookup(String ip) {
+        if (reader == null || ip == null || ip.isBlank()) {
+            return Optional.empty();
+        }
+        try {
+            // ip is always a literal address, so this never hits DNS
+            Optional<CityResponse> response = reader.tryCity(InetAddress.getByName(ip));
+            return response
+                    .filter(r -> r.getCountry().getIsoCode() != null)
+                    .map(r -> new GeoLocation(r.getCountry().getIsoCode(), r.getCity().getName()));
+        } catch (UnknownHostException e) {
+            return Optional.empty();
+        } catch (IOException | GeoIp2Exception e) {
+            log.warn("geo-ip lookup for {} failed: {}", ip, e.getMessage());
+            return Optional.empty();
+        }
+    }
+
+    public Optional<String> countryForIp(String ip) {
+        return lookup(ip).map(GeoLocation::country);
+    }
+}
@@ -0,0 +1,5 @@
+package com.kubesec.auth.geoip;
+
+// country is an ISO 3166-1 alpha-2 code; city may be null when the database
+// only resolves the address to a country.
+public record GeoLocation(String country, String city) {}
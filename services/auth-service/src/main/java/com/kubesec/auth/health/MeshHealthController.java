@@ -0,0 +1,50 @@
+package com.kubesec.auth.health;
+
+import org.springframework.http.HttpStatus;
+import org.springframework.http.MediaType;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.LinkedHashMap;
+import java.util.Map;
+
+// Envoy's health check filter expects plain-text bodies rather than JSON. The
+// pending-migrations case is the exception: it is read by operators, not Envoy.
+@RestController
+public class MeshHealthController {
+
+    private final ShutdownState shutdownState;
+    private final MigrationStatusChecker migrationStatus;
+
+    public MeshHealthController(ShutdownState shutdownState, MigrationStatusChecker migrationStatus) {
+        this.shutdownState = shutdownState;
+        this.migrationStatus = migrationStatus;
+    }
+
+    @GetMapping(value = "/live", produces = MediaType.TEXT_PLAIN_VALUE)
+    public ResponseEntity<String> live() {
+        return ResponseEntity.ok("OK");
+    }
+
+    @GetMapping("/ready")
+    public ResponseEntity<?> ready() {
+        if (shutdownState.isShuttingDown()) {
+            return ResponseEntity.status(HttpStatus.SERVICE_UNAVAILABLE)
+                    .contentType(MediaType.TEXT_PLAIN)
+                    .body("SHUTTING DOWN");
+        }
+        int current = migrationStatus.currentVersion();
+        int latest = migrationStatus.latestVersion();
+        if (current != latest) {
+            Map<String, Object> body = new LinkedHashMap<>();
+            body.put("migrations", "pending");
+            body.put("current", current);
+            body.put("latest", latest);
+            return ResponseEntity.status(HttpStatus.SERVICE_UNAVAILABLE)
+                    .contentType(MediaType.APPLICATION_JSON)
+                    .body(body);
+        }
+        return ResponseEntity.ok().contentType(MediaType.TEXT_PLAIN).body("OK");
+    }
+}
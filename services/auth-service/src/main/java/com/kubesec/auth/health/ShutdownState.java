@@ -0,0 +1,24 @@
+package com.kubesec.auth.health;
+
+import org.springframework.context.ApplicationListener;
+import org.springframework.context.event.ContextClosedEvent;
+import org.springframework.stereotype.Component;
+
+import java.util.concurrent.atomic.AtomicBoolean;
+
+// ContextClosedEvent fires on SIGTERM before the web server starts its graceful
+// shutdown, so /ready flips to 503 while in-flight requests are still drained.
+@Component
+public class ShutdownState implements ApplicationListener<ContextClosedEvent> {
+
+    private final AtomicBoolean shuttingDown = new AtomicBoolean(false);
+
+    @Override
+    public void onApplicationEvent(ContextClosedEvent event) {
+        shuttingDown.set(true);
+    }
+
+    public boolean isShuttingDown() {
+        return shuttingDown.get();
+    }
+}
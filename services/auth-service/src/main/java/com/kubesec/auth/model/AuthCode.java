@@ -0,0 +1,13 @@
+package com.kubesec.auth.model;
+
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record AuthCode(
+        String code,
+        UUID tenantId,
+        String userId,
+        String email,
+        String codeChallenge,
+        OffsetDateTime expiresAt
+) {}
@@ -1,3 +1,9 @@
 package com.kubesec.auth.model;
 
-public record Credentials(String email, String password) {}
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record Credentials(
+        String email,
+        String password,
+        @JsonProperty("device_fingerprint") String deviceFingerprint
+) {}
@@ -0,0 +1,12 @@
+package com.kubesec.auth.model;
+
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record EmailVerificationToken(
+        String tokenHash,
+        UUID tenantId,
+        String userId,
+        OffsetDateTime expiresAt,
+        OffsetDateTime createdAt
+) {}
@@ -2,11 +2,17 @@ package com.kubesec.auth.model;
 
 import com.fasterxml.jackson.annotation.JsonProperty;
 import java.time.OffsetDateTime;
+import java.util.UUID;
 
 public record LoginAttempt(
         String id,
+        @JsonProperty("tenant_id") UUID tenantId,
         String email,
+        @JsonProperty("user_id") String userId,
         boolean success,
         @JsonProperty("ip_address") String ipAddress,
+        @JsonProperty("ip_country") String ipCountry,
+        @JsonProperty("ip_city") String ipCity,
+        @JsonProperty("user_agent") String userAgent,
         @JsonProperty("created_at") OffsetDateTime createdAt
 ) {}
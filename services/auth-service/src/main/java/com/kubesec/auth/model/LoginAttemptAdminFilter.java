@@ -0,0 +1,39 @@
+package com.kubesec.auth.model;
+
+import java.time.OffsetDateTime;
+
+public class LoginAttemptAdminFilter {
+
+    private String ipAddress;
+    private String email;
+    private Boolean success;
+    private OffsetDateTime from;
+    private OffsetDateTime to;
+    private int limit = 50;
+    private int offset = 0;
+    private Cursor cursor; // takes precedence over offset
+
+    public String getIpAddress() { return ipAddress; }
+    public void setIpAddress(String ipAddress) { this.ipAddress = ipAddress; }
+
+    public String getEmail() { return email; }
+    public void setEmail(String email) { this.email = email; }
+
+    public Boolean getSuccess() { return success; }
+    public void setSuccess(Boolean success) { this.success = success; }
+
+    public OffsetDateTime getFrom() { return from; }
+    public void setFrom(OffsetDateTime from) { this.from = from; }
+
+    public OffsetDateTime getTo() { return to; }
+    public void setTo(OffsetDateTime to) { this.to = to; }
+
+    public int getLimit() { return limit; }
+    public void setLimit(int limit) { this.limit = limit; }
+
+    public int getOffset() { return offset; }
+    public void setOffset(int offset) { this.offset = offset; }
+
+    public Cursor getCursor() { return cursor; }
+    public void setCursor(Cursor cursor) { this.cursor = cursor; }
+}
@@ -0,0 +1,6 @@
+package com.kubesec.auth.model;
+
+import java.util.List;
+
+// nextCursor is null on the last page.
+public record LoginAttemptPage(List<LoginAttempt> attempts, String nextCursor) {}
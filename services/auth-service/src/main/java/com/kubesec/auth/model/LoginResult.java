@@ -0,0 +1,18 @@
+package com.kubesec.auth.model;
+
+// Either the session's token pair, or an mfa_token to exchange for one at
+// /api/v1/auth/mfa/verify together with a TOTP code.
+public record LoginResult(TokenPair tokens, String mfaToken) {
+
+    public static LoginResult authenticated(TokenPair tokens) {
+        return new LoginResult(tokens, null);
+    }
+
+    public static LoginResult mfaRequired(String mfaToken) {
+        return new LoginResult(null, mfaToken);
+    }
+
+    public boolean requiresMfa() {
+        return mfaToken != null;
+    }
+}
@@ -0,0 +1,9 @@
+package com.kubesec.auth.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+// secret is base32 for manual entry; the otpauth:// URI is what a QR code encodes.
+public record MfaEnrollment(
+        String secret,
+        @JsonProperty("provisioning_uri") String provisioningUri
+) {}
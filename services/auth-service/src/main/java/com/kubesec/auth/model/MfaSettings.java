@@ -0,0 +1,4 @@
+package com.kubesec.auth.model;
+
+// A user's TOTP enrollment. The secret is only enforced at login once enabled.
+public record MfaSettings(String totpSecret, boolean enabled) {}
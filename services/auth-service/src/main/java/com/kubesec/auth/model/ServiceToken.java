@@ -0,0 +1,9 @@
+package com.kubesec.auth.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.Instant;
+
+public record ServiceToken(
+        String token,
+        @JsonProperty("expires_at") Instant expiresAt
+) {}
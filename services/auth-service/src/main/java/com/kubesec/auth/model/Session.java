@@ -2,11 +2,15 @@ package com.kubesec.auth.model;
 
 import com.fasterxml.jackson.annotation.JsonProperty;
 import java.time.OffsetDateTime;
+import java.util.UUID;
 
 public class Session {
 
     private String id;
 
+    @JsonProperty("tenant_id")
+    private UUID tenantId;
+
     @JsonProperty("user_id")
     private String userId;
 
@@ -15,9 +19,15 @@ public class Session {
     @JsonProperty("expires_at")
     private OffsetDateTime expiresAt;
 
+    @JsonProperty("device_fingerprint")
+    private String deviceFingerprint;
+
     @JsonProperty("created_at")
     private OffsetDateTime createdAt;
 
+    @JsonProperty("updated_at")
+    private OffsetDateTime updatedAt;
+
     public Session() {}
 
     public Session(String id, String userId, String token, OffsetDateTime expiresAt, OffsetDateTime createdAt) {
@@ -31,6 +41,9 @@ public class Session {
     public String getId() { return id; }
     public void setId(String id) { this.id = id; }
 
+    public UUID getTenantId() { return tenantId; }
+    public void setTenantId(UUID tenantId) { this.tenantId = tenantId; }
+
     public String getUserId() { return userId; }
     public void setUserId(String userId) { this.userId = userId; }
 
@@ -40,6 +53,12 @@ public class Session {
     public OffsetDateTime getExpiresAt() { return expiresAt; }
     public void setExpiresAt(OffsetDateTime expiresAt) { this.expiresAt = expiresAt; }
 
+    public String getDeviceFingerprint() { return deviceFingerprint; }
+    public void setDeviceFingerprint(String deviceFingerprint) { this.deviceFingerprint = deviceFingerprint; }
+
     public OffsetDateTime getCreatedAt() { return createdAt; }
     public void setCreatedAt(OffsetDateTime createdAt) { this.createdAt = createdAt; }
+
+    public OffsetDateTime getUpdatedAt() { return updatedAt; }
+    public void setUpdatedAt(OffsetDateTime updatedAt) { this.updatedAt = updatedAt; }
 }
@@ -0,0 +1,13 @@
+package com.kubesec.auth.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+
+// An active session as shown to its owner. The token itself is never included.
+public record TokenInfo(
+        String id,
+        @JsonProperty("created_at") OffsetDateTime createdAt,
+        @JsonProperty("expires_at") OffsetDateTime expiresAt,
+        @JsonProperty("ip_address") String ipAddress,
+        @JsonProperty("user_agent") String userAgent
+) {}
@@ -0,0 +1,15 @@
+package com.kubesec.auth.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record TrustedDevice(
+        UUID id,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("user_id") String userId,
+        @JsonProperty("device_fingerprint") String deviceFingerprint,
+        @JsonProperty("device_name") String deviceName,
+        @JsonProperty("trusted_at") OffsetDateTime trustedAt,
+        @JsonProperty("expires_at") OffsetDateTime expiresAt
+) {}
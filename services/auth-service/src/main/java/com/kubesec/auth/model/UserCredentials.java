@@ -0,0 +1,13 @@
+package com.kubesec.auth.model;
+
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record UserCredentials(
+        String userId,
+        UUID tenantId,
+        String email,
+        String passwordHash,
+        boolean emailVerified,
+        OffsetDateTime createdAt
+) {}
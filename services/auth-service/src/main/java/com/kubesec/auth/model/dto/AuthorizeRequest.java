@@ -0,0 +1,8 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record AuthorizeRequest(
+        @JsonProperty("code_challenge") String codeChallenge,
+        @JsonProperty("code_challenge_method") String codeChallengeMethod
+) {}
@@ -0,0 +1,12 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record LogoutAllEvent(
+        @JsonProperty("user_id") String userId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("sessions_invalidated") int sessionsInvalidated,
+        OffsetDateTime timestamp
+) {}
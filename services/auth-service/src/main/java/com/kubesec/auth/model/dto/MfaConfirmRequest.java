@@ -0,0 +1,7 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record MfaConfirmRequest(
+        @JsonProperty("totp_code") String totpCode
+) {}
@@ -0,0 +1,8 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record MfaVerifyRequest(
+        @JsonProperty("mfa_token") String mfaToken,
+        @JsonProperty("totp_code") String totpCode
+) {}
@@ -0,0 +1,8 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record RegisterDeviceRequest(
+        @JsonProperty("device_fingerprint") String deviceFingerprint,
+        @JsonProperty("device_name") String deviceName
+) {}
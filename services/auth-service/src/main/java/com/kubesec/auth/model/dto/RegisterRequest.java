@@ -0,0 +1,9 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record RegisterRequest(
+        String email,
+        String password,
+        @JsonProperty("full_name") String fullName
+) {}
@@ -0,0 +1,16 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+// The plain verification token travels only in this event, so the mailer can put it in
+// the verification link. auth-service keeps just its hash.
+public record RegistrationRequestedEvent(
+        @JsonProperty("user_id") String userId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        String email,
+        @JsonProperty("full_name") String fullName,
+        @JsonProperty("verification_token") String verificationToken,
+        @JsonProperty("expires_at") OffsetDateTime expiresAt
+) {}
@@ -0,0 +1,8 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record RegistrationResponse(
+        @JsonProperty("user_id") String userId,
+        @JsonProperty("requires_email_verification") boolean requiresEmailVerification
+) {}
@@ -0,0 +1,3 @@
+package com.kubesec.auth.model.dto;
+
+public record ServiceTokenRequest(String audience) {}
@@ -0,0 +1,17 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.UUID;
+
+public record SuspiciousLoginEvent(
+        @JsonProperty("user_id") String userId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        String email,
+        @JsonProperty("ip_address") String ipAddress,
+        @JsonProperty("ip_country") String ipCountry,
+        @JsonProperty("ip_city") String ipCity,
+        @JsonProperty("recent_countries") List<String> recentCountries,
+        OffsetDateTime timestamp
+) {}
@@ -0,0 +1,8 @@
+package com.kubesec.auth.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public record TokenRequest(
+        String code,
+        @JsonProperty("code_verifier") String codeVerifier
+) {}
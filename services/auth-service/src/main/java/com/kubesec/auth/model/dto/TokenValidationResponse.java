@@ -7,9 +7,10 @@ import com.fasterxml.jackson.annotation.JsonProperty;
 public record TokenValidationResponse(
         boolean valid,
         @JsonProperty("user_id") String userId,
-        String email
+        String email,
+        String role
 ) {
     public static TokenValidationResponse invalid() {
-        return new TokenValidationResponse(false, null, null);
+        return new TokenValidationResponse(false, null, null, null);
     }
 }
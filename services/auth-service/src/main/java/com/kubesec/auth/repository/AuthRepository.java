@@ -1,21 +1,67 @@
 package com.kubesec.auth.repository;
 
+import com.kubesec.auth.model.AuthCode;
+import com.kubesec.auth.model.EmailVerificationToken;
 import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.MfaSettings;
 import com.kubesec.auth.model.Session;
+import com.kubesec.auth.model.TokenInfo;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.UserCredentials;
 
 import java.time.Duration;
 import java.time.OffsetDateTime;
+import java.util.Collection;
+import java.util.List;
+import java.util.Optional;
+import java.util.UUID;
+import java.util.function.Consumer;
 
 public interface AuthRepository {
 
     // Session operations (PostgreSQL)
     void createSession(Session session);
+    void upsertSession(Session session);
     void deleteSession(String token);
-    void deleteSessionsByUserId(String userId);
+    int deleteSessionsByUserId(String userId);
+    List<String> getSessionTokensByUserId(String userId);
+    List<TokenInfo> listActiveTokens(String userId);
 
     // Login attempt operations (PostgreSQL)
     void recordLoginAttempt(LoginAttempt attempt);
     int getRecentFailedAttempts(String email, OffsetDateTime since);
+    List<String> getRecentLoginCountries(String email, int limit);
+    List<LoginAttempt> listLoginAttempts(LoginAttemptAdminFilter filter);
+    int countLoginAttempts(LoginAttemptAdminFilter filter);
+    void streamLoginAttempts(LoginAttemptAdminFilter filter, Consumer<LoginAttempt> consumer);
+    void streamLoginAttemptsBefore(OffsetDateTime cutoff, Consumer<LoginAttempt> consumer);
+    int deleteLoginAttemptsBefore(OffsetDateTime cutoff, int batchSize);
+
+    // PKCE authorization codes (PostgreSQL)
+    void createAuthCode(AuthCode authCode);
+    Optional<AuthCode> consumeAuthCode(String code);
+
+    // Trusted devices (PostgreSQL)
+    TrustedDevice registerTrustedDevice(TrustedDevice device);
+    boolean isTrustedDevice(String userId, String fingerprint);
+    List<TrustedDevice> listTrustedDevices(String userId);
+    boolean deleteTrustedDevice(UUID id, String userId);
+
+    // TOTP second factor (PostgreSQL)
+    Optional<MfaSettings> getTotpSecret(String userId);
+    // Stores a new, not yet enabled secret, replacing any unconfirmed one.
+    void setTotpSecret(String userId, String secret);
+    boolean enableMfa(String userId);
+    boolean disableMfa(String userId);
+    // False if a code from this step or a later one was already accepted.
+    boolean useTotpStep(String userId, long step);
+
+    // Self-registration (PostgreSQL)
+    void createUserCredentials(UserCredentials credentials);
+    Optional<UserCredentials> getUserCredentialsByEmail(String email);
+    Optional<String> getUserRole(String userId);
+    void createEmailVerificationToken(EmailVerificationToken token);
 
     // Token blacklist (Redis)
     void blacklistToken(String token, Duration expiry);
@@ -24,4 +70,6 @@ public interface AuthRepository {
     // Session cache (Redis)
     void cacheSession(String token, String userId, Duration expiry);
     void invalidateCachedSession(String token);
+    // Scans the cache rather than deleting keys blind, so it reports what was actually cached.
+    int invalidateCachedSessions(Collection<String> tokens);
 }
@@ -1,26 +1,56 @@
 package com.kubesec.auth.repository;
 
+import com.kubesec.auth.model.AuthCode;
+import com.kubesec.auth.model.EmailVerificationToken;
 import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.MfaSettings;
 import com.kubesec.auth.model.Session;
+import com.kubesec.auth.model.TokenInfo;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.tenant.TenantContext;
+import org.springframework.data.redis.core.ScanOptions;
 import org.springframework.data.redis.core.StringRedisTemplate;
 import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.core.RowCallbackHandler;
 import org.springframework.stereotype.Repository;
+import org.springframework.transaction.annotation.Transactional;
 
+import java.sql.PreparedStatement;
+import java.sql.ResultSet;
+import java.sql.SQLException;
 import java.time.Duration;
 import java.time.OffsetDateTime;
+import java.util.ArrayList;
+import java.util.Collection;
+import java.util.HashSet;
+import java.util.List;
+import java.util.Optional;
+import java.util.Set;
+import java.util.UUID;
+import java.util.function.Consumer;
 
 @Repository
 public class AuthRepositoryImpl implements AuthRepository {
 
     private static final String BLACKLIST_PREFIX = "blacklist:";
     private static final String SESSION_CACHE_PREFIX = "session:";
+    private static final int SCAN_BATCH_SIZE = 500;
+
+    private static final String LOGIN_ATTEMPT_COLUMNS =
+            "id, tenant_id, email, user_id, success, ip_address, ip_country, ip_city, user_agent, created_at";
+
+    private static final String TRUSTED_DEVICE_COLUMNS =
+            "id, tenant_id, user_id, device_fingerprint, device_name, trusted_at, expires_at";
 
     private final JdbcTemplate jdbc;
     private final StringRedisTemplate redis;
 
-    public AuthRepositoryImpl(JdbcTemplate jdbc, StringRedisTemplate redis) {
+    public AuthRepositoryImpl(JdbcTemplate jdbc, StringRedisTemplate redis, SchemaReadiness schemaReadiness) {
         this.jdbc = jdbc;
         this.redis = redis;
+        schemaReadiness.await("sessions");
     }
 
     // --- Session operations (PostgreSQL) ---
@@ -28,20 +58,63 @@ public class AuthRepositoryImpl implements AuthRepository {
     @Override
     public void createSession(Session session) {
         jdbc.update(
-                "INSERT INTO sessions (id, user_id, token, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
-                session.getId(), session.getUserId(), session.getToken(),
+                "INSERT INTO sessions (id, tenant_id, user_id, token, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+                session.getId(), session.getTenantId(), session.getUserId(), session.getToken(),
                 session.getExpiresAt(), session.getCreatedAt()
         );
     }
 
+    // Concurrent logins from the same device converge on one row: the loser of the
+    // insert race updates the winner's token instead of failing.
+    @Override
+    public void upsertSession(Session session) {
+        jdbc.update(
+                "INSERT INTO sessions (id, tenant_id, user_id, device_fingerprint, token, expires_at, created_at, updated_at) "
+                        + "VALUES (?, ?, ?, ?, ?, ?, ?, ?) "
+                        + "ON CONFLICT (tenant_id, user_id, device_fingerprint) "
+                        + "DO UPDATE SET token = EXCLUDED.token, expires_at = EXCLUDED.expires_at, updated_at = NOW()",
+                session.getId(), session.getTenantId(), session.getUserId(), session.getDeviceFingerprint(),
+                session.getToken(), session.getExpiresAt(), session.getCreatedAt(), session.getUpdatedAt()
+        );
+    }
+
+    // Sessions don't store where they were opened from, so each is matched to the
+    // user's successful login recorded within a second of its latest token being
+    // issued. A session with no such login still lists, without IP or user agent.
+    @Override
+    public List<TokenInfo> listActiveTokens(String userId) {
+        return jdbc.query(
+                "SELECT s.id, s.created_at, s.expires_at, la.ip_address, la.user_agent FROM sessions s"
+                        + " LEFT JOIN LATERAL (SELECT ip_address, user_agent FROM login_attempts a"
+                        + " WHERE a.tenant_id = s.tenant_id AND a.user_id = s.user_id AND a.success"
+                        + " AND a.created_at BETWEEN s.updated_at - INTERVAL '1 second' AND s.updated_at + INTERVAL '1 second'"
+                        + " ORDER BY ABS(EXTRACT(EPOCH FROM a.created_at - s.updated_at)) LIMIT 1) la ON TRUE"
+                        + " WHERE s.tenant_id = ? AND s.user_id = ? AND s.expires_at > NOW()"
+                        + " ORDER BY s.created_at DESC",
+                (rs, rowNum) -> new TokenInfo(
+                        rs.getString("id"),
+                        rs.getObject("created_at", OffsetDateTime.class),
+                        rs.getObject("expires_at", OffsetDateTime.class),
+                        rs.getString("ip_address"),
+                        rs.getString("user_agent")),
+                TenantContext.require(), userId
+        );
+    }
+
     @Override
     public void deleteSession(String token) {
-        jdbc.update("DELETE FROM sessions WHERE token = ?", token);
+        jdbc.update("DELETE FROM sessions WHERE token = ? AND tenant_id = ?", token, TenantContext.require());
+    }
+
+    @Override
+    public int deleteSessionsByUserId(String userId) {
+        return jdbc.update("DELETE FROM sessions WHERE user_id = ? AND tenant_id = ?", userId, TenantContext.require());
     }
 
     @Override
-    public void deleteSessionsByUserId(String userId) {
-        jdbc.update("DELETE FROM sessions WHERE user_id = ?", userId);
+    public List<String> getSessionTokensByUserId(String userId) {
+        return jdbc.queryForList("SELECT token FROM sessions WHERE user_id = ? AND tenant_id = ?",
+                String.class, userId, TenantContext.require());
     }
 
     // --- Login attempt operations (PostgreSQL) ---
@@ -49,21 +122,343 @@ public class AuthRepositoryImpl implements AuthRepository {
     @Override
     public void recordLoginAttempt(LoginAttempt attempt) {
         jdbc.update(
-                "INSERT INTO login_attempts (id, email, success, ip_address, created_at) VALUES (?, ?, ?, ?, ?)",
-                attempt.id(), attempt.email(), attempt.success(),
-                attempt.ipAddress(), attempt.createdAt()
+                "INSERT INTO login_attempts (" + LOGIN_ATTEMPT_COLUMNS + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+                attempt.id(), attempt.tenantId(), attempt.email(), attempt.userId(), attempt.success(),
+                attempt.ipAddress(), attempt.ipCountry(), attempt.ipCity(), attempt.userAgent(), attempt.createdAt()
         );
     }
 
     @Override
     public int getRecentFailedAttempts(String email, OffsetDateTime since) {
         Integer count = jdbc.queryForObject(
-                "SELECT COUNT(*) FROM login_attempts WHERE email = ? AND success = false AND created_at > ?",
-                Integer.class, email, since
+                "SELECT COUNT(*) FROM login_attempts WHERE email = ? AND tenant_id = ? AND success = false AND created_at > ?",
+                Integer.class, email, TenantContext.require(), since
         );
         return count != null ? count : 0;
     }
 
+    // Distinct countries of the most recent successful logins, newest first.
+    @Override
+    public List<String> getRecentLoginCountries(String email, int limit) {
+        return jdbc.queryForList(
+                "SELECT ip_country FROM (SELECT ip_country, MAX(created_at) AS last_seen FROM ("
+                        + "SELECT ip_country, created_at FROM login_attempts"
+                        + " WHERE email = ? AND tenant_id = ? AND success AND ip_country IS NOT NULL"
+                        + " ORDER BY created_at DESC LIMIT ?) recent"
+                        + " GROUP BY ip_country) countries ORDER BY last_seen DESC",
+                String.class, email, TenantContext.require(), limit
+        );
+    }
+
+    @Override
+    public List<LoginAttempt> listLoginAttempts(LoginAttemptAdminFilter filter) {
+        StringBuilder query = new StringBuilder(
+                "SELECT " + LOGIN_ATTEMPT_COLUMNS + " FROM login_attempts WHERE tenant_id = ?"
+        );
+        List<Object> args = new ArrayList<>();
+        args.add(TenantContext.require());
+        appendFilter(query, args, filter);
+
+        if (filter.getCursor() != null) {
+            query.append(" AND (created_at, id) < (?, ?)");
+            args.add(filter.getCursor().createdAt());
+            args.add(filter.getCursor().id());
+        }
+
+        // id breaks ties between attempts recorded in the same instant so pages never overlap
+        query.append(" ORDER BY created_at DESC, id DESC");
+
+        if (filter.getLimit() > 0) {
+            query.append(" LIMIT ?");
+            args.add(filter.getLimit());
+        }
+
+        if (filter.getCursor() == null && filter.getOffset() > 0) {
+            query.append(" OFFSET ?");
+            args.add(filter.getOffset());
+        }
+
+        return jdbc.query(query.toString(), this::mapLoginAttempt, args.toArray());
+    }
+
+    @Override
+    public int countLoginAttempts(LoginAttemptAdminFilter filter) {
+        StringBuilder query = new StringBuilder("SELECT COUNT(*) FROM login_attempts WHERE tenant_id = ?");
+        List<Object> args = new ArrayList<>();
+        args.add(TenantContext.require());
+        appendFilter(query, args, filter);
+
+        Integer count = jdbc.queryForObject(query.toString(), Integer.class, args.toArray());
+        return count != null ? count : 0;
+    }
+
+    // Archival spans every tenant. The fetch size inside a transaction makes the driver
+    // use a cursor instead of materialising the whole result set.
+    @Override
+    @Transactional(readOnly = true)
+    public void streamLoginAttemptsBefore(OffsetDateTime cutoff, Consumer<LoginAttempt> consumer) {
+        jdbc.query(con -> {
+            PreparedStatement ps = con.prepareStatement(
+                    "SELECT " + LOGIN_ATTEMPT_COLUMNS + " FROM login_attempts WHERE created_at < ? ORDER BY created_at");
+            ps.setFetchSize(1000);
+            ps.setObject(1, cutoff);
+            return ps;
+        }, (RowCallbackHandler) rs -> consumer.accept(mapLoginAttempt(rs, 0)));
+    }
+
+    // Oldest first; limit, offset and cursor are ignored. Streamed like
+    // streamLoginAttemptsBefore.
+    @Override
+    @Transactional(readOnly = true)
+    public void streamLoginAttempts(LoginAttemptAdminFilter filter, Consumer<LoginAttempt> consumer) {
+        StringBuilder query = new StringBuilder(
+                "SELECT " + LOGIN_ATTEMPT_COLUMNS + " FROM login_attempts WHERE tenant_id = ?"
+        );
+        List<Object> args = new ArrayList<>();
+        args.add(TenantContext.require());
+        appendFilter(query, args, filter);
+        query.append(" ORDER BY created_at, id");
+
+        jdbc.query(con -> {
+            PreparedStatement ps = con.prepareStatement(query.toString());
+            ps.setFetchSize(1000);
+            for (int i = 0; i < args.size(); i++) {
+                ps.setObject(i + 1, args.get(i));
+            }
+            return ps;
+        }, (RowCallbackHandler) rs -> consumer.accept(mapLoginAttempt(rs, 0)));
+    }
+
+    @Override
+    public int deleteLoginAttemptsBefore(OffsetDateTime cutoff, int batchSize) {
+        return jdbc.update(
+                "DELETE FROM login_attempts WHERE id IN (SELECT id FROM login_attempts WHERE created_at < ? LIMIT ?)",
+                cutoff, batchSize
+        );
+    }
+
+    private void appendFilter(StringBuilder query, List<Object> args, LoginAttemptAdminFilter filter) {
+        if (filter.getIpAddress() != null && !filter.getIpAddress().isEmpty()) {
+            query.append(" AND ip_address = ?");
+            args.add(filter.getIpAddress());
+        }
+
+        if (filter.getEmail() != null && !filter.getEmail().isEmpty()) {
+            query.append(" AND email = ?");
+            args.add(filter.getEmail());
+        }
+
+        if (filter.getSuccess() != null) {
+            query.append(" AND success = ?");
+            args.add(filter.getSuccess());
+        }
+
+        if (filter.getFrom() != null) {
+            query.append(" AND created_at >= ?");
+            args.add(filter.getFrom());
+        }
+
+        if (filter.getTo() != null) {
+            query.append(" AND created_at < ?");
+            args.add(filter.getTo());
+        }
+    }
+
+    private LoginAttempt mapLoginAttempt(ResultSet rs, int rowNum) throws SQLException {
+        return new LoginAttempt(
+                rs.getString("id"),
+                rs.getObject("tenant_id", UUID.class),
+                rs.getString("email"),
+                rs.getString("user_id"),
+                rs.getBoolean("success"),
+                rs.getString("ip_address"),
+                rs.getString("ip_country"),
+                rs.getString("ip_city"),
+                rs.getString("user_agent"),
+                rs.getObject("created_at", OffsetDateTime.class)
+        );
+    }
+
+    // --- PKCE authorization codes (PostgreSQL) ---
+
+    @Override
+    public void createAuthCode(AuthCode authCode) {
+        jdbc.update(
+                "INSERT INTO auth_codes (code, tenant_id, user_id, email, code_challenge, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+                authCode.code(), authCode.tenantId(), authCode.userId(), authCode.email(),
+                authCode.codeChallenge(), authCode.expiresAt()
+        );
+    }
+
+    @Override
+    public Optional<AuthCode> consumeAuthCode(String code) {
+        // DELETE ... RETURNING makes redemption single-use even under concurrent requests
+        List<AuthCode> codes = jdbc.query(
+                "DELETE FROM auth_codes WHERE code = ? AND tenant_id = ? RETURNING code, tenant_id, user_id, email, code_challenge, expires_at",
+                (rs, rowNum) -> new AuthCode(
+                        rs.getString("code"),
+                        rs.getObject("tenant_id", UUID.class),
+                        rs.getString("user_id"),
+                        rs.getString("email"),
+                        rs.getString("code_challenge"),
+                        rs.getObject("expires_at", OffsetDateTime.class)
+                ),
+                code, TenantContext.require()
+        );
+        return codes.stream().findFirst();
+    }
+
+    // --- Trusted devices (PostgreSQL) ---
+
+    @Override
+    public TrustedDevice registerTrustedDevice(TrustedDevice device) {
+        // Re-trusting a known device keeps its id and extends the trust window
+        return jdbc.queryForObject(
+                "INSERT INTO trusted_devices (" + TRUSTED_DEVICE_COLUMNS + ") VALUES (?, ?, ?, ?, ?, ?, ?)"
+                        + " ON CONFLICT (tenant_id, user_id, device_fingerprint) DO UPDATE SET"
+                        + " device_name = EXCLUDED.device_name, trusted_at = EXCLUDED.trusted_at, expires_at = EXCLUDED.expires_at"
+                        + " RETURNING " + TRUSTED_DEVICE_COLUMNS,
+                this::mapTrustedDevice,
+                device.id(), device.tenantId(), device.userId(), device.deviceFingerprint(),
+                device.deviceName(), device.trustedAt(), device.expiresAt()
+        );
+    }
+
+    @Override
+    public boolean isTrustedDevice(String userId, String fingerprint) {
+        Boolean trusted = jdbc.queryForObject(
+                "SELECT EXISTS (SELECT 1 FROM trusted_devices WHERE tenant_id = ? AND user_id = ?"
+                        + " AND device_fingerprint = ? AND expires_at > NOW())",
+                Boolean.class, TenantContext.require(), userId, fingerprint
+        );
+        return Boolean.TRUE.equals(trusted);
+    }
+
+    @Override
+    public List<TrustedDevice> listTrustedDevices(String userId) {
+        return jdbc.query(
+                "SELECT " + TRUSTED_DEVICE_COLUMNS + " FROM trusted_devices WHERE tenant_id = ? AND user_id = ?"
+                        + " AND expires_at > NOW() ORDER BY trusted_at DESC",
+                this::mapTrustedDevice, TenantContext.require(), userId
+        );
+    }
+
+    @Override
+    public boolean deleteTrustedDevice(UUID id, String userId) {
+        int rows = jdbc.update(
+                "DELETE FROM trusted_devices WHERE id = ? AND tenant_id = ? AND user_id = ?",
+                id, TenantContext.require(), userId
+        );
+        return rows > 0;
+    }
+
+    private TrustedDevice mapTrustedDevice(ResultSet rs, int rowNum) throws SQLException {
+        return new TrustedDevice(
+                rs.getObject("id", UUID.class),
+                rs.getObject("tenant_id", UUID.class),
+                rs.getString("user_id"),
+                rs.getString("device_fingerprint"),
+                rs.getString("device_name"),
+                rs.getObject("trusted_at", OffsetDateTime.class),
+                rs.getObject("expires_at", OffsetDateTime.class)
+        );
+    }
+
+    // --- TOTP second factor (PostgreSQL) ---
+
+    @Override
+    public Optional<MfaSettings> getTotpSecret(String userId) {
+        return jdbc.query(
+                "SELECT totp_secret, mfa_enabled FROM user_mfa WHERE tenant_id = ? AND user_id = ?",
+                (rs, rowNum) -> new MfaSettings(rs.getString("totp_secret"), rs.getBoolean("mfa_enabled")),
+                TenantContext.require(), userId
+        ).stream().findFirst();
+    }
+
+    // An enabled secret is never overwritten; MFA has to be disabled first.
+    @Override
+    public void setTotpSecret(String userId, String secret) {
+        jdbc.update(
+                "INSERT INTO user_mfa (tenant_id, user_id, totp_secret) VALUES (?, ?, ?)"
+                        + " ON CONFLICT (tenant_id, user_id) DO UPDATE SET totp_secret = EXCLUDED.totp_secret,"
+                        + " last_used_step = NULL, updated_at = NOW() WHERE user_mfa.mfa_enabled = FALSE",
+                TenantContext.require(), userId, secret
+        );
+    }
+
+    @Override
+    public boolean enableMfa(String userId) {
+        return jdbc.update(
+                "UPDATE user_mfa SET mfa_enabled = TRUE, updated_at = NOW() WHERE tenant_id = ? AND user_id = ?",
+                TenantContext.require(), userId
+        ) > 0;
+    }
+
+    @Override
+    public boolean disableMfa(String userId) {
+        return jdbc.update(
+                "DELETE FROM user_mfa WHERE tenant_id = ? AND user_id = ?",
+                TenantContext.require(), userId
+        ) > 0;
+    }
+
+    @Override
+    public boolean useTotpStep(String userId, long step) {
+        return jdbc.update(
+                "UPDATE user_mfa SET last_used_step = ?, updated_at = NOW() WHERE tenant_id = ? AND user_id = ?"
+                        + " AND (last_used_step IS NULL OR last_used_step < ?)",
+                step, TenantContext.require(), userId, step
+        ) > 0;
+    }
+
+    // --- Self-registration ---
+
+    @Override
+    public void createUserCredentials(UserCredentials credentials) {
+        jdbc.update(
+                "INSERT INTO user_credentials (user_id, tenant_id, email, password_hash, email_verified, created_at, updated_at)"
+                        + " VALUES (?, ?, ?, ?, ?, ?, ?)",
+                credentials.userId(), credentials.tenantId(), credentials.email(), credentials.passwordHash(),
+                credentials.emailVerified(), credentials.createdAt(), credentials.createdAt()
+        );
+    }
+
+    @Override
+    public Optional<UserCredentials> getUserCredentialsByEmail(String email) {
+        List<UserCredentials> rows = jdbc.query(
+                "SELECT user_id, tenant_id, email, password_hash, email_verified, created_at FROM user_credentials"
+                        + " WHERE tenant_id = ? AND email = ?",
+                (rs, rowNum) -> new UserCredentials(
+                        rs.getString("user_id"),
+                        rs.getObject("tenant_id", UUID.class),
+                        rs.getString("email"),
+                        rs.getString("password_hash"),
+                        rs.getBoolean("email_verified"),
+                        rs.getObject("created_at", OffsetDateTime.class)
+                ),
+                TenantContext.require(), email
+        );
+        return rows.stream().findFirst();
+    }
+
+    @Override
+    public Optional<String> getUserRole(String userId) {
+        List<String> rows = jdbc.queryForList(
+                "SELECT role FROM user_credentials WHERE tenant_id = ? AND user_id = ?",
+                String.class, TenantContext.require(), userId
+        );
+        return rows.stream().findFirst();
+    }
+
+    @Override
+    public void createEmailVerificationToken(EmailVerificationToken token) {
+        jdbc.update(
+                "INSERT INTO email_verification_tokens (token_hash, tenant_id, user_id, expires_at, created_at)"
+                        + " VALUES (?, ?, ?, ?, ?)",
+                token.tokenHash(), token.tenantId(), token.userId(), token.expiresAt(), token.createdAt()
+        );
+    }
+
     // --- Token blacklist (Redis) ---
 
     @Override
@@ -87,4 +482,27 @@ public class AuthRepositoryImpl implements AuthRepository {
     public void invalidateCachedSession(String token) {
         redis.delete(SESSION_CACHE_PREFIX + token);
     }
+
+    @Override
+    public int invalidateCachedSessions(Collection<String> tokens) {
+        if (tokens.isEmpty()) {
+            return 0;
+        }
+        Set<String> wanted = new HashSet<>();
+        tokens.forEach(token -> wanted.add(SESSION_CACHE_PREFIX + token));
+        List<String> keys = new ArrayList<>();
+        ScanOptions options = ScanOptions.scanOptions().match(SESSION_CACHE_PREFIX + "*").count(SCAN_BATCH_SIZE).build();
+        try (var cursor = redis.scan(options)) {
+            cursor.forEachRemaining(key -> {
+                if (wanted.contains(key)) {
+                    keys.add(key);
+                }
+            });
+        }
+        if (keys.isEmpty()) {
+            return 0;
+        }
+        Long deleted = redis.delete(keys);
+        return deleted == null ? 0 : deleted.intValue();
+    }
 }
@@ -0,0 +1,67 @@
+package com.kubesec.auth.repository;
+
+import com.kubesec.auth.config.AppConfig;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.dao.DataAccessException;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.stereotype.Component;
+
+import java.time.Duration;
+
+// Repositories wait here until their table is reachable, so a pod started before the
+// database or its migrations retries with exponential backoff instead of failing its
+// first requests.
+@Component
+public class SchemaReadiness {
+
+    private static final Logger log = LoggerFactory.getLogger(SchemaReadiness.class);
+    private static final Duration MAX_BACKOFF = Duration.ofSeconds(30);
+
+    private final JdbcTemplate jdbc;
+    private final AppConfig config;
+
+    public SchemaReadiness(JdbcTemplate jdbc, AppConfig config) {
+        this.jdbc = jdbc;
+        this.config = config;
+    }
+
+    public void await(String table) {
+        int attempts = config.getDbSchemaRetryAttempts();
+        if (attempts <= 0) {
+            return;
+        }
+
+        Duration backoff = config.getDbSchemaRetryBackoff();
+        for (int attempt = 1; ; attempt++) {
+            try {
+                verify(table);
+                return;
+            } catch (SchemaNotReadyException e) {
+                if (attempt >= attempts) {
+                    throw e;
+                }
+                log.warn("{} (attempt {}/{}), retrying in {}", e.getMessage(), attempt, attempts, backoff);
+                sleep(backoff);
+                backoff = backoff.multipliedBy(2).compareTo(MAX_BACKOFF) > 0 ? MAX_BACKOFF : backoff.multipliedBy(2);
+            }
+        }
+    }
+
+    void verify(String table) {
+        try {
+            jdbc.queryForList("SELECT 1 FROM " + table + " LIMIT 1");
+        } catch (DataAccessException e) {
+            throw new SchemaNotReadyException(table, e);
+        }
+    }
+
+    private static void sleep(Duration duration) {
+        try {
+            Thread.sleep(duration.toMillis());
+        } catch (InterruptedException e) {
+            Thread.currentThread().interrupt();
+            throw new IllegalStateException("interrupted while waiting for the database schema", e);
+        }
+    }
+}
@@ -0,0 +1,8 @@
+package com.kubesec.auth.repository;
+
+import java.util.UUID;
+
+public interface TenantRepository {
+
+    boolean isActive(UUID tenantId);
+}
@@ -1,34 +1,84 @@
 package com.kubesec.auth.service;
 
+import com.kubesec.auth.exception.ConflictException;
+import com.kubesec.auth.exception.RateLimitedException;
+import com.kubesec.auth.exception.ResourceNotFoundException;
+import com.kubesec.auth.exception.UnauthorizedException;
+import com.kubesec.auth.exception.ValidationException;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.geoip.GeoLocation;
+import com.kubesec.auth.model.AuthCode;
+import com.kubesec.auth.model.Cursor;
 import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.LoginAttemptPage;
+import com.kubesec.auth.model.LoginResult;
+import com.kubesec.auth.model.MfaEnrollment;
+import com.kubesec.auth.model.MfaSettings;
 import com.kubesec.auth.model.Session;
+import com.kubesec.auth.model.TokenInfo;
 import com.kubesec.auth.model.TokenPair;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.model.dto.LogoutAllEvent;
+import com.kubesec.auth.model.dto.SuspiciousLoginEvent;
 import com.kubesec.auth.model.dto.TokenValidationResponse;
 import com.kubesec.auth.repository.AuthRepository;
+import com.kubesec.auth.tenant.TenantContext;
 import io.jsonwebtoken.Claims;
 import io.jsonwebtoken.JwtException;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
+import org.springframework.lang.Nullable;
 import org.springframework.stereotype.Service;
 
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.security.SecureRandom;
+import java.time.Duration;
+import java.time.Instant;
 import java.time.OffsetDateTime;
 import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Base64;
+import java.util.List;
+import java.util.Locale;
+import java.util.Optional;
+import java.util.OptionalLong;
 import java.util.UUID;
+import java.util.function.Consumer;
 
 @Service
 public class AuthService {
 
     private static final Logger log = LoggerFactory.getLogger(AuthService.class);
+    private static final Duration AUTH_CODE_EXPIRY = Duration.ofMinutes(5);
+    private static final Duration TRUSTED_DEVICE_EXPIRY = Duration.ofDays(30);
+    private static final int RECENT_LOGIN_COUNTRIES = 3;
+    private static final String TOTP_ISSUER = "KubeSecBank";
+    private static final String UNKNOWN_USER_HASH = PasswordHasher.hash(UUID.randomUUID().toString());
+
+    private final SecureRandom random = new SecureRandom();
 
     private final AuthRepository repository;
     private final JwtService jwtService;
+    private final GeoIpLookup geoIpLookup;
+    private final SuspiciousLoginPublisher suspiciousLoginPublisher;
+    private final LogoutAllPublisher logoutAllPublisher;
 
-    public AuthService(AuthRepository repository, JwtService jwtService) {
+    public AuthService(AuthRepository repository, JwtService jwtService, GeoIpLookup geoIpLookup,
+                       @Nullable SuspiciousLoginPublisher suspiciousLoginPublisher,
+                       @Nullable LogoutAllPublisher logoutAllPublisher) {
         this.repository = repository;
         this.jwtService = jwtService;
+        this.geoIpLookup = geoIpLookup;
+        this.suspiciousLoginPublisher = suspiciousLoginPublisher;
+        this.logoutAllPublisher = logoutAllPublisher;
     }
 
-    public TokenPair login(String email, String password, String ipAddress) {
+    public LoginResult login(String email, String password, String deviceFingerprint, String ipAddress,
+                             String userAgent) {
         OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
 
         // Check for brute-force attempts
@@ -37,16 +87,30 @@ public class AuthService {
             throw new RateLimitedException("too many failed login attempts, try again later");
         }
 
-        // TODO: Replace with real user lookup via account-service
-        String userId = "user-" + email;
-        boolean authenticated = true;
+        // An unknown email is still checked against a hash, so the response time doesn't
+        // reveal which emails are registered.
+        Optional<UserCredentials> credentials = repository.getUserCredentialsByEmail(email.trim().toLowerCase(Locale.ROOT));
+        boolean passwordMatches = PasswordHasher.verify(password,
+                credentials.map(UserCredentials::passwordHash).orElse(UNKNOWN_USER_HASH));
+        boolean authenticated = credentials.isPresent() && passwordMatches;
+        String userId = credentials.map(UserCredentials::userId).orElse(null);
+
+        Optional<GeoLocation> location = geoIpLookup.lookup(ipAddress);
+        if (authenticated && location.isPresent()) {
+            checkLoginLocation(userId, email, ipAddress, location.get(), now);
+        }
 
         // Record login attempt
         LoginAttempt attempt = new LoginAttempt(
                 UUID.randomUUID().toString(),
+                TenantContext.require(),
                 email,
+                authenticated ? userId : null,
                 authenticated,
                 ipAddress,
+                location.map(GeoLocation::country).orElse(null),
+                location.map(GeoLocation::city).orElse(null),
+                userAgent,
                 now
         );
         try {
@@ -56,29 +120,184 @@ public class AuthService {
         }
 
         if (!authenticated) {
-            throw new AuthenticationException("invalid credentials");
+            throw new UnauthorizedException("invalid credentials");
         }
 
-        // Issue tokens
-        TokenPair tokenPair = jwtService.issueTokens(userId, email);
+        // Users with MFA enabled get a short-lived mfa token instead of a session, to be
+        // exchanged at verifyMfa with a TOTP code. Trusted devices skip that step.
+        boolean mfaEnabled = repository.getTotpSecret(userId).map(MfaSettings::enabled).orElse(false);
+        if (mfaEnabled) {
+            boolean trustedDevice = deviceFingerprint != null && repository.isTrustedDevice(userId, deviceFingerprint);
+            if (!trustedDevice) {
+                return LoginResult.mfaRequired(
+                        jwtService.issueMfaToken(userId, email, TenantContext.require(), deviceFingerprint));
+            }
+            log.info("user {} logged in from a trusted device, skipping MFA", userId);
+        }
 
-        // Persist session
-        Session session = new Session(
-                UUID.randomUUID().toString(),
-                userId,
-                tokenPair.accessToken(),
-                now.plus(jwtService.getAccessTokenExpiry()),
-                now
-        );
+        return LoginResult.authenticated(startSession(userId, email, deviceFingerprint));
+    }
+
+    public TokenPair verifyMfa(String mfaToken, String totpCode, String ipAddress, String userAgent) {
+        if (repository.isTokenBlacklisted(mfaToken)) {
+            throw new UnauthorizedException("mfa token has already been used");
+        }
+        Claims claims;
         try {
-            repository.createSession(session);
-            repository.cacheSession(session.getToken(), session.getUserId(), jwtService.getAccessTokenExpiry());
+            claims = jwtService.parseMfaToken(mfaToken);
+        } catch (JwtException e) {
+            throw new UnauthorizedException("invalid or expired mfa token");
+        }
+        if (!jwtService.isIssuedFor(claims, TenantContext.require())) {
+            throw new UnauthorizedException("token was issued for a different tenant");
+        }
+        String userId = claims.get("user_id", String.class);
+        String email = claims.get("email", String.class);
+
+        // Wrong codes count towards the same lockout as wrong passwords, so the six
+        // digits can't be brute-forced within the token's lifetime.
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        if (repository.getRecentFailedAttempts(email, now.minusMinutes(15)) >= 5) {
+            throw new RateLimitedException("too many failed login attempts, try again later");
+        }
+
+        MfaSettings settings = repository.getTotpSecret(userId)
+                .filter(MfaSettings::enabled)
+                .orElseThrow(() -> new UnauthorizedException("mfa is not enabled"));
+        OptionalLong step = Totp.verify(settings.totpSecret(), totpCode, now.toInstant());
+        if (step.isEmpty() || !repository.useTotpStep(userId, step.getAsLong())) {
+            try {
+                repository.recordLoginAttempt(new LoginAttempt(UUID.randomUUID().toString(), TenantContext.require(),
+                        email, null, false, ipAddress, null, null, userAgent, now));
+            } catch (Exception e) {
+                log.error("error recording login attempt: {}", e.getMessage());
+            }
+            throw new UnauthorizedException("invalid totp code");
+        }
+
+        repository.blacklistToken(mfaToken, jwtService.getMfaTokenExpiry());
+        return startSession(userId, email, claims.get("device_fingerprint", String.class));
+    }
+
+    // Stores a fresh secret that only takes effect once confirmMfa sees a valid code
+    // from it, so a half-finished enrollment can't lock the user out.
+    public MfaEnrollment enrollMfa(String userId, String email) {
+        if (repository.getTotpSecret(userId).map(MfaSettings::enabled).orElse(false)) {
+            throw new ConflictException("mfa is already enabled");
+        }
+        String secret = Totp.generateSecret();
+        repository.setTotpSecret(userId, secret);
+        return new MfaEnrollment(secret, Totp.provisioningUri(TOTP_ISSUER, email, secret));
+    }
+
+    public void confirmMfa(String userId, String totpCode) {
+        MfaSettings settings = repository.getTotpSecret(userId)
+                .orElseThrow(() -> new ResourceNotFoundException("no mfa enrollment in progress"));
+        if (settings.enabled()) {
+            throw new ConflictException("mfa is already enabled");
+        }
+        OptionalLong step = Totp.verify(settings.totpSecret(), totpCode, Instant.now());
+        if (step.isEmpty() || !repository.useTotpStep(userId, step.getAsLong())) {
+            throw new ValidationException("invalid totp code");
+        }
+        repository.enableMfa(userId);
+        log.info("user {} enabled mfa", userId);
+    }
+
+    public void disableMfa(String userId) {
+        if (!repository.disableMfa(userId)) {
+            throw new ResourceNotFoundException("mfa is not enabled");
+        }
+        log.info("user {} disabled mfa", userId);
+    }
+
+    // Flags a login from a country none of the user's recent logins came from. A user
+    // with no located logins yet has nothing to compare against and is not flagged.
+    private void checkLoginLocation(String userId, String email, String ipAddress, GeoLocation location,
+                                    OffsetDateTime now) {
+        List<String> recentCountries;
+        try {
+            recentCountries = repository.getRecentLoginCountries(email, RECENT_LOGIN_COUNTRIES);
         } catch (Exception e) {
-            log.error("error creating session: {}", e.getMessage());
-            throw new RuntimeException("failed to create session");
+            log.error("error loading recent login countries: {}", e.getMessage());
+            return;
+        }
+        if (recentCountries.isEmpty() || recentCountries.contains(location.country())) {
+            return;
         }
 
-        return tokenPair;
+        log.warn("suspicious login for user {} from {} (recent: {})", userId, location.country(), recentCountries);
+        if (suspiciousLoginPublisher != null) {
+            suspiciousLoginPublisher.publish(new SuspiciousLoginEvent(
+                    userId, TenantContext.require(), email, ipAddress,
+                    location.country(), location.city(), recentCountries, now));
+        }
+    }
+
+    // The request schema restricts code_challenge_method to S256 and the challenge to a
+    // base64url-encoded SHA-256 digest.
+    public AuthCode authorize(String userId, String email, String codeChallenge) {
+        byte[] bytes = new byte[32];
+        random.nextBytes(bytes);
+        AuthCode authCode = new AuthCode(
+                Base64.getUrlEncoder().withoutPadding().encodeToString(bytes),
+                TenantContext.require(),
+                userId,
+                email,
+                codeChallenge,
+                OffsetDateTime.now(ZoneOffset.UTC).plus(AUTH_CODE_EXPIRY)
+        );
+        repository.createAuthCode(authCode);
+        return authCode;
+    }
+
+    public TokenPair exchangeCode(String code, String codeVerifier) {
+        // The code is deleted on lookup, so a replayed code is simply not found
+        AuthCode authCode = repository.consumeAuthCode(code)
+                .orElseThrow(() -> new UnauthorizedException("invalid authorization code"));
+
+        if (authCode.expiresAt().isBefore(OffsetDateTime.now(ZoneOffset.UTC))) {
+            throw new UnauthorizedException("authorization code expired");
+        }
+
+        byte[] expected = authCode.codeChallenge().getBytes(StandardCharsets.US_ASCII);
+        byte[] actual = s256(codeVerifier).getBytes(StandardCharsets.US_ASCII);
+        if (!MessageDigest.isEqual(expected, actual)) {
+            throw new UnauthorizedException("code_verifier does not match code_challenge");
+        }
+
+        return startSession(authCode.userId(), authCode.email(), null);
+    }
+
+    public TrustedDevice registerTrustedDevice(String userId, String fingerprint, String name) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        return repository.registerTrustedDevice(new TrustedDevice(
+                UUID.randomUUID(),
+                TenantContext.require(),
+                userId,
+                fingerprint,
+                name != null ? name : "",
+                now,
+                now.plus(TRUSTED_DEVICE_EXPIRY)
+        ));
+    }
+
+    public Duration getMfaTokenExpiry() {
+        return jwtService.getMfaTokenExpiry();
+    }
+
+    public List<TokenInfo> listActiveTokens(String userId) {
+        return repository.listActiveTokens(userId);
+    }
+
+    public List<TrustedDevice> listTrustedDevices(String userId) {
+        return repository.listTrustedDevices(userId);
+    }
+
+    public void revokeTrustedDevice(UUID id, String userId) {
+        if (!repository.deleteTrustedDevice(id, userId)) {
+            throw new ResourceNotFoundException("trusted device not found");
+        }
     }
 
     public void logout(String token, String userId) {
@@ -96,10 +315,36 @@ public class AuthService {
         log.info("user {} logged out", userId);
     }
 
+    // Ends every session the user has in this tenant, including the caller's own.
+    // Returns how many sessions were removed.
+    public int logoutAll(String userId) {
+        List<String> tokens = repository.getSessionTokensByUserId(userId);
+        for (String token : tokens) {
+            try {
+                repository.blacklistToken(token, jwtService.getAccessTokenExpiry());
+            } catch (Exception e) {
+                log.error("error blacklisting token: {}", e.getMessage());
+            }
+        }
+        int invalidated = repository.deleteSessionsByUserId(userId);
+        try {
+            repository.invalidateCachedSessions(tokens);
+        } catch (Exception e) {
+            log.error("error invalidating cached sessions: {}", e.getMessage());
+        }
+
+        if (logoutAllPublisher != null) {
+            logoutAllPublisher.publish(new LogoutAllEvent(userId, TenantContext.require(), invalidated,
+                    OffsetDateTime.now(ZoneOffset.UTC)));
+        }
+        log.info("user {} logged out of {} sessions", userId, invalidated);
+        return invalidated;
+    }
+
     public TokenPair refresh(String refreshToken) {
         // Check if blacklisted
         if (repository.isTokenBlacklisted(refreshToken)) {
-            throw new AuthenticationException("token has been revoked");
+            throw new UnauthorizedException("token has been revoked");
         }
 
         // Validate refresh token
@@ -107,12 +352,16 @@ public class AuthService {
         try {
             claims = jwtService.parseToken(refreshToken);
         } catch (JwtException e) {
-            throw new AuthenticationException("invalid or expired refresh token");
+            throw new UnauthorizedException("invalid or expired refresh token");
         }
 
         String tokenType = claims.get("type", String.class);
         if (!"refresh".equals(tokenType)) {
-            throw new AuthenticationException("not a refresh token");
+            throw new UnauthorizedException("not a refresh token");
+        }
+        UUID tenantId = TenantContext.require();
+        if (!jwtService.isIssuedFor(claims, tenantId)) {
+            throw new UnauthorizedException("token was issued for a different tenant");
         }
 
         String userId = claims.get("user_id", String.class);
@@ -122,7 +371,80 @@ public class AuthService {
         repository.blacklistToken(refreshToken, jwtService.getRefreshTokenExpiry());
 
         // Issue new pair
-        return jwtService.issueTokens(userId, email);
+        return jwtService.issueTokens(userId, email, tenantId);
+    }
+
+    private TokenPair startSession(String userId, String email, String deviceFingerprint) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+
+        // Issue tokens
+        UUID tenantId = TenantContext.require();
+        TokenPair tokenPair = jwtService.issueTokens(userId, email, tenantId);
+
+        // Persist session
+        Session session = new Session(
+                UUID.randomUUID().toString(),
+                userId,
+                tokenPair.accessToken(),
+                now.plus(jwtService.getAccessTokenExpiry()),
+                now
+        );
+        session.setTenantId(tenantId);
+        session.setDeviceFingerprint(deviceFingerprint);
+        session.setUpdatedAt(now);
+        try {
+            repository.upsertSession(session);
+            repository.cacheSession(session.getToken(), session.getUserId(), jwtService.getAccessTokenExpiry());
+        } catch (Exception e) {
+            log.error("error creating session: {}", e.getMessage());
+            throw new RuntimeException("failed to create session");
+        }
+
+        return tokenPair;
+    }
+
+    private static String s256(String verifier) {
+        try {
+            byte[] digest = MessageDigest.getInstance("SHA-256")
+                    .digest(verifier.getBytes(StandardCharsets.US_ASCII));
+            return Base64.getUrlEncoder().withoutPadding().encodeToString(digest);
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+
+    public LoginAttemptPage listLoginAttempts(LoginAttemptAdminFilter filter) {
+        if (filter.getFrom() != null && filter.getTo() != null && !filter.getFrom().isBefore(filter.getTo())) {
+            throw new ValidationException("from must be before to");
+        }
+
+        // Fetch one extra row to learn whether another page exists without a second query
+        int pageSize = filter.getLimit();
+        filter.setLimit(pageSize + 1);
+        List<LoginAttempt> attempts = repository.listLoginAttempts(filter);
+        filter.setLimit(pageSize);
+
+        if (attempts.size() <= pageSize) {
+            return new LoginAttemptPage(attempts, null);
+        }
+        attempts = attempts.subList(0, pageSize);
+        LoginAttempt last = attempts.get(pageSize - 1);
+        return new LoginAttemptPage(attempts, new Cursor(last.createdAt(), last.id()).encode());
+    }
+
+    public void exportLoginAttempts(LoginAttemptAdminFilter filter, Consumer<LoginAttempt> consumer) {
+        if (filter.getFrom() != null && filter.getTo() != null && !filter.getFrom().isBefore(filter.getTo())) {
+            throw new ValidationException("from must be before to");
+        }
+        repository.streamLoginAttempts(filter, consumer);
+    }
+
+    public int countLoginAttempts(LoginAttemptAdminFilter filter) {
+        return repository.countLoginAttempts(filter);
+    }
+
+    public int countFailedLogins(String email, OffsetDateTime since) {
+        return repository.getRecentFailedAttempts(email, since);
     }
 
     public TokenValidationResponse validate(String token) {
@@ -133,20 +455,80 @@ public class AuthService {
 
         try {
             Claims claims = jwtService.parseToken(token);
+            if (!jwtService.isIssuedFor(claims, TenantContext.require())) {
+                return TokenValidationResponse.invalid();
+            }
             String userId = claims.get("user_id", String.class);
             String email = claims.get("email", String.class);
-            return new TokenValidationResponse(true, userId, email);
+            // Read on every validation rather than carried in the token, so a revoked
+            // role stops working at once.
+            String role = repository.getUserRole(userId).orElse("customer");
+            return new TokenValidationResponse(true, userId, email, role);
         } catch (JwtException e) {
             return TokenValidationResponse.invalid();
         }
     }
 
-    // Custom exceptions
-    public static class AuthenticationException extends RuntimeException {
-        public AuthenticationException(String message) { super(message); }
+    public static Builder builder() {
+        return new Builder();
     }
 
-    public static class RateLimitedException extends RuntimeException {
-        public RateLimitedException(String message) { super(message); }
+    // Assembles the service outside the Spring context. The suspicious-login and
+    // logout-all publishers may be left unset.
+    public static final class Builder {
+
+        private AuthRepository repository;
+        private JwtService jwtService;
+        private GeoIpLookup geoIpLookup;
+        private SuspiciousLoginPublisher suspiciousLoginPublisher;
+        private LogoutAllPublisher logoutAllPublisher;
+
+        private Builder() {}
+
+        public Builder withRepository(AuthRepository repository) {
+            this.repository = repository;
+            return this;
+        }
+
+        public Builder withJwtService(JwtService jwtService) {
+            this.jwtService = jwtService;
+            return this;
+        }
+
+        public Builder withGeoIpLookup(GeoIpLookup geoIpLookup) {
+            this.geoIpLookup = geoIpLookup;
+            return this;
+        }
+
+        public Builder withSuspiciousLoginPublisher(SuspiciousLoginPublisher suspiciousLoginPublisher) {
+            this.suspiciousLoginPublisher = suspiciousLoginPublisher;
+            return this;
+        }
+
+        public Builder withLogoutAllPublisher(LogoutAllPublisher logoutAllPublisher) {
+            this.logoutAllPublisher = logoutAllPublisher;
+            return this;
+        }
+
+        public void validate() {
+            List<String> missing = new ArrayList<>();
+            if (repository == null) {
+                missing.add("repository");
+            }
+            if (jwtService == null) {
+                missing.add("jwtService");
+            }
+            if (geoIpLookup == null) {
+                missing.add("geoIpLookup");
+            }
+            if (!missing.isEmpty()) {
+                throw new IllegalStateException("missing required dependencies: " + String.join(", ", missing));
+            }
+        }
+
+        public AuthService build() {
+            validate();
+            return new AuthService(repository, jwtService, geoIpLookup, suspiciousLoginPublisher, logoutAllPublisher);
+        }
     }
 }
@@ -3,58 +3,181 @@ package com.kubesec.auth.service;
 import com.kubesec.auth.config.AppConfig;
 import com.kubesec.auth.model.TokenPair;
 import io.jsonwebtoken.Claims;
+import io.jsonwebtoken.JwsHeader;
+import io.jsonwebtoken.JwtBuilder;
 import io.jsonwebtoken.JwtException;
 import io.jsonwebtoken.Jwts;
+import io.jsonwebtoken.LocatorAdapter;
+import io.jsonwebtoken.UnsupportedJwtException;
+import io.jsonwebtoken.security.Jwks;
 import io.jsonwebtoken.security.Keys;
+import io.jsonwebtoken.security.PublicJwk;
+import io.jsonwebtoken.security.SignatureAlgorithm;
 import org.springframework.stereotype.Service;
 
 import javax.crypto.SecretKey;
 import java.nio.charset.StandardCharsets;
+import java.nio.file.Path;
+import java.security.Key;
+import java.security.PrivateKey;
 import java.time.Duration;
 import java.time.Instant;
 import java.util.Date;
+import java.util.List;
 import java.util.Map;
+import java.util.UUID;
 
 @Service
 public class JwtService {
 
     private final SecretKey key;
     private final Duration accessTokenExpiry;
+    private final String issuer;
+    private final String audience;
     private static final Duration REFRESH_TOKEN_EXPIRY = Duration.ofDays(7);
+    private static final Duration MFA_TOKEN_EXPIRY = Duration.ofMinutes(5);
+    private static final String MFA_PENDING = "mfa_pending";
+
+    // Set when tokens are signed with a private key; null signs with the shared secret.
+    private final SignatureAlgorithm signingAlgorithm;
+    private final PrivateKey privateKey;
+    private final PublicJwk<?> publicJwk;
 
     public JwtService(AppConfig config) {
         this.key = Keys.hmacShaKeyFor(config.getJwtSecret().getBytes(StandardCharsets.UTF_8));
         this.accessTokenExpiry = config.getJwtExpiryDuration();
+        this.issuer = config.getJwtIssuer();
+        this.audience = config.getJwtAudience();
+
+        // Without a private key the service keeps signing with the shared secret,
+        // whatever method is configured, so existing deployments are unaffected.
+        String method = config.getJwtSigningMethod();
+        String privateKeyPath = config.getJwtPrivateKeyPath();
+        if ("HS256".equals(method) || privateKeyPath.isBlank()) {
+            this.signingAlgorithm = null;
+            this.privateKey = null;
+            this.publicJwk = null;
+            return;
+        }
+        this.signingAlgorithm = switch (method) {
+            case "RS256" -> Jwts.SIG.RS256;
+            case "ES256" -> Jwts.SIG.ES256;
+            default -> throw new IllegalStateException("unsupported JWT signing method " + method);
+        };
+        if (config.getJwtPublicKeyPath().isBlank()) {
+            throw new IllegalStateException("JWT_PUBLIC_KEY_PATH is required with JWT_PRIVATE_KEY_PATH");
+        }
+        String keyAlgorithm = signingAlgorithm == Jwts.SIG.RS256 ? "RSA" : "EC";
+        this.privateKey = PemKeys.privateKey(Path.of(privateKeyPath), keyAlgorithm);
+        this.publicJwk = Jwks.builder()
+                .key(PemKeys.publicKey(Path.of(config.getJwtPublicKeyPath()), keyAlgorithm))
+                .algorithm(method)
+                .publicKeyUse("sig")
+                .idFromThumbprint()
+                .build();
     }
 
-    public TokenPair issueTokens(String userId, String email) {
+    public TokenPair issueTokens(String userId, String email, UUID tenantId) {
         Instant now = Instant.now();
 
-        String accessToken = Jwts.builder()
-                .claims(Map.of("user_id", userId, "email", email, "type", "access"))
+        // The jti keeps tokens issued to the same user within one second distinct;
+        // sessions.token is unique.
+        String accessToken = sign(Jwts.builder()
+                .claims(Map.of("user_id", userId, "email", email, "tenant_id", tenantId.toString(), "type", "access"))
+                .id(UUID.randomUUID().toString())
                 .issuedAt(Date.from(now))
-                .expiration(Date.from(now.plus(accessTokenExpiry)))
-                .signWith(key)
-                .compact();
+                .expiration(Date.from(now.plus(accessTokenExpiry))));
 
-        String refreshToken = Jwts.builder()
-                .claims(Map.of("user_id", userId, "email", email, "type", "refresh"))
+        String refreshToken = sign(Jwts.builder()
+                .claims(Map.of("user_id", userId, "email", email, "tenant_id", tenantId.toString(), "type", "refresh"))
+                .id(UUID.randomUUID().toString())
                 .issuedAt(Date.from(now))
-                .expiration(Date.from(now.plus(REFRESH_TOKEN_EXPIRY)))
-                .signWith(key)
-                .compact();
+                .expiration(Date.from(now.plus(REFRESH_TOKEN_EXPIRY))));
 
         return new TokenPair(accessToken, refreshToken);
     }
 
+    // Proves the password step of a login for a user with MFA enabled. It carries the
+    // device fingerprint so the session started after the TOTP step is bound to it.
+    public String issueMfaToken(String userId, String email, UUID tenantId, String deviceFingerprint) {
+        Instant now = Instant.now();
+        JwtBuilder builder = Jwts.builder()
+                .claims(Map.of("user_id", userId, "email", email, "tenant_id", tenantId.toString(), "type", MFA_PENDING))
+                .id(UUID.randomUUID().toString())
+                .issuedAt(Date.from(now))
+                .expiration(Date.from(now.plus(MFA_TOKEN_EXPIRY)));
+        if (deviceFingerprint != null) {
+            builder.claim("device_fingerprint", deviceFingerprint);
+        }
+        return sign(builder);
+    }
+
+    // Every token names this deployment as issuer and audience, so one minted by
+    // another environment sharing the key is refused by parse.
+    private String sign(JwtBuilder builder) {
+        builder.issuer(issuer).audience().add(audience).and();
+        if (signingAlgorithm == null) {
+            return builder.signWith(key).compact();
+        }
+        return builder.header().keyId(publicJwk.getId()).and()
+                .signWith(privateKey, signingAlgorithm)
+                .compact();
+    }
+
+    // Pending MFA tokens are refused here so they can never stand in for an access or
+    // refresh token; only parseMfaToken accepts them.
     public Claims parseToken(String token) throws JwtException {
+        Claims claims = parse(token);
+        if (MFA_PENDING.equals(claims.get("type", String.class))) {
+            throw new UnsupportedJwtException("mfa token used as a session token");
+        }
+        return claims;
+    }
+
+    public Claims parseMfaToken(String token) throws JwtException {
+        Claims claims = parse(token);
+        if (!MFA_PENDING.equals(claims.get("type", String.class))) {
+            throw new UnsupportedJwtException("not an mfa token");
+        }
+        return claims;
+    }
+
+    // The key is chosen from the token's alg header. Secret-signed tokens stay valid
+    // after a switch to a private key, so sessions survive the rollout; any other
+    // algorithm must match the configured one exactly.
+    private Claims parse(String token) throws JwtException {
         return Jwts.parser()
-                .verifyWith(key)
+                .keyLocator(new LocatorAdapter<Key>() {
+                    @Override
+                    protected Key locate(JwsHeader header) {
+                        String alg = header.getAlgorithm();
+                        if (alg != null && alg.startsWith("HS")) {
+                            return key;
+                        }
+                        if (signingAlgorithm != null && signingAlgorithm.getId().equals(alg)) {
+                            return publicJwk.toKey();
+                        }
+                        throw new UnsupportedJwtException("unsupported signing algorithm " + alg);
+                    }
+                })
+                .requireIssuer(issuer)
+                .requireAudience(audience)
                 .build()
                 .parseSignedClaims(token)
                 .getPayload();
     }
 
+    // The public half of the signing key as a JSON Web Key Set. Empty while tokens are
+    // signed with the shared secret, which is never published.
+    public Map<String, Object> jwks() {
+        return Map.of("keys", publicJwk == null ? List.of() : List.of(publicJwk));
+    }
+
+    // Tokens are only honoured under the tenant they were issued for.
+    public boolean isIssuedFor(Claims claims, UUID tenantId) {
+        return tenantId.toString().equals(claims.get("tenant_id", String.class));
+    }
+
     public Duration getAccessTokenExpiry() {
         return accessTokenExpiry;
     }
@@ -62,4 +185,8 @@ public class JwtService {
     public Duration getRefreshTokenExpiry() {
         return REFRESH_TOKEN_EXPIRY;
     }
+
+    public Duration getMfaTokenExpiry() {
+        return MFA_TOKEN_EXPIRY;
+    }
 }
@@ -0,0 +1,38 @@
+package com.kubesec.auth.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.model.dto.LogoutAllEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+// Lets other services evict claims they cached for the user's now-dead tokens.
+@Service
+@Profile("!test")
+public class LogoutAllPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(LogoutAllPublisher.class);
+    private static final String SUBJECT = "auth.logout_all";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public LogoutAllPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publish(LogoutAllEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode logout-all event")
+                    .addKeyValue("user_id", event.userId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
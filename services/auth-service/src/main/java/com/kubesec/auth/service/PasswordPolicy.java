@@ -0,0 +1,38 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.exception.ValidationException;
+
+import java.util.ArrayList;
+import java.util.List;
+
+// Rules a new password has to meet. Every broken rule is reported, not just the first.
+public final class PasswordPolicy {
+
+    static final int MIN_LENGTH = 12;
+
+    private PasswordPolicy() {}
+
+    public static void check(String password) {
+        List<String> violations = violations(password);
+        if (!violations.isEmpty()) {
+            throw new ValidationException("password must " + String.join(", ", violations));
+        }
+    }
+
+    static List<String> violations(String password) {
+        List<String> violations = new ArrayList<>();
+        if (password.codePointCount(0, password.length()) < MIN_LENGTH) {
+            violations.add("be at least " + MIN_LENGTH + " characters");
+        }
+        if (password.codePoints().noneMatch(Character::isUpperCase)) {
+            violations.add("contain an uppercase letter");
+        }
+        if (password.codePoints().noneMatch(Character::isDigit)) {
+            violations.add("contain a digit");
+        }
+        if (password.codePoints().allMatch(c -> Character.isLetterOrDigit(c) || Character.isWhitespace(c))) {
+            violations.add("contain a special character");
+        }
+        return violations;
+    }
+}
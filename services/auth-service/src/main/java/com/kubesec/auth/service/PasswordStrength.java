@@ -0,0 +1,49 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.exception.WeakPasswordException;
+import com.nulabinc.zxcvbn.Strength;
+import com.nulabinc.zxcvbn.Zxcvbn;
+
+import java.util.ArrayList;
+import java.util.List;
+
+// Scores passwords by estimated guesses with zxcvbn (0 = trivially guessable, 4 = very
+// unlikely to be guessed). The composition rules in PasswordPolicy are easy to satisfy
+// with predictable passwords like "Password123!"; this catches those.
+public class PasswordStrength {
+
+    private static final Zxcvbn ZXCVBN = new Zxcvbn();
+    private static final int MIN_INPUT_WORD_LENGTH = 3;
+
+    private final int minScore;
+
+    public PasswordStrength(int minScore) {
+        this.minScore = minScore;
+    }
+
+    // userInputs are the account's own details (email, name), which make a password
+    // easier to guess when reused in it.
+    public void check(String password, String... userInputs) {
+        Strength strength = score(password, userInputs);
+        if (strength.getScore() < minScore) {
+            throw new WeakPasswordException(strength.getScore(), strength.getFeedback().getSuggestions());
+        }
+    }
+
+    static Strength score(String password, String... userInputs) {
+        // Each word of an input counts on its own, so "Alice Smith" also catches "smith".
+        List<String> inputs = new ArrayList<>();
+        for (String input : userInputs) {
+            if (input == null || input.isBlank()) {
+                continue;
+            }
+            inputs.add(input);
+            for (String word : input.split("[^\\p{L}\\p{N}]+")) {
+                if (word.length() >= MIN_INPUT_WORD_LENGTH) {
+                    inputs.add(word);
+                }
+            }
+        }
+        return ZXCVBN.measure(password, inputs);
+    }
+}
@@ -0,0 +1,52 @@
+package com.kubesec.auth.service;
+
+import java.io.IOException;
+import java.io.UncheckedIOException;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.GeneralSecurityException;
+import java.security.KeyFactory;
+import java.security.PrivateKey;
+import java.security.PublicKey;
+import java.security.spec.PKCS8EncodedKeySpec;
+import java.security.spec.X509EncodedKeySpec;
+import java.util.Base64;
+
+// Reads the unencrypted PEM files written by `openssl genpkey` (PKCS#8 private keys)
+// and `openssl pkey -pubout` (X.509 public keys). keyAlgorithm is "RSA" or "EC".
+final class PemKeys {
+
+    private PemKeys() {}
+
+    static PrivateKey privateKey(Path file, String keyAlgorithm) {
+        byte[] der = read(file, "PRIVATE KEY");
+        try {
+            return KeyFactory.getInstance(keyAlgorithm).generatePrivate(new PKCS8EncodedKeySpec(der));
+        } catch (GeneralSecurityException e) {
+            throw new IllegalStateException("invalid " + keyAlgorithm + " private key " + file, e);
+        }
+    }
+
+    static PublicKey publicKey(Path file, String keyAlgorithm) {
+        byte[] der = read(file, "PUBLIC KEY");
+        try {
+            return KeyFactory.getInstance(keyAlgorithm).generatePublic(new X509EncodedKeySpec(der));
+        } catch (GeneralSecurityException e) {
+            throw new IllegalStateException("invalid " + keyAlgorithm + " public key " + file, e);
+        }
+    }
+
+    private static byte[] read(Path file, String label) {
+        try {
+            String pem = Files.readString(file)
+                    .replace("-----BEGIN " + label + "-----", "")
+                    .replace("-----END " + label + "-----", "")
+                    .replaceAll("\\s", "");
+            return Base64.getDecoder().decode(pem);
+        } catch (IOException e) {
+            throw new UncheckedIOException("read key " + file, e);
+        } catch (IllegalArgumentException e) {
+            throw new IllegalStateException("invalid PEM in " + file, e);
+        }
+    }
+}
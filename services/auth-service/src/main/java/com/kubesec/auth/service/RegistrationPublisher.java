@@ -0,0 +1,37 @@
+package com.kubesec.auth.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.model.dto.RegistrationRequestedEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+@Service
+@Profile("!test")
+public class RegistrationPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(RegistrationPublisher.class);
+    private static final String SUBJECT = "user.registration_requested";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public RegistrationPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publish(RegistrationRequestedEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode registration event")
+                    .addKeyValue("user_id", event.userId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
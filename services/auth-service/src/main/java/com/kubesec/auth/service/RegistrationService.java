@@ -0,0 +1,117 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.client.AccountServiceClient;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.ConflictException;
+import com.kubesec.auth.exception.UpstreamException;
+import com.kubesec.auth.model.EmailVerificationToken;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.model.dto.RegisterRequest;
+import com.kubesec.auth.model.dto.RegistrationRequestedEvent;
+import com.kubesec.auth.model.dto.RegistrationResponse;
+import com.kubesec.auth.repository.AuthRepository;
+import com.kubesec.auth.tenant.TenantContext;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.http.HttpStatus;
+import org.springframework.lang.Nullable;
+import org.springframework.stereotype.Service;
+import org.springframework.transaction.annotation.Transactional;
+import org.springframework.web.client.RestClientException;
+import org.springframework.web.client.RestClientResponseException;
+
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.security.SecureRandom;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Base64;
+import java.util.HexFormat;
+import java.util.Locale;
+import java.util.UUID;
+
+@Service
+public class RegistrationService {
+
+    private static final Logger log = LoggerFactory.getLogger(RegistrationService.class);
+    private static final int VERIFICATION_TOKEN_BYTES = 32;
+
+    private final SecureRandom random = new SecureRandom();
+
+    private final AuthRepository repository;
+    private final AccountServiceClient accountServiceClient;
+    private final AppConfig config;
+    private final RegistrationPublisher publisher;
+    private final PasswordStrength passwordStrength;
+
+    public RegistrationService(AuthRepository repository, AccountServiceClient accountServiceClient, AppConfig config,
+                               @Nullable RegistrationPublisher publisher) {
+        this.repository = repository;
+        this.accountServiceClient = accountServiceClient;
+        this.config = config;
+        this.publisher = publisher;
+        this.passwordStrength = new PasswordStrength(config.getMinPasswordStrength());
+    }
+
+    @Transactional
+    public RegistrationResponse register(RegisterRequest request) {
+        PasswordPolicy.check(request.password());
+        passwordStrength.check(request.password(), request.email(), request.fullName());
+        String email = request.email().trim().toLowerCase(Locale.ROOT);
+
+        // Checked before account-service is called so a repeat registration doesn't leave
+        // a second user behind there.
+        if (repository.getUserCredentialsByEmail(email).isPresent()) {
+            throw new ConflictException("email already registered");
+        }
+
+        String userId = createUser(email, request.fullName());
+        UUID tenantId = TenantContext.require();
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        repository.createUserCredentials(new UserCredentials(userId, tenantId, email,
+                PasswordHasher.hash(request.password()), false, now));
+
+        String token = newVerificationToken();
+        OffsetDateTime expiresAt = now.plus(config.getEmailVerificationExpiry());
+        repository.createEmailVerificationToken(new EmailVerificationToken(sha256Hex(token), tenantId, userId,
+                expiresAt, now));
+
+        if (publisher != null) {
+            publisher.publish(new RegistrationRequestedEvent(userId, tenantId, email, request.fullName(), token,
+                    expiresAt));
+        }
+        log.info("user {} registered, awaiting email verification", userId);
+        return new RegistrationResponse(userId, true);
+    }
+
+    private String createUser(String email, String fullName) {
+        try {
+            return accountServiceClient.createUser(email, fullName);
+        } catch (RestClientResponseException e) {
+            if (e.getStatusCode().isSameCodeAs(HttpStatus.CONFLICT)) {
+                throw new ConflictException("email already registered");
+            }
+            log.error("account-service rejected user creation: {}", e.getStatusCode());
+            throw new UpstreamException("account service unavailable");
+        } catch (RestClientException | IllegalStateException e) {
+            log.error("account-service user creation failed: {}", e.getMessage());
+            throw new UpstreamException("account service unavailable");
+        }
+    }
+
+    private String newVerificationToken() {
+        byte[] bytes = new byte[VERIFICATION_TOKEN_BYTES];
+        random.nextBytes(bytes);
+        return Base64.getUrlEncoder().withoutPadding().encodeToString(bytes);
+    }
+
+    static String sha256Hex(String value) {
+        try {
+            byte[] digest = MessageDigest.getInstance("SHA-256").digest(value.getBytes(StandardCharsets.UTF_8));
+            return HexFormat.of().formatHex(digest);
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+}
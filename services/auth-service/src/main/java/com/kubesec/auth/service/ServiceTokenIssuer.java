@@ -0,0 +1,47 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.ResourceNotFoundException;
+import com.kubesec.auth.model.ServiceToken;
+import io.jsonwebtoken.Jwts;
+import org.springframework.stereotype.Service;
+
+import java.nio.file.Path;
+import java.security.PrivateKey;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.Date;
+import java.util.UUID;
+
+// Signs the short-lived tokens services present to each other. They use the RSA key
+// in SERVICE_TOKEN_PRIVATE_KEY_FILE rather than the user token secret, so the other
+// services can verify them with the public key alone.
+@Service
+public class ServiceTokenIssuer {
+
+    static final Duration TOKEN_TTL = Duration.ofSeconds(60);
+
+    private final PrivateKey key;
+
+    public ServiceTokenIssuer(AppConfig config) {
+        String keyFile = config.getServiceTokenPrivateKeyFile();
+        this.key = keyFile.isBlank() ? null : PemKeys.privateKey(Path.of(keyFile), "RSA");
+    }
+
+    public ServiceToken issue(String issuer, String audience) {
+        if (key == null) {
+            throw new ResourceNotFoundException("service tokens are not enabled");
+        }
+        Instant now = Instant.now();
+        Instant expiresAt = now.plus(TOKEN_TTL);
+        String token = Jwts.builder()
+                .issuer(issuer)
+                .audience().add(audience).and()
+                .id(UUID.randomUUID().toString())
+                .issuedAt(Date.from(now))
+                .expiration(Date.from(expiresAt))
+                .signWith(key)
+                .compact();
+        return new ServiceToken(token, expiresAt);
+    }
+}
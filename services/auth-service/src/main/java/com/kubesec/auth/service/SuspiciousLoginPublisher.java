@@ -0,0 +1,38 @@
+package com.kubesec.auth.service;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.model.dto.SuspiciousLoginEvent;
+import io.nats.client.Connection;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Service;
+
+// Alerts are best effort: a lost event must never block the login itself.
+@Service
+@Profile("!test")
+public class SuspiciousLoginPublisher {
+
+    private static final Logger log = LoggerFactory.getLogger(SuspiciousLoginPublisher.class);
+    private static final String SUBJECT = "user.suspicious_login";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+
+    public SuspiciousLoginPublisher(Connection natsConnection, ObjectMapper objectMapper) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+    }
+
+    public void publish(SuspiciousLoginEvent event) {
+        try {
+            natsConnection.publish(SUBJECT, objectMapper.writeValueAsBytes(event));
+        } catch (JsonProcessingException e) {
+            log.atError().setMessage("encode suspicious login event")
+                    .addKeyValue("user_id", event.userId())
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
@@ -0,0 +1,119 @@
+package com.kubesec.auth.service;
+
+import javax.crypto.Mac;
+import javax.crypto.spec.SecretKeySpec;
+import java.io.ByteArrayOutputStream;
+import java.net.URLEncoder;
+import java.nio.charset.StandardCharsets;
+import java.security.GeneralSecurityException;
+import java.security.MessageDigest;
+import java.security.SecureRandom;
+import java.time.Instant;
+import java.util.OptionalLong;
+
+// RFC 6238 time-based one-time passwords with the parameters every authenticator app
+// supports: HMAC-SHA1, 6 digits, 30-second steps. Secrets are exchanged in base32.
+public final class Totp {
+
+    private static final String ALGORITHM = "HmacSHA1";
+    private static final String BASE32 = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567";
+    private static final long PERIOD_SECONDS = 30;
+    private static final int DIGITS = 6;
+    private static final int SECRET_BYTES = 20;
+    // Codes from one step either side are accepted to absorb clock drift.
+    private static final int WINDOW = 1;
+
+    private static final SecureRandom RANDOM = new SecureRandom();
+
+    private Totp() {}
+
+    public static String generateSecret() {
+        byte[] secret = new byte[SECRET_BYTES];
+        RANDOM.nextBytes(secret);
+        return encodeBase32(secret);
+    }
+
+    public static String provisioningUri(String issuer, String account, String secret) {
+        String label = URLEncoder.encode(issuer + ":" + account, StandardCharsets.UTF_8).replace("+", "%20");
+        return "otpauth://totp/" + label + "?secret=" + secret
+                + "&issuer=" + URLEncoder.encode(issuer, StandardCharsets.UTF_8).replace("+", "%20")
+                + "&algorithm=SHA1&digits=" + DIGITS + "&period=" + PERIOD_SECONDS;
+    }
+
+    // The step the code was generated for, so callers can refuse to accept it twice.
+    public static OptionalLong verify(String secret, String code, Instant now) {
+        if (code == null || code.length() != DIGITS) {
+            return OptionalLong.empty();
+        }
+        byte[] key = decodeBase32(secret);
+        long current = now.getEpochSecond() / PERIOD_SECONDS;
+        byte[] expected = code.getBytes(StandardCharsets.US_ASCII);
+        for (long step = current - WINDOW; step <= current + WINDOW; step++) {
+            if (MessageDigest.isEqual(expected, generate(key, step).getBytes(StandardCharsets.US_ASCII))) {
+                return OptionalLong.of(step);
+            }
+        }
+        return OptionalLong.empty();
+    }
+
+    public static String generate(String secret, Instant at) {
+        return generate(decodeBase32(secret), at.getEpochSecond() / PERIOD_SECONDS);
+    }
+
+    private static String generate(byte[] key, long step) {
+        byte[] hash;
+        try {
+            Mac mac = Mac.getInstance(ALGORITHM);
+            mac.init(new SecretKeySpec(key, ALGORITHM));
+            hash = mac.doFinal(new byte[]{
+                    (byte) (step >>> 56), (byte) (step >>> 48), (byte) (step >>> 40), (byte) (step >>> 32),
+                    (byte) (step >>> 24), (byte) (step >>> 16), (byte) (step >>> 8), (byte) step});
+        } catch (GeneralSecurityException e) {
+            throw new IllegalStateException(ALGORITHM + " unavailable", e);
+        }
+        int offset = hash[hash.length - 1] & 0x0f;
+        int binary = ((hash[offset] & 0x7f) << 24) | ((hash[offset + 1] & 0xff) << 16)
+                | ((hash[offset + 2] & 0xff) << 8) | (hash[offset + 3] & 0xff);
+        return String.format("%0" + DIGITS + "d", binary % 1_000_000);
+    }
+
+    static String encodeBase32(byte[] data) {
+        StringBuilder out = new StringBuilder();
+        int buffer = 0;
+        int bits = 0;
+        for (byte b : data) {
+            buffer = (buffer << 8) | (b & 0xff);
+            bits += 8;
+            while (bits >= 5) {
+                out.append(BASE32.charAt((buffer >>> (bits - 5)) & 0x1f));
+                bits -= 5;
+            }
+        }
+        if (bits > 0) {
+            out.append(BASE32.charAt((buffer << (5 - bits)) & 0x1f));
+        }
+        return out.toString();
+    }
+
+    static byte[] decodeBase32(String encoded) {
+        ByteArrayOutputStream out = new ByteArrayOutputStream();
+        int buffer = 0;
+        int bits = 0;
+        for (char c : encoded.toUpperCase().toCharArray()) {
+            if (c == '=' || c == ' ') {
+                continue;
+            }
+            int value = BASE32.indexOf(c);
+            if (value < 0) {
+                throw new IllegalArgumentException("invalid base32 character " + c);
+            }
+            buffer = (buffer << 5) | value;
+            bits += 5;
+            if (bits >= 8) {
+                out.write((buffer >>> (bits - 8)) & 0xff);
+                bits -= 8;
+            }
+        }
+        return out.toByteArray();
+    }
+}
@@ -0,0 +1,20 @@
+package com.kubesec.auth.tenant;
+
+import org.springframework.http.HttpRequest;
+import org.springframework.http.client.ClientHttpRequestExecution;
+import org.springframework.http.client.ClientHttpRequestInterceptor;
+import org.springframework.http.client.ClientHttpResponse;
+
+import java.io.IOException;
+
+// Forwards the caller's tenant to downstream services so their queries stay scoped.
+public class TenantHeaderInterceptor implements ClientHttpRequestInterceptor {
+
+    @Override
+    public ClientHttpResponse intercept(HttpRequest request, byte[] body,
+                                        ClientHttpRequestExecution execution) throws IOException {
+        TenantContext.get().ifPresent(tenantId ->
+                request.getHeaders().set(TenantContext.HEADER, tenantId.toString()));
+        return execution.execute(request, body);
+    }
+}
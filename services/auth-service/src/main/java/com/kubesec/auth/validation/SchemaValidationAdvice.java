@@ -0,0 +1,71 @@
+package com.kubesec.auth.validation;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.exception.SchemaViolationException;
+import org.springframework.core.MethodParameter;
+import org.springframework.http.HttpHeaders;
+import org.springframework.http.HttpInputMessage;
+import org.springframework.http.converter.HttpMessageConverter;
+import org.springframework.web.bind.annotation.ControllerAdvice;
+import org.springframework.web.servlet.mvc.method.annotation.RequestBodyAdviceAdapter;
+
+import java.io.ByteArrayInputStream;
+import java.io.IOException;
+import java.io.InputStream;
+import java.lang.reflect.Type;
+import java.util.List;
+
+// Validates @ValidatedBody request bodies against their JSON Schema and hands the
+// buffered bytes on to the regular message converter.
+@ControllerAdvice
+public class SchemaValidationAdvice extends RequestBodyAdviceAdapter {
+
+    private final SchemaValidator validator;
+    private final ObjectMapper objectMapper;
+
+    public SchemaValidationAdvice(SchemaValidator validator, ObjectMapper objectMapper) {
+        this.validator = validator;
+        this.objectMapper = objectMapper;
+    }
+
+    @Override
+    public boolean supports(MethodParameter parameter, Type targetType,
+                            Class<? extends HttpMessageConverter<?>> converterType) {
+        return parameter.hasParameterAnnotation(ValidatedBody.class);
+    }
+
+    @Override
+    public HttpInputMessage beforeBodyRead(HttpInputMessage inputMessage, MethodParameter parameter, Type targetType,
+                                           Class<? extends HttpMessageConverter<?>> converterType) throws IOException {
+        byte[] body = inputMessage.getBody().readAllBytes();
+
+        JsonNode json;
+        try {
+            json = objectMapper.readTree(body);
+        } catch (IOException e) {
+            throw new SchemaViolationException(List.of("request body is not valid JSON"));
+        }
+        if (json == null || json.isMissingNode()) {
+            throw new SchemaViolationException(List.of("request body is required"));
+        }
+
+        String schema = parameter.getParameterAnnotation(ValidatedBody.class).value();
+        List<String> violations = validator.validate(schema, json);
+        if (!violations.isEmpty()) {
+            throw new SchemaViolationException(violations);
+        }
+
+        return new HttpInputMessage() {
+            @Override
+            public InputStream getBody() {
+                return new ByteArrayInputStream(body);
+            }
+
+            @Override
+            public HttpHeaders getHeaders() {
+                return inputMessage.getHeaders();
+            }
+        };
+    }
+}
@@ -0,0 +1,24 @@
+-- Password credentials for self-registered users. The user row itself lives in
+-- account-service; user_id is the id it assigned.
+CREATE TABLE IF NOT EXISTS user_credentials (
+    user_id        VARCHAR(64)  PRIMARY KEY,
+    tenant_id      UUID         NOT NULL REFERENCES tenants(id),
+    email          VARCHAR(255) NOT NULL,
+    password_hash  VARCHAR(255) NOT NULL,
+    email_verified BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, email)
+);
+
+-- Only the SHA-256 of each verification token is stored; the token itself is sent to
+-- the user and never persisted.
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    tenant_id  UUID        NOT NULL REFERENCES tenants(id),
+    user_id    VARCHAR(64) NOT NULL REFERENCES user_credentials(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens (user_id);
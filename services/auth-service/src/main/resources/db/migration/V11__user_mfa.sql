@@ -0,0 +1,14 @@
+-- TOTP second factor. Keyed by user id like trusted_devices, so it covers users
+-- whose credentials live outside this service. A secret is stored at enrollment
+-- but only enforced once the user has confirmed a code from it. last_used_step is
+-- the 30-second step of the last accepted code, so a code can't be replayed.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    tenant_id      UUID         NOT NULL REFERENCES tenants(id),
+    user_id        VARCHAR(64)  NOT NULL,
+    totp_secret    VARCHAR(64)  NOT NULL,
+    mfa_enabled    BOOLEAN      NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, user_id)
+);
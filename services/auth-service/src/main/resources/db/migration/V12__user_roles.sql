@@ -0,0 +1,6 @@
+-- Staff roles, reported by /api/v1/auth/validate so other services never take a role
+-- from a request header. Everyone registers as a customer; staff roles are granted
+-- here by an operator.
+ALTER TABLE user_credentials
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer'
+        CHECK (role IN ('customer', 'teller', 'auditor', 'admin'));
@@ -0,0 +1,11 @@
+-- auth_codes holds short-lived PKCE authorization codes. Rows are deleted when redeemed.
+CREATE TABLE IF NOT EXISTS auth_codes (
+    code            VARCHAR(64)  PRIMARY KEY,
+    user_id         VARCHAR(64)  NOT NULL,
+    email           VARCHAR(255) NOT NULL,
+    code_challenge  VARCHAR(128) NOT NULL,
+    expires_at      TIMESTAMPTZ  NOT NULL,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_codes_expires_at ON auth_codes (expires_at);
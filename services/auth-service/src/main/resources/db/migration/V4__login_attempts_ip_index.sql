@@ -0,0 +1,2 @@
+-- Supports the admin login-attempt search by source IP over a time window.
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_time ON login_attempts (ip_address, created_at);
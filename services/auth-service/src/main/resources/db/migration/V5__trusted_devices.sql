@@ -0,0 +1,14 @@
+-- trusted_devices lets users skip the second login factor on devices they have already
+-- verified. Re-registering a device refreshes its trust window instead of adding a row.
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    id                 UUID         PRIMARY KEY,
+    tenant_id          UUID         NOT NULL REFERENCES tenants(id),
+    user_id            VARCHAR(64)  NOT NULL,
+    device_fingerprint VARCHAR(128) NOT NULL,
+    device_name        VARCHAR(255) NOT NULL DEFAULT '',
+    trusted_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    expires_at         TIMESTAMPTZ  NOT NULL,
+    UNIQUE (tenant_id, user_id, device_fingerprint)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trusted_devices_expires_at ON trusted_devices (expires_at);
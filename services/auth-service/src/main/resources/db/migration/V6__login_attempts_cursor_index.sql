@@ -0,0 +1,2 @@
+-- Keyset pagination of the admin login-attempt list walks (created_at, id) newest first.
+CREATE INDEX IF NOT EXISTS idx_login_attempts_tenant_cursor ON login_attempts (tenant_id, created_at DESC, id DESC);
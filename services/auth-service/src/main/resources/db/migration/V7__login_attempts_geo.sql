@@ -0,0 +1,7 @@
+-- Geo-IP of each login, used to flag logins from a country the user has not
+-- recently logged in from.
+ALTER TABLE login_attempts ADD COLUMN IF NOT EXISTS ip_country VARCHAR(2);
+ALTER TABLE login_attempts ADD COLUMN IF NOT EXISTS ip_city    VARCHAR(128);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_email_country
+    ON login_attempts (tenant_id, email, created_at DESC) WHERE success AND ip_country IS NOT NULL;
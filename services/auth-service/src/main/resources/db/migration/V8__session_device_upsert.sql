@@ -0,0 +1,8 @@
+-- One session per user and device: a second login from the same fingerprint replaces
+-- the token on the existing row instead of adding another. Logins without a
+-- fingerprint leave it NULL, which the unique constraint doesn't restrict.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS device_fingerprint VARCHAR(128);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+ALTER TABLE sessions ADD CONSTRAINT uq_sessions_user_device
+    UNIQUE (tenant_id, user_id, device_fingerprint);
@@ -0,0 +1,6 @@
+-- Successful logins record who logged in and from what client, so active sessions
+-- can be listed with the IP address and user agent that opened them.
+ALTER TABLE login_attempts ADD COLUMN IF NOT EXISTS user_id VARCHAR(64);
+ALTER TABLE login_attempts ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_created ON login_attempts (tenant_id, user_id, created_at);
@@ -14,6 +14,7 @@ import org.springframework.test.context.TestPropertySource;
         "spring.datasource.url=jdbc:h2:mem:testdb",
         "spring.datasource.driver-class-name=org.h2.Driver",
         "spring.flyway.enabled=false",
+        "app.db-schema-retry-attempts=0",
         "spring.autoconfigure.exclude=org.springframework.boot.autoconfigure.data.redis.RedisAutoConfiguration,org.springframework.boot.autoconfigure.data.redis.RedisRepositoriesAutoConfiguration",
         "app.jwt-secret=test-secret-key-that-is-at-least-256-bits-long-for-hs256"
 })
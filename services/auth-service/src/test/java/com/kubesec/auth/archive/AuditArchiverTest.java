@@ -0,0 +1,187 @@
+package com.kubesec.auth.archive;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.io.ByteArrayInputStream;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+import java.util.zip.GZIPInputStream;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// Runs the archiver against the in-memory repository with a recording uploader in
+// place of S3, reading each uploaded file back while it still exists.
+class AuditArchiverTest {
+
+    private static final OffsetDateTime CUTOFF = OffsetDateTime.of(2026, 1, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+
+    private BatchCountingRepository repository;
+    private RecordingUploader uploader;
+    private AppConfig config;
+    private AuditArchiver archiver;
+
+    @BeforeEach
+    void setUp() {
+        repository = new BatchCountingRepository();
+        uploader = new RecordingUploader();
+        config = new AppConfig();
+        archiver = new AuditArchiver(repository, uploader, objectMapper, config);
+    }
+
+    @Test
+    void oldAttemptsAreUploadedThenDeleted() throws IOException {
+        record("old-2", CUTOFF.minusDays(1));
+        record("old-1", CUTOFF.minusDays(30));
+        record("edge", CUTOFF);
+        record("new", CUTOFF.plusDays(1));
+
+        archiver.archiveBefore(CUTOFF);
+
+        assertEquals(1, uploader.uploads.size());
+        Upload upload = uploader.uploads.get(0);
+        assertTrue(upload.key().matches("login-attempts/2026-01-01-[0-9a-f-]{36}\\.ndjson\\.gz"), upload.key());
+        assertEquals(List.of("old-1", "old-2"), upload.lines().stream().map(this::id).toList(), "oldest first");
+        assertEquals(List.of("edge", "new"), remaining());
+        assertFalse(Files.exists(upload.file()), "the temp file is removed");
+    }
+
+    @Test
+    void nothingOldEnoughUploadsNothing() throws IOException {
+        record("new", CUTOFF.plusDays(1));
+
+        archiver.archiveBefore(CUTOFF);
+
+        assertEquals(List.of(), uploader.uploads);
+        assertEquals(List.of(), repository.batches);
+        assertEquals(List.of("new"), remaining());
+    }
+
+    @Test
+    void failedUploadKeepsTheRows() {
+        record("old", CUTOFF.minusDays(1));
+        uploader.failure = new IOException("s3 unavailable");
+
+        assertThrows(IOException.class, () -> archiver.archiveBefore(CUTOFF));
+
+        assertEquals(List.of("old"), remaining());
+        assertEquals(List.of(), repository.batches);
+        assertFalse(Files.exists(uploader.uploads.get(0).file()));
+    }
+
+    @Test
+    void deletesInBatchesOfAThousand() throws IOException {
+        for (int i = 0; i < 2000; i++) {
+            record(String.format("old-%04d", i), CUTOFF.minusMinutes(i + 1));
+        }
+        record("new", CUTOFF.plusDays(1));
+
+        archiver.archiveBefore(CUTOFF);
+
+        assertEquals(2000, uploader.uploads.get(0).lines().size());
+        // A full batch means there may be more, so an exact multiple needs one empty pass
+        assertEquals(List.of(1000, 1000, 0), repository.batches);
+        assertEquals(List.of("new"), remaining());
+    }
+
+    @Test
+    void scheduledRunIsOffWithoutABucket() {
+        record("old", OffsetDateTime.now(ZoneOffset.UTC).minusDays(365));
+
+        archiver.archive();
+
+        assertEquals(List.of(), uploader.uploads);
+        assertEquals(List.of("old"), remaining());
+    }
+
+    @Test
+    void scheduledRunArchivesAttemptsPastTheConfiguredAge() {
+        config.setAuditArchiveBucket("kubesec-audit");
+        config.setAuditLogArchiveAfterDays(30);
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        record("old", now.minusDays(31));
+        record("recent", now.minusDays(29));
+
+        archiver.archive();
+
+        assertEquals(List.of("old"), uploader.uploads.get(0).lines().stream().map(this::id).toList());
+        assertEquals(List.of("recent"), remaining());
+    }
+
+    @Test
+    void scheduledRunSwallowsUploadFailures() {
+        config.setAuditArchiveBucket("kubesec-audit");
+        record("old", OffsetDateTime.now(ZoneOffset.UTC).minusDays(365));
+        uploader.failure = new IOException("s3 unavailable");
+
+        archiver.archive();
+
+        assertEquals(List.of("old"), remaining());
+    }
+
+    private void record(String id, OffsetDateTime at) {
+        repository.recordLoginAttempt(new LoginAttempt(id, UUID.randomUUID(), "alice@example.com", null, false,
+                "10.0.0.1", null, null, "test-agent", at));
+    }
+
+    private List<String> remaining() {
+        List<String> ids = new ArrayList<>();
+        repository.streamLoginAttemptsBefore(OffsetDateTime.MAX, a -> ids.add(a.id()));
+        return ids;
+    }
+
+    private String id(String line) {
+        try {
+            return objectMapper.readTree(line).get("id").asText();
+        } catch (IOException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+
+    private record Upload(String key, Path file, List<String> lines) {}
+
+    // Stands in for S3: keeps the key and the decompressed lines of every upload.
+    private static class RecordingUploader implements ArchiveUploader {
+        final List<Upload> uploads = new ArrayList<>();
+        IOException failure;
+
+        @Override
+        public void upload(String key, Path file) throws IOException {
+            byte[] gzipped = Files.readAllBytes(file);
+            try (GZIPInputStream in = new GZIPInputStream(new ByteArrayInputStream(gzipped))) {
+                String text = new String(in.readAllBytes(), StandardCharsets.UTF_8);
+                uploads.add(new Upload(key, file, List.of(text.split("\n"))));
+            }
+            if (failure != null) {
+                throw failure;
+            }
+        }
+    }
+
+    private static class BatchCountingRepository extends InMemoryAuthRepository {
+        final List<Integer> batches = new ArrayList<>();
+
+        @Override
+        public int deleteLoginAttemptsBefore(OffsetDateTime cutoff, int batchSize) {
+            int deleted = super.deleteLoginAttemptsBefore(cutoff, batchSize);
+            batches.add(deleted);
+            return deleted;
+        }
+    }
+}
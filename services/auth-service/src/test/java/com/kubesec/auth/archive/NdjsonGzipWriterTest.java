@@ -0,0 +1,102 @@
+package com.kubesec.auth.archive;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.SerializationFeature;
+import com.kubesec.auth.model.LoginAttempt;
+import org.junit.jupiter.api.Test;
+
+import java.io.ByteArrayInputStream;
+import java.io.ByteArrayOutputStream;
+import java.io.IOException;
+import java.io.OutputStream;
+import java.nio.charset.StandardCharsets;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+import java.util.zip.GZIPInputStream;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class NdjsonGzipWriterTest {
+
+    private static final OffsetDateTime T0 = OffsetDateTime.of(2026, 1, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+
+    // Dates as ISO strings, as the application's Spring-configured mapper writes them
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules()
+            .disable(SerializationFeature.WRITE_DATES_AS_TIMESTAMPS);
+
+    @Test
+    void writesOneJsonDocumentPerLine() throws IOException {
+        ByteArrayOutputStream target = new ByteArrayOutputStream();
+        try (NdjsonGzipWriter writer = new NdjsonGzipWriter(objectMapper, target)) {
+            writer.write(attempt("a1", "alice@example.com", T0));
+            writer.write(attempt("a2", "bob@example.com", T0.plusHours(1)));
+            assertEquals(2, writer.getCount());
+        }
+
+        List<String> lines = gunzipLines(target.toByteArray());
+        assertEquals(2, lines.size());
+        JsonNode first = objectMapper.readTree(lines.get(0));
+        assertEquals("a1", first.get("id").asText());
+        assertEquals("alice@example.com", first.get("email").asText());
+        assertEquals("10.0.0.1", first.get("ip_address").asText());
+        assertEquals(T0.toInstant(), OffsetDateTime.parse(first.get("created_at").asText()).toInstant());
+        assertEquals("a2", objectMapper.readTree(lines.get(1)).get("id").asText());
+    }
+
+    @Test
+    void nothingWrittenIsAnEmptyArchive() throws IOException {
+        ByteArrayOutputStream target = new ByteArrayOutputStream();
+        try (NdjsonGzipWriter writer = new NdjsonGzipWriter(objectMapper, target)) {
+            assertEquals(0, writer.getCount());
+        }
+
+        assertEquals(List.of(), gunzipLines(target.toByteArray()));
+    }
+
+    // Compressed output has to reach the target while rows are still being written,
+    // otherwise a large archive would sit in memory until close.
+    @Test
+    void compressedOutputIsStreamedBeforeClose() throws IOException {
+        CountingOutputStream target = new CountingOutputStream();
+        long beforeClose;
+        try (NdjsonGzipWriter writer = new NdjsonGzipWriter(objectMapper, target)) {
+            for (int i = 0; i < 20_000; i++) {
+                writer.write(attempt(UUID.randomUUID().toString(), UUID.randomUUID() + "@example.com", T0));
+            }
+            beforeClose = target.count;
+        }
+
+        assertTrue(beforeClose > 0, "nothing reached the target before close");
+        assertTrue(beforeClose > target.count / 2, "most of the archive was held back until close");
+    }
+
+    private static LoginAttempt attempt(String id, String email, OffsetDateTime at) {
+        return new LoginAttempt(id, UUID.randomUUID(), email, null, false, "10.0.0.1", "TN", "Tunis",
+                "test-agent", at);
+    }
+
+    private static List<String> gunzipLines(byte[] gzipped) throws IOException {
+        try (GZIPInputStream in = new GZIPInputStream(new ByteArrayInputStream(gzipped))) {
+            String text = new String(in.readAllBytes(), StandardCharsets.UTF_8);
+            return text.isEmpty() ? List.of() : List.of(text.split("\n"));
+        }
+    }
+
+    private static class CountingOutputStream extends OutputStream {
+        long count;
+
+        @Override
+        public void write(int b) {
+            count++;
+        }
+
+        @Override
+        public void write(byte[] b, int off, int len) {
+            count += len;
+        }
+    }
+}
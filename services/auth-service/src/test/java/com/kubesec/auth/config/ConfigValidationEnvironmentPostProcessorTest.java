@@ -0,0 +1,84 @@
+package com.kubesec.auth.config;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.env.MockEnvironment;
+
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class ConfigValidationEnvironmentPostProcessorTest {
+
+    private static final String SECRET = "a-development-secret-that-is-32-bytes-or-more";
+
+    @Test
+    void developmentAcceptsAStrongSecret() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("app.jwt-secret", SECRET)
+                .withProperty("spring.datasource.url", "jdbc:postgresql://localhost:5432/kubesec_auth")
+                .withProperty("spring.datasource.password", "postgres");
+
+        new ConfigValidationEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+    }
+
+    @Test
+    void placeholderOrShortSecretFailsStartup() {
+        MockEnvironment placeholder = new MockEnvironment();
+        MockEnvironment tooShort = new MockEnvironment().withProperty("app.jwt-secret", "short-secret");
+
+        assertEquals(List.of("JWT_SECRET is still the placeholder default; set a random secret"),
+                ConfigValidationEnvironmentPostProcessor.validate(placeholder));
+        assertEquals(List.of("JWT_SECRET must be at least 32 bytes"),
+                ConfigValidationEnvironmentPostProcessor.validate(tooShort));
+        assertThrows(IllegalStateException.class,
+                () -> new ConfigValidationEnvironmentPostProcessor().postProcessEnvironment(placeholder, null));
+    }
+
+    @Test
+    void expiryMustBeBetweenOneMinuteAndOneDay() {
+        for (String expiry : List.of("0", "1441", "15m")) {
+            MockEnvironment environment = new MockEnvironment()
+                    .withProperty("app.jwt-secret", SECRET)
+                    .withProperty("app.jwt-expiry", expiry);
+
+            List<String> problems = ConfigValidationEnvironmentPostProcessor.validate(environment);
+
+            assertEquals(1, problems.size(), expiry);
+            assertTrue(problems.get(0).startsWith("JWT_EXPIRY must be"), problems.get(0));
+        }
+        MockEnvironment oneDay = new MockEnvironment()
+                .withProperty("app.jwt-secret", SECRET)
+                .withProperty("app.jwt-expiry", "1440");
+        assertTrue(ConfigValidationEnvironmentPostProcessor.validate(oneDay).isEmpty());
+    }
+
+    @Test
+    void productionRejectsDefaultPasswordAndLocalAddresses() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("KUBESEC_ENV", "production")
+                .withProperty("app.jwt-secret", SECRET)
+                .withProperty("spring.datasource.url", "jdbc:postgresql://127.0.0.1:5432/kubesec_auth")
+                .withProperty("spring.datasource.password", "postgres")
+                .withProperty("spring.data.redis.host", "localhost");
+
+        assertEquals(List.of(
+                "DB_PASSWORD must not be the default \"postgres\" in production",
+                "database address must not be localhost in production",
+                "Redis address must not be localhost in production"
+        ), ConfigValidationEnvironmentPostProcessor.validate(environment));
+    }
+
+    @Test
+    void productionAcceptsRemoteAddresses() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("ENV", "production")
+                .withProperty("app.jwt-secret", SECRET)
+                .withProperty("spring.datasource.url", "jdbc:postgresql://postgres.kubesec:5432/kubesec_auth")
+                .withProperty("spring.datasource.password", "s3cret")
+                .withProperty("spring.data.redis.cluster.nodes", "redis-0:6379,redis-1:6379");
+
+        assertTrue(ConfigValidationEnvironmentPostProcessor.validate(environment).isEmpty());
+    }
+}
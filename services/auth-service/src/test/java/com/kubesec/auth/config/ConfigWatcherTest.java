@@ -0,0 +1,77 @@
+package com.kubesec.auth.config;
+
+import com.kubesec.auth.filter.InMemoryRateLimiter;
+import com.kubesec.auth.filter.RateLimitFilter;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.io.TempDir;
+import org.springframework.mock.web.MockFilterChain;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.time.Duration;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+class ConfigWatcherTest {
+
+    @TempDir
+    Path dir;
+
+    private Path file;
+    private RateLimitFilter filter;
+    private ConfigWatcher watcher;
+
+    @BeforeEach
+    void setUp() {
+        file = dir.resolve("config.json");
+        AppConfig config = new AppConfig();
+        config.setConfigWatchFile(file.toString());
+        filter = new RateLimitFilter(new InMemoryRateLimiter());
+        watcher = new ConfigWatcher(config, filter);
+    }
+
+    @Test
+    void rateLimiterPicksUpNewLimit() throws Exception {
+        Files.writeString(file, "{\"rate_limit\": {\"limit\": 2, \"window_seconds\": 30}}");
+        watcher.poll();
+
+        assertEquals(new RateLimitSettings(2, Duration.ofSeconds(30)), filter.getSettings());
+        assertEquals(200, login().getStatus());
+        assertEquals(200, login().getStatus());
+        MockHttpServletResponse limited = login();
+        assertEquals(429, limited.getStatus());
+        assertEquals("2", limited.getHeader("RateLimit-Limit"));
+    }
+
+    @Test
+    void invalidConfigKeepsCurrentSettings() throws Exception {
+        Files.writeString(file, "{\"rate_limit\": {\"limit\": 5, \"window_seconds\": 10}}");
+        watcher.poll();
+
+        Files.writeString(file, "{\"rate_limit\": {\"limit\": ");
+        watcher.poll();
+        assertEquals(new RateLimitSettings(5, Duration.ofSeconds(10)), filter.getSettings());
+
+        Files.writeString(file, "{\"rate_limit\": {\"limit\": 0, \"window_seconds\": 10}}");
+        watcher.poll();
+        assertEquals(new RateLimitSettings(5, Duration.ofSeconds(10)), filter.getSettings());
+    }
+
+    @Test
+    void missingFileKeepsDefaults() {
+        watcher.poll();
+
+        assertEquals(RateLimitSettings.DEFAULT, filter.getSettings());
+    }
+
+    private MockHttpServletResponse login() throws Exception {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/api/v1/auth/login");
+        request.setRemoteAddr("203.0.113.7");
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        filter.doFilter(request, response, new MockFilterChain());
+        return response;
+    }
+}
@@ -0,0 +1,34 @@
+package com.kubesec.auth.config;
+
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+
+class DocsEnvironmentPostProcessorTest {
+
+    @ParameterizedTest
+    @ValueSource(strings = {"production", "PRODUCTION"})
+    void productionDisablesTheSpecAndTheDocsPages(String env) {
+        MockEnvironment environment = new MockEnvironment().withProperty("ENV", env)
+                .withProperty("springdoc.api-docs.enabled", "true");
+
+        new DocsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("false", environment.getProperty("springdoc.api-docs.enabled"));
+        assertEquals("false", environment.getProperty("springdoc.swagger-ui.enabled"));
+    }
+
+    @Test
+    void otherEnvironmentsKeepTheDocs() {
+        MockEnvironment environment = new MockEnvironment().withProperty("ENV", "staging");
+
+        new DocsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(environment.getProperty("springdoc.api-docs.enabled"));
+        assertNull(environment.getProperty("springdoc.swagger-ui.enabled"));
+    }
+}
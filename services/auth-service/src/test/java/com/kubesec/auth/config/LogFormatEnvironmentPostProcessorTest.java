@@ -0,0 +1,52 @@
+package com.kubesec.auth.config;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class LogFormatEnvironmentPostProcessorTest {
+
+    private static final String FORMAT = "logging.structured.format.console";
+
+    @Test
+    void productionDefaultsToJson() {
+        MockEnvironment environment = new MockEnvironment().withProperty("ENV", "production");
+
+        new LogFormatEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("ecs", environment.getProperty(FORMAT));
+    }
+
+    @Test
+    void explicitTextOverridesProduction() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("ENV", "production")
+                .withProperty("LOG_FORMAT", "text");
+
+        new LogFormatEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(environment.getProperty(FORMAT));
+    }
+
+    @Test
+    void developmentDefaultsToTextAndCanOptIntoJson() {
+        MockEnvironment environment = new MockEnvironment();
+        new LogFormatEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+        assertNull(environment.getProperty(FORMAT));
+
+        environment = new MockEnvironment().withProperty("LOG_FORMAT", "JSON");
+        new LogFormatEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+        assertEquals("ecs", environment.getProperty(FORMAT));
+    }
+
+    @Test
+    void unknownFormatFailsStartup() {
+        MockEnvironment environment = new MockEnvironment().withProperty("LOG_FORMAT", "xml");
+
+        assertThrows(IllegalStateException.class,
+                () -> new LogFormatEnvironmentPostProcessor().postProcessEnvironment(environment, null));
+    }
+}
@@ -0,0 +1,165 @@
+package com.kubesec.auth.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.GlobalExceptionHandler;
+import com.kubesec.auth.filter.AdminApiKeyFilter;
+import com.kubesec.auth.filter.TenantFilter;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.service.AuthService;
+import com.kubesec.auth.service.JwtService;
+import com.kubesec.auth.tenant.TenantContext;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import com.kubesec.auth.testdoubles.InMemoryTenantRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletResponse;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.nio.charset.StandardCharsets;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+import java.util.function.Consumer;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.content;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+class AuditLogControllerTest {
+
+    private static final String ADMIN_KEY = "test-admin-key";
+    private static final OffsetDateTime DAY1 = OffsetDateTime.of(2024, 3, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private AppConfig config;
+    private UUID tenantId;
+
+    @BeforeEach
+    void setUp() {
+        config = new AppConfig();
+        config.setJwtSecret("test-secret-that-is-long-enough-for-hs256");
+        config.setAdminApiKey(ADMIN_KEY);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void exportsRowsInDateRangeAsNdjson() throws Exception {
+        InMemoryAuthRepository repository = new InMemoryAuthRepository();
+        MockMvc mvc = mvc(repository);
+        record(repository, tenantId, "before", DAY1.minusSeconds(1));
+        record(repository, tenantId, "first", DAY1);
+        record(repository, tenantId, "last", DAY1.plusDays(1).plusHours(23));
+        record(repository, tenantId, "after", DAY1.plusDays(2));
+        record(repository, UUID.randomUUID(), "other-tenant", DAY1.plusHours(1));
+
+        String body = export(mvc, ADMIN_KEY, "2024-03-01", "2024-03-02")
+                .andExpect(status().isOk())
+                .andExpect(content().contentType(AuditLogController.NDJSON))
+                .andReturn().getResponse().getContentAsString();
+
+        List<String> lines = body.lines().toList();
+        assertEquals(2, lines.size());
+        assertEquals("first", objectMapper.readTree(lines.get(0)).get("email").asText());
+        assertEquals("last", objectMapper.readTree(lines.get(1)).get("email").asText());
+    }
+
+    @Test
+    void exportRequiresAdminApiKey() throws Exception {
+        MockMvc mvc = mvc(new InMemoryAuthRepository());
+
+        export(mvc, null, null, null).andExpect(status().isUnauthorized());
+        export(mvc, "wrong", null, null).andExpect(status().isUnauthorized());
+    }
+
+    @Test
+    void invertedRangeIsRejected() throws Exception {
+        MockMvc mvc = mvc(new InMemoryAuthRepository());
+
+        export(mvc, ADMIN_KEY, "2024-03-02", "2024-03-01").andExpect(status().isBadRequest());
+    }
+
+    // Each row must be on the wire before the next one is read from the cursor;
+    // anything else would buffer the result set in memory.
+    @Test
+    void rowsAreWrittenAsTheyAreRead() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        List<Integer> linesWrittenBeforeRow = new ArrayList<>();
+        InMemoryAuthRepository repository = new InMemoryAuthRepository() {
+            @Override
+            public void streamLoginAttempts(LoginAttemptAdminFilter filter, Consumer<LoginAttempt> consumer) {
+                super.streamLoginAttempts(filter, attempt -> {
+                    linesWrittenBeforeRow.add(lines(response));
+                    consumer.accept(attempt);
+                });
+            }
+        };
+        tenantId = UUID.randomUUID();
+        for (int i = 0; i < 1000; i++) {
+            record(repository, tenantId, "user" + i + "@example.com", DAY1.plusSeconds(i));
+        }
+
+        TenantContext.set(tenantId);
+        new AuditLogController(authService(repository), objectMapper).export(null, null, response);
+
+        assertEquals(1000, lines(response));
+        for (int i = 0; i < 1000; i++) {
+            assertEquals(i, linesWrittenBeforeRow.get(i), "lines written before row " + i);
+        }
+    }
+
+    private MockMvc mvc(InMemoryAuthRepository repository) {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+        return MockMvcBuilders.standaloneSetup(new AuditLogController(authService(repository), objectMapper))
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .addFilters(new TenantFilter(tenants), new AdminApiKeyFilter(config))
+                .build();
+    }
+
+    private AuthService authService(InMemoryAuthRepository repository) {
+        return AuthService.builder()
+                .withRepository(repository)
+                .withJwtService(new JwtService(config))
+                .withGeoIpLookup(new GeoIpLookup(config))
+                .build();
+    }
+
+    private ResultActions export(MockMvc mvc, String adminKey, String from, String to) throws Exception {
+        MockHttpServletRequestBuilder request = get("/api/v1/admin/audit-log/export")
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (adminKey != null) {
+            request.header("X-Admin-Api-Key", adminKey);
+        }
+        if (from != null) {
+            request.param("from", from);
+        }
+        if (to != null) {
+            request.param("to", to);
+        }
+        return mvc.perform(request);
+    }
+
+    private static int lines(MockHttpServletResponse response) {
+        return (int) new String(response.getContentAsByteArray(), StandardCharsets.UTF_8).chars()
+                .filter(c -> c == '\n').count();
+    }
+
+    private static void record(InMemoryAuthRepository repository, UUID tenant, String email, OffsetDateTime at) {
+        repository.recordLoginAttempt(new LoginAttempt(
+                UUID.randomUUID().toString(), tenant, email, null, false, "10.0.0.1", null, null, null, at));
+    }
+}
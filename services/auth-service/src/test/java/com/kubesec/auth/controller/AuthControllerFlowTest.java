@@ -0,0 +1,627 @@
+package com.kubesec.auth.controller;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.GlobalExceptionHandler;
+import com.kubesec.auth.filter.JwtAuthFilter;
+import com.kubesec.auth.filter.TenantFilter;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.Session;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.service.AuthService;
+import com.kubesec.auth.service.JwtService;
+import com.kubesec.auth.service.PasswordHasher;
+import com.kubesec.auth.service.Totp;
+import com.kubesec.auth.tenant.TenantContext;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import com.kubesec.auth.testdoubles.InMemoryTenantRepository;
+import com.kubesec.auth.validation.SchemaValidationAdvice;
+import com.kubesec.auth.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.time.Duration;
+import java.time.Instant;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Base64;
+import java.util.List;
+import java.util.UUID;
+import java.util.concurrent.CountDownLatch;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.Future;
+import java.util.concurrent.TimeUnit;
+
+import static org.hamcrest.Matchers.containsInAnyOrder;
+import static org.hamcrest.Matchers.startsWith;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.delete;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the login, validation and logout handlers through the tenant and JWT
+// filters with no database or Redis behind them.
+class AuthControllerFlowTest {
+
+    private static final String EMAIL = "alice@example.com";
+    private static final String PASSWORD = "secret";
+    // Hashed once; every hash costs the full PBKDF2 work factor.
+    private static final String PASSWORD_HASH = PasswordHasher.hash(PASSWORD);
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private UUID tenantId;
+    private InMemoryAuthRepository repository;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+
+        AppConfig config = new AppConfig();
+        config.setJwtSecret("test-secret-that-is-long-enough-for-hs256");
+        JwtService jwtService = new JwtService(config);
+        repository = new InMemoryAuthRepository();
+        addUser(EMAIL);
+        addUser("bob@example.com");
+        AuthService authService = AuthService.builder()
+                .withRepository(repository)
+                .withJwtService(jwtService)
+                .withGeoIpLookup(new GeoIpLookup(config))
+                .build();
+
+        mvc = MockMvcBuilders.standaloneSetup(new AuthController(authService))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants), new JwtAuthFilter(jwtService))
+                .build();
+    }
+
+    @Test
+    void logoutAllEndsEverySessionForTheUser() throws Exception {
+        String laptop = login("laptop");
+        String phone = login("phone");
+
+        mvc.perform(post("/api/v1/auth/logout-all")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + laptop))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.sessions_invalidated").value(2));
+
+        for (String token : new String[]{laptop, phone}) {
+            validate(token).andExpect(jsonPath("$.valid").value(false));
+            assertFalse(repository.findSession(token).isPresent());
+            assertFalse(repository.cachedSession(token).isPresent());
+        }
+    }
+
+    @Test
+    void logoutRevokesAccessToken() throws Exception {
+        String token = login();
+
+        validate(token).andExpect(jsonPath("$.valid").value(true))
+                .andExpect(jsonPath("$.email").value(EMAIL));
+        assertTrue(repository.findSession(token).isPresent());
+
+        mvc.perform(post("/api/v1/auth/logout")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token))
+                .andExpect(status().isOk());
+
+        validate(token).andExpect(jsonPath("$.valid").value(false));
+        assertFalse(repository.findSession(token).isPresent());
+        assertFalse(repository.cachedSession(token).isPresent());
+    }
+
+    @Test
+    void validationReportsTheStoredRole() throws Exception {
+        String token = login();
+        validate(token).andExpect(jsonPath("$.role").value("customer"));
+
+        repository.setUserRole("user-" + EMAIL, "admin");
+
+        validate(token).andExpect(jsonPath("$.role").value("admin"));
+    }
+
+    @Test
+    void pkceCodeIsExchangedForTokensOnce() throws Exception {
+        String verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk";
+        String code = authorize(login(), challenge(verifier));
+
+        String body = exchange(code, verifier).andExpect(status().isOk())
+                .andExpect(jsonPath("$.access_token").exists())
+                .andExpect(jsonPath("$.refresh_token").exists())
+                .andReturn().getResponse().getContentAsString();
+        validate(objectMapper.readTree(body).get("access_token").asText())
+                .andExpect(jsonPath("$.valid").value(true))
+                .andExpect(jsonPath("$.email").value(EMAIL));
+
+        // A replayed code was deleted by the first exchange.
+        exchange(code, verifier).andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.error").value("invalid authorization code"));
+    }
+
+    @Test
+    void pkceRejectsAVerifierThatDoesNotMatchTheChallenge() throws Exception {
+        String verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk";
+        String code = authorize(login(), challenge(verifier));
+
+        exchange(code, "a-different-verifier-that-is-long-enough-0123456789")
+                .andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.error").value("code_verifier does not match code_challenge"));
+        // The failed attempt used up the code, so guessing verifiers gets one try per code.
+        exchange(code, verifier).andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.error").value("invalid authorization code"));
+    }
+
+    @Test
+    void expiredPkceCodeIsRejected() throws Exception {
+        String verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk";
+        String code = authorize(login(), challenge(verifier));
+        repository.expireAuthCode(code);
+
+        exchange(code, verifier).andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.error").value("authorization code expired"));
+    }
+
+    @Test
+    void pkceAuthorizeRequiresALoggedInUserAndS256() throws Exception {
+        String challenge = challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk");
+
+        mvc.perform(post("/api/v1/auth/authorize")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"code_challenge\":\"" + challenge + "\",\"code_challenge_method\":\"S256\"}"))
+                .andExpect(status().isUnauthorized());
+        mvc.perform(post("/api/v1/auth/authorize")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + login())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"code_challenge\":\"" + challenge + "\",\"code_challenge_method\":\"plain\"}"))
+                .andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("schema_violation"));
+    }
+
+    @Test
+    void failedLoginCountOnlyIncludesWindow() throws Exception {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        recordFailure(tenantId, now.minusMinutes(20));
+        recordFailure(tenantId, now.minusMinutes(10));
+        recordFailure(tenantId, now.minusMinutes(1));
+        recordFailure(UUID.randomUUID(), now.minusMinutes(1));
+
+        String token = login();
+
+        mvc.perform(get("/api/v1/auth/login-attempts/failed")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token)
+                        .param("email", EMAIL)
+                        .param("since", now.minusMinutes(15).toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.failed_count").value(2));
+    }
+
+    @Test
+    void wrongPasswordOrUnknownEmailIsRejectedAndRecorded() throws Exception {
+        postLogin(EMAIL, "not-the-password").andExpect(status().isUnauthorized());
+        postLogin("mallory@example.com", PASSWORD).andExpect(status().isUnauthorized());
+
+        List<LoginAttempt> attempts = repository.loginAttempts();
+        assertEquals(2, attempts.size());
+        assertTrue(attempts.stream().noneMatch(LoginAttempt::success));
+        assertTrue(attempts.stream().allMatch(a -> a.userId() == null));
+
+        postLogin("  Alice@Example.com ", PASSWORD).andExpect(status().isOk());
+    }
+
+    @Test
+    void loginIsRateLimitedAfterFiveRecentFailures() throws Exception {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        for (int i = 0; i < 5; i++) {
+            recordFailure(tenantId, now.minusMinutes(i + 1));
+        }
+
+        mvc.perform(post("/api/v1/auth/login")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"" + EMAIL + "\",\"password\":\"secret\"}"))
+                .andExpect(status().isTooManyRequests())
+                .andExpect(jsonPath("$.code").value("rate_limited"));
+    }
+
+    @Test
+    void concurrentLoginsFromSameDeviceShareOneSession() throws Exception {
+        int logins = 10;
+        ExecutorService pool = Executors.newFixedThreadPool(logins);
+        CountDownLatch start = new CountDownLatch(1);
+        try {
+            List<Future<String>> tokens = new ArrayList<>();
+            for (int i = 0; i < logins; i++) {
+                tokens.add(pool.submit(() -> {
+                    start.await();
+                    return login("browser-tab-1-fingerprint");
+                }));
+            }
+            start.countDown();
+            for (Future<String> token : tokens) {
+                token.get(5, TimeUnit.SECONDS);
+            }
+        } finally {
+            pool.shutdownNow();
+        }
+
+        List<Session> sessions = repository.sessionsForUser("user-" + EMAIL);
+        assertEquals(1, sessions.size());
+        assertEquals("browser-tab-1-fingerprint", sessions.get(0).getDeviceFingerprint());
+    }
+
+    @Test
+    void activeTokensListEachDeviceOfTheCaller() throws Exception {
+        String laptop = loginFrom(EMAIL, "laptop-fingerprint-0001", "10.0.0.1", "Firefox/128.0");
+        String phone = loginFrom(EMAIL, "phone-fingerprint-00001", "10.0.0.2", "MobileSafari/17.0");
+        loginFrom("bob@example.com", "laptop-fingerprint-0001", "10.0.0.3", "Chrome/126.0");
+
+        activeTokens(laptop).andExpect(status().isOk())
+                .andExpect(jsonPath("$.tokens.length()").value(2))
+                .andExpect(jsonPath("$.tokens[*].ip_address", containsInAnyOrder("10.0.0.1", "10.0.0.2")))
+                .andExpect(jsonPath("$.tokens[*].user_agent", containsInAnyOrder("Firefox/128.0", "MobileSafari/17.0")))
+                .andExpect(jsonPath("$.tokens[0].token").doesNotExist());
+
+        mvc.perform(post("/api/v1/auth/logout")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + phone))
+                .andExpect(status().isOk());
+
+        activeTokens(laptop).andExpect(status().isOk())
+                .andExpect(jsonPath("$.tokens.length()").value(1))
+                .andExpect(jsonPath("$.tokens[0].user_agent").value("Firefox/128.0"));
+    }
+
+    @Test
+    void activeTokensRequireAuthentication() throws Exception {
+        mvc.perform(get("/api/v1/auth/tokens/active").header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isUnauthorized());
+    }
+
+    @Test
+    void unknownTenantIsRejected() throws Exception {
+        mvc.perform(post("/api/v1/auth/login")
+                        .header(TenantContext.HEADER, UUID.randomUUID().toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"" + EMAIL + "\",\"password\":\"secret\"}"))
+                .andExpect(status().isForbidden());
+    }
+
+    @Test
+    void blacklistedTokenExpires() throws Exception {
+        repository.blacklistToken("short-lived", Duration.ofMillis(50));
+        assertTrue(repository.isTokenBlacklisted("short-lived"));
+
+        long deadline = System.nanoTime() + Duration.ofSeconds(2).toNanos();
+        while (repository.isTokenBlacklisted("short-lived") && System.nanoTime() < deadline) {
+            Thread.sleep(10);
+        }
+        assertFalse(repository.isTokenBlacklisted("short-lived"));
+    }
+
+    @Test
+    void loginWithMfaEnabledRequiresTotpCode() throws Exception {
+        String secret = enableMfa(login());
+
+        String mfaToken = mfaLogin(null);
+        // Codes from the enrollment step are spent, so take the next one
+        String code = Totp.generate(secret, Instant.now().plusSeconds(30));
+        String body = verifyMfa(mfaToken, code)
+                .andExpect(status().isOk())
+                .andReturn().getResponse().getContentAsString();
+
+        String token = objectMapper.readTree(body).get("access_token").asText();
+        validate(token).andExpect(jsonPath("$.valid").value(true));
+        verifyMfa(mfaToken, code).andExpect(status().isUnauthorized());
+        verifyMfa(mfaLogin(null), code).andExpect(status().isUnauthorized());
+    }
+
+    @Test
+    void wrongTotpCodeCountsAsFailedLogin() throws Exception {
+        String secret = enableMfa(login());
+        String mfaToken = mfaLogin(null);
+        String wrong = Totp.generate(secret, Instant.now().plusSeconds(300));
+
+        verifyMfa(mfaToken, wrong)
+                .andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.code").value("unauthorized"));
+
+        assertEquals(1, repository.getRecentFailedAttempts(EMAIL, OffsetDateTime.now(ZoneOffset.UTC).minusMinutes(1)));
+    }
+
+    @Test
+    void mfaTokenIsNotASessionToken() throws Exception {
+        enableMfa(login());
+        String mfaToken = mfaLogin(null);
+
+        activeTokens(mfaToken).andExpect(status().isUnauthorized());
+        validate(mfaToken).andExpect(jsonPath("$.valid").value(false));
+    }
+
+    @Test
+    void trustedDeviceSkipsMfa() throws Exception {
+        String token = login("laptop-fingerprint-0001");
+        mvc.perform(post("/api/v1/auth/device/register")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token)
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"device_fingerprint\":\"laptop-fingerprint-0001\"}"))
+                .andExpect(status().isCreated());
+        enableMfa(token);
+
+        login("laptop-fingerprint-0001");
+        mfaLogin("phone-fingerprint-00001");
+    }
+
+    @Test
+    void registeringADeviceAgainRenamesItAndKeepsItsId() throws Exception {
+        String token = login();
+
+        String first = registerDevice(token, "laptop-fingerprint-0001", "Work laptop")
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.device_name").value("Work laptop"))
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(first).get("id").asText();
+        registerDevice(token, "laptop-fingerprint-0001", "Home laptop")
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.id").value(id));
+
+        devices(token).andExpect(status().isOk())
+                .andExpect(jsonPath("$.devices.length()").value(1))
+                .andExpect(jsonPath("$.devices[0].id").value(id))
+                .andExpect(jsonPath("$.devices[0].device_name").value("Home laptop"));
+    }
+
+    @Test
+    void devicesAreListedAndRevokedOnlyByTheirOwner() throws Exception {
+        String alice = login();
+        String bob = loginFrom("bob@example.com", "bob-laptop-fingerprint", "10.0.0.3", "Chrome/126.0");
+        String body = registerDevice(alice, "laptop-fingerprint-0001", "Work laptop")
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        devices(bob).andExpect(jsonPath("$.devices.length()").value(0));
+        revokeDevice(bob, id).andExpect(status().isNotFound());
+        devices(alice).andExpect(jsonPath("$.devices.length()").value(1));
+
+        revokeDevice(alice, id).andExpect(status().isNoContent());
+        revokeDevice(alice, id).andExpect(status().isNotFound());
+        devices(alice).andExpect(jsonPath("$.devices.length()").value(0));
+    }
+
+    @Test
+    void revokedDeviceNoLongerSkipsMfa() throws Exception {
+        String token = login("laptop-fingerprint-0001");
+        String body = registerDevice(token, "laptop-fingerprint-0001", null)
+                .andReturn().getResponse().getContentAsString();
+        enableMfa(token);
+        login("laptop-fingerprint-0001");
+
+        revokeDevice(token, objectMapper.readTree(body).get("id").asText()).andExpect(status().isNoContent());
+
+        mfaLogin("laptop-fingerprint-0001");
+    }
+
+    @Test
+    void expiredTrustRequiresMfaAgain() throws Exception {
+        String token = login();
+        enableMfa(token);
+        OffsetDateTime trustedAt = OffsetDateTime.now(ZoneOffset.UTC).minusDays(31);
+        repository.registerTrustedDevice(new TrustedDevice(UUID.randomUUID(), tenantId, "user-" + EMAIL,
+                "laptop-fingerprint-0001", "Old laptop", trustedAt, trustedAt.plusDays(30)));
+
+        mfaLogin("laptop-fingerprint-0001");
+        devices(token).andExpect(jsonPath("$.devices.length()").value(0));
+    }
+
+    @Test
+    void trustIsPerUser() throws Exception {
+        enableMfa(login());
+        String bob = loginFrom("bob@example.com", "shared-fingerprint-0001", "10.0.0.3", "Chrome/126.0");
+        registerDevice(bob, "shared-fingerprint-0001", "Kiosk").andExpect(status().isCreated());
+
+        mfaLogin("shared-fingerprint-0001");
+    }
+
+    @Test
+    void deviceEndpointsRequireAuthenticationAndAValidFingerprint() throws Exception {
+        mvc.perform(post("/api/v1/auth/device/register")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"device_fingerprint\":\"laptop-fingerprint-0001\"}"))
+                .andExpect(status().isUnauthorized());
+        mvc.perform(get("/api/v1/auth/devices").header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isUnauthorized());
+        mvc.perform(delete("/api/v1/auth/devices/" + UUID.randomUUID())
+                        .header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isUnauthorized());
+
+        registerDevice(login(), "too-short", null)
+                .andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("schema_violation"));
+    }
+
+    @Test
+    void disablingMfaRestoresPasswordOnlyLogin() throws Exception {
+        String token = login();
+        enableMfa(token);
+
+        mvc.perform(post("/api/v1/auth/mfa/enroll")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token))
+                .andExpect(status().isConflict());
+        mvc.perform(delete("/api/v1/auth/mfa")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token))
+                .andExpect(status().isNoContent());
+
+        login();
+    }
+
+    private String login() throws Exception {
+        return login(null);
+    }
+
+    private String login(String deviceFingerprint) throws Exception {
+        String fingerprint = deviceFingerprint == null ? "" : ",\"device_fingerprint\":\"" + deviceFingerprint + "\"";
+        String body = mvc.perform(post("/api/v1/auth/login")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"" + EMAIL + "\",\"password\":\"" + PASSWORD + "\"" + fingerprint + "}"))
+                .andExpect(status().isOk())
+                .andReturn().getResponse().getContentAsString();
+        JsonNode tokens = objectMapper.readTree(body);
+        return tokens.get("access_token").asText();
+    }
+
+    private String loginFrom(String email, String deviceFingerprint, String ip, String userAgent) throws Exception {
+        String body = mvc.perform(post("/api/v1/auth/login")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("User-Agent", userAgent)
+                        .with(request -> {
+                            request.setRemoteAddr(ip);
+                            return request;
+                        })
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"" + email + "\",\"password\":\"secret\",\"device_fingerprint\":\""
+                                + deviceFingerprint + "\"}"))
+                .andExpect(status().isOk())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("access_token").asText();
+    }
+
+    // Enrolls and confirms MFA for the logged-in user, returning the TOTP secret.
+    private String enableMfa(String token) throws Exception {
+        String body = mvc.perform(post("/api/v1/auth/mfa/enroll")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.provisioning_uri").value(startsWith("otpauth://totp/")))
+                .andReturn().getResponse().getContentAsString();
+        String secret = objectMapper.readTree(body).get("secret").asText();
+
+        mvc.perform(post("/api/v1/auth/mfa/confirm")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + token)
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"totp_code\":\"" + Totp.generate(secret, Instant.now()) + "\"}"))
+                .andExpect(status().isOk());
+        return secret;
+    }
+
+    private String mfaLogin(String deviceFingerprint) throws Exception {
+        String fingerprint = deviceFingerprint == null ? "" : ",\"device_fingerprint\":\"" + deviceFingerprint + "\"";
+        String body = mvc.perform(post("/api/v1/auth/login")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"" + EMAIL + "\",\"password\":\"" + PASSWORD + "\"" + fingerprint + "}"))
+                .andExpect(status().isAccepted())
+                .andExpect(jsonPath("$.expires_in").value(300))
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("mfa_token").asText();
+    }
+
+    private ResultActions verifyMfa(String mfaToken, String code) throws Exception {
+        return mvc.perform(post("/api/v1/auth/mfa/verify")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"mfa_token\":\"" + mfaToken + "\",\"totp_code\":\"" + code + "\"}"));
+    }
+
+    private ResultActions registerDevice(String token, String fingerprint, String name) throws Exception {
+        String deviceName = name == null ? "" : ",\"device_name\":\"" + name + "\"";
+        return mvc.perform(post("/api/v1/auth/device/register")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header("Authorization", "Bearer " + token)
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"device_fingerprint\":\"" + fingerprint + "\"" + deviceName + "}"));
+    }
+
+    private ResultActions devices(String token) throws Exception {
+        return mvc.perform(get("/api/v1/auth/devices")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header("Authorization", "Bearer " + token));
+    }
+
+    private ResultActions revokeDevice(String token, String id) throws Exception {
+        return mvc.perform(delete("/api/v1/auth/devices/" + id)
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header("Authorization", "Bearer " + token));
+    }
+
+    private ResultActions activeTokens(String token) throws Exception {
+        return mvc.perform(get("/api/v1/auth/tokens/active")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .header("Authorization", "Bearer " + token));
+    }
+
+    private String authorize(String accessToken, String challenge) throws Exception {
+        String body = mvc.perform(post("/api/v1/auth/authorize")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("Authorization", "Bearer " + accessToken)
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"code_challenge\":\"" + challenge + "\",\"code_challenge_method\":\"S256\"}"))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.expires_in").isNumber())
+                .andReturn().getResponse().getContentAsString();
+        return objectMapper.readTree(body).get("code").asText();
+    }
+
+    private ResultActions exchange(String code, String verifier) throws Exception {
+        return mvc.perform(post("/api/v1/auth/token")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"code\":\"" + code + "\",\"code_verifier\":\"" + verifier + "\"}"));
+    }
+
+    // BASE64URL(SHA256(verifier)), as RFC 7636 section 4.2 derives the S256 challenge.
+    private static String challenge(String verifier) throws Exception {
+        byte[] digest = MessageDigest.getInstance("SHA-256").digest(verifier.getBytes(StandardCharsets.US_ASCII));
+        return Base64.getUrlEncoder().withoutPadding().encodeToString(digest);
+    }
+
+    private ResultActions validate(String token) throws Exception {
+        return mvc.perform(post("/api/v1/auth/validate")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"token\":\"" + token + "\"}"))
+                .andExpect(status().isOk());
+    }
+
+    private void addUser(String email) {
+        repository.createUserCredentials(new UserCredentials("user-" + email, tenantId, email, PASSWORD_HASH, true,
+                OffsetDateTime.now(ZoneOffset.UTC)));
+    }
+
+    private ResultActions postLogin(String email, String password) throws Exception {
+        return mvc.perform(post("/api/v1/auth/login")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"email\":\"" + email + "\",\"password\":\"" + password + "\"}"));
+    }
+
+    private void recordFailure(UUID tenant, OffsetDateTime at) {
+        repository.recordLoginAttempt(new LoginAttempt(
+                UUID.randomUUID().toString(), tenant, EMAIL, null, false, "10.0.0.1", null, null, null, at));
+    }
+}
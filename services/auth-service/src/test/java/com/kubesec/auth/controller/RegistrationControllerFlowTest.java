@@ -0,0 +1,201 @@
+package com.kubesec.auth.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.client.AccountServiceClient;
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.GlobalExceptionHandler;
+import com.kubesec.auth.filter.TenantFilter;
+import com.kubesec.auth.model.EmailVerificationToken;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.model.dto.RegistrationRequestedEvent;
+import com.kubesec.auth.service.PasswordHasher;
+import com.kubesec.auth.service.RegistrationPublisher;
+import com.kubesec.auth.service.RegistrationService;
+import com.kubesec.auth.tenant.TenantContext;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import com.kubesec.auth.testdoubles.InMemoryTenantRepository;
+import com.kubesec.auth.validation.SchemaValidationAdvice;
+import com.kubesec.auth.validation.SchemaValidator;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.mockito.ArgumentCaptor;
+import org.springframework.http.MediaType;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.client.RestClient;
+
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.util.ArrayList;
+import java.util.HexFormat;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+
+import static org.hamcrest.Matchers.containsString;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNotEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives self-registration with account-service stubbed out and the publisher mocked.
+class RegistrationControllerFlowTest {
+
+    private static final String PASSWORD = "Quartz-Lantern-Velvet-42";
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private UUID tenantId;
+    private InMemoryAuthRepository repository;
+    private StubAccountServiceClient accounts;
+    private RegistrationPublisher publisher;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+
+        AppConfig config = new AppConfig();
+        repository = new InMemoryAuthRepository();
+        accounts = new StubAccountServiceClient(config);
+        publisher = mock(RegistrationPublisher.class);
+        RegistrationService registrationService = new RegistrationService(repository, accounts, config, publisher);
+
+        mvc = MockMvcBuilders.standaloneSetup(new RegistrationController(registrationService))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void registrationStoresAHashAndPublishesAVerificationToken() throws Exception {
+        String body = register("Alice@Example.com", PASSWORD, "Alice")
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.requires_email_verification").value(true))
+                .andReturn().getResponse().getContentAsString();
+        String userId = objectMapper.readTree(body).get("user_id").asText();
+
+        assertEquals(List.of("alice@example.com"), accounts.created);
+        UserCredentials stored = credentials("alice@example.com");
+        assertEquals(userId, stored.userId());
+        assertFalse(stored.emailVerified());
+        assertNotEquals(PASSWORD, stored.passwordHash());
+        assertTrue(PasswordHasher.verify(PASSWORD, stored.passwordHash()));
+        assertFalse(PasswordHasher.verify("Wrong-Horse-42", stored.passwordHash()));
+
+        ArgumentCaptor<RegistrationRequestedEvent> event = ArgumentCaptor.forClass(RegistrationRequestedEvent.class);
+        verify(publisher).publish(event.capture());
+        assertEquals(userId, event.getValue().userId());
+        assertEquals(tenantId, event.getValue().tenantId());
+
+        // Only the token's hash is kept.
+        List<EmailVerificationToken> tokens = repository.verificationTokens();
+        assertEquals(1, tokens.size());
+        assertEquals(sha256Hex(event.getValue().verificationToken()), tokens.get(0).tokenHash());
+        assertEquals(event.getValue().expiresAt(), tokens.get(0).expiresAt());
+    }
+
+    @ParameterizedTest
+    @ValueSource(strings = {"not-an-email", "alice@", "@example.com", ""})
+    void malformedEmailIsRejected(String email) throws Exception {
+        register(email, PASSWORD, "Alice").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("schema_violation"));
+
+        assertTrue(accounts.created.isEmpty());
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            "Short-42,            at least 12 characters",
+            "lowercase-only-42,   an uppercase letter",
+            "No-Digits-At-All,    a digit",
+            "NoSpecialChars42,    a special character",
+    })
+    void eachPasswordRuleIsEnforced(String password, String rule) throws Exception {
+        register("alice@example.com", password, "Alice")
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.code").value("validation_error"))
+                .andExpect(jsonPath("$.error").value(containsString(rule)));
+
+        assertTrue(accounts.created.isEmpty());
+        verify(publisher, never()).publish(any());
+    }
+
+    @Test
+    void predictablePasswordIsRejectedWithFeedback() throws Exception {
+        register("alice@example.com", "Password123!", "Alice")
+                .andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.error").value("password too weak"))
+                .andExpect(jsonPath("$.score").isNumber())
+                .andExpect(jsonPath("$.suggestions").isArray());
+
+        assertTrue(accounts.created.isEmpty());
+    }
+
+    @Test
+    void missingFieldsAreRejected() throws Exception {
+        mvc.perform(post("/api/v1/auth/register")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"email\":\"alice@example.com\",\"password\":\"" + PASSWORD + "\"}"))
+                .andExpect(status().isUnprocessableEntity());
+    }
+
+    @Test
+    void duplicateEmailIsRejectedBeforeAccountServiceIsCalled() throws Exception {
+        register("alice@example.com", PASSWORD, "Alice").andExpect(status().isCreated());
+
+        register("ALICE@example.com", PASSWORD, "Alice Again").andExpect(status().isConflict());
+
+        assertEquals(1, accounts.created.size());
+    }
+
+    private UserCredentials credentials(String email) {
+        TenantContext.set(tenantId);
+        try {
+            return repository.getUserCredentialsByEmail(email).orElseThrow();
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    private ResultActions register(String email, String password, String fullName) throws Exception {
+        return mvc.perform(post("/api/v1/auth/register")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content(objectMapper.writeValueAsString(
+                        Map.of("email", email, "password", password, "full_name", fullName))));
+    }
+
+    private static String sha256Hex(String value) throws Exception {
+        return HexFormat.of().formatHex(
+                MessageDigest.getInstance("SHA-256").digest(value.getBytes(StandardCharsets.UTF_8)));
+    }
+
+    private static class StubAccountServiceClient extends AccountServiceClient {
+
+        final List<String> created = new ArrayList<>();
+
+        StubAccountServiceClient(AppConfig config) {
+            super(config, RestClient.builder());
+        }
+
+        @Override
+        public String createUser(String email, String fullName) {
+            created.add(email);
+            return UUID.randomUUID().toString();
+        }
+    }
+}
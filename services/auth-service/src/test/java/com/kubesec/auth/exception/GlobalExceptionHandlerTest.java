@@ -0,0 +1,101 @@
+package com.kubesec.auth.exception;
+
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.Arguments;
+import org.junit.jupiter.params.provider.MethodSource;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.List;
+import java.util.stream.Stream;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+class GlobalExceptionHandlerTest {
+
+    private final ThrowingController controller = new ThrowingController();
+    private MockMvc mockMvc;
+
+    @BeforeEach
+    void setUp() {
+        mockMvc = MockMvcBuilders.standaloneSetup(controller)
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .build();
+    }
+
+    static Stream<Arguments> domainExceptions() {
+        return Stream.of(
+                Arguments.of(new ConflictException("email already registered"), 409,
+                        "conflict", "email already registered"),
+                Arguments.of(new ForbiddenException("mfa required"), 403, "forbidden", "mfa required"),
+                Arguments.of(new RateLimitedException("too many attempts"), 429, "rate_limited", "too many attempts"),
+                Arguments.of(new ResourceNotFoundException("user not found"), 404, "not_found", "user not found"),
+                Arguments.of(new UnauthorizedException("invalid credentials"), 401,
+                        "unauthorized", "invalid credentials"),
+                Arguments.of(new UpstreamException("mail relay unavailable"), 502,
+                        "upstream_error", "mail relay unavailable"),
+                Arguments.of(new ValidationException("email is required"), 400, "validation_error", "email is required"));
+    }
+
+    @ParameterizedTest
+    @MethodSource("domainExceptions")
+    void domainExceptionsMapToTheirStatusAndCode(DomainException ex, int status, String code, String error)
+            throws Exception {
+        controller.next = ex;
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(status))
+                .andExpect(jsonPath("$.error").value(error))
+                .andExpect(jsonPath("$.code").value(code));
+    }
+
+    @Test
+    void schemaViolationsListEachViolation() throws Exception {
+        controller.next = new SchemaViolationException(List.of("email: required"));
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.error").value("request body failed validation"))
+                .andExpect(jsonPath("$.code").value("schema_violation"))
+                .andExpect(jsonPath("$.violations[0]").value("email: required"));
+    }
+
+    @Test
+    void weakPasswordsReportScoreAndSuggestions() throws Exception {
+        controller.next = new WeakPasswordException(1, List.of("add another word or two"));
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.error").value("password too weak"))
+                .andExpect(jsonPath("$.code").value("weak_password"))
+                .andExpect(jsonPath("$.score").value(1))
+                .andExpect(jsonPath("$.suggestions[0]").value("add another word or two"));
+    }
+
+    @Test
+    void unexpectedExceptionsAreHiddenBehindA500() throws Exception {
+        controller.next = new IllegalStateException("connection pool exhausted");
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(500))
+                .andExpect(jsonPath("$.error").value("internal error"))
+                .andExpect(jsonPath("$.code").doesNotExist());
+    }
+
+    @RestController
+    static class ThrowingController {
+
+        RuntimeException next;
+
+        @GetMapping("/throw")
+        void fail() {
+            throw next;
+        }
+    }
+}
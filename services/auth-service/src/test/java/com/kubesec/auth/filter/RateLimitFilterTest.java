@@ -0,0 +1,72 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.RateLimitSettings;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.time.Duration;
+import java.time.Instant;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class RateLimitFilterTest {
+
+    @Test
+    void headersCountDownTheBudget() throws Exception {
+        RateLimitFilter filter = new RateLimitFilter(new InMemoryRateLimiter());
+        filter.updateSettings(new RateLimitSettings(2, Duration.ofSeconds(30)));
+
+        MockHttpServletResponse first = login(filter);
+        assertEquals(200, first.getStatus());
+        assertEquals("2", first.getHeader("RateLimit-Limit"));
+        assertEquals("1", first.getHeader("RateLimit-Remaining"));
+        assertReset(first, 29, 30);
+
+        MockHttpServletResponse second = login(filter);
+        assertEquals("0", second.getHeader("RateLimit-Remaining"));
+
+        MockHttpServletResponse rejected = login(filter);
+        assertEquals(429, rejected.getStatus());
+        assertEquals("2", rejected.getHeader("RateLimit-Limit"));
+        assertEquals("0", rejected.getHeader("RateLimit-Remaining"));
+        assertReset(rejected, 29, 30);
+    }
+
+    @Test
+    void resetIsSecondsUntilTheOldestRequestLeavesTheWindow() throws Exception {
+        // The window's oldest request leaves it in 12.3 seconds.
+        RateLimiter limiter = (key, settings, now) -> new RateLimiter.Decision(true, 5, Instant.now().plusMillis(12_300));
+        RateLimitFilter filter = new RateLimitFilter(limiter);
+
+        assertReset(login(filter), 12, 13);
+
+        RateLimiter elapsed = (key, settings, now) -> new RateLimiter.Decision(true, 5, now.minusSeconds(1));
+        assertEquals("0", login(new RateLimitFilter(elapsed)).getHeader("RateLimit-Reset"));
+    }
+
+    @Test
+    void onlyAuthRoutesAreLimited() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        new RateLimitFilter(new InMemoryRateLimiter())
+                .doFilter(new MockHttpServletRequest("GET", "/health"), response, (req, res) -> { });
+
+        assertNull(response.getHeader("RateLimit-Limit"));
+    }
+
+    private static MockHttpServletResponse login(RateLimitFilter filter) throws Exception {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/api/v1/auth/login");
+        request.setRemoteAddr("203.0.113.7");
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        filter.doFilter(request, response, (req, res) -> { });
+        return response;
+    }
+
+    private static void assertReset(MockHttpServletResponse response, int min, int max) {
+        int reset = Integer.parseInt(response.getHeader("RateLimit-Reset"));
+        assertTrue(reset >= min && reset <= max, "RateLimit-Reset was " + reset);
+    }
+}
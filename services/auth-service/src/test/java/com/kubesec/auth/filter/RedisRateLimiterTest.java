@@ -0,0 +1,122 @@
+package com.kubesec.auth.filter;
+
+import com.kubesec.auth.config.RateLimitSettings;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+import org.springframework.data.redis.RedisConnectionFailureException;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.data.redis.core.script.RedisScript;
+
+import java.time.Duration;
+import java.time.Instant;
+import java.util.LinkedHashMap;
+import java.util.List;
+import java.util.Map;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.anyList;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.doThrow;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+// Backs the limiter with a mocked template that plays the acquire script against a
+// map standing in for the sorted set, so what the script leaves behind can be observed.
+class RedisRateLimiterTest {
+
+    private static final RateLimitSettings SETTINGS = new RateLimitSettings(3, Duration.ofSeconds(60));
+    private static final Instant T0 = Instant.parse("2024-03-01T12:00:00Z");
+
+    // member -> score, as ZADD would store them
+    private final Map<String, Long> stored = new LinkedHashMap<>();
+    private StringRedisTemplate redis;
+
+    @BeforeEach
+    void setUp() {
+        redis = mock(StringRedisTemplate.class);
+        when(redis.execute(eq(RedisRateLimiter.ACQUIRE), anyList(), any(Object[].class)))
+                .thenAnswer(inv -> runScript(inv.getArguments()));
+    }
+
+    @Test
+    void rejectedRequestsAreNotCounted() {
+        RedisRateLimiter limiter = new RedisRateLimiter(redis);
+
+        for (int i = 0; i < 3; i++) {
+            RateLimiter.Decision decision = limiter.acquire("10.0.0.1", SETTINGS, T0.plusSeconds(i));
+            assertTrue(decision.allowed());
+            assertEquals(2 - i, decision.remaining());
+            assertEquals(T0.plusSeconds(60), decision.reset());
+        }
+
+        RateLimiter.Decision rejected = limiter.acquire("10.0.0.1", SETTINGS, T0.plusSeconds(5));
+
+        assertFalse(rejected.allowed());
+        assertEquals(0, rejected.remaining());
+        assertEquals(T0.plusSeconds(60), rejected.reset());
+        assertEquals(3, stored.size());
+    }
+
+    @Test
+    void requestsAgeOutOfTheWindow() {
+        RedisRateLimiter limiter = new RedisRateLimiter(redis);
+        for (int i = 0; i < 3; i++) {
+            limiter.acquire("10.0.0.1", SETTINGS, T0.plusSeconds(i));
+        }
+
+        RateLimiter.Decision decision = limiter.acquire("10.0.0.1", SETTINGS, T0.plusSeconds(60));
+
+        assertTrue(decision.allowed());
+        assertEquals(0, decision.remaining());
+        assertEquals(T0.plusSeconds(61), decision.reset(), "the oldest request still counted is at T0+1s");
+    }
+
+    // Redis Cluster only runs a script whose keys all hash to one slot; a single key
+    // always does.
+    @Test
+    @SuppressWarnings("unchecked")
+    void eachRequestRunsOneScriptOnOneKey() {
+        new RedisRateLimiter(redis).acquire("10.0.0.1", SETTINGS, T0);
+
+        ArgumentCaptor<List<String>> keys = ArgumentCaptor.forClass(List.class);
+        verify(redis).execute(eq(RedisRateLimiter.ACQUIRE), keys.capture(), any(Object[].class));
+        assertEquals(List.of("ratelimit:10.0.0.1"), keys.getValue());
+    }
+
+    @Test
+    void countsLocallyWhileRedisIsUnreachable() {
+        doThrow(new RedisConnectionFailureException("down"))
+                .when(redis).execute(any(RedisScript.class), anyList(), any(Object[].class));
+        RedisRateLimiter limiter = new RedisRateLimiter(redis);
+
+        for (int i = 0; i < 3; i++) {
+            assertTrue(limiter.acquire("10.0.0.1", SETTINGS, T0).allowed());
+        }
+        assertFalse(limiter.acquire("10.0.0.1", SETTINGS, T0).allowed());
+    }
+
+    // Mirrors RedisRateLimiter.ACQUIRE; arguments are the script, its keys, then ARGV.
+    private List<Long> runScript(Object[] args) {
+        long windowStart = Long.parseLong((String) args[2]);
+        long now = Long.parseLong((String) args[3]);
+        String member = (String) args[4];
+        int limit = Integer.parseInt((String) args[6]);
+
+        stored.values().removeIf(score -> score <= windowStart);
+        stored.put(member, now);
+        long count = stored.size();
+        long allowed = 1;
+        if (count > limit) {
+            stored.remove(member);
+            count--;
+            allowed = 0;
+        }
+        long oldest = stored.values().stream().mapToLong(Long::longValue).min().orElse(now);
+        return List.of(allowed, count, oldest);
+    }
+}
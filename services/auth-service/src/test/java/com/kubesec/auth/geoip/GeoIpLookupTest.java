@@ -0,0 +1,49 @@
+package com.kubesec.auth.geoip;
+
+import com.kubesec.auth.config.AppConfig;
+import org.junit.jupiter.api.BeforeAll;
+import org.junit.jupiter.api.Test;
+
+import java.io.File;
+import java.util.Optional;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+// Looks addresses up in test-city.mmdb, a small GeoLite2-City shaped database built by
+// src/test/resources/geoip/generate.py.
+class GeoIpLookupTest {
+
+    private static GeoIpLookup lookup;
+
+    @BeforeAll
+    static void openDatabase() throws Exception {
+        lookup = new GeoIpLookup(new File(GeoIpLookupTest.class.getResource("/geoip/test-city.mmdb").toURI()));
+    }
+
+    @Test
+    void resolvesCountryAndCity() {
+        assertEquals(Optional.of(new GeoLocation("GB", "London")), lookup.lookup("81.2.69.142"));
+        assertEquals(Optional.of(new GeoLocation("SE", "Stockholm")), lookup.lookup("89.160.20.128"));
+        assertEquals(Optional.of("US"), lookup.countryForIp("216.160.83.56"));
+    }
+
+    @Test
+    void networkKnownOnlyToACountryHasNoCity() {
+        assertEquals(Optional.of(new GeoLocation("GB", null)), lookup.lookup("2.125.160.216"));
+    }
+
+    @Test
+    void addressesOutsideTheDatabaseAreNotLocated() {
+        assertEquals(Optional.empty(), lookup.lookup("1.1.1.1"));
+        assertEquals(Optional.empty(), lookup.lookup("2001:db8::1"));
+        assertEquals(Optional.empty(), lookup.lookup(null));
+        assertEquals(Optional.empty(), lookup.lookup(" "));
+    }
+
+    @Test
+    void withoutADatabaseNothingIsLocated() {
+        GeoIpLookup unconfigured = new GeoIpLookup(new AppConfig());
+
+        assertEquals(Optional.empty(), unconfigured.lookup("81.2.69.142"));
+    }
+}
@@ -0,0 +1,223 @@
+package com.kubesec.auth.repository;
+
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.ValidationException;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.model.Cursor;
+import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.LoginAttemptPage;
+import com.kubesec.auth.service.AuthService;
+import com.kubesec.auth.service.JwtService;
+import com.kubesec.auth.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Arrays;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNotNull;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.mockito.Mockito.mock;
+
+// Runs the admin login attempt search against H2 with attempts spread over two IPs,
+// two emails, both outcomes and five hours, plus one attempt in another tenant that
+// every filter shares values with and must never return.
+class LoginAttemptFilterTest {
+
+    private static final OffsetDateTime T0 = OffsetDateTime.of(2026, 3, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+
+    private final UUID tenant = UUID.randomUUID();
+
+    private AuthRepositoryImpl repository;
+    private AuthService authService;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE TABLE login_attempts ("
+                + "id VARCHAR(64) PRIMARY KEY, tenant_id UUID NOT NULL, email VARCHAR(255) NOT NULL,"
+                + " user_id VARCHAR(64), success BOOLEAN NOT NULL DEFAULT FALSE, ip_address VARCHAR(45) NOT NULL,"
+                + " ip_country VARCHAR(2), ip_city VARCHAR(128), user_agent VARCHAR(512),"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new AuthRepositoryImpl(jdbc, mock(StringRedisTemplate.class), new SchemaReadiness(jdbc, config));
+        config.setJwtSecret("test-secret-that-is-long-enough-for-hs256");
+        authService = AuthService.builder()
+                .withRepository(repository)
+                .withJwtService(new JwtService(config))
+                .withGeoIpLookup(new GeoIpLookup(config))
+                .build();
+
+        TenantContext.set(UUID.randomUUID());
+        record("x1", "alice@example.com", false, "10.0.0.1", 2);
+
+        TenantContext.set(tenant);
+        record("a1", "alice@example.com", true, "10.0.0.1", 1);
+        record("a2", "alice@example.com", false, "10.0.0.1", 2);
+        record("a3", "alice@example.com", false, "10.0.0.2", 3);
+        record("a4", "bob@example.com", false, "10.0.0.1", 4);
+        record("a5", "bob@example.com", true, "10.0.0.2", 5);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            // ip, email, success, from hour, to hour, expected ids newest first
+            ",,,,, a5 a4 a3 a2 a1",
+            "10.0.0.1,,,,, a4 a2 a1",
+            ",alice@example.com,,,, a3 a2 a1",
+            ",,false,,, a4 a3 a2",
+            ",,true,,, a5 a1",
+            // from is inclusive, to is exclusive
+            ",,,3,, a5 a4 a3",
+            ",,,,3, a2 a1",
+            ",,,2,4, a3 a2",
+            "10.0.0.1,alice@example.com,,,, a2 a1",
+            "10.0.0.2,,false,,, a3",
+            ",bob@example.com,true,,, a5",
+            "10.0.0.1,,,2,5, a4 a2",
+            ",alice@example.com,false,3,, a3",
+            "10.0.0.1,alice@example.com,false,,, a2",
+            "10.0.0.1,alice@example.com,false,1,3, a2",
+            "10.0.0.2,bob@example.com,true,5,6, a5",
+            // Combinations that match nothing
+            "10.0.0.2,alice@example.com,true,,, ''",
+            ",bob@example.com,,,4, ''",
+            "10.0.0.9,,,,, ''"
+    })
+    void eachFilterCombinationNarrowsTheResults(String ip, String email, Boolean success, Integer fromHour,
+                                                 Integer toHour, String expected) {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setIpAddress(ip);
+        filter.setEmail(email);
+        filter.setSuccess(success);
+        filter.setFrom(fromHour != null ? T0.plusHours(fromHour) : null);
+        filter.setTo(toHour != null ? T0.plusHours(toHour) : null);
+
+        List<String> ids = expected.isEmpty() ? List.of() : Arrays.asList(expected.split(" "));
+        assertEquals(ids, ids(repository.listLoginAttempts(filter)));
+        assertEquals(ids.size(), repository.countLoginAttempts(filter));
+    }
+
+    @Test
+    void emptyStringsAreNotFilters() {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setIpAddress("");
+        filter.setEmail("");
+
+        assertEquals(List.of("a5", "a4", "a3", "a2", "a1"), ids(repository.listLoginAttempts(filter)));
+    }
+
+    @Test
+    void filteredResultsPageByOffsetAndByCursor() {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setSuccess(false);
+        filter.setLimit(2);
+
+        assertEquals(List.of("a4", "a3"), ids(repository.listLoginAttempts(filter)));
+
+        filter.setOffset(2);
+        assertEquals(List.of("a2"), ids(repository.listLoginAttempts(filter)));
+
+        // The cursor takes precedence over the offset
+        filter.setCursor(new Cursor(T0.plusHours(3), "a3"));
+        assertEquals(List.of("a2"), ids(repository.listLoginAttempts(filter)));
+        assertEquals(3, repository.countLoginAttempts(filter));
+    }
+
+    // Walks every page through AuthService, which hands out the cursor for the next one.
+    @Test
+    void pagesFollowTheNextCursorUntilItRunsOut() {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setLimit(2);
+
+        LoginAttemptPage first = authService.listLoginAttempts(filter);
+        assertEquals(List.of("a5", "a4"), ids(first.attempts()));
+        assertNotNull(first.nextCursor());
+
+        filter.setCursor(Cursor.decode(first.nextCursor()));
+        LoginAttemptPage middle = authService.listLoginAttempts(filter);
+        assertEquals(List.of("a3", "a2"), ids(middle.attempts()));
+        assertNotNull(middle.nextCursor());
+
+        filter.setCursor(Cursor.decode(middle.nextCursor()));
+        LoginAttemptPage last = authService.listLoginAttempts(filter);
+        assertEquals(List.of("a1"), ids(last.attempts()));
+        assertNull(last.nextCursor());
+    }
+
+    @Test
+    void pageThatEndsTheResultsExactlyHasNoNextCursor() {
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setSuccess(true);
+        filter.setLimit(2);
+
+        LoginAttemptPage page = authService.listLoginAttempts(filter);
+
+        assertEquals(List.of("a5", "a1"), ids(page.attempts()));
+        assertNull(page.nextCursor());
+        assertEquals(2, filter.getLimit(), "the look-ahead row is not left on the filter");
+    }
+
+    // Attempts recorded in the same instant are ordered by id, so a cursor between
+    // them neither repeats nor skips one.
+    @Test
+    void cursorSplitsAttemptsRecordedInTheSameInstant() {
+        record("b1", "carol@example.com", false, "10.0.0.3", 6);
+        record("b2", "carol@example.com", false, "10.0.0.3", 6);
+        record("b3", "carol@example.com", false, "10.0.0.3", 6);
+        LoginAttemptAdminFilter filter = new LoginAttemptAdminFilter();
+        filter.setEmail("carol@example.com");
+        filter.setLimit(2);
+
+        LoginAttemptPage first = authService.listLoginAttempts(filter);
+        filter.setCursor(Cursor.decode(first.nextCursor()));
+        LoginAttemptPage second = authService.listLoginAttempts(filter);
+
+        assertEquals(List.of("b3", "b2"), ids(first.attempts()));
+        assertEquals(List.of("b1"), ids(second.attempts()));
+        assertNull(second.nextCursor());
+    }
+
+    @ParameterizedTest
+    @ValueSource(strings = {
+            "not a cursor!",
+            "bm8tc2VwYXJhdG9y",          // "no-separator"
+            "eWVzdGVyZGF5fGEz",          // "yesterday|a3"
+            "MjAyNi0wMy0wMVQwMzowMFp8"   // "2026-03-01T03:00Z|", no id
+    })
+    void malformedCursorsAreRejected(String cursor) {
+        ValidationException e = assertThrows(ValidationException.class, () -> Cursor.decode(cursor));
+        assertEquals("invalid cursor", e.getMessage());
+    }
+
+    private void record(String id, String email, boolean success, String ip, int hour) {
+        repository.recordLoginAttempt(new LoginAttempt(id, TenantContext.require(), email, null, success, ip,
+                null, null, "test-agent", T0.plusHours(hour)));
+    }
+
+    private static List<String> ids(List<LoginAttempt> attempts) {
+        return attempts.stream().map(LoginAttempt::id).toList();
+    }
+}
@@ -0,0 +1,33 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import org.junit.jupiter.api.Test;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class AuthServiceBuilderTest {
+
+    @Test
+    void validateNamesEveryMissingRequiredDependency() {
+        IllegalStateException e = assertThrows(IllegalStateException.class,
+                () -> AuthService.builder().withRepository(new InMemoryAuthRepository()).validate());
+
+        assertEquals("missing required dependencies: jwtService, geoIpLookup", e.getMessage());
+    }
+
+    @Test
+    void suspiciousLoginPublisherIsOptional() {
+        AppConfig config = new AppConfig();
+        config.setJwtSecret("test-secret-that-is-long-enough-for-hs256");
+        AuthService.Builder builder = AuthService.builder()
+                .withRepository(new InMemoryAuthRepository())
+                .withJwtService(new JwtService(config))
+                .withGeoIpLookup(new GeoIpLookup(config));
+
+        assertDoesNotThrow(builder::build);
+    }
+}
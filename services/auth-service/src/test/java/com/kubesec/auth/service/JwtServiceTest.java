@@ -0,0 +1,153 @@
+package com.kubesec.auth.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.auth.config.AppConfig;
+import io.jsonwebtoken.Claims;
+import io.jsonwebtoken.JwtException;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.io.TempDir;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.security.KeyPair;
+import java.security.KeyPairGenerator;
+import java.security.spec.ECGenParameterSpec;
+import java.util.Base64;
+import java.util.List;
+import java.util.Map;
+import java.util.Set;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class JwtServiceTest {
+
+    private static final String SECRET = "test-secret-that-is-long-enough-for-hs256";
+    private static final UUID TENANT = UUID.randomUUID();
+
+    @TempDir
+    Path dir;
+
+    @ParameterizedTest
+    @CsvSource({"RS256, RSA", "ES256, EC"})
+    void signsWithPrivateKeyAndPublishesItsPublicHalf(String method, String kty) throws Exception {
+        JwtService service = new JwtService(asymmetricConfig(method, keyPair(kty)));
+
+        String token = service.issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        Map<String, Object> header = header(token);
+        assertEquals(method, header.get("alg"));
+        Claims claims = service.parseToken(token);
+        assertEquals("user-1", claims.get("user_id", String.class));
+
+        List<?> keys = (List<?>) service.jwks().get("keys");
+        assertEquals(1, keys.size());
+        Map<?, ?> jwk = (Map<?, ?>) keys.get(0);
+        assertEquals(kty, jwk.get("kty"));
+        assertEquals(method, jwk.get("alg"));
+        assertEquals(header.get("kid"), jwk.get("kid"));
+    }
+
+    @Test
+    void secretSignedTokensStillVerifyAfterSwitchingToAKey() throws Exception {
+        String token = new JwtService(secretConfig()).issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        JwtService service = new JwtService(asymmetricConfig("RS256", keyPair("RSA")));
+
+        assertEquals("user-1", service.parseToken(token).get("user_id", String.class));
+    }
+
+    @Test
+    void tokenFromAnotherKeyIsRejected() throws Exception {
+        String token = new JwtService(asymmetricConfig("RS256", keyPair("RSA")))
+                .issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        JwtService other = new JwtService(asymmetricConfig("RS256", keyPair("RSA")));
+
+        assertThrows(JwtException.class, () -> other.parseToken(token));
+        assertThrows(JwtException.class, () -> new JwtService(secretConfig()).parseToken(token));
+    }
+
+    @Test
+    void tokenForAnotherIssuerOrAudienceIsRejected() throws Exception {
+        AppConfig staging = secretConfig();
+        staging.setJwtIssuer("kubesec-bank-staging");
+        String stagingToken = new JwtService(staging).issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        AppConfig admin = secretConfig();
+        admin.setJwtAudience("admin");
+        String adminToken = new JwtService(admin).issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        JwtService service = new JwtService(secretConfig());
+
+        assertThrows(JwtException.class, () -> service.parseToken(stagingToken));
+        assertThrows(JwtException.class, () -> service.parseToken(adminToken));
+        Claims claims = service.parseToken(service.issueTokens("user-1", "alice@example.com", TENANT).accessToken());
+        assertEquals("kubesec-bank", claims.getIssuer());
+        assertEquals(Set.of("api"), claims.getAudience());
+    }
+
+    @Test
+    void fallsBackToTheSecretWithoutKeyPaths() throws Exception {
+        AppConfig config = secretConfig();
+        config.setJwtSigningMethod("RS256");
+        JwtService service = new JwtService(config);
+
+        String token = service.issueTokens("user-1", "alice@example.com", TENANT).accessToken();
+
+        assertEquals("HS256", header(token).get("alg"));
+        assertEquals(List.of(), service.jwks().get("keys"));
+    }
+
+    @Test
+    void privateKeyWithoutPublicKeyIsRejectedAtStartup() throws Exception {
+        AppConfig config = asymmetricConfig("ES256", keyPair("EC"));
+        config.setJwtPublicKeyPath("");
+
+        assertThrows(IllegalStateException.class, () -> new JwtService(config));
+    }
+
+    @SuppressWarnings("unchecked")
+    private static Map<String, Object> header(String token) throws Exception {
+        byte[] json = Base64.getUrlDecoder().decode(token.substring(0, token.indexOf('.')));
+        return new ObjectMapper().readValue(json, Map.class);
+    }
+
+    private AppConfig secretConfig() {
+        AppConfig config = new AppConfig();
+        config.setJwtSecret(SECRET);
+        return config;
+    }
+
+    private AppConfig asymmetricConfig(String method, KeyPair keys) throws Exception {
+        String name = UUID.randomUUID().toString();
+        Path privateKey = dir.resolve(name + ".key");
+        Path publicKey = dir.resolve(name + ".pub");
+        Files.writeString(privateKey, pem("PRIVATE KEY", keys.getPrivate().getEncoded()));
+        Files.writeString(publicKey, pem("PUBLIC KEY", keys.getPublic().getEncoded()));
+
+        AppConfig config = secretConfig();
+        config.setJwtSigningMethod(method);
+        config.setJwtPrivateKeyPath(privateKey.toString());
+        config.setJwtPublicKeyPath(publicKey.toString());
+        return config;
+    }
+
+    private static String pem(String label, byte[] der) {
+        return "-----BEGIN " + label + "-----\n" + Base64.getMimeEncoder().encodeToString(der)
+                + "\n-----END " + label + "-----\n";
+    }
+
+    private static KeyPair keyPair(String algorithm) throws Exception {
+        KeyPairGenerator generator = KeyPairGenerator.getInstance(algorithm);
+        if ("EC".equals(algorithm)) {
+            generator.initialize(new ECGenParameterSpec("secp256r1"));
+        } else {
+            generator.initialize(2048);
+        }
+        return generator.generateKeyPair();
+    }
+}
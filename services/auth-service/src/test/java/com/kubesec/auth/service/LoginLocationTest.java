@@ -0,0 +1,114 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.config.AppConfig;
+import com.kubesec.auth.exception.UnauthorizedException;
+import com.kubesec.auth.geoip.GeoIpLookup;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.model.dto.SuspiciousLoginEvent;
+import com.kubesec.auth.tenant.TenantContext;
+import com.kubesec.auth.testdoubles.InMemoryAuthRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+
+import java.nio.file.Path;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+
+// Logs in from addresses in test-city.mmdb and checks which logins are reported as
+// suspicious.
+class LoginLocationTest {
+
+    private static final String EMAIL = "alice@example.com";
+    private static final String PASSWORD = "secret";
+    private static final String LONDON = "81.2.69.142";
+    private static final String ELSEWHERE_IN_GB = "2.125.160.216";
+    private static final String STOCKHOLM = "89.160.20.128";
+
+    private final UUID tenantId = UUID.randomUUID();
+    private SuspiciousLoginPublisher publisher;
+    private AuthService authService;
+
+    @BeforeEach
+    void setUp() throws Exception {
+        AppConfig config = new AppConfig();
+        config.setJwtSecret("test-secret-that-is-long-enough-for-hs256");
+        config.setGeoipDatabasePath(Path.of(getClass().getResource("/geoip/test-city.mmdb").toURI()).toString());
+        InMemoryAuthRepository repository = new InMemoryAuthRepository();
+        publisher = mock(SuspiciousLoginPublisher.class);
+        authService = AuthService.builder()
+                .withRepository(repository)
+                .withJwtService(new JwtService(config))
+                .withGeoIpLookup(new GeoIpLookup(config))
+                .withSuspiciousLoginPublisher(publisher)
+                .build();
+
+        TenantContext.set(tenantId);
+        repository.createUserCredentials(new UserCredentials("user-1", tenantId, EMAIL,
+                PasswordHasher.hash(PASSWORD), true, OffsetDateTime.now(ZoneOffset.UTC)));
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void loginFromANewCountryIsReported() {
+        login(LONDON);
+
+        login(STOCKHOLM);
+
+        ArgumentCaptor<SuspiciousLoginEvent> event = ArgumentCaptor.forClass(SuspiciousLoginEvent.class);
+        verify(publisher).publish(event.capture());
+        assertEquals("user-1", event.getValue().userId());
+        assertEquals(tenantId, event.getValue().tenantId());
+        assertEquals(EMAIL, event.getValue().email());
+        assertEquals(STOCKHOLM, event.getValue().ipAddress());
+        assertEquals("SE", event.getValue().ipCountry());
+        assertEquals("Stockholm", event.getValue().ipCity());
+        assertEquals(List.of("GB"), event.getValue().recentCountries());
+    }
+
+    @Test
+    void loginFromAKnownCountryIsNotReported() {
+        login(LONDON);
+
+        login(ELSEWHERE_IN_GB);
+
+        verify(publisher, never()).publish(any());
+    }
+
+    @Test
+    void firstLocatedLoginHasNothingToCompareAgainst() {
+        login("1.1.1.1");
+
+        login(STOCKHOLM);
+
+        verify(publisher, never()).publish(any());
+    }
+
+    @Test
+    void failedLoginFromANewCountryIsNotReported() {
+        login(LONDON);
+
+        assertThrows(UnauthorizedException.class,
+                () -> authService.login(EMAIL, "wrong", null, STOCKHOLM, "test-agent"));
+
+        verify(publisher, never()).publish(any());
+    }
+
+    private void login(String ipAddress) {
+        authService.login(EMAIL, PASSWORD, null, ipAddress, "test-agent");
+    }
+}
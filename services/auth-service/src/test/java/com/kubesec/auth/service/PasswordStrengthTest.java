@@ -0,0 +1,81 @@
+package com.kubesec.auth.service;
+
+import com.kubesec.auth.exception.WeakPasswordException;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.Arguments;
+import org.junit.jupiter.params.provider.MethodSource;
+
+import java.util.List;
+import java.util.stream.IntStream;
+import java.util.stream.Stream;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class PasswordStrengthTest {
+
+    // Ordered roughly from weakest to strongest; together they cover the 0-4 scale.
+    private static final List<String> SAMPLES = List.of(
+            "password",
+            "Password123!",
+            "Summer2024!!",
+            "Tr0ub4dour&3",
+            "horse-Staple-97",
+            "Quartz-Lantern-Velvet-42",
+            "v7#Qm!2zR^pL9&xW");
+
+    static Stream<Arguments> everyThresholdAgainstEverySample() {
+        return IntStream.rangeClosed(0, 4).boxed()
+                .flatMap(min -> SAMPLES.stream().map(password -> Arguments.of(min, password)));
+    }
+
+    // A password is accepted exactly when its score reaches the threshold, so each
+    // threshold rejects the samples one score below it and accepts those at it.
+    @ParameterizedTest(name = "min {0}: {1}")
+    @MethodSource("everyThresholdAgainstEverySample")
+    void acceptsExactlyThePasswordsAtOrAboveTheThreshold(int min, String password) {
+        int score = PasswordStrength.score(password).getScore();
+        PasswordStrength strength = new PasswordStrength(min);
+
+        if (score >= min) {
+            assertDoesNotThrow(() -> strength.check(password));
+        } else {
+            WeakPasswordException e = assertThrows(WeakPasswordException.class, () -> strength.check(password));
+            assertEquals(score, e.getScore());
+        }
+    }
+
+    @Test
+    void samplesReachBothEndsOfTheScale() {
+        assertEquals(0, PasswordStrength.score("password").getScore());
+        assertEquals(4, PasswordStrength.score("v7#Qm!2zR^pL9&xW").getScore());
+        // The password the registration flow tests use.
+        assertEquals(4, PasswordStrength.score("Quartz-Lantern-Velvet-42").getScore());
+    }
+
+    @Test
+    void composedButPredictablePasswordFailsTheDefault() {
+        // Meets every PasswordPolicy rule.
+        assertTrue(PasswordPolicy.violations("Password123!").isEmpty());
+
+        WeakPasswordException e = assertThrows(WeakPasswordException.class,
+                () -> new PasswordStrength(3).check("Password123!"));
+        assertTrue(e.getScore() < 3);
+        assertFalse(e.getSuggestions().isEmpty());
+    }
+
+    @Test
+    void reusingAccountDetailsLowersTheScore() {
+        String password = "AlexandriaWhitfield";
+
+        int alone = PasswordStrength.score(password).getScore();
+        int withName = PasswordStrength.score(password, "alexandria.whitfield@example.com",
+                "Alexandria Whitfield").getScore();
+
+        assertTrue(withName < alone, alone + " -> " + withName);
+    }
+}
@@ -0,0 +1,43 @@
+package com.kubesec.auth.service;
+
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+
+import java.nio.charset.StandardCharsets;
+import java.time.Instant;
+import java.util.OptionalLong;
+
+import static org.junit.jupiter.api.Assertions.assertArrayEquals;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class TotpTest {
+
+    // The SHA1 seed from RFC 6238 appendix B, truncated to six digits.
+    private static final String RFC_SECRET = Totp.encodeBase32("12345678901234567890".getBytes(StandardCharsets.US_ASCII));
+
+    @ParameterizedTest
+    @CsvSource({"59, 287082", "1111111109, 081804", "1234567890, 005924", "2000000000, 279037"})
+    void matchesTheRfcTestVectors(long epochSecond, String code) {
+        assertEquals(code, Totp.generate(RFC_SECRET, Instant.ofEpochSecond(epochSecond)));
+    }
+
+    @Test
+    void acceptsOneStepOfDriftAndReportsTheStep() {
+        Instant now = Instant.ofEpochSecond(1111111109);
+        String previous = Totp.generate(RFC_SECRET, now.minusSeconds(30));
+
+        assertEquals(OptionalLong.of(1111111109 / 30 - 1), Totp.verify(RFC_SECRET, previous, now));
+        assertTrue(Totp.verify(RFC_SECRET, previous, now.plusSeconds(60)).isEmpty());
+        assertTrue(Totp.verify(RFC_SECRET, "12345", now).isEmpty());
+    }
+
+    @Test
+    void base32RoundTrips() {
+        byte[] data = "any carnal pleas".getBytes(StandardCharsets.US_ASCII);
+
+        assertArrayEquals(data, Totp.decodeBase32(Totp.encodeBase32(data)));
+        assertEquals(32, Totp.generateSecret().length());
+    }
+}
@@ -0,0 +1,447 @@
+package com.kubesec.auth.testdoubles;
+
+import com.kubesec.auth.model.AuthCode;
+import com.kubesec.auth.model.EmailVerificationToken;
+import com.kubesec.auth.model.LoginAttempt;
+import com.kubesec.auth.model.LoginAttemptAdminFilter;
+import com.kubesec.auth.model.MfaSettings;
+import com.kubesec.auth.model.Session;
+import com.kubesec.auth.model.TokenInfo;
+import com.kubesec.auth.model.TrustedDevice;
+import com.kubesec.auth.model.UserCredentials;
+import com.kubesec.auth.repository.AuthRepository;
+import com.kubesec.auth.tenant.TenantContext;
+
+import java.time.Duration;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Collection;
+import java.util.Comparator;
+import java.util.LinkedHashSet;
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+import java.util.concurrent.Executors;
+import java.util.concurrent.ScheduledExecutorService;
+import java.util.concurrent.TimeUnit;
+import java.util.function.Consumer;
+import java.util.stream.Stream;
+
+// In-memory AuthRepository for tests that exercise handlers without PostgreSQL or
+// Redis. Queries are tenant-scoped the same way AuthRepositoryImpl scopes them, and
+// Redis TTLs are simulated by removing keys once their expiry elapses.
+public class InMemoryAuthRepository implements AuthRepository {
+
+    private final Map<String, Session> sessions = new ConcurrentHashMap<>();
+    private final Map<String, LoginAttempt> loginAttempts = new ConcurrentHashMap<>();
+    private final Map<String, AuthCode> authCodes = new ConcurrentHashMap<>();
+    private final Map<UUID, TrustedDevice> trustedDevices = new ConcurrentHashMap<>();
+    private final Map<String, UserCredentials> userCredentials = new ConcurrentHashMap<>();
+    private final Map<String, String> userRoles = new ConcurrentHashMap<>();
+    private final Map<String, MfaRow> mfa = new ConcurrentHashMap<>();
+    private final Map<String, EmailVerificationToken> verificationTokens = new ConcurrentHashMap<>();
+    private final Map<String, Object> blacklist = new ConcurrentHashMap<>();
+    private final Map<String, String> sessionCache = new ConcurrentHashMap<>();
+
+    private final ScheduledExecutorService expirer = Executors.newSingleThreadScheduledExecutor(r -> {
+        Thread thread = new Thread(r, "in-memory-auth-expiry");
+        thread.setDaemon(true);
+        return thread;
+    });
+
+    // --- Sessions ---
+
+    @Override
+    public void createSession(Session session) {
+        sessions.put(session.getToken(), session);
+    }
+
+    // Mirrors the (tenant_id, user_id, device_fingerprint) unique constraint: the
+    // existing row keeps its id and creation time and takes the new token.
+    @Override
+    public synchronized void upsertSession(Session session) {
+        if (session.getDeviceFingerprint() != null) {
+            Optional<Session> existing = sessions.values().stream()
+                    .filter(s -> session.getTenantId().equals(s.getTenantId())
+                            && session.getUserId().equals(s.getUserId())
+                            && session.getDeviceFingerprint().equals(s.getDeviceFingerprint()))
+                    .findFirst();
+            if (existing.isPresent()) {
+                Session row = existing.get();
+                sessions.remove(row.getToken());
+                row.setToken(session.getToken());
+                row.setExpiresAt(session.getExpiresAt());
+                row.setUpdatedAt(OffsetDateTime.now(ZoneOffset.UTC));
+                sessions.put(row.getToken(), row);
+                return;
+            }
+        }
+        sessions.put(session.getToken(), session);
+    }
+
+    @Override
+    public List<TokenInfo> listActiveTokens(String userId) {
+        UUID tenantId = TenantContext.require();
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        return sessions.values().stream()
+                .filter(s -> tenantId.equals(s.getTenantId()) && userId.equals(s.getUserId())
+                        && s.getExpiresAt().isAfter(now))
+                .sorted(Comparator.comparing(Session::getCreatedAt).reversed())
+                .map(s -> {
+                    Optional<LoginAttempt> login = openingLogin(s);
+                    return new TokenInfo(s.getId(), s.getCreatedAt(), s.getExpiresAt(),
+                            login.map(LoginAttempt::ipAddress).orElse(null),
+                            login.map(LoginAttempt::userAgent).orElse(null));
+                })
+                .toList();
+    }
+
+    // The successful login nearest the session's latest token, within a second of it.
+    private Optional<LoginAttempt> openingLogin(Session session) {
+        OffsetDateTime issued = session.getUpdatedAt() != null ? session.getUpdatedAt() : session.getCreatedAt();
+        return loginAttempts.values().stream()
+                .filter(a -> session.getTenantId().equals(a.tenantId()) && session.getUserId().equals(a.userId())
+                        && a.success())
+                .filter(a -> Duration.between(a.createdAt(), issued).abs().compareTo(Duration.ofSeconds(1)) <= 0)
+                .min(Comparator.comparing(a -> Duration.between(a.createdAt(), issued).abs()));
+    }
+
+    @Override
+    public void deleteSession(String token) {
+        UUID tenantId = TenantContext.require();
+        sessions.computeIfPresent(token, (k, s) -> tenantId.equals(s.getTenantId()) ? null : s);
+    }
+
+    @Override
+    public int deleteSessionsByUserId(String userId) {
+        List<String> tokens = getSessionTokensByUserId(userId);
+        tokens.forEach(sessions::remove);
+        return tokens.size();
+    }
+
+    @Override
+    public List<String> getSessionTokensByUserId(String userId) {
+        UUID tenantId = TenantContext.require();
+        return sessions.values().stream()
+                .filter(s -> tenantId.equals(s.getTenantId()) && userId.equals(s.getUserId()))
+                .map(Session::getToken)
+                .toList();
+    }
+
+    public Optional<Session> findSession(String token) {
+        return Optional.ofNullable(sessions.get(token));
+    }
+
+    public List<Session> sessionsForUser(String userId) {
+        return sessions.values().stream().filter(s -> userId.equals(s.getUserId())).toList();
+    }
+
+    // --- Login attempts ---
+
+    public List<LoginAttempt> loginAttempts() {
+        return List.copyOf(loginAttempts.values());
+    }
+
+    @Override
+    public void recordLoginAttempt(LoginAttempt attempt) {
+        loginAttempts.put(attempt.id(), attempt);
+    }
+
+    @Override
+    public int getRecentFailedAttempts(String email, OffsetDateTime since) {
+        return (int) tenantAttempts()
+                .filter(a -> a.email().equals(email) && !a.success() && a.createdAt().isAfter(since))
+                .count();
+    }
+
+    @Override
+    public List<String> getRecentLoginCountries(String email, int limit) {
+        return List.copyOf(tenantAttempts()
+                .filter(a -> a.email().equals(email) && a.success() && a.ipCountry() != null)
+                .sorted(Comparator.comparing(LoginAttempt::createdAt).reversed())
+                .limit(limit)
+                .map(LoginAttempt::ipCountry)
+                .collect(LinkedHashSet::new, LinkedHashSet::add, LinkedHashSet::addAll));
+    }
+
+    @Override
+    public List<LoginAttempt> listLoginAttempts(LoginAttemptAdminFilter filter) {
+        Stream<LoginAttempt> attempts = tenantAttempts().filter(a -> matches(a, filter));
+        if (filter.getCursor() != null) {
+            attempts = attempts.filter(a -> a.createdAt().isBefore(filter.getCursor().createdAt())
+                    || (a.createdAt().isEqual(filter.getCursor().createdAt()) && a.id().compareTo(filter.getCursor().id()) < 0));
+        }
+        attempts = attempts.sorted(Comparator.comparing(LoginAttempt::createdAt)
+                .thenComparing(LoginAttempt::id).reversed());
+        if (filter.getCursor() == null && filter.getOffset() > 0) {
+            attempts = attempts.skip(filter.getOffset());
+        }
+        if (filter.getLimit() > 0) {
+            attempts = attempts.limit(filter.getLimit());
+        }
+        return attempts.toList();
+    }
+
+    @Override
+    public int countLoginAttempts(LoginAttemptAdminFilter filter) {
+        return (int) tenantAttempts().filter(a -> matches(a, filter)).count();
+    }
+
+    @Override
+    public void streamLoginAttempts(LoginAttemptAdminFilter filter, Consumer<LoginAttempt> consumer) {
+        tenantAttempts()
+                .filter(a -> matches(a, filter))
+                .sorted(Comparator.comparing(LoginAttempt::createdAt).thenComparing(LoginAttempt::id))
+                .forEach(consumer);
+    }
+
+    @Override
+    public void streamLoginAttemptsBefore(OffsetDateTime cutoff, Consumer<LoginAttempt> consumer) {
+        loginAttempts.values().stream()
+                .filter(a -> a.createdAt().isBefore(cutoff))
+                .sorted(Comparator.comparing(LoginAttempt::createdAt))
+                .forEach(consumer);
+    }
+
+    @Override
+    public int deleteLoginAttemptsBefore(OffsetDateTime cutoff, int batchSize) {
+        List<String> ids = loginAttempts.values().stream()
+                .filter(a -> a.createdAt().isBefore(cutoff))
+                .limit(batchSize)
+                .map(LoginAttempt::id)
+                .toList();
+        ids.forEach(loginAttempts::remove);
+        return ids.size();
+    }
+
+    private Stream<LoginAttempt> tenantAttempts() {
+        UUID tenantId = TenantContext.require();
+        return loginAttempts.values().stream().filter(a -> tenantId.equals(a.tenantId()));
+    }
+
+    private static boolean matches(LoginAttempt attempt, LoginAttemptAdminFilter filter) {
+        if (filter.getIpAddress() != null && !filter.getIpAddress().isEmpty() && !filter.getIpAddress().equals(attempt.ipAddress())) {
+            return false;
+        }
+        if (filter.getEmail() != null && !filter.getEmail().isEmpty() && !filter.getEmail().equals(attempt.email())) {
+            return false;
+        }
+        if (filter.getSuccess() != null && filter.getSuccess() != attempt.success()) {
+            return false;
+        }
+        if (filter.getFrom() != null && attempt.createdAt().isBefore(filter.getFrom())) {
+            return false;
+        }
+        return filter.getTo() == null || attempt.createdAt().isBefore(filter.getTo());
+    }
+
+    // --- PKCE authorization codes ---
+
+    @Override
+    public void createAuthCode(AuthCode authCode) {
+        authCodes.put(authCode.code(), authCode);
+    }
+
+    @Override
+    public Optional<AuthCode> consumeAuthCode(String code) {
+        UUID tenantId = TenantContext.require();
+        AuthCode[] consumed = new AuthCode[1];
+        authCodes.computeIfPresent(code, (k, c) -> {
+            if (!tenantId.equals(c.tenantId())) {
+                return c;
+            }
+            consumed[0] = c;
+            return null;
+        });
+        return Optional.ofNullable(consumed[0]);
+    }
+
+    // Moves a code's expiry into the past instead of waiting out AUTH_CODE_EXPIRY.
+    public void expireAuthCode(String code) {
+        authCodes.computeIfPresent(code, (k, c) -> new AuthCode(c.code(), c.tenantId(), c.userId(), c.email(),
+                c.codeChallenge(), OffsetDateTime.now(ZoneOffset.UTC).minusSeconds(1)));
+    }
+
+    // --- TOTP second factor ---
+
+    private record MfaRow(String secret, boolean enabled, Long lastUsedStep) {}
+
+    @Override
+    public Optional<MfaSettings> getTotpSecret(String userId) {
+        return Optional.ofNullable(mfa.get(mfaKey(userId))).map(row -> new MfaSettings(row.secret(), row.enabled()));
+    }
+
+    @Override
+    public void setTotpSecret(String userId, String secret) {
+        mfa.compute(mfaKey(userId), (k, row) -> row != null && row.enabled() ? row : new MfaRow(secret, false, null));
+    }
+
+    @Override
+    public boolean enableMfa(String userId) {
+        return mfa.computeIfPresent(mfaKey(userId), (k, row) -> new MfaRow(row.secret(), true, row.lastUsedStep())) != null;
+    }
+
+    @Override
+    public boolean disableMfa(String userId) {
+        return mfa.remove(mfaKey(userId)) != null;
+    }
+
+    @Override
+    public boolean useTotpStep(String userId, long step) {
+        boolean[] used = new boolean[1];
+        mfa.computeIfPresent(mfaKey(userId), (k, row) -> {
+            if (row.lastUsedStep() != null && row.lastUsedStep() >= step) {
+                return row;
+            }
+            used[0] = true;
+            return new MfaRow(row.secret(), row.enabled(), step);
+        });
+        return used[0];
+    }
+
+    private static String mfaKey(String userId) {
+        return TenantContext.require() + ":" + userId;
+    }
+
+    // --- Trusted devices ---
+
+    @Override
+    public TrustedDevice registerTrustedDevice(TrustedDevice device) {
+        synchronized (trustedDevices) {
+            Optional<TrustedDevice> existing = trustedDevices.values().stream()
+                    .filter(d -> d.tenantId().equals(device.tenantId()) && d.userId().equals(device.userId())
+                            && d.deviceFingerprint().equals(device.deviceFingerprint()))
+                    .findFirst();
+            TrustedDevice stored = existing
+                    .map(d -> new TrustedDevice(d.id(), d.tenantId(), d.userId(), d.deviceFingerprint(),
+                            device.deviceName(), device.trustedAt(), device.expiresAt()))
+                    .orElse(device);
+            trustedDevices.put(stored.id(), stored);
+            return stored;
+        }
+    }
+
+    @Override
+    public boolean isTrustedDevice(String userId, String fingerprint) {
+        return listTrustedDevices(userId).stream().anyMatch(d -> d.deviceFingerprint().equals(fingerprint));
+    }
+
+    @Override
+    public List<TrustedDevice> listTrustedDevices(String userId) {
+        UUID tenantId = TenantContext.require();
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        return trustedDevices.values().stream()
+                .filter(d -> tenantId.equals(d.tenantId()) && d.userId().equals(userId) && d.expiresAt().isAfter(now))
+                .sorted(Comparator.comparing(TrustedDevice::trustedAt).reversed())
+                .toList();
+    }
+
+    @Override
+    public boolean deleteTrustedDevice(UUID id, String userId) {
+        UUID tenantId = TenantContext.require();
+        boolean[] deleted = new boolean[1];
+        trustedDevices.computeIfPresent(id, (k, d) -> {
+            if (tenantId.equals(d.tenantId()) && d.userId().equals(userId)) {
+                deleted[0] = true;
+                return null;
+            }
+            return d;
+        });
+        return deleted[0];
+    }
+
+    // --- Self-registration ---
+
+    // Mirrors the (tenant_id, email) unique constraint.
+    @Override
+    public void createUserCredentials(UserCredentials credentials) {
+        synchronized (userCredentials) {
+            boolean taken = userCredentials.values().stream()
+                    .anyMatch(c -> c.tenantId().equals(credentials.tenantId()) && c.email().equals(credentials.email()));
+            if (taken) {
+                throw new IllegalStateException("duplicate email " + credentials.email());
+            }
+            userCredentials.put(credentials.userId(), credentials);
+        }
+    }
+
+    @Override
+    public Optional<UserCredentials> getUserCredentialsByEmail(String email) {
+        UUID tenantId = TenantContext.require();
+        return userCredentials.values().stream()
+                .filter(c -> tenantId.equals(c.tenantId()) && c.email().equals(email))
+                .findFirst();
+    }
+
+    @Override
+    public Optional<String> getUserRole(String userId) {
+        UserCredentials credentials = userCredentials.get(userId);
+        if (credentials == null || !credentials.tenantId().equals(TenantContext.require())) {
+            return Optional.empty();
+        }
+        return Optional.of(userRoles.getOrDefault(userId, "customer"));
+    }
+
+    // Stands in for an operator granting a staff role.
+    public void setUserRole(String userId, String role) {
+        userRoles.put(userId, role);
+    }
+
+    @Override
+    public void createEmailVerificationToken(EmailVerificationToken token) {
+        verificationTokens.put(token.tokenHash(), token);
+    }
+
+    public List<EmailVerificationToken> verificationTokens() {
+        return List.copyOf(verificationTokens.values());
+    }
+
+    // --- Token blacklist ---
+
+    @Override
+    public void blacklistToken(String token, Duration expiry) {
+        // A re-blacklisted token keeps the newer expiry; only its own entry is removed.
+        Object entry = new Object();
+        blacklist.put(token, entry);
+        expireLater(() -> blacklist.remove(token, entry), expiry);
+    }
+
+    @Override
+    public boolean isTokenBlacklisted(String token) {
+        return blacklist.containsKey(token);
+    }
+
+    // --- Session cache ---
+
+    @Override
+    public void cacheSession(String token, String userId, Duration expiry) {
+        sessionCache.put(token, userId);
+        expireLater(() -> sessionCache.remove(token, userId), expiry);
+    }
+
+    @Override
+    public void invalidateCachedSession(String token) {
+        sessionCache.remove(token);
+    }
+
+    @Override
+    public int invalidateCachedSessions(Collection<String> tokens) {
+        int removed = 0;
+        for (String token : tokens) {
+            if (sessionCache.remove(token) != null) {
+                removed++;
+            }
+        }
+        return removed;
+    }
+
+    public Optional<String> cachedSession(String token) {
+        return Optional.ofNullable(sessionCache.get(token));
+    }
+
+    private void expireLater(Runnable removal, Duration expiry) {
+        expirer.schedule(removal, expiry.toMillis(), TimeUnit.MILLISECONDS);
+    }
+}
@@ -0,0 +1,105 @@
+package com.kubesec.auth.validation;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.node.TextNode;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.core.io.Resource;
+import org.springframework.core.io.support.PathMatchingResourcePatternResolver;
+
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class SchemaValidatorTest {
+
+    // RFC 7636 appendix B
+    private static final String CHALLENGE = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM";
+    private static final String VERIFIER = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk";
+    private static final String FINGERPRINT = "3f9a6c1e2b7d4a08";
+
+    private final ObjectMapper objectMapper = new ObjectMapper();
+    private final SchemaValidator validator = new SchemaValidator();
+
+    @Test
+    void everySchemaLoadsAndRejectsNonObjectBodies() throws Exception {
+        Resource[] schemas = new PathMatchingResourcePatternResolver().getResources("classpath:/schemas/*.json");
+        assertTrue(schemas.length > 0);
+
+        for (Resource schema : schemas) {
+            String name = schema.getFilename().replace(".json", "");
+            assertFalse(validator.validate(name, TextNode.valueOf("body")).isEmpty(), name);
+        }
+    }
+
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "authorize | {\"code_challenge\":\"" + CHALLENGE + "\",\"code_challenge_method\":\"S256\"}",
+            "login | {\"email\":\"ada@example.com\",\"password\":\"x\"}",
+            "login | {\"email\":\"ada@example.com\",\"password\":\"hunter2\","
+                    + "\"device_fingerprint\":\"" + FINGERPRINT + "\"}",
+            "mfa-confirm | {\"totp_code\":\"012345\"}",
+            "mfa-verify | {\"mfa_token\":\"mfa-1\",\"totp_code\":\"987654\"}",
+            "refresh | {\"refresh_token\":\"rt-1\"}",
+            "register-device | {\"device_fingerprint\":\"" + FINGERPRINT + "\"}",
+            "register-device | {\"device_fingerprint\":\"" + FINGERPRINT + "\",\"device_name\":\"Work laptop\"}",
+            "register | {\"email\":\"ada@example.com\",\"password\":\"s3cret-pass\",\"full_name\":\"Ada Lovelace\"}",
+            "service-token | {\"audience\":\"account-service\"}",
+            "token | {\"code\":\"auth-code\",\"code_verifier\":\"" + VERIFIER + "\"}",
+            "token | {\"code\":\"auth-code\",\"code_verifier\":\"" + VERIFIER + VERIFIER + "~.\"}",
+            "validate | {\"token\":\"eyJhbGciOiJIUzI1NiJ9.e30.sig\"}"
+    })
+    void validBodiesPass(String schema, String body) throws Exception {
+        assertEquals(List.of(), validator.validate(schema, objectMapper.readTree(body)));
+    }
+
+    // field is the property each rejection must name
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "authorize | {\"code_challenge\":\"" + CHALLENGE + "\",\"code_challenge_method\":\"plain\"}"
+                    + " | code_challenge_method",
+            "authorize | {\"code_challenge\":\"" + CHALLENGE + "\"} | code_challenge_method",
+            "authorize | {\"code_challenge\":\"abc\",\"code_challenge_method\":\"S256\"} | code_challenge",
+            "authorize | {\"code_challenge\":\"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw+cM\","
+                    + "\"code_challenge_method\":\"S256\"} | code_challenge",
+            "login | {\"email\":\"ada@example.com\"} | password",
+            "login | {\"email\":\"ada@example.com\",\"password\":\"\"} | password",
+            "login | {\"email\":\"ada\",\"password\":\"hunter2\"} | email",
+            "login | {\"email\":\"ada@example.com\",\"password\":\"hunter2\",\"device_fingerprint\":\"short\"}"
+                    + " | device_fingerprint",
+            "mfa-confirm | {\"totp_code\":\"12345\"} | totp_code",
+            "mfa-confirm | {\"totp_code\":123456} | totp_code",
+            "mfa-verify | {\"totp_code\":\"123456\"} | mfa_token",
+            "mfa-verify | {\"mfa_token\":\"mfa-1\",\"totp_code\":\"12345a\"} | totp_code",
+            "refresh | {\"refresh_token\":\"\"} | refresh_token",
+            "register-device | {\"device_name\":\"Work laptop\"} | device_fingerprint",
+            "register-device | {\"device_fingerprint\":\"abc\"} | device_fingerprint",
+            "register | {\"email\":\"ada@example.com\",\"password\":\"s3cret-pass\"} | full_name",
+            "register | {\"email\":\"ada@example.com\",\"password\":\"s3cret-pass\",\"full_name\":\"\"} | full_name",
+            "service-token | {\"audience\":\"billing-service\"} | audience",
+            "token | {\"code\":\"auth-code\"} | code_verifier",
+            "token | {\"code\":\"auth-code\",\"code_verifier\":\"too-short\"} | code_verifier",
+            "token | {\"code\":\"auth-code\",\"code_verifier\":\"" + VERIFIER + "+\"} | code_verifier",
+            "token | {\"code\":\"\",\"code_verifier\":\"" + VERIFIER + "\"} | code",
+            "validate | {} | token"
+    })
+    void invalidBodiesNameTheOffendingField(String schema, String body, String field) throws Exception {
+        List<String> violations = validator.validate(schema, objectMapper.readTree(body));
+
+        assertEquals(1, violations.size(), violations.toString());
+        assertTrue(violations.get(0).contains(field), violations.get(0));
+    }
+
+    @Test
+    void everyViolationIsReported() throws Exception {
+        List<String> violations = validator.validate("register", objectMapper.readTree("{\"email\":\"ada\"}"));
+
+        assertEquals(3, violations.size(), violations.toString());
+        assertTrue(violations.stream().anyMatch(v -> v.contains("password")), violations.toString());
+        assertTrue(violations.stream().anyMatch(v -> v.contains("full_name")), violations.toString());
+        assertTrue(violations.get(2).contains("email"), violations.get(2));
+    }
+}
@@ -0,0 +1,100 @@
+#!/usr/bin/env python3
+# Regenerates test-city.mmdb: a GeoLite2-City shaped MaxMind DB holding a few IPv4
+# networks, so GeoIpLookup can be tested without the licensed database. Writes the
+# MaxMind DB format (https://maxmind.github.io/MaxMind-DB/) directly; stdlib only.
+import ipaddress
+import os
+import struct
+
+NETWORKS = {
+    "81.2.69.0/24": {"country": "GB", "city": "London"},
+    "89.160.20.0/24": {"country": "SE", "city": "Stockholm"},
+    "216.160.83.0/24": {"country": "US", "city": "Milton"},
+    # Located, but only to a country
+    "2.125.160.0/24": {"country": "GB", "city": None},
+}
+
+
+def control(type_, size):
+    if size < 29:
+        head, ext = size, b""
+    elif size < 285:
+        head, ext = 29, bytes([size - 29])
+    else:
+        head, ext = 30, struct.pack(">H", size - 285)
+    if type_ <= 7:
+        return bytes([(type_ << 5) | head]) + ext
+    return bytes([head, type_ - 7]) + ext
+
+
+def uint(type_, value):
+    raw = value.to_bytes((value.bit_length() + 7) // 8, "big")
+    return control(type_, len(raw)) + raw
+
+
+def encode(value):
+    if isinstance(value, str):
+        raw = value.encode("utf-8")
+        return control(2, len(raw)) + raw
+    if isinstance(value, dict):
+        return control(7, len(value)) + b"".join(encode(k) + encode(v) for k, v in value.items())
+    if isinstance(value, list):
+        return control(11, len(value)) + b"".join(encode(v) for v in value)
+    raise TypeError(value)
+
+
+def record(location):
+    names = {"GB": "United Kingdom", "SE": "Sweden", "US": "United States"}
+    data = {"country": {"iso_code": location["country"], "names": {"en": names[location["country"]]}}}
+    if location["city"]:
+        data["city"] = {"names": {"en": location["city"]}}
+    return data
+
+
+def build():
+    # Each node is [left, right]: None (empty), ("node", index) or ("data", offset).
+    nodes = [[None, None]]
+    data = b""
+    for cidr, location in NETWORKS.items():
+        network = ipaddress.ip_network(cidr)
+        # The tree is IPv6; IPv4 networks sit under ::/96, where readers look them up
+        bits = "0" * 96 + format(int(network.network_address), "032b")
+        prefix = 96 + network.prefixlen
+        offset = len(data)
+        data += encode(record(location))
+        node = 0
+        for depth in range(prefix - 1):
+            bit = int(bits[depth])
+            if nodes[node][bit] is None:
+                nodes.append([None, None])
+                nodes[node][bit] = ("node", len(nodes) - 1)
+            node = nodes[node][bit][1]
+        nodes[node][int(bits[prefix - 1])] = ("data", offset)
+
+    count = len(nodes)
+
+    def value(rec):
+        if rec is None:
+            return count
+        if rec[0] == "node":
+            return rec[1]
+        return count + 16 + rec[1]
+
+    tree = b"".join(value(l).to_bytes(3, "big") + value(r).to_bytes(3, "big") for l, r in nodes)
+    metadata = (control(7, 9)
+                + encode("binary_format_major_version") + uint(5, 2)
+                + encode("binary_format_minor_version") + uint(5, 0)
+                + encode("build_epoch") + uint(9, 1767225600)
+                + encode("database_type") + encode("GeoLite2-City")
+                + encode("description") + encode({"en": "kubesec test city database"})
+                + encode("ip_version") + uint(5, 6)
+                + encode("languages") + encode(["en"])
+                + encode("node_count") + uint(6, count)
+                + encode("record_size") + uint(5, 24))
+    return tree + b"\x00" * 16 + data + b"\xab\xcd\xefMaxMind.com" + metadata
+
+
+if __name__ == "__main__":
+    path = os.path.join(os.path.dirname(os.path.abspath(__file__)), "test-city.mmdb")
+    with open(path, "wb") as f:
+        f.write(build())
@@ -0,0 +1,27 @@
+#!/bin/sh
+# Regenerates the mTLS test fixtures: a CA, a server certificate for localhost, a
+# client certificate it signed, and a client certificate signed by an unrelated CA.
+set -e
+cd "$(dirname "$0")"
+
+key() { openssl genpkey -algorithm EC -pkeyopt ec_paramgen_curve:P-256 -out "$1"; }
+ca() {
+    key "$1.key"
+    openssl req -x509 -new -key "$1.key" -subj "/CN=$2" -days 36500 -out "$1.crt"
+}
+leaf() { # name, CN, issuing CA, extensions
+    key "$1.key"
+    openssl req -new -key "$1.key" -subj "/CN=$2" -out "$1.csr"
+    printf '%s\n' "$4" > "$1.ext"
+    openssl x509 -req -in "$1.csr" -CA "$3.crt" -CAkey "$3.key" -CAcreateserial -days 36500 \
+        -extfile "$1.ext" -out "$1.crt"
+    rm "$1.csr" "$1.ext"
+}
+
+ca ca "kubesec test ca"
+ca rogue-ca "rogue test ca"
+leaf server localhost ca "subjectAltName=DNS:localhost,IP:127.0.0.1
+extendedKeyUsage=serverAuth"
+leaf client transaction-service ca "extendedKeyUsage=clientAuth"
+leaf rogue-client transaction-service rogue-ca "extendedKeyUsage=clientAuth"
+rm -f ca.key rogue-ca.key ca.srl rogue-ca.srl rogue-ca.crt
@@ -1,30 +1,82 @@
 package com.kubesec.transaction.client;
 
 import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.filter.LogContextInterceptor;
+import com.kubesec.transaction.tenant.TenantHeaderInterceptor;
+import org.springframework.http.client.ClientHttpRequestFactory;
+import org.springframework.lang.Nullable;
 import org.springframework.stereotype.Component;
 import org.springframework.web.client.RestClient;
 
 import java.math.BigDecimal;
+import java.util.HashMap;
+import java.util.Map;
 import java.util.UUID;
 
 @Component
 public class AccountServiceClient {
 
+    static final String AUDIENCE = "account-service";
+
     private final RestClient restClient;
+    private final ServiceTokenSource serviceTokens;
 
-    public AccountServiceClient(AppConfig config) {
-        this.restClient = RestClient.builder()
+    // Service tokens are only issued over mTLS. Without it the user's token is forwarded,
+    // which account-service accepts on its user routes but not on balance reads and
+    // adjustments. The builder is Spring's, so calls carry the current trace in a
+    // traceparent header.
+    public AccountServiceClient(AppConfig config, RestClient.Builder restClientBuilder,
+                                ClientHttpRequestFactory serviceRequestFactory,
+                                @Nullable ServiceTokenSource serviceTokens) {
+        this.restClient = restClientBuilder
+                .requestFactory(serviceRequestFactory)
                 .baseUrl(config.getAccountServiceUrl())
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .requestInterceptor(new LogContextInterceptor())
                 .build();
+        this.serviceTokens = config.isMtlsEnabled() ? serviceTokens : null;
     }
 
     public BalanceResponse getBalance(UUID accountId, String authHeader) {
         return restClient.get()
-                .uri("/accounts/{id}/balance", accountId)
-                .header("Authorization", authHeader)
+                .uri("/api/v1/accounts/{id}/balance", accountId)
+                .header("Authorization", authorization(authHeader))
                 .retrieve()
                 .body(BalanceResponse.class);
     }
 
+    public AccountResponse getAccount(UUID accountId, String authHeader) {
+        return restClient.get()
+                .uri("/api/v1/accounts/{id}", accountId)
+                .header("Authorization", authorization(authHeader))
+                .retrieve()
+                .body(AccountResponse.class);
+    }
+
+    public void adjustBalance(UUID accountId, BigDecimal amount, UUID transactionId,
+                              String reason, String authHeader) {
+        Map<String, Object> body = new HashMap<>();
+        body.put("amount", amount);
+        body.put("transaction_id", transactionId);
+        body.put("reason", reason);
+
+        restClient.patch()
+                .uri("/api/v1/accounts/{id}/balance/adjust", accountId)
+                .header("Authorization", authorization(authHeader))
+                .body(body)
+                .retrieve()
+                .toBodilessEntity();
+    }
+
+    private String authorization(String authHeader) {
+        return serviceTokens != null ? "Bearer " + serviceTokens.token(AUDIENCE) : authHeader;
+    }
+
+    // Requested without a currency, so balance and currency are the account's own.
     public record BalanceResponse(UUID account_id, BigDecimal balance, String currency) {}
+
+    // A null max_daily_deposit means the account has no deposit limit.
+    // account_type doubles as the fee tier.
+    public record AccountResponse(UUID id, String currency, String account_type, BigDecimal max_daily_deposit,
+                                  String user_id) {}
 }
@@ -1,9 +1,13 @@
 package com.kubesec.transaction.client;
 
 import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.filter.LogContextInterceptor;
+import com.kubesec.transaction.tenant.TenantHeaderInterceptor;
+import org.springframework.http.client.ClientHttpRequestFactory;
 import org.springframework.stereotype.Component;
 import org.springframework.web.client.RestClient;
 
+import java.time.Instant;
 import java.util.Map;
 
 @Component
@@ -11,9 +15,14 @@ public class AuthServiceClient {
 
     private final RestClient restClient;
 
-    public AuthServiceClient(AppConfig config) {
-        this.restClient = RestClient.builder()
+    // The builder is Spring's, so calls carry the current trace in a traceparent header.
+    public AuthServiceClient(AppConfig config, RestClient.Builder restClientBuilder,
+                             ClientHttpRequestFactory serviceRequestFactory) {
+        this.restClient = restClientBuilder
+                .requestFactory(serviceRequestFactory)
                 .baseUrl(config.getAuthServiceUrl())
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .requestInterceptor(new LogContextInterceptor())
                 .build();
     }
 
@@ -25,5 +34,17 @@ public class AuthServiceClient {
                 .body(ValidateResponse.class);
     }
 
-    public record ValidateResponse(boolean valid, String user_id) {}
+    // Authenticated by this service's client certificate; the token names it as issuer.
+    public ServiceTokenResponse issueServiceToken(String audience) {
+        return restClient.post()
+                .uri("/api/v1/auth/service-token")
+                .body(Map.of("audience", audience))
+                .retrieve()
+                .body(ServiceTokenResponse.class);
+    }
+
+    // role is read from auth-service's user record on every validation.
+    public record ValidateResponse(boolean valid, String user_id, String role) {}
+
+    public record ServiceTokenResponse(String token, Instant expires_at) {}
 }
@@ -0,0 +1,15 @@
+package com.kubesec.transaction.client;
+
+// Raised when a balance deduction over NATS was rejected by account-service or got
+// no reply in time. Checked so callers decide what a missing deduction means.
+public class BalanceRpcException extends Exception {
+
+    private final String code;
+
+    public BalanceRpcException(String code, String message, Throwable cause) {
+        super(message, cause);
+        this.code = code;
+    }
+
+    public String getCode() { return code; }
+}
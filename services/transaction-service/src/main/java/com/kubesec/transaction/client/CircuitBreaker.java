@@ -0,0 +1,135 @@
+package com.kubesec.transaction.client;
+
+import io.micrometer.core.instrument.Gauge;
+import io.micrometer.core.instrument.MeterRegistry;
+
+import java.time.Duration;
+import java.time.Instant;
+import java.util.function.Predicate;
+import java.util.function.Supplier;
+
+// Fails calls to a struggling dependency fast instead of letting request threads queue
+// behind its timeout. Closed, consecutive failures are counted and the count is reset
+// every interval; reaching failureThreshold opens the circuit. Open, every call is
+// rejected with CircuitOpenException until timeout has passed. Half-open, up to
+// maxRequests trial calls go through: that many successes close the circuit, any
+// failure reopens it.
+public class CircuitBreaker {
+
+    // Ordinals are the values of the circuit_breaker_state gauge.
+    public enum State { CLOSED, HALF_OPEN, OPEN }
+
+    public static final String STATE_METRIC = "circuit_breaker_state";
+
+    private final String name;
+    private final int maxRequests;
+    private final Duration interval;
+    private final Duration timeout;
+    private final int failureThreshold;
+    private final Supplier<Instant> clock;
+
+    private State state = State.CLOSED;
+    private int consecutiveFailures;
+    private int consecutiveSuccesses;
+    private int inFlight;
+    // End of the current counting interval when closed, or of the open period when open.
+    private Instant expiry;
+
+    public CircuitBreaker(String name, int maxRequests, Duration interval, Duration timeout,
+                          int failureThreshold, MeterRegistry meterRegistry) {
+        this(name, maxRequests, interval, timeout, failureThreshold, meterRegistry, Instant::now);
+    }
+
+    CircuitBreaker(String name, int maxRequests, Duration interval, Duration timeout,
+                   int failureThreshold, MeterRegistry meterRegistry, Supplier<Instant> clock) {
+        this.name = name;
+        this.maxRequests = maxRequests;
+        this.interval = interval;
+        this.timeout = timeout;
+        this.failureThreshold = failureThreshold;
+        this.clock = clock;
+        this.expiry = clock.get().plus(interval);
+        Gauge.builder(STATE_METRIC, this, b -> b.state().ordinal())
+                .description("0 closed, 1 half-open, 2 open")
+                .tag("service", name)
+                .register(meterRegistry);
+    }
+
+    // Exceptions matching isFailure count against the circuit; others, such as a 4xx
+    // that says the dependency is healthy but the request was bad, count as successes.
+    // Both are rethrown.
+    public <T> T call(Supplier<T> call, Predicate<RuntimeException> isFailure) {
+        before();
+        try {
+            T result = call.get();
+            after(true);
+            return result;
+        } catch (RuntimeException e) {
+            after(!isFailure.test(e));
+            throw e;
+        }
+    }
+
+    public synchronized State state() {
+        refresh(clock.get());
+        return state;
+    }
+
+    private synchronized void before() {
+        refresh(clock.get());
+        if (state == State.OPEN) {
+            throw new CircuitOpenException(name);
+        }
+        if (state == State.HALF_OPEN) {
+            if (inFlight >= maxRequests) {
+                throw new CircuitOpenException(name);
+            }
+            inFlight++;
+        }
+    }
+
+    private synchronized void after(boolean success) {
+        Instant now = clock.get();
+        refresh(now);
+        if (state == State.HALF_OPEN) {
+            inFlight = Math.max(0, inFlight - 1);
+            if (!success) {
+                transition(State.OPEN, now);
+            } else if (++consecutiveSuccesses >= maxRequests) {
+                transition(State.CLOSED, now);
+            }
+            return;
+        }
+        if (state == State.CLOSED) {
+            if (success) {
+                consecutiveFailures = 0;
+            } else if (++consecutiveFailures >= failureThreshold) {
+                transition(State.OPEN, now);
+            }
+        }
+    }
+
+    private void refresh(Instant now) {
+        if (now.isBefore(expiry)) {
+            return;
+        }
+        if (state == State.OPEN) {
+            transition(State.HALF_OPEN, now);
+        } else if (state == State.CLOSED) {
+            consecutiveFailures = 0;
+            expiry = now.plus(interval);
+        }
+    }
+
+    private void transition(State next, Instant now) {
+        state = next;
+        consecutiveFailures = 0;
+        consecutiveSuccesses = 0;
+        inFlight = 0;
+        expiry = switch (next) {
+            case CLOSED -> now.plus(interval);
+            case OPEN -> now.plus(timeout);
+            case HALF_OPEN -> Instant.MAX;
+        };
+    }
+}
@@ -0,0 +1,8 @@
+package com.kubesec.transaction.client;
+
+public class CircuitOpenException extends RuntimeException {
+
+    public CircuitOpenException(String name) {
+        super(name + " circuit is open");
+    }
+}
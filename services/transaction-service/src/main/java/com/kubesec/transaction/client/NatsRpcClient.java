@@ -0,0 +1,88 @@
+package com.kubesec.transaction.client;
+
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.dto.BalanceDeductReply;
+import com.kubesec.transaction.model.dto.BalanceDeductRequest;
+import com.kubesec.transaction.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Message;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.util.UUID;
+import java.util.concurrent.CancellationException;
+import java.util.concurrent.CompletableFuture;
+import java.util.concurrent.ExecutionException;
+import java.util.concurrent.TimeUnit;
+import java.util.concurrent.TimeoutException;
+
+// Deducts balances synchronously over NATS request-reply, for paths that must know
+// the outcome before continuing rather than relying on a published event.
+@Component
+@Profile("!test")
+public class NatsRpcClient {
+
+    static final String DEDUCT_SUBJECT = "accounts.balance.deduct";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final Duration requestTimeout;
+
+    public NatsRpcClient(Connection natsConnection, ObjectMapper objectMapper, AppConfig config) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.requestTimeout = config.getNatsRequestTimeout();
+    }
+
+    // The transaction id makes the deduction idempotent on the account-service side;
+    // pass null for adjustments that must always apply.
+    public void requestBalanceDeduction(UUID accountId, BigDecimal amount, UUID transactionId, String reason)
+            throws BalanceRpcException {
+        byte[] data;
+        try {
+            data = objectMapper.writeValueAsBytes(
+                    new BalanceDeductRequest(TenantContext.require(), accountId, amount, transactionId, reason));
+        } catch (JsonProcessingException e) {
+            throw new BalanceRpcException("encode_failed", "could not encode deduction request", e);
+        }
+
+        BalanceDeductReply reply = decode(request(data));
+        if (!reply.ok()) {
+            throw new BalanceRpcException(reply.code(), "deduction rejected: " + reply.error(), null);
+        }
+    }
+
+    // Waits at most the configured timeout. With no subscriber on the subject the
+    // server answers "no responders" straight away, which surfaces as a cancellation.
+    private Message request(byte[] data) throws BalanceRpcException {
+        CompletableFuture<Message> future = natsConnection.request(DEDUCT_SUBJECT, data);
+        try {
+            return future.get(requestTimeout.toMillis(), TimeUnit.MILLISECONDS);
+        } catch (TimeoutException e) {
+            future.cancel(true);
+            throw new BalanceRpcException("timeout", "no reply on " + DEDUCT_SUBJECT + " within " + requestTimeout, e);
+        } catch (CancellationException e) {
+            throw new BalanceRpcException("no_responders", "no responders on " + DEDUCT_SUBJECT, e);
+        } catch (InterruptedException e) {
+            future.cancel(true);
+            Thread.currentThread().interrupt();
+            throw new BalanceRpcException("interrupted", "request on " + DEDUCT_SUBJECT + " was interrupted", e);
+        } catch (ExecutionException e) {
+            throw new BalanceRpcException("request_failed",
+                    "request on " + DEDUCT_SUBJECT + " failed: " + e.getCause().getMessage(), e.getCause());
+        }
+    }
+
+    private BalanceDeductReply decode(Message msg) throws BalanceRpcException {
+        try {
+            return objectMapper.readValue(msg.getData(), BalanceDeductReply.class);
+        } catch (IOException e) {
+            throw new BalanceRpcException("decode_failed", "could not decode deduction reply", e);
+        }
+    }
+}
@@ -0,0 +1,40 @@
+package com.kubesec.transaction.client;
+
+import org.springframework.stereotype.Component;
+
+import java.time.Duration;
+import java.time.Instant;
+import java.util.Map;
+import java.util.concurrent.ConcurrentHashMap;
+
+// Supplies the short-lived tokens this service presents to the others in place of the
+// user's own token. Each audience's token is reused until it is about to expire, so
+// auth-service sees one request per audience a minute rather than one per call.
+@Component
+public class ServiceTokenSource {
+
+    // Refresh ahead of expiry so a token can't lapse between being handed out and
+    // reaching the other service.
+    static final Duration REFRESH_MARGIN = Duration.ofSeconds(10);
+
+    private final AuthServiceClient authClient;
+    private final Map<String, AuthServiceClient.ServiceTokenResponse> tokens = new ConcurrentHashMap<>();
+
+    public ServiceTokenSource(AuthServiceClient authClient) {
+        this.authClient = authClient;
+    }
+
+    public String token(String audience) {
+        return token(audience, Instant.now());
+    }
+
+    String token(String audience, Instant now) {
+        // compute holds the entry's lock, so concurrent callers share a single refresh.
+        return tokens.compute(audience, (aud, cached) -> {
+            if (cached != null && now.isBefore(cached.expires_at().minus(REFRESH_MARGIN))) {
+                return cached;
+            }
+            return authClient.issueServiceToken(aud);
+        }).token();
+    }
+}
@@ -0,0 +1,118 @@
+package com.kubesec.transaction.client;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.stereotype.Component;
+
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.Base64;
+import java.util.HexFormat;
+import java.util.Optional;
+import java.util.Set;
+import java.util.concurrent.TimeUnit;
+
+// Caches successful auth-service validations (user and role) keyed by tenant and token hash. Entries expire with
+// the token itself, and each user's keys are also listed in a set so a logout-all can
+// evict them early. Redis failures are treated as cache misses so auth keeps working
+// without the cache.
+@Component
+public class TokenValidationCache {
+
+    private static final Logger log = LoggerFactory.getLogger(TokenValidationCache.class);
+    private static final String KEY_PREFIX = "validated:";
+    private static final String USER_KEY_PREFIX = "validated-user:";
+
+    private final StringRedisTemplate redis;
+    private final ObjectMapper objectMapper;
+
+    public TokenValidationCache(StringRedisTemplate redis, ObjectMapper objectMapper) {
+        this.redis = redis;
+        this.objectMapper = objectMapper;
+    }
+
+    // Entries that don't parse, such as ones written before the role was cached, are misses.
+    public Optional<AuthServiceClient.ValidateResponse> get(String token) {
+        try {
+            String cached = redis.opsForValue().get(key(token));
+            if (cached == null) {
+                return Optional.empty();
+            }
+            return Optional.of(objectMapper.readValue(cached, AuthServiceClient.ValidateResponse.class));
+        } catch (Exception e) {
+            log.warn("token cache lookup failed: {}", e.getMessage());
+            return Optional.empty();
+        }
+    }
+
+    public void put(String token, AuthServiceClient.ValidateResponse identity) {
+        Duration ttl = remainingLifetime(token);
+        if (ttl.isZero() || ttl.isNegative()) {
+            return;
+        }
+        try {
+            String key = key(token);
+            redis.opsForValue().set(key, objectMapper.writeValueAsString(identity), ttl);
+            // The index lives as long as the longest-lived entry it lists
+            String userKey = userKey(identity.user_id());
+            redis.opsForSet().add(userKey, key);
+            Long remaining = redis.getExpire(userKey, TimeUnit.SECONDS);
+            if (remaining == null || remaining < ttl.toSeconds()) {
+                redis.expire(userKey, ttl);
+            }
+        } catch (Exception e) {
+            log.warn("token cache write failed: {}", e.getMessage());
+        }
+    }
+
+    // Called when the user logs out everywhere; their next request is validated again.
+    public void evictUser(String userId) {
+        try {
+            String userKey = userKey(userId);
+            Set<String> keys = redis.opsForSet().members(userKey);
+            if (keys != null && !keys.isEmpty()) {
+                redis.delete(keys);
+            }
+            redis.delete(userKey);
+        } catch (Exception e) {
+            log.warn("token cache eviction failed: {}", e.getMessage());
+        }
+    }
+
+    // The signature was already checked by auth-service; the payload is only read for exp.
+    private Duration remainingLifetime(String token) {
+        try {
+            String[] parts = token.split("\\.");
+            JsonNode claims = objectMapper.readTree(Base64.getUrlDecoder().decode(parts[1]));
+            long exp = claims.path("exp").asLong(0);
+            return Duration.between(Instant.now(), Instant.ofEpochSecond(exp));
+        } catch (Exception e) {
+            return Duration.ZERO;
+        }
+    }
+
+    // auth-service rejects tokens presented under another tenant, so a hit must not cross tenants.
+    private static String key(String token) {
+        return KEY_PREFIX + TenantContext.require() + ":" + hash(token);
+    }
+
+    private static String userKey(String userId) {
+        return USER_KEY_PREFIX + TenantContext.require() + ":" + userId;
+    }
+
+    private static String hash(String token) {
+        try {
+            byte[] digest = MessageDigest.getInstance("SHA-256").digest(token.getBytes(StandardCharsets.UTF_8));
+            return HexFormat.of().formatHex(digest);
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+}
@@ -3,20 +3,142 @@ package com.kubesec.transaction.config;
 import org.springframework.boot.context.properties.ConfigurationProperties;
 import org.springframework.context.annotation.Configuration;
 
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+
 @Configuration
 @ConfigurationProperties(prefix = "app")
 public class AppConfig {
 
     private String natsUrl = "nats://localhost:4222";
+    private Duration natsPublishTimeout = Duration.ofSeconds(2);
+    private int natsPublishRetries = 3; // attempts per publish, including the first
+    private Duration natsPublishRetryBackoff = Duration.ofMillis(100); // doubled after each failed attempt
+    private Duration natsRequestTimeout = Duration.ofSeconds(2);
     private String authServiceUrl = "http://localhost:8082";
+    private int authCircuitFailureThreshold = 5; // consecutive failures that open the circuit
+    private Duration authCircuitInterval = Duration.ofSeconds(60); // closed: failure count reset period
+    private Duration authCircuitTimeout = Duration.ofSeconds(30); // open: time before trial calls
+    private int authCircuitMaxRequests = 1; // half-open: trial calls allowed, and successes needed to close
     private String accountServiceUrl = "http://localhost:8081";
+    private String adminApiKey = "";
+    private String webhookSecret = "";
+    private Map<String, BigDecimal> fxRates = new HashMap<>(); // keyed "FROM_TO", e.g. USD_EUR
+    private String feeServiceUrl = ""; // empty uses the flat fee rate
+    private BigDecimal feeRate = BigDecimal.ZERO;
+    private boolean enableTestEndpoints = false;
+    private String mtlsCaCertFile = "";
+    private String mtlsServerCertFile = "";
+    private String mtlsServerKeyFile = "";
+    private String tlsCertFile = ""; // server TLS without client certs; see TlsEnvironmentPostProcessor
+    private String tlsKeyFile = "";
+    private int dbSchemaRetryAttempts = 8; // 0 skips the schema check
+    private int dbMaxIdleConns; // defaulted by DbPoolEnvironmentPostProcessor
+    private Duration dbSchemaRetryBackoff = Duration.ofSeconds(1);
+    private long maxContentLengthBytes = 10 * 1024 * 1024; // declared Content-Length limit
+    private long maxBodyReadBytes = 10 * 1024 * 1024; // limit on bytes actually read, for chunked bodies
+    private long maxDecompressedBodyBytes = 10 * 1024 * 1024;
+    private String otelEndpoint = ""; // OTLP collector base URL; empty keeps traces in-process
+    private String serviceName = "transaction-service"; // service.name on exported spans
+    private List<String> corsAllowedOrigins = List.of("*"); // * allows any origin
 
     public String getNatsUrl() { return natsUrl; }
     public void setNatsUrl(String natsUrl) { this.natsUrl = natsUrl; }
 
+    public Duration getNatsPublishTimeout() { return natsPublishTimeout; }
+    public void setNatsPublishTimeout(Duration natsPublishTimeout) { this.natsPublishTimeout = natsPublishTimeout; }
+
+    public int getNatsPublishRetries() { return natsPublishRetries; }
+    public void setNatsPublishRetries(int natsPublishRetries) { this.natsPublishRetries = natsPublishRetries; }
+
+    public Duration getNatsPublishRetryBackoff() { return natsPublishRetryBackoff; }
+    public void setNatsPublishRetryBackoff(Duration natsPublishRetryBackoff) { this.natsPublishRetryBackoff = natsPublishRetryBackoff; }
+
+    public Duration getNatsRequestTimeout() { return natsRequestTimeout; }
+    public void setNatsRequestTimeout(Duration natsRequestTimeout) { this.natsRequestTimeout = natsRequestTimeout; }
+
     public String getAuthServiceUrl() { return authServiceUrl; }
     public void setAuthServiceUrl(String authServiceUrl) { this.authServiceUrl = authServiceUrl; }
 
+    public int getAuthCircuitFailureThreshold() { return authCircuitFailureThreshold; }
+    public void setAuthCircuitFailureThreshold(int authCircuitFailureThreshold) { this.authCircuitFailureThreshold = authCircuitFailureThreshold; }
+
+    public Duration getAuthCircuitInterval() { return authCircuitInterval; }
+    public void setAuthCircuitInterval(Duration authCircuitInterval) { this.authCircuitInterval = authCircuitInterval; }
+
+    public Duration getAuthCircuitTimeout() { return authCircuitTimeout; }
+    public void setAuthCircuitTimeout(Duration authCircuitTimeout) { this.authCircuitTimeout = authCircuitTimeout; }
+
+    public int getAuthCircuitMaxRequests() { return authCircuitMaxRequests; }
+    public void setAuthCircuitMaxRequests(int authCircuitMaxRequests) { this.authCircuitMaxRequests = authCircuitMaxRequests; }
+
     public String getAccountServiceUrl() { return accountServiceUrl; }
     public void setAccountServiceUrl(String accountServiceUrl) { this.accountServiceUrl = accountServiceUrl; }
+
+    public String getAdminApiKey() { return adminApiKey; }
+    public void setAdminApiKey(String adminApiKey) { this.adminApiKey = adminApiKey; }
+
+    public String getWebhookSecret() { return webhookSecret; }
+    public void setWebhookSecret(String webhookSecret) { this.webhookSecret = webhookSecret; }
+
+    public Map<String, BigDecimal> getFxRates() { return fxRates; }
+    public void setFxRates(Map<String, BigDecimal> fxRates) { this.fxRates = fxRates; }
+
+    public String getFeeServiceUrl() { return feeServiceUrl; }
+    public void setFeeServiceUrl(String feeServiceUrl) { this.feeServiceUrl = feeServiceUrl; }
+
+    public BigDecimal getFeeRate() { return feeRate; }
+    public void setFeeRate(BigDecimal feeRate) { this.feeRate = feeRate; }
+
+    public boolean isEnableTestEndpoints() { return enableTestEndpoints; }
+    public void setEnableTestEndpoints(boolean enableTestEndpoints) { this.enableTestEndpoints = enableTestEndpoints; }
+
+    public String getMtlsCaCertFile() { return mtlsCaCertFile; }
+    public void setMtlsCaCertFile(String mtlsCaCertFile) { this.mtlsCaCertFile = mtlsCaCertFile; }
+
+    public String getMtlsServerCertFile() { return mtlsServerCertFile; }
+    public void setMtlsServerCertFile(String mtlsServerCertFile) { this.mtlsServerCertFile = mtlsServerCertFile; }
+
+    public String getMtlsServerKeyFile() { return mtlsServerKeyFile; }
+    public void setMtlsServerKeyFile(String mtlsServerKeyFile) { this.mtlsServerKeyFile = mtlsServerKeyFile; }
+
+    public String getTlsCertFile() { return tlsCertFile; }
+    public void setTlsCertFile(String tlsCertFile) { this.tlsCertFile = tlsCertFile; }
+
+    public String getTlsKeyFile() { return tlsKeyFile; }
+    public void setTlsKeyFile(String tlsKeyFile) { this.tlsKeyFile = tlsKeyFile; }
+
+    public int getDbSchemaRetryAttempts() { return dbSchemaRetryAttempts; }
+    public void setDbSchemaRetryAttempts(int dbSchemaRetryAttempts) { this.dbSchemaRetryAttempts = dbSchemaRetryAttempts; }
+
+    public int getDbMaxIdleConns() { return dbMaxIdleConns; }
+    public void setDbMaxIdleConns(int dbMaxIdleConns) { this.dbMaxIdleConns = dbMaxIdleConns; }
+
+    public Duration getDbSchemaRetryBackoff() { return dbSchemaRetryBackoff; }
+    public void setDbSchemaRetryBackoff(Duration dbSchemaRetryBackoff) { this.dbSchemaRetryBackoff = dbSchemaRetryBackoff; }
+
+    public long getMaxDecompressedBodyBytes() { return maxDecompressedBodyBytes; }
+    public void setMaxDecompressedBodyBytes(long maxDecompressedBodyBytes) { this.maxDecompressedBodyBytes = maxDecompressedBodyBytes; }
+
+    public boolean isMtlsEnabled() {
+        return !mtlsCaCertFile.isBlank() && !mtlsServerCertFile.isBlank() && !mtlsServerKeyFile.isBlank();
+    }
+
+    public long getMaxContentLengthBytes() { return maxContentLengthBytes; }
+    public void setMaxContentLengthBytes(long maxContentLengthBytes) { this.maxContentLengthBytes = maxContentLengthBytes; }
+
+    public long getMaxBodyReadBytes() { return maxBodyReadBytes; }
+    public void setMaxBodyReadBytes(long maxBodyReadBytes) { this.maxBodyReadBytes = maxBodyReadBytes; }
+
+    public String getOtelEndpoint() { return otelEndpoint; }
+    public void setOtelEndpoint(String otelEndpoint) { this.otelEndpoint = otelEndpoint; }
+
+    public String getServiceName() { return serviceName; }
+    public void setServiceName(String serviceName) { this.serviceName = serviceName; }
+
+    public List<String> getCorsAllowedOrigins() { return corsAllowedOrigins; }
+    public void setCorsAllowedOrigins(List<String> corsAllowedOrigins) { this.corsAllowedOrigins = corsAllowedOrigins; }
 }
@@ -0,0 +1,30 @@
+package com.kubesec.transaction.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.Map;
+
+// Supplies the default for DB_MAX_IDLE_CONNS, the number of idle connections the pool
+// keeps open. A fixed count is too many on a one-core pod and too few on a large one, so
+// the default follows the CPUs the JVM may use: two per core, enough for a request
+// thread on every core plus one whose connection is mid-handoff. availableProcessors()
+// already honours the container's CPU limit. Hikari caps the value at
+// maximum-pool-size, and an explicit DB_MAX_IDLE_CONNS still wins because this source
+// is added last.
+public class DbPoolEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    static final String PROPERTY = "DB_MAX_IDLE_CONNS";
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        environment.getPropertySources().addLast(
+                new MapPropertySource("dbPoolDefaults", Map.of(PROPERTY, defaultMaxIdleConns())));
+    }
+
+    static int defaultMaxIdleConns() {
+        return Runtime.getRuntime().availableProcessors() * 2;
+    }
+}
@@ -0,0 +1,20 @@
+package com.kubesec.transaction.config;
+
+import com.kubesec.transaction.fees.FeeCalculator;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.fees.HttpFeeCalculator;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+
+@Configuration
+public class FeeConfig {
+
+    // An external fee service takes precedence; otherwise the flat rate applies.
+    @Bean
+    public FeeCalculator feeCalculator(AppConfig appConfig) {
+        if (!appConfig.getFeeServiceUrl().isBlank()) {
+            return new HttpFeeCalculator(appConfig.getFeeServiceUrl());
+        }
+        return new FlatFeeCalculator(appConfig.getFeeRate());
+    }
+}
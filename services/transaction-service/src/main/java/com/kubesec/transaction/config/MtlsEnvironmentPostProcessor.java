@@ -0,0 +1,40 @@
+package com.kubesec.transaction.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.HashMap;
+import java.util.Map;
+
+// Serves HTTPS from the PEM files in MTLS_* once all three are set. Client certificates
+// are requested but not required at the handshake: only the service-to-service routes
+// insist on one, so health probes and user traffic keep working without a cert.
+public class MtlsEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    public static final String BUNDLE = "mtls";
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String caCert = environment.getProperty("MTLS_CA_CERT_FILE");
+        String serverCert = environment.getProperty("MTLS_SERVER_CERT_FILE");
+        String serverKey = environment.getProperty("MTLS_SERVER_KEY_FILE");
+        if (isBlank(caCert) || isBlank(serverCert) || isBlank(serverKey)) {
+            return;
+        }
+
+        String prefix = "spring.ssl.bundle.pem." + BUNDLE;
+        Map<String, Object> properties = new HashMap<>();
+        properties.put(prefix + ".keystore.certificate", "file:" + serverCert);
+        properties.put(prefix + ".keystore.private-key", "file:" + serverKey);
+        properties.put(prefix + ".truststore.certificate", "file:" + caCert);
+        properties.put("server.ssl.bundle", BUNDLE);
+        properties.put("server.ssl.client-auth", "want");
+        environment.getPropertySources().addFirst(new MapPropertySource("mtls", properties));
+    }
+
+    private static boolean isBlank(String value) {
+        return value == null || value.isBlank();
+    }
+}
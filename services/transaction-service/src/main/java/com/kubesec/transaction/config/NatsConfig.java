@@ -1,8 +1,13 @@
 package com.kubesec.transaction.config;
 
 import io.nats.client.Connection;
+import io.nats.client.JetStream;
+import io.nats.client.JetStreamApiException;
+import io.nats.client.JetStreamManagement;
 import io.nats.client.Nats;
 import io.nats.client.Options;
+import io.nats.client.api.RetentionPolicy;
+import io.nats.client.api.StreamConfiguration;
 import jakarta.annotation.PreDestroy;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
@@ -11,12 +16,20 @@ import org.springframework.context.annotation.Configuration;
 import org.springframework.context.annotation.Profile;
 
 import java.io.IOException;
+import java.time.Duration;
+import java.util.List;
 
 @Configuration
 @Profile("!test")
 public class NatsConfig {
 
     private static final Logger log = LoggerFactory.getLogger(NatsConfig.class);
+    static final String STREAM = "TRANSACTIONS";
+    static final List<String> SUBJECTS =
+            List.of("transactions.completed.*", "transactions.reversed", "transactions.failed",
+                    "transactions.deposit", "transactions.withdrawal", "transactions.batch_completed");
+    // Events a consumer hasn't taken within a week are dropped rather than kept forever.
+    static final Duration MAX_AGE = Duration.ofDays(7);
     private Connection connection;
 
     @Bean
@@ -29,11 +42,44 @@ public class NatsConfig {
         return connection;
     }
 
+    @Bean
+    public JetStream jetStream(Connection natsConnection) throws IOException, JetStreamApiException {
+        ensureStream(natsConnection.jetStreamManagement());
+        return natsConnection.jetStream();
+    }
+
+    // account-service's durable consumer acks each completed event once applied, which is
+    // what removes it from the WorkQueue stream; the rest age out. A stream with another
+    // retention policy would keep or share events differently, and JetStream cannot change
+    // retention in place, so startup fails until the stream is recreated.
+    static void ensureStream(JetStreamManagement jsm) throws IOException, JetStreamApiException {
+        if (!jsm.getStreamNames().contains(STREAM)) {
+            jsm.addStream(StreamConfiguration.builder()
+                    .name(STREAM)
+                    .subjects(SUBJECTS)
+                    .retentionPolicy(RetentionPolicy.WorkQueue)
+                    .maxAge(MAX_AGE)
+                    .build());
+            log.info("Created JetStream stream {}", STREAM);
+            return;
+        }
+        StreamConfiguration current = jsm.getStreamInfo(STREAM).getConfiguration();
+        if (current.getRetentionPolicy() != RetentionPolicy.WorkQueue) {
+            throw new IllegalStateException("JetStream stream " + STREAM + " has " + current.getRetentionPolicy()
+                    + " retention instead of WorkQueue; delete it so it can be recreated");
+        }
+        // Streams created by earlier releases lack some subjects and the age limit.
+        if (!current.getSubjects().containsAll(SUBJECTS) || !MAX_AGE.equals(current.getMaxAge())) {
+            jsm.updateStream(StreamConfiguration.builder(current).subjects(SUBJECTS).maxAge(MAX_AGE).build());
+            log.info("Updated JetStream stream {} to subjects {} and max age {}", STREAM, SUBJECTS, MAX_AGE);
+        }
+    }
+
     @PreDestroy
     public void destroy() {
         if (connection != null) {
             try {
-                connection.drain(java.time.Duration.ofSeconds(5));
+                connection.drain(Duration.ofSeconds(5));
                 log.info("NATS connection drained");
             } catch (Exception e) {
                 log.warn("Error draining NATS connection: {}", e.getMessage());
@@ -0,0 +1,23 @@
+package com.kubesec.transaction.config;
+
+import org.springframework.boot.http.client.ClientHttpRequestFactoryBuilder;
+import org.springframework.boot.http.client.ClientHttpRequestFactorySettings;
+import org.springframework.boot.ssl.SslBundles;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+import org.springframework.http.client.ClientHttpRequestFactory;
+
+@Configuration
+public class ServiceClientConfig {
+
+    // With mTLS configured, calls to the other services present this service's own
+    // certificate and trust only peers signed by the shared CA.
+    @Bean
+    public ClientHttpRequestFactory serviceRequestFactory(AppConfig appConfig, SslBundles sslBundles) {
+        ClientHttpRequestFactorySettings settings = ClientHttpRequestFactorySettings.defaults();
+        if (appConfig.isMtlsEnabled()) {
+            settings = settings.withSslBundle(sslBundles.getBundle(MtlsEnvironmentPostProcessor.BUNDLE));
+        }
+        return ClientHttpRequestFactoryBuilder.detect().build(settings);
+    }
+}
@@ -0,0 +1,64 @@
+package com.kubesec.transaction.config;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.env.EnvironmentPostProcessor;
+import org.springframework.core.env.ConfigurableEnvironment;
+import org.springframework.core.env.MapPropertySource;
+
+import java.util.HashMap;
+import java.util.Map;
+
+// Serves HTTPS from TLS_CERT_FILE/TLS_KEY_FILE once both are set. When the MTLS_*
+// files are also configured the mTLS bundle takes precedence. Either way the server
+// is held to TLS 1.2+ with forward-secret AEAD suites, and the PEM files are
+// re-read when they change on disk, so a renewed certificate is picked up without
+// a restart.
+public class TlsEnvironmentPostProcessor implements EnvironmentPostProcessor {
+
+    public static final String BUNDLE = "tls";
+    static final String PROTOCOLS = "TLSv1.3,TLSv1.2";
+    // No RC4, 3DES, CBC or static-RSA key exchange. The TLS 1.3 suites come first.
+    static final String CIPHERS = String.join(",",
+            "TLS_AES_128_GCM_SHA256",
+            "TLS_AES_256_GCM_SHA384",
+            "TLS_CHACHA20_POLY1305_SHA256",
+            "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+            "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+            "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+            "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+            "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+            "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256");
+
+    @Override
+    public void postProcessEnvironment(ConfigurableEnvironment environment, SpringApplication application) {
+        String certFile = environment.getProperty("TLS_CERT_FILE");
+        String keyFile = environment.getProperty("TLS_KEY_FILE");
+        if (isBlank(certFile) != isBlank(keyFile)) {
+            throw new IllegalStateException("TLS_CERT_FILE and TLS_KEY_FILE must be set together");
+        }
+        boolean mtls = !isBlank(environment.getProperty("MTLS_CA_CERT_FILE"))
+                && !isBlank(environment.getProperty("MTLS_SERVER_CERT_FILE"))
+                && !isBlank(environment.getProperty("MTLS_SERVER_KEY_FILE"));
+        if (isBlank(certFile) && !mtls) {
+            return;
+        }
+
+        Map<String, Object> properties = new HashMap<>();
+        if (mtls) {
+            properties.put("spring.ssl.bundle.pem." + MtlsEnvironmentPostProcessor.BUNDLE + ".reload-on-update", true);
+        } else {
+            String prefix = "spring.ssl.bundle.pem." + BUNDLE;
+            properties.put(prefix + ".keystore.certificate", "file:" + certFile);
+            properties.put(prefix + ".keystore.private-key", "file:" + keyFile);
+            properties.put(prefix + ".reload-on-update", true);
+            properties.put("server.ssl.bundle", BUNDLE);
+        }
+        properties.put("server.ssl.enabled-protocols", PROTOCOLS);
+        properties.put("server.ssl.ciphers", CIPHERS);
+        environment.getPropertySources().addFirst(new MapPropertySource("tls", properties));
+    }
+
+    private static boolean isBlank(String value) {
+        return value == null || value.isBlank();
+    }
+}
@@ -0,0 +1,47 @@
+package com.kubesec.transaction.controller;
+
+import com.kubesec.transaction.service.TransactionService;
+import org.springframework.format.annotation.DateTimeFormat;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.DeleteMapping;
+import org.springframework.web.bind.annotation.PathVariable;
+import org.springframework.web.bind.annotation.PostMapping;
+import org.springframework.web.bind.annotation.RequestParam;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.time.OffsetDateTime;
+import java.util.LinkedHashMap;
+import java.util.Map;
+import java.util.UUID;
+
+@RestController
+public class AdminController {
+
+    private final TransactionService transactionService;
+
+    public AdminController(TransactionService transactionService) {
+        this.transactionService = transactionService;
+    }
+
+    @PostMapping("/admin/transactions/replay")
+    public Map<String, Object> replayTransactionEvents(
+            @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime from,
+            @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime to) {
+
+        int replayed = transactionService.replayCompletedEvents(from, to);
+
+        Map<String, Object> response = new LinkedHashMap<>();
+        response.put("replayed", replayed);
+        response.put("from", from);
+        response.put("to", to);
+        return response;
+    }
+
+    // Voids a transaction administratively: it disappears from lookups and listings but
+    // stays in the table. Scoped to the tenant in X-Tenant-ID.
+    @DeleteMapping("/admin/transactions/{id}")
+    public ResponseEntity<Void> softDeleteTransaction(@PathVariable UUID id) {
+        transactionService.softDeleteTransaction(id);
+        return ResponseEntity.noContent().build();
+    }
+}
@@ -0,0 +1,48 @@
+package com.kubesec.transaction.controller;
+
+import com.kubesec.transaction.model.ScheduledTransfer;
+import com.kubesec.transaction.model.dto.CreateScheduledTransferRequest;
+import com.kubesec.transaction.model.dto.UpdateScheduledTransferRequest;
+import com.kubesec.transaction.service.ScheduledTransferService;
+import com.kubesec.transaction.validation.ValidatedBody;
+import org.springframework.http.HttpStatus;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.*;
+
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+
+@RestController
+public class ScheduledTransferController {
+
+    private final ScheduledTransferService scheduledTransferService;
+
+    public ScheduledTransferController(ScheduledTransferService scheduledTransferService) {
+        this.scheduledTransferService = scheduledTransferService;
+    }
+
+    @PostMapping("/api/v1/accounts/{accountId}/scheduled-transfer")
+    public ResponseEntity<ScheduledTransfer> create(@PathVariable UUID accountId,
+                                                    @RequestBody @ValidatedBody("create-scheduled-transfer") CreateScheduledTransferRequest request) {
+        ScheduledTransfer transfer = scheduledTransferService.create(accountId, request);
+        return ResponseEntity.status(HttpStatus.CREATED).body(transfer);
+    }
+
+    @GetMapping("/api/v1/accounts/{accountId}/scheduled-transfer")
+    public Map<String, List<ScheduledTransfer>> list(@PathVariable UUID accountId) {
+        return Map.of("scheduled_transfers", scheduledTransferService.list(accountId));
+    }
+
+    @PatchMapping("/api/v1/accounts/{accountId}/scheduled-transfer/{id}")
+    public ScheduledTransfer update(@PathVariable UUID accountId, @PathVariable UUID id,
+                                    @RequestBody @ValidatedBody("update-scheduled-transfer") UpdateScheduledTransferRequest request) {
+        return scheduledTransferService.update(accountId, id, request);
+    }
+
+    @DeleteMapping("/api/v1/accounts/{accountId}/scheduled-transfer/{id}")
+    public Map<String, String> cancel(@PathVariable UUID accountId, @PathVariable UUID id) {
+        scheduledTransferService.cancel(accountId, id);
+        return Map.of("message", "scheduled transfer cancelled");
+    }
+}
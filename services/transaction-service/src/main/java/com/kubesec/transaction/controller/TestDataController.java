@@ -0,0 +1,34 @@
+package com.kubesec.transaction.controller;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.ResourceNotFoundException;
+import com.kubesec.transaction.service.TransactionService;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.DeleteMapping;
+import org.springframework.web.bind.annotation.PathVariable;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.UUID;
+
+// Lets integration tests clean up the transactions they create in shared databases.
+// Unless ENABLE_TEST_ENDPOINTS=true the route answers 404 as if it did not exist.
+@RestController
+public class TestDataController {
+
+    private final TransactionService transactionService;
+    private final AppConfig config;
+
+    public TestDataController(TransactionService transactionService, AppConfig config) {
+        this.transactionService = transactionService;
+        this.config = config;
+    }
+
+    @DeleteMapping("/transactions/{id}")
+    public ResponseEntity<Void> deleteTransaction(@PathVariable UUID id) {
+        if (!config.isEnableTestEndpoints()) {
+            throw new ResourceNotFoundException("not found");
+        }
+        transactionService.deleteTransaction(id);
+        return ResponseEntity.noContent().build();
+    }
+}
@@ -1,14 +1,28 @@
 package com.kubesec.transaction.controller;
 
+import com.kubesec.transaction.exception.ForbiddenException;
+import com.kubesec.transaction.exception.ValidationException;
+import com.kubesec.transaction.filter.AuthFilter;
+import com.kubesec.transaction.model.Cursor;
 import com.kubesec.transaction.model.Transaction;
 import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.TransactionPage;
+import com.kubesec.transaction.model.TransactionStateEvent;
+import com.kubesec.transaction.model.TransferBatch;
+import com.kubesec.transaction.model.TransferResult;
+import com.kubesec.transaction.model.dto.BatchTransferRequest;
+import com.kubesec.transaction.model.dto.DepositRequest;
 import com.kubesec.transaction.model.dto.TransferRequest;
+import com.kubesec.transaction.model.dto.WithdrawalRequest;
 import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.validation.ValidatedBody;
 import jakarta.servlet.http.HttpServletRequest;
+import org.springframework.format.annotation.DateTimeFormat;
 import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.*;
 
+import java.time.OffsetDateTime;
 import java.util.LinkedHashMap;
 import java.util.List;
 import java.util.Map;
@@ -28,41 +42,158 @@ public class TransactionController {
         return Map.of("status", "healthy");
     }
 
+    // A replay of an earlier request with the same Idempotency-Key is answered with 200
+    // and Idempotent-Replayed: true instead of 201.
     @PostMapping("/transactions/transfer")
-    public ResponseEntity<Transaction> createTransfer(@RequestBody TransferRequest request,
+    public ResponseEntity<Transaction> createTransfer(@RequestBody @ValidatedBody("transfer") TransferRequest request,
+                                                       @RequestHeader(name = "Idempotency-Key", required = false) String idempotencyKey,
                                                        HttpServletRequest httpRequest) {
         String authHeader = httpRequest.getHeader("Authorization");
-        Transaction txn = transactionService.createTransfer(request, authHeader);
+        TransferResult result = transactionService.createTransfer(request, authHeader, idempotencyKey);
+        if (result.replayed()) {
+            return ResponseEntity.ok().header("Idempotent-Replayed", "true").body(result.transaction());
+        }
+        return ResponseEntity.status(HttpStatus.CREATED).body(result.transaction());
+    }
+
+    // At most 100 transfers, enforced by the schema. One invalid or uncovered transfer
+    // rejects the whole batch.
+    @PostMapping("/transactions/batch")
+    public ResponseEntity<TransferBatch> createBatch(@RequestBody @ValidatedBody("batch-transfer") BatchTransferRequest request,
+                                                      HttpServletRequest httpRequest) {
+        String authHeader = httpRequest.getHeader("Authorization");
+        TransferBatch batch = transactionService.createBatch(request.transfers(), authHeader);
+        return ResponseEntity.status(HttpStatus.CREATED).body(batch);
+    }
+
+    // Cash and cheque deposits are keyed in by staff; customers' own money arrives through
+    // the payment webhook or a transfer.
+    @PostMapping("/transactions/deposit")
+    public ResponseEntity<Transaction> createDeposit(@RequestBody @ValidatedBody("deposit") DepositRequest request,
+                                                      @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                                      HttpServletRequest httpRequest) {
+        if (!"teller".equals(role) && !"admin".equals(role)) {
+            throw new ForbiddenException("teller or admin role required");
+        }
+        String authHeader = httpRequest.getHeader("Authorization");
+        Transaction txn = transactionService.createDeposit(request, authHeader);
         return ResponseEntity.status(HttpStatus.CREATED).body(txn);
     }
 
+    // Only the account's owner may withdraw from it.
+    @PostMapping("/transactions/withdrawal")
+    public ResponseEntity<Transaction> createWithdrawal(@RequestBody @ValidatedBody("withdrawal") WithdrawalRequest request,
+                                                         @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) String userId,
+                                                         HttpServletRequest httpRequest) {
+        String authHeader = httpRequest.getHeader("Authorization");
+        Transaction txn = transactionService.createWithdrawal(request, userId, authHeader);
+        return ResponseEntity.status(HttpStatus.CREATED).body(txn);
+    }
+
+    @GetMapping("/transactions/failed-count")
+    public Map<String, Object> countFailedTransactions(
+            @RequestParam(name = "account_id") List<UUID> accountIds,
+            @RequestParam @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime since) {
+        return Map.of("failed_count", transactionService.countFailedTransactions(accountIds, since));
+    }
+
     @GetMapping("/transactions/{id}")
     public Transaction getTransaction(@PathVariable UUID id) {
         return transactionService.getTransaction(id);
     }
 
+    // Only the sender or an admin may capture or void a held transfer.
+    @PostMapping("/transactions/{id}/capture")
+    public Transaction captureTransfer(@PathVariable UUID id,
+                                       @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) String userId,
+                                       @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                       HttpServletRequest httpRequest) {
+        return transactionService.captureTransfer(id, userId, role, httpRequest.getHeader("Authorization"));
+    }
+
+    @PostMapping("/transactions/{id}/void")
+    public Transaction voidAuthorization(@PathVariable UUID id,
+                                         @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) String userId,
+                                         @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                         HttpServletRequest httpRequest) {
+        return transactionService.voidAuthorization(id, userId, role, httpRequest.getHeader("Authorization"));
+    }
+
+    // Only the recipient or an admin may send a completed transfer back.
+    @PostMapping("/transactions/{id}/reverse")
+    public ResponseEntity<Transaction> reverseTransaction(@PathVariable UUID id,
+                                                          @RequestAttribute(name = AuthFilter.USER_ID_ATTRIBUTE, required = false) String userId,
+                                                          @RequestAttribute(name = AuthFilter.ROLE_ATTRIBUTE, required = false) String role,
+                                                          HttpServletRequest httpRequest) {
+        Transaction reversal = transactionService.reverseTransaction(id, userId, role,
+                httpRequest.getHeader("Authorization"));
+        return ResponseEntity.status(HttpStatus.CREATED).body(reversal);
+    }
+
+    @GetMapping("/transactions/{id}/history")
+    public Map<String, Object> getTransactionHistory(@PathVariable UUID id) {
+        List<TransactionStateEvent> events = transactionService.getTransactionHistory(id);
+
+        Map<String, Object> response = new LinkedHashMap<>();
+        response.put("transaction_id", id);
+        response.put("events", events);
+        return response;
+    }
+
     @GetMapping("/transactions")
     public Map<String, Object> listTransactions(
             @RequestParam(name = "account_id", required = false) UUID accountId,
             @RequestParam(required = false) String status,
+            @RequestParam(name = "created_after", required = false)
+            @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime createdAfter,
+            @RequestParam(name = "created_before", required = false)
+            @DateTimeFormat(iso = DateTimeFormat.ISO.DATE_TIME) OffsetDateTime createdBefore,
             @RequestParam(required = false, defaultValue = "20") int limit,
-            @RequestParam(required = false, defaultValue = "0") int offset) {
+            // Deprecated: pass back next_cursor instead, which stays fast on deep pages.
+            @RequestParam(required = false, defaultValue = "0") int offset,
+            @RequestParam(required = false) String cursor,
+            @RequestParam(name = "sort_by", required = false, defaultValue = TransactionFilter.DEFAULT_SORT_BY) String sortBy,
+            @RequestParam(name = "sort_order", required = false, defaultValue = TransactionFilter.DEFAULT_SORT_ORDER) String sortOrder,
+            @RequestParam(name = "metadata_key", required = false) String metadataKey,
+            @RequestParam(name = "metadata_value", required = false) String metadataValue) {
 
         if (limit < 1 || limit > 100) limit = 20;
         if (offset < 0) offset = 0;
+        if (!TransactionFilter.ALLOWED_SORT_COLUMNS.containsKey(sortBy)) {
+            throw new ValidationException("sort_by must be one of "
+                    + String.join(", ", TransactionFilter.ALLOWED_SORT_COLUMNS.keySet().stream().sorted().toList()));
+        }
+        if (!"asc".equals(sortOrder) && !"desc".equals(sortOrder)) {
+            throw new ValidationException("sort_order must be asc or desc");
+        }
+        if ((metadataKey == null) != (metadataValue == null)) {
+            throw new ValidationException("metadata_key and metadata_value must be given together");
+        }
 
         TransactionFilter filter = new TransactionFilter();
         filter.setAccountId(accountId);
         filter.setStatus(status);
+        filter.setCreatedAfter(createdAfter);
+        filter.setCreatedBefore(createdBefore);
         filter.setLimit(limit);
         filter.setOffset(offset);
+        filter.setSortBy(sortBy);
+        filter.setSortOrder(sortOrder);
+        filter.setMetadataKey(metadataKey);
+        filter.setMetadataValue(metadataValue);
+        if (cursor != null && !cursor.isEmpty()) {
+            filter.setCursor(Cursor.decode(cursor));
+        }
 
-        List<Transaction> transactions = transactionService.listTransactions(filter);
+        TransactionPage page = transactionService.listTransactions(filter);
 
         Map<String, Object> response = new LinkedHashMap<>();
-        response.put("transactions", transactions);
+        response.put("transactions", page.transactions());
         response.put("limit", filter.getLimit());
         response.put("offset", filter.getOffset());
+        response.put("sort_by", filter.getSortBy());
+        response.put("sort_order", filter.getSortOrder());
+        response.put("next_cursor", page.nextCursor());
         return response;
     }
 }
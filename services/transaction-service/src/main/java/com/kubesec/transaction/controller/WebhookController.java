@@ -0,0 +1,56 @@
+package com.kubesec.transaction.controller;
+
+import com.kubesec.transaction.exception.ForbiddenException;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.dto.PaymentConfirmedRequest;
+import com.kubesec.transaction.repository.TenantRepository;
+import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.validation.ValidatedBody;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.http.HttpStatus;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.PostMapping;
+import org.springframework.web.bind.annotation.RequestBody;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.util.Map;
+
+@RestController
+public class WebhookController {
+
+    private static final Logger log = LoggerFactory.getLogger(WebhookController.class);
+
+    private final TransactionService transactionService;
+    private final TenantRepository tenantRepository;
+
+    public WebhookController(TransactionService transactionService, TenantRepository tenantRepository) {
+        this.transactionService = transactionService;
+        this.tenantRepository = tenantRepository;
+    }
+
+    // Signature is verified by WebhookSignatureFilter before this runs
+    @PostMapping("/webhooks/payment-processor")
+    public ResponseEntity<Map<String, String>> paymentProcessor(
+            @RequestBody(required = false) Map<String, Object> payload) {
+        Object eventType = payload != null ? payload.get("type") : null;
+        log.info("received payment processor webhook: type={}", eventType);
+        return ResponseEntity.status(HttpStatus.ACCEPTED).body(Map.of("status", "accepted"));
+    }
+
+    // Webhooks bypass TenantFilter, so the tenant comes from the signed payload.
+    @PostMapping("/webhooks/payment-confirmed")
+    public Transaction paymentConfirmed(
+            @RequestBody @ValidatedBody("payment-confirmed") PaymentConfirmedRequest request) {
+        if (!tenantRepository.isActive(request.tenantId())) {
+            throw new ForbiddenException("unknown or inactive tenant");
+        }
+        TenantContext.set(request.tenantId());
+        try {
+            return transactionService.recordPaymentConfirmation(request);
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,21 @@
+package com.kubesec.transaction.exception;
+
+import org.springframework.http.HttpStatus;
+
+import java.math.BigDecimal;
+
+public class DailyDepositLimitExceededException extends DomainException {
+
+    private final BigDecimal limit;
+    private final BigDecimal current;
+
+    public DailyDepositLimitExceededException(BigDecimal limit, BigDecimal current) {
+        super("daily_deposit_limit_exceeded", HttpStatus.UNPROCESSABLE_ENTITY, "daily deposit limit exceeded");
+        this.limit = limit;
+        this.current = current;
+    }
+
+    public BigDecimal getLimit() { return limit; }
+
+    public BigDecimal getCurrent() { return current; }
+}
@@ -0,0 +1,21 @@
+package com.kubesec.transaction.exception;
+
+import org.springframework.http.HttpStatus;
+
+// Base type for domain errors. Each subclass carries a stable machine-readable code
+// and the HTTP status GlobalExceptionHandler should render it with.
+public abstract class DomainException extends RuntimeException {
+
+    private final String code;
+    private final HttpStatus status;
+
+    protected DomainException(String code, HttpStatus status, String message) {
+        super(message);
+        this.code = code;
+        this.status = status;
+    }
+
+    public String getCode() { return code; }
+
+    public HttpStatus getStatus() { return status; }
+}
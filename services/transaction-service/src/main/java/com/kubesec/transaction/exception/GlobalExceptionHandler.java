@@ -1,26 +1,40 @@
 package com.kubesec.transaction.exception;
 
+import com.kubesec.transaction.filter.RecoveryFilter;
+import io.micrometer.core.instrument.Metrics;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
 import org.springframework.http.HttpStatus;
 import org.springframework.http.ResponseEntity;
 import org.springframework.web.bind.annotation.ExceptionHandler;
 import org.springframework.web.bind.annotation.RestControllerAdvice;
 import org.springframework.web.method.annotation.MethodArgumentTypeMismatchException;
 
+import java.time.temporal.Temporal;
 import java.util.Map;
 
 @RestControllerAdvice
 public class GlobalExceptionHandler {
 
-    @ExceptionHandler(ResourceNotFoundException.class)
-    public ResponseEntity<Map<String, String>> handleNotFound(ResourceNotFoundException ex) {
-        return ResponseEntity.status(HttpStatus.NOT_FOUND)
-                .body(Map.of("error", ex.getMessage()));
+    private static final Logger log = LoggerFactory.getLogger(GlobalExceptionHandler.class);
+
+    @ExceptionHandler(DomainException.class)
+    public ResponseEntity<Map<String, String>> handleDomain(DomainException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode()));
     }
 
-    @ExceptionHandler(InsufficientBalanceException.class)
-    public ResponseEntity<Map<String, String>> handleInsufficientBalance(InsufficientBalanceException ex) {
-        return ResponseEntity.status(HttpStatus.UNPROCESSABLE_ENTITY)
-                .body(Map.of("error", ex.getMessage()));
+    @ExceptionHandler(SchemaViolationException.class)
+    public ResponseEntity<Map<String, Object>> handleSchemaViolation(SchemaViolationException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode(), "violations", ex.getViolations()));
+    }
+
+    @ExceptionHandler(DailyDepositLimitExceededException.class)
+    public ResponseEntity<Map<String, String>> handleDailyDepositLimit(DailyDepositLimitExceededException ex) {
+        return ResponseEntity.status(ex.getStatus())
+                .body(Map.of("error", ex.getMessage(), "code", ex.getCode(),
+                        "limit", ex.getLimit().toPlainString(), "current", ex.getCurrent().toPlainString()));
     }
 
     @ExceptionHandler(IllegalArgumentException.class)
@@ -31,16 +45,19 @@ public class GlobalExceptionHandler {
 
     @ExceptionHandler(MethodArgumentTypeMismatchException.class)
     public ResponseEntity<Map<String, String>> handleTypeMismatch(MethodArgumentTypeMismatchException ex) {
+        if (ex.getRequiredType() != null && Temporal.class.isAssignableFrom(ex.getRequiredType())) {
+            return ResponseEntity.status(HttpStatus.BAD_REQUEST)
+                    .body(Map.of("error", "invalid " + ex.getName() + ", expected an ISO 8601 timestamp"));
+        }
         return ResponseEntity.status(HttpStatus.BAD_REQUEST)
                 .body(Map.of("error", "invalid " + ex.getName()));
     }
 
-    @ExceptionHandler(RuntimeException.class)
-    public ResponseEntity<Map<String, String>> handleBadGateway(RuntimeException ex) {
-        if (ex.getMessage() != null && ex.getMessage().contains("could not verify")) {
-            return ResponseEntity.status(HttpStatus.BAD_GATEWAY)
-                    .body(Map.of("error", ex.getMessage()));
-        }
+    @ExceptionHandler(Exception.class)
+    public ResponseEntity<Map<String, String>> handleGeneral(Exception ex) {
+        // Counted with the exceptions RecoveryFilter catches outside the dispatcher.
+        Metrics.counter(RecoveryFilter.PANIC_METRIC).increment();
+        log.atError().setMessage("Unhandled exception").setCause(ex).log();
         return ResponseEntity.status(HttpStatus.INTERNAL_SERVER_ERROR)
                 .body(Map.of("error", "internal error"));
     }
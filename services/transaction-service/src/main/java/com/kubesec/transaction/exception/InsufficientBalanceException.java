@@ -1,8 +1,10 @@
 package com.kubesec.transaction.exception;
 
-public class InsufficientBalanceException extends RuntimeException {
+import org.springframework.http.HttpStatus;
+
+public class InsufficientBalanceException extends DomainException {
 
     public InsufficientBalanceException(String message) {
-        super(message);
+        super("insufficient_balance", HttpStatus.UNPROCESSABLE_ENTITY, message);
     }
 }
@@ -0,0 +1,10 @@
+package com.kubesec.transaction.exception;
+
+import org.springframework.http.HttpStatus;
+
+public class InvalidFilterFieldException extends DomainException {
+
+    public InvalidFilterFieldException(String field) {
+        super("invalid_filter_field", HttpStatus.BAD_REQUEST, "invalid filter field: " + field);
+    }
+}
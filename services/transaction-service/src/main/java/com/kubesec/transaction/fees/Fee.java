@@ -0,0 +1,22 @@
+package com.kubesec.transaction.fees;
+
+import java.math.BigDecimal;
+import java.math.RoundingMode;
+
+// A fee_schedules row resolved for one transaction.
+public record Fee(String feeType, BigDecimal feeValue) {
+
+    public static final String FLAT = "flat";
+    public static final String PERCENTAGE = "percentage";
+
+    private static final BigDecimal ONE_HUNDRED = BigDecimal.valueOf(100);
+
+    public BigDecimal apply(BigDecimal amount) {
+        BigDecimal fee = switch (feeType) {
+            case FLAT -> feeValue;
+            case PERCENTAGE -> amount.multiply(feeValue).divide(ONE_HUNDRED);
+            default -> throw new IllegalStateException("unknown fee type " + feeType);
+        };
+        return fee.setScale(2, RoundingMode.HALF_EVEN);
+    }
+}
@@ -0,0 +1,9 @@
+package com.kubesec.transaction.fees;
+
+import java.math.BigDecimal;
+
+// Computes the fee charged to the sender of a transfer, in the transfer's currency.
+public interface FeeCalculator {
+
+    BigDecimal calculate(BigDecimal amount, String currency);
+}
@@ -0,0 +1,21 @@
+package com.kubesec.transaction.fees;
+
+import java.math.BigDecimal;
+import java.math.RoundingMode;
+
+public class FlatFeeCalculator implements FeeCalculator {
+
+    private final BigDecimal rate;
+
+    public FlatFeeCalculator(BigDecimal rate) {
+        if (rate.signum() < 0) {
+            throw new IllegalArgumentException("fee rate must not be negative");
+        }
+        this.rate = rate;
+    }
+
+    @Override
+    public BigDecimal calculate(BigDecimal amount, String currency) {
+        return amount.multiply(rate).setScale(2, RoundingMode.HALF_EVEN);
+    }
+}
@@ -0,0 +1,36 @@
+package com.kubesec.transaction.fees;
+
+import com.kubesec.transaction.tenant.TenantHeaderInterceptor;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.math.RoundingMode;
+import java.util.Map;
+
+// Delegates to an external fee service: POST /fees {amount, currency} -> {fee}.
+public class HttpFeeCalculator implements FeeCalculator {
+
+    private final RestClient restClient;
+
+    public HttpFeeCalculator(String baseUrl) {
+        this.restClient = RestClient.builder()
+                .baseUrl(baseUrl)
+                .requestInterceptor(new TenantHeaderInterceptor())
+                .build();
+    }
+
+    @Override
+    public BigDecimal calculate(BigDecimal amount, String currency) {
+        FeeResponse response = restClient.post()
+                .uri("/fees")
+                .body(Map.of("amount", amount, "currency", currency))
+                .retrieve()
+                .body(FeeResponse.class);
+        if (response == null || response.fee() == null || response.fee().signum() < 0) {
+            throw new IllegalStateException("fee service returned an invalid fee");
+        }
+        return response.fee().setScale(2, RoundingMode.HALF_EVEN);
+    }
+
+    public record FeeResponse(BigDecimal fee) {}
+}
@@ -0,0 +1,49 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+
+@Component
+@Order(1)
+public class AdminApiKeyFilter extends OncePerRequestFilter {
+
+    private final AppConfig config;
+
+    public AdminApiKeyFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        return !request.getRequestURI().startsWith("/admin/");
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String expected = config.getAdminApiKey();
+        String provided = request.getHeader("X-Admin-Api-Key");
+
+        // An unset key disables the admin API entirely rather than leaving it open
+        if (expected == null || expected.isEmpty() || provided == null
+                || !MessageDigest.isEqual(expected.getBytes(StandardCharsets.UTF_8),
+                                          provided.getBytes(StandardCharsets.UTF_8))) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
+            response.getWriter().write("{\"error\":\"invalid admin api key\"}");
+            return;
+        }
+
+        chain.doFilter(request, response);
+    }
+}
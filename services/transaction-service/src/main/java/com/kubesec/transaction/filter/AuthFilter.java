@@ -1,14 +1,21 @@
 package com.kubesec.transaction.filter;
 
 import com.kubesec.transaction.client.AuthServiceClient;
+import com.kubesec.transaction.client.CircuitBreaker;
+import com.kubesec.transaction.client.CircuitOpenException;
+import com.kubesec.transaction.client.TokenValidationCache;
+import com.kubesec.transaction.config.AppConfig;
+import io.micrometer.core.instrument.MeterRegistry;
 import jakarta.servlet.FilterChain;
 import jakarta.servlet.ServletException;
 import jakarta.servlet.http.HttpServletRequest;
 import jakarta.servlet.http.HttpServletResponse;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
+import org.slf4j.MDC;
 import org.springframework.core.annotation.Order;
 import org.springframework.stereotype.Component;
+import org.springframework.web.client.HttpClientErrorException;
 import org.springframework.web.filter.OncePerRequestFilter;
 
 import java.io.IOException;
@@ -18,18 +25,28 @@ import java.io.IOException;
 public class AuthFilter extends OncePerRequestFilter {
 
     private static final Logger log = LoggerFactory.getLogger(AuthFilter.class);
+    public static final String USER_ID_ATTRIBUTE = "userId";
+    public static final String ROLE_ATTRIBUTE = "role";
 
     private final AuthServiceClient authServiceClient;
+    private final TokenValidationCache tokenCache;
+    // Shared by every request, so a failing auth-service is detected across them.
+    private final CircuitBreaker authCircuit;
 
-    public AuthFilter(AuthServiceClient authServiceClient) {
+    public AuthFilter(AuthServiceClient authServiceClient, TokenValidationCache tokenCache,
+                      AppConfig config, MeterRegistry meterRegistry) {
         this.authServiceClient = authServiceClient;
+        this.tokenCache = tokenCache;
+        this.authCircuit = new CircuitBreaker("auth", config.getAuthCircuitMaxRequests(),
+                config.getAuthCircuitInterval(), config.getAuthCircuitTimeout(),
+                config.getAuthCircuitFailureThreshold(), meterRegistry);
     }
 
     @Override
     protected boolean shouldNotFilter(HttpServletRequest request) {
         String path = request.getRequestURI();
-        // Only require auth for transaction endpoints
-        return !path.startsWith("/transactions");
+        // Only require auth for transaction and scheduled-transfer endpoints
+        return !path.startsWith("/transactions") && !path.startsWith("/api/");
     }
 
     @Override
@@ -45,15 +62,31 @@ public class AuthFilter extends OncePerRequestFilter {
 
         String token = authHeader.substring(7);
 
+        AuthServiceClient.ValidateResponse cached = tokenCache.get(token).orElse(null);
+        if (cached != null) {
+            authenticate(request, cached);
+            chain.doFilter(request, response);
+            return;
+        }
+
         try {
-            AuthServiceClient.ValidateResponse result = authServiceClient.validateToken(token);
+            AuthServiceClient.ValidateResponse result = authCircuit.call(
+                    () -> authServiceClient.validateToken(token),
+                    e -> !(e instanceof HttpClientErrorException));
             if (!result.valid()) {
                 response.setContentType("application/json");
                 response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
                 response.getWriter().write("{\"error\":\"token not valid\"}");
                 return;
             }
-            request.setAttribute("userId", result.user_id());
+            authenticate(request, result);
+            tokenCache.put(token, result);
+        } catch (CircuitOpenException e) {
+            // Answered at once rather than after the auth-service timeout.
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_SERVICE_UNAVAILABLE);
+            response.getWriter().write("{\"error\":\"auth service unavailable\"}");
+            return;
         } catch (Exception e) {
             log.error("Auth service error: {}", e.getMessage());
             response.setContentType("application/json");
@@ -64,4 +97,10 @@ public class AuthFilter extends OncePerRequestFilter {
 
         chain.doFilter(request, response);
     }
+
+    private static void authenticate(HttpServletRequest request, AuthServiceClient.ValidateResponse identity) {
+        request.setAttribute(USER_ID_ATTRIBUTE, identity.user_id());
+        request.setAttribute(ROLE_ATTRIBUTE, identity.role());
+        MDC.put(LogContextFilter.USER_ID, identity.user_id());
+    }
 }
@@ -0,0 +1,80 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.http.HttpHeaders;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.util.Set;
+import java.util.stream.Collectors;
+
+// Answers CORS preflight requests itself, ahead of AuthFilter and TenantFilter, since a
+// browser never sends credentials or the tenant header on a preflight. Actual requests
+// from an allowed origin carry Access-Control-Allow-Origin; others are passed through
+// without it and the browser withholds the response.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
+public class CorsFilter extends OncePerRequestFilter {
+
+    static final String ALLOWED_METHODS = "GET, POST, PUT, PATCH, DELETE, OPTIONS";
+    static final String ALLOWED_HEADERS =
+            "Authorization, Content-Type, Idempotency-Key, X-Tenant-ID, X-Request-ID, X-Correlation-ID";
+    static final String EXPOSED_HEADERS = "X-Request-ID, X-Correlation-ID, Idempotent-Replayed";
+    static final String MAX_AGE_SECONDS = "600";
+
+    private final Set<String> allowedOrigins;
+    private final boolean anyOrigin;
+
+    public CorsFilter(AppConfig config) {
+        this.allowedOrigins = config.getCorsAllowedOrigins().stream()
+                .map(String::strip)
+                .filter(o -> !o.isEmpty())
+                .collect(Collectors.toUnmodifiableSet());
+        this.anyOrigin = allowedOrigins.contains("*");
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String origin = request.getHeader(HttpHeaders.ORIGIN);
+        if (origin == null) {
+            chain.doFilter(request, response);
+            return;
+        }
+
+        boolean allowed = anyOrigin || allowedOrigins.contains(origin);
+        boolean preflight = "OPTIONS".equals(request.getMethod())
+                && request.getHeader(HttpHeaders.ACCESS_CONTROL_REQUEST_METHOD) != null;
+        if (!anyOrigin) {
+            response.addHeader(HttpHeaders.VARY, HttpHeaders.ORIGIN);
+        }
+
+        if (preflight) {
+            if (!allowed) {
+                response.setContentType("application/json");
+                response.setStatus(HttpServletResponse.SC_FORBIDDEN);
+                response.getWriter().write("{\"error\":\"origin not allowed\"}");
+                return;
+            }
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_ALLOW_ORIGIN, anyOrigin ? "*" : origin);
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_ALLOW_METHODS, ALLOWED_METHODS);
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_ALLOW_HEADERS, ALLOWED_HEADERS);
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_MAX_AGE, MAX_AGE_SECONDS);
+            response.setStatus(HttpServletResponse.SC_OK);
+            return;
+        }
+
+        if (allowed) {
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_ALLOW_ORIGIN, anyOrigin ? "*" : origin);
+            response.setHeader(HttpHeaders.ACCESS_CONTROL_EXPOSE_HEADERS, EXPOSED_HEADERS);
+        }
+        chain.doFilter(request, response);
+    }
+}
@@ -0,0 +1,123 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.ByteArrayOutputStream;
+import java.io.EOFException;
+import java.io.IOException;
+import java.io.InputStream;
+import java.util.Collections;
+import java.util.Enumeration;
+import java.util.List;
+import java.util.zip.GZIPInputStream;
+import java.util.zip.ZipException;
+
+// Inflates gzip-encoded bodies on the batch import route. Decompression is capped at
+// app.max-decompressed-body-bytes so a small compressed payload can't expand into
+// an arbitrarily large one.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
+public class DecompressRequestFilter extends OncePerRequestFilter {
+
+    static final String IMPORT_PATH = "/transactions/import";
+
+    private final AppConfig config;
+
+    public DecompressRequestFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        return !IMPORT_PATH.equals(request.getRequestURI())
+                || !"gzip".equalsIgnoreCase(request.getHeader("Content-Encoding"));
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        long limit = config.getMaxDecompressedBodyBytes();
+        byte[] body;
+        try (InputStream in = new GZIPInputStream(request.getInputStream())) {
+            body = readLimited(in, limit);
+        } catch (ZipException | EOFException e) {
+            reject(response, HttpServletResponse.SC_BAD_REQUEST, "malformed gzip body");
+            return;
+        }
+        if (body == null) {
+            reject(response, HttpServletResponse.SC_REQUEST_ENTITY_TOO_LARGE,
+                    "decompressed body exceeds " + limit + " bytes");
+            return;
+        }
+
+        chain.doFilter(new DecompressedRequest(request, body), response);
+    }
+
+    // Returns null as soon as more than limit bytes have been inflated.
+    private static byte[] readLimited(InputStream in, long limit) throws IOException {
+        ByteArrayOutputStream out = new ByteArrayOutputStream();
+        byte[] buf = new byte[8192];
+        long total = 0;
+        int n;
+        while ((n = in.read(buf)) != -1) {
+            total += n;
+            if (total > limit) {
+                return null;
+            }
+            out.write(buf, 0, n);
+        }
+        return out.toByteArray();
+    }
+
+    private static void reject(HttpServletResponse response, int status, String message) throws IOException {
+        response.setContentType("application/json");
+        response.setStatus(status);
+        response.getWriter().write("{\"error\":\"" + message + "\"}");
+    }
+
+    // The handler sees a plain body: Content-Encoding is dropped and the length matches.
+    private static class DecompressedRequest extends CachedBodyHttpServletRequest {
+
+        DecompressedRequest(HttpServletRequest request, byte[] body) {
+            super(request, body);
+        }
+
+        @Override
+        public String getHeader(String name) {
+            if ("Content-Encoding".equalsIgnoreCase(name)) {
+                return null;
+            }
+            if ("Content-Length".equalsIgnoreCase(name)) {
+                return String.valueOf(getBody().length);
+            }
+            return super.getHeader(name);
+        }
+
+        @Override
+        public Enumeration<String> getHeaders(String name) {
+            String value = getHeader(name);
+            if ("Content-Encoding".equalsIgnoreCase(name) || "Content-Length".equalsIgnoreCase(name)) {
+                return value == null ? Collections.emptyEnumeration() : Collections.enumeration(List.of(value));
+            }
+            return super.getHeaders(name);
+        }
+
+        @Override
+        public int getContentLength() {
+            return getBody().length;
+        }
+
+        @Override
+        public long getContentLengthLong() {
+            return getBody().length;
+        }
+    }
+}
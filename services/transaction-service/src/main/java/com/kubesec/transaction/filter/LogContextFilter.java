@@ -0,0 +1,73 @@
+package com.kubesec.transaction.filter;
+
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.MDC;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.util.UUID;
+import java.util.regex.Pattern;
+
+// Seeds the MDC so every log line written while handling a request carries its
+// request_id and correlation_id; auth filters add user_id once the caller is known.
+// Both ids are echoed in the response and forwarded on calls to the other services.
+// The request id names one hop, while the correlation id is kept for the whole user
+// journey and starts as the request id of its first request.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE)
+public class LogContextFilter extends OncePerRequestFilter {
+
+    public static final String HEADER = "X-Request-ID";
+    public static final String REQUEST_ID = "request_id";
+    public static final String CORRELATION_HEADER = "X-Correlation-ID";
+    public static final String CORRELATION_ID = "correlation_id";
+    public static final String USER_ID = "user_id";
+    // Written by Micrometer Tracing while a span is in scope.
+    public static final String TRACE_ID = "traceId";
+    // An id set by the gateway or a calling service is kept if it is safe to log and
+    // echo; anything else is replaced.
+    private static final Pattern VALID_ID = Pattern.compile("[A-Za-z0-9._:-]{1,128}");
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String requestId = request.getHeader(HEADER);
+        if (!isValid(requestId)) {
+            requestId = UUID.randomUUID().toString();
+        }
+        String correlationId = request.getHeader(CORRELATION_HEADER);
+        if (!isValid(correlationId)) {
+            correlationId = requestId;
+        }
+
+        MDC.put(REQUEST_ID, requestId);
+        MDC.put(CORRELATION_ID, correlationId);
+        response.setHeader(HEADER, requestId);
+        response.setHeader(CORRELATION_HEADER, correlationId);
+        try {
+            chain.doFilter(request, response);
+        } finally {
+            MDC.clear();
+        }
+    }
+
+    private static boolean isValid(String id) {
+        return id != null && VALID_ID.matcher(id).matches();
+    }
+
+    // The current request's id, or null outside a request.
+    public static String currentRequestId() {
+        return MDC.get(REQUEST_ID);
+    }
+
+    // The current request's correlation id, or null outside a request.
+    public static String currentCorrelationId() {
+        return MDC.get(CORRELATION_ID);
+    }
+}
@@ -0,0 +1,27 @@
+package com.kubesec.transaction.filter;
+
+import org.springframework.http.HttpRequest;
+import org.springframework.http.client.ClientHttpRequestExecution;
+import org.springframework.http.client.ClientHttpRequestInterceptor;
+import org.springframework.http.client.ClientHttpResponse;
+
+import java.io.IOException;
+
+// Forwards the current request and correlation ids so the downstream service logs
+// under the same ids.
+public class LogContextInterceptor implements ClientHttpRequestInterceptor {
+
+    @Override
+    public ClientHttpResponse intercept(HttpRequest request, byte[] body,
+                                        ClientHttpRequestExecution execution) throws IOException {
+        String requestId = LogContextFilter.currentRequestId();
+        if (requestId != null) {
+            request.getHeaders().set(LogContextFilter.HEADER, requestId);
+        }
+        String correlationId = LogContextFilter.currentCorrelationId();
+        if (correlationId != null) {
+            request.getHeaders().set(LogContextFilter.CORRELATION_HEADER, correlationId);
+        }
+        return execution.execute(request, body);
+    }
+}
@@ -0,0 +1,65 @@
+package com.kubesec.transaction.filter;
+
+import io.micrometer.core.instrument.Counter;
+import io.micrometer.core.instrument.MeterRegistry;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+
+// Outermost filter. An exception that escapes a filter or the dispatcher would otherwise
+// reach Tomcat, whose error page the load balancer may see as a dropped connection; here
+// it is logged with its stack trace and answered with a plain 500. Exceptions thrown by
+// controllers are handled by GlobalExceptionHandler before they get this far.
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE)
+public class RecoveryFilter extends OncePerRequestFilter {
+
+    private static final Logger log = LoggerFactory.getLogger(RecoveryFilter.class);
+    // Exported to Prometheus as panic_total.
+    public static final String PANIC_METRIC = "panic";
+
+    private final Counter panics;
+
+    public RecoveryFilter(MeterRegistry meterRegistry) {
+        this.panics = Counter.builder(PANIC_METRIC)
+                .description("Requests that failed with an unhandled exception")
+                .register(meterRegistry);
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        try {
+            chain.doFilter(request, response);
+        } catch (RuntimeException | ServletException e) {
+            panics.increment();
+            // LogContextFilter has cleared the MDC by now, but left the id on the response.
+            String requestId = response.getHeader(LogContextFilter.HEADER);
+            log.atError().setMessage("Unhandled exception")
+                    .addKeyValue("method", request.getMethod())
+                    .addKeyValue("path", request.getRequestURI())
+                    .addKeyValue(LogContextFilter.REQUEST_ID, requestId)
+                    .setCause(e)
+                    .log();
+            if (response.isCommitted()) {
+                return;
+            }
+            response.reset();
+            if (requestId != null) {
+                response.setHeader(LogContextFilter.HEADER, requestId);
+            }
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_INTERNAL_SERVER_ERROR);
+            response.getWriter().write("{\"error\":\"internal server error\"}");
+        }
+    }
+}
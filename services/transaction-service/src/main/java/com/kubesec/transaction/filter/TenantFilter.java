@@ -0,0 +1,67 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.repository.TenantRepository;
+import com.kubesec.transaction.tenant.TenantContext;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import java.io.IOException;
+import java.util.UUID;
+import java.util.regex.Pattern;
+
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 2)
+public class TenantFilter extends OncePerRequestFilter {
+
+    private final TenantRepository tenantRepository;
+
+    public TenantFilter(TenantRepository tenantRepository) {
+        this.tenantRepository = tenantRepository;
+    }
+
+    // Admin routes on a single transaction are tenant-scoped like the public ones; the
+    // other admin routes, such as replay, span every tenant.
+    private static final Pattern ADMIN_TRANSACTION = Pattern.compile("/admin/transactions/[0-9a-fA-F-]{36}");
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        String path = request.getRequestURI();
+        return !path.startsWith("/transactions") && !path.startsWith("/api/")
+                && !ADMIN_TRANSACTION.matcher(path).matches();
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String header = request.getHeader(TenantContext.HEADER);
+        UUID tenantId;
+        try {
+            tenantId = UUID.fromString(header);
+        } catch (IllegalArgumentException | NullPointerException e) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_BAD_REQUEST);
+            response.getWriter().write("{\"error\":\"missing or invalid X-Tenant-ID header\"}");
+            return;
+        }
+
+        if (!tenantRepository.isActive(tenantId)) {
+            response.setContentType("application/json");
+            response.setStatus(HttpServletResponse.SC_FORBIDDEN);
+            response.getWriter().write("{\"error\":\"unknown or inactive tenant\"}");
+            return;
+        }
+
+        TenantContext.set(tenantId);
+        try {
+            chain.doFilter(request, response);
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -0,0 +1,81 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.ServletException;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import org.springframework.web.filter.OncePerRequestFilter;
+
+import javax.crypto.Mac;
+import javax.crypto.spec.SecretKeySpec;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.security.GeneralSecurityException;
+import java.security.MessageDigest;
+import java.util.HexFormat;
+
+@Component
+@Order(1)
+public class WebhookSignatureFilter extends OncePerRequestFilter {
+
+    private static final String SIGNATURE_HEADER = "X-Webhook-Signature";
+
+    private final AppConfig config;
+
+    public WebhookSignatureFilter(AppConfig config) {
+        this.config = config;
+    }
+
+    @Override
+    protected boolean shouldNotFilter(HttpServletRequest request) {
+        return !request.getRequestURI().startsWith("/webhooks/");
+    }
+
+    @Override
+    protected void doFilterInternal(HttpServletRequest request, HttpServletResponse response,
+                                    FilterChain chain) throws ServletException, IOException {
+        String secret = config.getWebhookSecret();
+        String signature = request.getHeader(SIGNATURE_HEADER);
+        if (secret == null || secret.isEmpty() || signature == null || signature.isEmpty()) {
+            reject(response);
+            return;
+        }
+
+        CachedBodyHttpServletRequest cached = new CachedBodyHttpServletRequest(request);
+        byte[] expected = hmacSha256(secret, cached.getBody());
+        byte[] provided;
+        try {
+            provided = HexFormat.of().parseHex(signature.startsWith("sha256=") ? signature.substring(7) : signature);
+        } catch (IllegalArgumentException e) {
+            reject(response);
+            return;
+        }
+
+        // Constant-time comparison so the signature can't be guessed byte by byte
+        if (!MessageDigest.isEqual(expected, provided)) {
+            reject(response);
+            return;
+        }
+
+        chain.doFilter(cached, response);
+    }
+
+    private static byte[] hmacSha256(String secret, byte[] body) {
+        try {
+            Mac mac = Mac.getInstance("HmacSHA256");
+            mac.init(new SecretKeySpec(secret.getBytes(StandardCharsets.UTF_8), "HmacSHA256"));
+            return mac.doFinal(body);
+        } catch (GeneralSecurityException e) {
+            throw new IllegalStateException(e);
+        }
+    }
+
+    private static void reject(HttpServletResponse response) throws IOException {
+        response.setContentType("application/json");
+        response.setStatus(HttpServletResponse.SC_UNAUTHORIZED);
+        response.getWriter().write("{\"error\":\"invalid webhook signature\"}");
+    }
+}
@@ -0,0 +1,34 @@
+package com.kubesec.transaction.model;
+
+import com.kubesec.transaction.exception.ValidationException;
+
+import java.nio.charset.StandardCharsets;
+import java.time.OffsetDateTime;
+import java.time.format.DateTimeParseException;
+import java.util.Base64;
+import java.util.UUID;
+
+// Opaque keyset cursor over (created_at, id): the last row of the previous page.
+// Clients treat the encoded value as a token; its layout is free to change.
+public record Cursor(OffsetDateTime createdAt, UUID id) {
+
+    private static final char SEPARATOR = '|';
+
+    public String encode() {
+        String raw = createdAt.toString() + SEPARATOR + id;
+        return Base64.getUrlEncoder().withoutPadding().encodeToString(raw.getBytes(StandardCharsets.UTF_8));
+    }
+
+    public static Cursor decode(String encoded) {
+        try {
+            String raw = new String(Base64.getUrlDecoder().decode(encoded), StandardCharsets.UTF_8);
+            int sep = raw.indexOf(SEPARATOR);
+            if (sep <= 0 || sep == raw.length() - 1) {
+                throw new ValidationException("invalid cursor");
+            }
+            return new Cursor(OffsetDateTime.parse(raw.substring(0, sep)), UUID.fromString(raw.substring(sep + 1)));
+        } catch (IllegalArgumentException | DateTimeParseException e) {
+            throw new ValidationException("invalid cursor");
+        }
+    }
+}
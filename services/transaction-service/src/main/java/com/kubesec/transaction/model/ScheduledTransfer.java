@@ -0,0 +1,84 @@
+package com.kubesec.transaction.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public class ScheduledTransfer {
+
+    private UUID id;
+
+    @JsonProperty("tenant_id")
+    private UUID tenantId;
+
+    @JsonProperty("from_account_id")
+    private UUID fromAccountId;
+
+    @JsonProperty("to_account_id")
+    private UUID toAccountId;
+
+    private BigDecimal amount;
+    private String currency;
+    private String description;
+    private String frequency;
+
+    @JsonProperty("start_at")
+    private OffsetDateTime startAt;
+
+    @JsonProperty("next_run_at")
+    private OffsetDateTime nextRunAt;
+
+    @JsonProperty("last_run_at")
+    private OffsetDateTime lastRunAt;
+
+    private String status;
+
+    @JsonProperty("created_at")
+    private OffsetDateTime createdAt;
+
+    @JsonProperty("updated_at")
+    private OffsetDateTime updatedAt;
+
+    public UUID getId() { return id; }
+    public void setId(UUID id) { this.id = id; }
+
+    public UUID getTenantId() { return tenantId; }
+    public void setTenantId(UUID tenantId) { this.tenantId = tenantId; }
+
+    public UUID getFromAccountId() { return fromAccountId; }
+    public void setFromAccountId(UUID fromAccountId) { this.fromAccountId = fromAccountId; }
+
+    public UUID getToAccountId() { return toAccountId; }
+    public void setToAccountId(UUID toAccountId) { this.toAccountId = toAccountId; }
+
+    public BigDecimal getAmount() { return amount; }
+    public void setAmount(BigDecimal amount) { this.amount = amount; }
+
+    public String getCurrency() { return currency; }
+    public void setCurrency(String currency) { this.currency = currency; }
+
+    public String getDescription() { return description; }
+    public void setDescription(String description) { this.description = description; }
+
+    public String getFrequency() { return frequency; }
+    public void setFrequency(String frequency) { this.frequency = frequency; }
+
+    public OffsetDateTime getStartAt() { return startAt; }
+    public void setStartAt(OffsetDateTime startAt) { this.startAt = startAt; }
+
+    public OffsetDateTime getNextRunAt() { return nextRunAt; }
+    public void setNextRunAt(OffsetDateTime nextRunAt) { this.nextRunAt = nextRunAt; }
+
+    public OffsetDateTime getLastRunAt() { return lastRunAt; }
+    public void setLastRunAt(OffsetDateTime lastRunAt) { this.lastRunAt = lastRunAt; }
+
+    public String getStatus() { return status; }
+    public void setStatus(String status) { this.status = status; }
+
+    public OffsetDateTime getCreatedAt() { return createdAt; }
+    public void setCreatedAt(OffsetDateTime createdAt) { this.createdAt = createdAt; }
+
+    public OffsetDateTime getUpdatedAt() { return updatedAt; }
+    public void setUpdatedAt(OffsetDateTime updatedAt) { this.updatedAt = updatedAt; }
+}
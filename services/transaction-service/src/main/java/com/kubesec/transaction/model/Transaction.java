@@ -1,14 +1,19 @@
 package com.kubesec.transaction.model;
 
+import com.fasterxml.jackson.annotation.JsonInclude;
 import com.fasterxml.jackson.annotation.JsonProperty;
 import java.math.BigDecimal;
 import java.time.OffsetDateTime;
+import java.util.Map;
 import java.util.UUID;
 
 public class Transaction {
 
     private UUID id;
 
+    @JsonProperty("tenant_id")
+    private UUID tenantId;
+
     @JsonProperty("from_account_id")
     private UUID fromAccountId;
 
@@ -21,6 +26,53 @@ public class Transaction {
     private String status;
     private String description;
 
+    @JsonProperty("converted_amount")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private BigDecimal convertedAmount;
+
+    @JsonProperty("fx_rate")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private BigDecimal fxRate;
+
+    @JsonProperty("converted_currency")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private String convertedCurrency;
+
+    @JsonProperty("fee_amount")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private BigDecimal feeAmount;
+
+    @JsonProperty("fee_currency")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private String feeCurrency;
+
+    @JsonProperty("net_amount")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private BigDecimal netAmount;
+
+    @JsonProperty("authorized_at")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private OffsetDateTime authorizedAt;
+
+    @JsonProperty("authorized_hold_expires_at")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private OffsetDateTime authorizedHoldExpiresAt;
+
+    @JsonProperty("external_ref")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private String externalRef;
+
+    @JsonProperty("batch_id")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private UUID batchId;
+
+    @JsonProperty("deleted_at")
+    @JsonInclude(JsonInclude.Include.NON_NULL)
+    private OffsetDateTime deletedAt;
+
+    @JsonInclude(JsonInclude.Include.NON_EMPTY)
+    private Map<String, String> metadata = Map.of();
+
     @JsonProperty("created_at")
     private OffsetDateTime createdAt;
 
@@ -68,6 +120,45 @@ public class Transaction {
     public String getDescription() { return description; }
     public void setDescription(String description) { this.description = description; }
 
+    public BigDecimal getConvertedAmount() { return convertedAmount; }
+    public void setConvertedAmount(BigDecimal convertedAmount) { this.convertedAmount = convertedAmount; }
+
+    public BigDecimal getFxRate() { return fxRate; }
+    public void setFxRate(BigDecimal fxRate) { this.fxRate = fxRate; }
+
+    public String getConvertedCurrency() { return convertedCurrency; }
+    public void setConvertedCurrency(String convertedCurrency) { this.convertedCurrency = convertedCurrency; }
+
+    public BigDecimal getFeeAmount() { return feeAmount; }
+    public void setFeeAmount(BigDecimal feeAmount) { this.feeAmount = feeAmount; }
+
+    public String getFeeCurrency() { return feeCurrency; }
+    public void setFeeCurrency(String feeCurrency) { this.feeCurrency = feeCurrency; }
+
+    public BigDecimal getNetAmount() { return netAmount; }
+    public void setNetAmount(BigDecimal netAmount) { this.netAmount = netAmount; }
+
+    public OffsetDateTime getAuthorizedAt() { return authorizedAt; }
+    public void setAuthorizedAt(OffsetDateTime authorizedAt) { this.authorizedAt = authorizedAt; }
+
+    public OffsetDateTime getAuthorizedHoldExpiresAt() { return authorizedHoldExpiresAt; }
+    public void setAuthorizedHoldExpiresAt(OffsetDateTime authorizedHoldExpiresAt) { this.authorizedHoldExpiresAt = authorizedHoldExpiresAt; }
+
+    public String getExternalRef() { return externalRef; }
+    public void setExternalRef(String externalRef) { this.externalRef = externalRef; }
+
+    public UUID getBatchId() { return batchId; }
+    public void setBatchId(UUID batchId) { this.batchId = batchId; }
+
+    public OffsetDateTime getDeletedAt() { return deletedAt; }
+    public void setDeletedAt(OffsetDateTime deletedAt) { this.deletedAt = deletedAt; }
+
+    public Map<String, String> getMetadata() { return metadata; }
+    public void setMetadata(Map<String, String> metadata) { this.metadata = metadata != null ? metadata : Map.of(); }
+
+    public UUID getTenantId() { return tenantId; }
+    public void setTenantId(UUID tenantId) { this.tenantId = tenantId; }
+
     public OffsetDateTime getCreatedAt() { return createdAt; }
     public void setCreatedAt(OffsetDateTime createdAt) { this.createdAt = createdAt; }
 
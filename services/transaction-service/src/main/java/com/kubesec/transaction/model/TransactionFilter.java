@@ -1,13 +1,36 @@
 package com.kubesec.transaction.model;
 
+import java.time.OffsetDateTime;
+import java.util.Map;
 import java.util.UUID;
 
 public class TransactionFilter {
 
+    // API sort names mapped to the columns they order by. The column is spliced into
+    // ORDER BY, so only names listed here may reach the query.
+    public static final Map<String, String> ALLOWED_SORT_COLUMNS = Map.of(
+            "created_at", "created_at",
+            "updated_at", "updated_at",
+            "amount", "amount",
+            "status", "status");
+
+    public static final String DEFAULT_SORT_BY = "created_at";
+    public static final String DEFAULT_SORT_ORDER = "desc";
+
     private UUID accountId;
     private String status;
+    // Inclusive bounds on created_at; null leaves that side open.
+    private OffsetDateTime createdAfter;
+    private OffsetDateTime createdBefore;
     private int limit = 20;
+    // Deprecated in favour of cursor, which doesn't make Postgres skip the earlier rows.
     private int offset = 0;
+    private Cursor cursor; // takes precedence over offset; created_at sorts only
+    private String sortBy = DEFAULT_SORT_BY;
+    private String sortOrder = DEFAULT_SORT_ORDER;
+    private boolean includeDeleted; // soft-deleted rows are hidden unless set
+    private String metadataKey; // with metadataValue, matches rows whose metadata has that pair
+    private String metadataValue;
 
     public UUID getAccountId() { return accountId; }
     public void setAccountId(UUID accountId) { this.accountId = accountId; }
@@ -15,9 +38,41 @@ public class TransactionFilter {
     public String getStatus() { return status; }
     public void setStatus(String status) { this.status = status; }
 
+    public OffsetDateTime getCreatedAfter() { return createdAfter; }
+    public void setCreatedAfter(OffsetDateTime createdAfter) { this.createdAfter = createdAfter; }
+
+    public OffsetDateTime getCreatedBefore() { return createdBefore; }
+    public void setCreatedBefore(OffsetDateTime createdBefore) { this.createdBefore = createdBefore; }
+
+    public boolean hasDateRange() {
+        return createdAfter != null || createdBefore != null;
+    }
+
     public int getLimit() { return limit; }
     public void setLimit(int limit) { this.limit = limit; }
 
     public int getOffset() { return offset; }
     public void setOffset(int offset) { this.offset = offset; }
+
+    public Cursor getCursor() { return cursor; }
+    public void setCursor(Cursor cursor) { this.cursor = cursor; }
+
+    public String getSortBy() { return sortBy; }
+    public void setSortBy(String sortBy) { this.sortBy = sortBy; }
+
+    public String getSortOrder() { return sortOrder; }
+    public void setSortOrder(String sortOrder) { this.sortOrder = sortOrder; }
+
+    public boolean isIncludeDeleted() { return includeDeleted; }
+    public void setIncludeDeleted(boolean includeDeleted) { this.includeDeleted = includeDeleted; }
+
+    public String getMetadataKey() { return metadataKey; }
+    public void setMetadataKey(String metadataKey) { this.metadataKey = metadataKey; }
+
+    public String getMetadataValue() { return metadataValue; }
+    public void setMetadataValue(String metadataValue) { this.metadataValue = metadataValue; }
+
+    public boolean isDefaultSort() {
+        return DEFAULT_SORT_BY.equals(sortBy) && DEFAULT_SORT_ORDER.equals(sortOrder);
+    }
 }
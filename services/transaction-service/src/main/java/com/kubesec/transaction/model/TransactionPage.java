@@ -0,0 +1,6 @@
+package com.kubesec.transaction.model;
+
+import java.util.List;
+
+// nextCursor is null on the last page, and for sorts other than created_at.
+public record TransactionPage(List<Transaction> transactions, String nextCursor) {}
@@ -0,0 +1,17 @@
+package com.kubesec.transaction.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+// One status transition of a transaction. fromStatus is null for the event
+// recorded when the transaction is created.
+public record TransactionStateEvent(
+        UUID id,
+        @JsonProperty("transaction_id") UUID transactionId,
+        @JsonProperty("from_status") String fromStatus,
+        @JsonProperty("to_status") String toStatus,
+        String reason,
+        @JsonProperty("created_at") OffsetDateTime createdAt
+) {}
@@ -0,0 +1,7 @@
+package com.kubesec.transaction.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.util.List;
+import java.util.UUID;
+
+public record TransferBatch(@JsonProperty("batch_id") UUID batchId, List<Transaction> transactions) {}
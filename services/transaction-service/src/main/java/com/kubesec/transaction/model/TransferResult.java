@@ -0,0 +1,5 @@
+package com.kubesec.transaction.model;
+
+// replayed is true when the transfer was created by an earlier request with the
+// same Idempotency-Key and is being returned again.
+public record TransferResult(Transaction transaction, boolean replayed) {}
@@ -0,0 +1,19 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonIgnoreProperties;
+import com.fasterxml.jackson.annotation.JsonInclude;
+
+// Reply payload on accounts.balance.deduct. Failures carry the same code the HTTP
+// API would have returned.
+@JsonIgnoreProperties(ignoreUnknown = true)
+@JsonInclude(JsonInclude.Include.NON_NULL)
+public record BalanceDeductReply(boolean ok, String code, String error) {
+
+    public static BalanceDeductReply success() {
+        return new BalanceDeductReply(true, null, null);
+    }
+
+    public static BalanceDeductReply failure(String code, String error) {
+        return new BalanceDeductReply(false, code, error);
+    }
+}
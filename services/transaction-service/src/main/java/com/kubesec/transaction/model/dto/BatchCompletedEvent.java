@@ -0,0 +1,14 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.UUID;
+
+public record BatchCompletedEvent(
+        @JsonProperty("batch_id") UUID batchId,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("transaction_ids") List<UUID> transactionIds,
+        OffsetDateTime timestamp,
+        @JsonProperty("trace_id") String traceId
+) {}
@@ -0,0 +1,5 @@
+package com.kubesec.transaction.model.dto;
+
+import java.util.List;
+
+public record BatchTransferRequest(List<TransferRequest> transfers) {}
@@ -0,0 +1,15 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.util.UUID;
+
+public record CreateScheduledTransferRequest(
+        @JsonProperty("to_account_id") UUID toAccountId,
+        BigDecimal amount,
+        String currency,
+        String description,
+        String frequency,
+        @JsonProperty("start_at") OffsetDateTime startAt
+) {}
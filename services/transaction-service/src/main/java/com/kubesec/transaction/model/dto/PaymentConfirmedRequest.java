@@ -0,0 +1,16 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.UUID;
+
+// Payment processor's confirmation of an incoming payment. external_ref is the
+// processor's own payment id and is the same on every delivery of the event.
+public record PaymentConfirmedRequest(
+        @JsonProperty("external_ref") String externalRef,
+        @JsonProperty("tenant_id") UUID tenantId,
+        @JsonProperty("account_id") UUID accountId,
+        BigDecimal amount,
+        String currency,
+        String description
+) {}
@@ -7,11 +7,21 @@ import java.util.UUID;
 
 public record TransactionEvent(
         @JsonProperty("transaction_id") UUID transactionId,
+        @JsonProperty("tenant_id") UUID tenantId,
         @JsonProperty("from_account_id") UUID fromAccountId,
         @JsonProperty("to_account_id") UUID toAccountId,
         BigDecimal amount,
         String currency,
         String type,
         String status,
-        OffsetDateTime timestamp
+        @JsonProperty("converted_amount") BigDecimal convertedAmount,
+        @JsonProperty("fx_rate") BigDecimal fxRate,
+        @JsonProperty("converted_currency") String convertedCurrency,
+        @JsonProperty("fee_amount") BigDecimal feeAmount,
+        OffsetDateTime timestamp,
+        // The trace, request and correlation ids of the request that produced the event,
+        // for consumers to log against.
+        @JsonProperty("trace_id") String traceId,
+        @JsonProperty("request_id") String requestId,
+        @JsonProperty("correlation_id") String correlationId
 ) {}
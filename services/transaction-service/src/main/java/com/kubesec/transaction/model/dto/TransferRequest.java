@@ -2,6 +2,7 @@ package com.kubesec.transaction.model.dto;
 
 import com.fasterxml.jackson.annotation.JsonProperty;
 import java.math.BigDecimal;
+import java.util.Map;
 import java.util.UUID;
 
 public record TransferRequest(
@@ -9,5 +10,6 @@ public record TransferRequest(
         @JsonProperty("to_account_id") UUID toAccountId,
         BigDecimal amount,
         String currency,
-        String description
+        String description,
+        Map<String, String> metadata
 ) {}
@@ -0,0 +1,13 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+
+// All fields are optional; only the ones present are changed.
+public record UpdateScheduledTransferRequest(
+        BigDecimal amount,
+        String description,
+        String frequency,
+        @JsonProperty("next_run_at") OffsetDateTime nextRunAt
+) {}
@@ -0,0 +1,12 @@
+package com.kubesec.transaction.model.dto;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.math.BigDecimal;
+import java.util.UUID;
+
+public record WithdrawalRequest(
+        @JsonProperty("account_id") UUID accountId,
+        BigDecimal amount,
+        String currency,
+        String description
+) {}
@@ -0,0 +1,28 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.exception.InvalidFilterFieldException;
+
+import java.util.List;
+import java.util.Set;
+
+// Column names can't be bound as parameters, so every column spliced into a
+// dynamic WHERE clause must come from this allowlist.
+final class FilterColumns {
+
+    private static final Set<String> ALLOWED = Set.of("from_account_id", "to_account_id", "status");
+
+    private FilterColumns() {
+    }
+
+    static String require(String column) {
+        if (column == null || !ALLOWED.contains(column)) {
+            throw new InvalidFilterFieldException(String.valueOf(column));
+        }
+        return column;
+    }
+
+    static void appendEquals(StringBuilder query, List<Object> args, String column, Object value) {
+        query.append(" AND ").append(require(column)).append(" = ?");
+        args.add(value);
+    }
+}
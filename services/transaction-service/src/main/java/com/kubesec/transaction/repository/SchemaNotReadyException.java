@@ -0,0 +1,8 @@
+package com.kubesec.transaction.repository;
+
+public class SchemaNotReadyException extends RuntimeException {
+
+    public SchemaNotReadyException(String table, Throwable cause) {
+        super("table " + table + " is not accessible yet", cause);
+    }
+}
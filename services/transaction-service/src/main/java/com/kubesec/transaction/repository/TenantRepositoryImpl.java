@@ -0,0 +1,25 @@
+package com.kubesec.transaction.repository;
+
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.stereotype.Repository;
+
+import java.util.UUID;
+
+@Repository
+public class TenantRepositoryImpl implements TenantRepository {
+
+    private final JdbcTemplate jdbc;
+
+    public TenantRepositoryImpl(JdbcTemplate jdbc) {
+        this.jdbc = jdbc;
+    }
+
+    @Override
+    public boolean isActive(UUID tenantId) {
+        Integer count = jdbc.queryForObject(
+                "SELECT COUNT(*) FROM tenants WHERE id = ? AND active = TRUE",
+                Integer.class, tenantId
+        );
+        return count != null && count > 0;
+    }
+}
@@ -1,9 +1,17 @@
 package com.kubesec.transaction.repository;
 
+import com.kubesec.transaction.fees.Fee;
+import com.kubesec.transaction.model.Cursor;
+import com.kubesec.transaction.model.ScheduledTransfer;
 import com.kubesec.transaction.model.Transaction;
 import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.TransactionStateEvent;
 
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
 import java.util.List;
+import java.util.Map;
 import java.util.Optional;
 import java.util.UUID;
 
@@ -11,9 +19,81 @@ public interface TransactionRepository {
 
     void create(Transaction transaction);
 
+    // Inserts all of the transactions in one DB transaction, or none of them.
+    void createBatch(List<Transaction> transactions);
+
+    // Inserts the authorized transfers in one DB transaction if each sender's balance,
+    // less its active holds, covers what they add. Otherwise nothing is written and the
+    // first sender found short is returned.
+    Optional<UUID> createHolds(List<Transaction> transactions, Map<UUID, BigDecimal> balances, OffsetDateTime now);
+
+    // Inserts the transaction unless one with the same external_ref exists, in which case
+    // that row is returned unchanged. Empty if the reference belongs to another tenant.
+    Optional<Transaction> upsertByExternalRef(Transaction transaction);
+
     Optional<Transaction> getById(UUID id);
 
+    // Claims the key for a new request. False if another request holds a claim made
+    // after expiredBefore, whether or not its transaction exists yet.
+    boolean claimIdempotencyKey(String key, OffsetDateTime now, OffsetDateTime expiredBefore);
+
+    void setIdempotencyKey(String key, UUID transactionId);
+
+    // Frees a claim whose request failed before creating a transaction.
+    void releaseIdempotencyKey(String key);
+
+    Optional<Transaction> getByIdempotencyKey(String key);
+
     List<Transaction> list(TransactionFilter filter);
 
-    void updateStatus(UUID id, String status);
+    List<Transaction> getByAccountIdAndStatus(UUID accountId, String status, int limit, int offset);
+
+    // Newest first, ties broken by id descending, at most limit rows.
+    List<Transaction> getRecentByAccount(UUID accountId, int limit);
+
+    void updateStatus(UUID id, String status, String reason);
+
+    boolean transitionStatus(UUID id, String expectedStatus, String status, String reason);
+
+    // Marks the completed original reversed and inserts the reversal in one DB
+    // transaction. False, with nothing written, if the original is no longer completed.
+    boolean reverse(UUID originalId, Transaction reversal);
+
+    BigDecimal sumActiveHolds(UUID fromAccountId, OffsetDateTime now);
+
+    List<Transaction> listExpiredAuthorizations(OffsetDateTime now, int limit);
+
+    void appendTransactionEvent(UUID transactionId, String fromStatus, String toStatus, String reason);
+
+    List<TransactionStateEvent> getTransactionEventHistory(UUID transactionId);
+
+    boolean deleteById(UUID id);
+
+    boolean softDelete(UUID id);
+
+    int countByAccountsAndStatusSince(List<UUID> accountIds, String status, OffsetDateTime since);
+
+    List<Transaction> listCompletedByDateRange(OffsetDateTime from, OffsetDateTime to, Cursor after, int limit);
+
+    void lockDeposits(UUID accountId);
+
+    BigDecimal getDailyDepositTotal(UUID accountId, LocalDate date);
+
+    Optional<Fee> getApplicableFee(String accountTier, String transactionType, BigDecimal amount, OffsetDateTime at);
+
+    void createScheduledTransfer(ScheduledTransfer transfer);
+
+    Optional<ScheduledTransfer> getScheduledTransfer(UUID id);
+
+    List<ScheduledTransfer> listScheduledTransfers(UUID fromAccountId);
+
+    void updateScheduledTransfer(ScheduledTransfer transfer);
+
+    boolean cancelScheduledTransfer(UUID id);
+
+    List<UUID> listDueScheduledTransferIds(OffsetDateTime now, int limit);
+
+    Optional<ScheduledTransfer> lockDueScheduledTransfer(UUID id, OffsetDateTime now);
+
+    void recordScheduledTransferRun(UUID scheduledTransferId, UUID transactionId, String status, String error);
 }
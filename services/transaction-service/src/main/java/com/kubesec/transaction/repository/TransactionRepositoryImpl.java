@@ -1,77 +1,266 @@
 package com.kubesec.transaction.repository;
 
+import com.fasterxml.jackson.core.JsonProcessingException;
+import com.fasterxml.jackson.core.type.TypeReference;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.exception.InvalidFilterFieldException;
+import com.kubesec.transaction.fees.Fee;
+import com.kubesec.transaction.model.Cursor;
+import com.kubesec.transaction.model.ScheduledTransfer;
 import com.kubesec.transaction.model.Transaction;
 import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.TransactionStateEvent;
+import com.kubesec.transaction.tenant.TenantContext;
 import org.springframework.dao.EmptyResultDataAccessException;
 import org.springframework.jdbc.core.JdbcTemplate;
 import org.springframework.stereotype.Repository;
 import org.springframework.transaction.annotation.Transactional;
 
+import java.math.BigDecimal;
 import java.sql.ResultSet;
 import java.sql.SQLException;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
 import java.util.ArrayList;
+import java.util.Collections;
 import java.util.List;
+import java.util.Map;
 import java.util.Optional;
+import java.util.TreeMap;
 import java.util.UUID;
 
 @Repository
 public class TransactionRepositoryImpl implements TransactionRepository {
 
+    private static final String COLUMNS =
+            "id, tenant_id, from_account_id, to_account_id, amount, currency, type, status, description, "
+                    + "converted_amount, fx_rate, converted_currency, fee_amount, fee_currency, net_amount, "
+                    + "authorized_at, authorized_hold_expires_at, external_ref, batch_id, created_at, updated_at, metadata";
+
+    // metadata is bound as JSON text and cast to the column's jsonb type.
+    private static final String INSERT_VALUES =
+            "VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CAST(? AS JSONB))";
+
+    // deleted_at is only ever set by softDelete, so it is read but never inserted.
+    private static final String SELECT_COLUMNS = COLUMNS + ", deleted_at";
+
+    private static final String SCHEDULED_COLUMNS =
+            "id, tenant_id, from_account_id, to_account_id, amount, currency, description, frequency, "
+                    + "start_at, next_run_at, last_run_at, status, created_at, updated_at";
+
+    private static final ObjectMapper JSON = new ObjectMapper();
+    private static final TypeReference<Map<String, String>> METADATA_TYPE = new TypeReference<>() {};
+
     private final JdbcTemplate jdbc;
 
-    public TransactionRepositoryImpl(JdbcTemplate jdbc) {
+    public TransactionRepositoryImpl(JdbcTemplate jdbc, SchemaReadiness schemaReadiness) {
         this.jdbc = jdbc;
+        schemaReadiness.await("transactions");
     }
 
     @Override
     @Transactional
     public void create(Transaction txn) {
         jdbc.update(
-                "INSERT INTO transactions (id, from_account_id, to_account_id, amount, currency, type, status, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-                txn.getId(), txn.getFromAccountId(), txn.getToAccountId(),
-                txn.getAmount(), txn.getCurrency(), txn.getType(), txn.getStatus(),
-                txn.getDescription(), txn.getCreatedAt(), txn.getUpdatedAt()
+                "INSERT INTO transactions (" + COLUMNS + ") " + INSERT_VALUES,
+                insertArgs(txn)
         );
+        appendTransactionEvent(txn.getId(), null, txn.getStatus(), "created");
+    }
+
+    @Override
+    @Transactional
+    public void createBatch(List<Transaction> transactions) {
+        for (Transaction txn : transactions) {
+            create(txn);
+        }
+    }
+
+    // Each sender's holds are locked until the surrounding transaction ends, so concurrent
+    // authorizations against one account are checked one at a time. Senders are locked in
+    // id order so two batches sharing senders cannot deadlock.
+    @Override
+    @Transactional
+    public Optional<UUID> createHolds(List<Transaction> transactions, Map<UUID, BigDecimal> balances,
+                                      OffsetDateTime now) {
+        Map<UUID, BigDecimal> debits = new TreeMap<>();
+        for (Transaction txn : transactions) {
+            debits.merge(txn.getFromAccountId(), txn.getNetAmount(), BigDecimal::add);
+        }
+        for (Map.Entry<UUID, BigDecimal> debit : debits.entrySet()) {
+            jdbc.queryForList("SELECT pg_advisory_xact_lock(hashtext(?))", "holds:" + debit.getKey());
+            BigDecimal available = balances.get(debit.getKey()).subtract(sumActiveHolds(debit.getKey(), now));
+            if (available.compareTo(debit.getValue()) < 0) {
+                return Optional.of(debit.getKey());
+            }
+        }
+        createBatch(transactions);
+        return Optional.empty();
+    }
+
+    // The conflict branch is a no-op update so RETURNING yields the stored row. The
+    // tenant condition keeps another tenant's row out of reach; the result is then empty.
+    @Override
+    @Transactional
+    public Optional<Transaction> upsertByExternalRef(Transaction txn) {
+        List<Transaction> rows = jdbc.query(
+                "INSERT INTO transactions (" + COLUMNS + ") " + INSERT_VALUES + " "
+                        + "ON CONFLICT (external_ref) DO UPDATE SET external_ref = EXCLUDED.external_ref "
+                        + "WHERE transactions.tenant_id = EXCLUDED.tenant_id "
+                        + "RETURNING " + SELECT_COLUMNS,
+                this::mapTransaction, insertArgs(txn)
+        );
+        if (rows.isEmpty()) {
+            return Optional.empty();
+        }
+        Transaction stored = rows.get(0);
+        if (stored.getId().equals(txn.getId())) {
+            appendTransactionEvent(stored.getId(), null, stored.getStatus(), "created");
+        }
+        return Optional.of(stored);
+    }
+
+    private static Object[] insertArgs(Transaction txn) {
+        return new Object[]{
+                txn.getId(), txn.getTenantId(), txn.getFromAccountId(), txn.getToAccountId(),
+                txn.getAmount(), txn.getCurrency(), txn.getType(), txn.getStatus(),
+                txn.getDescription(), txn.getConvertedAmount(), txn.getFxRate(),
+                txn.getConvertedCurrency(), txn.getFeeAmount(), txn.getFeeCurrency(), txn.getNetAmount(),
+                txn.getAuthorizedAt(), txn.getAuthorizedHoldExpiresAt(), txn.getExternalRef(), txn.getBatchId(),
+                txn.getCreatedAt(), txn.getUpdatedAt(), writeMetadata(txn.getMetadata())
+        };
+    }
+
+    private static String writeMetadata(Map<String, String> metadata) {
+        try {
+            return JSON.writeValueAsString(metadata);
+        } catch (JsonProcessingException e) {
+            throw new IllegalArgumentException("metadata is not serializable", e);
+        }
+    }
+
+    // Rows written before the column existed, or by hand, may hold NULL.
+    private static Map<String, String> readMetadata(String json) throws SQLException {
+        if (json == null || json.isBlank()) {
+            return Map.of();
+        }
+        try {
+            return JSON.readValue(json, METADATA_TYPE);
+        } catch (JsonProcessingException e) {
+            throw new SQLException("unreadable transaction metadata", e);
+        }
     }
 
     @Override
     public Optional<Transaction> getById(UUID id) {
         try {
             return Optional.ofNullable(jdbc.queryForObject(
-                    "SELECT id, from_account_id, to_account_id, amount, currency, type, status, description, created_at, updated_at FROM transactions WHERE id = ?",
-                    this::mapTransaction, id
+                    "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL",
+                    this::mapTransaction, id, TenantContext.require()
             ));
         } catch (EmptyResultDataAccessException e) {
             return Optional.empty();
         }
     }
 
+    // An expired claim is taken over in place, so the key's row never has to be
+    // cleaned up for the key to be reused.
+    @Override
+    public boolean claimIdempotencyKey(String key, OffsetDateTime now, OffsetDateTime expiredBefore) {
+        return !jdbc.queryForList(
+                "INSERT INTO idempotency_keys (tenant_id, idempotency_key, created_at) VALUES (?, ?, ?) "
+                        + "ON CONFLICT (tenant_id, idempotency_key) DO UPDATE "
+                        + "SET transaction_id = NULL, created_at = EXCLUDED.created_at "
+                        + "WHERE idempotency_keys.created_at < ? "
+                        + "RETURNING idempotency_key",
+                String.class, TenantContext.require(), key, now, expiredBefore
+        ).isEmpty();
+    }
+
+    @Override
+    public void setIdempotencyKey(String key, UUID transactionId) {
+        jdbc.update(
+                "UPDATE idempotency_keys SET transaction_id = ? WHERE tenant_id = ? AND idempotency_key = ?",
+                transactionId, TenantContext.require(), key
+        );
+    }
+
+    @Override
+    public void releaseIdempotencyKey(String key) {
+        jdbc.update(
+                "DELETE FROM idempotency_keys WHERE tenant_id = ? AND idempotency_key = ? AND transaction_id IS NULL",
+                TenantContext.require(), key
+        );
+    }
+
+    @Override
+    public Optional<Transaction> getByIdempotencyKey(String key) {
+        UUID tenantId = TenantContext.require();
+        return jdbc.query(
+                "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ? AND id = "
+                        + "(SELECT transaction_id FROM idempotency_keys WHERE tenant_id = ? AND idempotency_key = ?)",
+                this::mapTransaction, tenantId, tenantId, key
+        ).stream().findFirst();
+    }
+
     @Override
     public List<Transaction> list(TransactionFilter filter) {
         StringBuilder query = new StringBuilder(
-                "SELECT id, from_account_id, to_account_id, amount, currency, type, status, description, created_at, updated_at FROM transactions WHERE 1=1"
+                "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ?"
         );
         List<Object> args = new ArrayList<>();
+        args.add(TenantContext.require());
+
+        if (!filter.isIncludeDeleted()) {
+            query.append(" AND deleted_at IS NULL");
+        }
 
         if (filter.getAccountId() != null) {
-            query.append(" AND (from_account_id = ? OR to_account_id = ?)");
+            query.append(" AND (").append(FilterColumns.require("from_account_id")).append(" = ? OR ")
+                    .append(FilterColumns.require("to_account_id")).append(" = ?)");
             args.add(filter.getAccountId());
             args.add(filter.getAccountId());
         }
 
         if (filter.getStatus() != null && !filter.getStatus().isEmpty()) {
-            query.append(" AND status = ?");
-            args.add(filter.getStatus());
+            FilterColumns.appendEquals(query, args, "status", filter.getStatus());
         }
 
-        query.append(" ORDER BY created_at DESC");
+        // Served by the GIN index on metadata.
+        if (filter.getMetadataKey() != null) {
+            query.append(" AND metadata @> CAST(? AS JSONB)");
+            args.add(writeMetadata(Map.of(filter.getMetadataKey(), filter.getMetadataValue())));
+        }
+
+        // With an account filter these ranges are served by the (account, created_at) indexes
+        if (filter.getCreatedAfter() != null) {
+            query.append(" AND created_at >= ?");
+            args.add(filter.getCreatedAfter());
+        }
+        if (filter.getCreatedBefore() != null) {
+            query.append(" AND created_at <= ?");
+            args.add(filter.getCreatedBefore());
+        }
+
+        // Keyset pagination: rows strictly after the cursor in the requested order
+        String direction = "asc".equals(filter.getSortOrder()) ? "ASC" : "DESC";
+        if (filter.getCursor() != null) {
+            query.append(" AND (created_at, id) ").append("ASC".equals(direction) ? '>' : '<').append(" (?, ?)");
+            args.add(filter.getCursor().createdAt());
+            args.add(filter.getCursor().id());
+        }
+
+        // id breaks ties so pages stay stable when many rows share a sort value
+        query.append(" ORDER BY ").append(sortColumn(filter.getSortBy())).append(' ').append(direction)
+                .append(", id ").append(direction);
 
         if (filter.getLimit() > 0) {
             query.append(" LIMIT ?");
             args.add(filter.getLimit());
         }
 
-        if (filter.getOffset() > 0) {
+        if (filter.getCursor() == null && filter.getOffset() > 0) {
             query.append(" OFFSET ?");
             args.add(filter.getOffset());
         }
@@ -79,19 +268,319 @@ public class TransactionRepositoryImpl implements TransactionRepository {
         return jdbc.query(query.toString(), this::mapTransaction, args.toArray());
     }
 
+    // Planner hint: the OR in list() forces a bitmap scan across both account indexes.
+    // Splitting it into a UNION ALL lets each branch use its own (account, status) index.
+    // Each branch returns at most offset + limit rows, so only the requested page is
+    // merged and sent back rather than every pending row of a busy account.
     @Override
-    public void updateStatus(UUID id, String status) {
+    public List<Transaction> getByAccountIdAndStatus(UUID accountId, String status, int limit, int offset) {
+        UUID tenantId = TenantContext.require();
+        int branchLimit = offset + limit;
+        return jdbc.query(
+                "(SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ? AND from_account_id = ? AND status = ?"
+                        + " AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT ?)"
+                        + " UNION ALL"
+                        + " (SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ? AND to_account_id = ? AND status = ?"
+                        + " AND from_account_id IS DISTINCT FROM ? AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT ?)"
+                        + " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?",
+                this::mapTransaction, tenantId, accountId, status, branchLimit,
+                tenantId, accountId, status, accountId, branchLimit, limit, offset
+        );
+    }
+
+    // Same split as above for the unfiltered account history. Each branch walks its
+    // (account, created_at DESC) index and stops after limit rows, so the merge only
+    // ever sorts 2 * limit rows however long the account's history is.
+    @Override
+    public List<Transaction> getRecentByAccount(UUID accountId, int limit) {
+        UUID tenantId = TenantContext.require();
+        return jdbc.query(
+                "(SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ? AND from_account_id = ?"
+                        + " AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT ?)"
+                        + " UNION ALL"
+                        + " (SELECT " + SELECT_COLUMNS + " FROM transactions WHERE tenant_id = ? AND to_account_id = ?"
+                        + " AND from_account_id IS DISTINCT FROM ? AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT ?)"
+                        + " ORDER BY created_at DESC, id DESC LIMIT ?",
+                this::mapTransaction, tenantId, accountId, limit, tenantId, accountId, accountId, limit, limit
+        );
+    }
+
+    // The status column is a projection of the latest event; both are written
+    // under the row lock so concurrent transitions can't interleave.
+    @Override
+    @Transactional
+    public void updateStatus(UUID id, String status, String reason) {
+        List<String> current = jdbc.queryForList(
+                "SELECT status FROM transactions WHERE id = ? AND tenant_id = ? FOR UPDATE",
+                String.class, id, TenantContext.require()
+        );
+        if (current.isEmpty()) {
+            throw new IllegalStateException("transaction " + id + " not found");
+        }
+        jdbc.update(
+                "UPDATE transactions SET status = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                status, id, TenantContext.require()
+        );
+        appendTransactionEvent(id, current.get(0), status, reason);
+    }
+
+    // Moves the transaction only if it is still in the expected status. Capture and
+    // void both go through here, so whichever takes the row lock first wins.
+    @Override
+    @Transactional
+    public boolean transitionStatus(UUID id, String expectedStatus, String status, String reason) {
         int rows = jdbc.update(
-                "UPDATE transactions SET status = ?, updated_at = NOW() WHERE id = ?",
-                status, id
+                "UPDATE transactions SET status = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ? AND status = ?",
+                status, id, TenantContext.require(), expectedStatus
         );
         if (rows == 0) {
-            throw new IllegalStateException("transaction " + id + " not found");
+            return false;
+        }
+        appendTransactionEvent(id, expectedStatus, status, reason);
+        return true;
+    }
+
+    @Override
+    @Transactional
+    public boolean reverse(UUID originalId, Transaction reversal) {
+        if (!transitionStatus(originalId, "completed", "reversed", "reversed by " + reversal.getId())) {
+            return false;
+        }
+        create(reversal);
+        return true;
+    }
+
+    @Override
+    public BigDecimal sumActiveHolds(UUID fromAccountId, OffsetDateTime now) {
+        BigDecimal held = jdbc.queryForObject(
+                "SELECT COALESCE(SUM(COALESCE(net_amount, amount)), 0) FROM transactions"
+                        + " WHERE tenant_id = ? AND from_account_id = ? AND status = 'authorized' AND authorized_hold_expires_at > ?",
+                BigDecimal.class, TenantContext.require(), fromAccountId, now
+        );
+        return held != null ? held : BigDecimal.ZERO;
+    }
+
+    // The expiry worker runs outside any request, so holds are looked up across tenants.
+    @Override
+    public List<Transaction> listExpiredAuthorizations(OffsetDateTime now, int limit) {
+        return jdbc.query(
+                "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE status = 'authorized' AND authorized_hold_expires_at <= ?"
+                        + " ORDER BY authorized_hold_expires_at LIMIT ?",
+                this::mapTransaction, now, limit
+        );
+    }
+
+    @Override
+    public void appendTransactionEvent(UUID transactionId, String fromStatus, String toStatus, String reason) {
+        jdbc.update(
+                "INSERT INTO transaction_events (id, transaction_id, from_status, to_status, reason, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+                UUID.randomUUID(), transactionId, fromStatus, toStatus, reason != null ? reason : "",
+                OffsetDateTime.now(ZoneOffset.UTC)
+        );
+    }
+
+    @Override
+    public List<TransactionStateEvent> getTransactionEventHistory(UUID transactionId) {
+        return jdbc.query(
+                "SELECT e.id, e.transaction_id, e.from_status, e.to_status, e.reason, e.created_at"
+                        + " FROM transaction_events e JOIN transactions t ON t.id = e.transaction_id"
+                        + " WHERE e.transaction_id = ? AND t.tenant_id = ? ORDER BY e.seq",
+                (rs, rowNum) -> new TransactionStateEvent(
+                        rs.getObject("id", UUID.class),
+                        rs.getObject("transaction_id", UUID.class),
+                        rs.getString("from_status"),
+                        rs.getString("to_status"),
+                        rs.getString("reason"),
+                        rs.getObject("created_at", OffsetDateTime.class)
+                ),
+                transactionId, TenantContext.require()
+        );
+    }
+
+    // Only reachable through the test-data cleanup endpoint. Scheduled-transfer run
+    // history keeps its row but loses the link to the deleted transaction.
+    @Override
+    @Transactional
+    public boolean deleteById(UUID id) {
+        UUID tenantId = TenantContext.require();
+        jdbc.update(
+                "UPDATE scheduled_transfer_runs SET transaction_id = NULL WHERE transaction_id = ?"
+                        + " AND transaction_id IN (SELECT id FROM transactions WHERE tenant_id = ?)",
+                id, tenantId
+        );
+        int rows = jdbc.update("DELETE FROM transactions WHERE id = ? AND tenant_id = ?", id, tenantId);
+        return rows > 0;
+    }
+
+    // Hides the row from getById and listings while leaving it, and its event history,
+    // in place. A row already deleted counts as not found.
+    @Override
+    public boolean softDelete(UUID id) {
+        int rows = jdbc.update(
+                "UPDATE transactions SET deleted_at = NOW() WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL",
+                id, TenantContext.require()
+        );
+        return rows > 0;
+    }
+
+    @Override
+    public int countByAccountsAndStatusSince(List<UUID> accountIds, String status, OffsetDateTime since) {
+        if (accountIds.isEmpty()) {
+            return 0;
+        }
+        String placeholders = String.join(", ", Collections.nCopies(accountIds.size(), "?"));
+        List<Object> args = new ArrayList<>();
+        args.add(TenantContext.require());
+        args.add(status);
+        args.add(since);
+        args.addAll(accountIds);
+        args.addAll(accountIds);
+
+        Integer count = jdbc.queryForObject(
+                "SELECT COUNT(*) FROM transactions WHERE tenant_id = ? AND status = ? AND created_at >= ?"
+                        + " AND (from_account_id IN (" + placeholders + ") OR to_account_id IN (" + placeholders + "))",
+                Integer.class, args.toArray()
+        );
+        return count != null ? count : 0;
+    }
+
+    // Replay is an operator action and spans every tenant; each event carries its own tenant_id.
+    // Pages are keyset over (created_at, id), starting after the given cursor when set.
+    @Override
+    public List<Transaction> listCompletedByDateRange(OffsetDateTime from, OffsetDateTime to, Cursor after, int limit) {
+        if (after == null) {
+            return jdbc.query(
+                    "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE status = 'completed' AND created_at >= ? AND created_at < ?"
+                            + " ORDER BY created_at, id LIMIT ?",
+                    this::mapTransaction, from, to, limit
+            );
         }
+        return jdbc.query(
+                "SELECT " + SELECT_COLUMNS + " FROM transactions WHERE status = 'completed' AND created_at >= ? AND created_at < ?"
+                        + " AND (created_at, id) > (?, ?) ORDER BY created_at, id LIMIT ?",
+                this::mapTransaction, from, to, after.createdAt(), after.id(), limit
+        );
+    }
+
+    // Held until the surrounding transaction ends, so concurrent deposits to one
+    // account check the daily limit one at a time.
+    @Override
+    public void lockDeposits(UUID accountId) {
+        jdbc.queryForList("SELECT pg_advisory_xact_lock(hashtext(?))", "deposits:" + accountId);
+    }
+
+    // Calendar days are UTC.
+    @Override
+    public BigDecimal getDailyDepositTotal(UUID accountId, LocalDate date) {
+        OffsetDateTime start = date.atStartOfDay().atOffset(ZoneOffset.UTC);
+        BigDecimal total = jdbc.queryForObject(
+                "SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE tenant_id = ? AND to_account_id = ?"
+                        + " AND type = 'deposit' AND status = 'completed' AND created_at >= ? AND created_at < ?",
+                BigDecimal.class, TenantContext.require(), accountId, start, start.plusDays(1)
+        );
+        return total != null ? total : BigDecimal.ZERO;
+    }
+
+    // --- Fee schedules ---
+
+    // The schedule is platform-wide, so the lookup isn't tenant-scoped. When a newer
+    // schedule overlaps an older one the most recently effective row wins, and at a
+    // shared band boundary the higher band does.
+    @Override
+    public Optional<Fee> getApplicableFee(String accountTier, String transactionType, BigDecimal amount,
+                                          OffsetDateTime at) {
+        List<Fee> fees = jdbc.query(
+                "SELECT fee_type, fee_value FROM fee_schedules WHERE account_tier = ? AND transaction_type = ?"
+                        + " AND ? BETWEEN min_amount AND max_amount AND effective_from <= ?"
+                        + " AND (effective_to IS NULL OR effective_to >= ?)"
+                        + " ORDER BY effective_from DESC, min_amount DESC LIMIT 1",
+                (rs, rowNum) -> new Fee(rs.getString("fee_type"), rs.getBigDecimal("fee_value")),
+                accountTier, transactionType, amount, at, at
+        );
+        return fees.stream().findFirst();
+    }
+
+    // --- Scheduled transfers ---
+
+    @Override
+    public void createScheduledTransfer(ScheduledTransfer transfer) {
+        jdbc.update(
+                "INSERT INTO scheduled_transfers (" + SCHEDULED_COLUMNS + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+                transfer.getId(), transfer.getTenantId(), transfer.getFromAccountId(), transfer.getToAccountId(),
+                transfer.getAmount(), transfer.getCurrency(), transfer.getDescription(), transfer.getFrequency(),
+                transfer.getStartAt(), transfer.getNextRunAt(), transfer.getLastRunAt(), transfer.getStatus(),
+                transfer.getCreatedAt(), transfer.getUpdatedAt()
+        );
+    }
+
+    @Override
+    public Optional<ScheduledTransfer> getScheduledTransfer(UUID id) {
+        List<ScheduledTransfer> transfers = jdbc.query(
+                "SELECT " + SCHEDULED_COLUMNS + " FROM scheduled_transfers WHERE id = ? AND tenant_id = ?",
+                this::mapScheduledTransfer, id, TenantContext.require()
+        );
+        return transfers.stream().findFirst();
+    }
+
+    @Override
+    public List<ScheduledTransfer> listScheduledTransfers(UUID fromAccountId) {
+        return jdbc.query(
+                "SELECT " + SCHEDULED_COLUMNS + " FROM scheduled_transfers WHERE from_account_id = ? AND tenant_id = ? ORDER BY created_at",
+                this::mapScheduledTransfer, fromAccountId, TenantContext.require()
+        );
+    }
+
+    @Override
+    public void updateScheduledTransfer(ScheduledTransfer transfer) {
+        int rows = jdbc.update(
+                "UPDATE scheduled_transfers SET amount = ?, description = ?, frequency = ?, start_at = ?, "
+                        + "next_run_at = ?, last_run_at = ?, updated_at = NOW() WHERE id = ? AND tenant_id = ?",
+                transfer.getAmount(), transfer.getDescription(), transfer.getFrequency(), transfer.getStartAt(),
+                transfer.getNextRunAt(), transfer.getLastRunAt(), transfer.getId(), TenantContext.require()
+        );
+        if (rows == 0) {
+            throw new IllegalStateException("scheduled transfer " + transfer.getId() + " not found");
+        }
+    }
+
+    @Override
+    public boolean cancelScheduledTransfer(UUID id) {
+        int rows = jdbc.update(
+                "UPDATE scheduled_transfers SET status = 'cancelled', updated_at = NOW() WHERE id = ? AND tenant_id = ? AND status = 'active'",
+                id, TenantContext.require()
+        );
+        return rows > 0;
+    }
+
+    // The worker runs outside any request, so due transfers are looked up across tenants.
+    @Override
+    public List<UUID> listDueScheduledTransferIds(OffsetDateTime now, int limit) {
+        return jdbc.queryForList(
+                "SELECT id FROM scheduled_transfers WHERE status = 'active' AND next_run_at <= ? ORDER BY next_run_at LIMIT ?",
+                UUID.class, now, limit
+        );
+    }
+
+    // SKIP LOCKED lets several replicas run the worker without executing a transfer twice.
+    @Override
+    public Optional<ScheduledTransfer> lockDueScheduledTransfer(UUID id, OffsetDateTime now) {
+        List<ScheduledTransfer> transfers = jdbc.query(
+                "SELECT " + SCHEDULED_COLUMNS + " FROM scheduled_transfers WHERE id = ? AND status = 'active' AND next_run_at <= ? FOR UPDATE SKIP LOCKED",
+                this::mapScheduledTransfer, id, now
+        );
+        return transfers.stream().findFirst();
+    }
+
+    @Override
+    public void recordScheduledTransferRun(UUID scheduledTransferId, UUID transactionId, String status, String error) {
+        jdbc.update(
+                "INSERT INTO scheduled_transfer_runs (id, scheduled_transfer_id, transaction_id, status, error) VALUES (?, ?, ?, ?, ?)",
+                UUID.randomUUID(), scheduledTransferId, transactionId, status, error
+        );
     }
 
     private Transaction mapTransaction(ResultSet rs, int rowNum) throws SQLException {
-        return new Transaction(
+        Transaction txn = new Transaction(
                 rs.getObject("id", UUID.class),
                 rs.getObject("from_account_id", UUID.class),
                 rs.getObject("to_account_id", UUID.class),
@@ -103,5 +592,46 @@ public class TransactionRepositoryImpl implements TransactionRepository {
                 rs.getObject("created_at", java.time.OffsetDateTime.class),
                 rs.getObject("updated_at", java.time.OffsetDateTime.class)
         );
+        txn.setTenantId(rs.getObject("tenant_id", UUID.class));
+        txn.setConvertedAmount(rs.getBigDecimal("converted_amount"));
+        txn.setFxRate(rs.getBigDecimal("fx_rate"));
+        txn.setConvertedCurrency(rs.getString("converted_currency"));
+        txn.setFeeAmount(rs.getBigDecimal("fee_amount"));
+        txn.setFeeCurrency(rs.getString("fee_currency"));
+        txn.setNetAmount(rs.getBigDecimal("net_amount"));
+        txn.setAuthorizedAt(rs.getObject("authorized_at", OffsetDateTime.class));
+        txn.setAuthorizedHoldExpiresAt(rs.getObject("authorized_hold_expires_at", OffsetDateTime.class));
+        txn.setExternalRef(rs.getString("external_ref"));
+        txn.setBatchId(rs.getObject("batch_id", UUID.class));
+        txn.setDeletedAt(rs.getObject("deleted_at", OffsetDateTime.class));
+        txn.setMetadata(readMetadata(rs.getString("metadata")));
+        return txn;
+    }
+
+    private ScheduledTransfer mapScheduledTransfer(ResultSet rs, int rowNum) throws SQLException {
+        ScheduledTransfer transfer = new ScheduledTransfer();
+        transfer.setId(rs.getObject("id", UUID.class));
+        transfer.setTenantId(rs.getObject("tenant_id", UUID.class));
+        transfer.setFromAccountId(rs.getObject("from_account_id", UUID.class));
+        transfer.setToAccountId(rs.getObject("to_account_id", UUID.class));
+        transfer.setAmount(rs.getBigDecimal("amount"));
+        transfer.setCurrency(rs.getString("currency"));
+        transfer.setDescription(rs.getString("description"));
+        transfer.setFrequency(rs.getString("frequency"));
+        transfer.setStartAt(rs.getObject("start_at", OffsetDateTime.class));
+        transfer.setNextRunAt(rs.getObject("next_run_at", OffsetDateTime.class));
+        transfer.setLastRunAt(rs.getObject("last_run_at", OffsetDateTime.class));
+        transfer.setStatus(rs.getString("status"));
+        transfer.setCreatedAt(rs.getObject("created_at", OffsetDateTime.class));
+        transfer.setUpdatedAt(rs.getObject("updated_at", OffsetDateTime.class));
+        return transfer;
+    }
+
+    private static String sortColumn(String sortBy) {
+        String column = TransactionFilter.ALLOWED_SORT_COLUMNS.get(sortBy);
+        if (column == null) {
+            throw new InvalidFilterFieldException(String.valueOf(sortBy));
+        }
+        return column;
     }
 }
@@ -0,0 +1,47 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.model.Transaction;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+
+// Voids authorized transfers whose hold has expired, releasing the held balance.
+@Component
+public class AuthorizationExpiryWorker {
+
+    private static final Logger log = LoggerFactory.getLogger(AuthorizationExpiryWorker.class);
+    private static final int BATCH_SIZE = 100;
+
+    private final TransactionService transactionService;
+
+    public AuthorizationExpiryWorker(TransactionService transactionService) {
+        this.transactionService = transactionService;
+    }
+
+    @Scheduled(fixedDelay = 60_000)
+    public void voidExpiredAuthorizations() {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        List<Transaction> expired = transactionService.listExpiredAuthorizations(now, BATCH_SIZE);
+        int voided = 0;
+        for (Transaction txn : expired) {
+            try {
+                if (transactionService.expireAuthorization(txn)) {
+                    voided++;
+                }
+            } catch (Exception e) {
+                log.atError().setMessage("void expired authorization")
+                        .addKeyValue("transaction_id", txn.getId())
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+            }
+        }
+        if (voided > 0) {
+            log.info("voided {} expired transfer authorizations", voided);
+        }
+    }
+}
@@ -0,0 +1,9 @@
+package com.kubesec.transaction.service;
+
+// Undoes a side effect in another service when the local DB transaction that
+// depended on it is rolled back.
+@FunctionalInterface
+public interface CompensatingAction {
+
+    void compensate();
+}
@@ -0,0 +1,10 @@
+package com.kubesec.transaction.service;
+
+// Raised when an event could not be acknowledged by JetStream in time. Checked so
+// callers decide explicitly what state to leave the transaction in.
+public class EventPublishException extends Exception {
+
+    public EventPublishException(String message, Throwable cause) {
+        super(message, cause);
+    }
+}
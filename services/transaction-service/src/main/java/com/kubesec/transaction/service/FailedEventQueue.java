@@ -0,0 +1,38 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.tenant.TenantContext;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.stereotype.Component;
+
+import java.util.UUID;
+
+// Transactions whose completion event could not be published after every retry. Each
+// entry is "<tenant_id>:<transaction_id>", since the replay has to run under the
+// transaction's tenant; a reconciliation job pops entries and republishes them.
+@Component
+public class FailedEventQueue {
+
+    private static final Logger log = LoggerFactory.getLogger(FailedEventQueue.class);
+    static final String KEY = "failed_events";
+
+    private final StringRedisTemplate redis;
+
+    public FailedEventQueue(StringRedisTemplate redis) {
+        this.redis = redis;
+    }
+
+    // If Redis is down too, the error log is the only record left.
+    public void add(UUID transactionId) {
+        String entry = TenantContext.require() + ":" + transactionId;
+        try {
+            redis.opsForList().rightPush(KEY, entry);
+        } catch (Exception e) {
+            log.atError().setMessage("record failed event")
+                    .addKeyValue("transaction_id", transactionId)
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+        }
+    }
+}
@@ -0,0 +1,30 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.config.AppConfig;
+import org.springframework.stereotype.Service;
+
+import java.math.BigDecimal;
+import java.math.RoundingMode;
+import java.util.Optional;
+
+@Service
+public class FxRateService {
+
+    private final AppConfig config;
+
+    public FxRateService(AppConfig config) {
+        this.config = config;
+    }
+
+    public Optional<BigDecimal> getRate(String fromCurrency, String toCurrency) {
+        if (fromCurrency.equalsIgnoreCase(toCurrency)) {
+            return Optional.of(BigDecimal.ONE);
+        }
+        String key = fromCurrency.toUpperCase() + "_" + toCurrency.toUpperCase();
+        return Optional.ofNullable(config.getFxRates().get(key));
+    }
+
+    public BigDecimal convert(BigDecimal amount, BigDecimal rate) {
+        return amount.multiply(rate).setScale(2, RoundingMode.HALF_EVEN);
+    }
+}
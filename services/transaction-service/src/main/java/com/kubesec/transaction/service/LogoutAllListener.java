@@ -0,0 +1,59 @@
+package com.kubesec.transaction.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.client.TokenValidationCache;
+import com.kubesec.transaction.model.dto.LogoutAllEvent;
+import com.kubesec.transaction.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Dispatcher;
+import io.nats.client.Message;
+import jakarta.annotation.PostConstruct;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.context.annotation.Profile;
+import org.springframework.stereotype.Component;
+
+// Drops the cached validations of a user who logged out everywhere, so their tokens
+// stop working here as soon as auth-service revokes them. The cache is shared by
+// every replica, so one of them handling the event is enough.
+@Component
+@Profile("!test")
+public class LogoutAllListener {
+
+    private static final Logger log = LoggerFactory.getLogger(LogoutAllListener.class);
+    private static final String SUBJECT = "auth.logout_all";
+    private static final String QUEUE_GROUP = "transaction-service";
+
+    private final Connection natsConnection;
+    private final ObjectMapper objectMapper;
+    private final TokenValidationCache tokenCache;
+
+    public LogoutAllListener(Connection natsConnection, ObjectMapper objectMapper, TokenValidationCache tokenCache) {
+        this.natsConnection = natsConnection;
+        this.objectMapper = objectMapper;
+        this.tokenCache = tokenCache;
+    }
+
+    @PostConstruct
+    public void subscribe() {
+        Dispatcher dispatcher = natsConnection.createDispatcher(this::onMessage);
+        dispatcher.subscribe(SUBJECT, QUEUE_GROUP);
+        log.info("Subscribed to {}", SUBJECT);
+    }
+
+    void onMessage(Message msg) {
+        try {
+            LogoutAllEvent event = objectMapper.readValue(msg.getData(), LogoutAllEvent.class);
+            if (event.tenantId() == null || event.userId() == null) {
+                log.warn("Dropping logout-all event without tenant_id or user_id");
+                return;
+            }
+            TenantContext.set(event.tenantId());
+            tokenCache.evictUser(event.userId());
+        } catch (Exception e) {
+            log.error("Failed to handle logout-all event: {}", e.getMessage());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
@@ -1,33 +1,145 @@
 package com.kubesec.transaction.service;
 
+import com.fasterxml.jackson.core.JsonProcessingException;
 import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.dto.BatchCompletedEvent;
 import com.kubesec.transaction.model.dto.TransactionEvent;
-import io.nats.client.Connection;
-import org.slf4j.Logger;
-import org.slf4j.LoggerFactory;
+import io.nats.client.JetStream;
+import io.nats.client.api.PublishAck;
+import io.nats.client.impl.Headers;
 import org.springframework.context.annotation.Profile;
 import org.springframework.stereotype.Service;
 
+import java.time.Duration;
+import java.util.LinkedHashSet;
+import java.util.Set;
+import java.util.UUID;
+import java.util.concurrent.CompletableFuture;
+import java.util.concurrent.ExecutionException;
+import java.util.concurrent.TimeUnit;
+import java.util.concurrent.TimeoutException;
+
 @Service
 @Profile("!test")
 public class NatsPublisher {
 
-    private static final Logger log = LoggerFactory.getLogger(NatsPublisher.class);
+    static final String SUBJECT_PREFIX = "transactions.completed.";
+    static final String REVERSED_SUBJECT = "transactions.reversed";
+    static final String DEPOSIT_SUBJECT = "transactions.deposit";
+    static final String WITHDRAWAL_SUBJECT = "transactions.withdrawal";
+    static final String BATCH_COMPLETED_SUBJECT = "transactions.batch_completed";
 
-    private final Connection natsConnection;
+    private final JetStream jetStream;
     private final ObjectMapper objectMapper;
+    private final Duration publishTimeout;
+    private final int maxAttempts;
+    private final Duration retryBackoff;
 
-    public NatsPublisher(Connection natsConnection, ObjectMapper objectMapper) {
-        this.natsConnection = natsConnection;
+    public NatsPublisher(JetStream jetStream, ObjectMapper objectMapper, AppConfig config) {
+        this.jetStream = jetStream;
         this.objectMapper = objectMapper;
+        this.publishTimeout = config.getNatsPublishTimeout();
+        this.maxAttempts = Math.max(1, config.getNatsPublishRetries());
+        this.retryBackoff = config.getNatsPublishRetryBackoff();
+    }
+
+    public void publishTransactionCompleted(TransactionEvent event) throws EventPublishException {
+        byte[] data = encode(event);
+        // One copy per account involved, so consumers can subscribe to just their accounts.
+        // Nats-Msg-Id lets JetStream drop duplicates when events are replayed; it includes the
+        // account so the two copies of a transfer aren't mistaken for each other.
+        for (UUID accountId : accountIds(event)) {
+            Headers headers = new Headers().put("Nats-Msg-Id", event.transactionId() + ":" + accountId);
+            publish(SUBJECT_PREFIX + accountId, headers, data);
+        }
+    }
+
+    // Announces the reversal itself, once, for consumers that track reversals. Balances
+    // move through the reversal's own completed event.
+    public void publishTransactionReversed(TransactionEvent event) throws EventPublishException {
+        publishOnce(REVERSED_SUBJECT, event);
+    }
+
+    // Announces money entering or leaving the bank, once per transaction, for consumers
+    // that track cash movements rather than individual accounts.
+    public void publishDeposit(TransactionEvent event) throws EventPublishException {
+        publishOnce(DEPOSIT_SUBJECT, event);
+    }
+
+    public void publishWithdrawal(TransactionEvent event) throws EventPublishException {
+        publishOnce(WITHDRAWAL_SUBJECT, event);
+    }
+
+    // One event for the whole batch rather than one per transfer.
+    public void publishBatchCompleted(BatchCompletedEvent event) throws EventPublishException {
+        publish(BATCH_COMPLETED_SUBJECT, new Headers().put("Nats-Msg-Id", event.batchId().toString()), encode(event));
+    }
+
+    private void publishOnce(String subject, TransactionEvent event) throws EventPublishException {
+        publish(subject, new Headers().put("Nats-Msg-Id", event.transactionId().toString()), encode(event));
+    }
+
+    private byte[] encode(Object event) throws EventPublishException {
+        try {
+            return objectMapper.writeValueAsBytes(event);
+        } catch (JsonProcessingException e) {
+            throw new EventPublishException("could not encode event", e);
+        }
+    }
+
+    // Deposits have no source account and withdrawals no destination.
+    static Set<UUID> accountIds(TransactionEvent event) {
+        Set<UUID> ids = new LinkedHashSet<>();
+        if (event.fromAccountId() != null) {
+            ids.add(event.fromAccountId());
+        }
+        if (event.toAccountId() != null) {
+            ids.add(event.toAccountId());
+        }
+        return ids;
+    }
+
+    // Rides out a brief NATS outage by retrying with exponential backoff. Every attempt
+    // carries the same Nats-Msg-Id, so an attempt that was stored but not acked is
+    // deduplicated by JetStream. An interrupt ends the retries at once.
+    private void publish(String subject, Headers headers, byte[] data) throws EventPublishException {
+        Duration backoff = retryBackoff;
+        for (int attempt = 1; ; attempt++) {
+            try {
+                publishAttempt(subject, headers, data);
+                return;
+            } catch (EventPublishException e) {
+                if (attempt >= maxAttempts || Thread.currentThread().isInterrupted()) {
+                    throw e;
+                }
+            }
+            try {
+                Thread.sleep(backoff.toMillis());
+            } catch (InterruptedException e) {
+                Thread.currentThread().interrupt();
+                throw new EventPublishException("publish to " + subject + " was interrupted", e);
+            }
+            backoff = backoff.multipliedBy(2);
+        }
     }
 
-    public void publishTransactionCompleted(TransactionEvent event) {
+    // Waits for the JetStream ack for at most the configured timeout, so a slow or
+    // unreachable server cannot hold a request thread indefinitely. An interrupted
+    // caller (e.g. during shutdown) abandons the publish the same way.
+    private void publishAttempt(String subject, Headers headers, byte[] data) throws EventPublishException {
+        CompletableFuture<PublishAck> ack = jetStream.publishAsync(subject, headers, data);
         try {
-            byte[] data = objectMapper.writeValueAsBytes(event);
-            natsConnection.publish("transactions.completed", data);
-        } catch (Exception e) {
-            log.warn("Failed to publish event: {}", e.getMessage());
+            ack.get(publishTimeout.toMillis(), TimeUnit.MILLISECONDS);
+        } catch (TimeoutException e) {
+            ack.cancel(true);
+            throw new EventPublishException("publish to " + subject + " timed out after " + publishTimeout, e);
+        } catch (InterruptedException e) {
+            ack.cancel(true);
+            Thread.currentThread().interrupt();
+            throw new EventPublishException("publish to " + subject + " was interrupted", e);
+        } catch (ExecutionException e) {
+            throw new EventPublishException("publish to " + subject + " failed: " + e.getCause().getMessage(), e.getCause());
         }
     }
 }
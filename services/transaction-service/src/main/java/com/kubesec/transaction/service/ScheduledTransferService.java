@@ -0,0 +1,165 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.exception.DomainException;
+import com.kubesec.transaction.exception.ResourceNotFoundException;
+import com.kubesec.transaction.exception.ValidationException;
+import com.kubesec.transaction.model.ScheduledTransfer;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.dto.CreateScheduledTransferRequest;
+import com.kubesec.transaction.model.dto.TransferRequest;
+import com.kubesec.transaction.model.dto.UpdateScheduledTransferRequest;
+import com.kubesec.transaction.repository.TransactionRepository;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.stereotype.Service;
+import org.springframework.transaction.annotation.Transactional;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+
+@Service
+public class ScheduledTransferService {
+
+    private static final Logger log = LoggerFactory.getLogger(ScheduledTransferService.class);
+
+    private final TransactionRepository repository;
+    private final TransactionService transactionService;
+
+    public ScheduledTransferService(TransactionRepository repository, TransactionService transactionService) {
+        this.repository = repository;
+        this.transactionService = transactionService;
+    }
+
+    public ScheduledTransfer create(UUID fromAccountId, CreateScheduledTransferRequest request) {
+        if (fromAccountId.equals(request.toAccountId())) {
+            throw new ValidationException("cannot transfer to the same account");
+        }
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        OffsetDateTime startAt = request.startAt() != null ? request.startAt() : now;
+        if (startAt.isBefore(now.minusMinutes(1))) {
+            throw new ValidationException("start_at must not be in the past");
+        }
+
+        ScheduledTransfer transfer = new ScheduledTransfer();
+        transfer.setId(UUID.randomUUID());
+        transfer.setTenantId(TenantContext.require());
+        transfer.setFromAccountId(fromAccountId);
+        transfer.setToAccountId(request.toAccountId());
+        transfer.setAmount(request.amount());
+        transfer.setCurrency(request.currency());
+        transfer.setDescription(request.description() != null ? request.description() : "");
+        transfer.setFrequency(request.frequency());
+        transfer.setStartAt(startAt);
+        transfer.setNextRunAt(startAt);
+        transfer.setStatus("active");
+        transfer.setCreatedAt(now);
+        transfer.setUpdatedAt(now);
+        repository.createScheduledTransfer(transfer);
+        return transfer;
+    }
+
+    public List<ScheduledTransfer> list(UUID fromAccountId) {
+        return repository.listScheduledTransfers(fromAccountId);
+    }
+
+    public ScheduledTransfer update(UUID fromAccountId, UUID id, UpdateScheduledTransferRequest request) {
+        ScheduledTransfer transfer = get(fromAccountId, id);
+        if (!"active".equals(transfer.getStatus())) {
+            throw new ValidationException("scheduled transfer is cancelled");
+        }
+
+        if (request.amount() != null) {
+            transfer.setAmount(request.amount());
+        }
+        if (request.description() != null) {
+            transfer.setDescription(request.description());
+        }
+        if (request.frequency() != null) {
+            transfer.setFrequency(request.frequency());
+        }
+        if (request.nextRunAt() != null) {
+            // Moving the next run re-anchors the schedule on the new date
+            transfer.setStartAt(request.nextRunAt());
+            transfer.setNextRunAt(request.nextRunAt());
+        }
+        transfer.setUpdatedAt(OffsetDateTime.now(ZoneOffset.UTC));
+        repository.updateScheduledTransfer(transfer);
+        return transfer;
+    }
+
+    public void cancel(UUID fromAccountId, UUID id) {
+        get(fromAccountId, id);
+        if (!repository.cancelScheduledTransfer(id)) {
+            throw new ValidationException("scheduled transfer is already cancelled");
+        }
+    }
+
+    public List<UUID> listDue(OffsetDateTime now, int limit) {
+        return repository.listDueScheduledTransferIds(now, limit);
+    }
+
+    // Executes one due transfer and advances its schedule. A failed transfer is logged
+    // as a failed run and retried at the next scheduled date, not immediately.
+    @Transactional
+    public void runDue(UUID id, OffsetDateTime now) {
+        ScheduledTransfer transfer = repository.lockDueScheduledTransfer(id, now).orElse(null);
+        if (transfer == null) {
+            return;
+        }
+
+        TenantContext.set(transfer.getTenantId());
+        try {
+            try {
+                Transaction txn = transactionService.createTransfer(new TransferRequest(
+                        transfer.getFromAccountId(), transfer.getToAccountId(), transfer.getAmount(),
+                        transfer.getCurrency(), transfer.getDescription(), null), null);
+                try {
+                    transactionService.captureTransfer(txn.getId());
+                } catch (DomainException e) {
+                    // Release the hold rather than leave it to expire
+                    transactionService.voidAuthorization(txn.getId());
+                    throw e;
+                }
+                repository.recordScheduledTransferRun(transfer.getId(), txn.getId(), "succeeded", null);
+            } catch (DomainException e) {
+                log.warn("scheduled transfer {} failed: {}", transfer.getId(), e.getMessage());
+                repository.recordScheduledTransferRun(transfer.getId(), null, "failed", e.getMessage());
+            }
+
+            OffsetDateTime next = transfer.getNextRunAt();
+            while (!next.isAfter(now)) {
+                next = nextRunAt(transfer.getStartAt(), next, transfer.getFrequency());
+            }
+            transfer.setLastRunAt(now);
+            transfer.setNextRunAt(next);
+            repository.updateScheduledTransfer(transfer);
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    // Monthly runs are computed from the anchor rather than the previous run so that a
+    // transfer started on the 31st runs on Feb 28/29 and then on Mar 31 again.
+    public static OffsetDateTime nextRunAt(OffsetDateTime startAt, OffsetDateTime current, String frequency) {
+        return switch (frequency) {
+            case "daily" -> current.plusDays(1);
+            case "weekly" -> current.plusWeeks(1);
+            case "monthly" -> {
+                long months = (current.getYear() * 12L + current.getMonthValue())
+                        - (startAt.getYear() * 12L + startAt.getMonthValue());
+                yield startAt.plusMonths(months + 1);
+            }
+            default -> throw new IllegalArgumentException("unknown frequency " + frequency);
+        };
+    }
+
+    private ScheduledTransfer get(UUID fromAccountId, UUID id) {
+        return repository.getScheduledTransfer(id)
+                .filter(transfer -> transfer.getFromAccountId().equals(fromAccountId))
+                .orElseThrow(() -> new ResourceNotFoundException("scheduled transfer not found"));
+    }
+}
@@ -0,0 +1,43 @@
+package com.kubesec.transaction.service;
+
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.scheduling.annotation.Scheduled;
+import org.springframework.stereotype.Component;
+
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+
+@Component
+public class ScheduledTransferWorker {
+
+    private static final Logger log = LoggerFactory.getLogger(ScheduledTransferWorker.class);
+    private static final int BATCH_SIZE = 100;
+
+    private final ScheduledTransferService scheduledTransferService;
+
+    public ScheduledTransferWorker(ScheduledTransferService scheduledTransferService) {
+        this.scheduledTransferService = scheduledTransferService;
+    }
+
+    @Scheduled(fixedDelay = 60_000)
+    public void runDueTransfers() {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        List<UUID> due = scheduledTransferService.listDue(now, BATCH_SIZE);
+        for (UUID id : due) {
+            try {
+                scheduledTransferService.runDue(id, now);
+            } catch (Exception e) {
+                log.atError().setMessage("run scheduled transfer")
+                        .addKeyValue("scheduled_transfer_id", id)
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+            }
+        }
+        if (!due.isEmpty()) {
+            log.info("processed {} due scheduled transfers", due.size());
+        }
+    }
+}
@@ -1,54 +1,93 @@
 package com.kubesec.transaction.service;
 
 import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.client.BalanceRpcException;
+import com.kubesec.transaction.client.NatsRpcClient;
+import com.kubesec.transaction.exception.ConflictException;
+import com.kubesec.transaction.exception.DailyDepositLimitExceededException;
+import com.kubesec.transaction.exception.ForbiddenException;
 import com.kubesec.transaction.exception.InsufficientBalanceException;
 import com.kubesec.transaction.exception.ResourceNotFoundException;
+import com.kubesec.transaction.exception.UpstreamException;
+import com.kubesec.transaction.exception.ValidationException;
+import com.kubesec.transaction.fees.Fee;
+import com.kubesec.transaction.fees.FeeCalculator;
+import com.kubesec.transaction.filter.LogContextFilter;
+import com.kubesec.transaction.model.Cursor;
 import com.kubesec.transaction.model.Transaction;
 import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.TransactionPage;
+import com.kubesec.transaction.model.TransactionStateEvent;
+import com.kubesec.transaction.model.TransferBatch;
+import com.kubesec.transaction.model.TransferResult;
+import com.kubesec.transaction.model.dto.BatchCompletedEvent;
+import com.kubesec.transaction.model.dto.DepositRequest;
+import com.kubesec.transaction.model.dto.PaymentConfirmedRequest;
 import com.kubesec.transaction.model.dto.TransactionEvent;
 import com.kubesec.transaction.model.dto.TransferRequest;
+import com.kubesec.transaction.model.dto.WithdrawalRequest;
 import com.kubesec.transaction.repository.TransactionRepository;
+import com.kubesec.transaction.tenant.TenantContext;
 import org.slf4j.Logger;
 import org.slf4j.LoggerFactory;
+import org.slf4j.MDC;
 import org.springframework.lang.Nullable;
 import org.springframework.stereotype.Service;
+import org.springframework.transaction.annotation.Transactional;
+import org.springframework.transaction.support.TransactionSynchronization;
+import org.springframework.transaction.support.TransactionSynchronizationManager;
+import org.springframework.web.client.HttpClientErrorException;
 
 import java.math.BigDecimal;
+import java.math.RoundingMode;
+import java.time.Duration;
 import java.time.OffsetDateTime;
 import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.HashMap;
 import java.util.List;
+import java.util.Map;
+import java.util.Optional;
 import java.util.UUID;
 
 @Service
 public class TransactionService {
 
     private static final Logger log = LoggerFactory.getLogger(TransactionService.class);
+    static final Duration AUTHORIZATION_HOLD = Duration.ofHours(24);
+    static final Duration IDEMPOTENCY_KEY_TTL = Duration.ofHours(24);
+    static final int REPLAY_PAGE_SIZE = 500;
 
     private final TransactionRepository repository;
     private final AccountServiceClient accountClient;
+    private final FxRateService fxRateService;
+    private final FeeCalculator feeCalculator;
     private final NatsPublisher natsPublisher;
+    private final NatsRpcClient rpcClient;
+    private final FailedEventQueue failedEvents;
 
     public TransactionService(TransactionRepository repository,
                               AccountServiceClient accountClient,
-                              @Nullable NatsPublisher natsPublisher) {
+                              FxRateService fxRateService,
+                              FeeCalculator feeCalculator,
+                              @Nullable NatsPublisher natsPublisher,
+                              @Nullable NatsRpcClient rpcClient,
+                              @Nullable FailedEventQueue failedEvents) {
         this.repository = repository;
         this.accountClient = accountClient;
+        this.fxRateService = fxRateService;
+        this.feeCalculator = feeCalculator;
         this.natsPublisher = natsPublisher;
+        this.rpcClient = rpcClient;
+        this.failedEvents = failedEvents;
     }
 
+    // Authorizes the transfer and holds the amount plus fee against the sender's
+    // balance. Nothing moves until the transfer is captured.
     public Transaction createTransfer(TransferRequest request, String authHeader) {
-        // Validate
-        if (request.fromAccountId() == null || request.toAccountId() == null) {
-            throw new IllegalArgumentException("from_account_id and to_account_id are required");
-        }
-        if (request.amount() == null || request.amount().compareTo(BigDecimal.ZERO) <= 0) {
-            throw new IllegalArgumentException("amount must be positive");
-        }
-        if (request.currency() == null || request.currency().isEmpty()) {
-            throw new IllegalArgumentException("currency is required");
-        }
+        // Field presence and formats are enforced by the transfer request schema
         if (request.fromAccountId().equals(request.toAccountId())) {
-            throw new IllegalArgumentException("cannot transfer to the same account");
+            throw new ValidationException("cannot transfer to the same account");
         }
 
         // Check balance via account-service
@@ -56,16 +95,38 @@ public class TransactionService {
         try {
             balance = accountClient.getBalance(request.fromAccountId(), authHeader);
         } catch (Exception e) {
-            log.error("ERROR: check balance: {}", e.getMessage());
-            throw new RuntimeException("could not verify account balance");
+            log.atError().setMessage("check balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not verify account balance");
         }
+        requireSenderCurrency(request, balance);
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        BigDecimal fee = calculateTransferFee(request, authHeader, now);
 
-        if (balance.balance().compareTo(request.amount()) < 0) {
+        // Look up the recipient's currency to decide whether conversion is needed
+        AccountServiceClient.BalanceResponse recipient;
+        try {
+            recipient = accountClient.getBalance(request.toAccountId(), authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("check recipient account")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not verify recipient account");
+        }
+
+        // The sender pays the fee on top of the amount; the recipient receives the full amount.
+        // Outstanding holds from other authorized transfers are not available to spend.
+        Transaction txn = authorizedTransfer(request, fee, recipient, now);
+        if (repository.createHolds(List.of(txn), Map.of(request.fromAccountId(), balance.balance()), now).isPresent()) {
             throw new InsufficientBalanceException("insufficient balance");
         }
+        return txn;
+    }
 
-        // Create transaction
-        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+    private Transaction authorizedTransfer(TransferRequest request, BigDecimal fee,
+                                           AccountServiceClient.BalanceResponse recipient, OffsetDateTime now) {
         Transaction txn = new Transaction(
                 UUID.randomUUID(),
                 request.fromAccountId(),
@@ -73,42 +134,781 @@ public class TransactionService {
                 request.amount(),
                 request.currency(),
                 "transfer",
-                "pending",
+                "authorized",
                 request.description() != null ? request.description() : "",
                 now,
                 now
         );
+        txn.setTenantId(TenantContext.require());
+        txn.setFeeAmount(fee);
+        txn.setFeeCurrency(request.currency());
+        txn.setNetAmount(request.amount().add(fee));
+        txn.setAuthorizedAt(now);
+        txn.setAuthorizedHoldExpiresAt(now.plus(AUTHORIZATION_HOLD));
+        txn.setMetadata(request.metadata());
 
-        repository.create(txn);
+        if (recipient.currency() != null && !recipient.currency().equalsIgnoreCase(request.currency())) {
+            BigDecimal rate = fxRateService.getRate(request.currency(), recipient.currency())
+                    .orElseThrow(() -> new ValidationException(
+                            "no exchange rate for " + request.currency() + " to " + recipient.currency()));
+            txn.setFxRate(rate);
+            txn.setConvertedAmount(fxRateService.convert(request.amount(), rate));
+            txn.setConvertedCurrency(recipient.currency());
+        }
+        return txn;
+    }
+
+    // Authorizes every transfer in the batch or none of them. Each account's balance is
+    // looked up once, and a sender's available balance must cover all of its transfers
+    // plus fees. Like single transfers these are holds until captured; the batch event
+    // lets a consumer track them as one submission.
+    public TransferBatch createBatch(List<TransferRequest> requests, String authHeader) {
+        for (TransferRequest request : requests) {
+            if (request.fromAccountId().equals(request.toAccountId())) {
+                throw new ValidationException("cannot transfer to the same account");
+            }
+        }
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        UUID batchId = UUID.randomUUID();
+        Map<UUID, AccountServiceClient.BalanceResponse> balances = new HashMap<>();
+        Map<UUID, AccountServiceClient.AccountResponse> senders = new HashMap<>();
+        List<Transaction> txns = new ArrayList<>();
+        for (TransferRequest request : requests) {
+            requireSenderCurrency(request, balances.computeIfAbsent(request.fromAccountId(),
+                    id -> lookupBalance(id, authHeader, "could not verify account balance")));
+            AccountServiceClient.BalanceResponse recipient = balances.computeIfAbsent(request.toAccountId(),
+                    id -> lookupBalance(id, authHeader, "could not verify recipient account"));
+            BigDecimal fee = calculateTransferFee(request, senders, authHeader, now);
+            Transaction txn = authorizedTransfer(request, fee, recipient, now);
+            txn.setBatchId(batchId);
+            txns.add(txn);
+        }
+
+        Map<UUID, BigDecimal> senderBalances = new HashMap<>();
+        for (TransferRequest request : requests) {
+            senderBalances.put(request.fromAccountId(), balances.get(request.fromAccountId()).balance());
+        }
+        Optional<UUID> uncovered = repository.createHolds(txns, senderBalances, now);
+        if (uncovered.isPresent()) {
+            throw new InsufficientBalanceException("insufficient balance in account " + uncovered.get());
+        }
+
+        if (natsPublisher != null) {
+            try {
+                natsPublisher.publishBatchCompleted(new BatchCompletedEvent(batchId, TenantContext.require(),
+                        txns.stream().map(Transaction::getId).toList(), now, MDC.get(LogContextFilter.TRACE_ID)));
+            } catch (EventPublishException e) {
+                log.atError().setMessage("publish batch event")
+                        .addKeyValue("batch_id", batchId)
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+            }
+        }
+        return new TransferBatch(batchId, txns);
+    }
+
+    // Only the recipient's side is converted. The amount is checked against the sender's
+    // balance as is, so it must already be in the sender account's currency.
+    private static void requireSenderCurrency(TransferRequest request, AccountServiceClient.BalanceResponse sender) {
+        if (sender.currency() != null && !sender.currency().equalsIgnoreCase(request.currency())) {
+            throw new ValidationException("currency must be the sending account's currency " + sender.currency());
+        }
+    }
+
+    private AccountServiceClient.BalanceResponse lookupBalance(UUID accountId, String authHeader, String failure) {
+        try {
+            return accountClient.getBalance(accountId, authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("check balance")
+                    .addKeyValue("account_id", accountId)
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException(failure);
+        }
+    }
+
+    // A retry carrying the same Idempotency-Key within 24 hours gets the original
+    // transfer back instead of creating another. The key is claimed before any work is
+    // done, so a concurrent retry finds it in flight and is turned away with a conflict
+    // rather than racing the first request to create a second transfer.
+    public TransferResult createTransfer(TransferRequest request, String authHeader, String idempotencyKey) {
+        if (idempotencyKey == null) {
+            return new TransferResult(createTransfer(request, authHeader), false);
+        }
+        if (idempotencyKey.isBlank() || idempotencyKey.length() > 255) {
+            throw new ValidationException("Idempotency-Key must be 1 to 255 characters");
+        }
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        if (!repository.claimIdempotencyKey(idempotencyKey, now, now.minus(IDEMPOTENCY_KEY_TTL))) {
+            Transaction original = repository.getByIdempotencyKey(idempotencyKey)
+                    .orElseThrow(() -> new ConflictException("a request with this idempotency key is in progress"));
+            if (!isSameTransfer(original, request)) {
+                throw new ConflictException("idempotency key was used for a different transfer");
+            }
+            return new TransferResult(original, true);
+        }
+
+        Transaction txn;
+        try {
+            txn = createTransfer(request, authHeader);
+        } catch (RuntimeException e) {
+            repository.releaseIdempotencyKey(idempotencyKey);
+            throw e;
+        }
+        repository.setIdempotencyKey(idempotencyKey, txn.getId());
+        return new TransferResult(txn, false);
+    }
+
+    private static boolean isSameTransfer(Transaction txn, TransferRequest request) {
+        return request.fromAccountId().equals(txn.getFromAccountId())
+                && request.toAccountId().equals(txn.getToAccountId())
+                && request.amount().compareTo(txn.getAmount()) == 0
+                && request.currency().equals(txn.getCurrency());
+    }
+
+    // Capture and void as a caller: the caller must own the account the transfer is
+    // paid from, unless they are an admin.
+    public Transaction captureTransfer(UUID id, String userId, String role, String authHeader) {
+        requireSenderOrAdmin(getTransaction(id), userId, role, authHeader);
+        return captureTransfer(id);
+    }
+
+    public Transaction voidAuthorization(UUID id, String userId, String role, String authHeader) {
+        requireSenderOrAdmin(getTransaction(id), userId, role, authHeader);
+        return voidAuthorization(id);
+    }
+
+    // Unchecked, for the scheduled transfer runner acting on the system's behalf.
+    // Claims the authorization before publishing, so a concurrent void or second
+    // capture sees it as completed and cannot also act on it. The transfer only
+    // counts as completed once JetStream has acknowledged the event; otherwise it
+    // goes back to authorized and the caller can retry.
+    public Transaction captureTransfer(UUID id) {
+        Transaction txn = getTransaction(id);
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        if ("authorized".equals(txn.getStatus()) && !txn.getAuthorizedHoldExpiresAt().isAfter(now)) {
+            throw new ConflictException("authorization has expired");
+        }
+        if (!repository.transitionStatus(id, "authorized", "completed", "captured")) {
+            throw new ConflictException("only authorized transfers can be captured");
+        }
 
-        // Mark completed
         txn.setStatus("completed");
-        txn.setUpdatedAt(OffsetDateTime.now(ZoneOffset.UTC));
+        txn.setUpdatedAt(now);
+        if (natsPublisher != null) {
+            try {
+                natsPublisher.publishTransactionCompleted(toEvent(txn));
+            } catch (EventPublishException e) {
+                log.atError().setMessage("publish transaction event")
+                        .addKeyValue("transaction_id", txn.getId())
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+                repository.transitionStatus(id, "completed", "authorized", "completion event not published");
+                throw new UpstreamException("could not publish transfer completion");
+            }
+        }
+        return txn;
+    }
+
+    public Transaction voidAuthorization(UUID id) {
+        getTransaction(id);
+        if (!repository.transitionStatus(id, "authorized", "voided", "voided")) {
+            throw new ConflictException("only authorized transfers can be voided");
+        }
+        return getTransaction(id);
+    }
+
+    // Sends a completed transfer's money back with a mirror transaction of type reversal.
+    // The recipient is the one paying it back, so only the recipient account's owner or
+    // an admin may ask for it; deposits and withdrawals have no counterparty to reverse.
+    // As with withdrawals the recipient's balance is checked here for a clear error and
+    // again by account-service under its account lock while debiting. The sender is
+    // credited once that debit is applied, and both are undone if the commit fails. The
+    // transfer fee is not refunded.
+    @Transactional
+    public Transaction reverseTransaction(UUID id, String userId, String role, String authHeader) {
+        Transaction original = getTransaction(id);
+        if (!"transfer".equals(original.getType())) {
+            throw new ConflictException("only transfers can be reversed");
+        }
+        if (!"completed".equals(original.getStatus())) {
+            throw new ConflictException("only completed transactions can be reversed");
+        }
+        if (!"admin".equals(role)) {
+            requireOwner(original.getToAccountId(), userId, authHeader);
+        }
+
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Transaction reversal = mirror(original, now);
+        AccountServiceClient.BalanceResponse balance = lookupBalance(reversal.getFromAccountId(), authHeader,
+                "could not verify account balance");
+        BigDecimal available = balance.balance().subtract(repository.sumActiveHolds(reversal.getFromAccountId(), now));
+        if (available.compareTo(reversal.getAmount()) < 0) {
+            throw new InsufficientBalanceException("insufficient balance");
+        }
+        if (!repository.reverse(id, reversal)) {
+            throw new ConflictException("only completed transactions can be reversed");
+        }
+
+        BigDecimal refund = reversal.getConvertedAmount() != null ? reversal.getConvertedAmount() : reversal.getAmount();
+        try {
+            accountClient.adjustBalance(reversal.getFromAccountId(), reversal.getAmount().negate(), reversal.getId(),
+                    "reversal", authHeader);
+        } catch (HttpClientErrorException e) {
+            throw new ConflictException("reversal rejected by account-service");
+        } catch (Exception e) {
+            log.atError().setMessage("adjust balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not update account balance");
+        }
         try {
-            repository.updateStatus(txn.getId(), "completed");
+            accountClient.adjustBalance(reversal.getToAccountId(), refund, null, "reversal", authHeader);
         } catch (Exception e) {
-            log.error("ERROR: update status: {}", e.getMessage());
+            log.atError().setMessage("adjust balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            // The row rolls back with this exception, which gives the recipient's debit back
+            registerCompletion(reversal, () -> accountClient.adjustBalance(
+                    reversal.getFromAccountId(), reversal.getAmount(), null, "reversal_rollback", authHeader));
+            throw new UpstreamException("could not update account balance");
         }
 
-        // Publish event
-        TransactionEvent event = new TransactionEvent(
-                txn.getId(), txn.getFromAccountId(), txn.getToAccountId(),
-                txn.getAmount(), txn.getCurrency(), txn.getType(),
-                txn.getStatus(), txn.getUpdatedAt()
+        registerCompletion(reversal, () -> {
+            accountClient.adjustBalance(reversal.getToAccountId(), refund.negate(), null, "reversal_rollback", authHeader);
+            accountClient.adjustBalance(reversal.getFromAccountId(), reversal.getAmount(), null, "reversal_rollback",
+                    authHeader);
+        });
+        return reversal;
+    }
+
+    private void requireSenderOrAdmin(Transaction txn, String userId, String role, String authHeader) {
+        if ("admin".equals(role)) {
+            return;
+        }
+        requireOwner(txn.getFromAccountId(), userId, authHeader);
+    }
+
+    private void requireOwner(UUID accountId, String userId, String authHeader) {
+        if (userId == null) {
+            throw new ForbiddenException("account does not belong to the caller");
+        }
+        AccountServiceClient.AccountResponse account;
+        try {
+            account = accountClient.getAccount(accountId, authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("get account owner")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not verify account ownership");
+        }
+        if (!userId.equals(account.user_id())) {
+            throw new ForbiddenException("account does not belong to the caller");
+        }
+    }
+
+    // The reversed account gives back what it received, in its own currency, and the
+    // original sender gets back the original amount.
+    private static Transaction mirror(Transaction original, OffsetDateTime now) {
+        boolean converted = original.getConvertedAmount() != null;
+        Transaction reversal = new Transaction(
+                UUID.randomUUID(),
+                original.getToAccountId(),
+                original.getFromAccountId(),
+                converted ? original.getConvertedAmount() : original.getAmount(),
+                converted ? original.getConvertedCurrency() : original.getCurrency(),
+                "reversal",
+                "completed",
+                "reversal of " + original.getId(),
+                now,
+                now
         );
-        if (natsPublisher != null) {
-            natsPublisher.publishTransactionCompleted(event);
+        reversal.setTenantId(original.getTenantId());
+        if (converted) {
+            reversal.setConvertedAmount(original.getAmount());
+            reversal.setConvertedCurrency(original.getCurrency());
+            reversal.setFxRate(original.getAmount().divide(original.getConvertedAmount(), 8, RoundingMode.HALF_EVEN));
+        }
+        return reversal;
+    }
+
+    public List<Transaction> listExpiredAuthorizations(OffsetDateTime now, int limit) {
+        return repository.listExpiredAuthorizations(now, limit);
+    }
+
+    // Called by the expiry worker outside any request. A hold captured or voided
+    // since it was listed is left alone.
+    public boolean expireAuthorization(Transaction txn) {
+        TenantContext.set(txn.getTenantId());
+        try {
+            return repository.transitionStatus(txn.getId(), "authorized", "voided", "authorization expired");
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    // The fee schedule for the sender's tier takes precedence; amounts or tiers it has
+    // no row for fall back to the configured fee calculator.
+    private BigDecimal calculateTransferFee(TransferRequest request, String authHeader, OffsetDateTime now) {
+        return calculateTransferFee(request, new HashMap<>(), authHeader, now);
+    }
+
+    // senders caches account lookups across the transfers of a batch.
+    private BigDecimal calculateTransferFee(TransferRequest request, Map<UUID, AccountServiceClient.AccountResponse> senders,
+                                            String authHeader, OffsetDateTime now) {
+        try {
+            AccountServiceClient.AccountResponse sender = senders.get(request.fromAccountId());
+            if (sender == null) {
+                sender = accountClient.getAccount(request.fromAccountId(), authHeader);
+                senders.put(request.fromAccountId(), sender);
+            }
+            if (sender.account_type() != null) {
+                Optional<Fee> scheduled = repository.getApplicableFee(
+                        sender.account_type(), "transfer", request.amount(), now);
+                if (scheduled.isPresent()) {
+                    return scheduled.get().apply(request.amount());
+                }
+            }
+            return feeCalculator.calculate(request.amount(), request.currency());
+        } catch (Exception e) {
+            log.atError().setMessage("calculate fee")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not calculate transfer fee");
+        }
+    }
+
+    // The transaction row is only committed once account-service has applied the
+    // credit. If the commit itself fails afterwards, the credit is reversed.
+    @Transactional
+    public Transaction createDeposit(DepositRequest request, String authHeader) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        checkDailyDepositLimit(request, now, authHeader);
+
+        Transaction txn = new Transaction(
+                UUID.randomUUID(),
+                null,
+                request.accountId(),
+                request.amount(),
+                request.currency(),
+                "deposit",
+                "completed",
+                request.description() != null ? request.description() : "",
+                now,
+                now
+        );
+        txn.setTenantId(TenantContext.require());
+        repository.create(txn);
+
+        try {
+            accountClient.adjustBalance(txn.getToAccountId(), txn.getAmount(), txn.getId(), "deposit", authHeader);
+        } catch (HttpClientErrorException e) {
+            throw new ConflictException("deposit rejected by account-service");
+        } catch (Exception e) {
+            log.atError().setMessage("adjust balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not update account balance");
+        }
+
+        registerCompletion(txn, () -> deductBalance(txn.getToAccountId(), txn.getAmount(), "deposit_rollback", authHeader));
+        return txn;
+    }
+
+    // Only the account's owner may withdraw, checked before anything is read or moved.
+    // The balance is checked up front for a clear error; account-service checks it again
+    // under its own lock when deducting, which catches a withdrawal racing this one. As
+    // with deposits, the row is only committed once the deduction is applied, and the
+    // amount is credited back if the commit fails afterwards.
+    @Transactional
+    public Transaction createWithdrawal(WithdrawalRequest request, String userId, String authHeader) {
+        requireOwner(request.accountId(), userId, authHeader);
+
+        AccountServiceClient.BalanceResponse balance;
+        try {
+            balance = accountClient.getBalance(request.accountId(), authHeader);
+        } catch (Exception e) {
+            log.atError().setMessage("check balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not verify account balance");
+        }
+        if (balance.currency() != null && !balance.currency().equalsIgnoreCase(request.currency())) {
+            throw new ValidationException("currency must match the account currency " + balance.currency());
+        }
+
+        // Outstanding holds from authorized transfers are not available to withdraw.
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        BigDecimal available = balance.balance().subtract(repository.sumActiveHolds(request.accountId(), now));
+        if (available.compareTo(request.amount()) < 0) {
+            throw new InsufficientBalanceException("insufficient balance");
+        }
+
+        Transaction txn = new Transaction(
+                UUID.randomUUID(),
+                request.accountId(),
+                null,
+                request.amount(),
+                request.currency(),
+                "withdrawal",
+                "completed",
+                request.description() != null ? request.description() : "",
+                now,
+                now
+        );
+        txn.setTenantId(TenantContext.require());
+        repository.create(txn);
+
+        try {
+            accountClient.adjustBalance(txn.getFromAccountId(), txn.getAmount().negate(), txn.getId(),
+                    "withdrawal", authHeader);
+        } catch (HttpClientErrorException e) {
+            throw new ConflictException("withdrawal rejected by account-service");
+        } catch (Exception e) {
+            log.atError().setMessage("adjust balance")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not update account balance");
         }
 
+        registerCompletion(txn, () -> accountClient.adjustBalance(
+                txn.getFromAccountId(), txn.getAmount(), null, "withdrawal_rollback", authHeader));
         return txn;
     }
 
+    // Records a processor-confirmed payment as a completed deposit. The processor retries
+    // until it gets a 2xx, so a replay returns the stored row and publishes its event
+    // again; account-service applies each transaction id once. The account is credited
+    // by that event rather than synchronously.
+    public Transaction recordPaymentConfirmation(PaymentConfirmedRequest request) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Transaction txn = new Transaction(
+                UUID.randomUUID(),
+                null,
+                request.accountId(),
+                request.amount(),
+                request.currency(),
+                "deposit",
+                "completed",
+                request.description() != null ? request.description() : "",
+                now,
+                now
+        );
+        txn.setTenantId(TenantContext.require());
+        txn.setExternalRef(request.externalRef());
+
+        Transaction stored = repository.upsertByExternalRef(txn)
+                .orElseThrow(() -> new ConflictException("external_ref is already in use"));
+        if (!stored.getToAccountId().equals(request.accountId())
+                || stored.getAmount().compareTo(request.amount()) != 0
+                || !stored.getCurrency().equals(request.currency())) {
+            throw new ConflictException("external_ref was already confirmed with different details");
+        }
+
+        if (natsPublisher != null) {
+            try {
+                natsPublisher.publishTransactionCompleted(toEvent(stored));
+            } catch (EventPublishException e) {
+                log.atError().setMessage("publish transaction event")
+                        .addKeyValue("transaction_id", stored.getId())
+                        .addKeyValue("err", e.getMessage())
+                        .log();
+                throw new UpstreamException("could not publish payment confirmation");
+            }
+        }
+        return stored;
+    }
+
+    // Prefers NATS request-reply so the deduction's outcome is known before returning;
+    // without a NATS connection it falls back to the HTTP adjust endpoint.
+    private void deductBalance(UUID accountId, BigDecimal amount, String reason, String authHeader) {
+        if (rpcClient == null) {
+            accountClient.adjustBalance(accountId, amount.negate(), null, reason, authHeader);
+            return;
+        }
+        try {
+            rpcClient.requestBalanceDeduction(accountId, amount, null, reason);
+        } catch (BalanceRpcException e) {
+            throw new UpstreamException("could not deduct balance: " + e.getMessage());
+        }
+    }
+
+    // Anti-money-laundering cap. The lock is held until the deposit commits, so two
+    // concurrent deposits can't both fit under the limit.
+    private void checkDailyDepositLimit(DepositRequest request, OffsetDateTime now, String authHeader) {
+        BigDecimal limit;
+        try {
+            limit = accountClient.getAccount(request.accountId(), authHeader).max_daily_deposit();
+        } catch (Exception e) {
+            log.atError().setMessage("get deposit limit")
+                    .addKeyValue("err", e.getMessage())
+                    .log();
+            throw new UpstreamException("could not verify deposit limit");
+        }
+        if (limit == null) {
+            return;
+        }
+
+        repository.lockDeposits(request.accountId());
+        BigDecimal current = repository.getDailyDepositTotal(request.accountId(), now.toLocalDate());
+        if (current.add(request.amount()).compareTo(limit) > 0) {
+            throw new DailyDepositLimitExceededException(limit, current);
+        }
+    }
+
+    // The transaction itself stands; its id is queued so the event can be replayed.
+    private void recordFailedEvent(UUID transactionId) {
+        if (failedEvents != null) {
+            failedEvents.add(transactionId);
+        }
+    }
+
+    // Publishes the event once the row is committed, or runs the compensating action
+    // if the DB transaction rolls back after the remote side effect was applied.
+    private void registerCompletion(Transaction txn, CompensatingAction compensation) {
+        TransactionSynchronizationManager.registerSynchronization(new TransactionSynchronization() {
+            @Override
+            public void afterCommit() {
+                if (natsPublisher == null) {
+                    return;
+                }
+                TransactionEvent event = toEvent(txn);
+                try {
+                    natsPublisher.publishTransactionCompleted(event);
+                    if ("deposit".equals(txn.getType())) {
+                        natsPublisher.publishDeposit(event);
+                    } else if ("withdrawal".equals(txn.getType())) {
+                        natsPublisher.publishWithdrawal(event);
+                    } else if ("reversal".equals(txn.getType())) {
+                        natsPublisher.publishTransactionReversed(event);
+                    }
+                } catch (EventPublishException e) {
+                    log.atError().setMessage("publish transaction event")
+                            .addKeyValue("transaction_id", txn.getId())
+                            .addKeyValue("err", e.getMessage())
+                            .log();
+                    recordFailedEvent(txn.getId());
+                }
+            }
+
+            @Override
+            public void afterCompletion(int status) {
+                if (status != STATUS_ROLLED_BACK) {
+                    return;
+                }
+                try {
+                    compensation.compensate();
+                } catch (Exception e) {
+                    log.atError().setMessage("compensating action failed")
+                            .addKeyValue("err", e.getMessage())
+                            .log();
+                }
+            }
+        });
+    }
+
     public Transaction getTransaction(UUID id) {
         return repository.getById(id)
                 .orElseThrow(() -> new ResourceNotFoundException("transaction not found"));
     }
 
-    public List<Transaction> listTransactions(TransactionFilter filter) {
+    public List<TransactionStateEvent> getTransactionHistory(UUID id) {
+        getTransaction(id);
+        return repository.getTransactionEventHistory(id);
+    }
+
+    public void deleteTransaction(UUID id) {
+        if (!repository.deleteById(id)) {
+            throw new ResourceNotFoundException("transaction not found");
+        }
+    }
+
+    public void softDeleteTransaction(UUID id) {
+        if (!repository.softDelete(id)) {
+            throw new ResourceNotFoundException("transaction not found");
+        }
+    }
+
+    // next_cursor is only offered for created_at sorts, the only order a cursor can resume.
+    public TransactionPage listTransactions(TransactionFilter filter) {
+        if (filter.getCreatedAfter() != null && filter.getCreatedBefore() != null
+                && filter.getCreatedAfter().isAfter(filter.getCreatedBefore())) {
+            throw new ValidationException("created_after must not be later than created_before");
+        }
+        if (filter.getCursor() != null && !TransactionFilter.DEFAULT_SORT_BY.equals(filter.getSortBy())) {
+            throw new ValidationException("cursor requires sort_by=created_at");
+        }
+
+        // Fetch one extra row to learn whether another page exists without a second query
+        int pageSize = filter.getLimit();
+        filter.setLimit(pageSize + 1);
+        List<Transaction> transactions = fetchTransactions(filter);
+        filter.setLimit(pageSize);
+
+        if (transactions.size() <= pageSize) {
+            return new TransactionPage(transactions, null);
+        }
+        transactions = transactions.subList(0, pageSize);
+        if (!TransactionFilter.DEFAULT_SORT_BY.equals(filter.getSortBy())) {
+            return new TransactionPage(transactions, null);
+        }
+        Transaction last = transactions.get(pageSize - 1);
+        return new TransactionPage(transactions, new Cursor(last.getCreatedAt(), last.getId()).encode());
+    }
+
+    private List<Transaction> fetchTransactions(TransactionFilter filter) {
+        // Pending transactions per account are the hot path for fraud monitoring. That
+        // lookup is always newest first and unbounded in time, so other orderings, date
+        // ranges, cursors, metadata filters and listings that include soft-deleted rows
+        // take the general query.
+        boolean fastPath = filter.getAccountId() != null && filter.isDefaultSort() && !filter.hasDateRange()
+                && filter.getCursor() == null && !filter.isIncludeDeleted() && filter.getMetadataKey() == null;
+        if (fastPath && "pending".equals(filter.getStatus())) {
+            return repository.getByAccountIdAndStatus(filter.getAccountId(), filter.getStatus(),
+                    filter.getLimit(), filter.getOffset());
+        }
+        // The unfiltered account history is the most common listing. It takes the same
+        // per-branch index walk, fetching enough rows to cover the requested page.
+        if (fastPath && (filter.getStatus() == null || filter.getStatus().isEmpty())) {
+            return repository.getRecentByAccount(filter.getAccountId(), filter.getOffset() + filter.getLimit()).stream()
+                    .skip(filter.getOffset())
+                    .toList();
+        }
         return repository.list(filter);
     }
+
+    public int countFailedTransactions(List<UUID> accountIds, OffsetDateTime since) {
+        if (accountIds.size() > 100) {
+            throw new ValidationException("at most 100 account_id values are allowed");
+        }
+        return repository.countByAccountsAndStatusSince(accountIds, "failed", since);
+    }
+
+    public int replayCompletedEvents(OffsetDateTime from, OffsetDateTime to) {
+        if (!from.isBefore(to)) {
+            throw new ValidationException("from must be before to");
+        }
+        if (natsPublisher == null) {
+            throw new UpstreamException("event publishing is not available");
+        }
+
+        // Paged so a wide range doesn't load every completed row at once
+        int published = 0;
+        Cursor after = null;
+        List<Transaction> page;
+        do {
+            page = repository.listCompletedByDateRange(from, to, after, REPLAY_PAGE_SIZE);
+            for (Transaction txn : page) {
+                try {
+                    natsPublisher.publishTransactionCompleted(toEvent(txn));
+                } catch (EventPublishException e) {
+                    // Consumers are idempotent, so the operator can simply rerun the same range
+                    log.atError().setMessage("replay stopped")
+                            .addKeyValue("published", published)
+                            .addKeyValue("err", e.getMessage())
+                            .log();
+                    throw new UpstreamException("replay stopped after " + published + " events");
+                }
+                published++;
+            }
+            if (!page.isEmpty()) {
+                Transaction last = page.get(page.size() - 1);
+                after = new Cursor(last.getCreatedAt(), last.getId());
+            }
+        } while (page.size() == REPLAY_PAGE_SIZE);
+        log.info("replayed {} transaction events from {} to {}", published, from, to);
+        return published;
+    }
+
+    private TransactionEvent toEvent(Transaction txn) {
+        return new TransactionEvent(
+                txn.getId(), txn.getTenantId(), txn.getFromAccountId(), txn.getToAccountId(),
+                txn.getAmount(), txn.getCurrency(), txn.getType(),
+                txn.getStatus(), txn.getConvertedAmount(), txn.getFxRate(),
+                txn.getConvertedCurrency(), txn.getFeeAmount(), txn.getUpdatedAt(),
+                MDC.get(LogContextFilter.TRACE_ID), LogContextFilter.currentRequestId(),
+                LogContextFilter.currentCorrelationId()
+        );
+    }
+
+    public static Builder builder() {
+        return new Builder();
+    }
+
+    // Assembles the service outside the Spring context, where seven positional arguments
+    // are easy to mix up. The NATS publisher, RPC client and failed-event queue may be left unset.
+    public static final class Builder {
+
+        private TransactionRepository repository;
+        private AccountServiceClient accountClient;
+        private FxRateService fxRateService;
+        private FeeCalculator feeCalculator;
+        private NatsPublisher natsPublisher;
+        private NatsRpcClient rpcClient;
+        private FailedEventQueue failedEvents;
+
+        private Builder() {}
+
+        public Builder withRepository(TransactionRepository repository) {
+            this.repository = repository;
+            return this;
+        }
+
+        public Builder withAccountClient(AccountServiceClient accountClient) {
+            this.accountClient = accountClient;
+            return this;
+        }
+
+        public Builder withFxRateService(FxRateService fxRateService) {
+            this.fxRateService = fxRateService;
+            return this;
+        }
+
+        public Builder withFeeCalculator(FeeCalculator feeCalculator) {
+            this.feeCalculator = feeCalculator;
+            return this;
+        }
+
+        public Builder withNatsPublisher(NatsPublisher natsPublisher) {
+            this.natsPublisher = natsPublisher;
+            return this;
+        }
+
+        public Builder withRpcClient(NatsRpcClient rpcClient) {
+            this.rpcClient = rpcClient;
+            return this;
+        }
+
+        public Builder withFailedEventQueue(FailedEventQueue failedEvents) {
+            this.failedEvents = failedEvents;
+            return this;
+        }
+
+        public void validate() {
+            List<String> missing = new ArrayList<>();
+            if (repository == null) {
+                missing.add("repository");
+            }
+            if (accountClient == null) {
+                missing.add("accountClient");
+            }
+            if (fxRateService == null) {
+                missing.add("fxRateService");
+            }
+            if (feeCalculator == null) {
+                missing.add("feeCalculator");
+            }
+            if (!missing.isEmpty()) {
+                throw new IllegalStateException("missing required dependencies: " + String.join(", ", missing));
+            }
+        }
+
+        public TransactionService build() {
+            validate();
+            return new TransactionService(repository, accountClient, fxRateService, feeCalculator, natsPublisher,
+                    rpcClient, failedEvents);
+        }
+    }
 }
@@ -0,0 +1,48 @@
+package com.kubesec.transaction.validation;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.networknt.schema.JsonSchema;
+import com.networknt.schema.JsonSchemaFactory;
+import com.networknt.schema.SchemaValidatorsConfig;
+import com.networknt.schema.SpecVersion;
+import com.networknt.schema.ValidationMessage;
+import org.springframework.stereotype.Component;
+
+import java.io.IOException;
+import java.io.InputStream;
+import java.io.UncheckedIOException;
+import java.util.Comparator;
+import java.util.List;
+import java.util.Map;
+import java.util.concurrent.ConcurrentHashMap;
+
+@Component
+public class SchemaValidator {
+
+    private final JsonSchemaFactory factory = JsonSchemaFactory.getInstance(SpecVersion.VersionFlag.V202012);
+    // Draft 2020-12 treats "format" as an annotation only; uuid/email/date-time must be enforced
+    private final SchemaValidatorsConfig config = SchemaValidatorsConfig.builder()
+            .formatAssertionsEnabled(true)
+            .build();
+    private final Map<String, JsonSchema> schemas = new ConcurrentHashMap<>();
+
+    // Returns every violation rather than stopping at the first, sorted for stable output.
+    public List<String> validate(String schemaName, JsonNode body) {
+        return schemas.computeIfAbsent(schemaName, this::load).validate(body).stream()
+                .sorted(Comparator.comparing(ValidationMessage::getInstanceLocation, Comparator.comparing(Object::toString)))
+                .map(ValidationMessage::getMessage)
+                .toList();
+    }
+
+    private JsonSchema load(String schemaName) {
+        String path = "/schemas/" + schemaName + ".json";
+        try (InputStream in = SchemaValidator.class.getResourceAsStream(path)) {
+            if (in == null) {
+                throw new IllegalStateException("schema not found: " + path);
+            }
+            return factory.getSchema(in, config);
+        } catch (IOException e) {
+            throw new UncheckedIOException(e);
+        }
+    }
+}
@@ -0,0 +1,15 @@
+package com.kubesec.transaction.validation;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// Marks a @RequestBody parameter to be checked against classpath:schemas/<value>.json
+// before it is deserialized.
+@Target(ElementType.PARAMETER)
+@Retention(RetentionPolicy.RUNTIME)
+public @interface ValidatedBody {
+
+    String value();
+}
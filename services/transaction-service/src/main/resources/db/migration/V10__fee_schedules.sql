@@ -0,0 +1,21 @@
+-- Tiered fees. A row applies to transactions of its type from accounts of its tier
+-- whose amount falls within [min_amount, max_amount] and that occur inside the
+-- effective window; effective_to NULL means open-ended. fee_value is a currency
+-- amount for flat fees and a percentage of the amount (1.5 = 1.5%) otherwise.
+-- The schedule is platform-wide rather than per tenant.
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id               UUID PRIMARY KEY,
+    account_tier     VARCHAR(20)    NOT NULL,
+    transaction_type VARCHAR(20)    NOT NULL,
+    min_amount       NUMERIC(18, 2) NOT NULL,
+    max_amount       NUMERIC(18, 2) NOT NULL,
+    fee_type         VARCHAR(20)    NOT NULL CHECK (fee_type IN ('flat', 'percentage')),
+    fee_value        NUMERIC(18, 6) NOT NULL CHECK (fee_value >= 0),
+    effective_from   TIMESTAMPTZ    NOT NULL,
+    effective_to     TIMESTAMPTZ,
+    CHECK (min_amount <= max_amount),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fee_schedules_lookup
+    ON fee_schedules (account_tier, transaction_type, effective_from);
@@ -0,0 +1,3 @@
+-- Payment processor reference for transactions created from webhooks. Unique so a
+-- replayed confirmation updates the existing row instead of creating a second one.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_ref VARCHAR(255) UNIQUE;
@@ -0,0 +1,4 @@
+-- Newest-first scan over rows with a source account. Lets the planner walk
+-- created_at for very active accounts instead of sorting their whole history.
+CREATE INDEX IF NOT EXISTS idx_txn_account_recent
+    ON transactions (created_at DESC) WHERE from_account_id IS NOT NULL;
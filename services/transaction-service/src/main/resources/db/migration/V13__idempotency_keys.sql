@@ -0,0 +1,10 @@
+-- Idempotency-Key values sent with transfers. A row is claimed before the transfer
+-- is created and points at it once it exists; claims older than 24 hours may be
+-- taken again.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    tenant_id       UUID NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    transaction_id  UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, idempotency_key)
+);
@@ -0,0 +1,5 @@
+-- A reversal is a completed transaction that mirrors a completed one with the
+-- accounts swapped; the original moves to 'reversed' in the same DB transaction.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('transfer', 'payment', 'deposit', 'withdrawal', 'reversal'));
@@ -0,0 +1,3 @@
+-- Transfers submitted together through POST /transactions/batch share a batch id.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS batch_id UUID;
+CREATE INDEX IF NOT EXISTS idx_transactions_batch ON transactions (batch_id) WHERE batch_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Set by DELETE /admin/transactions/{id}; the row and its events are kept for audit.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Caller-supplied key-value pairs such as an invoice number or merchant id.
+-- GIN keeps the metadata_key/metadata_value listing filter off a sequential scan.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_transactions_metadata ON transactions USING GIN (metadata);
@@ -0,0 +1,5 @@
+-- Cross-currency transfers record the amount credited in the recipient's currency
+-- and the rate used to compute it. All three columns are NULL for same-currency transfers.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS converted_amount   DECIMAL(18, 2);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fx_rate            DECIMAL(18, 8);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS converted_currency VARCHAR(3);
@@ -0,0 +1,2 @@
+-- Deposits credit an account from outside the bank, so they have no source account.
+ALTER TABLE transactions ALTER COLUMN from_account_id DROP NOT NULL;
@@ -0,0 +1,3 @@
+-- Composite indexes backing per-account status lookups used by fraud monitoring.
+CREATE INDEX IF NOT EXISTS idx_transactions_from_account_status ON transactions (from_account_id, status);
+CREATE INDEX IF NOT EXISTS idx_transactions_to_account_status   ON transactions (to_account_id, status);
@@ -0,0 +1,16 @@
+-- tenants are the financial institutions served by the platform. Rows created before
+-- multitenancy belong to the default tenant.
+CREATE TABLE IF NOT EXISTS tenants (
+    id         UUID         PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    active     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default')
+    ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tenant_id UUID NOT NULL
+    DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES tenants(id);
+ALTER TABLE transactions ALTER COLUMN tenant_id DROP DEFAULT;
+CREATE INDEX IF NOT EXISTS idx_transactions_tenant_id ON transactions (tenant_id);
@@ -0,0 +1,36 @@
+-- Recurring transfers set up by users (e.g. bill payments). start_at anchors the
+-- schedule so monthly runs keep their day of month after short months.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id              UUID PRIMARY KEY,
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    from_account_id UUID NOT NULL,
+    to_account_id   UUID NOT NULL,
+    amount          DECIMAL(18, 2) NOT NULL CHECK (amount > 0),
+    currency        VARCHAR(3) NOT NULL,
+    description     TEXT DEFAULT '',
+    frequency       VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    start_at        TIMESTAMPTZ NOT NULL,
+    next_run_at     TIMESTAMPTZ NOT NULL,
+    last_run_at     TIMESTAMPTZ,
+    status          VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled')),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due
+    ON scheduled_transfers (next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_from_account
+    ON scheduled_transfers (tenant_id, from_account_id);
+
+-- One row per execution attempt, successful or not.
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+    id                    UUID PRIMARY KEY,
+    scheduled_transfer_id UUID NOT NULL REFERENCES scheduled_transfers(id),
+    transaction_id        UUID REFERENCES transactions(id),
+    status                VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    error                 TEXT,
+    run_at                TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfer_runs_transfer
+    ON scheduled_transfer_runs (scheduled_transfer_id, run_at DESC);
@@ -0,0 +1,6 @@
+-- Transfers charge the sender a fee on top of the amount credited to the recipient.
+-- net_amount is the total debited from the sender (amount + fee_amount).
+-- All three columns are NULL for deposits and for transfers made before fees existed.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_amount   DECIMAL(18, 2);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fee_currency VARCHAR(3);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS net_amount   DECIMAL(18, 2);
@@ -0,0 +1,18 @@
+-- Append-only log of status transitions, ordered by seq. transactions.status is
+-- kept as a projection of the latest event for each transaction.
+CREATE TABLE IF NOT EXISTS transaction_events (
+    id             UUID PRIMARY KEY,
+    seq            BIGSERIAL NOT NULL,
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    from_status    VARCHAR(20),
+    to_status      VARCHAR(20) NOT NULL,
+    reason         TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_events_transaction
+    ON transaction_events (transaction_id, seq);
+
+-- Seed one event per existing transaction so every row has a history.
+INSERT INTO transaction_events (id, transaction_id, from_status, to_status, reason, created_at)
+SELECT gen_random_uuid(), id, NULL, status, 'backfill', created_at FROM transactions ORDER BY created_at;
@@ -0,0 +1,14 @@
+-- Transfers are authorized first and captured or voided later. An authorized
+-- transfer holds its amount against the sender's balance until it expires.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('pending', 'authorized', 'completed', 'voided', 'failed', 'reversed'));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS authorized_at TIMESTAMPTZ;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS authorized_hold_expires_at TIMESTAMPTZ;
+
+-- Backs both the per-account hold sum and the expiry worker's scan.
+CREATE INDEX IF NOT EXISTS idx_transactions_authorized_holds
+    ON transactions (from_account_id, authorized_hold_expires_at) WHERE status = 'authorized';
+CREATE INDEX IF NOT EXISTS idx_transactions_authorized_expiry
+    ON transactions (authorized_hold_expires_at) WHERE status = 'authorized';
@@ -11,6 +11,7 @@ import org.springframework.test.context.TestPropertySource;
         "spring.datasource.url=jdbc:h2:mem:testdb",
         "spring.datasource.driver-class-name=org.h2.Driver",
         "spring.flyway.enabled=false",
+        "app.db-schema-retry-attempts=0",
         "app.nats-url=nats://localhost:4222"
 })
 class ApplicationTest {
@@ -0,0 +1,87 @@
+package com.kubesec.transaction.client;
+
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.time.Duration;
+import java.time.Instant;
+import java.util.concurrent.atomic.AtomicInteger;
+import java.util.concurrent.atomic.AtomicReference;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class CircuitBreakerTest {
+
+    private static final Duration TIMEOUT = Duration.ofSeconds(30);
+
+    private final AtomicReference<Instant> now = new AtomicReference<>(Instant.parse("2026-01-01T00:00:00Z"));
+    private final SimpleMeterRegistry registry = new SimpleMeterRegistry();
+    private CircuitBreaker breaker;
+
+    @BeforeEach
+    void setUp() {
+        breaker = new CircuitBreaker("auth", 1, Duration.ofSeconds(60), TIMEOUT, 3, registry, now::get);
+    }
+
+    @Test
+    void opensAfterConsecutiveFailuresAndFailsFast() {
+        open();
+
+        AtomicInteger calls = new AtomicInteger();
+        assertThrows(CircuitOpenException.class, () -> breaker.call(calls::incrementAndGet, e -> true));
+        assertEquals(0, calls.get());
+        assertEquals(CircuitBreaker.State.OPEN, breaker.state());
+        assertEquals(2.0, gauge());
+    }
+
+    @Test
+    void successResetsTheFailureCount() {
+        assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> true));
+        assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> true));
+        breaker.call(() -> "ok", e -> true);
+        assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> true));
+
+        assertEquals(CircuitBreaker.State.CLOSED, breaker.state());
+        assertEquals(0.0, gauge());
+    }
+
+    @Test
+    void exceptionsThatAreNotFailuresLeaveTheCircuitClosed() {
+        for (int i = 0; i < 5; i++) {
+            assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> false));
+        }
+
+        assertEquals(CircuitBreaker.State.CLOSED, breaker.state());
+    }
+
+    @Test
+    void trialCallAfterTimeoutClosesOrReopensTheCircuit() {
+        open();
+        now.set(now.get().plus(TIMEOUT));
+        assertEquals(CircuitBreaker.State.HALF_OPEN, breaker.state());
+        assertEquals(1.0, gauge());
+
+        assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> true));
+        assertEquals(CircuitBreaker.State.OPEN, breaker.state());
+
+        now.set(now.get().plus(TIMEOUT));
+        assertEquals("ok", breaker.call(() -> "ok", e -> true));
+        assertEquals(CircuitBreaker.State.CLOSED, breaker.state());
+    }
+
+    private void open() {
+        for (int i = 0; i < 3; i++) {
+            assertThrows(IllegalStateException.class, () -> breaker.call(CircuitBreakerTest::fail, e -> true));
+        }
+    }
+
+    private double gauge() {
+        return registry.get(CircuitBreaker.STATE_METRIC).tag("service", "auth").gauge().value();
+    }
+
+    private static String fail() {
+        throw new IllegalStateException("auth-service down");
+    }
+}
@@ -0,0 +1,103 @@
+package com.kubesec.transaction.client;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Message;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+
+import java.math.BigDecimal;
+import java.nio.charset.StandardCharsets;
+import java.time.Duration;
+import java.util.UUID;
+import java.util.concurrent.CompletableFuture;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+// Covers the request encoding and reply handling against a mocked connection.
+// There is no embeddable NATS server for the JVM to run this end to end.
+class NatsRpcClientTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private final UUID tenantId = UUID.randomUUID();
+    private final UUID accountId = UUID.randomUUID();
+
+    private Connection connection;
+    private NatsRpcClient client;
+
+    @BeforeEach
+    void setUp() {
+        connection = mock(Connection.class);
+        AppConfig config = new AppConfig();
+        config.setNatsRequestTimeout(Duration.ofMillis(50));
+        client = new NatsRpcClient(connection, objectMapper, config);
+        TenantContext.set(tenantId);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void sendsTenantScopedDeduction() throws Exception {
+        reply("{\"ok\":true}");
+        UUID transactionId = UUID.randomUUID();
+
+        client.requestBalanceDeduction(accountId, new BigDecimal("12.50"), transactionId, "deposit_rollback");
+
+        ArgumentCaptor<byte[]> sent = ArgumentCaptor.forClass(byte[].class);
+        verify(connection).request(eq(NatsRpcClient.DEDUCT_SUBJECT), sent.capture());
+        JsonNode request = objectMapper.readTree(sent.getValue());
+        assertEquals(tenantId.toString(), request.get("tenant_id").asText());
+        assertEquals(accountId.toString(), request.get("account_id").asText());
+        assertEquals(0, new BigDecimal("12.50").compareTo(request.get("amount").decimalValue()));
+        assertEquals(transactionId.toString(), request.get("transaction_id").asText());
+    }
+
+    @Test
+    void rejectionCarriesTheRemoteCode() {
+        reply("{\"ok\":false,\"code\":\"conflict\",\"error\":\"adjustment would overdraw the account\"}");
+
+        BalanceRpcException e = assertThrows(BalanceRpcException.class,
+                () -> client.requestBalanceDeduction(accountId, BigDecimal.ONE, null, null));
+        assertEquals("conflict", e.getCode());
+    }
+
+    @Test
+    void missingReplyTimesOut() {
+        when(connection.request(any(String.class), any(byte[].class))).thenReturn(new CompletableFuture<>());
+
+        BalanceRpcException e = assertThrows(BalanceRpcException.class,
+                () -> client.requestBalanceDeduction(accountId, BigDecimal.ONE, null, null));
+        assertEquals("timeout", e.getCode());
+    }
+
+    @Test
+    void noRespondersFailsFast() {
+        CompletableFuture<Message> cancelled = new CompletableFuture<>();
+        cancelled.cancel(true);
+        when(connection.request(any(String.class), any(byte[].class))).thenReturn(cancelled);
+
+        BalanceRpcException e = assertThrows(BalanceRpcException.class,
+                () -> client.requestBalanceDeduction(accountId, BigDecimal.ONE, null, null));
+        assertEquals("no_responders", e.getCode());
+    }
+
+    private void reply(String json) {
+        Message msg = mock(Message.class);
+        when(msg.getData()).thenReturn(json.getBytes(StandardCharsets.UTF_8));
+        when(connection.request(any(String.class), any(byte[].class))).thenReturn(CompletableFuture.completedFuture(msg));
+    }
+}
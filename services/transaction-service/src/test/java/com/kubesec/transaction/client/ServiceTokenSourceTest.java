@@ -0,0 +1,66 @@
+package com.kubesec.transaction.client;
+
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.time.Instant;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+class ServiceTokenSourceTest {
+
+    private static final Instant T0 = Instant.parse("2024-03-01T12:00:00Z");
+
+    private AuthServiceClient authClient;
+    private ServiceTokenSource source;
+
+    @BeforeEach
+    void setUp() {
+        authClient = mock(AuthServiceClient.class);
+        // Tokens live 60s from T0, then from T0+55s.
+        when(authClient.issueServiceToken(anyString())).thenReturn(
+                new AuthServiceClient.ServiceTokenResponse("first", T0.plusSeconds(60)),
+                new AuthServiceClient.ServiceTokenResponse("second", T0.plusSeconds(115)));
+        source = new ServiceTokenSource(authClient);
+    }
+
+    @Test
+    void tokenIsReusedWhileItHasTimeLeft() {
+        assertEquals("first", source.token("account-service", T0));
+        assertEquals("first", source.token("account-service", T0.plusSeconds(30)));
+        assertEquals("first", source.token("account-service", T0.plusSeconds(49)));
+
+        verify(authClient, times(1)).issueServiceToken("account-service");
+    }
+
+    @Test
+    void tokenIsRefreshedAheadOfExpiry() {
+        assertEquals("first", source.token("account-service", T0));
+        // 10s before expiry is inside the refresh margin.
+        assertEquals("second", source.token("account-service", T0.plusSeconds(50)));
+        assertEquals("second", source.token("account-service", T0.plusSeconds(100)));
+
+        verify(authClient, times(2)).issueServiceToken("account-service");
+    }
+
+    @Test
+    void expiredTokenIsNeverHandedOut() {
+        source.token("account-service", T0);
+
+        assertEquals("second", source.token("account-service", T0.plusSeconds(90)));
+    }
+
+    @Test
+    void eachAudienceHasItsOwnToken() {
+        assertEquals("first", source.token("account-service", T0));
+        assertEquals("second", source.token("auth-service", T0));
+
+        verify(authClient).issueServiceToken("account-service");
+        verify(authClient).issueServiceToken("auth-service");
+    }
+}
@@ -0,0 +1,202 @@
+package com.kubesec.transaction.client;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.data.redis.RedisConnectionFailureException;
+import org.springframework.data.redis.core.SetOperations;
+import org.springframework.data.redis.core.StringRedisTemplate;
+import org.springframework.data.redis.core.ValueOperations;
+
+import java.nio.charset.StandardCharsets;
+import java.time.Duration;
+import java.time.Instant;
+import java.util.Base64;
+import java.util.Collection;
+import java.util.HashMap;
+import java.util.HashSet;
+import java.util.Map;
+import java.util.Optional;
+import java.util.Set;
+import java.util.UUID;
+import java.util.concurrent.TimeUnit;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.anyCollection;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.doAnswer;
+import static org.mockito.Mockito.doThrow;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.when;
+
+// Backs the cache with a mocked template whose values, sets and TTLs live in maps, so
+// stored keys and expiry can be inspected.
+class TokenValidationCacheTest {
+
+    private static final AuthServiceClient.ValidateResponse IDENTITY =
+            new AuthServiceClient.ValidateResponse(true, "user-1", "teller");
+
+    private final Map<String, String> values = new HashMap<>();
+    private final Map<String, Set<String>> sets = new HashMap<>();
+    private final Map<String, Duration> ttls = new HashMap<>();
+    private ValueOperations<String, String> ops;
+    private TokenValidationCache cache;
+
+    @BeforeEach
+    @SuppressWarnings("unchecked")
+    void setUp() {
+        StringRedisTemplate redis = mock(StringRedisTemplate.class);
+        ops = mock(ValueOperations.class);
+        when(redis.opsForValue()).thenReturn(ops);
+        when(ops.get(anyString())).thenAnswer(inv -> values.get(inv.<String>getArgument(0)));
+        doAnswer(inv -> {
+            values.put(inv.getArgument(0), inv.getArgument(1));
+            ttls.put(inv.getArgument(0), inv.getArgument(2));
+            return null;
+        }).when(ops).set(anyString(), anyString(), any(Duration.class));
+        SetOperations<String, String> setOps = mock(SetOperations.class);
+        when(redis.opsForSet()).thenReturn(setOps);
+        when(setOps.add(anyString(), any(String[].class))).thenAnswer(inv -> {
+            sets.computeIfAbsent(inv.getArgument(0), k -> new HashSet<>()).add(inv.getArgument(1));
+            return 1L;
+        });
+        when(setOps.members(anyString())).thenAnswer(inv -> sets.get(inv.<String>getArgument(0)));
+        when(redis.getExpire(anyString(), eq(TimeUnit.SECONDS))).thenAnswer(inv -> {
+            Duration ttl = ttls.get(inv.<String>getArgument(0));
+            return ttl != null ? ttl.toSeconds() : -1L;
+        });
+        when(redis.expire(anyString(), any(Duration.class))).thenAnswer(inv -> {
+            ttls.put(inv.getArgument(0), inv.getArgument(1));
+            return true;
+        });
+        when(redis.delete(anyString())).thenAnswer(inv -> {
+            String key = inv.getArgument(0);
+            return values.remove(key) != null || sets.remove(key) != null;
+        });
+        when(redis.delete(anyCollection())).thenAnswer(inv -> {
+            Collection<String> keys = inv.getArgument(0);
+            keys.forEach(values::remove);
+            return (long) keys.size();
+        });
+        cache = new TokenValidationCache(redis, new ObjectMapper());
+        TenantContext.set(UUID.randomUUID());
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void missThenHitReturnsTheCachedUserAndRole() {
+        String token = jwt(Instant.now().plusSeconds(600));
+
+        assertEquals(Optional.empty(), cache.get(token));
+
+        cache.put(token, IDENTITY);
+
+        assertEquals(Optional.of(IDENTITY), cache.get(token));
+    }
+
+    @Test
+    void entryExpiresWithTheToken() {
+        cache.put(jwt(Instant.now().plusSeconds(300)), IDENTITY);
+
+        Duration ttl = ttls.get(values.keySet().iterator().next());
+        assertTrue(ttl.compareTo(Duration.ofSeconds(295)) >= 0 && ttl.compareTo(Duration.ofSeconds(300)) <= 0,
+                "ttl " + ttl + " should match the token's remaining lifetime");
+    }
+
+    @Test
+    void expiredOrUnreadableTokensAreNotCached() {
+        cache.put(jwt(Instant.now().minusSeconds(1)), IDENTITY);
+        cache.put(encode("{\"sub\":\"user-1\"}"), IDENTITY); // no exp claim
+        cache.put("not-a-jwt", IDENTITY);
+
+        assertTrue(values.isEmpty());
+    }
+
+    @Test
+    void keysAreScopedToTenantAndHoldNoRawToken() {
+        String token = jwt(Instant.now().plusSeconds(600));
+        cache.put(token, IDENTITY);
+
+        String key = values.keySet().iterator().next();
+        assertTrue(key.startsWith("validated:" + TenantContext.require() + ":"));
+        assertFalse(key.contains(token));
+
+        TenantContext.set(UUID.randomUUID());
+        assertEquals(Optional.empty(), cache.get(token));
+    }
+
+    @Test
+    void logoutAllEvictsEveryTokenOfTheUserInTheTenant() {
+        String first = jwt(Instant.now().plusSeconds(600));
+        String second = jwt(Instant.now().plusSeconds(900));
+        String other = encode("{\"sub\":\"user-2\",\"exp\":" + Instant.now().plusSeconds(600).getEpochSecond() + "}");
+        AuthServiceClient.ValidateResponse otherUser = new AuthServiceClient.ValidateResponse(true, "user-2", "customer");
+        UUID tenant = TenantContext.require();
+        UUID otherTenant = UUID.randomUUID();
+        cache.put(first, IDENTITY);
+        cache.put(second, IDENTITY);
+        cache.put(other, otherUser);
+        TenantContext.set(otherTenant);
+        cache.put(first, IDENTITY);
+
+        TenantContext.set(tenant);
+        cache.evictUser("user-1");
+
+        assertEquals(Optional.empty(), cache.get(first));
+        assertEquals(Optional.empty(), cache.get(second));
+        assertEquals(Optional.of(otherUser), cache.get(other));
+        TenantContext.set(otherTenant);
+        assertEquals(Optional.of(IDENTITY), cache.get(first), "the same user id in another tenant is someone else");
+    }
+
+    @Test
+    void userIndexOutlivesItsLongestEntry() {
+        cache.put(jwt(Instant.now().plusSeconds(900)), IDENTITY);
+        cache.put(jwt(Instant.now().plusSeconds(300)), IDENTITY);
+
+        Duration ttl = ttls.get("validated-user:" + TenantContext.require() + ":user-1");
+        assertTrue(ttl.compareTo(Duration.ofSeconds(895)) >= 0, "ttl " + ttl + " should cover the later token");
+    }
+
+    @Test
+    void unparseableEntryIsAMiss() {
+        String token = jwt(Instant.now().plusSeconds(600));
+        cache.put(token, IDENTITY);
+        // An entry from before the role was cached
+        values.replaceAll((key, value) -> "1");
+
+        assertEquals(Optional.empty(), cache.get(token));
+    }
+
+    @Test
+    void redisFailuresAreMisses() {
+        String token = jwt(Instant.now().plusSeconds(600));
+        doThrow(new RedisConnectionFailureException("down")).when(ops).get(anyString());
+        doThrow(new RedisConnectionFailureException("down")).when(ops).set(anyString(), anyString(), any(Duration.class));
+
+        cache.put(token, IDENTITY);
+
+        assertEquals(Optional.empty(), cache.get(token));
+    }
+
+    // Only the payload is read, so the header and signature can be anything.
+    private static String jwt(Instant exp) {
+        return encode("{\"sub\":\"user-1\",\"exp\":" + exp.getEpochSecond() + "}");
+    }
+
+    private static String encode(String payload) {
+        Base64.Encoder encoder = Base64.getUrlEncoder().withoutPadding();
+        return encoder.encodeToString("{\"alg\":\"HS256\"}".getBytes(StandardCharsets.UTF_8)) + "."
+                + encoder.encodeToString(payload.getBytes(StandardCharsets.UTF_8)) + ".signature";
+    }
+}
@@ -0,0 +1,41 @@
+package com.kubesec.transaction.config;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class CorsEnvironmentPostProcessorTest {
+
+    @Test
+    void allowsAnyOriginByDefaultOutsideProduction() {
+        MockEnvironment environment = new MockEnvironment();
+
+        new CorsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("*", environment.getProperty("CORS_ALLOWED_ORIGINS"));
+    }
+
+    @Test
+    void productionRequiresAnExplicitList() {
+        MockEnvironment unset = new MockEnvironment().withProperty("ENV", "production");
+        MockEnvironment wildcard = new MockEnvironment().withProperty("ENV", "production")
+                .withProperty("CORS_ALLOWED_ORIGINS", "https://app.kubesec.example, *");
+
+        assertThrows(IllegalStateException.class,
+                () -> new CorsEnvironmentPostProcessor().postProcessEnvironment(unset, null));
+        assertThrows(IllegalStateException.class,
+                () -> new CorsEnvironmentPostProcessor().postProcessEnvironment(wildcard, null));
+    }
+
+    @Test
+    void productionKeepsTheConfiguredOrigins() {
+        MockEnvironment environment = new MockEnvironment().withProperty("ENV", "production")
+                .withProperty("CORS_ALLOWED_ORIGINS", "https://app.kubesec.example");
+
+        new CorsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("https://app.kubesec.example", environment.getProperty("CORS_ALLOWED_ORIGINS"));
+    }
+}
@@ -0,0 +1,91 @@
+package com.kubesec.transaction.config;
+
+import io.nats.client.JetStreamManagement;
+import io.nats.client.api.RetentionPolicy;
+import io.nats.client.api.StreamConfiguration;
+import io.nats.client.api.StreamInfo;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+
+import java.time.Duration;
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+// Checks the TRANSACTIONS stream setup against a mocked JetStreamManagement.
+class NatsConfigTest {
+
+    private JetStreamManagement jsm;
+
+    @BeforeEach
+    void setUp() {
+        jsm = mock(JetStreamManagement.class);
+    }
+
+    @Test
+    void missingStreamIsCreatedAsAWorkQueue() throws Exception {
+        when(jsm.getStreamNames()).thenReturn(List.of("OTHER"));
+
+        NatsConfig.ensureStream(jsm);
+
+        ArgumentCaptor<StreamConfiguration> created = ArgumentCaptor.forClass(StreamConfiguration.class);
+        verify(jsm).addStream(created.capture());
+        assertEquals("TRANSACTIONS", created.getValue().getName());
+        assertEquals(RetentionPolicy.WorkQueue, created.getValue().getRetentionPolicy());
+        assertEquals(NatsConfig.SUBJECTS, created.getValue().getSubjects());
+        assertEquals(Duration.ofDays(7), created.getValue().getMaxAge());
+    }
+
+    @Test
+    void matchingStreamIsLeftAlone() throws Exception {
+        existing(RetentionPolicy.WorkQueue, NatsConfig.SUBJECTS, NatsConfig.MAX_AGE);
+
+        NatsConfig.ensureStream(jsm);
+
+        verify(jsm, never()).addStream(any());
+        verify(jsm, never()).updateStream(any());
+    }
+
+    @Test
+    void olderStreamGetsTheMissingSubjectsAndAgeLimit() throws Exception {
+        existing(RetentionPolicy.WorkQueue, List.of("transactions.completed.*"), Duration.ZERO);
+
+        NatsConfig.ensureStream(jsm);
+
+        ArgumentCaptor<StreamConfiguration> updated = ArgumentCaptor.forClass(StreamConfiguration.class);
+        verify(jsm).updateStream(updated.capture());
+        assertEquals(NatsConfig.SUBJECTS, updated.getValue().getSubjects());
+        assertEquals(NatsConfig.MAX_AGE, updated.getValue().getMaxAge());
+        assertEquals(RetentionPolicy.WorkQueue, updated.getValue().getRetentionPolicy());
+    }
+
+    @Test
+    void streamWithAnotherRetentionFailsStartup() throws Exception {
+        existing(RetentionPolicy.Limits, NatsConfig.SUBJECTS, NatsConfig.MAX_AGE);
+
+        IllegalStateException e = assertThrows(IllegalStateException.class, () -> NatsConfig.ensureStream(jsm));
+
+        assertTrue(e.getMessage().contains(RetentionPolicy.Limits.toString()), e.getMessage());
+        verify(jsm, never()).updateStream(any());
+    }
+
+    private void existing(RetentionPolicy retention, List<String> subjects, Duration maxAge) throws Exception {
+        StreamInfo info = mock(StreamInfo.class);
+        when(info.getConfiguration()).thenReturn(StreamConfiguration.builder()
+                .name("TRANSACTIONS")
+                .subjects(subjects)
+                .retentionPolicy(retention)
+                .maxAge(maxAge)
+                .build());
+        when(jsm.getStreamNames()).thenReturn(List.of("TRANSACTIONS"));
+        when(jsm.getStreamInfo("TRANSACTIONS")).thenReturn(info);
+    }
+}
@@ -0,0 +1,90 @@
+package com.kubesec.transaction.config;
+
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.springframework.boot.autoconfigure.data.redis.RedisProperties;
+import org.springframework.boot.context.properties.bind.Binder;
+import org.springframework.mock.env.MockEnvironment;
+
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class RedisModeEnvironmentPostProcessorTest {
+
+    @Test
+    void singleIsTheDefault() {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_CLUSTER_ADDRS", "redis-0:6379");
+
+        new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(redisProperties(environment).getCluster());
+    }
+
+    @ParameterizedTest
+    @ValueSource(strings = {"single", "SINGLE"})
+    void singleModeIgnoresTheClusterAddresses(String mode) {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_MODE", mode)
+                .withProperty("REDIS_CLUSTER_ADDRS", "redis-0:6379");
+
+        new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(environment.getProperty("spring.data.redis.cluster.nodes"));
+        assertNull(redisProperties(environment).getCluster());
+    }
+
+    @ParameterizedTest
+    @ValueSource(strings = {"cluster", "Cluster"})
+    void clusterModeSeedsLettuceWithTheClusterNodes(String mode) {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_MODE", mode)
+                .withProperty("REDIS_CLUSTER_ADDRS", "redis-0:6379, redis-1:6379 ,redis-2:6379");
+
+        new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals(List.of("redis-0:6379", "redis-1:6379", "redis-2:6379"),
+                redisProperties(environment).getCluster().getNodes());
+    }
+
+    @Test
+    void clusterAddressesOverrideNodesConfiguredElsewhere() {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_MODE", "cluster")
+                .withProperty("REDIS_CLUSTER_ADDRS", "redis-0:6379")
+                .withProperty("spring.data.redis.cluster.nodes", "stale:6379");
+
+        new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals(List.of("redis-0:6379"), redisProperties(environment).getCluster().getNodes());
+    }
+
+    @ParameterizedTest
+    @ValueSource(strings = {"", "  "})
+    void clusterModeRequiresAddresses(String addrs) {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_MODE", "cluster")
+                .withProperty("REDIS_CLUSTER_ADDRS", addrs);
+
+        IllegalStateException e = assertThrows(IllegalStateException.class,
+                () -> new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null));
+        assertEquals("REDIS_CLUSTER_ADDRS is required when REDIS_MODE=cluster", e.getMessage());
+
+        MockEnvironment unset = new MockEnvironment().withProperty("REDIS_MODE", "cluster");
+        assertThrows(IllegalStateException.class,
+                () -> new RedisModeEnvironmentPostProcessor().postProcessEnvironment(unset, null));
+    }
+
+    @Test
+    void unknownModeIsRejected() {
+        MockEnvironment environment = new MockEnvironment().withProperty("REDIS_MODE", "sentinel");
+
+        IllegalStateException e = assertThrows(IllegalStateException.class,
+                () -> new RedisModeEnvironmentPostProcessor().postProcessEnvironment(environment, null));
+        assertEquals("REDIS_MODE must be single or cluster, got sentinel", e.getMessage());
+    }
+
+    private static RedisProperties redisProperties(MockEnvironment environment) {
+        return Binder.get(environment).bind("spring.data.redis", RedisProperties.class)
+                .orElseGet(RedisProperties::new);
+    }
+}
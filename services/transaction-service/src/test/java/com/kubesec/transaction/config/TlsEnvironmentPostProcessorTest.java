@@ -0,0 +1,64 @@
+package com.kubesec.transaction.config;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class TlsEnvironmentPostProcessorTest {
+
+    @Test
+    void plainHttpWhenNothingIsConfigured() {
+        MockEnvironment environment = new MockEnvironment();
+
+        new TlsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(environment.getProperty("server.ssl.bundle"));
+        assertNull(environment.getProperty("server.ssl.enabled-protocols"));
+    }
+
+    @Test
+    void certAndKeyServeHttpsWithReloadAndRestrictedCiphers() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("TLS_CERT_FILE", "/etc/tls/tls.crt")
+                .withProperty("TLS_KEY_FILE", "/etc/tls/tls.key");
+
+        new TlsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("tls", environment.getProperty("server.ssl.bundle"));
+        assertEquals("file:/etc/tls/tls.crt", environment.getProperty("spring.ssl.bundle.pem.tls.keystore.certificate"));
+        assertEquals("true", environment.getProperty("spring.ssl.bundle.pem.tls.reload-on-update"));
+        assertEquals("TLSv1.3,TLSv1.2", environment.getProperty("server.ssl.enabled-protocols"));
+        String ciphers = environment.getProperty("server.ssl.ciphers");
+        assertFalse(ciphers.contains("RC4") || ciphers.contains("3DES") || ciphers.contains("CBC"), ciphers);
+    }
+
+    @Test
+    void mtlsBundleTakesPrecedence() {
+        MockEnvironment environment = new MockEnvironment()
+                .withProperty("TLS_CERT_FILE", "/etc/tls/tls.crt")
+                .withProperty("TLS_KEY_FILE", "/etc/tls/tls.key")
+                .withProperty("MTLS_CA_CERT_FILE", "/etc/mtls/ca.crt")
+                .withProperty("MTLS_SERVER_CERT_FILE", "/etc/mtls/tls.crt")
+                .withProperty("MTLS_SERVER_KEY_FILE", "/etc/mtls/tls.key");
+
+        new MtlsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+        new TlsEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("mtls", environment.getProperty("server.ssl.bundle"));
+        assertNull(environment.getProperty("spring.ssl.bundle.pem.tls.keystore.certificate"));
+        assertEquals("true", environment.getProperty("spring.ssl.bundle.pem.mtls.reload-on-update"));
+        assertEquals("TLSv1.3,TLSv1.2", environment.getProperty("server.ssl.enabled-protocols"));
+    }
+
+    @Test
+    void certWithoutKeyFailsStartup() {
+        MockEnvironment environment = new MockEnvironment().withProperty("TLS_CERT_FILE", "/etc/tls/tls.crt");
+
+        assertThrows(IllegalStateException.class,
+                () -> new TlsEnvironmentPostProcessor().postProcessEnvironment(environment, null));
+    }
+}
@@ -0,0 +1,31 @@
+package com.kubesec.transaction.config;
+
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.ValueSource;
+import org.springframework.mock.env.MockEnvironment;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+
+class TracingEnvironmentPostProcessorTest {
+
+    @ParameterizedTest
+    @ValueSource(strings = {"http://collector:4318", "http://collector:4318/", "http://collector:4318/v1/traces"})
+    void pointsTheExporterAtTheTracesPath(String endpoint) {
+        MockEnvironment environment = new MockEnvironment().withProperty("OTEL_EXPORTER_OTLP_ENDPOINT", endpoint);
+
+        new TracingEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertEquals("http://collector:4318/v1/traces", environment.getProperty("management.otlp.tracing.endpoint"));
+    }
+
+    @Test
+    void leavesExportOffWithoutAnEndpoint() {
+        MockEnvironment environment = new MockEnvironment();
+
+        new TracingEnvironmentPostProcessor().postProcessEnvironment(environment, null);
+
+        assertNull(environment.getProperty("management.otlp.tracing.endpoint"));
+    }
+}
@@ -0,0 +1,214 @@
+package com.kubesec.transaction.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.GlobalExceptionHandler;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.filter.AdminApiKeyFilter;
+import com.kubesec.transaction.filter.TenantFilter;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.service.FxRateService;
+import com.kubesec.transaction.service.NatsPublisher;
+import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.testdoubles.InMemoryTenantRepository;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import io.nats.client.JetStream;
+import io.nats.client.api.PublishAck;
+import io.nats.client.impl.Headers;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+import org.springframework.http.client.SimpleClientHttpRequestFactory;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.HashSet;
+import java.util.List;
+import java.util.Set;
+import java.util.UUID;
+import java.util.concurrent.CompletableFuture;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.Mockito.atLeastOnce;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.delete;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the admin replay and soft-delete endpoints through the admin key and tenant
+// filters, with JetStream mocked so the published message ids can be inspected.
+class AdminControllerFlowTest {
+
+    private static final String ADMIN_KEY = "admin-secret";
+    private static final OffsetDateTime FROM = OffsetDateTime.of(2024, 3, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+    private static final OffsetDateTime TO = FROM.plusDays(1);
+
+    private UUID tenantId;
+    private InMemoryTransactionRepository repository;
+    private JetStream jetStream;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+
+        AppConfig config = new AppConfig();
+        config.setAdminApiKey(ADMIN_KEY);
+
+        jetStream = mock(JetStream.class);
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class)))
+                .thenReturn(CompletableFuture.completedFuture(mock(PublishAck.class)));
+        NatsPublisher publisher = new NatsPublisher(jetStream, new ObjectMapper().findAndRegisterModules(), config);
+
+        repository = new InMemoryTransactionRepository();
+        mvc = MockMvcBuilders.standaloneSetup(new AdminController(service(config, publisher)))
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .addFilters(new AdminApiKeyFilter(config), new TenantFilter(tenants))
+                .build();
+    }
+
+    @Test
+    void replayPublishesCompletedTransactionsInsideTheRangeOnly() throws Exception {
+        UUID inside = deposit(FROM, "completed");
+        UUID later = deposit(FROM.plusHours(6), "completed");
+        deposit(FROM.minusSeconds(1), "completed");
+        deposit(TO, "completed");
+        deposit(FROM.plusHours(1), "failed");
+
+        replay(ADMIN_KEY).andExpect(status().isOk())
+                .andExpect(jsonPath("$.replayed").value(2));
+
+        assertEquals(List.of(inside.toString(), later.toString()),
+                publishedMessageIds().stream().map(id -> id.substring(0, id.indexOf(':'))).toList());
+    }
+
+    @Test
+    void replayPagesThroughRangesLargerThanOnePage() throws Exception {
+        // More than two pages, several rows sharing a created_at so the cursor has to break ties on id
+        Set<String> expected = new HashSet<>();
+        for (int i = 0; i < 1201; i++) {
+            UUID id = deposit(FROM.plusSeconds(i / 3), "completed");
+            expected.add(id.toString());
+        }
+
+        replay(ADMIN_KEY).andExpect(status().isOk())
+                .andExpect(jsonPath("$.replayed").value(1201));
+
+        List<String> published = publishedMessageIds();
+        assertEquals(1201, published.size());
+        Set<String> transactionIds = new HashSet<>();
+        published.forEach(id -> transactionIds.add(id.substring(0, id.indexOf(':'))));
+        assertEquals(expected, transactionIds, "each transaction is published exactly once");
+    }
+
+    @Test
+    void replayingTheSameRangeReusesTheMessageIds() throws Exception {
+        deposit(FROM.plusHours(1), "completed");
+        deposit(FROM.plusHours(2), "completed");
+
+        replay(ADMIN_KEY).andExpect(status().isOk());
+        replay(ADMIN_KEY).andExpect(status().isOk());
+
+        // JetStream drops the second run's messages inside its duplicate window
+        List<String> ids = publishedMessageIds();
+        assertEquals(4, ids.size());
+        assertEquals(ids.subList(0, 2), ids.subList(2, 4));
+    }
+
+    @Test
+    void replayRejectsAnEmptyRange() throws Exception {
+        mvc.perform(post("/admin/transactions/replay")
+                        .header("X-Admin-Api-Key", ADMIN_KEY)
+                        .param("from", TO.toString())
+                        .param("to", FROM.toString()))
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.code").value("validation_error"));
+    }
+
+    @Test
+    void adminRoutesRequireTheAdminKey() throws Exception {
+        deposit(FROM.plusHours(1), "completed");
+
+        mvc.perform(post("/admin/transactions/replay")
+                        .param("from", FROM.toString())
+                        .param("to", TO.toString()))
+                .andExpect(status().isUnauthorized())
+                .andExpect(jsonPath("$.error").value("invalid admin api key"));
+        replay("wrong-key").andExpect(status().isUnauthorized());
+        mvc.perform(delete("/admin/transactions/" + UUID.randomUUID())
+                        .header("X-Admin-Api-Key", "wrong-key")
+                        .header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isUnauthorized());
+
+        verify(jetStream, never()).publishAsync(anyString(), any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void unsetAdminKeyDisablesTheAdminApi() throws Exception {
+        AppConfig config = new AppConfig();
+        MockMvc unconfigured = MockMvcBuilders.standaloneSetup(new AdminController(service(config, null)))
+                .addFilters(new AdminApiKeyFilter(config))
+                .build();
+
+        unconfigured.perform(post("/admin/transactions/replay")
+                        .header("X-Admin-Api-Key", "")
+                        .param("from", FROM.toString())
+                        .param("to", TO.toString()))
+                .andExpect(status().isUnauthorized());
+    }
+
+    @Test
+    void softDeleteOfAnUnknownTransactionIsNotFound() throws Exception {
+        mvc.perform(delete("/admin/transactions/" + UUID.randomUUID())
+                        .header("X-Admin-Api-Key", ADMIN_KEY)
+                        .header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isNotFound());
+    }
+
+    // account-service is never called by these endpoints, so the client points nowhere
+    private TransactionService service(AppConfig config, NatsPublisher publisher) {
+        return TransactionService.builder()
+                .withRepository(repository)
+                .withAccountClient(new AccountServiceClient(config, RestClient.builder(),
+                        new SimpleClientHttpRequestFactory(), null))
+                .withFxRateService(new FxRateService(config))
+                .withFeeCalculator(new FlatFeeCalculator(BigDecimal.ZERO))
+                .withNatsPublisher(publisher)
+                .build();
+    }
+
+    private ResultActions replay(String adminKey) throws Exception {
+        return mvc.perform(post("/admin/transactions/replay")
+                .header("X-Admin-Api-Key", adminKey)
+                .param("from", FROM.toString())
+                .param("to", TO.toString()));
+    }
+
+    private List<String> publishedMessageIds() {
+        ArgumentCaptor<Headers> headers = ArgumentCaptor.forClass(Headers.class);
+        verify(jetStream, atLeastOnce()).publishAsync(anyString(), headers.capture(), any(byte[].class));
+        return headers.getAllValues().stream().map(h -> h.getFirst("Nats-Msg-Id")).toList();
+    }
+
+    private UUID deposit(OffsetDateTime createdAt, String status) {
+        Transaction txn = new Transaction(UUID.randomUUID(), null, UUID.randomUUID(), new BigDecimal("10.00"), "USD",
+                "deposit", status, "", createdAt, createdAt);
+        txn.setTenantId(tenantId);
+        repository.create(txn);
+        return txn.getId();
+    }
+}
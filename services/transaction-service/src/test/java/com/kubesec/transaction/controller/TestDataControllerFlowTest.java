@@ -0,0 +1,148 @@
+package com.kubesec.transaction.controller;
+
+import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.GlobalExceptionHandler;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.filter.TenantFilter;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.service.FxRateService;
+import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.testdoubles.InMemoryTenantRepository;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.client.SimpleClientHttpRequestFactory;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.delete;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the test-data cleanup endpoint through the tenant filter, with the switch
+// that exposes it both off and on.
+class TestDataControllerFlowTest {
+
+    private InMemoryTenantRepository tenants;
+    private UUID tenantId;
+    private InMemoryTransactionRepository repository;
+    private AppConfig config;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+        repository = new InMemoryTransactionRepository();
+        config = new AppConfig();
+
+        // account-service is never called by this endpoint, so the client points nowhere
+        TransactionService service = TransactionService.builder()
+                .withRepository(repository)
+                .withAccountClient(new AccountServiceClient(config, RestClient.builder(),
+                        new SimpleClientHttpRequestFactory(), null))
+                .withFxRateService(new FxRateService(config))
+                .withFeeCalculator(new FlatFeeCalculator(BigDecimal.ZERO))
+                .build();
+        mvc = MockMvcBuilders.standaloneSetup(new TestDataController(service, config))
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .addFilters(new TenantFilter(tenants))
+                .build();
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void routeDoesNotExistUnlessEnabled() throws Exception {
+        UUID id = deposit(tenantId);
+
+        deleteTransaction(tenantId, id).andExpect(status().isNotFound())
+                .andExpect(jsonPath("$.error").value("not found"));
+
+        assertTrue(exists(tenantId, id), "nothing is deleted while the endpoint is off");
+    }
+
+    @Test
+    void enabledRouteDeletesTheTransaction() throws Exception {
+        config.setEnableTestEndpoints(true);
+        UUID id = deposit(tenantId);
+        UUID other = deposit(tenantId);
+
+        deleteTransaction(tenantId, id).andExpect(status().isNoContent());
+
+        assertFalse(exists(tenantId, id));
+        assertTrue(exists(tenantId, other));
+        TenantContext.set(tenantId);
+        assertTrue(repository.getTransactionEventHistory(id).isEmpty(), "its history goes with it");
+    }
+
+    @Test
+    void deletingTwiceOrAnUnknownIdIsNotFound() throws Exception {
+        config.setEnableTestEndpoints(true);
+        UUID id = deposit(tenantId);
+
+        deleteTransaction(tenantId, id).andExpect(status().isNoContent());
+        deleteTransaction(tenantId, id).andExpect(status().isNotFound())
+                .andExpect(jsonPath("$.error").value("transaction not found"));
+        deleteTransaction(tenantId, UUID.randomUUID()).andExpect(status().isNotFound());
+    }
+
+    @Test
+    void anotherTenantsTransactionIsNotFoundAndKept() throws Exception {
+        config.setEnableTestEndpoints(true);
+        UUID otherTenant = tenants.addTenant(true);
+        UUID id = deposit(otherTenant);
+
+        deleteTransaction(tenantId, id).andExpect(status().isNotFound());
+
+        assertTrue(exists(otherTenant, id));
+    }
+
+    @Test
+    void requestWithoutATenantIsRejected() throws Exception {
+        config.setEnableTestEndpoints(true);
+        UUID id = deposit(tenantId);
+
+        mvc.perform(delete("/transactions/" + id))
+                .andExpect(status().isBadRequest());
+
+        assertTrue(exists(tenantId, id));
+    }
+
+    private ResultActions deleteTransaction(UUID tenant, UUID id) throws Exception {
+        return mvc.perform(delete("/transactions/" + id).header(TenantContext.HEADER, tenant.toString()));
+    }
+
+    private UUID deposit(UUID tenant) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Transaction txn = new Transaction(UUID.randomUUID(), null, UUID.randomUUID(), new BigDecimal("10.00"), "USD",
+                "deposit", "completed", "", now, now);
+        txn.setTenantId(tenant);
+        repository.create(txn);
+        return txn.getId();
+    }
+
+    private boolean exists(UUID tenant, UUID id) {
+        TenantContext.set(tenant);
+        try {
+            return repository.getById(id).isPresent();
+        } finally {
+            TenantContext.clear();
+        }
+    }
+}
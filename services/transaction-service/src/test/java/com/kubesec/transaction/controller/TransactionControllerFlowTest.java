@@ -0,0 +1,929 @@
+package com.kubesec.transaction.controller;
+
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.GlobalExceptionHandler;
+import com.kubesec.transaction.fees.Fee;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.model.Cursor;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.filter.AuthFilter;
+import com.kubesec.transaction.filter.TenantFilter;
+import com.kubesec.transaction.service.FxRateService;
+import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.testdoubles.InMemoryTenantRepository;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import com.kubesec.transaction.validation.SchemaValidationAdvice;
+import com.kubesec.transaction.validation.SchemaValidator;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.HttpStatus;
+import org.springframework.http.MediaType;
+import org.springframework.http.client.SimpleClientHttpRequestFactory;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.request.MockHttpServletRequestBuilder;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.transaction.support.TransactionSynchronization;
+import org.springframework.transaction.support.TransactionSynchronizationManager;
+import org.springframework.web.client.HttpClientErrorException;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+import java.util.concurrent.CompletionService;
+import java.util.concurrent.ConcurrentHashMap;
+import java.util.concurrent.CountDownLatch;
+import java.util.concurrent.ExecutorCompletionService;
+import java.util.concurrent.ExecutorService;
+import java.util.concurrent.Executors;
+import java.util.concurrent.TimeUnit;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.header;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the transfer, batch, deposit, withdrawal, lookup and history handlers through
+// the tenant filter with no database, NATS or account-service behind them.
+class TransactionControllerFlowTest {
+
+    private static final String SENDER = "user-1";
+    private static final String RECIPIENT = "user-2";
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private final UUID from = UUID.randomUUID();
+    private final UUID to = UUID.randomUUID();
+
+    private UUID tenantId;
+    private InMemoryTransactionRepository repository;
+    private StubAccountServiceClient accounts;
+    private TransactionService transactionService;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        InMemoryTenantRepository tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+
+        AppConfig config = new AppConfig();
+        config.setFxRates(Map.of("USD_EUR", new BigDecimal("0.9150")));
+        accounts = new StubAccountServiceClient(config);
+        accounts.balances.put(from, new BigDecimal("100.00"));
+        accounts.balances.put(to, BigDecimal.ZERO);
+        accounts.owners.put(from, SENDER);
+        accounts.owners.put(to, RECIPIENT);
+
+        repository = new InMemoryTransactionRepository();
+        transactionService = TransactionService.builder()
+                .withRepository(repository)
+                .withAccountClient(accounts)
+                .withFxRateService(new FxRateService(config))
+                .withFeeCalculator(new FlatFeeCalculator(new BigDecimal("0.01")))
+                .build();
+
+        mvc = MockMvcBuilders.standaloneSetup(new TransactionController(transactionService))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .addFilters(new TenantFilter(tenants))
+                .build();
+
+        // Deposits register an after-commit hook, which needs synchronization active
+        TransactionSynchronizationManager.initSynchronization();
+    }
+
+    @AfterEach
+    void tearDown() {
+        TransactionSynchronizationManager.clearSynchronization();
+    }
+
+    @Test
+    void capturedTransferIsCompletedAndRecordedInHistory() throws Exception {
+        String body = transfer("50.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.status").value("authorized"))
+                .andExpect(jsonPath("$.fee_amount").value(0.50))
+                .andExpect(jsonPath("$.authorized_at").exists())
+                .andExpect(jsonPath("$.authorized_hold_expires_at").exists())
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        action(id, "capture").andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("completed"));
+        action(id, "capture").andExpect(status().isConflict());
+        action(id, "void").andExpect(status().isConflict());
+
+        mvc.perform(get("/transactions/" + id).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("completed"));
+
+        mvc.perform(get("/transactions/" + id + "/history").header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.events.length()").value(2))
+                .andExpect(jsonPath("$.events[0].to_status").value("authorized"))
+                .andExpect(jsonPath("$.events[1].from_status").value("authorized"))
+                .andExpect(jsonPath("$.events[1].to_status").value("completed"));
+
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("account_id", to.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.transactions.length()").value(1));
+    }
+
+    @Test
+    void authorizedAmountIsHeldUntilVoided() throws Exception {
+        String body = transfer("60.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        // 60.60 of the 100.00 balance is held, so a second 60.00 transfer can't be covered
+        transfer("60.00").andExpect(status().isUnprocessableEntity());
+
+        action(id, "void").andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("voided"));
+        action(id, "capture").andExpect(status().isConflict());
+
+        transfer("60.00").andExpect(status().isCreated());
+    }
+
+    @Test
+    void expiredAuthorizationsAreVoided() throws Exception {
+        String body = transfer("60.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        // Not yet expired
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        assertTrue(transactionService.listExpiredAuthorizations(now, 10).isEmpty());
+
+        List<Transaction> expired = transactionService.listExpiredAuthorizations(now.plusHours(25), 10);
+        assertEquals(1, expired.size());
+        assertTrue(transactionService.expireAuthorization(expired.get(0)));
+        assertFalse(transactionService.expireAuthorization(expired.get(0)));
+
+        mvc.perform(get("/transactions/" + id + "/history").header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.events[1].to_status").value("voided"))
+                .andExpect(jsonPath("$.events[1].reason").value("authorization expired"));
+        action(id, "capture").andExpect(status().isConflict());
+        transfer("60.00").andExpect(status().isCreated());
+    }
+
+    @Test
+    void foreignUserCannotCaptureOrVoidTheSendersHold() throws Exception {
+        String body = transfer("50.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        for (String action : List.of("capture", "void")) {
+            action(id, action, "user-2", null).andExpect(status().isForbidden())
+                    .andExpect(jsonPath("$.code").value("forbidden"));
+            action(id, action, "user-2", "customer").andExpect(status().isForbidden());
+            action(id, action, null, null).andExpect(status().isForbidden());
+        }
+
+        assertEquals("authorized", repository.getById(UUID.fromString(id)).orElseThrow().getStatus());
+        action(id, "capture").andExpect(status().isOk());
+    }
+
+    @Test
+    void adminCanCaptureOrVoidAnyHold() throws Exception {
+        String first = objectMapper.readTree(transfer("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        String second = objectMapper.readTree(transfer("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+
+        action(first, "capture", "ops-1", "admin").andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("completed"));
+        action(second, "void", "ops-1", "admin").andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("voided"));
+    }
+
+    @Test
+    void depositsAreCappedAtTheDailyLimit() throws Exception {
+        accounts.depositLimits.put(to, new BigDecimal("100.00"));
+
+        deposit("60.00").andExpect(status().isCreated());
+        deposit("40.00").andExpect(status().isCreated());
+        deposit("0.01").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.error").value("daily deposit limit exceeded"))
+                .andExpect(jsonPath("$.limit").value("100.00"))
+                .andExpect(jsonPath("$.current").value("100.00"));
+
+        assertEquals(new BigDecimal("100.00"), accounts.balances.get(to));
+    }
+
+    @Test
+    void depositLimitOnlyCountsToday() throws Exception {
+        accounts.depositLimits.put(to, new BigDecimal("100.00"));
+        OffsetDateTime yesterday = OffsetDateTime.now(ZoneOffset.UTC).minusDays(1);
+        Transaction earlier = new Transaction(UUID.randomUUID(), null, to, new BigDecimal("100.00"), "USD",
+                "deposit", "completed", "", yesterday, yesterday);
+        earlier.setTenantId(tenantId);
+        repository.create(earlier);
+
+        deposit("100.00").andExpect(status().isCreated());
+        deposit("100.01").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.current").value("100.00"));
+    }
+
+    @Test
+    void depositsAreUnlimitedWithoutALimit() throws Exception {
+        deposit("1000000.00").andExpect(status().isCreated());
+    }
+
+    @Test
+    void depositsRequireATellerOrAdminRole() throws Exception {
+        deposit("10.00", null).andExpect(status().isForbidden());
+        deposit("10.00", "customer").andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.code").value("forbidden"));
+        // A role header from the client is not a role.
+        mvc.perform(post("/transactions/deposit")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .header("X-User-Role", "teller")
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"account_id\":\"" + to + "\",\"amount\":10.00,\"currency\":\"USD\"}"))
+                .andExpect(status().isForbidden());
+        assertEquals(0, BigDecimal.ZERO.compareTo(accounts.balances.get(to)));
+
+        deposit("10.00", "teller").andExpect(status().isCreated());
+        deposit("10.00", "admin").andExpect(status().isCreated());
+        assertEquals(0, new BigDecimal("20.00").compareTo(accounts.balances.get(to)));
+    }
+
+    @Test
+    void committedDepositKeepsItsCredit() throws Exception {
+        String id = objectMapper.readTree(deposit("25.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+
+        completeTransaction(TransactionSynchronization.STATUS_COMMITTED);
+
+        assertEquals(0, new BigDecimal("25.00").compareTo(accounts.balances.get(to)));
+        mvc.perform(get("/transactions/" + id).header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.status").value("completed"));
+    }
+
+    @Test
+    void rolledBackDepositIsCreditedBack() throws Exception {
+        deposit("25.00").andExpect(status().isCreated());
+        assertEquals(0, new BigDecimal("25.00").compareTo(accounts.balances.get(to)));
+
+        // The row's commit failed after account-service applied the credit.
+        completeTransaction(TransactionSynchronization.STATUS_ROLLED_BACK);
+
+        assertEquals(0, BigDecimal.ZERO.compareTo(accounts.balances.get(to)));
+    }
+
+    @Test
+    void depositRejectedByAccountServiceIsNotCredited() throws Exception {
+        accounts.rejectAdjustments = true;
+
+        deposit("25.00").andExpect(status().isConflict());
+
+        // Nothing was applied remotely, so there is nothing to compensate.
+        assertTrue(TransactionSynchronizationManager.getSynchronizations().isEmpty());
+        assertEquals(0, BigDecimal.ZERO.compareTo(accounts.balances.get(to)));
+    }
+
+    @Test
+    void withdrawalIsDeductedAndCannotExceedTheAvailableBalance() throws Exception {
+        withdraw("30.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.type").value("withdrawal"))
+                .andExpect(jsonPath("$.status").value("completed"))
+                .andExpect(jsonPath("$.from_account_id").value(from.toString()));
+        assertEquals(0, new BigDecimal("70.00").compareTo(accounts.balances.get(from)));
+
+        // 50.50 of what remains is held by the authorized transfer.
+        transfer("50.00").andExpect(status().isCreated());
+        withdraw("20.00").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("insufficient_balance"));
+        withdraw("19.50").andExpect(status().isCreated());
+    }
+
+    @Test
+    void onlyTheOwnerCanWithdrawFromAnAccount() throws Exception {
+        withdraw("30.00", RECIPIENT).andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.code").value("forbidden"));
+        withdraw("30.00", null).andExpect(status().isForbidden());
+
+        assertEquals(0, new BigDecimal("100.00").compareTo(accounts.balances.get(from)));
+        TenantContext.set(tenantId);
+        try {
+            assertEquals(0, repository.list(new TransactionFilter()).size());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    @Test
+    void withdrawalMustBeInTheAccountCurrency() throws Exception {
+        mvc.perform(post("/transactions/withdrawal")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .requestAttr(AuthFilter.USER_ID_ATTRIBUTE, SENDER)
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"account_id\":\"" + from + "\",\"amount\":10.00,\"currency\":\"EUR\"}"))
+                .andExpect(status().isBadRequest());
+        withdraw("0").andExpect(status().isUnprocessableEntity());
+    }
+
+    @Test
+    void batchIsAuthorizedTogetherUnderOneBatchId() throws Exception {
+        UUID other = UUID.randomUUID();
+        String body = batch(transferJson(from, to, "40.00"), transferJson(from, other, "50.00"))
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.transactions.length()").value(2))
+                .andExpect(jsonPath("$.transactions[0].status").value("authorized"))
+                .andReturn().getResponse().getContentAsString();
+        JsonNode batch = objectMapper.readTree(body);
+        String batchId = batch.get("batch_id").asText();
+        assertEquals(batchId, batch.get("transactions").get(0).get("batch_id").asText());
+        assertEquals(batchId, batch.get("transactions").get(1).get("batch_id").asText());
+
+        mvc.perform(get("/transactions/" + batch.get("transactions").get(1).get("id").asText())
+                        .header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.batch_id").value(batchId));
+    }
+
+    @Test
+    void batchIsRejectedWholeWhenTheSenderCannotCoverIt() throws Exception {
+        // Each transfer fits on its own, but 100.00 plus 1.00 in fees does not.
+        batch(transferJson(from, to, "60.00"), transferJson(from, UUID.randomUUID(), "40.00"))
+                .andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("insufficient_balance"));
+        batch(transferJson(from, to, "10.00"), transferJson(to, to, "1.00"))
+                .andExpect(status().isBadRequest());
+
+        TenantContext.set(tenantId);
+        try {
+            assertEquals(0, repository.list(new TransactionFilter()).size());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    @Test
+    void batchIsLimitedToOneHundredTransfers() throws Exception {
+        String[] transfers = new String[101];
+        Arrays.fill(transfers, transferJson(from, to, "0.10"));
+
+        batch(transfers).andExpect(status().isUnprocessableEntity());
+        batch().andExpect(status().isUnprocessableEntity());
+    }
+
+    @Test
+    void transferIncludingFeeMustBeCovered() throws Exception {
+        transfer("99.50").andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("insufficient_balance"));
+    }
+
+    @Test
+    void feeScheduleForSenderTierOverridesFlatRate() throws Exception {
+        OffsetDateTime lastYear = OffsetDateTime.now(ZoneOffset.UTC).minusYears(1);
+        repository.addFeeSchedule(new InMemoryTransactionRepository.FeeScheduleRow("checking", "transfer",
+                new BigDecimal("0.00"), new BigDecimal("20.00"), new Fee(Fee.FLAT, new BigDecimal("0.30")),
+                lastYear, null));
+
+        transfer("20.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.fee_amount").value(0.30));
+        // Above the scheduled band the flat 1% rate still applies.
+        transfer("30.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.fee_amount").value(0.30));
+        transfer("40.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.fee_amount").value(0.40));
+    }
+
+    @Test
+    void crossCurrencyTransferRecordsTheConvertedAmount() throws Exception {
+        accounts.currencies.put(to, "EUR");
+
+        transfer("40.00").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.amount").value(40.00))
+                .andExpect(jsonPath("$.currency").value("USD"))
+                .andExpect(jsonPath("$.fx_rate").value(0.9150))
+                .andExpect(jsonPath("$.converted_amount").value(36.60))
+                .andExpect(jsonPath("$.converted_currency").value("EUR"));
+        // 33.33 * 0.915 = 30.49695, rounded half-even to cents
+        transfer("33.33").andExpect(status().isCreated())
+                .andExpect(jsonPath("$.converted_amount").value(30.50));
+    }
+
+    @Test
+    void transferMustBeInTheSendersCurrency() throws Exception {
+        accounts.currencies.put(from, "EUR");
+
+        transfer("10.00").andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.code").value("validation_error"));
+        batch(transferJson(from, to, "10.00")).andExpect(status().isBadRequest());
+        assertEquals(0, repository.list(new TransactionFilter()).size());
+    }
+
+    @Test
+    void listingSortsByRequestedColumn() throws Exception {
+        transfer("30.00").andExpect(status().isCreated());
+        transfer("10.00").andExpect(status().isCreated());
+        transfer("20.00").andExpect(status().isCreated());
+
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("sort_by", "amount").param("sort_order", "asc"))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.sort_by").value("amount"))
+                .andExpect(jsonPath("$.sort_order").value("asc"))
+                .andExpect(jsonPath("$.transactions[0].amount").value(10.00))
+                .andExpect(jsonPath("$.transactions[2].amount").value(30.00));
+    }
+
+    @Test
+    void listingRejectsUnknownSortParameters() throws Exception {
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("sort_by", "description"))
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.code").value("validation_error"));
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("sort_order", "sideways"))
+                .andExpect(status().isBadRequest());
+    }
+
+    @Test
+    void listingFiltersByMetadata() throws Exception {
+        transfer("10.00").andExpect(status().isCreated());
+        mvc.perform(post("/transactions/transfer")
+                        .header(TenantContext.HEADER, tenantId.toString())
+                        .contentType(MediaType.APPLICATION_JSON)
+                        .content("{\"from_account_id\":\"" + from + "\",\"to_account_id\":\"" + to
+                                + "\",\"amount\":20.00,\"currency\":\"USD\",\"metadata\":{\"invoice\":\"INV-42\"}}"))
+                .andExpect(status().isCreated())
+                .andExpect(jsonPath("$.metadata.invoice").value("INV-42"));
+
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("metadata_key", "invoice").param("metadata_value", "INV-42"))
+                .andExpect(status().isOk())
+                .andExpect(jsonPath("$.transactions.length()").value(1))
+                .andExpect(jsonPath("$.transactions[0].amount").value(20.00));
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("metadata_key", "invoice"))
+                .andExpect(status().isBadRequest());
+    }
+
+    @Test
+    void reversalMirrorsACompletedTransfer() throws Exception {
+        String id = capturedTransfer("40.00");
+
+        reverse(id, RECIPIENT, null).andExpect(status().isCreated())
+                .andExpect(jsonPath("$.type").value("reversal"))
+                .andExpect(jsonPath("$.status").value("completed"))
+                .andExpect(jsonPath("$.from_account_id").value(to.toString()))
+                .andExpect(jsonPath("$.to_account_id").value(from.toString()))
+                .andExpect(jsonPath("$.amount").value(40.00))
+                .andExpect(jsonPath("$.currency").value("USD"));
+
+        // The fee stays with the bank
+        assertEquals(0, new BigDecimal("99.60").compareTo(accounts.balances.get(from)));
+        assertEquals(0, BigDecimal.ZERO.compareTo(accounts.balances.get(to)));
+        mvc.perform(get("/transactions/" + id + "/history").header(TenantContext.HEADER, tenantId.toString()))
+                .andExpect(jsonPath("$.events[2].from_status").value("completed"))
+                .andExpect(jsonPath("$.events[2].to_status").value("reversed"));
+        reverse(id, RECIPIENT, null).andExpect(status().isConflict());
+    }
+
+    @Test
+    void reversalRequiresACompletedTransfer() throws Exception {
+        String authorized = objectMapper.readTree(transfer("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        reverse(authorized, RECIPIENT, null).andExpect(status().isConflict());
+
+        String reversal = objectMapper.readTree(reverse(capturedTransfer("10.00"), RECIPIENT, null)
+                .andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        reverse(reversal, SENDER, "admin").andExpect(status().isConflict())
+                .andExpect(jsonPath("$.error").value("only transfers can be reversed"));
+    }
+
+    @Test
+    void depositsAndWithdrawalsCannotBeReversed() throws Exception {
+        String deposit = objectMapper.readTree(deposit("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+        String withdrawal = objectMapper.readTree(withdraw("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString()).get("id").asText();
+
+        for (String id : List.of(deposit, withdrawal)) {
+            reverse(id, "ops-1", "admin").andExpect(status().isConflict())
+                    .andExpect(jsonPath("$.error").value("only transfers can be reversed"));
+            assertEquals("completed", repository.getById(UUID.fromString(id)).orElseThrow().getStatus());
+        }
+        assertEquals(0, new BigDecimal("10.00").compareTo(accounts.balances.get(to)));
+        assertEquals(0, new BigDecimal("90.00").compareTo(accounts.balances.get(from)));
+    }
+
+    // The sender cannot take the money back without the recipient.
+    @Test
+    void reversalRequiresTheRecipientOrAnAdmin() throws Exception {
+        String completed = capturedTransfer("10.00");
+
+        reverse(completed, SENDER, null).andExpect(status().isForbidden())
+                .andExpect(jsonPath("$.code").value("forbidden"));
+        reverse(completed, "user-3", "customer").andExpect(status().isForbidden());
+        reverse(completed, null, null).andExpect(status().isForbidden());
+        assertEquals("completed", repository.getById(UUID.fromString(completed)).orElseThrow().getStatus());
+        assertEquals(0, new BigDecimal("10.00").compareTo(accounts.balances.get(to)));
+
+        reverse(completed, "ops-1", "admin").andExpect(status().isCreated());
+        assertEquals(0, BigDecimal.ZERO.compareTo(accounts.balances.get(to)));
+    }
+
+    @Test
+    void reversalMustBeCoveredByTheRecipient() throws Exception {
+        String id = capturedTransfer("40.00");
+        accounts.balances.put(to, new BigDecimal("39.99"));
+
+        reverse(id, RECIPIENT, null).andExpect(status().isUnprocessableEntity())
+                .andExpect(jsonPath("$.code").value("insufficient_balance"));
+
+        assertEquals("completed", repository.getById(UUID.fromString(id)).orElseThrow().getStatus());
+        assertEquals(0, new BigDecimal("39.99").compareTo(accounts.balances.get(to)));
+    }
+
+    // account-service checks the balance again under its account lock, so a debit that
+    // raced the check above is turned away there and the sender is not credited.
+    @Test
+    void reversalRejectedByAccountServiceMovesNothing() throws Exception {
+        String id = capturedTransfer("40.00");
+        accounts.rejectAdjustments = true;
+
+        reverse(id, RECIPIENT, null).andExpect(status().isConflict())
+                .andExpect(jsonPath("$.error").value("reversal rejected by account-service"));
+
+        assertTrue(TransactionSynchronizationManager.getSynchronizations().isEmpty());
+        assertEquals(0, new BigDecimal("40.00").compareTo(accounts.balances.get(to)));
+        assertEquals(0, new BigDecimal("59.60").compareTo(accounts.balances.get(from)));
+    }
+
+    @Test
+    void rolledBackReversalIsUndone() throws Exception {
+        String id = capturedTransfer("40.00");
+        reverse(id, RECIPIENT, null).andExpect(status().isCreated());
+
+        completeTransaction(TransactionSynchronization.STATUS_ROLLED_BACK);
+
+        assertEquals(0, new BigDecimal("40.00").compareTo(accounts.balances.get(to)));
+        assertEquals(0, new BigDecimal("59.60").compareTo(accounts.balances.get(from)));
+    }
+
+    @Test
+    void listingFiltersByCreationRange() throws Exception {
+        OffsetDateTime start = OffsetDateTime.of(2024, 3, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+        List<UUID> ids = new ArrayList<>();
+        for (int day = 0; day < 4; day++) {
+            OffsetDateTime at = start.plusDays(day);
+            Transaction txn = new Transaction(UUID.randomUUID(), null, to, new BigDecimal("10.00"), "USD",
+                    "deposit", "completed", "", at, at);
+            txn.setTenantId(tenantId);
+            repository.create(txn);
+            ids.add(txn.getId());
+        }
+
+        // Both bounds are inclusive, with or without an account filter.
+        for (String account : new String[]{null, to.toString()}) {
+            MockHttpServletRequestBuilder request = get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                    .param("created_after", "2024-03-02T00:00:00Z")
+                    .param("created_before", "2024-03-03T00:00:00Z");
+            if (account != null) {
+                request.param("account_id", account);
+            }
+            mvc.perform(request)
+                    .andExpect(status().isOk())
+                    .andExpect(jsonPath("$.transactions.length()").value(2))
+                    .andExpect(jsonPath("$.transactions[0].id").value(ids.get(2).toString()))
+                    .andExpect(jsonPath("$.transactions[1].id").value(ids.get(1).toString()));
+        }
+    }
+
+    @Test
+    void cursorWalksEveryRowOnce() throws Exception {
+        OffsetDateTime start = OffsetDateTime.of(2024, 3, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+        List<UUID> expected = new ArrayList<>();
+        for (int i = 0; i < 5; i++) {
+            // Pairs share a timestamp, so the id has to break the tie between pages
+            OffsetDateTime at = start.plusMinutes(i / 2);
+            Transaction txn = new Transaction(UUID.randomUUID(), null, to, new BigDecimal("10.00"), "USD",
+                    "deposit", "completed", "", at, at);
+            txn.setTenantId(tenantId);
+            repository.create(txn);
+        }
+        TenantContext.set(tenantId);
+        try {
+            repository.list(new TransactionFilter()).forEach(t -> expected.add(t.getId()));
+        } finally {
+            TenantContext.clear();
+        }
+
+        List<UUID> seen = new ArrayList<>();
+        String cursor = null;
+        do {
+            MockHttpServletRequestBuilder request = get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                    .param("limit", "2");
+            if (cursor != null) {
+                request.param("cursor", cursor);
+            }
+            JsonNode page = objectMapper.readTree(mvc.perform(request).andExpect(status().isOk())
+                    .andReturn().getResponse().getContentAsString());
+            page.get("transactions").forEach(t -> seen.add(UUID.fromString(t.get("id").asText())));
+            cursor = page.get("next_cursor").isNull() ? null : page.get("next_cursor").asText();
+        } while (cursor != null);
+
+        assertEquals(expected, seen);
+    }
+
+    @Test
+    void listingRejectsMalformedCursor() throws Exception {
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("cursor", "not-a-cursor"))
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("invalid cursor"));
+        String cursor = new Cursor(OffsetDateTime.now(ZoneOffset.UTC), UUID.randomUUID()).encode();
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("cursor", cursor).param("sort_by", "amount"))
+                .andExpect(status().isBadRequest());
+    }
+
+    @Test
+    void listingRejectsInvalidCreationRange() throws Exception {
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("created_after", "last tuesday"))
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.error").value("invalid created_after, expected an ISO 8601 timestamp"));
+        mvc.perform(get("/transactions").header(TenantContext.HEADER, tenantId.toString())
+                        .param("created_after", "2024-03-03T00:00:00Z")
+                        .param("created_before", "2024-03-02T00:00:00Z"))
+                .andExpect(status().isBadRequest())
+                .andExpect(jsonPath("$.code").value("validation_error"));
+    }
+
+    @Test
+    void transactionsAreScopedToTenant() throws Exception {
+        String body = transfer("10.00").andExpect(status().isCreated())
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        mvc.perform(get("/transactions/" + id).header(TenantContext.HEADER, UUID.randomUUID().toString()))
+                .andExpect(status().isForbidden());
+    }
+
+    @Test
+    void retryWithSameIdempotencyKeyReplaysTheOriginalTransfer() throws Exception {
+        String key = UUID.randomUUID().toString();
+        String body = transfer("10.00", key).andExpect(status().isCreated())
+                .andExpect(header().doesNotExist("Idempotent-Replayed"))
+                .andReturn().getResponse().getContentAsString();
+        String id = objectMapper.readTree(body).get("id").asText();
+
+        transfer("10.00", key).andExpect(status().isOk())
+                .andExpect(header().string("Idempotent-Replayed", "true"))
+                .andExpect(jsonPath("$.id").value(id));
+        transfer("20.00", key).andExpect(status().isConflict());
+        transfer("10.00", UUID.randomUUID().toString()).andExpect(status().isCreated());
+
+        TenantContext.set(tenantId);
+        try {
+            assertEquals(2, repository.list(new TransactionFilter()).size());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    @Test
+    void failedTransferReleasesItsIdempotencyKey() throws Exception {
+        String key = UUID.randomUUID().toString();
+        transfer("500.00", key).andExpect(status().isUnprocessableEntity());
+
+        transfer("10.00", key).andExpect(status().isCreated());
+    }
+
+    // The first request holds the key while it waits on account-service; every
+    // concurrent duplicate is turned away instead of creating a second transfer.
+    @Test
+    void concurrentRequestsWithSameIdempotencyKeyCreateOneTransfer() throws Exception {
+        String key = UUID.randomUUID().toString();
+        accounts.balanceGate = new CountDownLatch(1);
+        ExecutorService pool = Executors.newFixedThreadPool(8);
+        try {
+            CompletionService<Integer> requests = new ExecutorCompletionService<>(pool);
+            for (int i = 0; i < 8; i++) {
+                requests.submit(() -> transfer("10.00", key).andReturn().getResponse().getStatus());
+            }
+            for (int i = 0; i < 7; i++) {
+                assertEquals(409, requests.poll(5, TimeUnit.SECONDS).get());
+            }
+            accounts.balanceGate.countDown();
+            assertEquals(201, requests.poll(5, TimeUnit.SECONDS).get());
+        } finally {
+            pool.shutdownNow();
+        }
+
+        transfer("10.00", key).andExpect(status().isOk());
+        TenantContext.set(tenantId);
+        try {
+            assertEquals(1, repository.list(new TransactionFilter()).size());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    // Every request reads the same 100.00 balance before any hold exists; only as many
+    // 30.30 holds as that balance covers may be placed.
+    @Test
+    void concurrentAuthorizationsCannotHoldMoreThanTheBalance() throws Exception {
+        accounts.balanceGate = new CountDownLatch(1);
+        ExecutorService pool = Executors.newFixedThreadPool(8);
+        List<Integer> statuses = new ArrayList<>();
+        try {
+            CompletionService<Integer> requests = new ExecutorCompletionService<>(pool);
+            for (int i = 0; i < 8; i++) {
+                requests.submit(() -> transfer("30.00").andReturn().getResponse().getStatus());
+            }
+            accounts.balanceGate.countDown();
+            for (int i = 0; i < 8; i++) {
+                statuses.add(requests.poll(5, TimeUnit.SECONDS).get());
+            }
+        } finally {
+            pool.shutdownNow();
+        }
+
+        assertEquals(3, statuses.stream().filter(s -> s == 201).count());
+        assertEquals(5, statuses.stream().filter(s -> s == 422).count());
+        TenantContext.set(tenantId);
+        try {
+            BigDecimal held = repository.sumActiveHolds(from, OffsetDateTime.now(ZoneOffset.UTC));
+            assertEquals(0, new BigDecimal("90.90").compareTo(held), held.toString());
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    private ResultActions action(String id, String action) throws Exception {
+        return action(id, action, SENDER, null);
+    }
+
+    private ResultActions action(String id, String action, String userId, String role) throws Exception {
+        MockHttpServletRequestBuilder request = post("/transactions/" + id + "/" + action)
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (userId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, userId);
+        }
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions deposit(String amount) throws Exception {
+        return deposit(amount, "teller");
+    }
+
+    private ResultActions deposit(String amount, String role) throws Exception {
+        MockHttpServletRequestBuilder request = post("/transactions/deposit")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"account_id\":\"" + to + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}");
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    // Stands in for the transaction manager finishing the request's DB transaction.
+    private static void completeTransaction(int status) {
+        List<TransactionSynchronization> synchronizations = TransactionSynchronizationManager.getSynchronizations();
+        if (status == TransactionSynchronization.STATUS_COMMITTED) {
+            synchronizations.forEach(TransactionSynchronization::afterCommit);
+        }
+        synchronizations.forEach(sync -> sync.afterCompletion(status));
+    }
+
+    private ResultActions withdraw(String amount) throws Exception {
+        return withdraw(amount, SENDER);
+    }
+
+    private ResultActions withdraw(String amount, String userId) throws Exception {
+        MockHttpServletRequestBuilder request = post("/transactions/withdrawal")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"account_id\":\"" + from + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}");
+        if (userId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, userId);
+        }
+        return mvc.perform(request);
+    }
+
+    private ResultActions batch(String... transfers) throws Exception {
+        return mvc.perform(post("/transactions/batch")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"transfers\":[" + String.join(",", transfers) + "]}"));
+    }
+
+    private static String transferJson(UUID fromAccount, UUID toAccount, String amount) {
+        return "{\"from_account_id\":\"" + fromAccount + "\",\"to_account_id\":\"" + toAccount
+                + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}";
+    }
+
+    private ResultActions transfer(String amount) throws Exception {
+        return transfer(amount, null);
+    }
+
+    private ResultActions transfer(String amount, String idempotencyKey) throws Exception {
+        MockHttpServletRequestBuilder request = post("/transactions/transfer")
+                .header(TenantContext.HEADER, tenantId.toString())
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"from_account_id\":\"" + from + "\",\"to_account_id\":\"" + to
+                        + "\",\"amount\":" + amount + ",\"currency\":\"USD\"}");
+        if (idempotencyKey != null) {
+            request.header("Idempotency-Key", idempotencyKey);
+        }
+        return mvc.perform(request);
+    }
+
+    // Captures a transfer and moves the stub balances the way the completion event
+    // would in account-service.
+    private String capturedTransfer(String amount) throws Exception {
+        String body = transfer(amount).andExpect(status().isCreated()).andReturn().getResponse().getContentAsString();
+        JsonNode txn = objectMapper.readTree(body);
+        String id = txn.get("id").asText();
+        action(id, "capture").andExpect(status().isOk());
+        accounts.balances.merge(from, new BigDecimal(txn.get("net_amount").asText()).negate(), BigDecimal::add);
+        accounts.balances.merge(to, new BigDecimal(amount), BigDecimal::add);
+        return id;
+    }
+
+    private ResultActions reverse(String id, String userId, String role) throws Exception {
+        MockHttpServletRequestBuilder request = post("/transactions/" + id + "/reverse")
+                .header(TenantContext.HEADER, tenantId.toString());
+        if (userId != null) {
+            request.requestAttr(AuthFilter.USER_ID_ATTRIBUTE, userId);
+        }
+        if (role != null) {
+            request.requestAttr(AuthFilter.ROLE_ATTRIBUTE, role);
+        }
+        return mvc.perform(request);
+    }
+
+    // Answers balance lookups from memory instead of calling account-service.
+    private static class StubAccountServiceClient extends AccountServiceClient {
+
+        final Map<UUID, BigDecimal> balances = new ConcurrentHashMap<>();
+        final Map<UUID, BigDecimal> depositLimits = new ConcurrentHashMap<>();
+        final Map<UUID, String> owners = new ConcurrentHashMap<>();
+        // Accounts not listed here are in USD.
+        final Map<UUID, String> currencies = new ConcurrentHashMap<>();
+        // When set, balance lookups wait for it, holding the request mid-flight.
+        volatile CountDownLatch balanceGate;
+        // When set, account-service answers every adjustment with a 409.
+        volatile boolean rejectAdjustments;
+
+        StubAccountServiceClient(AppConfig config) {
+            super(config, RestClient.builder(), new SimpleClientHttpRequestFactory(), null);
+        }
+
+        @Override
+        public BalanceResponse getBalance(UUID accountId, String authHeader) {
+            if (balanceGate != null) {
+                try {
+                    balanceGate.await(5, TimeUnit.SECONDS);
+                } catch (InterruptedException e) {
+                    Thread.currentThread().interrupt();
+                }
+            }
+            return new BalanceResponse(accountId, balances.get(accountId), currencies.getOrDefault(accountId, "USD"));
+        }
+
+        @Override
+        public AccountResponse getAccount(UUID accountId, String authHeader) {
+            return new AccountResponse(accountId, currencies.getOrDefault(accountId, "USD"), "checking",
+                    depositLimits.get(accountId), owners.get(accountId));
+        }
+
+        @Override
+        public void adjustBalance(UUID accountId, BigDecimal amount, UUID transactionId,
+                                  String reason, String authHeader) {
+            // Like account-service, an adjustment may not overdraw the account
+            if (rejectAdjustments || balances.getOrDefault(accountId, BigDecimal.ZERO).add(amount).signum() < 0) {
+                throw HttpClientErrorException.create(HttpStatus.CONFLICT, "Conflict", null, null, null);
+            }
+            balances.merge(accountId, amount, BigDecimal::add);
+        }
+    }
+}
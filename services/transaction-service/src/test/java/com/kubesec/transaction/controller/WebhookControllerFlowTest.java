@@ -0,0 +1,152 @@
+package com.kubesec.transaction.controller;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.GlobalExceptionHandler;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.dto.TransactionEvent;
+import com.kubesec.transaction.service.FxRateService;
+import com.kubesec.transaction.service.NatsPublisher;
+import com.kubesec.transaction.service.TransactionService;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.testdoubles.InMemoryTenantRepository;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import com.kubesec.transaction.validation.SchemaValidationAdvice;
+import com.kubesec.transaction.validation.SchemaValidator;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+import org.springframework.http.MediaType;
+import org.springframework.http.client.SimpleClientHttpRequestFactory;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.ResultActions;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.post;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+// Drives the payment-confirmed webhook with no database or NATS. The signature filter
+// is left out; it is the same for every /webhooks path.
+class WebhookControllerFlowTest {
+
+    private final ObjectMapper objectMapper = new ObjectMapper().findAndRegisterModules();
+    private final UUID accountId = UUID.randomUUID();
+
+    private InMemoryTenantRepository tenants;
+    private UUID tenantId;
+    private InMemoryTransactionRepository repository;
+    private NatsPublisher natsPublisher;
+    private MockMvc mvc;
+
+    @BeforeEach
+    void setUp() {
+        tenants = new InMemoryTenantRepository();
+        tenantId = tenants.addTenant(true);
+
+        AppConfig config = new AppConfig();
+        repository = new InMemoryTransactionRepository();
+        natsPublisher = mock(NatsPublisher.class);
+        TransactionService transactionService = TransactionService.builder()
+                .withRepository(repository)
+                .withAccountClient(new AccountServiceClient(config, RestClient.builder(), new SimpleClientHttpRequestFactory(), null))
+                .withFxRateService(new FxRateService(config))
+                .withFeeCalculator(new FlatFeeCalculator(BigDecimal.ZERO))
+                .withNatsPublisher(natsPublisher)
+                .build();
+
+        mvc = MockMvcBuilders.standaloneSetup(new WebhookController(transactionService, tenants))
+                .setControllerAdvice(new GlobalExceptionHandler(),
+                        new SchemaValidationAdvice(new SchemaValidator(), objectMapper))
+                .build();
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void firstConfirmationCreatesCompletedDeposit() throws Exception {
+        confirm(tenantId, "pay_123", "25.00").andExpect(status().isOk())
+                .andExpect(jsonPath("$.external_ref").value("pay_123"))
+                .andExpect(jsonPath("$.type").value("deposit"))
+                .andExpect(jsonPath("$.status").value("completed"))
+                .andExpect(jsonPath("$.to_account_id").value(accountId.toString()))
+                .andExpect(jsonPath("$.amount").value(25.00));
+
+        List<Transaction> stored = storedTransactions();
+        assertEquals(1, stored.size());
+        assertEquals(1, repository.getTransactionEventHistory(stored.get(0).getId()).size());
+
+        ArgumentCaptor<TransactionEvent> event = ArgumentCaptor.forClass(TransactionEvent.class);
+        verify(natsPublisher).publishTransactionCompleted(event.capture());
+        assertEquals(stored.get(0).getId(), event.getValue().transactionId());
+    }
+
+    @Test
+    void replayedConfirmationReturnsTheSameTransaction() throws Exception {
+        String first = confirm(tenantId, "pay_123", "25.00").andExpect(status().isOk())
+                .andReturn().getResponse().getContentAsString();
+        String replay = confirm(tenantId, "pay_123", "25.00").andExpect(status().isOk())
+                .andReturn().getResponse().getContentAsString();
+
+        String id = objectMapper.readTree(first).get("id").asText();
+        assertEquals(id, objectMapper.readTree(replay).get("id").asText());
+        assertEquals(1, storedTransactions().size());
+        assertEquals(1, repository.getTransactionEventHistory(UUID.fromString(id)).size());
+
+        // Both deliveries publish under the same transaction id, which consumers apply once.
+        ArgumentCaptor<TransactionEvent> events = ArgumentCaptor.forClass(TransactionEvent.class);
+        verify(natsPublisher, times(2)).publishTransactionCompleted(events.capture());
+        assertEquals(List.of(UUID.fromString(id), UUID.fromString(id)),
+                events.getAllValues().stream().map(TransactionEvent::transactionId).toList());
+    }
+
+    @Test
+    void replayWithDifferentAmountIsRejected() throws Exception {
+        confirm(tenantId, "pay_123", "25.00").andExpect(status().isOk());
+
+        confirm(tenantId, "pay_123", "30.00").andExpect(status().isConflict());
+        assertEquals(1, storedTransactions().size());
+    }
+
+    @Test
+    void externalRefOfAnotherTenantIsNotReturned() throws Exception {
+        confirm(tenantId, "pay_123", "25.00").andExpect(status().isOk());
+        UUID otherTenant = tenants.addTenant(true);
+
+        confirm(otherTenant, "pay_123", "25.00").andExpect(status().isConflict());
+    }
+
+    @Test
+    void inactiveTenantIsRejected() throws Exception {
+        confirm(tenants.addTenant(false), "pay_123", "25.00").andExpect(status().isForbidden());
+    }
+
+    private List<Transaction> storedTransactions() {
+        TenantContext.set(tenantId);
+        return repository.list(new TransactionFilter());
+    }
+
+    private ResultActions confirm(UUID tenant, String externalRef, String amount) throws Exception {
+        return mvc.perform(post("/webhooks/payment-confirmed")
+                .contentType(MediaType.APPLICATION_JSON)
+                .content("{\"external_ref\":\"" + externalRef + "\",\"tenant_id\":\"" + tenant
+                        + "\",\"account_id\":\"" + accountId + "\",\"amount\":" + amount
+                        + ",\"currency\":\"USD\"}"));
+    }
+}
@@ -0,0 +1,106 @@
+package com.kubesec.transaction.exception;
+
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.Arguments;
+import org.junit.jupiter.params.provider.MethodSource;
+import org.springframework.test.web.servlet.MockMvc;
+import org.springframework.test.web.servlet.setup.MockMvcBuilders;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+import java.math.BigDecimal;
+import java.util.List;
+import java.util.stream.Stream;
+
+import static org.springframework.test.web.servlet.request.MockMvcRequestBuilders.get;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.jsonPath;
+import static org.springframework.test.web.servlet.result.MockMvcResultMatchers.status;
+
+class GlobalExceptionHandlerTest {
+
+    private final ThrowingController controller = new ThrowingController();
+    private MockMvc mockMvc;
+
+    @BeforeEach
+    void setUp() {
+        mockMvc = MockMvcBuilders.standaloneSetup(controller)
+                .setControllerAdvice(new GlobalExceptionHandler())
+                .build();
+    }
+
+    static Stream<Arguments> domainExceptions() {
+        return Stream.of(
+                Arguments.of(new ConflictException("already exists"), 409, "conflict", "already exists"),
+                Arguments.of(new ForbiddenException("not yours"), 403, "forbidden", "not yours"),
+                Arguments.of(new InsufficientBalanceException("insufficient balance"), 422,
+                        "insufficient_balance", "insufficient balance"),
+                Arguments.of(new InvalidFilterFieldException("colour"), 400,
+                        "invalid_filter_field", "invalid filter field: colour"),
+                Arguments.of(new ResourceNotFoundException("transaction not found"), 404,
+                        "not_found", "transaction not found"),
+                Arguments.of(new UnauthorizedException("invalid token"), 401, "unauthorized", "invalid token"),
+                Arguments.of(new UpstreamException("account service unavailable"), 502,
+                        "upstream_error", "account service unavailable"),
+                Arguments.of(new ValidationException("amount must be positive"), 400,
+                        "validation_error", "amount must be positive"));
+    }
+
+    @ParameterizedTest
+    @MethodSource("domainExceptions")
+    void domainExceptionsMapToTheirStatusAndCode(DomainException ex, int status, String code, String error)
+            throws Exception {
+        controller.next = ex;
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(status))
+                .andExpect(jsonPath("$.error").value(error))
+                .andExpect(jsonPath("$.code").value(code));
+    }
+
+    @Test
+    void schemaViolationsListEachViolation() throws Exception {
+        controller.next = new SchemaViolationException(List.of("amount: required", "currency: too long"));
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.error").value("request body failed validation"))
+                .andExpect(jsonPath("$.code").value("schema_violation"))
+                .andExpect(jsonPath("$.violations.length()").value(2))
+                .andExpect(jsonPath("$.violations[0]").value("amount: required"));
+    }
+
+    @Test
+    void dailyDepositLimitReportsLimitAndCurrent() throws Exception {
+        controller.next = new DailyDepositLimitExceededException(new BigDecimal("10000.00"), new BigDecimal("9500.00"));
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(422))
+                .andExpect(jsonPath("$.error").value("daily deposit limit exceeded"))
+                .andExpect(jsonPath("$.code").value("daily_deposit_limit_exceeded"))
+                .andExpect(jsonPath("$.limit").value("10000.00"))
+                .andExpect(jsonPath("$.current").value("9500.00"));
+    }
+
+    @Test
+    void unexpectedExceptionsAreHiddenBehindA500() throws Exception {
+        controller.next = new IllegalStateException("connection pool exhausted");
+
+        mockMvc.perform(get("/throw"))
+                .andExpect(status().is(500))
+                .andExpect(jsonPath("$.error").value("internal error"))
+                .andExpect(jsonPath("$.code").doesNotExist());
+    }
+
+    @RestController
+    static class ThrowingController {
+
+        RuntimeException next;
+
+        @GetMapping("/throw")
+        void fail() {
+            throw next;
+        }
+    }
+}
@@ -0,0 +1,127 @@
+package com.kubesec.transaction.fees;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.config.FeeConfig;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.sun.net.httpserver.HttpServer;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.web.client.RestClientResponseException;
+
+import java.io.OutputStream;
+import java.math.BigDecimal;
+import java.net.InetSocketAddress;
+import java.nio.charset.StandardCharsets;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertInstanceOf;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+// The HTTP calculator talks to an in-process server standing in for the fee service.
+class FeeCalculatorTest {
+
+    private HttpServer server;
+    private int status;
+    private String responseBody;
+    private String requestBody;
+    private String requestTenant;
+
+    @BeforeEach
+    void setUp() throws Exception {
+        server = HttpServer.create(new InetSocketAddress("127.0.0.1", 0), 0);
+        server.createContext("/fees", exchange -> {
+            requestBody = new String(exchange.getRequestBody().readAllBytes(), StandardCharsets.UTF_8);
+            requestTenant = exchange.getRequestHeaders().getFirst(TenantContext.HEADER);
+            byte[] body = responseBody.getBytes(StandardCharsets.UTF_8);
+            exchange.getResponseHeaders().set("Content-Type", "application/json");
+            exchange.sendResponseHeaders(status, body.length);
+            try (OutputStream out = exchange.getResponseBody()) {
+                out.write(body);
+            }
+        });
+        server.start();
+        status = 200;
+    }
+
+    @AfterEach
+    void tearDown() {
+        server.stop(0);
+        TenantContext.clear();
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            // rate, amount, fee
+            "0.01, 100.00, 1.00",
+            "0.015, 10.00, 0.15",
+            "0.01, 0.01, 0.00",
+            "0.0025, 1234567.89, 3086.42",
+            // Half-even rounding: a trailing 5 rounds to the even cent
+            "0.1, 0.25, 0.02",
+            "0.1, 0.35, 0.04",
+            "0, 500.00, 0.00"
+    })
+    void flatRateIsAShareOfTheAmountRoundedToCents(BigDecimal rate, BigDecimal amount, BigDecimal expected) {
+        assertEquals(expected, new FlatFeeCalculator(rate).calculate(amount, "USD"));
+    }
+
+    @Test
+    void negativeFlatRateIsRejected() {
+        assertThrows(IllegalArgumentException.class, () -> new FlatFeeCalculator(new BigDecimal("-0.01")));
+    }
+
+    @Test
+    void feeServiceIsAskedWithTheAmountCurrencyAndTenant() {
+        UUID tenantId = UUID.randomUUID();
+        TenantContext.set(tenantId);
+        responseBody = "{\"fee\":1.255}";
+
+        BigDecimal fee = calculator().calculate(new BigDecimal("125.50"), "EUR");
+
+        assertEquals(new BigDecimal("1.26"), fee);
+        assertTrue(requestBody.contains("\"amount\":125.50"), requestBody);
+        assertTrue(requestBody.contains("\"currency\":\"EUR\""), requestBody);
+        assertEquals(tenantId.toString(), requestTenant);
+    }
+
+    @Test
+    void invalidFeeFromTheServiceIsAnError() {
+        responseBody = "{\"fee\":-1.00}";
+        assertThrows(IllegalStateException.class, () -> calculator().calculate(BigDecimal.TEN, "USD"));
+
+        responseBody = "{}";
+        assertThrows(IllegalStateException.class, () -> calculator().calculate(BigDecimal.TEN, "USD"));
+    }
+
+    @Test
+    void feeServiceErrorsPropagate() {
+        status = 503;
+        responseBody = "{\"error\":\"unavailable\"}";
+
+        assertThrows(RestClientResponseException.class, () -> calculator().calculate(BigDecimal.TEN, "USD"));
+    }
+
+    @Test
+    void configuredFeeServiceTakesPrecedenceOverTheFlatRate() {
+        AppConfig config = new AppConfig();
+        config.setFeeRate(new BigDecimal("0.01"));
+
+        assertInstanceOf(FlatFeeCalculator.class, new FeeConfig().feeCalculator(config));
+
+        config.setFeeServiceUrl(baseUrl());
+        assertInstanceOf(HttpFeeCalculator.class, new FeeConfig().feeCalculator(config));
+    }
+
+    private HttpFeeCalculator calculator() {
+        return new HttpFeeCalculator(baseUrl());
+    }
+
+    private String baseUrl() {
+        return "http://127.0.0.1:" + server.getAddress().getPort();
+    }
+}
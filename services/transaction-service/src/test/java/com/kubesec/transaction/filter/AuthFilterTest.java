@@ -0,0 +1,92 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.client.AuthServiceClient;
+import com.kubesec.transaction.client.TokenValidationCache;
+import com.kubesec.transaction.config.AppConfig;
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+import org.springframework.web.client.ResourceAccessException;
+
+import java.util.Optional;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+class AuthFilterTest {
+
+    private AuthServiceClient authClient;
+    private TokenValidationCache tokenCache;
+    private AuthFilter filter;
+
+    @BeforeEach
+    void setUp() {
+        authClient = mock(AuthServiceClient.class);
+        AppConfig config = new AppConfig();
+        config.setAuthCircuitFailureThreshold(3);
+        tokenCache = mock(TokenValidationCache.class);
+        filter = new AuthFilter(authClient, tokenCache, config, new SimpleMeterRegistry());
+    }
+
+    @Test
+    void repeatedAuthServiceFailuresOpenTheCircuit() throws Exception {
+        when(authClient.validateToken(anyString())).thenThrow(new ResourceAccessException("connect timed out"));
+
+        for (int i = 0; i < 3; i++) {
+            assertEquals(401, authenticate().getStatus());
+        }
+        MockHttpServletResponse response = authenticate();
+
+        assertEquals(503, response.getStatus());
+        assertEquals("{\"error\":\"auth service unavailable\"}", response.getContentAsString());
+        verify(authClient, times(3)).validateToken(anyString());
+    }
+
+    @Test
+    void validTokenPassesThroughWithItsUserAndRole() throws Exception {
+        AuthServiceClient.ValidateResponse identity = new AuthServiceClient.ValidateResponse(true, "user-1", "teller");
+        when(authClient.validateToken(anyString())).thenReturn(identity);
+        MockHttpServletRequest request = request();
+
+        MockHttpServletResponse response = authenticate(request);
+
+        assertEquals(200, response.getStatus());
+        assertEquals("user-1", request.getAttribute(AuthFilter.USER_ID_ATTRIBUTE));
+        assertEquals("teller", request.getAttribute(AuthFilter.ROLE_ATTRIBUTE));
+        verify(tokenCache).put("token", identity);
+    }
+
+    @Test
+    void cachedValidationSkipsAuthService() throws Exception {
+        when(tokenCache.get("token"))
+                .thenReturn(Optional.of(new AuthServiceClient.ValidateResponse(true, "user-1", "admin")));
+        MockHttpServletRequest request = request();
+
+        assertEquals(200, authenticate(request).getStatus());
+        assertEquals("admin", request.getAttribute(AuthFilter.ROLE_ATTRIBUTE));
+        verify(authClient, never()).validateToken(anyString());
+    }
+
+    private MockHttpServletResponse authenticate() throws Exception {
+        return authenticate(request());
+    }
+
+    private MockHttpServletResponse authenticate(MockHttpServletRequest request) throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        filter.doFilter(request, response, (req, res) -> { });
+        return response;
+    }
+
+    private static MockHttpServletRequest request() {
+        MockHttpServletRequest request = new MockHttpServletRequest("GET", "/transactions");
+        request.addHeader("Authorization", "Bearer token");
+        return request;
+    }
+}
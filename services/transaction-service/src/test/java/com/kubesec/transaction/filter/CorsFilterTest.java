@@ -0,0 +1,84 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.util.List;
+import java.util.concurrent.atomic.AtomicBoolean;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class CorsFilterTest {
+
+    private static final String APP = "https://app.kubesec.example";
+
+    @Test
+    void preflightFromAllowedOriginIsAnsweredWithoutReachingTheChain() throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter(List.of(APP)).doFilter(preflight(APP), response, (req, res) -> called.set(true));
+
+        assertEquals(200, response.getStatus());
+        assertFalse(called.get(), "the auth and tenant filters must not see a preflight");
+        assertEquals(APP, response.getHeader("Access-Control-Allow-Origin"));
+        assertEquals(CorsFilter.ALLOWED_METHODS, response.getHeader("Access-Control-Allow-Methods"));
+        assertEquals(CorsFilter.ALLOWED_HEADERS, response.getHeader("Access-Control-Allow-Headers"));
+        assertEquals("600", response.getHeader("Access-Control-Max-Age"));
+        assertEquals("Origin", response.getHeader("Vary"));
+    }
+
+    @Test
+    void preflightFromOtherOriginIsRejected() throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter(List.of(APP)).doFilter(preflight("https://evil.example"), response, (req, res) -> called.set(true));
+
+        assertEquals(403, response.getStatus());
+        assertFalse(called.get());
+        assertNull(response.getHeader("Access-Control-Allow-Origin"));
+    }
+
+    @Test
+    void actualRequestIsPassedOnWithTheOriginAllowed() throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/transactions/transfer");
+        request.addHeader("Origin", APP);
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter(List.of("https://other.example", APP)).doFilter(request, response, (req, res) -> called.set(true));
+
+        assertTrue(called.get());
+        assertEquals(APP, response.getHeader("Access-Control-Allow-Origin"));
+        assertEquals(CorsFilter.EXPOSED_HEADERS, response.getHeader("Access-Control-Expose-Headers"));
+    }
+
+    @Test
+    void wildcardAllowsAnyOrigin() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter(List.of("*")).doFilter(preflight("http://localhost:3000"), response, (req, res) -> { });
+
+        assertEquals(200, response.getStatus());
+        assertEquals("*", response.getHeader("Access-Control-Allow-Origin"));
+    }
+
+    private static CorsFilter filter(List<String> origins) {
+        AppConfig config = new AppConfig();
+        config.setCorsAllowedOrigins(origins);
+        return new CorsFilter(config);
+    }
+
+    private static MockHttpServletRequest preflight(String origin) {
+        MockHttpServletRequest request = new MockHttpServletRequest("OPTIONS", "/transactions/transfer");
+        request.addHeader("Origin", origin);
+        request.addHeader("Access-Control-Request-Method", "POST");
+        return request;
+    }
+}
@@ -0,0 +1,98 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.http.HttpServletRequest;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockFilterChain;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.io.ByteArrayOutputStream;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.util.zip.GZIPOutputStream;
+
+import static org.junit.jupiter.api.Assertions.assertArrayEquals;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.junit.jupiter.api.Assertions.assertSame;
+
+class DecompressRequestFilterTest {
+
+    private AppConfig config;
+    private DecompressRequestFilter filter;
+
+    @BeforeEach
+    void setUp() {
+        config = new AppConfig();
+        config.setMaxDecompressedBodyBytes(1024);
+        filter = new DecompressRequestFilter(config);
+    }
+
+    @Test
+    void inflatesGzipBodyForHandler() throws Exception {
+        byte[] json = "[{\"amount\":\"10.00\"}]".getBytes(StandardCharsets.UTF_8);
+        MockFilterChain chain = new MockFilterChain();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(gzipRequest(gzip(json)), response, chain);
+
+        assertEquals(200, response.getStatus());
+        HttpServletRequest forwarded = (HttpServletRequest) chain.getRequest();
+        assertArrayEquals(json, forwarded.getInputStream().readAllBytes());
+        assertNull(forwarded.getHeader("Content-Encoding"));
+        assertEquals(json.length, forwarded.getContentLength());
+    }
+
+    @Test
+    void rejectsMalformedGzip() throws Exception {
+        MockFilterChain chain = new MockFilterChain();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(gzipRequest("not gzip at all".getBytes(StandardCharsets.UTF_8)), response, chain);
+
+        assertEquals(400, response.getStatus());
+        assertNull(chain.getRequest());
+    }
+
+    @Test
+    void rejectsBodyThatExpandsPastLimit() throws Exception {
+        // 64 KiB of zeros compresses to well under the 1 KiB limit.
+        byte[] bomb = gzip(new byte[64 * 1024]);
+        MockFilterChain chain = new MockFilterChain();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(gzipRequest(bomb), response, chain);
+
+        assertEquals(413, response.getStatus());
+        assertNull(chain.getRequest());
+    }
+
+    @Test
+    void leavesOtherRoutesAlone() throws Exception {
+        MockHttpServletRequest request = gzipRequest("not gzip".getBytes(StandardCharsets.UTF_8));
+        request.setRequestURI("/transactions/deposit");
+        MockFilterChain chain = new MockFilterChain();
+
+        filter.doFilter(request, new MockHttpServletResponse(), chain);
+
+        assertSame(request, chain.getRequest());
+    }
+
+    private static MockHttpServletRequest gzipRequest(byte[] body) {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", DecompressRequestFilter.IMPORT_PATH);
+        request.addHeader("Content-Encoding", "gzip");
+        request.setContentType("application/json");
+        request.setContent(body);
+        return request;
+    }
+
+    private static byte[] gzip(byte[] data) throws IOException {
+        ByteArrayOutputStream out = new ByteArrayOutputStream();
+        try (GZIPOutputStream gz = new GZIPOutputStream(out)) {
+            gz.write(data);
+        }
+        return out.toByteArray();
+    }
+}
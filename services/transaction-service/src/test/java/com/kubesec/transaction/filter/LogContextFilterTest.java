@@ -0,0 +1,169 @@
+package com.kubesec.transaction.filter;
+
+import ch.qos.logback.classic.Level;
+import ch.qos.logback.classic.Logger;
+import ch.qos.logback.classic.spi.ILoggingEvent;
+import ch.qos.logback.core.read.ListAppender;
+import com.kubesec.transaction.client.AuthServiceClient;
+import com.kubesec.transaction.client.TokenValidationCache;
+import com.kubesec.transaction.config.AppConfig;
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import org.junit.jupiter.api.Test;
+import org.slf4j.LoggerFactory;
+import org.springframework.http.HttpHeaders;
+import org.springframework.http.HttpMethod;
+import org.springframework.http.client.ClientHttpResponse;
+import org.springframework.mock.http.client.MockClientHttpRequest;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.net.URI;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+import java.util.concurrent.atomic.AtomicReference;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNotEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.when;
+
+class LogContextFilterTest {
+
+    private final LogContextFilter filter = new LogContextFilter();
+    private final Logger logger = (Logger) LoggerFactory.getLogger(LogContextFilterTest.class);
+
+    @Test
+    void upstreamRequestIdIsKeptEchoedAndForwarded() throws Exception {
+        MockHttpServletRequest request = new MockHttpServletRequest("GET", "/transactions");
+        request.addHeader(LogContextFilter.HEADER, "gw-7f3a.42");
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        AtomicReference<String> forwarded = new AtomicReference<>();
+
+        filter.doFilter(request, response, (req, res) ->
+                forwarded.set(forward().getFirst(LogContextFilter.HEADER)));
+
+        assertEquals("gw-7f3a.42", response.getHeader(LogContextFilter.HEADER));
+        assertEquals("gw-7f3a.42", forwarded.get());
+        assertNull(LogContextFilter.currentRequestId(), "the MDC is cleared after the request");
+    }
+
+    @Test
+    void correlationIdIsKeptAcrossRequestsAndStartsAsTheFirstRequestId() throws Exception {
+        MockHttpServletResponse first = new MockHttpServletResponse();
+        filter.doFilter(new MockHttpServletRequest("POST", "/transactions/transfer"), first, (req, res) -> {});
+        String correlationId = first.getHeader(LogContextFilter.CORRELATION_HEADER);
+        assertEquals(first.getHeader(LogContextFilter.HEADER), correlationId);
+
+        MockHttpServletRequest next = new MockHttpServletRequest("GET", "/transactions");
+        next.addHeader(LogContextFilter.CORRELATION_HEADER, correlationId);
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        AtomicReference<String> forwarded = new AtomicReference<>();
+
+        filter.doFilter(next, response, (req, res) ->
+                forwarded.set(forward().getFirst(LogContextFilter.CORRELATION_HEADER)));
+
+        assertEquals(correlationId, response.getHeader(LogContextFilter.CORRELATION_HEADER));
+        assertNotEquals(correlationId, response.getHeader(LogContextFilter.HEADER));
+        assertEquals(correlationId, forwarded.get());
+    }
+
+    @Test
+    void missingOrUnsafeRequestIdIsReplaced() throws Exception {
+        for (String supplied : new String[]{null, "", "id\r\nforged: 1", "x".repeat(129)}) {
+            MockHttpServletRequest request = new MockHttpServletRequest("GET", "/transactions");
+            if (supplied != null) {
+                request.addHeader(LogContextFilter.HEADER, supplied);
+            }
+            MockHttpServletResponse response = new MockHttpServletResponse();
+
+            filter.doFilter(request, response, (req, res) -> {});
+
+            String echoed = response.getHeader(LogContextFilter.HEADER);
+            assertNotEquals(supplied, echoed);
+            assertDoesNotThrow(() -> UUID.fromString(echoed));
+        }
+    }
+
+    @Test
+    void logLinesWrittenDuringTheRequestCarryItsIds() throws Exception {
+        AuthServiceClient authClient = mock(AuthServiceClient.class);
+        when(authClient.validateToken(anyString()))
+                .thenReturn(new AuthServiceClient.ValidateResponse(true, "user-1", "customer"));
+        AuthFilter authFilter = new AuthFilter(authClient, mock(TokenValidationCache.class), new AppConfig(),
+                new SimpleMeterRegistry());
+        MockHttpServletRequest request = new MockHttpServletRequest("GET", "/transactions");
+        request.addHeader(LogContextFilter.HEADER, "gw-7f3a.42");
+        request.addHeader("Authorization", "Bearer token");
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        List<ILoggingEvent> events = captureLogs(() -> {
+            // The same filter order as the application: ids first, then the caller.
+            filter.doFilter(request, response, (req, res) -> {
+                logger.info("before auth");
+                authFilter.doFilter(req, res, (authed, out) -> logger.info("handling request"));
+            });
+            logger.info("after the request");
+        });
+
+        assertEquals(3, events.size());
+        Map<String, String> beforeAuth = events.get(0).getMDCPropertyMap();
+        assertEquals("gw-7f3a.42", beforeAuth.get(LogContextFilter.REQUEST_ID));
+        assertNull(beforeAuth.get(LogContextFilter.USER_ID));
+        Map<String, String> handling = events.get(1).getMDCPropertyMap();
+        assertEquals("gw-7f3a.42", handling.get(LogContextFilter.REQUEST_ID));
+        assertEquals("gw-7f3a.42", handling.get(LogContextFilter.CORRELATION_ID));
+        assertEquals("user-1", handling.get(LogContextFilter.USER_ID));
+        assertNull(events.get(2).getMDCPropertyMap().get(LogContextFilter.REQUEST_ID));
+    }
+
+    @Test
+    void generatedRequestIdIsTheOneLoggedAndEchoed() throws Exception {
+        MockHttpServletResponse first = new MockHttpServletResponse();
+        MockHttpServletResponse second = new MockHttpServletResponse();
+
+        List<ILoggingEvent> events = captureLogs(() -> {
+            filter.doFilter(new MockHttpServletRequest("GET", "/health"), first, (req, res) -> logger.info("one"));
+            filter.doFilter(new MockHttpServletRequest("GET", "/health"), second, (req, res) -> logger.info("two"));
+        });
+
+        assertEquals(first.getHeader(LogContextFilter.HEADER),
+                events.get(0).getMDCPropertyMap().get(LogContextFilter.REQUEST_ID));
+        assertEquals(second.getHeader(LogContextFilter.HEADER),
+                events.get(1).getMDCPropertyMap().get(LogContextFilter.REQUEST_ID));
+        assertNotEquals(first.getHeader(LogContextFilter.HEADER), second.getHeader(LogContextFilter.HEADER));
+    }
+
+    private List<ILoggingEvent> captureLogs(ThrowingRunnable action) throws Exception {
+        Level originalLevel = logger.getLevel();
+        logger.setLevel(Level.INFO);
+        ListAppender<ILoggingEvent> appender = new ListAppender<>();
+        appender.start();
+        logger.addAppender(appender);
+        try {
+            action.run();
+        } finally {
+            logger.detachAppender(appender);
+            logger.setLevel(originalLevel);
+        }
+        return appender.list;
+    }
+
+    private interface ThrowingRunnable {
+        void run() throws Exception;
+    }
+
+    private static HttpHeaders forward() {
+        MockClientHttpRequest outgoing = new MockClientHttpRequest(HttpMethod.GET,
+                URI.create("http://account-service/api/v1/accounts"));
+        try {
+            new LogContextInterceptor().intercept(outgoing, new byte[0], (req, body) -> mock(ClientHttpResponse.class));
+        } catch (Exception e) {
+            throw new IllegalStateException(e);
+        }
+        return outgoing.getHeaders();
+    }
+}
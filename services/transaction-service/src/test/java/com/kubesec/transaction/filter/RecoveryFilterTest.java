@@ -0,0 +1,45 @@
+package com.kubesec.transaction.filter;
+
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNull;
+
+class RecoveryFilterTest {
+
+    private final SimpleMeterRegistry registry = new SimpleMeterRegistry();
+    private final RecoveryFilter filter = new RecoveryFilter(registry);
+
+    @Test
+    void exceptionFromTheChainBecomesA500() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+        response.setHeader(LogContextFilter.HEADER, "req-1");
+        response.setHeader("Idempotent-Replayed", "true");
+
+        filter.doFilter(new MockHttpServletRequest("GET", "/transactions"), response, (req, res) -> {
+            Object missing = null;
+            missing.toString();
+        });
+
+        assertEquals(500, response.getStatus());
+        assertEquals("{\"error\":\"internal server error\"}", response.getContentAsString());
+        assertEquals("req-1", response.getHeader(LogContextFilter.HEADER));
+        assertNull(response.getHeader("Idempotent-Replayed"));
+        assertEquals(1.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+
+    @Test
+    void requestsThatCompleteAreNotCounted() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(new MockHttpServletRequest("GET", "/health"), response,
+                (req, res) -> res.getWriter().write("ok"));
+
+        assertEquals(200, response.getStatus());
+        assertEquals("ok", response.getContentAsString());
+        assertEquals(0.0, registry.counter(RecoveryFilter.PANIC_METRIC).count());
+    }
+}
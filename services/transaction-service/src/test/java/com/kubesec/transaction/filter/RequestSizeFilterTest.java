@@ -0,0 +1,123 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletRequestWrapper;
+import jakarta.servlet.http.HttpServletResponse;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import java.io.IOException;
+import java.util.concurrent.atomic.AtomicBoolean;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class RequestSizeFilterTest {
+
+    private AppConfig config;
+    private RequestSizeFilter filter;
+
+    @BeforeEach
+    void setUp() {
+        config = new AppConfig();
+        config.setMaxContentLengthBytes(100);
+        config.setMaxBodyReadBytes(50);
+        filter = new RequestSizeFilter(config);
+    }
+
+    @Test
+    void declaredLengthOverLimitIsRejectedUnread() throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request(new byte[101]), response, (req, res) -> called.set(true));
+
+        assertEquals(413, response.getStatus());
+        assertFalse(called.get(), "the handler must not run");
+    }
+
+    @Test
+    void bodyWithinBothLimitsReachesHandler() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request(new byte[50]), response, readingHandler());
+
+        assertEquals(200, response.getStatus());
+        assertEquals("read 50", response.getContentAsString());
+    }
+
+    @Test
+    void chunkedBodyIsCutOffAtReadLimit() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(chunked(request(new byte[51])), response, readingHandler());
+
+        assertEquals(413, response.getStatus());
+        assertTrue(response.getContentAsString().contains("exceeds 50 bytes"));
+    }
+
+    @Test
+    void readLimitAppliesWhenDeclaredLengthPasses() throws Exception {
+        // 80 bytes pass the Content-Length check but not the read limit.
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request(new byte[80]), response, readingHandler());
+
+        assertEquals(413, response.getStatus());
+    }
+
+    @Test
+    void limitsAreIndependent() throws Exception {
+        config.setMaxContentLengthBytes(10);
+        config.setMaxBodyReadBytes(1000);
+        MockHttpServletResponse declared = new MockHttpServletResponse();
+        MockHttpServletResponse chunked = new MockHttpServletResponse();
+
+        filter.doFilter(request(new byte[20]), declared, readingHandler());
+        filter.doFilter(chunked(request(new byte[20])), chunked, readingHandler());
+
+        assertEquals(413, declared.getStatus());
+        assertEquals(200, chunked.getStatus());
+    }
+
+    // Reads the whole body the way a message converter would, turning a failed read
+    // into a 400 as Spring does.
+    private static FilterChain readingHandler() {
+        return (req, res) -> {
+            HttpServletResponse response = (HttpServletResponse) res;
+            try {
+                int n = req.getInputStream().readAllBytes().length;
+                response.getWriter().write("read " + n);
+            } catch (IOException e) {
+                response.setStatus(HttpServletResponse.SC_BAD_REQUEST);
+            }
+        };
+    }
+
+    private static MockHttpServletRequest request(byte[] body) {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/transactions/deposit");
+        request.setContent(body);
+        return request;
+    }
+
+    // A chunked request declares no length.
+    private static HttpServletRequest chunked(HttpServletRequest request) {
+        return new HttpServletRequestWrapper(request) {
+            @Override
+            public int getContentLength() { return -1; }
+
+            @Override
+            public long getContentLengthLong() { return -1; }
+
+            @Override
+            public String getHeader(String name) {
+                return "Transfer-Encoding".equalsIgnoreCase(name) ? "chunked" : super.getHeader(name);
+            }
+        };
+    }
+}
@@ -0,0 +1,137 @@
+package com.kubesec.transaction.filter;
+
+import com.kubesec.transaction.config.AppConfig;
+import jakarta.servlet.FilterChain;
+import jakarta.servlet.http.HttpServletResponse;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.mock.web.MockHttpServletRequest;
+import org.springframework.mock.web.MockHttpServletResponse;
+
+import javax.crypto.Mac;
+import javax.crypto.spec.SecretKeySpec;
+import java.nio.charset.StandardCharsets;
+import java.util.HexFormat;
+import java.util.concurrent.atomic.AtomicBoolean;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class WebhookSignatureFilterTest {
+
+    private static final String SECRET = "whsec_test";
+    private static final String BODY = "{\"external_ref\":\"pay_123\",\"amount\":25.00}";
+
+    private AppConfig config;
+    private WebhookSignatureFilter filter;
+
+    @BeforeEach
+    void setUp() {
+        config = new AppConfig();
+        config.setWebhookSecret(SECRET);
+        filter = new WebhookSignatureFilter(config);
+    }
+
+    @Test
+    void validSignatureReachesHandlerWithTheBodyIntact() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request(BODY, sign(SECRET, BODY)), response, echoingHandler());
+
+        assertEquals(200, response.getStatus());
+        assertEquals(BODY, response.getContentAsString(), "the handler reads the body the filter already consumed");
+    }
+
+    @Test
+    void prefixedSignatureIsAccepted() throws Exception {
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request(BODY, "sha256=" + sign(SECRET, BODY)), response, echoingHandler());
+
+        assertEquals(200, response.getStatus());
+    }
+
+    @Test
+    void emptyBodyIsVerifiedLikeAnyOther() throws Exception {
+        MockHttpServletResponse signed = new MockHttpServletResponse();
+        MockHttpServletResponse unsigned = new MockHttpServletResponse();
+
+        filter.doFilter(request("", sign(SECRET, "")), signed, echoingHandler());
+        filter.doFilter(request("", sign(SECRET, BODY)), unsigned, echoingHandler());
+
+        assertEquals(200, signed.getStatus());
+        assertEquals(401, unsigned.getStatus());
+    }
+
+    @Test
+    void tamperedBodyIsRejected() throws Exception {
+        String tampered = BODY.replace("25.00", "2500.00");
+
+        assertRejected(request(tampered, sign(SECRET, BODY)));
+    }
+
+    @Test
+    void signatureFromAnotherSecretIsRejected() throws Exception {
+        assertRejected(request(BODY, sign("some-other-secret", BODY)));
+    }
+
+    @Test
+    void missingOrMalformedSignatureIsRejected() throws Exception {
+        assertRejected(request(BODY, null));
+        assertRejected(request(BODY, ""));
+        assertRejected(request(BODY, "not-hex"));
+        // A valid signature with its last byte dropped
+        String signature = sign(SECRET, BODY);
+        assertRejected(request(BODY, signature.substring(0, signature.length() - 2)));
+    }
+
+    @Test
+    void unsetSecretRejectsEverything() throws Exception {
+        config.setWebhookSecret("");
+
+        assertRejected(request(BODY, sign(SECRET, BODY)));
+    }
+
+    @Test
+    void otherPathsAreNotChecked() throws Exception {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/transactions/deposit");
+        request.setContent(BODY.getBytes(StandardCharsets.UTF_8));
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request, response, echoingHandler());
+
+        assertEquals(200, response.getStatus());
+    }
+
+    private void assertRejected(MockHttpServletRequest request) throws Exception {
+        AtomicBoolean called = new AtomicBoolean();
+        MockHttpServletResponse response = new MockHttpServletResponse();
+
+        filter.doFilter(request, response, (req, res) -> called.set(true));
+
+        assertEquals(401, response.getStatus());
+        assertTrue(response.getContentAsString().contains("invalid webhook signature"));
+        assertFalse(called.get(), "the handler must not run");
+    }
+
+    private static FilterChain echoingHandler() {
+        return (req, res) -> ((HttpServletResponse) res).getWriter()
+                .write(new String(req.getInputStream().readAllBytes(), StandardCharsets.UTF_8));
+    }
+
+    private static MockHttpServletRequest request(String body, String signature) {
+        MockHttpServletRequest request = new MockHttpServletRequest("POST", "/webhooks/payment-confirmed");
+        request.setContent(body.getBytes(StandardCharsets.UTF_8));
+        if (signature != null) {
+            request.addHeader("X-Webhook-Signature", signature);
+        }
+        return request;
+    }
+
+    private static String sign(String secret, String body) throws Exception {
+        Mac mac = Mac.getInstance("HmacSHA256");
+        mac.init(new SecretKeySpec(secret.getBytes(StandardCharsets.UTF_8), "HmacSHA256"));
+        return HexFormat.of().formatHex(mac.doFinal(body.getBytes(StandardCharsets.UTF_8)));
+    }
+}
@@ -0,0 +1,120 @@
+package com.kubesec.transaction.health;
+
+import io.micrometer.core.instrument.simple.SimpleMeterRegistry;
+import org.junit.jupiter.api.Test;
+import org.springframework.context.support.GenericApplicationContext;
+import org.springframework.http.MediaType;
+import org.springframework.http.ResponseEntity;
+import org.springframework.jdbc.CannotGetJdbcConnectionException;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.sql.SQLException;
+import java.util.Map;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.when;
+
+class MeshHealthControllerTest {
+
+    @Test
+    void readyTurns503WhenTheContextCloses() {
+        GenericApplicationContext context = new GenericApplicationContext();
+        context.registerBean(ShutdownState.class);
+        context.refresh();
+        MeshHealthController controller = new MeshHealthController(
+                context.getBean(ShutdownState.class), new FixedMigrationStatus(3, 3));
+
+        ResponseEntity<?> before = controller.ready();
+        assertEquals(200, before.getStatusCode().value());
+        assertEquals("OK", before.getBody());
+
+        // Publishes ContextClosedEvent, as a SIGTERM does.
+        context.close();
+
+        ResponseEntity<?> after = controller.ready();
+        assertEquals(503, after.getStatusCode().value());
+        assertEquals("SHUTTING DOWN", after.getBody());
+        assertEquals(200, controller.live().getStatusCode().value(), "liveness is unaffected by draining");
+    }
+
+    @Test
+    void readyIs503WhileMigrationsArePending() {
+        MeshHealthController controller = new MeshHealthController(new ShutdownState(), new FixedMigrationStatus(2, 3));
+
+        ResponseEntity<?> response = controller.ready();
+
+        assertEquals(503, response.getStatusCode().value());
+        assertEquals(MediaType.APPLICATION_JSON, response.getHeaders().getContentType());
+        assertEquals(Map.of("migrations", "pending", "current", 2, "latest", 3), response.getBody());
+        assertEquals(200, controller.live().getStatusCode().value());
+    }
+
+    @Test
+    void readyIs503WhenTheDatabaseIsUnreachable() {
+        JdbcTemplate jdbc = mock(JdbcTemplate.class);
+        when(jdbc.queryForObject(anyString(), eq(Integer.class)))
+                .thenThrow(new CannotGetJdbcConnectionException("connection refused", new SQLException("refused")));
+        MigrationStatusChecker migrationStatus = new MigrationStatusChecker(jdbc, new SimpleMeterRegistry());
+        MeshHealthController controller = new MeshHealthController(new ShutdownState(), migrationStatus);
+
+        ResponseEntity<?> response = controller.ready();
+
+        assertEquals(503, response.getStatusCode().value());
+        assertEquals(Map.of("migrations", "pending", "current", 0, "latest", migrationStatus.latestVersion()),
+                response.getBody());
+        assertEquals(200, controller.live().getStatusCode().value(), "liveness does not depend on the database");
+    }
+
+    @Test
+    void readinessFollowsTheAppliedMigrations() {
+        JdbcTemplate jdbc = new JdbcTemplate(new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1"));
+        MigrationStatusChecker migrationStatus = new MigrationStatusChecker(jdbc, new SimpleMeterRegistry());
+        MeshHealthController controller = new MeshHealthController(new ShutdownState(), migrationStatus);
+
+        // No history table yet: nothing has been migrated
+        assertEquals(503, controller.ready().getStatusCode().value());
+
+        jdbc.execute("CREATE TABLE flyway_schema_history (version VARCHAR(50), success BOOLEAN NOT NULL)");
+        int latest = migrationStatus.latestVersion();
+        for (int version = 1; version < latest; version++) {
+            jdbc.update("INSERT INTO flyway_schema_history VALUES (?, TRUE)", String.valueOf(version));
+        }
+        // A failed run of the last migration does not count as applied
+        jdbc.update("INSERT INTO flyway_schema_history VALUES (?, FALSE)", String.valueOf(latest));
+        assertEquals(503, controller.ready().getStatusCode().value());
+
+        jdbc.update("UPDATE flyway_schema_history SET success = TRUE WHERE version = ?", String.valueOf(latest));
+        ResponseEntity<?> response = controller.ready();
+        assertEquals(200, response.getStatusCode().value());
+        assertEquals("OK", response.getBody());
+    }
+
+    // Reports fixed versions instead of reading flyway_schema_history.
+    static class FixedMigrationStatus extends MigrationStatusChecker {
+
+        private final int current;
+        private final int latest;
+
+        FixedMigrationStatus(int current, int latest) {
+            super(null, new SimpleMeterRegistry());
+            this.current = current;
+            this.latest = latest;
+        }
+
+        @Override
+        public int currentVersion() {
+            return current;
+        }
+
+        @Override
+        public int latestVersion() {
+            return latest;
+        }
+    }
+}
@@ -0,0 +1,103 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.fees.Fee;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Optional;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class FeeScheduleTest {
+
+    private static final OffsetDateTime JAN_1 = OffsetDateTime.of(2024, 1, 1, 0, 0, 0, 0, ZoneOffset.UTC);
+    private static final OffsetDateTime JUL_1 = JAN_1.plusMonths(6);
+
+    private JdbcTemplate jdbc;
+    private TransactionRepositoryImpl repository;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE TABLE fee_schedules ("
+                + "id UUID PRIMARY KEY, account_tier VARCHAR(20), transaction_type VARCHAR(20),"
+                + " min_amount DECIMAL(18, 2), max_amount DECIMAL(18, 2), fee_type VARCHAR(20),"
+                + " fee_value DECIMAL(18, 6), effective_from TIMESTAMP WITH TIME ZONE,"
+                + " effective_to TIMESTAMP WITH TIME ZONE)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+
+        schedule("checking", "0.00", "100.00", Fee.FLAT, "1.00", JAN_1, null);
+        schedule("checking", "100.01", "1000.00", Fee.PERCENTAGE, "1.5", JAN_1, null);
+        schedule("savings", "0.00", "1000.00", Fee.FLAT, "0.25", JAN_1, null);
+    }
+
+    @Test
+    void flatFeeIsChargedAsIs() {
+        Fee fee = lookup("checking", "40.00", JUL_1).orElseThrow();
+
+        assertEquals(Fee.FLAT, fee.feeType());
+        assertEquals(new BigDecimal("1.00"), fee.apply(new BigDecimal("40.00")));
+    }
+
+    @Test
+    void percentageFeeScalesWithAmount() {
+        Fee fee = lookup("checking", "200.00", JUL_1).orElseThrow();
+
+        assertEquals(Fee.PERCENTAGE, fee.feeType());
+        assertEquals(new BigDecimal("3.00"), fee.apply(new BigDecimal("200.00")));
+        assertEquals(new BigDecimal("0.15"), fee.apply(new BigDecimal("10.00")));
+    }
+
+    @Test
+    void bandBoundariesAreInclusive() {
+        assertEquals(Fee.FLAT, lookup("checking", "0.00", JUL_1).orElseThrow().feeType());
+        assertEquals(Fee.FLAT, lookup("checking", "100.00", JUL_1).orElseThrow().feeType());
+        assertEquals(Fee.PERCENTAGE, lookup("checking", "100.01", JUL_1).orElseThrow().feeType());
+        assertEquals(Fee.PERCENTAGE, lookup("checking", "1000.00", JUL_1).orElseThrow().feeType());
+        assertTrue(lookup("checking", "1000.01", JUL_1).isEmpty());
+    }
+
+    @Test
+    void feesDifferByTier() {
+        assertEquals(new BigDecimal("0.25"), lookup("savings", "100.00", JUL_1).orElseThrow().feeValue()
+                .setScale(2));
+        assertTrue(lookup("premium", "100.00", JUL_1).isEmpty());
+    }
+
+    @Test
+    void onlySchedulesInEffectApply() {
+        OffsetDateTime mar1 = JAN_1.plusMonths(2);
+        schedule("savings", "0.00", "1000.00", Fee.FLAT, "0.10", mar1, mar1.plusMonths(1));
+
+        assertTrue(lookup("savings", "50.00", JAN_1.minusSeconds(1)).isEmpty());
+        assertEquals(0, new BigDecimal("0.25").compareTo(lookup("savings", "50.00", JAN_1).orElseThrow().feeValue()));
+        assertEquals(0, new BigDecimal("0.10").compareTo(lookup("savings", "50.00", mar1).orElseThrow().feeValue()));
+        assertEquals(0, new BigDecimal("0.10").compareTo(
+                lookup("savings", "50.00", mar1.plusMonths(1)).orElseThrow().feeValue()));
+        assertEquals(0, new BigDecimal("0.25").compareTo(lookup("savings", "50.00", JUL_1).orElseThrow().feeValue()));
+    }
+
+    private Optional<Fee> lookup(String tier, String amount, OffsetDateTime at) {
+        return repository.getApplicableFee(tier, "transfer", new BigDecimal(amount), at);
+    }
+
+    private void schedule(String tier, String min, String max, String feeType, String value,
+                          OffsetDateTime from, OffsetDateTime to) {
+        jdbc.update("INSERT INTO fee_schedules VALUES (?, ?, 'transfer', ?, ?, ?, ?, ?, ?)",
+                UUID.randomUUID(), tier, new BigDecimal(min), new BigDecimal(max), feeType,
+                new BigDecimal(value), from, to);
+    }
+}
@@ -0,0 +1,158 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.condition.EnabledIfSystemProperty;
+import org.slf4j.Logger;
+import org.slf4j.LoggerFactory;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Random;
+import java.util.UUID;
+import java.util.function.Supplier;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+class RecentByAccountTest {
+
+    private static final Logger log = LoggerFactory.getLogger(RecentByAccountTest.class);
+    private static final OffsetDateTime T0 = OffsetDateTime.of(2024, 3, 1, 12, 0, 0, 0, ZoneOffset.UTC);
+
+    private JdbcTemplate jdbc;
+    private TransactionRepositoryImpl repository;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE DOMAIN IF NOT EXISTS JSONB AS VARCHAR");
+        jdbc.execute("CREATE TABLE transactions ("
+                + "id UUID PRIMARY KEY, tenant_id UUID, from_account_id UUID, to_account_id UUID,"
+                + " amount DECIMAL(18, 2), currency VARCHAR(3), type VARCHAR(20), status VARCHAR(20),"
+                + " description TEXT, converted_amount DECIMAL(18, 2), fx_rate DECIMAL(18, 8),"
+                + " converted_currency VARCHAR(3), fee_amount DECIMAL(18, 2), fee_currency VARCHAR(3),"
+                + " net_amount DECIMAL(18, 2), authorized_at TIMESTAMP WITH TIME ZONE,"
+                + " authorized_hold_expires_at TIMESTAMP WITH TIME ZONE, external_ref VARCHAR(255) UNIQUE,"
+                + " batch_id UUID, created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE, metadata JSONB DEFAULT '{}')");
+        jdbc.execute("CREATE TABLE transaction_events ("
+                + "id UUID PRIMARY KEY, seq BIGSERIAL NOT NULL,"
+                + " transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,"
+                + " from_status VARCHAR(20), to_status VARCHAR(20) NOT NULL, reason TEXT NOT NULL DEFAULT '',"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+        // The per-account indexes from V1 that each UNION ALL branch walks.
+        jdbc.execute("CREATE INDEX idx_transactions_from_account ON transactions (from_account_id, created_at DESC)");
+        jdbc.execute("CREATE INDEX idx_transactions_to_account ON transactions (to_account_id, created_at DESC)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+        TenantContext.set(UUID.randomUUID());
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void matchesTheGeneralListingForBothSidesOfTheAccount() {
+        UUID account = UUID.randomUUID();
+        UUID other = UUID.randomUUID();
+        create(account, other, T0);
+        create(other, account, T0.plusMinutes(1));
+        create(null, account, T0.plusMinutes(2));
+        create(account, account, T0.plusMinutes(3));
+        create(other, UUID.randomUUID(), T0.plusMinutes(4));
+        create(account, other, T0.plusMinutes(3)); // ties on created_at with the self-transfer
+
+        List<UUID> recent = ids(repository.getRecentByAccount(account, 10));
+        assertEquals(5, recent.size(), "the self-transfer appears once and the unrelated row not at all");
+        assertEquals(ids(repository.list(filter(account, 10))), recent);
+
+        assertEquals(recent.subList(0, 2), ids(repository.getRecentByAccount(account, 2)));
+    }
+
+    @Test
+    void isScopedToTenant() {
+        UUID account = UUID.randomUUID();
+        create(account, UUID.randomUUID(), T0);
+
+        TenantContext.set(UUID.randomUUID());
+
+        assertEquals(List.of(), repository.getRecentByAccount(account, 10));
+    }
+
+    // Compares the OR query in list() with the UNION ALL in getRecentByAccount over a
+    // table where the account's rows are a small fraction of the total. Run with
+    // -Dbenchmark=true; H2 stands in for Postgres, so the ratio is indicative only.
+    @Test
+    @EnabledIfSystemProperty(named = "benchmark", matches = "true")
+    void benchmarkAgainstGeneralListing() {
+        Random random = new Random(42);
+        List<UUID> accounts = new ArrayList<>();
+        for (int i = 0; i < 500; i++) {
+            accounts.add(UUID.randomUUID());
+        }
+        UUID tenantId = TenantContext.require();
+        List<Object[]> rows = new ArrayList<>();
+        for (int i = 0; i < 100_000; i++) {
+            UUID from = random.nextInt(10) == 0 ? null : accounts.get(random.nextInt(accounts.size()));
+            UUID to = accounts.get(random.nextInt(accounts.size()));
+            OffsetDateTime at = T0.plusSeconds(i);
+            rows.add(new Object[]{UUID.randomUUID(), tenantId, from, to, at, at});
+        }
+        jdbc.batchUpdate("INSERT INTO transactions (id, tenant_id, from_account_id, to_account_id, amount, currency,"
+                + " type, status, description, created_at, updated_at)"
+                + " VALUES (?, ?, ?, ?, 10.00, 'USD', 'transfer', 'completed', '', ?, ?)", rows);
+
+        UUID account = accounts.get(0);
+        assertEquals(ids(repository.list(filter(account, 20))), ids(repository.getRecentByAccount(account, 20)));
+
+        long orNanos = time(() -> repository.list(filter(account, 20)));
+        long unionNanos = time(() -> repository.getRecentByAccount(account, 20));
+        log.info("list (OR): {} us/op, getRecentByAccount (UNION): {} us/op", orNanos / 1000, unionNanos / 1000);
+    }
+
+    private static long time(Supplier<List<Transaction>> query) {
+        for (int i = 0; i < 50; i++) {
+            query.get();
+        }
+        int iterations = 200;
+        long start = System.nanoTime();
+        for (int i = 0; i < iterations; i++) {
+            query.get();
+        }
+        return (System.nanoTime() - start) / iterations;
+    }
+
+    private static TransactionFilter filter(UUID accountId, int limit) {
+        TransactionFilter filter = new TransactionFilter();
+        filter.setAccountId(accountId);
+        filter.setLimit(limit);
+        return filter;
+    }
+
+    private static List<UUID> ids(List<Transaction> transactions) {
+        return transactions.stream().map(Transaction::getId).toList();
+    }
+
+    private void create(UUID from, UUID to, OffsetDateTime createdAt) {
+        Transaction txn = new Transaction(UUID.randomUUID(), from, to,
+                new BigDecimal("10.00"), "USD", "transfer", "completed", "", createdAt, createdAt);
+        txn.setTenantId(TenantContext.require());
+        repository.create(txn);
+    }
+}
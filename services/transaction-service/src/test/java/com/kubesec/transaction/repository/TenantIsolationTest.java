@@ -0,0 +1,140 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.Optional;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+// Runs the real queries against H2 with transactions in two tenants, checking that
+// every lookup and update made as one tenant misses the other's rows.
+class TenantIsolationTest {
+
+    private static final OffsetDateTime NOW = OffsetDateTime.now(ZoneOffset.UTC);
+
+    private final UUID tenantA = UUID.randomUUID();
+    private final UUID tenantB = UUID.randomUUID();
+    private final UUID accountB = UUID.randomUUID();
+
+    private TransactionRepositoryImpl repository;
+    private UUID completedB;
+    private UUID authorizedB;
+    private int created;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE DOMAIN IF NOT EXISTS JSONB AS VARCHAR");
+        jdbc.execute("CREATE TABLE transactions ("
+                + "id UUID PRIMARY KEY, tenant_id UUID, from_account_id UUID, to_account_id UUID,"
+                + " amount DECIMAL(18, 2), currency VARCHAR(3), type VARCHAR(20), status VARCHAR(20),"
+                + " description TEXT, converted_amount DECIMAL(18, 2), fx_rate DECIMAL(18, 8),"
+                + " converted_currency VARCHAR(3), fee_amount DECIMAL(18, 2), fee_currency VARCHAR(3),"
+                + " net_amount DECIMAL(18, 2), authorized_at TIMESTAMP WITH TIME ZONE,"
+                + " authorized_hold_expires_at TIMESTAMP WITH TIME ZONE, external_ref VARCHAR(255) UNIQUE,"
+                + " batch_id UUID, created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE, metadata JSONB DEFAULT '{}')");
+        jdbc.execute("CREATE TABLE transaction_events ("
+                + "id UUID PRIMARY KEY, seq BIGSERIAL NOT NULL,"
+                + " transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,"
+                + " from_status VARCHAR(20), to_status VARCHAR(20) NOT NULL, reason TEXT NOT NULL DEFAULT '',"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+
+        TenantContext.set(tenantB);
+        completedB = create(accountB, "completed");
+        authorizedB = create(accountB, "authorized");
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void anotherTenantCannotReadTransactions() {
+        TenantContext.set(tenantA);
+
+        assertEquals(Optional.empty(), repository.getById(completedB));
+        assertEquals(List.of(), repository.getTransactionEventHistory(completedB));
+        assertEquals(List.of(), repository.list(new TransactionFilter()));
+        assertEquals(List.of(), repository.list(byAccount(accountB)));
+        assertEquals(List.of(), repository.getRecentByAccount(accountB, 10));
+        assertEquals(List.of(), repository.getByAccountIdAndStatus(accountB, "authorized", 10, 0));
+        assertEquals(0, repository.countByAccountsAndStatusSince(List.of(accountB), "completed", NOW.minusDays(1)));
+        assertEquals(0, BigDecimal.ZERO.compareTo(repository.sumActiveHolds(accountB, NOW)));
+    }
+
+    @Test
+    void anotherTenantCannotChangeTransactions() {
+        TenantContext.set(tenantA);
+
+        assertFalse(repository.transitionStatus(authorizedB, "authorized", "completed", "captured"));
+        assertThrows(IllegalStateException.class, () -> repository.updateStatus(completedB, "failed", "forced"));
+        assertFalse(repository.softDelete(completedB));
+
+        TenantContext.set(tenantB);
+        assertEquals("authorized", repository.getById(authorizedB).orElseThrow().getStatus());
+        assertEquals("completed", repository.getById(completedB).orElseThrow().getStatus());
+        assertEquals(1, repository.getTransactionEventHistory(completedB).size());
+    }
+
+    @Test
+    void eachTenantSeesOnlyItsOwnTransactionsOnASharedAccountId() {
+        // Account ids are only unique within a tenant's account-service data, so the
+        // same id in two tenants must still list separately.
+        TenantContext.set(tenantA);
+        UUID completedA = create(accountB, "completed");
+
+        assertEquals(List.of(completedA), ids(repository.list(byAccount(accountB))));
+        assertEquals(List.of(completedA), ids(repository.getRecentByAccount(accountB, 10)));
+
+        TenantContext.set(tenantB);
+        assertEquals(List.of(authorizedB, completedB), ids(repository.getRecentByAccount(accountB, 10)));
+        assertEquals(Optional.empty(), repository.getById(completedA));
+    }
+
+    private UUID create(UUID accountId, String status) {
+        // One second apart so later rows sort first in the listings.
+        OffsetDateTime createdAt = NOW.minusHours(1).plusSeconds(created++);
+        Transaction txn = new Transaction(UUID.randomUUID(), accountId, UUID.randomUUID(),
+                new BigDecimal("10.00"), "USD", "transfer", status, "", createdAt, createdAt);
+        txn.setTenantId(TenantContext.require());
+        if ("authorized".equals(status)) {
+            txn.setAuthorizedAt(createdAt);
+            txn.setAuthorizedHoldExpiresAt(NOW.plusDays(7));
+        }
+        repository.create(txn);
+        return txn.getId();
+    }
+
+    private static TransactionFilter byAccount(UUID accountId) {
+        TransactionFilter filter = new TransactionFilter();
+        filter.setAccountId(accountId);
+        return filter;
+    }
+
+    private static List<UUID> ids(List<Transaction> transactions) {
+        return transactions.stream().map(Transaction::getId).toList();
+    }
+}
@@ -0,0 +1,109 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionStateEvent;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Arrays;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class TransactionEventHistoryTest {
+
+    private JdbcTemplate jdbc;
+    private TransactionRepositoryImpl repository;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE DOMAIN IF NOT EXISTS JSONB AS VARCHAR");
+        jdbc.execute("CREATE TABLE transactions ("
+                + "id UUID PRIMARY KEY, tenant_id UUID, from_account_id UUID, to_account_id UUID,"
+                + " amount DECIMAL(18, 2), currency VARCHAR(3), type VARCHAR(20), status VARCHAR(20),"
+                + " description TEXT, converted_amount DECIMAL(18, 2), fx_rate DECIMAL(18, 8),"
+                + " converted_currency VARCHAR(3), fee_amount DECIMAL(18, 2), fee_currency VARCHAR(3),"
+                + " net_amount DECIMAL(18, 2), authorized_at TIMESTAMP WITH TIME ZONE,"
+                + " authorized_hold_expires_at TIMESTAMP WITH TIME ZONE, external_ref VARCHAR(255) UNIQUE,"
+                + " batch_id UUID, created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE, metadata JSONB DEFAULT '{}')");
+        jdbc.execute("CREATE TABLE transaction_events ("
+                + "id UUID PRIMARY KEY, seq BIGSERIAL NOT NULL,"
+                + " transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,"
+                + " from_status VARCHAR(20), to_status VARCHAR(20) NOT NULL, reason TEXT NOT NULL DEFAULT '',"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+        TenantContext.set(UUID.randomUUID());
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void historyMatchesStatusUpdates() {
+        Transaction txn = newTransaction("pending");
+        repository.create(txn);
+        repository.updateStatus(txn.getId(), "completed", "published");
+        repository.updateStatus(txn.getId(), "failed", "reversed");
+        repository.updateStatus(txn.getId(), "completed", "retried");
+
+        List<TransactionStateEvent> history = repository.getTransactionEventHistory(txn.getId());
+
+        assertEquals(Arrays.asList(null, "pending", "completed", "failed"),
+                history.stream().map(TransactionStateEvent::fromStatus).toList());
+        assertEquals(List.of("pending", "completed", "failed", "completed"),
+                history.stream().map(TransactionStateEvent::toStatus).toList());
+        assertEquals(List.of("created", "published", "reversed", "retried"),
+                history.stream().map(TransactionStateEvent::reason).toList());
+        assertEquals("completed", repository.getById(txn.getId()).orElseThrow().getStatus());
+    }
+
+    @Test
+    void historyIsScopedToTenant() {
+        Transaction txn = newTransaction("completed");
+        repository.create(txn);
+
+        TenantContext.set(UUID.randomUUID());
+        assertTrue(repository.getTransactionEventHistory(txn.getId()).isEmpty());
+    }
+
+    @Test
+    void transitionOnlyAppliesFromExpectedStatus() {
+        Transaction txn = newTransaction("authorized");
+        repository.create(txn);
+
+        assertTrue(repository.transitionStatus(txn.getId(), "authorized", "voided", "expired"));
+        assertFalse(repository.transitionStatus(txn.getId(), "authorized", "completed", "captured"));
+
+        assertEquals("voided", repository.getById(txn.getId()).orElseThrow().getStatus());
+        assertEquals(List.of("authorized", "voided"),
+                repository.getTransactionEventHistory(txn.getId()).stream().map(TransactionStateEvent::toStatus).toList());
+    }
+
+    private static Transaction newTransaction(String status) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Transaction txn = new Transaction(UUID.randomUUID(), UUID.randomUUID(), UUID.randomUUID(),
+                new BigDecimal("10.00"), "USD", "transfer", status, "", now, now);
+        txn.setTenantId(TenantContext.require());
+        return txn;
+    }
+}
@@ -0,0 +1,160 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertNotNull;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class TransactionSoftDeleteTest {
+
+    private JdbcTemplate jdbc;
+    private TransactionRepositoryImpl repository;
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        jdbc = new JdbcTemplate(dataSource);
+        // H2 has no JSONB; the repository only casts to it, so text is enough here.
+        jdbc.execute("CREATE DOMAIN IF NOT EXISTS JSONB AS VARCHAR");
+        jdbc.execute("CREATE TABLE transactions ("
+                + "id UUID PRIMARY KEY, tenant_id UUID, from_account_id UUID, to_account_id UUID,"
+                + " amount DECIMAL(18, 2), currency VARCHAR(3), type VARCHAR(20), status VARCHAR(20),"
+                + " description TEXT, converted_amount DECIMAL(18, 2), fx_rate DECIMAL(18, 8),"
+                + " converted_currency VARCHAR(3), fee_amount DECIMAL(18, 2), fee_currency VARCHAR(3),"
+                + " net_amount DECIMAL(18, 2), authorized_at TIMESTAMP WITH TIME ZONE,"
+                + " authorized_hold_expires_at TIMESTAMP WITH TIME ZONE, external_ref VARCHAR(255) UNIQUE,"
+                + " batch_id UUID, created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE, metadata JSONB DEFAULT '{}')");
+        jdbc.execute("CREATE TABLE transaction_events ("
+                + "id UUID PRIMARY KEY, seq BIGSERIAL NOT NULL,"
+                + " transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,"
+                + " from_status VARCHAR(20), to_status VARCHAR(20) NOT NULL, reason TEXT NOT NULL DEFAULT '',"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+        jdbc.execute("CREATE TABLE scheduled_transfer_runs ("
+                + "id UUID PRIMARY KEY, scheduled_transfer_id UUID NOT NULL,"
+                + " transaction_id UUID REFERENCES transactions(id), status VARCHAR(20) NOT NULL, error TEXT,"
+                + " run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW())");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+        TenantContext.set(UUID.randomUUID());
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @Test
+    void softDeletedRowsAreHiddenFromLookupsAndListings() {
+        UUID account = UUID.randomUUID();
+        Transaction kept = newTransaction(account, "pending");
+        Transaction deleted = newTransaction(account, "pending");
+        repository.create(kept);
+        repository.create(deleted);
+
+        assertTrue(repository.softDelete(deleted.getId()));
+
+        assertTrue(repository.getById(deleted.getId()).isEmpty());
+        assertEquals(List.of(kept.getId()), ids(repository.list(new TransactionFilter())));
+        assertEquals(List.of(kept.getId()), ids(repository.getRecentByAccount(account, 10)));
+        assertEquals(List.of(kept.getId()), ids(repository.getByAccountIdAndStatus(account, "pending", 10, 0)));
+    }
+
+    @Test
+    void listingCanIncludeSoftDeletedRows() {
+        Transaction txn = newTransaction(UUID.randomUUID(), "completed");
+        repository.create(txn);
+        repository.softDelete(txn.getId());
+
+        TransactionFilter filter = new TransactionFilter();
+        filter.setIncludeDeleted(true);
+        List<Transaction> listed = repository.list(filter);
+
+        assertEquals(List.of(txn.getId()), ids(listed));
+        assertNotNull(listed.get(0).getDeletedAt());
+        assertFalse(repository.getTransactionEventHistory(txn.getId()).isEmpty(), "the audit trail is kept");
+    }
+
+    @Test
+    void softDeleteIsScopedToTenantAndAppliesOnce() {
+        Transaction txn = newTransaction(UUID.randomUUID(), "completed");
+        repository.create(txn);
+        UUID tenantId = TenantContext.require();
+
+        TenantContext.set(UUID.randomUUID());
+        assertFalse(repository.softDelete(txn.getId()));
+
+        TenantContext.set(tenantId);
+        assertTrue(repository.softDelete(txn.getId()));
+        assertFalse(repository.softDelete(txn.getId()));
+    }
+
+    @Test
+    void hardDeleteRemovesTheRowItsEventsAndTheRunLink() {
+        Transaction txn = newTransaction(UUID.randomUUID(), "completed");
+        repository.create(txn);
+        UUID runId = UUID.randomUUID();
+        jdbc.update("INSERT INTO scheduled_transfer_runs (id, scheduled_transfer_id, transaction_id, status)"
+                + " VALUES (?, ?, ?, 'succeeded')", runId, UUID.randomUUID(), txn.getId());
+
+        assertTrue(repository.deleteById(txn.getId()));
+
+        TransactionFilter filter = new TransactionFilter();
+        filter.setIncludeDeleted(true);
+        assertEquals(List.of(), repository.list(filter));
+        assertEquals(List.of(), repository.getTransactionEventHistory(txn.getId()));
+        assertEquals(1, jdbc.queryForObject(
+                "SELECT COUNT(*) FROM scheduled_transfer_runs WHERE id = ? AND transaction_id IS NULL",
+                Integer.class, runId), "the run is kept without its transaction");
+        assertFalse(repository.deleteById(txn.getId()));
+    }
+
+    @Test
+    void hardDeleteIsScopedToTenant() {
+        Transaction txn = newTransaction(UUID.randomUUID(), "completed");
+        repository.create(txn);
+        UUID runId = UUID.randomUUID();
+        jdbc.update("INSERT INTO scheduled_transfer_runs (id, scheduled_transfer_id, transaction_id, status)"
+                + " VALUES (?, ?, ?, 'succeeded')", runId, UUID.randomUUID(), txn.getId());
+        UUID tenantId = TenantContext.require();
+
+        TenantContext.set(UUID.randomUUID());
+        assertFalse(repository.deleteById(txn.getId()));
+
+        TenantContext.set(tenantId);
+        assertTrue(repository.getById(txn.getId()).isPresent());
+        assertEquals(txn.getId(), jdbc.queryForObject(
+                "SELECT transaction_id FROM scheduled_transfer_runs WHERE id = ?", UUID.class, runId));
+    }
+
+    private static List<UUID> ids(List<Transaction> transactions) {
+        return transactions.stream().map(Transaction::getId).toList();
+    }
+
+    private static Transaction newTransaction(UUID fromAccountId, String status) {
+        OffsetDateTime now = OffsetDateTime.now(ZoneOffset.UTC);
+        Transaction txn = new Transaction(UUID.randomUUID(), fromAccountId, UUID.randomUUID(),
+                new BigDecimal("10.00"), "USD", "transfer", status, "", now, now);
+        txn.setTenantId(TenantContext.require());
+        return txn;
+    }
+}
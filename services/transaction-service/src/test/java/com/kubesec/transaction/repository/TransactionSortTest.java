@@ -0,0 +1,117 @@
+package com.kubesec.transaction.repository;
+
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.exception.InvalidFilterFieldException;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.tenant.TenantContext;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.jdbc.core.JdbcTemplate;
+import org.springframework.jdbc.datasource.DriverManagerDataSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.Arrays;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class TransactionSortTest {
+
+    private static final OffsetDateTime T0 = OffsetDateTime.of(2024, 3, 1, 12, 0, 0, 0, ZoneOffset.UTC);
+
+    private TransactionRepositoryImpl repository;
+    private final Map<UUID, String> names = new HashMap<>();
+
+    @BeforeEach
+    void setUp() {
+        DriverManagerDataSource dataSource = new DriverManagerDataSource(
+                "jdbc:h2:mem:" + UUID.randomUUID() + ";MODE=PostgreSQL;DB_CLOSE_DELAY=-1");
+        JdbcTemplate jdbc = new JdbcTemplate(dataSource);
+        jdbc.execute("CREATE DOMAIN IF NOT EXISTS JSONB AS VARCHAR");
+        jdbc.execute("CREATE TABLE transactions ("
+                + "id UUID PRIMARY KEY, tenant_id UUID, from_account_id UUID, to_account_id UUID,"
+                + " amount DECIMAL(18, 2), currency VARCHAR(3), type VARCHAR(20), status VARCHAR(20),"
+                + " description TEXT, converted_amount DECIMAL(18, 2), fx_rate DECIMAL(18, 8),"
+                + " converted_currency VARCHAR(3), fee_amount DECIMAL(18, 2), fee_currency VARCHAR(3),"
+                + " net_amount DECIMAL(18, 2), authorized_at TIMESTAMP WITH TIME ZONE,"
+                + " authorized_hold_expires_at TIMESTAMP WITH TIME ZONE, external_ref VARCHAR(255) UNIQUE,"
+                + " batch_id UUID, created_at TIMESTAMP WITH TIME ZONE, updated_at TIMESTAMP WITH TIME ZONE,"
+                + " deleted_at TIMESTAMP WITH TIME ZONE, metadata JSONB DEFAULT '{}')");
+        jdbc.execute("CREATE TABLE transaction_events ("
+                + "id UUID PRIMARY KEY, seq BIGSERIAL NOT NULL,"
+                + " transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,"
+                + " from_status VARCHAR(20), to_status VARCHAR(20) NOT NULL, reason TEXT NOT NULL DEFAULT '',"
+                + " created_at TIMESTAMP WITH TIME ZONE NOT NULL)");
+
+        AppConfig config = new AppConfig();
+        config.setDbSchemaRetryAttempts(0);
+        repository = new TransactionRepositoryImpl(jdbc, new SchemaReadiness(jdbc, config));
+        TenantContext.set(UUID.randomUUID());
+
+        // Each column orders the three rows differently.
+        create("a", "30.00", "completed", T0, T0.plusHours(1));
+        create("b", "10.00", "pending", T0.plusHours(1), T0.plusHours(2));
+        create("c", "20.00", "failed", T0.plusHours(2), T0);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            "created_at, a b c",
+            "updated_at, c a b",
+            "amount,     b c a",
+            "status,     a c b",
+    })
+    void sortsByEachAllowedColumn(String sortBy, String ascending) {
+        List<String> expected = Arrays.asList(ascending.split(" "));
+
+        assertEquals(expected, list(sortBy, "asc"));
+        assertEquals(expected.reversed(), list(sortBy, "desc"));
+    }
+
+    @Test
+    void defaultsToNewestFirst() {
+        assertEquals(List.of("c", "b", "a"), names(repository.list(new TransactionFilter())));
+    }
+
+    @Test
+    void unknownSortColumnIsRejected() {
+        TransactionFilter filter = new TransactionFilter();
+        filter.setSortBy("description; DROP TABLE transactions");
+
+        assertThrows(InvalidFilterFieldException.class, () -> repository.list(filter));
+    }
+
+    private List<String> list(String sortBy, String sortOrder) {
+        TransactionFilter filter = new TransactionFilter();
+        filter.setSortBy(sortBy);
+        filter.setSortOrder(sortOrder);
+        return names(repository.list(filter));
+    }
+
+    private List<String> names(List<Transaction> transactions) {
+        return transactions.stream().map(t -> names.get(t.getId())).toList();
+    }
+
+    private void create(String name, String amount, String status, OffsetDateTime createdAt, OffsetDateTime updatedAt) {
+        Transaction txn = new Transaction(UUID.randomUUID(), UUID.randomUUID(), UUID.randomUUID(),
+                new BigDecimal(amount), "USD", "transfer", status, "", createdAt, updatedAt);
+        txn.setTenantId(TenantContext.require());
+        repository.create(txn);
+        names.put(txn.getId(), name);
+    }
+}
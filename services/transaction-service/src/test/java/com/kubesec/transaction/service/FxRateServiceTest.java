@@ -0,0 +1,45 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.config.AppConfig;
+import org.junit.jupiter.api.Test;
+
+import java.math.BigDecimal;
+import java.util.Map;
+import java.util.Optional;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+class FxRateServiceTest {
+
+    private final FxRateService fx = fxRates(Map.of("USD_EUR", new BigDecimal("0.9150"), "EUR_JPY", new BigDecimal("161.25")));
+
+    @Test
+    void convertedAmountIsProportionalToTheRate() {
+        BigDecimal rate = fx.getRate("usd", "eur").orElseThrow();
+
+        assertEquals(new BigDecimal("91.50"), fx.convert(new BigDecimal("100.00"), rate));
+        assertEquals(new BigDecimal("183.00"), fx.convert(new BigDecimal("200.00"), rate));
+        assertEquals(new BigDecimal("0.01"), fx.convert(new BigDecimal("0.01"), rate));
+        assertEquals(new BigDecimal("16125.00"), fx.convert(new BigDecimal("100.00"), fx.getRate("EUR", "JPY").orElseThrow()));
+    }
+
+    @Test
+    void convertedAmountIsRoundedHalfEvenToCents() {
+        // 0.005 and 0.015 sit exactly between two cents
+        BigDecimal half = new BigDecimal("0.5");
+        assertEquals(new BigDecimal("0.00"), fx.convert(new BigDecimal("0.01"), half));
+        assertEquals(new BigDecimal("0.02"), fx.convert(new BigDecimal("0.03"), half));
+    }
+
+    @Test
+    void sameCurrencyNeedsNoConfiguredRate() {
+        assertEquals(Optional.of(BigDecimal.ONE), fx.getRate("GBP", "gbp"));
+        assertEquals(Optional.empty(), fx.getRate("EUR", "USD"));
+    }
+
+    private static FxRateService fxRates(Map<String, BigDecimal> rates) {
+        AppConfig config = new AppConfig();
+        config.setFxRates(rates);
+        return new FxRateService(config);
+    }
+}
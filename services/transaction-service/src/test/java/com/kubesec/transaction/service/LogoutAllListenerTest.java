@@ -0,0 +1,62 @@
+package com.kubesec.transaction.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.client.TokenValidationCache;
+import com.kubesec.transaction.tenant.TenantContext;
+import io.nats.client.Connection;
+import io.nats.client.Message;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+
+import java.nio.charset.StandardCharsets;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.Mockito.doAnswer;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.when;
+
+class LogoutAllListenerTest {
+
+    private final List<String> evicted = new ArrayList<>();
+    private LogoutAllListener listener;
+
+    @BeforeEach
+    void setUp() {
+        TokenValidationCache cache = mock(TokenValidationCache.class);
+        // Records the tenant the eviction ran under along with the user
+        doAnswer(inv -> evicted.add(TenantContext.require() + "/" + inv.getArgument(0)))
+                .when(cache).evictUser(anyString());
+        listener = new LogoutAllListener(mock(Connection.class), new ObjectMapper().findAndRegisterModules(), cache);
+    }
+
+    @Test
+    void evictsTheUsersEntriesUnderTheEventsTenant() {
+        UUID tenant = UUID.randomUUID();
+
+        listener.onMessage(message("{\"user_id\":\"user-1\",\"tenant_id\":\"" + tenant
+                + "\",\"sessions_invalidated\":2,\"timestamp\":\"2026-03-01T12:00:00Z\"}"));
+
+        assertEquals(List.of(tenant + "/user-1"), evicted);
+        assertTrue(TenantContext.get().isEmpty(), "the tenant is cleared afterwards");
+    }
+
+    @Test
+    void eventsWithoutTenantOrUserOrThatDoNotDecodeAreDropped() {
+        listener.onMessage(message("{\"user_id\":\"user-1\",\"sessions_invalidated\":1}"));
+        listener.onMessage(message("{\"tenant_id\":\"" + UUID.randomUUID() + "\",\"sessions_invalidated\":1}"));
+        listener.onMessage(message("not json"));
+
+        assertTrue(evicted.isEmpty());
+    }
+
+    private static Message message(String json) {
+        Message msg = mock(Message.class);
+        when(msg.getData()).thenReturn(json.getBytes(StandardCharsets.UTF_8));
+        return msg;
+    }
+}
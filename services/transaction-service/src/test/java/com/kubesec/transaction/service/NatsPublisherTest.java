@@ -0,0 +1,205 @@
+package com.kubesec.transaction.service;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.model.dto.TransactionEvent;
+import io.nats.client.JetStream;
+import io.nats.client.api.PublishAck;
+import io.nats.client.impl.Headers;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.mockito.ArgumentCaptor;
+
+import java.io.IOException;
+import java.math.BigDecimal;
+import java.time.Duration;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.List;
+import java.util.UUID;
+import java.util.concurrent.CompletableFuture;
+import java.util.concurrent.TimeoutException;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNotEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.anyString;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+class NatsPublisherTest {
+
+    private JetStream jetStream;
+    private NatsPublisher publisher;
+
+    @BeforeEach
+    void setUp() {
+        jetStream = mock(JetStream.class);
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class)))
+                .thenReturn(CompletableFuture.completedFuture(mock(PublishAck.class)));
+        publisher = new NatsPublisher(jetStream, new ObjectMapper().findAndRegisterModules(), new AppConfig());
+    }
+
+    @Test
+    void transferIsPublishedOncePerAccount() throws Exception {
+        UUID from = UUID.randomUUID();
+        UUID to = UUID.randomUUID();
+        TransactionEvent event = event(from, to);
+
+        publisher.publishTransactionCompleted(event);
+
+        ArgumentCaptor<String> subjects = ArgumentCaptor.forClass(String.class);
+        ArgumentCaptor<Headers> headers = ArgumentCaptor.forClass(Headers.class);
+        verify(jetStream, times(2)).publishAsync(subjects.capture(), headers.capture(), any(byte[].class));
+        assertEquals(List.of("transactions.completed." + from, "transactions.completed." + to), subjects.getAllValues());
+
+        // Distinct message ids, or JetStream would drop the second copy as a duplicate.
+        String firstId = headers.getAllValues().get(0).getFirst("Nats-Msg-Id");
+        String secondId = headers.getAllValues().get(1).getFirst("Nats-Msg-Id");
+        assertEquals(event.transactionId() + ":" + from, firstId);
+        assertNotEquals(firstId, secondId);
+    }
+
+    @Test
+    void depositIsPublishedToItsDestinationOnly() throws Exception {
+        UUID to = UUID.randomUUID();
+
+        publisher.publishTransactionCompleted(event(null, to));
+
+        verify(jetStream).publishAsync(any(String.class), any(Headers.class), any(byte[].class));
+        verify(jetStream).publishAsync(eq("transactions.completed." + to),
+                any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void withdrawalIsPublishedToItsSourceOnly() throws Exception {
+        UUID from = UUID.randomUUID();
+
+        publisher.publishTransactionCompleted(event(from, null));
+
+        verify(jetStream).publishAsync(any(String.class), any(Headers.class), any(byte[].class));
+        verify(jetStream).publishAsync(eq("transactions.completed." + from),
+                any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void reversalIsAnnouncedOnce() throws Exception {
+        TransactionEvent event = event(UUID.randomUUID(), UUID.randomUUID());
+
+        publisher.publishTransactionReversed(event);
+
+        ArgumentCaptor<Headers> headers = ArgumentCaptor.forClass(Headers.class);
+        verify(jetStream).publishAsync(eq("transactions.reversed"), headers.capture(), any(byte[].class));
+        assertEquals(event.transactionId().toString(), headers.getValue().getFirst("Nats-Msg-Id"));
+    }
+
+    @Test
+    void depositsAndWithdrawalsAreAnnouncedOnTheirOwnSubjects() throws Exception {
+        TransactionEvent deposit = event(null, UUID.randomUUID());
+        TransactionEvent withdrawal = event(UUID.randomUUID(), null);
+
+        publisher.publishDeposit(deposit);
+        publisher.publishWithdrawal(withdrawal);
+
+        ArgumentCaptor<Headers> headers = ArgumentCaptor.forClass(Headers.class);
+        verify(jetStream).publishAsync(eq("transactions.deposit"), headers.capture(), any(byte[].class));
+        assertEquals(deposit.transactionId().toString(), headers.getValue().getFirst("Nats-Msg-Id"));
+        verify(jetStream).publishAsync(eq("transactions.withdrawal"), any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void failedPublishIsRetriedWithTheSameMessageId() throws Exception {
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class)))
+                .thenReturn(CompletableFuture.failedFuture(new IOException("no responders")))
+                .thenReturn(CompletableFuture.completedFuture(mock(PublishAck.class)));
+        TransactionEvent event = event(null, UUID.randomUUID());
+
+        retryingPublisher(3).publishDeposit(event);
+
+        ArgumentCaptor<Headers> headers = ArgumentCaptor.forClass(Headers.class);
+        verify(jetStream, times(2)).publishAsync(eq("transactions.deposit"), headers.capture(), any(byte[].class));
+        assertEquals(headers.getAllValues().get(0).getFirst("Nats-Msg-Id"),
+                headers.getAllValues().get(1).getFirst("Nats-Msg-Id"));
+    }
+
+    @Test
+    void publishGivesUpAfterTheConfiguredAttempts() {
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class)))
+                .thenAnswer(inv -> CompletableFuture.failedFuture(new IOException("no responders")));
+
+        assertThrows(EventPublishException.class, () -> retryingPublisher(3).publishDeposit(event(null, UUID.randomUUID())));
+
+        verify(jetStream, times(3)).publishAsync(eq("transactions.deposit"), any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void unackedPublishTimesOutAndAbandonsTheAck() {
+        CompletableFuture<PublishAck> ack = new CompletableFuture<>();
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class))).thenReturn(ack);
+
+        long start = System.nanoTime();
+        EventPublishException e = assertThrows(EventPublishException.class,
+                () -> timingOutPublisher(1).publishDeposit(event(null, UUID.randomUUID())));
+        Duration waited = Duration.ofNanos(System.nanoTime() - start);
+
+        assertTrue(e.getMessage().contains("timed out after PT0.05S"), e.getMessage());
+        assertTrue(e.getCause() instanceof TimeoutException);
+        assertTrue(ack.isCancelled(), "the pending ack is cancelled rather than left waiting");
+        assertTrue(waited.compareTo(Duration.ofSeconds(2)) < 0, "waited " + waited);
+    }
+
+    @Test
+    void timedOutAttemptIsRetried() throws Exception {
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class)))
+                .thenReturn(new CompletableFuture<>())
+                .thenReturn(CompletableFuture.completedFuture(mock(PublishAck.class)));
+
+        timingOutPublisher(2).publishDeposit(event(null, UUID.randomUUID()));
+
+        verify(jetStream, times(2)).publishAsync(eq("transactions.deposit"), any(Headers.class), any(byte[].class));
+    }
+
+    @Test
+    void interruptedCallerStopsWaitingWithoutRetrying() {
+        CompletableFuture<PublishAck> ack = new CompletableFuture<>();
+        when(jetStream.publishAsync(anyString(), any(Headers.class), any(byte[].class))).thenReturn(ack);
+
+        Thread.currentThread().interrupt();
+        try {
+            EventPublishException e = assertThrows(EventPublishException.class,
+                    () -> timingOutPublisher(3).publishDeposit(event(null, UUID.randomUUID())));
+
+            assertTrue(e.getMessage().contains("interrupted"), e.getMessage());
+            assertTrue(Thread.currentThread().isInterrupted(), "the interrupt is kept for the caller");
+        } finally {
+            Thread.interrupted();
+        }
+        assertTrue(ack.isCancelled());
+        verify(jetStream, times(1)).publishAsync(anyString(), any(Headers.class), any(byte[].class));
+    }
+
+    private NatsPublisher timingOutPublisher(int attempts) {
+        AppConfig config = new AppConfig();
+        config.setNatsPublishTimeout(Duration.ofMillis(50));
+        config.setNatsPublishRetries(attempts);
+        config.setNatsPublishRetryBackoff(Duration.ofMillis(1));
+        return new NatsPublisher(jetStream, new ObjectMapper().findAndRegisterModules(), config);
+    }
+
+    private NatsPublisher retryingPublisher(int attempts) {
+        AppConfig config = new AppConfig();
+        config.setNatsPublishRetries(attempts);
+        config.setNatsPublishRetryBackoff(Duration.ofMillis(1));
+        return new NatsPublisher(jetStream, new ObjectMapper().findAndRegisterModules(), config);
+    }
+
+    private static TransactionEvent event(UUID from, UUID to) {
+        return new TransactionEvent(UUID.randomUUID(), UUID.randomUUID(), from, to, new BigDecimal("10.00"), "USD",
+                "transfer", "completed", null, null, null, null, OffsetDateTime.now(ZoneOffset.UTC), null, null, null);
+    }
+}
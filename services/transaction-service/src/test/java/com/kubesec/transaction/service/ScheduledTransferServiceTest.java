@@ -0,0 +1,184 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.exception.InsufficientBalanceException;
+import com.kubesec.transaction.model.ScheduledTransfer;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.dto.TransferRequest;
+import com.kubesec.transaction.tenant.TenantContext;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import org.junit.jupiter.api.AfterEach;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+
+import java.math.BigDecimal;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.isNull;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.times;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+class ScheduledTransferServiceTest {
+
+    private static final ZoneOffset UTC = ZoneOffset.UTC;
+
+    private UUID tenantId;
+    private InMemoryTransactionRepository repository;
+    private TransactionService transactionService;
+    private ScheduledTransferService service;
+
+    @BeforeEach
+    void setUp() {
+        tenantId = UUID.randomUUID();
+        repository = new InMemoryTransactionRepository();
+        transactionService = mock(TransactionService.class);
+        service = new ScheduledTransferService(repository, transactionService);
+    }
+
+    @AfterEach
+    void tearDown() {
+        TenantContext.clear();
+    }
+
+    @ParameterizedTest
+    @CsvSource({
+            // anchor, current run, next run
+            "2024-01-15T09:30:00Z, 2024-01-15T09:30:00Z, 2024-02-15T09:30:00Z",
+            // Short months clamp to their last day...
+            "2024-01-31T09:30:00Z, 2024-01-31T09:30:00Z, 2024-02-29T09:30:00Z",
+            "2023-01-31T09:30:00Z, 2023-01-31T09:30:00Z, 2023-02-28T09:30:00Z",
+            "2024-03-31T09:30:00Z, 2024-03-31T09:30:00Z, 2024-04-30T09:30:00Z",
+            // ...and the next month returns to the anchor's day
+            "2024-01-31T09:30:00Z, 2024-02-29T09:30:00Z, 2024-03-31T09:30:00Z",
+            "2024-01-31T09:30:00Z, 2024-04-30T09:30:00Z, 2024-05-31T09:30:00Z",
+            "2024-01-30T09:30:00Z, 2024-02-29T09:30:00Z, 2024-03-30T09:30:00Z",
+            // Leap day anchors fall back to the 28th in other years
+            "2024-02-29T09:30:00Z, 2024-02-29T09:30:00Z, 2024-03-29T09:30:00Z",
+            "2024-02-29T09:30:00Z, 2025-01-29T09:30:00Z, 2025-02-28T09:30:00Z",
+            // Year boundaries
+            "2024-12-31T09:30:00Z, 2024-12-31T09:30:00Z, 2025-01-31T09:30:00Z",
+            "2024-10-31T09:30:00Z, 2024-11-30T09:30:00Z, 2024-12-31T09:30:00Z"
+    })
+    void monthlyRunsRollOverFromTheAnchorDay(OffsetDateTime anchor, OffsetDateTime current, OffsetDateTime expected) {
+        assertEquals(expected, ScheduledTransferService.nextRunAt(anchor, current, "monthly"));
+    }
+
+    @Test
+    void monthlyScheduleKeepsTheAnchorDayForAWholeYear() {
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 1, 31, 9, 30, 0, 0, UTC);
+        List<Integer> days = new ArrayList<>();
+        OffsetDateTime run = anchor;
+        for (int i = 0; i < 12; i++) {
+            run = ScheduledTransferService.nextRunAt(anchor, run, "monthly");
+            days.add(run.getDayOfMonth());
+        }
+
+        assertEquals(List.of(29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31, 31), days);
+        assertEquals(OffsetDateTime.of(2025, 1, 31, 9, 30, 0, 0, UTC), run);
+    }
+
+    @Test
+    void monthlyRunsKeepTheAnchorsTimeAndOffset() {
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 1, 31, 23, 45, 0, 0, ZoneOffset.ofHours(2));
+
+        assertEquals(OffsetDateTime.of(2024, 2, 29, 23, 45, 0, 0, ZoneOffset.ofHours(2)),
+                ScheduledTransferService.nextRunAt(anchor, anchor, "monthly"));
+    }
+
+    @Test
+    void dailyAndWeeklyRunsStepFromTheCurrentRun() {
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 2, 28, 9, 30, 0, 0, UTC);
+
+        assertEquals(OffsetDateTime.of(2024, 2, 29, 9, 30, 0, 0, UTC),
+                ScheduledTransferService.nextRunAt(anchor, anchor, "daily"));
+        assertEquals(OffsetDateTime.of(2024, 3, 6, 9, 30, 0, 0, UTC),
+                ScheduledTransferService.nextRunAt(anchor, anchor, "weekly"));
+        assertThrows(IllegalArgumentException.class,
+                () -> ScheduledTransferService.nextRunAt(anchor, anchor, "yearly"));
+    }
+
+    @Test
+    void overdueTransferRunsOnceAndSkipsToTheNextAnchorDate() {
+        // Due on Jan 31, but the worker only gets to it on Mar 5: one transfer is made,
+        // and the schedule moves to Mar 31 rather than replaying February.
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 1, 31, 9, 30, 0, 0, UTC);
+        OffsetDateTime now = OffsetDateTime.of(2024, 3, 5, 12, 0, 0, 0, UTC);
+        UUID id = schedule(anchor);
+        Transaction txn = transaction();
+        when(transactionService.createTransfer(any(TransferRequest.class), isNull())).thenReturn(txn);
+
+        service.runDue(id, now);
+
+        verify(transactionService, times(1)).createTransfer(any(TransferRequest.class), isNull());
+        verify(transactionService).captureTransfer(txn.getId());
+        TenantContext.set(tenantId);
+        ScheduledTransfer transfer = repository.getScheduledTransfer(id).orElseThrow();
+        assertEquals(OffsetDateTime.of(2024, 3, 31, 9, 30, 0, 0, UTC), transfer.getNextRunAt());
+        assertEquals(now, transfer.getLastRunAt());
+        assertEquals(List.of(new InMemoryTransactionRepository.ScheduledTransferRun(id, txn.getId(), "succeeded", null)),
+                repository.runsFor(id));
+    }
+
+    @Test
+    void failedRunIsRecordedAndTheScheduleStillAdvances() {
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 1, 31, 9, 30, 0, 0, UTC);
+        UUID id = schedule(anchor);
+        when(transactionService.createTransfer(any(TransferRequest.class), isNull()))
+                .thenThrow(new InsufficientBalanceException("insufficient balance"));
+
+        service.runDue(id, anchor.plusMinutes(1));
+
+        TenantContext.set(tenantId);
+        assertEquals(OffsetDateTime.of(2024, 2, 29, 9, 30, 0, 0, UTC),
+                repository.getScheduledTransfer(id).orElseThrow().getNextRunAt());
+        assertEquals("failed", repository.runsFor(id).get(0).status());
+    }
+
+    @Test
+    void transferThatIsNotDueIsLeftAlone() {
+        OffsetDateTime anchor = OffsetDateTime.of(2024, 1, 31, 9, 30, 0, 0, UTC);
+        UUID id = schedule(anchor);
+
+        service.runDue(id, anchor.minusSeconds(1));
+
+        verify(transactionService, times(0)).createTransfer(any(TransferRequest.class), isNull());
+        assertEquals(List.of(), repository.runsFor(id));
+    }
+
+    private UUID schedule(OffsetDateTime anchor) {
+        ScheduledTransfer transfer = new ScheduledTransfer();
+        transfer.setId(UUID.randomUUID());
+        transfer.setTenantId(tenantId);
+        transfer.setFromAccountId(UUID.randomUUID());
+        transfer.setToAccountId(UUID.randomUUID());
+        transfer.setAmount(new BigDecimal("75.00"));
+        transfer.setCurrency("USD");
+        transfer.setDescription("rent");
+        transfer.setFrequency("monthly");
+        transfer.setStartAt(anchor);
+        transfer.setNextRunAt(anchor);
+        transfer.setStatus("active");
+        transfer.setCreatedAt(anchor);
+        transfer.setUpdatedAt(anchor);
+        repository.createScheduledTransfer(transfer);
+        return transfer.getId();
+    }
+
+    private Transaction transaction() {
+        OffsetDateTime now = OffsetDateTime.now(UTC);
+        Transaction txn = new Transaction(UUID.randomUUID(), UUID.randomUUID(), UUID.randomUUID(),
+                new BigDecimal("75.00"), "USD", "transfer", "authorized", "rent", now, now);
+        txn.setTenantId(tenantId);
+        return txn;
+    }
+}
@@ -0,0 +1,44 @@
+package com.kubesec.transaction.service;
+
+import com.kubesec.transaction.client.AccountServiceClient;
+import com.kubesec.transaction.config.AppConfig;
+import com.kubesec.transaction.fees.FlatFeeCalculator;
+import com.kubesec.transaction.testdoubles.InMemoryTransactionRepository;
+import org.junit.jupiter.api.Test;
+import org.springframework.http.client.SimpleClientHttpRequestFactory;
+import org.springframework.web.client.RestClient;
+
+import java.math.BigDecimal;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+class TransactionServiceBuilderTest {
+
+    @Test
+    void validateNamesEveryMissingRequiredDependency() {
+        IllegalStateException e = assertThrows(IllegalStateException.class,
+                () -> TransactionService.builder().withRepository(new InMemoryTransactionRepository()).validate());
+
+        assertEquals("missing required dependencies: accountClient, fxRateService, feeCalculator", e.getMessage());
+    }
+
+    @Test
+    void buildFailsWhenRequiredDependencyIsMissing() {
+        assertThrows(IllegalStateException.class, () -> TransactionService.builder().build());
+    }
+
+    @Test
+    void natsDependenciesAreOptional() {
+        AppConfig config = new AppConfig();
+        TransactionService.Builder builder = TransactionService.builder()
+                .withRepository(new InMemoryTransactionRepository())
+                .withAccountClient(new AccountServiceClient(config, RestClient.builder(), new SimpleClientHttpRequestFactory(), null))
+                .withFxRateService(new FxRateService(config))
+                .withFeeCalculator(new FlatFeeCalculator(BigDecimal.ZERO));
+
+        assertDoesNotThrow(builder::validate);
+        assertDoesNotThrow(builder::build);
+    }
+}
@@ -0,0 +1,23 @@
+package com.kubesec.transaction.testdoubles;
+
+import com.kubesec.transaction.repository.TenantRepository;
+
+import java.util.Map;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+
+public class InMemoryTenantRepository implements TenantRepository {
+
+    private final Map<UUID, Boolean> tenants = new ConcurrentHashMap<>();
+
+    public UUID addTenant(boolean active) {
+        UUID id = UUID.randomUUID();
+        tenants.put(id, active);
+        return id;
+    }
+
+    @Override
+    public boolean isActive(UUID tenantId) {
+        return tenants.getOrDefault(tenantId, false);
+    }
+}
@@ -0,0 +1,514 @@
+package com.kubesec.transaction.testdoubles;
+
+import com.kubesec.transaction.exception.InvalidFilterFieldException;
+import com.kubesec.transaction.fees.Fee;
+import com.kubesec.transaction.model.Cursor;
+import com.kubesec.transaction.model.ScheduledTransfer;
+import com.kubesec.transaction.model.Transaction;
+import com.kubesec.transaction.model.TransactionFilter;
+import com.kubesec.transaction.model.TransactionStateEvent;
+import com.kubesec.transaction.repository.TransactionRepository;
+import com.kubesec.transaction.tenant.TenantContext;
+
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.ZoneOffset;
+import java.util.ArrayList;
+import java.util.Collections;
+import java.util.Comparator;
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+import java.util.TreeMap;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
+import java.util.stream.Stream;
+
+// In-memory TransactionRepository for handler tests that run without PostgreSQL.
+// Entities are copied on the way in and out so callers can't mutate stored rows.
+// Status changes append to the event history exactly as TransactionRepositoryImpl does.
+public class InMemoryTransactionRepository implements TransactionRepository {
+
+    public record ScheduledTransferRun(UUID scheduledTransferId, UUID transactionId, String status, String error) {}
+
+    // A fee_schedules row.
+    public record FeeScheduleRow(String accountTier, String transactionType, BigDecimal minAmount,
+                                 BigDecimal maxAmount, Fee fee, OffsetDateTime effectiveFrom,
+                                 OffsetDateTime effectiveTo) {}
+
+    // An idempotency_keys row; transactionId is null while the claim is in flight.
+    private record IdempotencyKeyRow(UUID transactionId, OffsetDateTime createdAt) {}
+
+    private final Map<UUID, Transaction> transactions = new ConcurrentHashMap<>();
+    private final Map<UUID, List<TransactionStateEvent>> events = new ConcurrentHashMap<>();
+    private final Map<UUID, ScheduledTransfer> scheduledTransfers = new ConcurrentHashMap<>();
+    private final List<ScheduledTransferRun> runs = Collections.synchronizedList(new ArrayList<>());
+    private final List<FeeScheduleRow> feeSchedules = Collections.synchronizedList(new ArrayList<>());
+    private final Map<String, IdempotencyKeyRow> idempotencyKeys = new ConcurrentHashMap<>();
+
+    // --- Transactions ---
+
+    @Override
+    public void create(Transaction txn) {
+        transactions.put(txn.getId(), copy(txn));
+        appendTransactionEvent(txn.getId(), null, txn.getStatus(), "created");
+    }
+
+    @Override
+    public void createBatch(List<Transaction> batch) {
+        batch.forEach(this::create);
+    }
+
+    // Synchronized in place of the per-sender advisory locks.
+    @Override
+    public synchronized Optional<UUID> createHolds(List<Transaction> batch, Map<UUID, BigDecimal> balances,
+                                                   OffsetDateTime now) {
+        Map<UUID, BigDecimal> debits = new TreeMap<>();
+        batch.forEach(t -> debits.merge(t.getFromAccountId(), t.getNetAmount(), BigDecimal::add));
+        for (Map.Entry<UUID, BigDecimal> debit : debits.entrySet()) {
+            BigDecimal available = balances.get(debit.getKey()).subtract(sumActiveHolds(debit.getKey(), now));
+            if (available.compareTo(debit.getValue()) < 0) {
+                return Optional.of(debit.getKey());
+            }
+        }
+        createBatch(batch);
+        return Optional.empty();
+    }
+
+    @Override
+    public synchronized Optional<Transaction> upsertByExternalRef(Transaction txn) {
+        Optional<Transaction> existing = transactions.values().stream()
+                .filter(t -> txn.getExternalRef().equals(t.getExternalRef()))
+                .findFirst();
+        if (existing.isPresent()) {
+            return existing.filter(t -> t.getTenantId().equals(txn.getTenantId()))
+                    .map(InMemoryTransactionRepository::copy);
+        }
+        create(txn);
+        return Optional.of(copy(txn));
+    }
+
+    @Override
+    public synchronized boolean claimIdempotencyKey(String key, OffsetDateTime now, OffsetDateTime expiredBefore) {
+        IdempotencyKeyRow existing = idempotencyKeys.get(tenantKey(key));
+        if (existing != null && !existing.createdAt().isBefore(expiredBefore)) {
+            return false;
+        }
+        idempotencyKeys.put(tenantKey(key), new IdempotencyKeyRow(null, now));
+        return true;
+    }
+
+    @Override
+    public synchronized void setIdempotencyKey(String key, UUID transactionId) {
+        idempotencyKeys.computeIfPresent(tenantKey(key), (k, row) -> new IdempotencyKeyRow(transactionId, row.createdAt()));
+    }
+
+    @Override
+    public synchronized void releaseIdempotencyKey(String key) {
+        idempotencyKeys.computeIfPresent(tenantKey(key), (k, row) -> row.transactionId() == null ? null : row);
+    }
+
+    @Override
+    public Optional<Transaction> getByIdempotencyKey(String key) {
+        return Optional.ofNullable(idempotencyKeys.get(tenantKey(key)))
+                .map(IdempotencyKeyRow::transactionId)
+                .flatMap(this::getById);
+    }
+
+    @Override
+    public Optional<Transaction> getById(UUID id) {
+        return Optional.ofNullable(transactions.get(id)).filter(this::inTenant)
+                .filter(t -> t.getDeletedAt() == null)
+                .map(InMemoryTransactionRepository::copy);
+    }
+
+    @Override
+    public List<Transaction> list(TransactionFilter filter) {
+        Stream<Transaction> matching = tenantTransactions()
+                .filter(t -> filter.isIncludeDeleted() || t.getDeletedAt() == null)
+                .filter(t -> filter.getAccountId() == null || involves(t, filter.getAccountId()))
+                .filter(t -> filter.getStatus() == null || filter.getStatus().isEmpty() || filter.getStatus().equals(t.getStatus()))
+                .filter(t -> filter.getCreatedAfter() == null || !t.getCreatedAt().isBefore(filter.getCreatedAfter()))
+                .filter(t -> filter.getCreatedBefore() == null || !t.getCreatedAt().isAfter(filter.getCreatedBefore()))
+                .filter(t -> filter.getMetadataKey() == null
+                        || filter.getMetadataValue().equals(t.getMetadata().get(filter.getMetadataKey())))
+                .filter(t -> filter.getCursor() == null || afterCursor(t, filter))
+                .sorted(sortOrder(filter));
+        if (filter.getCursor() == null && filter.getOffset() > 0) {
+            matching = matching.skip(filter.getOffset());
+        }
+        if (filter.getLimit() > 0) {
+            matching = matching.limit(filter.getLimit());
+        }
+        return matching.map(InMemoryTransactionRepository::copy).toList();
+    }
+
+    private static boolean afterCursor(Transaction t, TransactionFilter filter) {
+        int cmp = t.getCreatedAt().compareTo(filter.getCursor().createdAt());
+        if (cmp == 0) {
+            cmp = t.getId().compareTo(filter.getCursor().id());
+        }
+        return "asc".equals(filter.getSortOrder()) ? cmp > 0 : cmp < 0;
+    }
+
+    private static Comparator<Transaction> sortOrder(TransactionFilter filter) {
+        Comparator<Transaction> order = switch (filter.getSortBy()) {
+            case "created_at" -> Comparator.comparing(Transaction::getCreatedAt);
+            case "updated_at" -> Comparator.comparing(Transaction::getUpdatedAt);
+            case "amount" -> Comparator.comparing(Transaction::getAmount);
+            case "status" -> Comparator.comparing(Transaction::getStatus);
+            default -> throw new InvalidFilterFieldException(filter.getSortBy());
+        };
+        order = order.thenComparing(Transaction::getId);
+        return "asc".equals(filter.getSortOrder()) ? order : order.reversed();
+    }
+
+    @Override
+    public List<Transaction> getByAccountIdAndStatus(UUID accountId, String status, int limit, int offset) {
+        return tenantTransactions()
+                .filter(t -> involves(t, accountId) && status.equals(t.getStatus()) && t.getDeletedAt() == null)
+                .sorted(Comparator.comparing(Transaction::getCreatedAt).thenComparing(Transaction::getId).reversed())
+                .skip(offset)
+                .limit(limit)
+                .map(InMemoryTransactionRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public List<Transaction> getRecentByAccount(UUID accountId, int limit) {
+        return tenantTransactions()
+                .filter(t -> involves(t, accountId) && t.getDeletedAt() == null)
+                .sorted(Comparator.comparing(Transaction::getCreatedAt).thenComparing(Transaction::getId).reversed())
+                .limit(limit)
+                .map(InMemoryTransactionRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public void updateStatus(UUID id, String status, String reason) {
+        String[] previous = new String[1];
+        transactions.computeIfPresent(id, (k, t) -> {
+            if (inTenant(t)) {
+                previous[0] = t.getStatus();
+                t.setStatus(status);
+                t.setUpdatedAt(now());
+            }
+            return t;
+        });
+        if (previous[0] == null) {
+            throw new IllegalStateException("transaction " + id + " not found");
+        }
+        appendTransactionEvent(id, previous[0], status, reason);
+    }
+
+    @Override
+    public boolean transitionStatus(UUID id, String expectedStatus, String status, String reason) {
+        boolean[] moved = new boolean[1];
+        transactions.computeIfPresent(id, (k, t) -> {
+            if (inTenant(t) && expectedStatus.equals(t.getStatus())) {
+                t.setStatus(status);
+                t.setUpdatedAt(now());
+                moved[0] = true;
+            }
+            return t;
+        });
+        if (moved[0]) {
+            appendTransactionEvent(id, expectedStatus, status, reason);
+        }
+        return moved[0];
+    }
+
+    @Override
+    public boolean reverse(UUID originalId, Transaction reversal) {
+        if (!transitionStatus(originalId, "completed", "reversed", "reversed by " + reversal.getId())) {
+            return false;
+        }
+        create(reversal);
+        return true;
+    }
+
+    @Override
+    public BigDecimal sumActiveHolds(UUID fromAccountId, OffsetDateTime now) {
+        return tenantTransactions()
+                .filter(t -> fromAccountId.equals(t.getFromAccountId()) && isActiveHold(t, now))
+                .map(t -> t.getNetAmount() != null ? t.getNetAmount() : t.getAmount())
+                .reduce(BigDecimal.ZERO, BigDecimal::add);
+    }
+
+    @Override
+    public List<Transaction> listExpiredAuthorizations(OffsetDateTime now, int limit) {
+        return transactions.values().stream()
+                .filter(t -> "authorized".equals(t.getStatus()) && !t.getAuthorizedHoldExpiresAt().isAfter(now))
+                .sorted(Comparator.comparing(Transaction::getAuthorizedHoldExpiresAt))
+                .limit(limit)
+                .map(InMemoryTransactionRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public void appendTransactionEvent(UUID transactionId, String fromStatus, String toStatus, String reason) {
+        TransactionStateEvent event = new TransactionStateEvent(
+                UUID.randomUUID(), transactionId, fromStatus, toStatus, reason != null ? reason : "", now());
+        events.computeIfAbsent(transactionId, k -> Collections.synchronizedList(new ArrayList<>())).add(event);
+    }
+
+    @Override
+    public List<TransactionStateEvent> getTransactionEventHistory(UUID transactionId) {
+        if (getById(transactionId).isEmpty()) {
+            return List.of();
+        }
+        List<TransactionStateEvent> history = events.getOrDefault(transactionId, List.of());
+        synchronized (history) {
+            return List.copyOf(history);
+        }
+    }
+
+    @Override
+    public boolean deleteById(UUID id) {
+        boolean[] deleted = new boolean[1];
+        transactions.computeIfPresent(id, (k, t) -> {
+            if (!inTenant(t)) {
+                return t;
+            }
+            deleted[0] = true;
+            return null;
+        });
+        if (deleted[0]) {
+            events.remove(id);
+            synchronized (runs) {
+                runs.replaceAll(r -> id.equals(r.transactionId())
+                        ? new ScheduledTransferRun(r.scheduledTransferId(), null, r.status(), r.error())
+                        : r);
+            }
+        }
+        return deleted[0];
+    }
+
+    @Override
+    public boolean softDelete(UUID id) {
+        boolean[] deleted = new boolean[1];
+        transactions.computeIfPresent(id, (k, t) -> {
+            if (inTenant(t) && t.getDeletedAt() == null) {
+                t.setDeletedAt(now());
+                deleted[0] = true;
+            }
+            return t;
+        });
+        return deleted[0];
+    }
+
+    @Override
+    public int countByAccountsAndStatusSince(List<UUID> accountIds, String status, OffsetDateTime since) {
+        return (int) tenantTransactions()
+                .filter(t -> status.equals(t.getStatus()) && !t.getCreatedAt().isBefore(since))
+                .filter(t -> accountIds.contains(t.getFromAccountId()) || accountIds.contains(t.getToAccountId()))
+                .count();
+    }
+
+    @Override
+    public List<Transaction> listCompletedByDateRange(OffsetDateTime from, OffsetDateTime to, Cursor after, int limit) {
+        return transactions.values().stream()
+                .filter(t -> "completed".equals(t.getStatus())
+                        && !t.getCreatedAt().isBefore(from) && t.getCreatedAt().isBefore(to))
+                .filter(t -> after == null || t.getCreatedAt().isAfter(after.createdAt())
+                        || (t.getCreatedAt().isEqual(after.createdAt()) && t.getId().compareTo(after.id()) > 0))
+                .sorted(Comparator.comparing(Transaction::getCreatedAt).thenComparing(Transaction::getId))
+                .limit(limit)
+                .map(InMemoryTransactionRepository::copy)
+                .toList();
+    }
+
+    // Tests don't run deposits concurrently.
+    @Override
+    public void lockDeposits(UUID accountId) {
+    }
+
+    @Override
+    public BigDecimal getDailyDepositTotal(UUID accountId, LocalDate date) {
+        return tenantTransactions()
+                .filter(t -> accountId.equals(t.getToAccountId()) && "deposit".equals(t.getType())
+                        && "completed".equals(t.getStatus())
+                        && t.getCreatedAt().withOffsetSameInstant(ZoneOffset.UTC).toLocalDate().equals(date))
+                .map(Transaction::getAmount)
+                .reduce(BigDecimal.ZERO, BigDecimal::add);
+    }
+
+    // --- Fee schedules ---
+
+    public void addFeeSchedule(FeeScheduleRow row) {
+        feeSchedules.add(row);
+    }
+
+    @Override
+    public Optional<Fee> getApplicableFee(String accountTier, String transactionType, BigDecimal amount,
+                                          OffsetDateTime at) {
+        synchronized (feeSchedules) {
+            return feeSchedules.stream()
+                    .filter(r -> r.accountTier().equals(accountTier) && r.transactionType().equals(transactionType)
+                            && amount.compareTo(r.minAmount()) >= 0 && amount.compareTo(r.maxAmount()) <= 0
+                            && !r.effectiveFrom().isAfter(at)
+                            && (r.effectiveTo() == null || !r.effectiveTo().isBefore(at)))
+                    .max(Comparator.comparing(FeeScheduleRow::effectiveFrom)
+                            .thenComparing(FeeScheduleRow::minAmount))
+                    .map(FeeScheduleRow::fee);
+        }
+    }
+
+    // --- Scheduled transfers ---
+
+    @Override
+    public void createScheduledTransfer(ScheduledTransfer transfer) {
+        scheduledTransfers.put(transfer.getId(), copy(transfer));
+    }
+
+    @Override
+    public Optional<ScheduledTransfer> getScheduledTransfer(UUID id) {
+        return Optional.ofNullable(scheduledTransfers.get(id)).filter(this::inTenant).map(InMemoryTransactionRepository::copy);
+    }
+
+    @Override
+    public List<ScheduledTransfer> listScheduledTransfers(UUID fromAccountId) {
+        return scheduledTransfers.values().stream()
+                .filter(s -> inTenant(s) && fromAccountId.equals(s.getFromAccountId()))
+                .sorted(Comparator.comparing(ScheduledTransfer::getCreatedAt))
+                .map(InMemoryTransactionRepository::copy)
+                .toList();
+    }
+
+    @Override
+    public void updateScheduledTransfer(ScheduledTransfer transfer) {
+        boolean[] found = new boolean[1];
+        scheduledTransfers.computeIfPresent(transfer.getId(), (k, s) -> {
+            if (!inTenant(s)) {
+                return s;
+            }
+            found[0] = true;
+            s.setAmount(transfer.getAmount());
+            s.setDescription(transfer.getDescription());
+            s.setFrequency(transfer.getFrequency());
+            s.setStartAt(transfer.getStartAt());
+            s.setNextRunAt(transfer.getNextRunAt());
+            s.setLastRunAt(transfer.getLastRunAt());
+            s.setUpdatedAt(now());
+            return s;
+        });
+        if (!found[0]) {
+            throw new IllegalStateException("scheduled transfer " + transfer.getId() + " not found");
+        }
+    }
+
+    @Override
+    public boolean cancelScheduledTransfer(UUID id) {
+        boolean[] cancelled = new boolean[1];
+        scheduledTransfers.computeIfPresent(id, (k, s) -> {
+            if (inTenant(s) && "active".equals(s.getStatus())) {
+                s.setStatus("cancelled");
+                s.setUpdatedAt(now());
+                cancelled[0] = true;
+            }
+            return s;
+        });
+        return cancelled[0];
+    }
+
+    @Override
+    public List<UUID> listDueScheduledTransferIds(OffsetDateTime now, int limit) {
+        return scheduledTransfers.values().stream()
+                .filter(s -> isDue(s, now))
+                .sorted(Comparator.comparing(ScheduledTransfer::getNextRunAt))
+                .limit(limit)
+                .map(ScheduledTransfer::getId)
+                .toList();
+    }
+
+    // There are no concurrent workers in tests, so the row lock reduces to the due check.
+    @Override
+    public Optional<ScheduledTransfer> lockDueScheduledTransfer(UUID id, OffsetDateTime now) {
+        return Optional.ofNullable(scheduledTransfers.get(id))
+                .filter(s -> isDue(s, now))
+                .map(InMemoryTransactionRepository::copy);
+    }
+
+    @Override
+    public void recordScheduledTransferRun(UUID scheduledTransferId, UUID transactionId, String status, String error) {
+        runs.add(new ScheduledTransferRun(scheduledTransferId, transactionId, status, error));
+    }
+
+    public List<ScheduledTransferRun> runsFor(UUID scheduledTransferId) {
+        synchronized (runs) {
+            return runs.stream().filter(r -> r.scheduledTransferId().equals(scheduledTransferId)).toList();
+        }
+    }
+
+    private static String tenantKey(String key) {
+        return TenantContext.require() + ":" + key;
+    }
+
+    private Stream<Transaction> tenantTransactions() {
+        return transactions.values().stream().filter(this::inTenant);
+    }
+
+    private boolean inTenant(Transaction txn) {
+        return TenantContext.require().equals(txn.getTenantId());
+    }
+
+    private boolean inTenant(ScheduledTransfer transfer) {
+        return TenantContext.require().equals(transfer.getTenantId());
+    }
+
+    private static boolean involves(Transaction txn, UUID accountId) {
+        return accountId.equals(txn.getFromAccountId()) || accountId.equals(txn.getToAccountId());
+    }
+
+    private static boolean isActiveHold(Transaction txn, OffsetDateTime now) {
+        return "authorized".equals(txn.getStatus()) && txn.getAuthorizedHoldExpiresAt().isAfter(now);
+    }
+
+    private static boolean isDue(ScheduledTransfer transfer, OffsetDateTime now) {
+        return "active".equals(transfer.getStatus()) && !transfer.getNextRunAt().isAfter(now);
+    }
+
+    private static OffsetDateTime now() {
+        return OffsetDateTime.now(ZoneOffset.UTC);
+    }
+
+    private static Transaction copy(Transaction txn) {
+        Transaction copy = new Transaction(txn.getId(), txn.getFromAccountId(), txn.getToAccountId(),
+                txn.getAmount(), txn.getCurrency(), txn.getType(), txn.getStatus(), txn.getDescription(),
+                txn.getCreatedAt(), txn.getUpdatedAt());
+        copy.setTenantId(txn.getTenantId());
+        copy.setConvertedAmount(txn.getConvertedAmount());
+        copy.setFxRate(txn.getFxRate());
+        copy.setConvertedCurrency(txn.getConvertedCurrency());
+        copy.setFeeAmount(txn.getFeeAmount());
+        copy.setFeeCurrency(txn.getFeeCurrency());
+        copy.setNetAmount(txn.getNetAmount());
+        copy.setAuthorizedAt(txn.getAuthorizedAt());
+        copy.setAuthorizedHoldExpiresAt(txn.getAuthorizedHoldExpiresAt());
+        copy.setExternalRef(txn.getExternalRef());
+        copy.setBatchId(txn.getBatchId());
+        copy.setDeletedAt(txn.getDeletedAt());
+        copy.setMetadata(txn.getMetadata());
+        return copy;
+    }
+
+    private static ScheduledTransfer copy(ScheduledTransfer transfer) {
+        ScheduledTransfer copy = new ScheduledTransfer();
+        copy.setId(transfer.getId());
+        copy.setTenantId(transfer.getTenantId());
+        copy.setFromAccountId(transfer.getFromAccountId());
+        copy.setToAccountId(transfer.getToAccountId());
+        copy.setAmount(transfer.getAmount());
+        copy.setCurrency(transfer.getCurrency());
+        copy.setDescription(transfer.getDescription());
+        copy.setFrequency(transfer.getFrequency());
+        copy.setStartAt(transfer.getStartAt());
+        copy.setNextRunAt(transfer.getNextRunAt());
+        copy.setLastRunAt(transfer.getLastRunAt());
+        copy.setStatus(transfer.getStatus());
+        copy.setCreatedAt(transfer.getCreatedAt());
+        copy.setUpdatedAt(transfer.getUpdatedAt());
+        return copy;
+    }
+}
@@ -0,0 +1,110 @@
+package com.kubesec.transaction.validation;
+
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.node.TextNode;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.CsvSource;
+import org.springframework.core.io.Resource;
+import org.springframework.core.io.support.PathMatchingResourcePatternResolver;
+
+import java.util.List;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class SchemaValidatorTest {
+
+    private static final String FROM = "6f1c1b7e-2f4a-4c43-9a57-0d9b1c7a5e21";
+    private static final String TO = "0b8e4f2a-91c3-4d6e-8a7b-3c2d1e0f9a8b";
+    private static final String TRANSFER = "\"from_account_id\":\"" + FROM + "\",\"to_account_id\":\"" + TO + "\","
+            + "\"amount\":25.00,\"currency\":\"USD\"";
+    private static final String MOVEMENT = "\"account_id\":\"" + FROM + "\",\"amount\":25.00,\"currency\":\"USD\"";
+
+    private final ObjectMapper objectMapper = new ObjectMapper();
+    private final SchemaValidator validator = new SchemaValidator();
+
+    @Test
+    void everySchemaLoadsAndRejectsNonObjectBodies() throws Exception {
+        Resource[] schemas = new PathMatchingResourcePatternResolver().getResources("classpath:/schemas/*.json");
+        assertTrue(schemas.length > 0);
+
+        for (Resource schema : schemas) {
+            String name = schema.getFilename().replace(".json", "");
+            assertFalse(validator.validate(name, TextNode.valueOf("body")).isEmpty(), name);
+        }
+    }
+
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "deposit | {" + MOVEMENT + "}",
+            "deposit | {" + MOVEMENT + ",\"description\":\"payroll\"}",
+            "withdrawal | {" + MOVEMENT + "}",
+            "transfer | {" + TRANSFER + "}",
+            "transfer | {" + TRANSFER + ",\"description\":\"rent\",\"metadata\":{\"invoice\":\"INV-7\"}}",
+            "batch-transfer | {\"transfers\":[{" + TRANSFER + "}]}",
+            "batch-transfer | {\"transfers\":[{" + TRANSFER + "},{" + TRANSFER + ",\"metadata\":{}}]}",
+            "payment-confirmed | {\"external_ref\":\"pay_123\",\"tenant_id\":\"" + TO + "\"," + MOVEMENT + "}",
+            "create-scheduled-transfer | {\"to_account_id\":\"" + TO + "\",\"amount\":75,\"currency\":\"EUR\","
+                    + "\"frequency\":\"monthly\"}",
+            "create-scheduled-transfer | {\"to_account_id\":\"" + TO + "\",\"amount\":75,\"currency\":\"EUR\","
+                    + "\"frequency\":\"weekly\",\"start_at\":\"2026-11-01T09:00:00Z\"}",
+            "update-scheduled-transfer | {\"amount\":80}",
+            "update-scheduled-transfer | {\"frequency\":\"daily\",\"next_run_at\":\"2026-11-02T09:00:00+01:00\"}"
+    })
+    void validBodiesPass(String schema, String body) throws Exception {
+        assertEquals(List.of(), validator.validate(schema, objectMapper.readTree(body)));
+    }
+
+    // field is the property each rejection must name
+    @ParameterizedTest
+    @CsvSource(delimiter = '|', value = {
+            "deposit | {\"amount\":25.00,\"currency\":\"USD\"} | account_id",
+            "deposit | {\"account_id\":\"acc-1\",\"amount\":25.00,\"currency\":\"USD\"} | account_id",
+            "deposit | {\"account_id\":\"" + FROM + "\",\"amount\":0,\"currency\":\"USD\"} | amount",
+            "deposit | {\"account_id\":\"" + FROM + "\",\"amount\":\"25\",\"currency\":\"USD\"} | amount",
+            "withdrawal | {\"account_id\":\"" + FROM + "\",\"amount\":-5,\"currency\":\"USD\"} | amount",
+            "withdrawal | {\"account_id\":\"" + FROM + "\",\"amount\":5,\"currency\":\"usd\"} | currency",
+            "transfer | {\"to_account_id\":\"" + TO + "\",\"amount\":25.00,\"currency\":\"USD\"} | from_account_id",
+            "transfer | {" + TRANSFER + ",\"metadata\":{\"invoice\":7}} | invoice",
+            "transfer | {" + TRANSFER + ",\"metadata\":{\"\":\"blank key\"}} | metadata",
+            "transfer | {" + TRANSFER + ",\"metadata\":\"INV-7\"} | metadata",
+            "batch-transfer | {\"transfers\":[]} | transfers",
+            "batch-transfer | {} | transfers",
+            "batch-transfer | {\"transfers\":[{" + TRANSFER + "},"
+                    + "{\"from_account_id\":\"" + FROM + "\",\"to_account_id\":\"" + TO + "\","
+                    + "\"amount\":1}]} | currency",
+            "payment-confirmed | {\"external_ref\":\"pay_123\"," + MOVEMENT + "} | tenant_id",
+            "payment-confirmed | {\"external_ref\":\"\",\"tenant_id\":\"" + TO + "\"," + MOVEMENT + "} | external_ref",
+            "create-scheduled-transfer | {\"to_account_id\":\"" + TO + "\",\"amount\":75,\"currency\":\"EUR\","
+                    + "\"frequency\":\"yearly\"} | frequency",
+            "create-scheduled-transfer | {\"to_account_id\":\"" + TO + "\",\"amount\":75,\"currency\":\"EUR\","
+                    + "\"frequency\":\"monthly\",\"start_at\":\"next tuesday\"} | start_at",
+            "update-scheduled-transfer | {\"amount\":0} | amount",
+            "update-scheduled-transfer | {\"next_run_at\":\"2026-11-02\"} | next_run_at"
+    })
+    void invalidBodiesNameTheOffendingField(String schema, String body, String field) throws Exception {
+        List<String> violations = validator.validate(schema, objectMapper.readTree(body));
+
+        assertEquals(1, violations.size(), violations.toString());
+        assertTrue(violations.get(0).contains(field), violations.get(0));
+    }
+
+    @Test
+    void emptyScheduledTransferUpdateIsRejected() throws Exception {
+        assertEquals(1, validator.validate("update-scheduled-transfer", objectMapper.readTree("{}")).size());
+    }
+
+    @Test
+    void everyViolationIsReported() throws Exception {
+        List<String> violations = validator.validate("transfer",
+                objectMapper.readTree("{\"amount\":0,\"currency\":\"usd\"}"));
+
+        assertEquals(4, violations.size(), violations.toString());
+        assertTrue(violations.stream().anyMatch(v -> v.contains("from_account_id")), violations.toString());
+        assertTrue(violations.stream().anyMatch(v -> v.contains("to_account_id")), violations.toString());
+        assertTrue(violations.get(2).contains("amount"), violations.get(2));
+        assertTrue(violations.get(3).contains("currency"), violations.get(3));
+    }
+}